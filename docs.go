@@ -0,0 +1,9 @@
+package main
+
+import "embed"
+
+// docsFS embeds the project documents served as docs:// resources so they no longer
+// depend on the working directory the binary is started from.
+//
+//go:embed README.md LICENSE
+var docsFS embed.FS
@@ -35,6 +35,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -44,15 +45,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// authKey is a custom context key for storing the auth token
-type authKey struct{}
-
-// withAuthKey adds an auth key to the context
-func withAuthKey(ctx context.Context, auth string) context.Context {
-	return context.WithValue(ctx, authKey{}, auth)
-}
-
-// authFromRequest extracts the auth token from the request headers
+// authFromRequest extracts the auth token from the request headers. Nomad requests made
+// while serving the call use it instead of NOMAD_TOKEN (see utils.WithToken).
 func authFromRequest(ctx context.Context, r *http.Request) context.Context {
 	// If no token is provided, return the context as is
 	token := r.Header.Get("Authorization")
@@ -63,17 +57,30 @@ func authFromRequest(ctx context.Context, r *http.Request) context.Context {
 	if token == "" {
 		return ctx
 	}
-	return withAuthKey(ctx, token)
+	return utils.WithToken(ctx, token)
 }
 
-// authFromEnv extracts the auth token from the environment
-func authFromEnv(ctx context.Context) context.Context {
-	// If no token is provided, return the context as is
-	token := os.Getenv("NOMAD_TOKEN")
-	if token == "" {
+// authFromVault resolves the identity a request presents (an API key or a JWT subject) to
+// the Nomad token mapped to it in vault. The presented credential itself is never sent to
+// Nomad; requests with no mapped identity are rejected by authHandler before they get here.
+func authFromVault(vault *utils.TokenVault) func(context.Context, *http.Request) context.Context {
+	if vault == nil {
+		return authFromRequest
+	}
+	return func(ctx context.Context, r *http.Request) context.Context {
+		if token, _, ok := vault.TokenForRequest(r); ok {
+			return utils.WithToken(ctx, token)
+		}
 		return ctx
 	}
-	return withAuthKey(ctx, token)
+}
+
+// authHandler rejects requests without a vault identity when a token vault is configured.
+func authHandler(vault *utils.TokenVault, next http.Handler) http.Handler {
+	if vault == nil {
+		return next
+	}
+	return vault.RequireIdentity(next)
 }
 
 // validateOrigin checks if the request origin is allowed
@@ -114,6 +121,29 @@ func main() {
 	// Define flags
 	transport := flag.String("transport", "stdio", "Transport type (stdio, sse, or streamable-http)")
 	port := flag.String("port", "8080", "Port for HTTP server")
+	resultPageBytes := flag.Int("result-page-bytes", 64*1024, "Split JSON array tool results larger than this many bytes into paged content blocks on HTTP transports (0 disables)")
+	sandboxNamespace := flag.String("sandbox-namespace", "", "Force every mutating tool call into this namespace and refuse mutating tools that are not namespace-scoped")
+	readTimeout := flag.Duration("read-timeout", 30*time.Second, "Maximum execution time of read-only tool calls (0 disables)")
+	writeTimeout := flag.Duration("write-timeout", 2*time.Minute, "Maximum execution time of mutating tool calls (0 disables)")
+	toolTimeouts := flag.String("tool-timeouts", "", "Per-tool overrides of the timeouts as tool=duration pairs, e.g. get_allocation_logs=60s,run_job=5m")
+	serializeJobSubmissions := flag.Bool("serialize-job-submissions", false, "Queue concurrent run_job calls for the same job and register with a JobModifyIndex check-and-set")
+	resultCacheTTL := flag.Duration("result-cache-ttl", 15*time.Second, "Serve repeated read-only tool calls with identical arguments from a per-session cache for this long (0 disables)")
+	permissionDenialTTL := flag.Duration("permission-denial-ttl", 10*time.Minute, "Skip tool calls that need an endpoint family the caller's token was denied (HTTP 403) in the session for this long (0 disables)")
+	sessionIdleTimeout := flag.Duration("session-idle-timeout", 30*time.Minute, "Drop the defaults, cached results and permission denials of HTTP sessions idle for this long, and end idle streamable-http sessions (0 disables)")
+	maxConcurrentToolCalls := flag.Int("max-concurrent-tool-calls", 8, "Maximum tool calls one MCP session runs at once; further calls wait for a free slot (0 disables)")
+	snapshotDir := flag.String("snapshot-dir", ".", "Directory snapshot_save writes to and snapshot_restore reads from; snapshot paths cannot leave it")
+	journalFile := flag.String("journal-file", "", "Append every mutating tool call to this JSON-lines file and expose list_recent_operations (empty disables)")
+	reportSchedule := flag.String("report-schedule", "", "YAML file of read-only tool calls to run on cron schedules, keeping each latest result as a nomad://reports/<name> resource and optionally posting it to a webhook")
+	redactionRules := flag.String("redaction-rules", "", "YAML file of regex rules masking secrets in every tool result and resource before it leaves the server")
+	nomadVersion := flag.String("nomad-version", "", "Nomad version to assume for API compatibility checks instead of asking the agent (e.g. 1.5.6)")
+	defaultTailLines := flag.Int("default-tail-lines", 100, "Number of lines get_allocation_logs shows from the end of a log when the call gives neither tail nor offset")
+	maxLogBytes := flag.Int64("max-log-bytes", 1<<20, "Maximum bytes of log output one log tool call reads; caps limit, tail_bytes, max_bytes and tail estimates")
+	tokenVaultFile := flag.String("token-vault", "", "Encrypted file mapping the API keys and JWT subjects of HTTP clients to Nomad tokens; the passphrase is read from MCP_NOMAD_TOKEN_VAULT_KEY (empty disables)")
+	tokenVaultImport := flag.String("token-vault-import", "", "YAML file of api_key or jwt_subject to nomad_token mappings to merge into -token-vault, after which the server exits")
+	enableDevAgent := flag.Bool("enable-dev-agent", false, "Register start_dev_agent and stop_dev_agent, which run a local nomad agent -dev and point the server at it; the server also starts when NOMAD_ADDR is unreachable (stdio transport only)")
+	enableChaosTools := flag.Bool("enable-chaos-tools", false, "Register chaos testing tools such as kill_random_allocation, limited to the jobs of -chaos-allowlist")
+	chaosAllowlist := flag.String("chaos-allowlist", "", "Comma-separated namespace/job pairs chaos tools may target; namespace/* allows every job of a namespace, e.g. staging/*,default/web")
+	artifactAllowedHosts := flag.String("artifact-allowed-hosts", "", "Comma-separated hosts check_job_artifacts may contact; entries starting with . match subdomains (empty allows any public host; loopback, private and link-local addresses must be listed)")
 	// nomadAddr := flag.String("nomad-addr", "http://localhost:4646", "Nomad server address")
 	flag.Parse()
 
@@ -128,23 +158,228 @@ func main() {
 	// Set up logging
 	logger := log.New(os.Stderr, "[NomadMCP] ", log.LstdFlags)
 
+	// Import mode only updates the vault file, so it runs before connecting to Nomad.
+	var tokenVault *utils.TokenVault
+	if *tokenVaultFile != "" {
+		vault, err := utils.OpenTokenVault(*tokenVaultFile, os.Getenv("MCP_NOMAD_TOKEN_VAULT_KEY"))
+		if err != nil {
+			logger.Fatalf("Invalid -token-vault: %v", err)
+		}
+		if *tokenVaultImport != "" {
+			set, removed, err := utils.ImportTokenVaultMappings(vault, *tokenVaultImport)
+			if err != nil {
+				logger.Fatalf("Invalid -token-vault-import: %v", err)
+			}
+			if err := vault.Save(); err != nil {
+				logger.Fatalf("Failed to save token vault: %v", err)
+			}
+			logger.Printf("Token vault %s updated: %d mappings set, %d removed, %d in total", *tokenVaultFile, set, removed, len(vault.Identities()))
+			return
+		}
+		if *transport == "stdio" {
+			logger.Fatalf("Invalid -token-vault: identities are only read from HTTP requests; use the sse or streamable-http transport")
+		}
+		if secret := os.Getenv("MCP_NOMAD_JWT_SECRET"); secret != "" {
+			vault.SetJWTSecret([]byte(secret))
+		}
+		tokenVault = vault
+		logger.Printf("Token vault %s maps %d identities to Nomad tokens", *tokenVaultFile, len(vault.Identities()))
+	} else if *tokenVaultImport != "" {
+		logger.Fatalf("Invalid -token-vault-import: -token-vault is required")
+	}
+
+	if *enableDevAgent && *transport != "stdio" {
+		logger.Fatalf("Invalid -enable-dev-agent: start_dev_agent runs a process on this host, so it is only offered to the local client of -transport=stdio")
+	}
+
+	// Initialize Nomad client with token
+	nomadClient, err := utils.NewNomadClient(nomadAddr, token)
+	if err != nil && *enableDevAgent {
+		// start_dev_agent can provide the cluster later.
+		logger.Printf("Nomad is not reachable at %s (%v); call start_dev_agent to run a local dev agent", nomadAddr, err)
+		nomadClient, err = utils.NewLazyNomadClient(nomadAddr, token)
+	}
+	if err != nil {
+		logger.Fatalf("Failed to create Nomad client: %v", err)
+	}
+	if err := nomadClient.PinNomadVersion(*nomadVersion); err != nil {
+		logger.Fatalf("Invalid -nomad-version: %v", err)
+	}
+	if err := nomadClient.SetDefaultTailLines(*defaultTailLines); err != nil {
+		logger.Fatalf("Invalid -default-tail-lines: %v", err)
+	}
+	if err := nomadClient.SetMaxLogBytes(*maxLogBytes); err != nil {
+		logger.Fatalf("Invalid -max-log-bytes: %v", err)
+	}
+
+	perToolTimeouts, err := tools.ParseToolTimeouts(*toolTimeouts)
+	if err != nil {
+		logger.Fatalf("Invalid -tool-timeouts: %v", err)
+	}
+
+	// Per-session state, dropped by the reaper when a session ends or idles out.
+	sessionDefaults := tools.NewSessionDefaultsStore()
+	bootstrapGuard := tools.NewACLBootstrapGuard(nomadClient)
+	sessionStates := []tools.SessionState{sessionDefaults, bootstrapGuard}
+	var permissions *tools.PermissionTracker
+	if *permissionDenialTTL > 0 {
+		permissions = tools.NewPermissionTracker(*permissionDenialTTL)
+		sessionStates = append(sessionStates, permissions)
+	}
+	var resultCache *tools.ResultCache
+	if *resultCacheTTL > 0 {
+		resultCache = tools.NewResultCache(*resultCacheTTL)
+		sessionStates = append(sessionStates, resultCache)
+		go resultCache.WatchEvents(context.Background(), nomadClient, logger)
+	}
+	idleTimeout := *sessionIdleTimeout
+	if *transport == "stdio" {
+		idleTimeout = 0
+	}
+	sessionReaper := tools.NewSessionReaper(idleTimeout, logger, sessionStates...)
+	go sessionReaper.Run(context.Background())
+
+	serverOpts := []server.ServerOption{
+		server.WithResourceCapabilities(true, true),
+		server.WithLogging(),
+		server.WithRecovery(),
+		server.WithHooks(sessionReaper.Hooks()),
+		server.WithToolHandlerMiddleware(tools.RequestIDMiddleware(logger)),
+		// Outside the timeouts so every running call of a session is cancelled when it ends.
+		server.WithToolHandlerMiddleware(tools.SessionReaperMiddleware(sessionReaper)),
+		server.WithToolHandlerMiddleware(tools.TimeoutMiddleware(tools.ToolTimeouts{
+			Read:    *readTimeout,
+			Write:   *writeTimeout,
+			PerTool: perToolTimeouts,
+		})),
+	}
+
+	// Inside the timeouts so time spent queued counts against a call's deadline; a call
+	// abandoned by its timeout keeps its slot until its handler returns.
+	if *maxConcurrentToolCalls > 0 {
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(tools.SessionConcurrencyMiddleware(tools.NewSessionConcurrencyLimiter(*maxConcurrentToolCalls))))
+	}
+
+	// Runs outside the sandbox so a session default namespace cannot escape it.
+	serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(tools.SessionDefaultsMiddleware(sessionDefaults)))
+
+	// Outside query, paging and the cache so a bootstrap secret is masked in the final
+	// output of every tool, cached or not.
+	serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(tools.ACLBootstrapMiddleware(bootstrapGuard)))
+
+	if *sandboxNamespace != "" {
+		logger.Printf("Sandbox mode: mutating operations are confined to namespace %q", *sandboxNamespace)
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(tools.SandboxNamespaceMiddleware(*sandboxNamespace)))
+	}
+
+	// Inside the session defaults so hints see the namespace and region the call really used.
+	serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(tools.ErrorHintsMiddleware(nomadClient)))
+
+	if permissions != nil {
+		serverOpts = append(serverOpts,
+			server.WithToolHandlerMiddleware(tools.PermissionProbeMiddleware(permissions)),
+			server.WithToolFilter(permissions.ToolFilter()))
+	}
+
+	// Inside the sandbox and permission checks so only calls that reached a handler are
+	// recorded, with the namespace they really used.
+	var journal *tools.OperationJournal
+	if *journalFile != "" {
+		journal, err = tools.OpenOperationJournal(*journalFile)
+		if err != nil {
+			logger.Fatalf("Invalid -journal-file: %v", err)
+		}
+		if err := journal.SetItemsKey(os.Getenv("MCP_NOMAD_JOURNAL_KEY")); err != nil {
+			logger.Fatalf("Invalid MCP_NOMAD_JOURNAL_KEY: %v", err)
+		}
+		defer journal.Close()
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(tools.OperationJournalMiddleware(journal, nomadClient, logger)))
+	}
+
+	// Some HTTP clients truncate very large single text blocks, so page big list results there.
+	if *transport != "stdio" {
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(tools.PaginateLargeResults(*resultPageBytes)))
+	}
+
+	// Runs inside pagination so paging applies to the query output, not the full result.
+	serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(tools.QueryMiddleware()))
+
+	// Inside the query so whole documents are redacted before a query can pick a secret out
+	// of them; outside the cache, which keeps raw results.
+	if *redactionRules != "" {
+		redactor, err := tools.LoadRedactionRules(*redactionRules)
+		if err != nil {
+			logger.Fatalf("Invalid -redaction-rules: %v", err)
+		}
+		serverOpts = append(serverOpts,
+			server.WithToolHandlerMiddleware(tools.RedactionMiddleware(redactor)),
+			server.WithResourceHandlerMiddleware(tools.RedactionResourceMiddleware(redactor)))
+	}
+
+	// Innermost, so cached results are the raw tool output before query and paging.
+	if resultCache != nil {
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(tools.ResultCacheMiddleware(resultCache)))
+	}
+
 	// Create MCP server
 	s := server.NewMCPServer(
 		"Nomad MCP",
 		"0.1.4",
-		server.WithResourceCapabilities(true, true),
-		server.WithLogging(),
-		server.WithRecovery(),
+		serverOpts...,
 	)
 
-	// Initialize Nomad client with token
-	nomadClient, err := utils.NewNomadClient(nomadAddr, token)
-	if err != nil {
-		logger.Fatalf("Failed to create Nomad client: %v", err)
+	var jobSubmissions *tools.JobSubmissionLocks
+	if *serializeJobSubmissions {
+		jobSubmissions = tools.NewJobSubmissionLocks()
 	}
 
 	// Register all tools
-	registerTools(s, nomadClient, logger)
+	categories := registerTools(s, nomadClient, jobSubmissions, bootstrapGuard, splitCommaList(*artifactAllowedHosts), logger)
+	categories.Track(s, "session", func() { tools.RegisterSessionTools(s, sessionDefaults, logger) })
+	snapshotRoot, err := filepath.Abs(*snapshotDir)
+	if err != nil {
+		logger.Fatalf("Invalid -snapshot-dir: %v", err)
+	}
+	categories.Track(s, "cluster", func() { tools.RegisterSnapshotTools(s, nomadClient, snapshotRoot, logger) })
+	if *enableDevAgent {
+		var addressStates []tools.AddressState
+		if resultCache != nil {
+			addressStates = append(addressStates, resultCache)
+		}
+		devAgent := tools.NewDevAgent(nomadClient, "nomad", logger, addressStates...)
+		defer devAgent.Close()
+		categories.Track(s, "cluster", func() { tools.RegisterDevAgentTools(s, devAgent, logger) })
+	}
+	if *enableChaosTools {
+		allowlist, err := tools.ParseChaosAllowlist(splitCommaList(*chaosAllowlist))
+		if err != nil {
+			logger.Fatalf("Invalid -chaos-allowlist: %v", err)
+		}
+		logger.Printf("Chaos tools enabled for %s", *chaosAllowlist)
+		categories.Track(s, "chaos", func() { tools.RegisterChaosTools(s, nomadClient, allowlist, logger) })
+	}
+	if journal != nil {
+		categories.Track(s, "journal", func() { tools.RegisterJournalTools(s, journal, nomadClient, logger) })
+	}
+	tools.AddQueryArgument(s)
+	if *resultCacheTTL > 0 {
+		tools.AddRefreshArgument(s)
+	}
+	tools.RegisterCapabilityResources(s, nomadClient, categories, tools.CapabilityConfig{SandboxNamespace: *sandboxNamespace}, logger)
+
+	// After every tool is registered, since reports are checked against them.
+	if *reportSchedule != "" {
+		reports, err := tools.LoadReportSchedule(*reportSchedule)
+		if err != nil {
+			logger.Fatalf("Invalid -report-schedule: %v", err)
+		}
+		scheduler, err := tools.NewReportScheduler(s, reports, logger)
+		if err != nil {
+			logger.Fatalf("Invalid -report-schedule: %v", err)
+		}
+		tools.RegisterReportResources(s, scheduler, logger)
+		go scheduler.Run(context.Background())
+	}
 
 	// Register all prompts
 	prompts.RegisterPrompts(s)
@@ -155,7 +390,7 @@ func main() {
 	switch *transport {
 	case "stdio":
 		logger.Println("Server started on stdio")
-		if err := server.ServeStdio(s, server.WithStdioContextFunc(authFromEnv)); err != nil {
+		if err := server.ServeStdio(s); err != nil {
 			logger.Fatalf("Server error: %v", err)
 		}
 	case "sse":
@@ -169,13 +404,13 @@ func main() {
 		// Create SSE server
 		sseServer := server.NewSSEServer(s,
 			server.WithBaseURL(fmt.Sprintf("http://%s:%s", nomadURL.Hostname(), *port)),
-			server.WithSSEContextFunc(authFromRequest),
+			server.WithSSEContextFunc(authFromVault(tokenVault)),
 		)
 
 		// Create HTTP server with origin validation middleware
 		httpServer := &http.Server{
 			Addr:              fmt.Sprintf("%s:%s", "0.0.0.0", *port),
-			Handler:           originValidationMiddleware(sseServer),
+			Handler:           originValidationMiddleware(authHandler(tokenVault, sseServer)),
 			ReadHeaderTimeout: 30 * time.Second,
 			ReadTimeout:       30 * time.Second,
 			WriteTimeout:      30 * time.Second,
@@ -195,14 +430,16 @@ func main() {
 		logger.Printf("Nomad URL: %s", nomadURL.Hostname())
 
 		// Create StreamableHTTP server
-		streamableServer := server.NewStreamableHTTPServer(s,
-			server.WithHTTPContextFunc(authFromRequest),
-		)
+		streamableOpts := []server.StreamableHTTPOption{server.WithHTTPContextFunc(authFromVault(tokenVault))}
+		if *sessionIdleTimeout > 0 {
+			streamableOpts = append(streamableOpts, server.WithSessionIdleTTL(*sessionIdleTimeout))
+		}
+		streamableServer := server.NewStreamableHTTPServer(s, streamableOpts...)
 
 		// Create HTTP server with origin validation middleware
 		httpServer := &http.Server{
 			Addr:              fmt.Sprintf("%s:%s", "0.0.0.0", *port),
-			Handler:           originValidationMiddleware(streamableServer),
+			Handler:           originValidationMiddleware(authHandler(tokenVault, streamableServer)),
 			ReadHeaderTimeout: 30 * time.Second,
 			ReadTimeout:       30 * time.Second,
 			WriteTimeout:      30 * time.Second,
@@ -218,41 +455,125 @@ func main() {
 	}
 }
 
-// Register all tools with the MCP server
-func registerTools(s *server.MCPServer, nomadClient *utils.NomadClient, logger *log.Logger) {
+// Register all tools with the MCP server and return the category each tool was registered under
+func registerTools(s *server.MCPServer, nomadClient *utils.NomadClient, jobSubmissions *tools.JobSubmissionLocks, bootstrapGuard *tools.ACLBootstrapGuard, artifactAllowedHosts []string, logger *log.Logger) tools.ToolCategories {
+	categories := tools.ToolCategories{}
+
 	// Register job-related tools
-	tools.RegisterJobTools(s, nomadClient, logger)
+	categories.Track(s, "jobs", func() { tools.RegisterJobTools(s, nomadClient, jobSubmissions, logger) })
+
+	// Register batch job tools
+	categories.Track(s, "jobs", func() { tools.RegisterBatchJobTools(s, nomadClient, logger) })
+
+	// Register alerting rule tools
+	categories.Track(s, "jobs", func() { tools.RegisterAlertTools(s, nomadClient, logger) })
+
+	// Register restart policy tools
+	categories.Track(s, "jobs", func() { tools.RegisterRestartPolicyTools(s, nomadClient, logger) })
+
+	// Register artifact tools
+	categories.Track(s, "jobs", func() { tools.RegisterArtifactTools(s, nomadClient, artifactAllowedHosts, logger) })
+
+	// Register image inspection tools
+	categories.Track(s, "jobs", func() { tools.RegisterImageTools(s, nomadClient, logger) })
+
+	// Register configuration surface tools
+	categories.Track(s, "jobs", func() { tools.RegisterConfigSurfaceTools(s, nomadClient, logger) })
+
+	// Register Consul Connect tools
+	categories.Track(s, "jobs", func() { tools.RegisterConnectTools(s, nomadClient, logger) })
+
+	// Register scaling policy tools
+	categories.Track(s, "jobs", func() { tools.RegisterScalingTools(s, nomadClient, logger) })
+
+	// Register placement tools
+	categories.Track(s, "scheduling", func() { tools.RegisterPlacementTools(s, nomadClient, logger) })
+
+	// Register priority simulation tools
+	categories.Track(s, "scheduling", func() { tools.RegisterPriorityTools(s, nomadClient, logger) })
+
+	// Register allocation placement tools
+	categories.Track(s, "scheduling", func() { tools.RegisterAllocationPlacementTools(s, nomadClient, logger) })
 
 	// Register deployment tools
-	tools.RegisterDeploymentTools(s, nomadClient, logger)
+	categories.Track(s, "deployments", func() { tools.RegisterDeploymentTools(s, nomadClient, logger) })
+
+	// Register evaluation tools
+	categories.Track(s, "evaluations", func() { tools.RegisterEvaluationTools(s, nomadClient, logger) })
 
 	// Register namespace tools
-	tools.RegisterNamespaceTools(s, nomadClient, logger)
+	categories.Track(s, "namespaces", func() { tools.RegisterNamespaceTools(s, nomadClient, logger) })
 
 	// Register node tools
-	tools.RegisterNodeTools(s, nomadClient, logger)
+	categories.Track(s, "nodes", func() { tools.RegisterNodeTools(s, nomadClient, logger) })
+
+	// Register node connectivity tools
+	categories.Track(s, "nodes", func() { tools.RegisterNodeConnectivityTools(s, nomadClient, logger) })
+
+	// Register node pool tools
+	categories.Track(s, "nodes", func() { tools.RegisterNodePoolTools(s, nomadClient, logger) })
 
 	// Register allocation tools
-	tools.RegisterAllocationTools(s, nomadClient, logger)
+	categories.Track(s, "allocations", func() { tools.RegisterAllocationTools(s, nomadClient, logger) })
+
+	// Register allocation filesystem tools
+	categories.Track(s, "allocations", func() { tools.RegisterAllocFSTools(s, nomadClient, logger) })
 
 	// Register variable tools
-	tools.RegisterVariableTools(s, nomadClient, logger)
+	categories.Track(s, "variables", func() { tools.RegisterVariableTools(s, nomadClient, logger) })
+
+	// Register service discovery tools
+	categories.Track(s, "services", func() { tools.RegisterServiceTools(s, nomadClient, logger) })
 
 	// Register volume tools
-	tools.RegisterVolumeTools(s, nomadClient, logger)
+	categories.Track(s, "volumes", func() { tools.RegisterVolumeTools(s, nomadClient, logger) })
 
 	// Register ACL tools
-	tools.RegisterACLTools(s, nomadClient, logger)
+	categories.Track(s, "acl", func() { tools.RegisterACLTools(s, nomadClient, bootstrapGuard, logger) })
 
 	// Register log tools
-	tools.RegisterLogTools(s, nomadClient, logger)
+	categories.Track(s, "logs", func() { tools.RegisterLogTools(s, nomadClient, logger) })
+
+	// Register event stream tools
+	categories.Track(s, "events", func() { tools.RegisterEventTools(s, nomadClient, logger) })
 
 	// Register resources
-	tools.RegisterResources(s, nomadClient, logger)
+	tools.RegisterResources(s, nomadClient, docsFS, logger)
 
 	// Register cluster tools
-	tools.RegisterClusterTools(s, nomadClient, logger)
+	categories.Track(s, "cluster", func() { tools.RegisterClusterTools(s, nomadClient, logger) })
+
+	// Register agent tools
+	categories.Track(s, "cluster", func() { tools.RegisterAgentTools(s, nomadClient, logger) })
+
+	// Register autopilot tools
+	categories.Track(s, "cluster", func() { tools.RegisterAutopilotTools(s, nomadClient, logger) })
+
+	// Register Raft peer tools
+	categories.Track(s, "cluster", func() { tools.RegisterRaftTools(s, nomadClient, logger) })
+
+	// Register diagnostic tools
+	categories.Track(s, "cluster", func() { tools.RegisterDiagnosticTools(s, nomadClient, logger) })
+
+	// Register Enterprise license tools
+	categories.Track(s, "cluster", func() { tools.RegisterLicenseTools(s, nomadClient, logger) })
 
 	// Register Sentinel tools
-	tools.RegisterSentinelTools(s, nomadClient, logger)
+	categories.Track(s, "sentinel", func() { tools.RegisterSentinelTools(s, nomadClient, logger) })
+
+	// Register Enterprise resource quota tools
+	categories.Track(s, "namespaces", func() { tools.RegisterQuotaTools(s, nomadClient, logger) })
+
+	return categories
+}
+
+// splitCommaList splits a comma-separated flag value, dropping empty entries.
+func splitCommaList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
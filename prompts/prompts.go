@@ -131,7 +131,7 @@ func registerNodePrompts(s *server.MCPServer) {
 				return nil, fmt.Errorf("node_id is required for eligibility action")
 			}
 			messages = append(messages, mcp.NewPromptMessage("assistant", mcp.NewTextContent(
-				fmt.Sprintf("Use **eligibility_node** for %q; eligible argument is typically `eligible` or `ineligible` per Nomad API.", nodeID),
+				fmt.Sprintf("Use **eligibility_node** for %q; pass eligible=true to allow scheduling or eligible=false to mark the node ineligible.", nodeID),
 			)))
 		default:
 			return nil, fmt.Errorf("invalid action: %s", action)
@@ -266,7 +266,7 @@ func registerACLPrompts(s *server.MCPServer) {
 		action := request.Params.Arguments["action"]
 
 		sys := "You are a Nomad ACL assistant. Treat tokens and policies as sensitive: never echo SecretID broadly; remind users about least privilege. " +
-			"Initial cluster ACL setup uses **bootstrap_acl_token** only when the user explicitly intends to bootstrap. " + guideJSONTools
+			"Initial cluster ACL setup uses **bootstrap_acl_token** (with confirm: true) only when the user explicitly intends to bootstrap; **rollback_acl_bootstrap** switches back to the previous token. " + guideJSONTools
 		var messages []mcp.PromptMessage
 		messages = append(messages, mcp.NewPromptMessage("system", mcp.NewTextContent(sys)))
 
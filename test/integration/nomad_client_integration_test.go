@@ -130,14 +130,13 @@ func NewMockNomadServer() *MockNomadServer {
 			if strings.Contains(r.URL.Path, "/eligibility") {
 				// Extract node ID from path (remove /eligibility suffix)
 				actualNodeID := strings.TrimSuffix(nodeID, "/eligibility")
-				// For eligibility, return a NodeSummary
-				nodeSummary := types.NodeSummary{
-					ID:     actualNodeID,
-					Name:   actualNodeID,
-					Status: "ready",
+				// For eligibility, return the node update response
+				response := map[string]interface{}{
+					"EvalIDs":         []string{"eval-" + actualNodeID},
+					"NodeModifyIndex": 42,
 				}
 				w.Header().Set("Content-Type", "application/json")
-				json.NewEncoder(w).Encode(nodeSummary)
+				json.NewEncoder(w).Encode(response)
 			} else {
 				// For drain operations
 				response := map[string]interface{}{
@@ -304,17 +303,15 @@ func TestNomadClientIntegration(t *testing.T) {
 	})
 
 	t.Run("RunJob", func(t *testing.T) {
-		result, err := client.RunJob(ctx, testdata.SampleJobSpecs["simple"], false)
+		result, err := client.RunJob(ctx, testdata.SampleJobSpecs["simple"], "", false)
 		require.NoError(t, err)
-		assert.Contains(t, result, "EvalID")
-		assert.Equal(t, "eval-123", result["EvalID"])
+		assert.Equal(t, "eval-123", result.EvalID)
 	})
 
 	t.Run("StopJob", func(t *testing.T) {
-		result, err := client.StopJob(ctx, "test-job-1", "default", false)
+		result, err := client.StopJob(ctx, "test-job-1", "default", types.JobStopOptions{})
 		require.NoError(t, err)
-		assert.Contains(t, result, "EvalID")
-		assert.Equal(t, "eval-456", result["EvalID"])
+		assert.Equal(t, "eval-456", result.EvalID)
 	})
 
 	t.Run("ListNodes", func(t *testing.T) {
@@ -339,9 +336,11 @@ func TestNomadClientIntegration(t *testing.T) {
 	})
 
 	t.Run("EligibilityNode", func(t *testing.T) {
-		node, err := client.EligibilityNode(ctx, "node-1", "eligible")
+		node, err := client.EligibilityNode(ctx, "node-1", true)
 		require.NoError(t, err)
-		assert.Equal(t, "node-1", node.ID)
+		assert.Equal(t, "node-1", node.NodeID)
+		assert.Equal(t, "ready", node.Status)
+		assert.Equal(t, []string{"eval-node-1"}, node.EvalIDs)
 	})
 
 	t.Run("ListNamespaces", func(t *testing.T) {
@@ -471,9 +470,9 @@ func TestNomadClientIntegration(t *testing.T) {
 	})
 
 	t.Run("ListRegions", func(t *testing.T) {
-		data, err := client.ListRegions(ctx)
+		regions, err := client.ListRegions(ctx)
 		require.NoError(t, err)
-		assert.Contains(t, string(data), "global")
+		assert.Contains(t, regions, "global")
 	})
 }
 
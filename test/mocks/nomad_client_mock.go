@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"io"
 
 	"github.com/kocierik/mcp-nomad/types"
 	"github.com/kocierik/mcp-nomad/utils"
@@ -11,15 +12,42 @@ import (
 var (
 	_ utils.JobAPI                = (*MockNomadClient)(nil)
 	_ utils.NodeAPI               = (*MockNomadClient)(nil)
+	_ utils.PlacementAPI          = (*MockNomadClient)(nil)
 	_ utils.NamespaceAPI          = (*MockNomadClient)(nil)
+	_ utils.NamespaceToolsDeps    = (*MockNomadClient)(nil)
 	_ utils.DeploymentAPI         = (*MockNomadClient)(nil)
+	_ utils.DeploymentControlAPI  = (*MockNomadClient)(nil)
+	_ utils.ErrorHintAPI          = (*MockNomadClient)(nil)
+	_ utils.EvaluationAPI         = (*MockNomadClient)(nil)
+	_ utils.DeploymentToolsDeps   = (*MockNomadClient)(nil)
+	_ utils.NodeToolsDeps         = (*MockNomadClient)(nil)
+	_ utils.DiagnosticsAPI        = (*MockNomadClient)(nil)
+	_ utils.EventAPI              = (*MockNomadClient)(nil)
 	_ utils.VolumeAPI             = (*MockNomadClient)(nil)
+	_ utils.ServiceAPI            = (*MockNomadClient)(nil)
 	_ utils.VariableAPI           = (*MockNomadClient)(nil)
 	_ utils.AllocationAPI         = (*MockNomadClient)(nil)
+	_ utils.AllocFSAPI            = (*MockNomadClient)(nil)
 	_ utils.LogAPI                = (*MockNomadClient)(nil)
 	_ utils.ACLToolsDeps          = (*MockNomadClient)(nil)
 	_ utils.SentinelAPI           = (*MockNomadClient)(nil)
+	_ utils.ServerHealthAPI       = (*MockNomadClient)(nil)
 	_ utils.ClusterToolsAPI       = (*MockNomadClient)(nil)
+	_ utils.AgentAPI              = (*MockNomadClient)(nil)
+	_ utils.DevAgentAPI           = (*MockNomadClient)(nil)
+	_ utils.AgentToolsAPI         = (*MockNomadClient)(nil)
+	_ utils.JournalAPI            = (*MockNomadClient)(nil)
+	_ utils.UndoAPI               = (*MockNomadClient)(nil)
+	_ utils.QuotaAPI              = (*MockNomadClient)(nil)
+	_ utils.ScalingAPI            = (*MockNomadClient)(nil)
+	_ utils.NodePoolAPI           = (*MockNomadClient)(nil)
+	_ utils.NodeConnectivityAPI   = (*MockNomadClient)(nil)
+	_ utils.PlacementMetricsAPI   = (*MockNomadClient)(nil)
+	_ utils.PrioritySimulationAPI = (*MockNomadClient)(nil)
+	_ utils.AutopilotAPI          = (*MockNomadClient)(nil)
+	_ utils.RaftPeerAPI           = (*MockNomadClient)(nil)
+	_ utils.SnapshotAPI           = (*MockNomadClient)(nil)
+	_ utils.LicenseAPI            = (*MockNomadClient)(nil)
 	_ utils.DynamicResourcesNomad = (*MockNomadClient)(nil)
 )
 
@@ -28,31 +56,71 @@ type MockNomadClient struct {
 	// Job methods
 	ListJobsFunc             func(context.Context, string, string) ([]types.JobSummary, error)
 	GetJobFunc               func(context.Context, string, string) (types.Job, error)
-	RunJobFunc               func(context.Context, string, bool) (map[string]interface{}, error)
-	StopJobFunc              func(context.Context, string, string, bool) (map[string]interface{}, error)
-	ScaleTaskGroupFunc       func(context.Context, string, string, int, string) error
+	GetJobDefinitionFunc     func(context.Context, string, string) (map[string]interface{}, error)
+	PlanJobFunc              func(context.Context, map[string]interface{}, string) (types.JobPlan, error)
+	ParseJobSpecFunc         func(context.Context, string) (map[string]interface{}, error)
+	CanonicalizeJobSpecFunc  func(context.Context, string) (map[string]interface{}, error)
+	RunJobFunc               func(context.Context, string, string, bool) (types.JobRegisterResponse, error)
+	EnforceRunJobFunc        func(context.Context, string, string, bool, int) (types.JobRegisterResponse, error)
+	StopJobFunc              func(context.Context, string, string, types.JobStopOptions) (types.JobDeregisterResponse, error)
+	CreateJobEvaluationFunc  func(context.Context, string, string, bool) (types.JobRegisterResponse, error)
+	DispatchJobFunc          func(context.Context, string, string, types.JobDispatchOptions) (types.JobDispatchResponse, error)
+	ForcePeriodicFunc        func(context.Context, string, string) (types.PeriodicForceResponse, error)
+	ScaleTaskGroupFunc       func(context.Context, string, string, int, string) (types.JobRegisterResponse, error)
 	ListJobAllocationsFunc   func(context.Context, string, string) ([]types.Allocation, error)
 	ListJobEvaluationsFunc   func(context.Context, string, string) ([]types.Evaluation, error)
 	ListJobDeploymentsFunc   func(context.Context, string, string) ([]types.JobDeployment, error)
+	GetJobDeploymentFunc     func(context.Context, string, string) (types.JobDeployment, error)
 	GetJobSummaryFunc        func(context.Context, string, string) (types.JobSummary, error)
-	ListJobServicesFunc      func(context.Context, string, string) ([]types.Service, error)
+	ListJobServicesFunc      func(context.Context, string, string) ([]types.ServiceRegistration, error)
 	GetJobVersionsFunc       func(context.Context, string, string) ([]types.Job, error)
+	GetJobVersionDiffsFunc   func(context.Context, string, string) (types.JobVersionsResponse, error)
+	GetJobVersionSpecFunc    func(context.Context, string, string, int) (map[string]interface{}, error)
+	GetJobSubmissionFunc     func(context.Context, string, string, int) (types.JobSubmission, error)
 	ListDeploymentsFunc      func(context.Context, string) ([]types.DeploymentSummary, error)
 	GetDeploymentFunc        func(context.Context, string) (types.Deployment, error)
+	PromoteDeploymentFunc    func(context.Context, string, string, []string) (types.DeploymentUpdateResponse, error)
+	FailDeploymentFunc       func(context.Context, string, string) (types.DeploymentUpdateResponse, error)
+	PauseDeploymentFunc      func(context.Context, string, string, bool) (types.DeploymentUpdateResponse, error)
+	ListEvaluationsFunc      func(context.Context, string, string, string) ([]types.Evaluation, error)
+	FilterEvaluationsFunc    func(context.Context, string, types.EvaluationFilter) ([]types.Evaluation, error)
+	GetEvaluationFunc        func(context.Context, string) (types.Evaluation, error)
+	ListEvalAllocationsFunc  func(context.Context, string) ([]types.Allocation, error)
+	DeleteEvaluationsFunc    func(context.Context, []string) error
+	DiagnoseConnectionFunc   func(context.Context) (types.ConnectionDiagnosis, error)
+	ListServicesFunc         func(context.Context, string) ([]types.ServiceRegistrationListStub, error)
+	GetServiceFunc           func(context.Context, string, string) ([]types.ServiceRegistration, error)
+	DeleteServiceFunc        func(context.Context, string, string, string) error
+	GetAllocationChecksFunc  func(context.Context, string) (map[string]types.AllocCheckStatus, error)
 	ListVolumesFunc          func(context.Context, string, string, string, int, string) ([]types.Volume, error)
 	GetVolumeFunc            func(context.Context, string) (*types.Volume, error)
 	DeleteVolumeFunc         func(context.Context, string) error
+	ListNamespaceVolumesFunc func(context.Context, string) ([]types.VolumeStub, error)
 	ListNodesFunc            func(context.Context, string) ([]types.NodeSummary, error)
 	GetNodeFunc              func(context.Context, string) (types.Node, error)
+	GetNodeDetailFunc        func(context.Context, string) (types.NodeDetail, error)
+	GetNodeConnectivityFunc  func(context.Context, string) (types.NodeConnectivity, error)
+	ListNodeAllocationsFunc  func(context.Context, string) ([]types.Allocation, error)
 	DrainNodeFunc            func(context.Context, string, bool, int64) (string, error)
-	EligibilityNodeFunc      func(context.Context, string, string) (types.NodeSummary, error)
+	EligibilityNodeFunc      func(context.Context, string, bool) (types.NodeEligibilityUpdate, error)
+	PurgeNodeFunc            func(context.Context, string) (types.NodePurgeResponse, error)
+	GCNodeFunc               func(context.Context, string) error
 	ListNamespacesFunc       func(context.Context) ([]types.Namespace, error)
+	GetNamespaceFunc         func(context.Context, string) (types.Namespace, error)
 	CreateNamespaceFunc      func(context.Context, types.Namespace) error
 	DeleteNamespaceFunc      func(context.Context, string) error
 	ListAllocationsFunc      func(context.Context, string, string) ([]types.Allocation, error)
 	GetAllocationFunc        func(context.Context, string) (types.Allocation, error)
+	GetAllocationMetricsFunc func(context.Context, string) (types.AllocationPlacement, error)
 	StopAllocationFunc       func(context.Context, string) error
+	RestartAllocationFunc    func(context.Context, string, string) error
+	ExecAllocationFunc       func(context.Context, types.ExecRequest) (types.ExecResult, error)
+	ListAllocationFilesFunc  func(context.Context, string, string) ([]types.AllocFileInfo, error)
+	StatAllocationFileFunc   func(context.Context, string, string) (types.AllocFileInfo, error)
+	ReadAllocationFileFunc   func(context.Context, string, string, int64, int64) ([]byte, error)
 	GetAllocationLogsFunc    func(context.Context, string, string, string, bool, int64, int64) (string, error)
+	StreamAllocationLogsFunc func(context.Context, types.LogStreamRequest, func(types.LogFrame) error) error
+	ReadAllocationLogsFunc   func(context.Context, types.LogStreamRequest, int64) (types.LogPage, error)
 	ListVariablesFunc        func(context.Context, string, string, string, int, string) ([]types.Variable, error)
 	GetVariableFunc          func(context.Context, string, string) (types.Variable, error)
 	CreateVariableFunc       func(context.Context, types.Variable, string, int, string) error
@@ -75,9 +143,44 @@ type MockNomadClient struct {
 	CreateSentinelPolicyFunc func(context.Context, types.SentinelPolicy) error
 	DeleteSentinelPolicyFunc func(context.Context, string) error
 	ListClusterPeersFunc     func(context.Context) ([]byte, error)
+	ListRegionsFunc          func(context.Context) ([]string, error)
+	StreamEventsFunc         func(context.Context, types.EventStreamRequest, func(types.EventFrame) error) error
+	GetRaftConfigurationFunc func(context.Context) (types.RaftConfiguration, error)
+	ListAgentMembersFunc     func(context.Context) ([]types.AgentMember, error)
+	GetAutopilotHealthFunc   func(context.Context) (types.AutopilotHealth, error)
+	GetAutopilotConfigFunc   func(context.Context) (types.AutopilotConfiguration, error)
+	RemoveRaftPeerFunc       func(context.Context, string, string) error
+	TransferLeadershipFunc   func(context.Context, string, string) error
+	SaveSnapshotFunc         func(context.Context, io.Writer, bool) (int64, error)
+	RestoreSnapshotFunc      func(context.Context, io.Reader) error
+	GetLicenseFunc           func(context.Context) (types.LicenseReply, error)
+	PutLicenseFunc           func(context.Context, string, bool) error
+	GetJobScaleStatusFunc    func(context.Context, string, string) (types.JobScaleStatus, error)
+	ListScalingPoliciesFunc  func(context.Context, string, string, string) ([]types.ScalingPolicyListStub, error)
+	GetScalingPolicyFunc     func(context.Context, string) (types.ScalingPolicy, error)
+	ListQuotasFunc           func(context.Context) ([]types.QuotaSpec, error)
+	RevertJobFunc            func(context.Context, string, string, int, types.JobRevertOptions) (types.JobRegisterResponse, error)
+	ListNodePoolsFunc        func(context.Context, string) ([]types.NodePool, error)
+	GetNodePoolFunc          func(context.Context, string) (types.NodePool, error)
+	UpsertNodePoolFunc       func(context.Context, types.NodePool) error
+	DeleteNodePoolFunc       func(context.Context, string) error
+	ListNodePoolNodesFunc    func(context.Context, string) ([]types.NodeListStub, error)
+	GetQuotaFunc             func(context.Context, string) (types.QuotaSpec, error)
+	UpsertQuotaFunc          func(context.Context, types.QuotaSpec) error
+	DeleteQuotaFunc          func(context.Context, string) error
+	ListQuotaUsagesFunc      func(context.Context) ([]types.QuotaUsage, error)
+	GetQuotaUsageFunc        func(context.Context, string) (types.QuotaUsage, error)
+	SetAutopilotConfigFunc   func(context.Context, types.AutopilotConfiguration, bool) (bool, error)
+	GetNomadVersionFunc      func(context.Context) (string, error)
+	GetAgentMembersFunc      func(context.Context) (types.AgentMembers, error)
+	GetAgentSelfFunc         func(context.Context) (types.AgentSelf, error)
+	GetAgentHealthFunc       func(context.Context) (types.AgentHealth, error)
+	GetLeaderFunc            func(context.Context) (string, error)
+	GetSchedulerConfigFunc   func(context.Context) (types.SchedulerConfiguration, error)
 	MakeRequestFunc          func(context.Context, string, string, map[string]string, interface{}) ([]byte, error)
 
-	token string // SetToken persists here for assertions in tests
+	token   string // SetToken persists here for assertions in tests
+	address string // SetAddress persists here for assertions in tests
 }
 
 func (m *MockNomadClient) ListJobs(ctx context.Context, namespace, status string) ([]types.JobSummary, error) {
@@ -94,25 +197,67 @@ func (m *MockNomadClient) GetJob(ctx context.Context, jobID, namespace string) (
 	return types.Job{}, nil
 }
 
-func (m *MockNomadClient) RunJob(ctx context.Context, jobSpec string, detach bool) (map[string]interface{}, error) {
-	if m.RunJobFunc != nil {
-		return m.RunJobFunc(ctx, jobSpec, detach)
+func (m *MockNomadClient) ParseJobSpec(ctx context.Context, jobSpec string) (map[string]interface{}, error) {
+	if m.ParseJobSpecFunc != nil {
+		return m.ParseJobSpecFunc(ctx, jobSpec)
 	}
 	return map[string]interface{}{}, nil
 }
 
-func (m *MockNomadClient) StopJob(ctx context.Context, jobID, namespace string, purge bool) (map[string]interface{}, error) {
+func (m *MockNomadClient) CanonicalizeJobSpec(ctx context.Context, jobSpec string) (map[string]interface{}, error) {
+	if m.CanonicalizeJobSpecFunc != nil {
+		return m.CanonicalizeJobSpecFunc(ctx, jobSpec)
+	}
+	return m.ParseJobSpec(ctx, jobSpec)
+}
+
+func (m *MockNomadClient) RunJob(ctx context.Context, jobSpec, namespace string, detach bool) (types.JobRegisterResponse, error) {
+	if m.RunJobFunc != nil {
+		return m.RunJobFunc(ctx, jobSpec, namespace, detach)
+	}
+	return types.JobRegisterResponse{}, nil
+}
+
+func (m *MockNomadClient) EnforceRunJob(ctx context.Context, jobSpec, namespace string, detach bool, jobModifyIndex int) (types.JobRegisterResponse, error) {
+	if m.EnforceRunJobFunc != nil {
+		return m.EnforceRunJobFunc(ctx, jobSpec, namespace, detach, jobModifyIndex)
+	}
+	return types.JobRegisterResponse{}, nil
+}
+
+func (m *MockNomadClient) StopJob(ctx context.Context, jobID, namespace string, opts types.JobStopOptions) (types.JobDeregisterResponse, error) {
 	if m.StopJobFunc != nil {
-		return m.StopJobFunc(ctx, jobID, namespace, purge)
+		return m.StopJobFunc(ctx, jobID, namespace, opts)
 	}
-	return map[string]interface{}{}, nil
+	return types.JobDeregisterResponse{}, nil
+}
+
+func (m *MockNomadClient) CreateJobEvaluation(ctx context.Context, jobID, namespace string, forceReschedule bool) (types.JobRegisterResponse, error) {
+	if m.CreateJobEvaluationFunc != nil {
+		return m.CreateJobEvaluationFunc(ctx, jobID, namespace, forceReschedule)
+	}
+	return types.JobRegisterResponse{}, nil
+}
+
+func (m *MockNomadClient) DispatchJob(ctx context.Context, jobID, namespace string, opts types.JobDispatchOptions) (types.JobDispatchResponse, error) {
+	if m.DispatchJobFunc != nil {
+		return m.DispatchJobFunc(ctx, jobID, namespace, opts)
+	}
+	return types.JobDispatchResponse{}, nil
+}
+
+func (m *MockNomadClient) ForceNewPeriodicInstance(ctx context.Context, jobID, namespace string) (types.PeriodicForceResponse, error) {
+	if m.ForcePeriodicFunc != nil {
+		return m.ForcePeriodicFunc(ctx, jobID, namespace)
+	}
+	return types.PeriodicForceResponse{}, nil
 }
 
-func (m *MockNomadClient) ScaleTaskGroup(ctx context.Context, jobID, group string, count int, namespace string) error {
+func (m *MockNomadClient) ScaleTaskGroup(ctx context.Context, jobID, group string, count int, namespace string) (types.JobRegisterResponse, error) {
 	if m.ScaleTaskGroupFunc != nil {
 		return m.ScaleTaskGroupFunc(ctx, jobID, group, count, namespace)
 	}
-	return nil
+	return types.JobRegisterResponse{}, nil
 }
 
 func (m *MockNomadClient) ListJobAllocations(ctx context.Context, jobID, namespace string) ([]types.Allocation, error) {
@@ -136,6 +281,13 @@ func (m *MockNomadClient) ListJobDeployments(ctx context.Context, jobID, namespa
 	return nil, nil
 }
 
+func (m *MockNomadClient) GetJobDeployment(ctx context.Context, jobID, namespace string) (types.JobDeployment, error) {
+	if m.GetJobDeploymentFunc != nil {
+		return m.GetJobDeploymentFunc(ctx, jobID, namespace)
+	}
+	return types.JobDeployment{}, nil
+}
+
 func (m *MockNomadClient) GetJobSummary(ctx context.Context, jobID, namespace string) (types.JobSummary, error) {
 	if m.GetJobSummaryFunc != nil {
 		return m.GetJobSummaryFunc(ctx, jobID, namespace)
@@ -143,13 +295,27 @@ func (m *MockNomadClient) GetJobSummary(ctx context.Context, jobID, namespace st
 	return types.JobSummary{}, nil
 }
 
-func (m *MockNomadClient) ListJobServices(ctx context.Context, jobID, namespace string) ([]types.Service, error) {
+func (m *MockNomadClient) ListJobServices(ctx context.Context, jobID, namespace string) ([]types.ServiceRegistration, error) {
 	if m.ListJobServicesFunc != nil {
 		return m.ListJobServicesFunc(ctx, jobID, namespace)
 	}
 	return nil, nil
 }
 
+func (m *MockNomadClient) GetJobDefinition(ctx context.Context, jobID, namespace string) (map[string]interface{}, error) {
+	if m.GetJobDefinitionFunc != nil {
+		return m.GetJobDefinitionFunc(ctx, jobID, namespace)
+	}
+	return nil, nil
+}
+
+func (m *MockNomadClient) PlanJob(ctx context.Context, job map[string]interface{}, namespace string) (types.JobPlan, error) {
+	if m.PlanJobFunc != nil {
+		return m.PlanJobFunc(ctx, job, namespace)
+	}
+	return types.JobPlan{}, nil
+}
+
 func (m *MockNomadClient) GetJobVersions(ctx context.Context, jobID, namespace string) ([]types.Job, error) {
 	if m.GetJobVersionsFunc != nil {
 		return m.GetJobVersionsFunc(ctx, jobID, namespace)
@@ -157,6 +323,27 @@ func (m *MockNomadClient) GetJobVersions(ctx context.Context, jobID, namespace s
 	return nil, nil
 }
 
+func (m *MockNomadClient) GetJobVersionDiffs(ctx context.Context, jobID, namespace string) (types.JobVersionsResponse, error) {
+	if m.GetJobVersionDiffsFunc != nil {
+		return m.GetJobVersionDiffsFunc(ctx, jobID, namespace)
+	}
+	return types.JobVersionsResponse{}, nil
+}
+
+func (m *MockNomadClient) GetJobVersionSpec(ctx context.Context, jobID, namespace string, version int) (map[string]interface{}, error) {
+	if m.GetJobVersionSpecFunc != nil {
+		return m.GetJobVersionSpecFunc(ctx, jobID, namespace, version)
+	}
+	return nil, nil
+}
+
+func (m *MockNomadClient) GetJobSubmission(ctx context.Context, jobID, namespace string, version int) (types.JobSubmission, error) {
+	if m.GetJobSubmissionFunc != nil {
+		return m.GetJobSubmissionFunc(ctx, jobID, namespace, version)
+	}
+	return types.JobSubmission{}, nil
+}
+
 func (m *MockNomadClient) ListDeployments(ctx context.Context, namespace string) ([]types.DeploymentSummary, error) {
 	if m.ListDeploymentsFunc != nil {
 		return m.ListDeploymentsFunc(ctx, namespace)
@@ -171,6 +358,97 @@ func (m *MockNomadClient) GetDeployment(ctx context.Context, deploymentID string
 	return types.Deployment{}, nil
 }
 
+func (m *MockNomadClient) PromoteDeployment(ctx context.Context, deploymentID, namespace string, groups []string) (types.DeploymentUpdateResponse, error) {
+	if m.PromoteDeploymentFunc != nil {
+		return m.PromoteDeploymentFunc(ctx, deploymentID, namespace, groups)
+	}
+	return types.DeploymentUpdateResponse{DeploymentID: deploymentID}, nil
+}
+
+func (m *MockNomadClient) FailDeployment(ctx context.Context, deploymentID, namespace string) (types.DeploymentUpdateResponse, error) {
+	if m.FailDeploymentFunc != nil {
+		return m.FailDeploymentFunc(ctx, deploymentID, namespace)
+	}
+	return types.DeploymentUpdateResponse{DeploymentID: deploymentID}, nil
+}
+
+func (m *MockNomadClient) PauseDeployment(ctx context.Context, deploymentID, namespace string, pause bool) (types.DeploymentUpdateResponse, error) {
+	if m.PauseDeploymentFunc != nil {
+		return m.PauseDeploymentFunc(ctx, deploymentID, namespace, pause)
+	}
+	return types.DeploymentUpdateResponse{DeploymentID: deploymentID}, nil
+}
+
+func (m *MockNomadClient) ListEvaluations(ctx context.Context, namespace, status, jobID string) ([]types.Evaluation, error) {
+	if m.ListEvaluationsFunc != nil {
+		return m.ListEvaluationsFunc(ctx, namespace, status, jobID)
+	}
+	return []types.Evaluation{}, nil
+}
+
+func (m *MockNomadClient) FilterEvaluations(ctx context.Context, namespace string, filter types.EvaluationFilter) ([]types.Evaluation, error) {
+	if m.FilterEvaluationsFunc != nil {
+		return m.FilterEvaluationsFunc(ctx, namespace, filter)
+	}
+	return []types.Evaluation{}, nil
+}
+
+func (m *MockNomadClient) GetEvaluation(ctx context.Context, evalID string) (types.Evaluation, error) {
+	if m.GetEvaluationFunc != nil {
+		return m.GetEvaluationFunc(ctx, evalID)
+	}
+	return types.Evaluation{}, nil
+}
+
+func (m *MockNomadClient) ListEvaluationAllocations(ctx context.Context, evalID string) ([]types.Allocation, error) {
+	if m.ListEvalAllocationsFunc != nil {
+		return m.ListEvalAllocationsFunc(ctx, evalID)
+	}
+	return []types.Allocation{}, nil
+}
+
+func (m *MockNomadClient) DeleteEvaluations(ctx context.Context, evalIDs []string) error {
+	if m.DeleteEvaluationsFunc != nil {
+		return m.DeleteEvaluationsFunc(ctx, evalIDs)
+	}
+	return nil
+}
+
+func (m *MockNomadClient) DiagnoseConnection(ctx context.Context) (types.ConnectionDiagnosis, error) {
+	if m.DiagnoseConnectionFunc != nil {
+		return m.DiagnoseConnectionFunc(ctx)
+	}
+	return types.ConnectionDiagnosis{}, nil
+}
+
+func (m *MockNomadClient) ListServices(ctx context.Context, namespace string) ([]types.ServiceRegistrationListStub, error) {
+	if m.ListServicesFunc != nil {
+		return m.ListServicesFunc(ctx, namespace)
+	}
+	return nil, nil
+}
+
+func (m *MockNomadClient) GetService(ctx context.Context, name, namespace string) ([]types.ServiceRegistration, error) {
+	if m.GetServiceFunc != nil {
+		return m.GetServiceFunc(ctx, name, namespace)
+	}
+	return nil, nil
+}
+
+func (m *MockNomadClient) DeleteService(ctx context.Context, name, id, namespace string) error {
+	if m.DeleteServiceFunc != nil {
+		return m.DeleteServiceFunc(ctx, name, id, namespace)
+	}
+	return nil
+}
+
+func (m *MockNomadClient) GetAllocationChecks(ctx context.Context, allocID string) (map[string]types.AllocCheckStatus, error) {
+	if m.GetAllocationChecksFunc != nil {
+		return m.GetAllocationChecksFunc(ctx, allocID)
+	}
+	return nil, nil
+}
+
 func (m *MockNomadClient) ListVolumes(ctx context.Context, nodeID string, pluginID string, nextToken string, perPage int, filter string) ([]types.Volume, error) {
 	if m.ListVolumesFunc != nil {
 		return m.ListVolumesFunc(ctx, nodeID, pluginID, nextToken, perPage, filter)
@@ -192,6 +470,13 @@ func (m *MockNomadClient) DeleteVolume(ctx context.Context, volumeID string) err
 	return nil
 }
 
+func (m *MockNomadClient) ListNamespaceVolumes(ctx context.Context, namespace string) ([]types.VolumeStub, error) {
+	if m.ListNamespaceVolumesFunc != nil {
+		return m.ListNamespaceVolumesFunc(ctx, namespace)
+	}
+	return nil, nil
+}
+
 func (m *MockNomadClient) ListNodes(ctx context.Context, status string) ([]types.NodeSummary, error) {
 	if m.ListNodesFunc != nil {
 		return m.ListNodesFunc(ctx, status)
@@ -206,6 +491,27 @@ func (m *MockNomadClient) GetNode(ctx context.Context, nodeID string) (types.Nod
 	return types.Node{}, nil
 }
 
+func (m *MockNomadClient) GetNodeDetail(ctx context.Context, nodeID string) (types.NodeDetail, error) {
+	if m.GetNodeDetailFunc != nil {
+		return m.GetNodeDetailFunc(ctx, nodeID)
+	}
+	return types.NodeDetail{}, nil
+}
+
+func (m *MockNomadClient) GetNodeConnectivity(ctx context.Context, nodeID string) (types.NodeConnectivity, error) {
+	if m.GetNodeConnectivityFunc != nil {
+		return m.GetNodeConnectivityFunc(ctx, nodeID)
+	}
+	return types.NodeConnectivity{}, nil
+}
+
+func (m *MockNomadClient) ListNodeAllocations(ctx context.Context, nodeID string) ([]types.Allocation, error) {
+	if m.ListNodeAllocationsFunc != nil {
+		return m.ListNodeAllocationsFunc(ctx, nodeID)
+	}
+	return []types.Allocation{}, nil
+}
+
 func (m *MockNomadClient) DrainNode(ctx context.Context, nodeID string, enable bool, deadline int64) (string, error) {
 	if m.DrainNodeFunc != nil {
 		return m.DrainNodeFunc(ctx, nodeID, enable, deadline)
@@ -213,11 +519,25 @@ func (m *MockNomadClient) DrainNode(ctx context.Context, nodeID string, enable b
 	return "", nil
 }
 
-func (m *MockNomadClient) EligibilityNode(ctx context.Context, nodeID string, eligible string) (types.NodeSummary, error) {
+func (m *MockNomadClient) EligibilityNode(ctx context.Context, nodeID string, eligible bool) (types.NodeEligibilityUpdate, error) {
 	if m.EligibilityNodeFunc != nil {
 		return m.EligibilityNodeFunc(ctx, nodeID, eligible)
 	}
-	return types.NodeSummary{}, nil
+	return types.NodeEligibilityUpdate{}, nil
+}
+
+func (m *MockNomadClient) PurgeNode(ctx context.Context, nodeID string) (types.NodePurgeResponse, error) {
+	if m.PurgeNodeFunc != nil {
+		return m.PurgeNodeFunc(ctx, nodeID)
+	}
+	return types.NodePurgeResponse{NodeID: nodeID}, nil
+}
+
+func (m *MockNomadClient) GCNode(ctx context.Context, nodeID string) error {
+	if m.GCNodeFunc != nil {
+		return m.GCNodeFunc(ctx, nodeID)
+	}
+	return nil
 }
 
 func (m *MockNomadClient) ListNamespaces(ctx context.Context) ([]types.Namespace, error) {
@@ -227,6 +547,13 @@ func (m *MockNomadClient) ListNamespaces(ctx context.Context) ([]types.Namespace
 	return []types.Namespace{}, nil
 }
 
+func (m *MockNomadClient) GetNamespace(ctx context.Context, name string) (types.Namespace, error) {
+	if m.GetNamespaceFunc != nil {
+		return m.GetNamespaceFunc(ctx, name)
+	}
+	return types.Namespace{}, nil
+}
+
 func (m *MockNomadClient) CreateNamespace(ctx context.Context, namespace types.Namespace) error {
 	if m.CreateNamespaceFunc != nil {
 		return m.CreateNamespaceFunc(ctx, namespace)
@@ -255,6 +582,13 @@ func (m *MockNomadClient) GetAllocation(ctx context.Context, allocID string) (ty
 	return types.Allocation{}, nil
 }
 
+func (m *MockNomadClient) GetAllocationMetrics(ctx context.Context, allocID string) (types.AllocationPlacement, error) {
+	if m.GetAllocationMetricsFunc != nil {
+		return m.GetAllocationMetricsFunc(ctx, allocID)
+	}
+	return types.AllocationPlacement{}, nil
+}
+
 func (m *MockNomadClient) StopAllocation(ctx context.Context, allocID string) error {
 	if m.StopAllocationFunc != nil {
 		return m.StopAllocationFunc(ctx, allocID)
@@ -262,6 +596,41 @@ func (m *MockNomadClient) StopAllocation(ctx context.Context, allocID string) er
 	return nil
 }
 
+func (m *MockNomadClient) RestartAllocation(ctx context.Context, allocID, task string) error {
+	if m.RestartAllocationFunc != nil {
+		return m.RestartAllocationFunc(ctx, allocID, task)
+	}
+	return nil
+}
+
+func (m *MockNomadClient) ExecAllocation(ctx context.Context, request types.ExecRequest) (types.ExecResult, error) {
+	if m.ExecAllocationFunc != nil {
+		return m.ExecAllocationFunc(ctx, request)
+	}
+	return types.ExecResult{}, nil
+}
+
+func (m *MockNomadClient) ListAllocationFiles(ctx context.Context, allocID, path string) ([]types.AllocFileInfo, error) {
+	if m.ListAllocationFilesFunc != nil {
+		return m.ListAllocationFilesFunc(ctx, allocID, path)
+	}
+	return []types.AllocFileInfo{}, nil
+}
+
+func (m *MockNomadClient) StatAllocationFile(ctx context.Context, allocID, path string) (types.AllocFileInfo, error) {
+	if m.StatAllocationFileFunc != nil {
+		return m.StatAllocationFileFunc(ctx, allocID, path)
+	}
+	return types.AllocFileInfo{}, nil
+}
+
+func (m *MockNomadClient) ReadAllocationFile(ctx context.Context, allocID, path string, offset, limit int64) ([]byte, error) {
+	if m.ReadAllocationFileFunc != nil {
+		return m.ReadAllocationFileFunc(ctx, allocID, path, offset, limit)
+	}
+	return nil, nil
+}
+
 func (m *MockNomadClient) MakeRequest(ctx context.Context, method, path string, queryParams map[string]string, body interface{}) ([]byte, error) {
 	if m.MakeRequestFunc != nil {
 		return m.MakeRequestFunc(ctx, method, path, queryParams, body)
@@ -276,6 +645,20 @@ func (m *MockNomadClient) GetAllocationLogs(ctx context.Context, allocID, task,
 	return "", nil
 }
 
+func (m *MockNomadClient) StreamAllocationLogs(ctx context.Context, request types.LogStreamRequest, handle func(types.LogFrame) error) error {
+	if m.StreamAllocationLogsFunc != nil {
+		return m.StreamAllocationLogsFunc(ctx, request, handle)
+	}
+	return nil
+}
+
+func (m *MockNomadClient) ReadAllocationLogs(ctx context.Context, request types.LogStreamRequest, limit int64) (types.LogPage, error) {
+	if m.ReadAllocationLogsFunc != nil {
+		return m.ReadAllocationLogsFunc(ctx, request, limit)
+	}
+	return types.LogPage{}, nil
+}
+
 func (m *MockNomadClient) ListVariables(ctx context.Context, namespace, prefix string, nextToken string, perPage int, filter string) ([]types.Variable, error) {
 	if m.ListVariablesFunc != nil {
 		return m.ListVariablesFunc(ctx, namespace, prefix, nextToken, perPage, filter)
@@ -430,6 +813,237 @@ func (m *MockNomadClient) ListClusterPeers(ctx context.Context) ([]byte, error)
 	return []byte{}, nil
 }
 
+func (m *MockNomadClient) ListRegions(ctx context.Context) ([]string, error) {
+	if m.ListRegionsFunc != nil {
+		return m.ListRegionsFunc(ctx)
+	}
+	return []string{}, nil
+}
+
+func (m *MockNomadClient) StreamEvents(ctx context.Context, request types.EventStreamRequest, handle func(types.EventFrame) error) error {
+	if m.StreamEventsFunc != nil {
+		return m.StreamEventsFunc(ctx, request, handle)
+	}
+	return nil
+}
+
+func (m *MockNomadClient) GetRaftConfiguration(ctx context.Context) (types.RaftConfiguration, error) {
+	if m.GetRaftConfigurationFunc != nil {
+		return m.GetRaftConfigurationFunc(ctx)
+	}
+	return types.RaftConfiguration{}, nil
+}
+
+func (m *MockNomadClient) ListAgentMembers(ctx context.Context) ([]types.AgentMember, error) {
+	if m.ListAgentMembersFunc != nil {
+		return m.ListAgentMembersFunc(ctx)
+	}
+	return []types.AgentMember{}, nil
+}
+
+func (m *MockNomadClient) GetAutopilotHealth(ctx context.Context) (types.AutopilotHealth, error) {
+	if m.GetAutopilotHealthFunc != nil {
+		return m.GetAutopilotHealthFunc(ctx)
+	}
+	return types.AutopilotHealth{}, nil
+}
+
+func (m *MockNomadClient) RemoveRaftPeer(ctx context.Context, id, address string) error {
+	if m.RemoveRaftPeerFunc != nil {
+		return m.RemoveRaftPeerFunc(ctx, id, address)
+	}
+	return nil
+}
+
+func (m *MockNomadClient) TransferRaftLeadership(ctx context.Context, id, address string) error {
+	if m.TransferLeadershipFunc != nil {
+		return m.TransferLeadershipFunc(ctx, id, address)
+	}
+	return nil
+}
+
+func (m *MockNomadClient) SaveSnapshot(ctx context.Context, w io.Writer, stale bool) (int64, error) {
+	if m.SaveSnapshotFunc != nil {
+		return m.SaveSnapshotFunc(ctx, w, stale)
+	}
+	return 0, nil
+}
+
+func (m *MockNomadClient) RestoreSnapshot(ctx context.Context, r io.Reader) error {
+	if m.RestoreSnapshotFunc != nil {
+		return m.RestoreSnapshotFunc(ctx, r)
+	}
+	return nil
+}
+
+func (m *MockNomadClient) GetLicense(ctx context.Context) (types.LicenseReply, error) {
+	if m.GetLicenseFunc != nil {
+		return m.GetLicenseFunc(ctx)
+	}
+	return types.LicenseReply{}, nil
+}
+
+func (m *MockNomadClient) PutLicense(ctx context.Context, license string, force bool) error {
+	if m.PutLicenseFunc != nil {
+		return m.PutLicenseFunc(ctx, license, force)
+	}
+	return nil
+}
+
+func (m *MockNomadClient) RevertJob(ctx context.Context, jobID, namespace string, version int, opts types.JobRevertOptions) (types.JobRegisterResponse, error) {
+	if m.RevertJobFunc != nil {
+		return m.RevertJobFunc(ctx, jobID, namespace, version, opts)
+	}
+	return types.JobRegisterResponse{}, nil
+}
+
+func (m *MockNomadClient) ListNodePools(ctx context.Context, prefix string) ([]types.NodePool, error) {
+	if m.ListNodePoolsFunc != nil {
+		return m.ListNodePoolsFunc(ctx, prefix)
+	}
+	return []types.NodePool{}, nil
+}
+
+func (m *MockNomadClient) GetNodePool(ctx context.Context, name string) (types.NodePool, error) {
+	if m.GetNodePoolFunc != nil {
+		return m.GetNodePoolFunc(ctx, name)
+	}
+	return types.NodePool{}, nil
+}
+
+func (m *MockNomadClient) UpsertNodePool(ctx context.Context, pool types.NodePool) error {
+	if m.UpsertNodePoolFunc != nil {
+		return m.UpsertNodePoolFunc(ctx, pool)
+	}
+	return nil
+}
+
+func (m *MockNomadClient) DeleteNodePool(ctx context.Context, name string) error {
+	if m.DeleteNodePoolFunc != nil {
+		return m.DeleteNodePoolFunc(ctx, name)
+	}
+	return nil
+}
+
+func (m *MockNomadClient) ListNodePoolNodes(ctx context.Context, name string) ([]types.NodeListStub, error) {
+	if m.ListNodePoolNodesFunc != nil {
+		return m.ListNodePoolNodesFunc(ctx, name)
+	}
+	return []types.NodeListStub{}, nil
+}
+
+func (m *MockNomadClient) GetJobScaleStatus(ctx context.Context, jobID, namespace string) (types.JobScaleStatus, error) {
+	if m.GetJobScaleStatusFunc != nil {
+		return m.GetJobScaleStatusFunc(ctx, jobID, namespace)
+	}
+	return types.JobScaleStatus{}, nil
+}
+
+func (m *MockNomadClient) ListScalingPolicies(ctx context.Context, namespace, jobID, policyType string) ([]types.ScalingPolicyListStub, error) {
+	if m.ListScalingPoliciesFunc != nil {
+		return m.ListScalingPoliciesFunc(ctx, namespace, jobID, policyType)
+	}
+	return []types.ScalingPolicyListStub{}, nil
+}
+
+func (m *MockNomadClient) GetScalingPolicy(ctx context.Context, policyID string) (types.ScalingPolicy, error) {
+	if m.GetScalingPolicyFunc != nil {
+		return m.GetScalingPolicyFunc(ctx, policyID)
+	}
+	return types.ScalingPolicy{}, nil
+}
+
+func (m *MockNomadClient) ListQuotas(ctx context.Context) ([]types.QuotaSpec, error) {
+	if m.ListQuotasFunc != nil {
+		return m.ListQuotasFunc(ctx)
+	}
+	return []types.QuotaSpec{}, nil
+}
+
+func (m *MockNomadClient) GetQuota(ctx context.Context, name string) (types.QuotaSpec, error) {
+	if m.GetQuotaFunc != nil {
+		return m.GetQuotaFunc(ctx, name)
+	}
+	return types.QuotaSpec{}, nil
+}
+
+func (m *MockNomadClient) UpsertQuota(ctx context.Context, quota types.QuotaSpec) error {
+	if m.UpsertQuotaFunc != nil {
+		return m.UpsertQuotaFunc(ctx, quota)
+	}
+	return nil
+}
+
+func (m *MockNomadClient) DeleteQuota(ctx context.Context, name string) error {
+	if m.DeleteQuotaFunc != nil {
+		return m.DeleteQuotaFunc(ctx, name)
+	}
+	return nil
+}
+
+func (m *MockNomadClient) ListQuotaUsages(ctx context.Context) ([]types.QuotaUsage, error) {
+	if m.ListQuotaUsagesFunc != nil {
+		return m.ListQuotaUsagesFunc(ctx)
+	}
+	return []types.QuotaUsage{}, nil
+}
+
+func (m *MockNomadClient) GetQuotaUsage(ctx context.Context, name string) (types.QuotaUsage, error) {
+	if m.GetQuotaUsageFunc != nil {
+		return m.GetQuotaUsageFunc(ctx, name)
+	}
+	return types.QuotaUsage{}, nil
+}
+
+func (m *MockNomadClient) GetAutopilotConfiguration(ctx context.Context) (types.AutopilotConfiguration, error) {
+	if m.GetAutopilotConfigFunc != nil {
+		return m.GetAutopilotConfigFunc(ctx)
+	}
+	return types.AutopilotConfiguration{}, nil
+}
+
+func (m *MockNomadClient) SetAutopilotConfiguration(ctx context.Context, config types.AutopilotConfiguration, cas bool) (bool, error) {
+	if m.SetAutopilotConfigFunc != nil {
+		return m.SetAutopilotConfigFunc(ctx, config, cas)
+	}
+	return true, nil
+}
+
+func (m *MockNomadClient) GetNomadVersion(ctx context.Context) (string, error) {
+	if m.GetNomadVersionFunc != nil {
+		return m.GetNomadVersionFunc(ctx)
+	}
+	return "", nil
+}
+
+func (m *MockNomadClient) GetAgentMembers(ctx context.Context) (types.AgentMembers, error) {
+	if m.GetAgentMembersFunc != nil {
+		return m.GetAgentMembersFunc(ctx)
+	}
+	return types.AgentMembers{}, nil
+}
+
+func (m *MockNomadClient) GetAgentSelf(ctx context.Context) (types.AgentSelf, error) {
+	if m.GetAgentSelfFunc != nil {
+		return m.GetAgentSelfFunc(ctx)
+	}
+	return types.AgentSelf{}, nil
+}
+
+func (m *MockNomadClient) GetAgentHealth(ctx context.Context) (types.AgentHealth, error) {
+	if m.GetAgentHealthFunc != nil {
+		return m.GetAgentHealthFunc(ctx)
+	}
+	return types.AgentHealth{}, nil
+}
+
+func (m *MockNomadClient) GetSchedulerConfig(ctx context.Context) (types.SchedulerConfiguration, error) {
+	if m.GetSchedulerConfigFunc != nil {
+		return m.GetSchedulerConfigFunc(ctx)
+	}
+	return types.SchedulerConfiguration{}, nil
+}
+
 func (m *MockNomadClient) SetToken(token string) {
 	m.token = token
 }
@@ -438,5 +1052,21 @@ func (m *MockNomadClient) GetToken() string {
 	return m.token
 }
 
+func (m *MockNomadClient) SetAddress(address string) {
+	m.address = address
+}
+
+func (m *MockNomadClient) GetAddress() string {
+	return m.address
+}
+
+func (m *MockNomadClient) GetLeader(ctx context.Context) (string, error) {
+	if m.GetLeaderFunc != nil {
+		return m.GetLeaderFunc(ctx)
+	}
+	return "", nil
+}
+
 func (m *MockNomadClient) SetDefaultTailLines(lines int) error { return nil }
 func (m *MockNomadClient) GetDefaultTailLines() int            { return 100 }
+func (m *MockNomadClient) GetMaxLogBytes() int64               { return 1 << 20 }
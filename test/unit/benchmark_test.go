@@ -48,16 +48,16 @@ func BenchmarkMockClientGetJob(b *testing.B) {
 // BenchmarkMockClientRunJob benchmarks the mock client RunJob method directly
 func BenchmarkMockClientRunJob(b *testing.B) {
 	mockClient := &mocks.MockNomadClient{}
-	mockClient.RunJobFunc = func(_ context.Context, jobSpec string, detach bool) (map[string]interface{}, error) {
-		return map[string]interface{}{
-			"EvalID":         "eval-123",
-			"JobModifyIndex": 1,
+	mockClient.RunJobFunc = func(_ context.Context, jobSpec, namespace string, detach bool) (types.JobRegisterResponse, error) {
+		return types.JobRegisterResponse{
+			EvalID:         "eval-123",
+			JobModifyIndex: 1,
 		}, nil
 	}
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := mockClient.RunJob(context.Background(), testdata.SampleJobSpecs["simple"], false)
+		_, err := mockClient.RunJob(context.Background(), testdata.SampleJobSpecs["simple"], "", false)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/kocierik/mcp-nomad/test/mocks"
+	"github.com/kocierik/mcp-nomad/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCapabilitiesTestServer(version string, versionErr error) *server.MCPServer {
+	s := server.NewMCPServer("test", "0.0.0", server.WithResourceCapabilities(true, true))
+	noop := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	categories := tools.ToolCategories{}
+	categories.Track(s, "jobs", func() {
+		s.AddTool(mcp.NewTool("get_job", mcp.WithReadOnlyHintAnnotation(true)), noop)
+		s.AddTool(mcp.NewTool("stop_job", mcp.WithString("namespace")), noop)
+	})
+	categories.Track(s, "nodes", func() {
+		s.AddTool(mcp.NewTool("drain_node"), noop)
+	})
+	categories.Track(s, "variables", func() {
+		s.AddTool(mcp.NewTool("list_variables", mcp.WithDescription("List variables"), mcp.WithReadOnlyHintAnnotation(true)), noop)
+	})
+
+	client := &mocks.MockNomadClient{
+		GetNomadVersionFunc: func(ctx context.Context) (string, error) {
+			return version, versionErr
+		},
+	}
+	tools.RegisterCapabilityResources(s, client, categories, tools.CapabilityConfig{SandboxNamespace: "sandbox"}, testLogger())
+	return s
+}
+
+func readResourceText(t *testing.T, s *server.MCPServer, uri string) string {
+	t.Helper()
+
+	msg := `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"` + uri + `"}}`
+	resp, ok := s.HandleMessage(context.Background(), json.RawMessage(msg)).(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	result, ok := resp.Result.(mcp.ReadResourceResult)
+	require.True(t, ok, "unexpected result %#v", resp.Result)
+	text, ok := result.Contents[0].(mcp.TextResourceContents)
+	require.True(t, ok)
+	return text.Text
+}
+
+func TestCapabilitiesResource_describesRegisteredTools(t *testing.T) {
+	t.Parallel()
+
+	s := newCapabilitiesTestServer("1.3.5", nil)
+
+	var capabilities []tools.ToolCapability
+	require.NoError(t, json.Unmarshal([]byte(readResourceText(t, s, "system://capabilities")), &capabilities))
+	require.Len(t, capabilities, 4)
+
+	byName := map[string]tools.ToolCapability{}
+	for _, c := range capabilities {
+		byName[c.Name] = c
+	}
+	assert.Equal(t, "jobs", byName["get_job"].Category)
+	assert.Equal(t, "read", byName["get_job"].Access)
+	assert.Equal(t, "write", byName["stop_job"].Access)
+	assert.True(t, byName["stop_job"].Enabled)
+	assert.Equal(t, "nodes", byName["drain_node"].Category)
+	assert.False(t, byName["drain_node"].Enabled)
+	require.NotNil(t, byName["list_variables"].SupportedByCluster)
+	assert.False(t, *byName["list_variables"].SupportedByCluster)
+	assert.Nil(t, byName["get_job"].SupportedByCluster)
+}
+
+func TestToolsDocsResource_generatedFromRegistry(t *testing.T) {
+	t.Parallel()
+
+	s := newCapabilitiesTestServer("1.8.0", nil)
+	docs := readResourceText(t, s, "docs://tools")
+
+	assert.Contains(t, docs, "Generated for Nomad 1.8.0")
+	assert.Contains(t, docs, "## variables")
+	assert.Contains(t, docs, "- **list_variables** (read): List variables")
+	assert.Contains(t, docs, "requires Nomad 1.4.0")
+	assert.NotContains(t, docs, "not supported by the connected cluster")
+	assert.Contains(t, docs, "disabled: not namespace-scoped")
+	assert.Contains(t, docs, "`system://capabilities`")
+	assert.Contains(t, docs, "`docs://tools`")
+}
+
+func TestToolsDocsResource_unknownVersion(t *testing.T) {
+	t.Parallel()
+
+	s := newCapabilitiesTestServer("", errors.New("connection refused"))
+	docs := readResourceText(t, s, "docs://tools")
+
+	assert.Contains(t, docs, "without a detected Nomad version")
+}
@@ -134,23 +134,23 @@ func TestNomadClient_RunJob(t *testing.T) {
 		name           string
 		jobSpec        string
 		detach         bool
-		mockFunc       func(context.Context, string, bool) (map[string]interface{}, error)
-		expectedResult map[string]interface{}
+		mockFunc       func(context.Context, string, string, bool) (types.JobRegisterResponse, error)
+		expectedResult types.JobRegisterResponse
 		expectedError  string
 	}{
 		{
 			name:    "successful run job",
 			jobSpec: testdata.SampleJobSpecs["simple"],
 			detach:  false,
-			mockFunc: func(_ context.Context, jobSpec string, detach bool) (map[string]interface{}, error) {
-				return map[string]interface{}{
-					"EvalID":         "eval-123",
-					"JobModifyIndex": 1,
+			mockFunc: func(_ context.Context, jobSpec, namespace string, detach bool) (types.JobRegisterResponse, error) {
+				return types.JobRegisterResponse{
+					EvalID:         "eval-123",
+					JobModifyIndex: 1,
 				}, nil
 			},
-			expectedResult: map[string]interface{}{
-				"EvalID":         "eval-123",
-				"JobModifyIndex": 1,
+			expectedResult: types.JobRegisterResponse{
+				EvalID:         "eval-123",
+				JobModifyIndex: 1,
 			},
 			expectedError: "",
 		},
@@ -158,13 +158,13 @@ func TestNomadClient_RunJob(t *testing.T) {
 			name:    "run job with detach",
 			jobSpec: testdata.SampleJobSpecs["simple"],
 			detach:  true,
-			mockFunc: func(_ context.Context, jobSpec string, detach bool) (map[string]interface{}, error) {
-				return map[string]interface{}{
-					"EvalID": "eval-456",
+			mockFunc: func(_ context.Context, jobSpec, namespace string, detach bool) (types.JobRegisterResponse, error) {
+				return types.JobRegisterResponse{
+					EvalID: "eval-456",
 				}, nil
 			},
-			expectedResult: map[string]interface{}{
-				"EvalID": "eval-456",
+			expectedResult: types.JobRegisterResponse{
+				EvalID: "eval-456",
 			},
 			expectedError: "",
 		},
@@ -172,10 +172,10 @@ func TestNomadClient_RunJob(t *testing.T) {
 			name:    "invalid job spec",
 			jobSpec: testdata.SampleJobSpecs["invalid"],
 			detach:  false,
-			mockFunc: func(_ context.Context, jobSpec string, detach bool) (map[string]interface{}, error) {
-				return nil, errors.New("invalid job specification")
+			mockFunc: func(_ context.Context, jobSpec, namespace string, detach bool) (types.JobRegisterResponse, error) {
+				return types.JobRegisterResponse{}, errors.New("invalid job specification")
 			},
-			expectedResult: nil,
+			expectedResult: types.JobRegisterResponse{},
 			expectedError:  "invalid job specification",
 		},
 	}
@@ -185,7 +185,7 @@ func TestNomadClient_RunJob(t *testing.T) {
 			mockClient := &mocks.MockNomadClient{}
 			mockClient.RunJobFunc = tt.mockFunc
 
-			result, err := mockClient.RunJob(context.Background(), tt.jobSpec, tt.detach)
+			result, err := mockClient.RunJob(context.Background(), tt.jobSpec, "", tt.detach)
 
 			if tt.expectedError != "" {
 				require.Error(t, err)
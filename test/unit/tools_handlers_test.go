@@ -1,17 +1,36 @@
 package unit
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/kocierik/mcp-nomad/test/mocks"
 	"github.com/kocierik/mcp-nomad/tools"
 	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func testLogger() *log.Logger {
@@ -82,9 +101,9 @@ func TestScaleJobHandler_usesEffectiveToolNamespace(t *testing.T) {
 
 	var got string
 	mock := &mocks.MockNomadClient{}
-	mock.ScaleTaskGroupFunc = func(_ context.Context, jobID string, group string, count int, namespace string) error {
+	mock.ScaleTaskGroupFunc = func(_ context.Context, jobID string, group string, count int, namespace string) (types.JobRegisterResponse, error) {
 		got = namespace
-		return nil
+		return types.JobRegisterResponse{}, nil
 	}
 
 	h := tools.ScaleJobHandler(mock, testLogger())
@@ -186,3 +205,4176 @@ func TestListAllocationsHandler_passesNamespaceAndJob(t *testing.T) {
 	assert.Equal(t, "apps", gotNs)
 	assert.Equal(t, "demo", gotJob)
 }
+
+func TestPurgeDeadJobsHandler_rejectsZeroOlderThan(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.StopJobFunc = func(_ context.Context, jobID, _ string, _ types.JobStopOptions) (types.JobDeregisterResponse, error) {
+		t.Errorf("job %s was purged", jobID)
+		return types.JobDeregisterResponse{}, nil
+	}
+
+	h := tools.PurgeDeadJobsHandler(mock, testLogger())
+	for _, olderThan := range []string{"0s", "0d"} {
+		res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+			"older_than": olderThan,
+			"dry_run":    false,
+		}}})
+		require.NoError(t, err)
+		require.True(t, res.IsError, olderThan)
+		assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "older_than must be a positive duration")
+	}
+}
+
+func TestPurgeDeadJobsHandler_dryRunSkipsRecentJobs(t *testing.T) {
+	t.Parallel()
+
+	old := time.Now().Add(-72 * time.Hour).UnixNano()
+	recent := time.Now().Add(-time.Hour).UnixNano()
+
+	var gotStatus string
+	var purged []string
+	mock := &mocks.MockNomadClient{}
+	mock.ListJobsFunc = func(_ context.Context, _ string, status string) ([]types.JobSummary, error) {
+		gotStatus = status
+		return []types.JobSummary{
+			{ID: "old-batch", Namespace: "apps", Status: "dead", SubmitTime: old},
+			{ID: "fresh", Namespace: "apps", Status: "dead", SubmitTime: recent},
+		}, nil
+	}
+	mock.StopJobFunc = func(_ context.Context, jobID, _ string, _ types.JobStopOptions) (types.JobDeregisterResponse, error) {
+		purged = append(purged, jobID)
+		return types.JobDeregisterResponse{}, nil
+	}
+
+	h := tools.PurgeDeadJobsHandler(mock, testLogger())
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"namespace":  "*",
+		"older_than": "24h",
+	}}}
+
+	res, err := h(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, "dead", gotStatus)
+	assert.Empty(t, purged)
+
+	text, ok := res.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "old-batch")
+	assert.NotContains(t, text.Text, "fresh")
+}
+
+func TestListJobsHandler_includeRolloutAnnotatesJobs(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.ListJobsFunc = func(_ context.Context, _, _ string) ([]types.JobSummary, error) {
+		return []types.JobSummary{{ID: "web"}}, nil
+	}
+	mock.GetJobFunc = func(_ context.Context, jobID, _ string) (types.Job, error) {
+		return types.Job{ID: jobID, Name: jobID, Status: "running"}, nil
+	}
+	mock.GetJobDeploymentFunc = func(_ context.Context, _, _ string) (types.JobDeployment, error) {
+		return types.JobDeployment{ID: "dep-1", Status: "running", JobVersion: 3}, nil
+	}
+	mock.ListJobEvaluationsFunc = func(_ context.Context, _, _ string) ([]types.Evaluation, error) {
+		return []types.Evaluation{{Status: "pending"}, {Status: "complete"}, {Status: "pending"}}, nil
+	}
+
+	h := tools.ListJobsHandler(mock, testLogger())
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"include_rollout": true,
+	}}}
+
+	res, err := h(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	text, ok := res.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, text.Text, `"ID": "dep-1"`)
+	assert.Contains(t, text.Text, `"PendingEvaluations": 2`)
+}
+
+func TestListJobsHandler_includeRolloutReadsEachJobInItsNamespace(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	lookups := map[string][]string{}
+	record := func(call, jobID, namespace string) {
+		mu.Lock()
+		defer mu.Unlock()
+		lookups[jobID] = append(lookups[jobID], call+":"+namespace)
+	}
+
+	mock := &mocks.MockNomadClient{}
+	mock.ListJobsFunc = func(_ context.Context, _, _ string) ([]types.JobSummary, error) {
+		return []types.JobSummary{{ID: "web", Namespace: "prod"}, {ID: "api", Namespace: "dev"}}, nil
+	}
+	mock.GetJobFunc = func(_ context.Context, jobID, namespace string) (types.Job, error) {
+		record("job", jobID, namespace)
+		return types.Job{ID: jobID, Name: jobID, Status: "running"}, nil
+	}
+	mock.GetJobSummaryFunc = func(_ context.Context, jobID, namespace string) (types.JobSummary, error) {
+		record("summary", jobID, namespace)
+		return types.JobSummary{ID: jobID}, nil
+	}
+	mock.GetJobDeploymentFunc = func(_ context.Context, jobID, namespace string) (types.JobDeployment, error) {
+		record("deployment", jobID, namespace)
+		return types.JobDeployment{ID: "dep-" + jobID, Status: "running"}, nil
+	}
+	mock.ListJobEvaluationsFunc = func(_ context.Context, jobID, namespace string) ([]types.Evaluation, error) {
+		record("evaluations", jobID, namespace)
+		if jobID == "api" {
+			return nil, errors.New("permission denied")
+		}
+		return []types.Evaluation{{Status: "complete"}}, nil
+	}
+
+	h := tools.ListJobsHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"namespace":       "*",
+		"include_rollout": true,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	assert.ElementsMatch(t, []string{"job:prod", "summary:prod", "deployment:prod", "evaluations:prod"}, lookups["web"])
+	assert.ElementsMatch(t, []string{"job:dev", "summary:dev", "deployment:dev", "evaluations:dev"}, lookups["api"])
+
+	var jobs []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &jobs))
+	require.Len(t, jobs, 2)
+	for _, job := range jobs {
+		switch job["ID"] {
+		case "web":
+			assert.EqualValues(t, 0, job["PendingEvaluations"], "listed evaluations, none pending")
+		case "api":
+			assert.NotContains(t, job, "PendingEvaluations", "evaluations could not be listed")
+		}
+	}
+}
+
+func TestEligibilityNodeHandler_validatesEligible(t *testing.T) {
+	t.Parallel()
+
+	var calls []bool
+	mock := &mocks.MockNomadClient{}
+	mock.EligibilityNodeFunc = func(_ context.Context, nodeID string, eligible bool) (types.NodeEligibilityUpdate, error) {
+		calls = append(calls, eligible)
+		return types.NodeEligibilityUpdate{NodeID: nodeID, EvalIDs: []string{"e1"}}, nil
+	}
+	h := tools.EligibilityNodeHandler(mock, testLogger())
+
+	call := func(eligible interface{}) *mcp.CallToolResult {
+		res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+			"node_id":  "n1",
+			"eligible": eligible,
+		}}})
+		require.NoError(t, err)
+		return res
+	}
+
+	res := call(false)
+	require.False(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, `"e1"`)
+	require.False(t, call("eligible").IsError)
+	assert.True(t, call("yes").IsError)
+	assert.Equal(t, []bool{false, true}, calls)
+}
+
+func TestGetTaskHandler_findsTaskAcrossGroups(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobFunc = func(_ context.Context, jobID, _ string) (types.Job, error) {
+		return types.Job{ID: jobID, TaskGroups: []types.TaskGroup{
+			{Name: "web", Tasks: []types.Task{{Name: "server", Driver: "docker"}, {Name: "sidecar"}}},
+			{Name: "worker", Tasks: []types.Task{{Name: "sidecar"}}},
+		}}, nil
+	}
+	h := tools.GetTaskHandler(mock, testLogger())
+
+	call := func(args map[string]interface{}) *mcp.CallToolResult {
+		args["job_id"] = "app"
+		res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}})
+		require.NoError(t, err)
+		return res
+	}
+
+	res := call(map[string]interface{}{"task": "server"})
+	require.False(t, res.IsError)
+	text := res.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"Group": "web"`)
+	assert.Contains(t, text, `"Driver": "docker"`)
+
+	assert.True(t, call(map[string]interface{}{"task": "sidecar"}).IsError)
+	assert.False(t, call(map[string]interface{}{"task": "sidecar", "group": "worker"}).IsError)
+	assert.True(t, call(map[string]interface{}{"task": "missing"}).IsError)
+}
+
+func TestCheckJobArtifactsHandler_probesSources(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.tgz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, http.MethodHead, r.Method)
+		assert.Empty(t, r.URL.Query().Get("checksum"))
+	}))
+	defer srv.Close()
+
+	mock := &mocks.MockNomadClient{}
+	mock.ParseJobSpecFunc = func(_ context.Context, _ string) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"ID": "app",
+			"TaskGroups": []interface{}{map[string]interface{}{
+				"Name": "web",
+				"Tasks": []interface{}{map[string]interface{}{
+					"Name": "server",
+					"Artifacts": []interface{}{
+						map[string]interface{}{"GetterSource": srv.URL + "/app.tgz?checksum=sha256:abc"},
+						map[string]interface{}{"GetterSource": srv.URL + "/missing.tgz"},
+						map[string]interface{}{"GetterSource": "git::https://example.com/repo.git"},
+					},
+				}},
+			}},
+		}, nil
+	}
+
+	h := tools.CheckJobArtifactsHandler(mock, []string{"127.0.0.1"}, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_spec": "job \"app\" {}",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var checks []tools.ArtifactCheck
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &checks))
+	require.Len(t, checks, 3)
+	assert.Equal(t, "ok", checks[0].Status)
+	assert.Equal(t, "unreachable", checks[1].Status)
+	assert.Equal(t, http.StatusNotFound, checks[1].HTTPStatus)
+	assert.Equal(t, "skipped", checks[2].Status)
+
+	blocked := tools.CheckJobArtifactsHandler(mock, []string{".example.com"}, testLogger())
+	res, err = blocked(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_spec": "job \"app\" {}",
+	}}})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &checks))
+	assert.Equal(t, "blocked", checks[0].Status)
+}
+
+func TestCheckJobArtifactsHandler_blocksInternalAddressesUnlessListed(t *testing.T) {
+	t.Parallel()
+
+	reached := false
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		reached = true
+	}))
+	defer srv.Close()
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+
+	mock := &mocks.MockNomadClient{}
+	mock.ParseJobSpecFunc = func(_ context.Context, _ string) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"ID": "app",
+			"TaskGroups": []interface{}{map[string]interface{}{
+				"Name": "web",
+				"Tasks": []interface{}{map[string]interface{}{
+					"Name": "server",
+					"Artifacts": []interface{}{
+						map[string]interface{}{"GetterSource": srv.URL + "/app.tgz"},
+						map[string]interface{}{"GetterSource": "http://localhost:" + port + "/app.tgz"},
+						map[string]interface{}{"GetterSource": "http://169.254.169.254/latest/meta-data/"},
+					},
+				}},
+			}},
+		}, nil
+	}
+
+	h := tools.CheckJobArtifactsHandler(mock, nil, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_spec": "job \"app\" {}",
+	}}})
+	require.NoError(t, err)
+
+	var checks []tools.ArtifactCheck
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &checks))
+	require.Len(t, checks, 3)
+	for _, check := range checks {
+		assert.Equal(t, "blocked", check.Status, check.URL)
+		assert.Contains(t, check.Error, "internal address")
+	}
+	assert.False(t, reached)
+}
+
+func TestCheckJobArtifactsHandler_blocksRedirectsToDisallowedHosts(t *testing.T) {
+	t.Parallel()
+
+	reachedInternal := false
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			reachedInternal = true
+			return
+		}
+		// Same server under a host name that is not allowlisted.
+		http.Redirect(w, r, strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)+"/internal", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	mock := &mocks.MockNomadClient{}
+	mock.ParseJobSpecFunc = func(_ context.Context, _ string) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"ID": "app",
+			"TaskGroups": []interface{}{map[string]interface{}{
+				"Name": "web",
+				"Tasks": []interface{}{map[string]interface{}{
+					"Name":      "server",
+					"Artifacts": []interface{}{map[string]interface{}{"GetterSource": srv.URL + "/app.tgz"}},
+				}},
+			}},
+		}, nil
+	}
+
+	h := tools.CheckJobArtifactsHandler(mock, []string{"127.0.0.1"}, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_spec": "job \"app\" {}",
+	}}})
+	require.NoError(t, err)
+
+	var checks []tools.ArtifactCheck
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &checks))
+	require.Len(t, checks, 1)
+	assert.Equal(t, "blocked", checks[0].Status)
+	assert.Contains(t, checks[0].Error, "localhost")
+	assert.False(t, reachedInternal)
+}
+
+func TestInspectJobImagesHandler_parsesReferences(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobFunc = func(_ context.Context, jobID, _ string) (types.Job, error) {
+		return types.Job{ID: jobID, TaskGroups: []types.TaskGroup{{Name: "web", Tasks: []types.Task{
+			{Name: "nginx", Driver: "docker", Config: map[string]interface{}{"image": "nginx"}},
+			{Name: "app", Driver: "docker", Config: map[string]interface{}{"image": "ghcr.io/acme/app:1.2.3"}},
+			{Name: "cache", Driver: "podman", Config: map[string]interface{}{"image": "localhost:5000/redis@sha256:abc"}},
+			{Name: "script", Driver: "exec", Config: map[string]interface{}{"command": "/bin/true"}},
+		}}}}, nil
+	}
+
+	h := tools.InspectJobImagesHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id": "app",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var images []tools.ImageReference
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &images))
+	require.Len(t, images, 3)
+
+	assert.Equal(t, "docker.io", images[0].Registry)
+	assert.Equal(t, "library/nginx", images[0].Repository)
+	assert.True(t, images[0].UsesLatest)
+
+	assert.Equal(t, "ghcr.io", images[1].Registry)
+	assert.Equal(t, "acme/app", images[1].Repository)
+	assert.Equal(t, "1.2.3", images[1].Tag)
+	assert.False(t, images[1].UsesLatest)
+
+	assert.Equal(t, "localhost:5000", images[2].Registry)
+	assert.Equal(t, "sha256:abc", images[2].Digest)
+	assert.Empty(t, images[2].Tag)
+	assert.Nil(t, images[2].Exists)
+}
+
+func TestMatchNodesForJobHandler_reportsFilteringConstraint(t *testing.T) {
+	t.Parallel()
+
+	nodes := map[string]types.NodeDetail{
+		"n1": {ID: "n1", Name: "linux-new", Datacenter: "dc1", Status: "ready", SchedulingEligibility: "eligible",
+			Attributes: map[string]string{"kernel.name": "linux", "nomad.version": "1.8.2"}, Meta: map[string]string{"rack": "r1"}},
+		"n2": {ID: "n2", Name: "linux-old", Datacenter: "dc1", Status: "ready", SchedulingEligibility: "eligible",
+			Attributes: map[string]string{"kernel.name": "linux", "nomad.version": "1.5.0"}},
+		"n3": {ID: "n3", Name: "windows", Datacenter: "dc1", Status: "ready", SchedulingEligibility: "eligible",
+			Attributes: map[string]string{"kernel.name": "windows", "nomad.version": "1.8.2"}},
+		"n4": {ID: "n4", Name: "elsewhere", Datacenter: "dc2", Status: "ready", SchedulingEligibility: "eligible"},
+	}
+
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobFunc = func(_ context.Context, jobID, _ string) (types.Job, error) {
+		return types.Job{
+			ID:          jobID,
+			Datacenters: []string{"dc1"},
+			Constraints: []types.Constraint{{LTarget: "${attr.kernel.name}", RTarget: "linux", Operand: "="}},
+			TaskGroups: []types.TaskGroup{{
+				Name:        "web",
+				Constraints: []types.Constraint{{LTarget: "${attr.nomad.version}", RTarget: ">= 1.6", Operand: "version"}},
+				Affinities:  []types.Affinity{{LTarget: "${meta.rack}", RTarget: "r1", Operand: "=", Weight: 50}},
+			}},
+		}, nil
+	}
+	mock.ListNodesFunc = func(_ context.Context, _ string) ([]types.NodeSummary, error) {
+		return []types.NodeSummary{{ID: "n1"}, {ID: "n2"}, {ID: "n3"}, {ID: "n4"}}, nil
+	}
+	mock.GetNodeDetailFunc = func(_ context.Context, nodeID string) (types.NodeDetail, error) {
+		return nodes[nodeID], nil
+	}
+
+	h := tools.MatchNodesForJobHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id": "app",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var match tools.JobNodeMatch
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &match))
+	require.Len(t, match.Eligible, 1)
+	assert.Equal(t, "n1", match.Eligible[0].NodeID)
+	assert.Equal(t, []string{"web"}, match.Eligible[0].Groups)
+	assert.InDelta(t, 1.0, match.Eligible[0].AffinityScore, 0.001)
+
+	reasons := map[string]string{}
+	for _, n := range match.Ineligible {
+		require.NotEmpty(t, n.FilteredBy)
+		reasons[n.NodeID] = n.FilteredBy[0]
+	}
+	assert.Contains(t, reasons["n2"], "version")
+	assert.Contains(t, reasons["n3"], "${attr.kernel.name} = linux")
+	assert.Contains(t, reasons["n4"], "datacenter dc2")
+}
+
+func TestCheckSystemJobCoverageHandler_listsNodesWithoutAllocation(t *testing.T) {
+	t.Parallel()
+
+	nodes := map[string]types.NodeDetail{
+		"n1": {ID: "n1", Name: "a", Datacenter: "dc1", Status: "ready", SchedulingEligibility: "eligible"},
+		"n2": {ID: "n2", Name: "b", Datacenter: "dc1", Status: "ready", SchedulingEligibility: "eligible"},
+		"n3": {ID: "n3", Name: "c", Datacenter: "dc2", Status: "ready", SchedulingEligibility: "eligible"},
+		"n4": {ID: "n4", Name: "d", Datacenter: "dc1", Status: "ready", SchedulingEligibility: "ineligible"},
+	}
+
+	mock := &mocks.MockNomadClient{}
+	mock.ListJobsFunc = func(_ context.Context, _, _ string) ([]types.JobSummary, error) {
+		return []types.JobSummary{
+			{ID: "agent", Type: "system", Status: "running", Namespace: "default"},
+			{ID: "web", Type: "service", Status: "running", Namespace: "default"},
+		}, nil
+	}
+	mock.GetJobFunc = func(_ context.Context, jobID, _ string) (types.Job, error) {
+		assert.Equal(t, "agent", jobID)
+		return types.Job{ID: jobID, Type: "system", Datacenters: []string{"dc*"}, TaskGroups: []types.TaskGroup{{Name: "collector"}}}, nil
+	}
+	mock.ListJobAllocationsFunc = func(_ context.Context, _, _ string) ([]types.Allocation, error) {
+		return []types.Allocation{
+			{NodeID: "n1", TaskGroup: "collector", DesiredStatus: "run", ClientStatus: "running"},
+			{NodeID: "n2", TaskGroup: "collector", DesiredStatus: "run", ClientStatus: "failed"},
+		}, nil
+	}
+	mock.ListNodesFunc = func(_ context.Context, _ string) ([]types.NodeSummary, error) {
+		return []types.NodeSummary{{ID: "n1"}, {ID: "n2"}, {ID: "n3"}, {ID: "n4"}}, nil
+	}
+	mock.GetNodeDetailFunc = func(_ context.Context, nodeID string) (types.NodeDetail, error) {
+		return nodes[nodeID], nil
+	}
+
+	res, err := tools.CheckSystemJobCoverageHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var reports []tools.SystemJobCoverage
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &reports))
+	require.Len(t, reports, 1)
+	report := reports[0]
+	assert.Equal(t, 3, report.EligibleNodes)
+	assert.Equal(t, 1, report.CoveredNodes)
+	require.Len(t, report.Gaps, 2)
+	assert.Equal(t, "n2", report.Gaps[0].NodeID)
+	assert.Equal(t, []string{"collector"}, report.Gaps[0].TaskGroups)
+	assert.Equal(t, "n3", report.Gaps[1].NodeID)
+	assert.Equal(t, []tools.CoverageBucket{
+		{Datacenter: "dc1", Eligible: 2, Covered: 1},
+		{Datacenter: "dc2", Eligible: 1, Covered: 0},
+	}, report.Buckets)
+}
+
+func TestGetBatchJobHistoryHandler_aggregatesPeriodicChildren(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	at := func(ago time.Duration) *time.Time {
+		t := now.Add(-ago)
+		return &t
+	}
+
+	mock := &mocks.MockNomadClient{}
+	mock.ListJobsFunc = func(_ context.Context, _, _ string) ([]types.JobSummary, error) {
+		return []types.JobSummary{
+			{ID: "report", Type: "batch", Periodic: true, Namespace: "default"},
+			{ID: "report/periodic-1", ParentID: "report", Type: "batch", Namespace: "default"},
+			{ID: "report/periodic-2", ParentID: "report", Type: "batch", Namespace: "default"},
+			{ID: "backfill", Type: "batch", Namespace: "default"},
+			{ID: "web", Type: "service", Namespace: "default"},
+		}, nil
+	}
+	mock.ListJobAllocationsFunc = func(_ context.Context, jobID, _ string) ([]types.Allocation, error) {
+		switch jobID {
+		case "report/periodic-1":
+			return []types.Allocation{{ClientStatus: "complete", TaskStates: map[string]types.TaskState{
+				"main": {StartedAt: at(3 * time.Hour), FinishedAt: at(3*time.Hour - time.Minute)},
+			}}}, nil
+		case "report/periodic-2":
+			return []types.Allocation{{ClientStatus: "failed", TaskStates: map[string]types.TaskState{
+				"main": {StartedAt: at(time.Hour), FinishedAt: at(time.Hour - 3*time.Minute)},
+			}}}, nil
+		case "backfill":
+			return []types.Allocation{{ClientStatus: "complete", TaskStates: map[string]types.TaskState{
+				"main": {StartedAt: at(30 * time.Hour), FinishedAt: at(29 * time.Hour)},
+			}}}, nil
+		}
+		t.Fatalf("unexpected allocation lookup for %s", jobID)
+		return nil, nil
+	}
+
+	res, err := tools.GetBatchJobHistoryHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"since": "1d",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var histories []tools.BatchJobHistory
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &histories))
+	require.Len(t, histories, 2)
+
+	assert.Equal(t, "backfill", histories[0].JobID)
+	assert.Zero(t, histories[0].Runs)
+	assert.Nil(t, histories[0].SuccessRate)
+
+	report := histories[1]
+	assert.Equal(t, "report", report.JobID)
+	assert.Equal(t, "periodic", report.Kind)
+	assert.Equal(t, 2, report.Runs)
+	assert.Equal(t, 1, report.Completed)
+	assert.Equal(t, 1, report.Failed)
+	require.NotNil(t, report.SuccessRate)
+	assert.InDelta(t, 0.5, *report.SuccessRate, 0.001)
+	assert.Equal(t, "2m0s", report.AverageRuntime)
+	assert.Equal(t, "failed", report.LastStatus)
+}
+
+func TestPurgeDispatchedJobsHandler_purgesOnlyOldDeadChildren(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	mock := &mocks.MockNomadClient{}
+	mock.ListJobsFunc = func(_ context.Context, _, status string) ([]types.JobSummary, error) {
+		assert.Equal(t, "dead", status)
+		return []types.JobSummary{
+			{ID: "resize", Type: "batch", ParameterizedJob: true, Status: "running"},
+			{ID: "resize/dispatch-1-a", ParentID: "resize", Status: "dead", SubmitTime: now.Add(-48 * time.Hour).UnixNano()},
+			{ID: "resize/dispatch-2-b", ParentID: "resize", Status: "dead", SubmitTime: now.Add(-time.Hour).UnixNano()},
+			{ID: "resize/dispatch-3-c", ParentID: "resize", Status: "running", SubmitTime: now.Add(-72 * time.Hour).UnixNano()},
+			{ID: "other/dispatch-4-d", ParentID: "other", Status: "dead", SubmitTime: now.Add(-72 * time.Hour).UnixNano()},
+		}, nil
+	}
+	var purged []string
+	mock.StopJobFunc = func(_ context.Context, jobID, namespace string, opts types.JobStopOptions) (types.JobDeregisterResponse, error) {
+		assert.True(t, opts.Purge)
+		assert.Equal(t, "default", namespace)
+		purged = append(purged, jobID)
+		return types.JobDeregisterResponse{}, nil
+	}
+
+	res, err := tools.PurgeDispatchedJobsHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":  "resize",
+		"dry_run": false,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, []string{"resize/dispatch-1-a"}, purged)
+}
+
+func TestExplainDeploymentEvaluationsHandler_followsBlockedChain(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.GetDeploymentFunc = func(_ context.Context, id string) (types.Deployment, error) {
+		return types.Deployment{ID: id, JobID: "web", Namespace: "default", JobModifyIndex: 10, Status: "running"}, nil
+	}
+	mock.ListEvaluationsFunc = func(_ context.Context, namespace, _, jobID string) ([]types.Evaluation, error) {
+		assert.Equal(t, "default", namespace)
+		assert.Equal(t, "web", jobID)
+		return []types.Evaluation{
+			{ID: "blocked-2", TriggeredBy: "queued-allocs", Status: "blocked", PreviousEvalID: "blocked-1", EscapedComputedClass: true, CreateIndex: 14},
+			{ID: "old", TriggeredBy: "job-register", Status: "complete", JobModifyIndex: 5, CreateIndex: 3},
+			{ID: "register", TriggeredBy: "job-register", Status: "complete", JobModifyIndex: 10, BlockedEvalID: "blocked-1", CreateIndex: 11,
+				FailedTGAllocs: map[string]interface{}{"web": map[string]interface{}{"DimensionExhausted": map[string]interface{}{"memory": 3}}}},
+			{ID: "blocked-1", TriggeredBy: "queued-allocs", Status: "complete", PreviousEvalID: "register", BlockedEvalID: "blocked-2", CreateIndex: 12},
+		}, nil
+	}
+
+	res, err := tools.ExplainDeploymentEvaluationsHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"deployment_id": "d1",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var explanation tools.DeploymentEvaluations
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &explanation))
+	require.Len(t, explanation.Steps, 3)
+	assert.Equal(t, "register", explanation.Steps[0].EvalID)
+	assert.Equal(t, "blocked-1", explanation.Steps[1].EvalID)
+	assert.Equal(t, "blocked-2", explanation.Steps[2].EvalID)
+	assert.Equal(t, 1, explanation.BlockedLoops)
+	assert.Contains(t, explanation.Steps[0].Explanation, "group web (memory)")
+	assert.Contains(t, explanation.Steps[2].Explanation, "any node change unblocks it")
+	assert.Contains(t, explanation.Narrative, "The deployment is running.")
+}
+
+func TestCheckServerQuorumHandler_flagsLeftBehindServer(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.GetRaftConfigurationFunc = func(_ context.Context) (types.RaftConfiguration, error) {
+		return types.RaftConfiguration{Servers: []types.RaftOperator{
+			{ID: "s1", Node: "s1.global", Address: "10.0.0.1:4647", Leader: true, Voter: true},
+			{ID: "s2", Node: "s2.global", Address: "10.0.0.2:4647", Voter: true},
+			{ID: "s3", Node: "s3.global", Address: "10.0.0.3:4647", Voter: true},
+		}}, nil
+	}
+	mock.ListAgentMembersFunc = func(_ context.Context) ([]types.AgentMember, error) {
+		return []types.AgentMember{
+			{Name: "s1.global", Addr: "10.0.0.1", Port: 4648, Status: "alive", Tags: map[string]string{"id": "s1", "region": "global", "port": "4647"}},
+			{Name: "s2.global", Addr: "10.0.0.2", Port: 4648, Status: "alive", Tags: map[string]string{"region": "global", "port": "4647"}},
+			{Name: "s9.eu", Addr: "10.1.0.9", Port: 4648, Status: "alive", Tags: map[string]string{"id": "s9", "region": "eu", "port": "4647"}},
+		}, nil
+	}
+	mock.GetAutopilotHealthFunc = func(_ context.Context) (types.AutopilotHealth, error) {
+		return types.AutopilotHealth{Servers: []types.AutopilotServerHealth{
+			{ID: "s1", Healthy: true}, {ID: "s2", Healthy: true}, {ID: "s3", Healthy: false, LastContact: "12s"},
+		}}, nil
+	}
+
+	res, err := tools.CheckServerQuorumHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var report tools.QuorumReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+	assert.Equal(t, "amber", report.Verdict)
+	assert.Equal(t, 3, report.Voters)
+	assert.Equal(t, 2, report.Quorum)
+	assert.Equal(t, 2, report.HealthyVoters)
+	assert.Equal(t, 0, report.FailureTolerance)
+	assert.Equal(t, "s1.global", report.Leader)
+	require.Len(t, report.Servers, 3, "members of other regions are ignored")
+	assert.Equal(t, "alive", report.Servers[1].SerfStatus, "members match on the RPC port tag")
+	assert.Contains(t, strings.Join(report.Issues, "\n"), "s3.global (10.0.0.3:4647) is in the Raft configuration but not in the gossip pool")
+}
+
+func TestGenerateAlertRulesHandler_templatesJobSelectors(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobFunc = func(_ context.Context, jobID, namespace string) (types.Job, error) {
+		assert.Equal(t, "web", jobID)
+		assert.Equal(t, "prod", namespace)
+		return types.Job{ID: jobID}, nil
+	}
+
+	res, err := tools.GenerateAlertRulesHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":      "web",
+		"namespace":   "prod",
+		"window":      "1h30m",
+		"pending_for": "20m",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var rules struct {
+		Groups []struct {
+			Name  string `yaml:"name"`
+			Rules []struct {
+				Alert  string            `yaml:"alert"`
+				Expr   string            `yaml:"expr"`
+				For    string            `yaml:"for"`
+				Labels map[string]string `yaml:"labels"`
+			} `yaml:"rules"`
+		} `yaml:"groups"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &rules))
+	require.Len(t, rules.Groups, 1)
+	assert.Equal(t, "nomad-job-prod-web", rules.Groups[0].Name)
+	require.Len(t, rules.Groups[0].Rules, 3)
+
+	restarts := rules.Groups[0].Rules[0]
+	assert.Equal(t, `sum by (task_group, task) (increase(nomad_client_allocs_restart{exported_job="web",namespace="prod"}[1h30m])) > 3`, restarts.Expr)
+	assert.Equal(t, "web", restarts.Labels["nomad_job"])
+	assert.Contains(t, rules.Groups[0].Rules[1].Expr, "nomad_client_allocs_oom_killed")
+	assert.Equal(t, "20m", rules.Groups[0].Rules[2].For)
+}
+
+func TestListJobConfigSurfaceHandler_extractsTemplateReferences(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobFunc = func(_ context.Context, jobID, _ string) (types.Job, error) {
+		return types.Job{ID: jobID, TaskGroups: []types.TaskGroup{{
+			Name: "api",
+			Tasks: []types.Task{{
+				Name:  "server",
+				Env:   map[string]string{"PORT": "8080", "LOG_LEVEL": "debug"},
+				Vault: &types.Vault{Policies: []string{"api-read"}},
+				Templates: []types.Template{
+					{
+						DestPath: "secrets/app.env",
+						Envvars:  true,
+						EmbeddedTmpl: `{{ with nomadVar "nomad/jobs/web" }}DB_PASSWORD={{ .password }}{{ end }}
+API_KEY={{ with secret "kv/data/api" }}{{ .Data.data.key }}{{ end }}`,
+					},
+					{DestPath: "local/config.yml", EmbeddedTmpl: `upstream: {{ range service "db" }}{{ .Address }}{{ end }}
+flag: {{ keyOrDefault "config/web/flag" "off" }}`},
+				},
+			}},
+		}}}, nil
+	}
+
+	res, err := tools.ListJobConfigSurfaceHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id": "web",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var surface tools.JobConfigSurface
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &surface))
+	assert.Equal(t, []string{"nomad/jobs/web"}, surface.NomadVariables)
+	assert.Equal(t, []string{"kv/data/api"}, surface.VaultSecrets)
+	assert.Equal(t, []string{"config/web/flag"}, surface.ConsulKeys)
+	assert.Equal(t, []string{"api-read"}, surface.VaultPolicies)
+
+	require.Len(t, surface.Tasks, 1)
+	task := surface.Tasks[0]
+	assert.Equal(t, []tools.EnvVarSurface{
+		{Name: "LOG_LEVEL", Source: "env"},
+		{Name: "PORT", Source: "env"},
+		{Name: "DB_PASSWORD", Source: "secrets/app.env"},
+		{Name: "API_KEY", Source: "secrets/app.env"},
+	}, task.Env, "values are omitted unless include_values is set")
+	require.Len(t, task.Templates, 2)
+	assert.Equal(t, []string{"db"}, task.Templates[1].Services)
+}
+
+func TestGetJobConnectTopologyHandler_reportsUpstreamsAndIssues(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobFunc = func(_ context.Context, jobID, _ string) (types.Job, error) {
+		return types.Job{ID: jobID, TaskGroups: []types.TaskGroup{
+			{
+				Name:     "api",
+				Networks: []types.Network{{Mode: "bridge", DynamicPorts: []types.Port{{Label: "health"}}}},
+				Services: []types.Service{{
+					Name: "api",
+					Connect: &types.ConsulConnect{
+						SidecarService: &types.ConsulSidecarService{Proxy: &types.ConsulProxy{
+							Upstreams: []types.ConsulUpstream{
+								{DestinationName: "db", LocalBindPort: 5432},
+								{DestinationName: "cache", LocalBindPort: 5432},
+							},
+							Expose: &types.ConsulExposeConfig{Paths: []types.ConsulExposePath{
+								{Path: "/health", LocalPathPort: 8080, ListenerPort: "health"},
+							}},
+						}},
+					},
+				}},
+				Tasks: []types.Task{
+					{Name: "server"},
+					{Name: "connect-proxy-api", Kind: "connect-proxy:api", Resources: types.Resources{CPU: 250, MemoryMB: 128}},
+				},
+			},
+			{
+				Name:     "web",
+				Networks: []types.Network{{Mode: "host"}},
+				Services: []types.Service{{
+					Name: "web",
+					Connect: &types.ConsulConnect{
+						SidecarService: &types.ConsulSidecarService{},
+						SidecarTask:    &types.SidecarTask{Resources: &types.Resources{CPU: 100, MemoryMB: 64}},
+					},
+				}},
+			},
+		}}, nil
+	}
+
+	res, err := tools.GetJobConnectTopologyHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id": "shop",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var topology tools.ConnectTopology
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &topology))
+	require.Len(t, topology.Services, 2)
+	assert.Equal(t, "sidecar", topology.Services[0].Mode)
+	assert.Len(t, topology.Services[0].Upstreams, 2)
+	assert.Len(t, topology.Services[0].ExposedPaths, 1)
+	assert.Equal(t, 64, topology.Services[1].SidecarMemoryMB)
+
+	issues := strings.Join(topology.Issues, "\n")
+	assert.Contains(t, issues, "service api sets no sidecar_task resources")
+	assert.Contains(t, issues, "upstreams db and cache both bind local port 5432")
+	assert.Contains(t, issues, `group web: Connect sidecars require network mode "bridge"`)
+	assert.NotContains(t, issues, "exposed path")
+}
+
+func TestListStaticPortsHandler_flagsConflictsAndExhaustion(t *testing.T) {
+	t.Parallel()
+
+	jobs := map[string]types.Job{
+		"a": {ID: "a", Datacenters: []string{"dc1"}, TaskGroups: []types.TaskGroup{{Name: "web", Networks: []types.Network{{ReservedPorts: []types.Port{{Label: "http", Value: 80}}}}}}},
+		"b": {ID: "b", Datacenters: []string{"dc1"}, TaskGroups: []types.TaskGroup{{Name: "proxy", Networks: []types.Network{{ReservedPorts: []types.Port{{Label: "http", Value: 80}}}}}}},
+		"c": {ID: "c", Datacenters: []string{"dc1"}, TaskGroups: []types.TaskGroup{{Name: "admin", Networks: []types.Network{{ReservedPorts: []types.Port{{Label: "ui", Value: 8080}}}}}}},
+	}
+
+	mock := &mocks.MockNomadClient{}
+	mock.ListJobsFunc = func(_ context.Context, namespace, _ string) ([]types.JobSummary, error) {
+		assert.Equal(t, "*", namespace)
+		return []types.JobSummary{
+			{ID: "a", Namespace: "default", Status: "running"},
+			{ID: "b", Namespace: "default", Status: "running"},
+			{ID: "c", Namespace: "default", Status: "pending"},
+			{ID: "old", Namespace: "default", Status: "dead"},
+		}, nil
+	}
+	mock.GetJobFunc = func(_ context.Context, jobID, _ string) (types.Job, error) {
+		require.NotEqual(t, "old", jobID, "dead jobs hold no ports")
+		return jobs[jobID], nil
+	}
+	mock.ListNodesFunc = func(_ context.Context, _ string) ([]types.NodeSummary, error) {
+		return []types.NodeSummary{{ID: "n1"}}, nil
+	}
+	mock.GetNodeDetailFunc = func(_ context.Context, nodeID string) (types.NodeDetail, error) {
+		node := types.NodeDetail{ID: nodeID, Name: "node-1", Datacenter: "dc1", Status: "ready", SchedulingEligibility: "eligible",
+			NodeResources:     &types.NodePortResources{MinDynamicPort: 20000, MaxDynamicPort: 20001},
+			ReservedResources: &types.NodeReservedResources{}}
+		node.ReservedResources.Networks.ReservedHostPorts = "22,8000-8100"
+		return node, nil
+	}
+	mock.ListNodeAllocationsFunc = func(_ context.Context, _ string) ([]types.Allocation, error) {
+		resources := &types.AllocatedResources{}
+		resources.Shared.Ports = []types.AllocatedPort{{Label: "http", Value: 80}, {Label: "metrics", Value: 20000}, {Label: "admin", Value: 20001}}
+		return []types.Allocation{{Namespace: "default", JobID: "a", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "running", AllocatedResources: resources}}, nil
+	}
+
+	res, err := tools.ListStaticPortsHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var inventory tools.StaticPortInventory
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &inventory))
+	assert.Len(t, inventory.Reservations, 3)
+	require.Len(t, inventory.Conflicts, 1)
+	assert.Equal(t, 80, inventory.Conflicts[0].Port)
+
+	require.Len(t, inventory.Nodes, 1)
+	node := inventory.Nodes[0]
+	require.Len(t, node.StaticPorts, 1)
+	assert.Equal(t, "a", node.StaticPorts[0].JobID)
+	assert.Equal(t, 2, node.DynamicPortsUsed)
+	assert.Equal(t, 0, node.DynamicPortsFree)
+	issues := strings.Join(node.Issues, "\n")
+	assert.Contains(t, issues, "2 of 2 dynamic ports are in use")
+	assert.Contains(t, issues, "static port 8080 of c/admin is reserved by the node's client config")
+}
+
+func TestAnalyzeJobSpreadHandler_flagsSkewAgainstTargets(t *testing.T) {
+	t.Parallel()
+
+	nodes := map[string]types.NodeDetail{
+		"n1": {ID: "n1", Name: "a", Datacenter: "dc1", Status: "ready", SchedulingEligibility: "eligible"},
+		"n2": {ID: "n2", Name: "b", Datacenter: "dc1", Status: "ready", SchedulingEligibility: "eligible"},
+		"n3": {ID: "n3", Name: "c", Datacenter: "dc2", Status: "ready", SchedulingEligibility: "eligible"},
+	}
+
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobFunc = func(_ context.Context, jobID, _ string) (types.Job, error) {
+		return types.Job{
+			ID:          jobID,
+			Datacenters: []string{"dc1", "dc2"},
+			Spreads: []types.Spread{{
+				Attribute:    "${node.datacenter}",
+				Weight:       100,
+				SpreadTarget: []types.SpreadTarget{{Value: "dc1", Percent: 50}, {Value: "dc2", Percent: 50}},
+			}},
+			TaskGroups: []types.TaskGroup{{Name: "web"}},
+		}, nil
+	}
+	mock.ListJobAllocationsFunc = func(_ context.Context, _, _ string) ([]types.Allocation, error) {
+		return []types.Allocation{
+			{NodeID: "n1", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "running"},
+			{NodeID: "n1", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "running"},
+			{NodeID: "n1", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "running"},
+			{NodeID: "n2", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "running"},
+			{NodeID: "n3", TaskGroup: "web", DesiredStatus: "stop", ClientStatus: "complete"},
+		}, nil
+	}
+	mock.ListNodesFunc = func(_ context.Context, _ string) ([]types.NodeSummary, error) {
+		return []types.NodeSummary{{ID: "n1"}, {ID: "n2"}, {ID: "n3"}}, nil
+	}
+	mock.GetNodeDetailFunc = func(_ context.Context, nodeID string) (types.NodeDetail, error) {
+		return nodes[nodeID], nil
+	}
+
+	res, err := tools.AnalyzeJobSpreadHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"job_id": "web"}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var report tools.JobSpreadReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+	require.Len(t, report.Groups, 1)
+	group := report.Groups[0]
+	assert.Equal(t, 4, group.Allocations)
+	assert.Equal(t, "dc1", group.ByDatacenter[0].Value)
+	assert.Equal(t, 100.0, group.ByDatacenter[0].Percent)
+
+	require.Len(t, group.Spreads, 1)
+	spread := group.Spreads[0]
+	assert.True(t, spread.Skewed)
+	assert.Equal(t, 50.0, spread.MaxDeviation)
+	require.Len(t, spread.Buckets, 2)
+	assert.Equal(t, "dc2", spread.Buckets[1].Value)
+	assert.Equal(t, 0, spread.Buckets[1].Allocations)
+
+	require.Len(t, group.Issues, 2)
+	assert.Contains(t, group.Issues[0], "spread on ${node.datacenter}")
+	assert.Contains(t, group.Issues[1], "3 of 4 allocations run on node a")
+}
+
+func TestSimulateRestartPolicyHandler_givesUpAfterRescheduleAttempts(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobFunc = func(_ context.Context, jobID, _ string) (types.Job, error) {
+		return types.Job{
+			ID:   jobID,
+			Type: "service",
+			TaskGroups: []types.TaskGroup{{
+				Name:             "web",
+				RestartPolicy:    &types.RestartPolicy{Attempts: 2, Interval: int(30 * time.Minute), Delay: int(15 * time.Second), Mode: "fail"},
+				ReschedulePolicy: &types.ReschedulePolicy{Attempts: 2, Interval: int(time.Hour), Delay: int(30 * time.Second), DelayFunction: "exponential", MaxDelay: int(time.Hour)},
+				Tasks:            []types.Task{{Name: "server"}},
+			}},
+		}, nil
+	}
+
+	res, err := tools.SimulateRestartPolicyHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":           "web",
+		"failure_interval": "1m",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var simulations []tools.RestartSimulation
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &simulations))
+	require.Len(t, simulations, 1)
+	sim := simulations[0]
+	assert.Equal(t, "group", sim.Restart.Source)
+	// Three failures a minute apart with two 15s restarts in between.
+	assert.Equal(t, "3m30s", sim.AllocationFailsAfter)
+	assert.Equal(t, 2, sim.RestartsPerAllocation)
+	assert.Equal(t, 2, sim.Reschedules)
+	assert.True(t, sim.GivesUp)
+	// 3m30s + 30s delay + 3m30s + 60s delay + 3m30s
+	assert.Equal(t, "12m", sim.GivesUpAfter)
+	assert.Equal(t, "1m", sim.MaxRecoveryTime)
+	assert.Equal(t, "gave up: 2 reschedule attempts used within 1h", sim.Timeline[len(sim.Timeline)-1].Event)
+}
+
+func TestSimulateRestartPolicyHandler_requiresFailureInterval(t *testing.T) {
+	t.Parallel()
+
+	res, err := tools.SimulateRestartPolicyHandler(&mocks.MockNomadClient{}, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"job_id": "web"}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+}
+
+func TestPlanNodeUpgradeWavesHandler_separatesGroupAllocations(t *testing.T) {
+	t.Parallel()
+
+	nodes := map[string]types.NodeDetail{
+		"n1": {ID: "n1", Name: "a", Datacenter: "dc1", Status: "ready"},
+		"n2": {ID: "n2", Name: "b", Datacenter: "dc1", Status: "ready"},
+		"n3": {ID: "n3", Name: "c", Datacenter: "dc1", Status: "ready"},
+		"n4": {ID: "n4", Name: "d", Datacenter: "dc1", Status: "down"},
+	}
+	nodeAllocs := map[string][]types.Allocation{
+		"n1": {{Namespace: "default", JobID: "web", TaskGroup: "app", DesiredStatus: "run", ClientStatus: "running"}},
+		"n2": {{Namespace: "default", JobID: "web", TaskGroup: "app", DesiredStatus: "run", ClientStatus: "running"}},
+		"n3": {{Namespace: "default", JobID: "agent", TaskGroup: "collector", DesiredStatus: "run", ClientStatus: "running"}},
+	}
+
+	mock := &mocks.MockNomadClient{}
+	mock.ListNodesFunc = func(_ context.Context, _ string) ([]types.NodeSummary, error) {
+		return []types.NodeSummary{{ID: "n1"}, {ID: "n2"}, {ID: "n3"}, {ID: "n4"}}, nil
+	}
+	mock.GetNodeDetailFunc = func(_ context.Context, nodeID string) (types.NodeDetail, error) {
+		return nodes[nodeID], nil
+	}
+	mock.ListNodeAllocationsFunc = func(_ context.Context, nodeID string) ([]types.Allocation, error) {
+		return nodeAllocs[nodeID], nil
+	}
+	mock.ListJobsFunc = func(_ context.Context, _, _ string) ([]types.JobSummary, error) {
+		return []types.JobSummary{
+			{ID: "web", Type: "service", Status: "running", Namespace: "default"},
+			{ID: "agent", Type: "system", Status: "running", Namespace: "default"},
+		}, nil
+	}
+	mock.GetJobFunc = func(_ context.Context, jobID, ns string) (types.Job, error) {
+		if jobID == "agent" {
+			return types.Job{ID: jobID, Namespace: ns, Type: "system", TaskGroups: []types.TaskGroup{{Name: "collector", Count: 1}}}, nil
+		}
+		return types.Job{ID: jobID, Namespace: ns, Type: "service", TaskGroups: []types.TaskGroup{{
+			Name:    "app",
+			Count:   2,
+			Migrate: &types.MigrateStrategy{MaxParallel: 2},
+		}}}, nil
+	}
+
+	res, err := tools.PlanNodeUpgradeWavesHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"max_wave_size": float64(3),
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var plan tools.UpgradePlan
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &plan))
+	assert.Equal(t, 3, plan.Nodes)
+	require.Len(t, plan.Limits, 1)
+	// count 2 keeps one allocation running even though max_parallel allows 2
+	assert.Equal(t, 1, plan.Limits[0].PerWave)
+	require.Len(t, plan.Waves, 2)
+	assert.Equal(t, 1, plan.Waves[0].Allocations)
+	assert.Equal(t, 1, plan.Waves[1].Allocations)
+	for _, wave := range plan.Waves {
+		assert.Len(t, wave.Steps, 3)
+	}
+	assert.Len(t, plan.Waves[0].Nodes, 2)
+	assert.Equal(t, "a", plan.Waves[0].Nodes[0].Name)
+	assert.Equal(t, "c", plan.Waves[0].Nodes[1].Name)
+	assert.Equal(t, "b", plan.Waves[1].Nodes[0].Name)
+	assert.Contains(t, plan.Warnings[0], "1 matching nodes are not ready")
+}
+
+func TestUpdateJobImageHandler_plansAndSubmitsAtPlannedIndex(t *testing.T) {
+	t.Parallel()
+
+	var planned, submitted map[string]interface{}
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobDefinitionFunc = func(_ context.Context, jobID, _ string) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"ID": jobID,
+			"TaskGroups": []interface{}{map[string]interface{}{
+				"Name": "app",
+				"Tasks": []interface{}{map[string]interface{}{
+					"Name":   "server",
+					"Driver": "docker",
+					"Config": map[string]interface{}{"image": "registry.local:5000/team/web:1.0@sha256:abc", "ports": []interface{}{"http"}},
+				}},
+			}},
+		}, nil
+	}
+	mock.PlanJobFunc = func(_ context.Context, job map[string]interface{}, _ string) (types.JobPlan, error) {
+		planned = job
+		return types.JobPlan{
+			JobModifyIndex: 17,
+			Annotations:    &types.PlanAnnotations{DesiredTGUpdates: map[string]types.DesiredUpdates{"app": {DestructiveUpdate: 2}}},
+		}, nil
+	}
+	mock.ParseJobSpecFunc = func(_ context.Context, spec string) (map[string]interface{}, error) {
+		var job map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(spec), &job))
+		return job, nil
+	}
+	mock.EnforceRunJobFunc = func(_ context.Context, spec, _ string, _ bool, jobModifyIndex int) (types.JobRegisterResponse, error) {
+		require.Equal(t, 17, jobModifyIndex)
+		require.NoError(t, json.Unmarshal([]byte(spec), &submitted))
+		return types.JobRegisterResponse{EvalID: "e1"}, nil
+	}
+
+	res, err := tools.UpdateJobImageHandler(mock, tools.NewJobSubmissionLocks(), testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id": "web",
+		"task":   "server",
+		"tag":    "1.1",
+		"submit": true,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, res.Content)
+
+	var update tools.ImageUpdate
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &update))
+	assert.Equal(t, "app", update.Group)
+	assert.Equal(t, "registry.local:5000/team/web:1.0@sha256:abc", update.OldImage)
+	assert.Equal(t, "registry.local:5000/team/web:1.1", update.NewImage)
+	assert.Equal(t, int64(2), update.Updates["app"].DestructiveUpdate)
+	assert.True(t, update.Submitted)
+
+	image := func(job map[string]interface{}) interface{} {
+		task := job["TaskGroups"].([]interface{})[0].(map[string]interface{})["Tasks"].([]interface{})[0].(map[string]interface{})
+		return task["Config"].(map[string]interface{})["image"]
+	}
+	assert.Equal(t, update.NewImage, image(planned))
+	assert.Equal(t, update.NewImage, image(submitted))
+}
+
+func TestUpdateJobImageHandler_rejectsUnknownTask(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobDefinitionFunc = func(_ context.Context, jobID, _ string) (map[string]interface{}, error) {
+		return map[string]interface{}{"ID": jobID, "TaskGroups": []interface{}{map[string]interface{}{"Name": "app"}}}, nil
+	}
+
+	res, err := tools.UpdateJobImageHandler(mock, nil, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id": "web",
+		"task":   "worker",
+		"tag":    "2.0",
+	}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "task worker not found")
+}
+
+func blueGreenMock(t *testing.T, deploymentStatus string) (*mocks.MockNomadClient, *[]map[string]interface{}, *[]string) {
+	registered := []map[string]interface{}{}
+	stopped := []string{}
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobDefinitionFunc = func(_ context.Context, jobID, _ string) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"ID":             jobID,
+			"Name":           jobID,
+			"JobModifyIndex": float64(3),
+			"Status":         "running",
+			"TaskGroups": []interface{}{map[string]interface{}{
+				"Name":     "app",
+				"Count":    float64(2),
+				"Services": []interface{}{map[string]interface{}{"Name": "web", "Tags": []interface{}{"http", "live"}}},
+			}},
+		}, nil
+	}
+	mock.ParseJobSpecFunc = func(_ context.Context, spec string) (map[string]interface{}, error) {
+		var job map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(spec), &job))
+		return job, nil
+	}
+	mock.EnforceRunJobFunc = func(_ context.Context, spec, _ string, _ bool, _ int) (types.JobRegisterResponse, error) {
+		var job map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(spec), &job))
+		registered = append(registered, job)
+		return types.JobRegisterResponse{JobModifyIndex: 10}, nil
+	}
+	mock.GetJobDeploymentFunc = func(_ context.Context, jobID, _ string) (types.JobDeployment, error) {
+		return types.JobDeployment{ID: "d1", JobID: jobID, JobModifyIndex: 10, Status: deploymentStatus}, nil
+	}
+	mock.StopJobFunc = func(_ context.Context, jobID, _ string, _ types.JobStopOptions) (types.JobDeregisterResponse, error) {
+		stopped = append(stopped, jobID)
+		return types.JobDeregisterResponse{}, nil
+	}
+	return mock, &registered, &stopped
+}
+
+func TestDeployBlueGreenHandler_movesLiveTagsAndStopsBlue(t *testing.T) {
+	t.Parallel()
+
+	mock, registered, stopped := blueGreenMock(t, "successful")
+	res, err := tools.DeployBlueGreenHandler(mock, tools.NewJobSubmissionLocks(), testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":    "web",
+		"live_tags": "live",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, res.Content)
+
+	var report tools.BlueGreenReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+	assert.True(t, report.Complete)
+	assert.Equal(t, "web-green", report.GreenJobID)
+
+	tags := func(job map[string]interface{}) []interface{} {
+		group := job["TaskGroups"].([]interface{})[0].(map[string]interface{})
+		return group["Services"].([]interface{})[0].(map[string]interface{})["Tags"].([]interface{})
+	}
+	require.Len(t, *registered, 3)
+	green, promoted, blue := (*registered)[0], (*registered)[1], (*registered)[2]
+	assert.Equal(t, "web-green", green["ID"])
+	assert.Equal(t, "web", green["Name"])
+	assert.NotContains(t, green, "JobModifyIndex")
+	assert.Equal(t, []interface{}{"http"}, tags(green))
+	assert.Equal(t, []interface{}{"http", "live"}, tags(promoted))
+	assert.Equal(t, "web", blue["ID"])
+	assert.Equal(t, []interface{}{"http"}, tags(blue))
+	assert.Equal(t, []string{"web"}, *stopped)
+}
+
+func TestDeployBlueGreenHandler_rollsBackUnhealthyGreen(t *testing.T) {
+	t.Parallel()
+
+	mock, registered, stopped := blueGreenMock(t, "failed")
+	res, err := tools.DeployBlueGreenHandler(mock, nil, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":    "web-green",
+		"live_tags": "live",
+	}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+
+	var report tools.BlueGreenReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+	assert.Equal(t, "web", report.GreenJobID)
+	assert.False(t, report.Complete)
+	assert.Len(t, *registered, 1)
+	assert.Equal(t, []string{"web"}, *stopped)
+	assert.Equal(t, "roll back green", report.Steps[len(report.Steps)-1].Step)
+}
+
+func TestDeployBlueGreenHandler_rollsBackGreenWhenTheCallTimesOut(t *testing.T) {
+	t.Parallel()
+
+	mock, _, stopped := blueGreenMock(t, "running")
+	stopJob := mock.StopJobFunc
+	mock.StopJobFunc = func(ctx context.Context, jobID, namespace string, opts types.JobStopOptions) (types.JobDeregisterResponse, error) {
+		require.NoError(t, ctx.Err())
+		return stopJob(ctx, jobID, namespace, opts)
+	}
+	h := tools.DeployBlueGreenHandler(mock, nil, testLogger())
+	call := func(ctx context.Context) tools.BlueGreenReport {
+		res, err := h(ctx, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"job_id": "web"}}})
+		require.NoError(t, err)
+		require.True(t, res.IsError)
+		var report tools.BlueGreenReport
+		require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+		return report
+	}
+
+	// The call is cancelled while waiting for green to become healthy.
+	ctx, cancel := context.WithCancel(context.Background())
+	mock.GetJobDeploymentFunc = func(_ context.Context, jobID, _ string) (types.JobDeployment, error) {
+		cancel()
+		return types.JobDeployment{ID: "d1", JobID: jobID, JobModifyIndex: 10, Status: "running"}, nil
+	}
+	report := call(ctx)
+	assert.Equal(t, []string{"web-green"}, *stopped)
+	assert.Equal(t, "done", report.Steps[len(report.Steps)-1].Status)
+
+	// A deadline too close for the default 5m health timeout cuts the wait short.
+	ctx, cancelDeadline := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelDeadline()
+	mock.GetJobDeploymentFunc = func(_ context.Context, jobID, _ string) (types.JobDeployment, error) {
+		return types.JobDeployment{ID: "d1", JobID: jobID, JobModifyIndex: 10, Status: "running"}, nil
+	}
+	start := time.Now()
+	report = call(ctx)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, []string{"web-green", "web-green"}, *stopped)
+	assert.Contains(t, report.Steps[1].Detail, "not healthy after")
+}
+
+func TestDeleteNamespaceHandler_refusesNonEmptyNamespaceWithoutCascade(t *testing.T) {
+	t.Parallel()
+
+	deleted := false
+	mock := &mocks.MockNomadClient{}
+	mock.ListJobsFunc = func(_ context.Context, namespace, _ string) ([]types.JobSummary, error) {
+		require.Equal(t, "team-a", namespace)
+		return []types.JobSummary{{ID: "web", Type: "service", Status: "running"}}, nil
+	}
+	mock.DeleteNamespaceFunc = func(_ context.Context, _ string) error {
+		deleted = true
+		return nil
+	}
+
+	res, err := tools.DeleteNamespaceHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"name": "team-a"}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.False(t, deleted)
+
+	var report tools.NamespaceDeletionReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+	require.Len(t, report.Residue.Jobs, 1)
+	assert.True(t, report.Residue.Jobs[0].Blocking)
+}
+
+func TestDeleteNamespaceHandler_cascadeRemovesJobsAndVariables(t *testing.T) {
+	t.Parallel()
+
+	var purged, deletedVars []string
+	deleted := ""
+	mock := &mocks.MockNomadClient{}
+	mock.ListJobsFunc = func(_ context.Context, _, _ string) ([]types.JobSummary, error) {
+		return []types.JobSummary{{ID: "web", Status: "running"}, {ID: "old", Status: "dead"}}, nil
+	}
+	mock.ListVariablesFunc = func(_ context.Context, _, _, _ string, _ int, _ string) ([]types.Variable, error) {
+		return []types.Variable{{Path: "nomad/jobs/web"}}, nil
+	}
+	mock.StopJobFunc = func(_ context.Context, jobID, namespace string, opts types.JobStopOptions) (types.JobDeregisterResponse, error) {
+		require.Equal(t, "team-a", namespace)
+		require.True(t, opts.Purge)
+		purged = append(purged, jobID)
+		return types.JobDeregisterResponse{}, nil
+	}
+	mock.DeleteVariableFunc = func(_ context.Context, path, _ string, _ int) error {
+		deletedVars = append(deletedVars, path)
+		return nil
+	}
+	mock.DeleteNamespaceFunc = func(_ context.Context, name string) error {
+		deleted = name
+		return nil
+	}
+
+	res, err := tools.DeleteNamespaceHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"name": "team-a", "cascade": true}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, res.Content)
+
+	var report tools.NamespaceDeletionReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+	assert.True(t, report.Deleted)
+	assert.Equal(t, "team-a", deleted)
+	assert.Equal(t, []string{"web", "old"}, purged)
+	assert.Equal(t, []string{"nomad/jobs/web"}, deletedVars)
+	assert.Equal(t, []string{"web", "old"}, report.Removed.Jobs)
+}
+
+func TestDeleteNamespaceHandler_volumesBlockCascade(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.ListNamespaceVolumesFunc = func(_ context.Context, _ string) ([]types.VolumeStub, error) {
+		return []types.VolumeStub{{ID: "data", Name: "data", Type: "csi"}}, nil
+	}
+	mock.DeleteNamespaceFunc = func(_ context.Context, _ string) error {
+		t.Fatal("namespace must not be deleted while volumes remain")
+		return nil
+	}
+
+	res, err := tools.DeleteNamespaceHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"name": "team-a", "cascade": true}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "1 volume(s)")
+}
+
+func TestExportVariablesHandler_redactsValuesByDefault(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		ListVariablesFunc: func(ctx context.Context, namespace, prefix, nextToken string, perPage int, filter string) ([]types.Variable, error) {
+			assert.Equal(t, "app/", prefix)
+			return []types.Variable{{Path: "app/web"}, {Path: "app/db"}}, nil
+		},
+		GetVariableFunc: func(ctx context.Context, path, namespace string) (types.Variable, error) {
+			return types.Variable{Path: path, Items: map[string]string{"password": "secret-" + path}, ModifyIndex: 7}, nil
+		},
+	}
+
+	handler := tools.ExportVariablesHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"prefix": "app/"}
+
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	text := res.Content[0].(mcp.TextContent).Text
+	assert.NotContains(t, text, "secret-")
+
+	var export tools.VariableExport
+	require.NoError(t, json.Unmarshal([]byte(text), &export))
+	assert.True(t, export.Redacted)
+	require.Len(t, export.Variables, 2)
+	assert.Equal(t, "app/db", export.Variables[0].Path)
+	assert.Equal(t, "<redacted>", export.Variables[0].Items["password"])
+}
+
+func TestImportVariablesHandler_roundTripsArchiveWithCAS(t *testing.T) {
+	source := &mocks.MockNomadClient{
+		ListVariablesFunc: func(ctx context.Context, namespace, prefix, nextToken string, perPage int, filter string) ([]types.Variable, error) {
+			return []types.Variable{{Path: "app/new"}, {Path: "app/same"}, {Path: "app/changed"}}, nil
+		},
+		GetVariableFunc: func(ctx context.Context, path, namespace string) (types.Variable, error) {
+			return types.Variable{Path: path, Items: map[string]string{"key": "v1"}}, nil
+		},
+	}
+	exportReq := mcp.CallToolRequest{}
+	exportReq.Params.Arguments = map[string]interface{}{"redact_values": false, "format": "archive"}
+	exported, err := tools.ExportVariablesHandler(source, testLogger())(context.Background(), exportReq)
+	require.NoError(t, err)
+	require.False(t, exported.IsError)
+	resource, ok := exported.Content[1].(mcp.EmbeddedResource)
+	require.True(t, ok)
+	blob := resource.Resource.(mcp.BlobResourceContents).Blob
+
+	writes := map[string]int{}
+	target := &mocks.MockNomadClient{
+		GetVariableFunc: func(ctx context.Context, path, namespace string) (types.Variable, error) {
+			assert.Equal(t, "staging", namespace)
+			switch path {
+			case "app/same":
+				return types.Variable{Path: path, Items: map[string]string{"key": "v1"}, ModifyIndex: 10}, nil
+			case "app/changed":
+				return types.Variable{Path: path, Items: map[string]string{"key": "old"}, ModifyIndex: 12}, nil
+			}
+			return types.Variable{}, &utils.NomadHTTPError{StatusCode: http.StatusNotFound}
+		},
+		CreateVariableFunc: func(ctx context.Context, variable types.Variable, namespace string, cas int, lockOperation string) error {
+			writes[variable.Path] = cas
+			assert.JSONEq(t, `{"Items":{"key":"v1"}}`, variable.Value)
+			return nil
+		},
+	}
+	importReq := mcp.CallToolRequest{}
+	importReq.Params.Arguments = map[string]interface{}{"archive": blob, "namespace": "staging", "on_conflict": "overwrite"}
+
+	res, err := tools.ImportVariablesHandler(target, testLogger())(context.Background(), importReq)
+	require.NoError(t, err)
+	require.False(t, res.IsError, res.Content[0].(mcp.TextContent).Text)
+
+	var report tools.VariableImportReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+	assert.Equal(t, []string{"app/new"}, report.Created)
+	assert.Equal(t, []string{"app/changed"}, report.Updated)
+	assert.Equal(t, []string{"app/same"}, report.Unchanged)
+	assert.Equal(t, map[string]int{"app/new": utils.VariableCASMustNotExist, "app/changed": 12}, writes)
+}
+
+func TestImportVariablesHandler_refusesRedactedExport(t *testing.T) {
+	handler := tools.ImportVariablesHandler(&mocks.MockNomadClient{}, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"variables": `{"Redacted":true,"Variables":[{"Path":"a","Items":{"k":"<redacted>"}}]}`}
+
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "redact_values=false")
+}
+
+func TestDiffVariablesHandler_reportsMissingAndDifferingItems(t *testing.T) {
+	vars := map[string]map[string]map[string]string{
+		"staging": {
+			"app/web": {"url": "http://staging", "token": "same"},
+			"app/old": {"k": "v"},
+		},
+		"prod": {
+			"app/web": {"url": "http://prod", "token": "same", "extra": "x"},
+			"app/new": {"k": "v"},
+		},
+	}
+	mockClient := &mocks.MockNomadClient{
+		ListVariablesFunc: func(ctx context.Context, namespace, prefix, nextToken string, perPage int, filter string) ([]types.Variable, error) {
+			stubs := []types.Variable{}
+			for p := range vars[namespace] {
+				stubs = append(stubs, types.Variable{Path: p})
+			}
+			return stubs, nil
+		},
+		GetVariableFunc: func(ctx context.Context, path, namespace string) (types.Variable, error) {
+			return types.Variable{Path: path, Items: vars[namespace][path]}, nil
+		},
+	}
+
+	handler := tools.DiffVariablesHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"namespace": "staging", "other_namespace": "prod", "prefix": "app/"}
+
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	text := res.Content[0].(mcp.TextContent).Text
+	assert.NotContains(t, text, "http://")
+
+	var report tools.VariableDiffReport
+	require.NoError(t, json.Unmarshal([]byte(text), &report))
+	assert.False(t, report.Identical)
+	assert.Equal(t, 3, report.Paths)
+
+	statuses := map[string]string{}
+	for _, d := range report.Differences {
+		statuses[d.Path+"#"+d.Key] = d.Status
+	}
+	assert.Equal(t, map[string]string{
+		"app/new#":      "missing_left",
+		"app/old#":      "missing_right",
+		"app/web#extra": "missing_left",
+		"app/web#url":   "differs",
+	}, statuses)
+}
+
+func TestExplainTokenAccessHandler_mergesPoliciesAndRoles(t *testing.T) {
+	policies := map[string]string{
+		"readonly": `
+# everyone may read
+namespace "*" {
+  policy = "read"
+}
+node {
+  policy = "read"
+}`,
+		"team-a": `namespace "team-*" {
+  capabilities = ["submit-job", "read-logs"]
+  variables {
+    path "app/*" { capabilities = ["read"] }
+  }
+}
+/* no operator access */
+operator { policy = "deny" }`,
+		"nodes": `{"node": {"policy": "write"}}`,
+	}
+	mockClient := &mocks.MockNomadClient{
+		GetACLTokenFunc: func(ctx context.Context, accessorID string) (types.ACLToken, error) {
+			return types.ACLToken{AccessorID: accessorID, Name: "ci", Type: "client", Policies: []string{"readonly", "gone"}, Roles: []types.ACLRoleLink{{ID: "role-1"}}}, nil
+		},
+		GetACLRoleFunc: func(ctx context.Context, id string) (types.ACLRole, error) {
+			return types.ACLRole{ID: id, Name: "deployers", Policies: []map[string]string{{"Name": "team-a"}, {"Name": "nodes"}}}, nil
+		},
+		GetACLPolicyFunc: func(ctx context.Context, name string) (types.ACLPolicy, error) {
+			rules, ok := policies[name]
+			if !ok {
+				return types.ACLPolicy{}, &utils.NomadHTTPError{StatusCode: http.StatusNotFound}
+			}
+			return types.ACLPolicy{Name: name, Rules: rules}, nil
+		},
+	}
+	handler := tools.ExplainTokenAccessHandler(mockClient, testLogger())
+
+	explain := func(args map[string]interface{}) tools.TokenAccessReport {
+		args["accessor_id"] = "abc"
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		res, err := handler(context.Background(), req)
+		require.NoError(t, err)
+		require.False(t, res.IsError, res.Content[0].(mcp.TextContent).Text)
+		var report tools.TokenAccessReport
+		require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+		return report
+	}
+
+	report := explain(map[string]interface{}{"operation": "submit-job", "namespace": "team-a"})
+	assert.Equal(t, []string{"deployers"}, report.Roles)
+	assert.Equal(t, []string{"nodes", "readonly", "team-a"}, report.Policies)
+	assert.Equal(t, []string{"gone"}, report.MissingPolicies)
+	assert.Equal(t, map[string]string{"node": "write", "operator": "deny"}, report.Scopes)
+	require.NotNil(t, report.Decision)
+	assert.True(t, report.Decision.Allowed)
+	assert.Equal(t, `namespace "team-*"`, report.Decision.MatchedRule)
+	assert.Equal(t, []string{"team-a"}, report.Decision.GrantedBy)
+
+	// The closest glob wins, so read rights from "*" do not add to "team-*".
+	report = explain(map[string]interface{}{"operation": "read-job", "namespace": "team-a"})
+	assert.False(t, report.Decision.Allowed)
+
+	report = explain(map[string]interface{}{"operation": "submit-job", "namespace": "prod"})
+	assert.False(t, report.Decision.Allowed)
+	assert.Equal(t, `namespace "*"`, report.Decision.MatchedRule)
+
+	report = explain(map[string]interface{}{"operation": "variables:list", "namespace": "team-b", "variable_path": "app/db"})
+	assert.True(t, report.Decision.Allowed)
+
+	report = explain(map[string]interface{}{"operation": "node:write"})
+	assert.True(t, report.Decision.Allowed)
+	assert.Equal(t, []string{"nodes"}, report.Decision.GrantedBy)
+
+	report = explain(map[string]interface{}{"operation": "operator:read"})
+	assert.False(t, report.Decision.Allowed)
+}
+
+func TestListNodesHandler_allRegionsMergesAndReportsFailures(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		ListRegionsFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"us", "eu", "ap"}, nil
+		},
+		ListNodesFunc: func(ctx context.Context, status string) ([]types.NodeSummary, error) {
+			switch region := utils.RegionFromContext(ctx); region {
+			case "ap":
+				return nil, errors.New("no path to region")
+			default:
+				return []types.NodeSummary{{ID: region + "-node", Status: "ready"}}, nil
+			}
+		},
+	}
+
+	handler := tools.ListNodesHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"all_regions": true}
+
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	require.Len(t, res.Content, 2)
+
+	var nodes []struct {
+		Region string
+		ID     string
+	}
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &nodes))
+	require.Len(t, nodes, 2)
+	assert.Equal(t, "eu", nodes[0].Region)
+	assert.Equal(t, "eu-node", nodes[0].ID)
+	assert.Equal(t, "us", nodes[1].Region)
+	assert.Contains(t, res.Content[1].(mcp.TextContent).Text, "ap: no path to region")
+}
+
+func TestListJobsHandler_allRegionsTagsJobs(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		ListRegionsFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"global", "eu"}, nil
+		},
+		ListJobsFunc: func(ctx context.Context, namespace, status string) ([]types.JobSummary, error) {
+			return []types.JobSummary{{ID: "web"}}, nil
+		},
+		GetJobFunc: func(ctx context.Context, jobID, namespace string) (types.Job, error) {
+			return types.Job{ID: jobID, Name: utils.RegionFromContext(ctx)}, nil
+		},
+	}
+
+	handler := tools.ListJobsHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"all_regions": true}
+
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var jobs []struct{ ID, Name, Region string }
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &jobs))
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "eu", jobs[0].Region)
+	assert.Equal(t, "eu", jobs[0].Name)
+	assert.Equal(t, "global", jobs[1].Region)
+}
+
+func TestListEvaluationsHandler_aggregatesByTrigger(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		FilterEvaluationsFunc: func(ctx context.Context, namespace string, filter types.EvaluationFilter) ([]types.Evaluation, error) {
+			assert.Equal(t, []string{"node-update", "job-register"}, filter.TriggeredBy)
+			return []types.Evaluation{
+				{ID: "1", TriggeredBy: "node-update", JobID: "web", Priority: 50, Status: "complete"},
+				{ID: "2", TriggeredBy: "node-update", JobID: "api", Priority: 70, Status: "blocked"},
+				{ID: "3", TriggeredBy: "node-update", JobID: "web", Priority: 20, Status: "complete"},
+				{ID: "4", TriggeredBy: "job-register", JobID: "web", Priority: 50, Status: "pending"},
+			}, nil
+		},
+	}
+
+	handler := tools.ListEvaluationsHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"triggered_by": []interface{}{"node-update", "job-register"},
+		"min_priority": float64(50),
+		"aggregate":    true,
+	}
+
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var loads []tools.EvaluationTriggerLoad
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &loads))
+	require.Len(t, loads, 2)
+	assert.Equal(t, tools.EvaluationTriggerLoad{
+		TriggeredBy: "node-update",
+		Count:       2,
+		ByStatus:    map[string]int{"complete": 1, "blocked": 1},
+		Jobs:        2,
+		MinPriority: 50,
+		MaxPriority: 70,
+	}, loads[0])
+	assert.Equal(t, "job-register", loads[1].TriggeredBy)
+}
+
+func TestSubscribeEventsHandler_collectsUntilMaxEvents(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		StreamEventsFunc: func(ctx context.Context, request types.EventStreamRequest, handle func(types.EventFrame) error) error {
+			assert.Equal(t, []string{"Job:web"}, request.Topics)
+			assert.Equal(t, "prod", request.Namespace)
+			assert.Equal(t, uint64(40), request.Index)
+			for i := uint64(41); i <= 45; i++ {
+				err := handle(types.EventFrame{Index: i, Events: []types.Event{
+					{Topic: "Job", Type: "JobRegistered", Key: "web", Index: i, Payload: json.RawMessage(`{"Job":{}}`)},
+				}})
+				if errors.Is(err, utils.ErrStopStream) {
+					return nil
+				}
+				require.NoError(t, err)
+			}
+			return nil
+		},
+	}
+
+	handler := tools.SubscribeEventsHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"topics":     []interface{}{"Job:web"},
+		"namespace":  "prod",
+		"index":      float64(40),
+		"max_events": float64(3),
+	}
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var subscription tools.EventSubscription
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &subscription))
+	require.Len(t, subscription.Events, 3)
+	assert.True(t, subscription.Truncated)
+	assert.Equal(t, uint64(43), subscription.LastIndex)
+	assert.Nil(t, subscription.Events[0].Payload)
+}
+
+func TestExecAllocationHandler_defaultsToOnlyTask(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		GetAllocationFunc: func(ctx context.Context, allocID string) (types.Allocation, error) {
+			return types.Allocation{ID: allocID, TaskStates: map[string]types.TaskState{"web": {State: "running"}}}, nil
+		},
+		ExecAllocationFunc: func(ctx context.Context, request types.ExecRequest) (types.ExecResult, error) {
+			assert.Equal(t, "a1", request.AllocID)
+			assert.Equal(t, "web", request.Task)
+			assert.Equal(t, []string{"cat", "/local/config.yml"}, request.Command)
+			return types.ExecResult{Stdout: "port: 8080\n"}, nil
+		},
+	}
+
+	handler := tools.ExecAllocationHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"allocation_id": "a1",
+		"command":       []interface{}{"cat", "/local/config.yml"},
+	}
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var result types.ExecResult
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &result))
+	assert.Equal(t, "port: 8080\n", result.Stdout)
+	assert.Equal(t, 0, result.ExitCode)
+}
+
+func TestExecAllocationHandler_requiresTaskWhenAmbiguous(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		GetAllocationFunc: func(ctx context.Context, allocID string) (types.Allocation, error) {
+			return types.Allocation{ID: allocID, TaskStates: map[string]types.TaskState{"web": {}, "sidecar": {}}}, nil
+		},
+		ExecAllocationFunc: func(ctx context.Context, request types.ExecRequest) (types.ExecResult, error) {
+			t.Fatal("exec must not run without a task")
+			return types.ExecResult{}, nil
+		},
+	}
+
+	handler := tools.ExecAllocationHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"allocation_id": "a1",
+		"command":       []interface{}{"id"},
+	}
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "sidecar, web")
+}
+
+func TestGetJobSubmissionHandler_usesCurrentVersionAndDetectsFormat(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		GetJobFunc: func(ctx context.Context, jobID, namespace string) (types.Job, error) {
+			return types.Job{ID: jobID, Version: 4}, nil
+		},
+		GetJobSubmissionFunc: func(ctx context.Context, jobID, namespace string, version int) (types.JobSubmission, error) {
+			assert.Equal(t, "web", jobID)
+			assert.Equal(t, 4, version)
+			return types.JobSubmission{Source: `{"Job":{"ID":"web"}}`}, nil
+		},
+	}
+
+	handler := tools.GetJobSubmissionHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"job_id": "web"}
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var source tools.JobSource
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &source))
+	assert.Equal(t, 4, source.Version)
+	assert.Equal(t, "json", source.Format)
+	assert.Equal(t, `{"Job":{"ID":"web"}}`, source.Source)
+}
+
+func TestGetJobSubmissionHandler_reportsMissingSource(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		GetJobSubmissionFunc: func(ctx context.Context, jobID, namespace string, version int) (types.JobSubmission, error) {
+			return types.JobSubmission{}, &utils.NomadHTTPError{StatusCode: http.StatusNotFound}
+		},
+	}
+
+	handler := tools.GetJobSubmissionHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"job_id": "web", "version": float64(1)}
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "No source is stored for version 1 of job web")
+}
+
+func TestAllocFSCatHandler_readsWindowOfLargeFile(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		StatAllocationFileFunc: func(ctx context.Context, allocID, path string) (types.AllocFileInfo, error) {
+			return types.AllocFileInfo{Name: "app.log", Size: 100}, nil
+		},
+		ReadAllocationFileFunc: func(ctx context.Context, allocID, path string, offset, limit int64) ([]byte, error) {
+			assert.Equal(t, "a1", allocID)
+			assert.Equal(t, "alloc/logs/app.log", path)
+			assert.Equal(t, int64(10), offset)
+			assert.Equal(t, int64(40), limit)
+			return []byte(strings.Repeat("x", 40)), nil
+		},
+	}
+
+	handler := tools.AllocFSCatHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"allocation_id": "a1",
+		"path":          "alloc/logs/app.log",
+		"offset":        float64(10),
+		"max_bytes":     float64(40),
+	}
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	require.Len(t, res.Content, 2)
+	assert.Equal(t, strings.Repeat("x", 40), res.Content[0].(mcp.TextContent).Text)
+	assert.Contains(t, res.Content[1].(mcp.TextContent).Text, "pass offset=50 to continue")
+}
+
+func TestAllocFSCatHandler_refusesDirectory(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		StatAllocationFileFunc: func(ctx context.Context, allocID, path string) (types.AllocFileInfo, error) {
+			return types.AllocFileInfo{Name: "local", IsDir: true}, nil
+		},
+	}
+
+	handler := tools.AllocFSCatHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"allocation_id": "a1", "path": "web/local"}
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "use alloc_fs_list")
+}
+
+func TestGetAllocationLogsHandler_followStreamsUntilByteCap(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		GetAllocationLogsFunc: func(context.Context, string, string, string, bool, int64, int64) (string, error) {
+			t.Fatal("follow must stream instead of reading once")
+			return "", nil
+		},
+		StreamAllocationLogsFunc: func(ctx context.Context, request types.LogStreamRequest, handle func(types.LogFrame) error) error {
+			assert.Equal(t, "start", request.Origin)
+			assert.Equal(t, int64(100), request.Offset)
+			offset := request.Offset
+			for _, chunk := range []string{"line 1\n", "line 2\n", "line 3\n"} {
+				offset += int64(len(chunk))
+				if err := handle(types.LogFrame{Data: []byte(chunk), Offset: offset}); err != nil {
+					if errors.Is(err, utils.ErrStopStream) {
+						return nil
+					}
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	handler := tools.GetAllocationLogsHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"allocation_id": "a1",
+		"task":          "web",
+		"follow":        true,
+		"offset":        float64(100),
+		"max_bytes":     float64(10),
+	}
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &result))
+	assert.Equal(t, "line 1\nlin", result["logs"])
+	assert.Equal(t, true, result["truncated"])
+	assert.Equal(t, float64(110), result["next_offset"])
+}
+
+func TestGetAllocationLogsHandler_limitReadsPageAtOffset(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		ReadAllocationLogsFunc: func(ctx context.Context, request types.LogStreamRequest, limit int64) (types.LogPage, error) {
+			assert.Equal(t, "end", request.Origin)
+			assert.Equal(t, int64(0), request.Offset)
+			assert.Equal(t, int64(8), limit)
+			return types.LogPage{Data: []byte("line 9\n\n"), Offset: 92, NextOffset: 100, Size: 100}, nil
+		},
+	}
+
+	handler := tools.GetAllocationLogsHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"allocation_id": "a1",
+		"task":          "web",
+		"origin":        "end",
+		"limit":         float64(8),
+	}
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &result))
+	assert.Equal(t, "line 9\n\n", result["logs"])
+	assert.Equal(t, float64(92), result["offset"])
+	assert.Equal(t, float64(100), result["next_offset"])
+	assert.Equal(t, float64(84), result["previous_offset"])
+}
+
+func TestGetAllocationLogsHandler_tailBytesReadsEndOfLog(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		ReadAllocationLogsFunc: func(ctx context.Context, request types.LogStreamRequest, limit int64) (types.LogPage, error) {
+			assert.Equal(t, "end", request.Origin)
+			assert.Equal(t, int64(1<<20), request.Offset)
+			assert.Equal(t, int64(1<<20), limit)
+			return types.LogPage{Data: []byte("\x00\x01tail"), Offset: 10, NextOffset: 16, Size: 16}, nil
+		},
+	}
+
+	handler := tools.GetAllocationLogsHandler(mockClient, testLogger())
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"allocation_id": "a1", "task": "web", "tail_bytes": float64(8 << 20)}
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &result))
+	assert.Equal(t, "\x00\x01tail", result["logs"])
+	assert.Equal(t, float64(16), result["next_offset"])
+
+	req.Params.Arguments = map[string]interface{}{"allocation_id": "a1", "task": "web", "tail_bytes": float64(8), "tail": float64(10)}
+	res, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "cannot be combined")
+}
+
+func TestDeploymentControlHandlers_passNamespaceAndAction(t *testing.T) {
+	var paused []bool
+	mockClient := &mocks.MockNomadClient{
+		PromoteDeploymentFunc: func(_ context.Context, deploymentID, namespace string, groups []string) (types.DeploymentUpdateResponse, error) {
+			assert.Equal(t, "prod", namespace)
+			assert.Nil(t, groups)
+			return types.DeploymentUpdateResponse{DeploymentID: deploymentID, EvalID: "e1"}, nil
+		},
+		FailDeploymentFunc: func(_ context.Context, deploymentID, _ string) (types.DeploymentUpdateResponse, error) {
+			version := uint64(2)
+			return types.DeploymentUpdateResponse{DeploymentID: deploymentID, RevertedJobVersion: &version}, nil
+		},
+		PauseDeploymentFunc: func(_ context.Context, deploymentID, _ string, pause bool) (types.DeploymentUpdateResponse, error) {
+			paused = append(paused, pause)
+			return types.DeploymentUpdateResponse{}, errors.New("deployment is not running")
+		},
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"deployment_id": "d1", "namespace": "prod"}
+
+	res, err := tools.PromoteDeploymentHandler(mockClient, testLogger())(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, `"EvalID": "e1"`)
+
+	res, err = tools.FailDeploymentHandler(mockClient, testLogger())(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, `"RevertedJobVersion": 2`)
+
+	res, err = tools.PauseDeploymentHandler(mockClient, false, testLogger())(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "Failed to resume deployment")
+	assert.Equal(t, []bool{false}, paused)
+}
+
+func TestTaskLogsResource_readsTaskAndStream(t *testing.T) {
+	var got []string
+	mockClient := &mocks.MockNomadClient{
+		GetAllocationLogsFunc: func(_ context.Context, allocID, task, logType string, _ bool, tail, _ int64) (string, error) {
+			got = append(got, allocID+" "+task+" "+logType)
+			assert.Equal(t, int64(100), tail)
+			return "boom\n", nil
+		},
+	}
+	s := server.NewMCPServer("test", "0.0.0", server.WithResourceCapabilities(true, true))
+	tools.RegisterResources(s, mockClient, fstest.MapFS{}, testLogger())
+
+	assert.Equal(t, "boom\n", readResourceText(t, s, "nomad://allocations/a1/tasks/web/logs?type=stderr"))
+	assert.Equal(t, "boom\n", readResourceText(t, s, "nomad://allocations/a1/tasks/sidecar/logs"))
+	assert.Equal(t, []string{"a1 web stderr", "a1 sidecar stdout"}, got)
+}
+
+func TestListEvaluationsHandler_passesFilterExpression(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		FilterEvaluationsFunc: func(_ context.Context, namespace string, filter types.EvaluationFilter) ([]types.Evaluation, error) {
+			assert.Equal(t, "blocked", filter.Status)
+			assert.Equal(t, `NodeID == "n1"`, filter.Expression)
+			return []types.Evaluation{{ID: "e1"}}, nil
+		},
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"status": "blocked", "filter": `NodeID == "n1"`}
+	res, err := tools.ListEvaluationsHandler(mockClient, testLogger())(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, `"ID": "e1"`)
+}
+
+func TestDeleteEvaluationHandler_reportsSchedulerError(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		DeleteEvaluationsFunc: func(_ context.Context, evalIDs []string) error {
+			assert.Equal(t, []string{"e1"}, evalIDs)
+			return errors.New("delete is not allowed when the eval broker is enabled")
+		},
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"evaluation_id": "e1"}
+	res, err := tools.DeleteEvaluationHandler(mockClient, testLogger())(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "eval broker is enabled")
+}
+
+func TestGetJobStatusHandler_mergesJobSummaryDeploymentAndAllocations(t *testing.T) {
+	now := time.Now()
+	mockClient := &mocks.MockNomadClient{
+		GetJobFunc: func(context.Context, string, string) (types.Job, error) {
+			return types.Job{ID: "web", Name: "web", Namespace: "prod", Type: "service", Status: "running", Version: 4}, nil
+		},
+		GetJobSummaryFunc: func(context.Context, string, string) (types.JobSummary, error) {
+			return types.JobSummary{Summary: map[string]types.TaskSummary{"api": {Running: 2, Failed: 1}}}, nil
+		},
+		ListJobAllocationsFunc: func(context.Context, string, string) ([]types.Allocation, error) {
+			return []types.Allocation{
+				{ID: "a1", TaskGroup: "api", DesiredStatus: "run", ClientStatus: "running", CreateTime: 1, DeploymentStatus: &types.AllocDeploymentStatus{Healthy: true, Timestamp: &now}},
+				{ID: "a2", TaskGroup: "api", DesiredStatus: "run", ClientStatus: "running", CreateTime: 2},
+				{ID: "a0", TaskGroup: "api", DesiredStatus: "stop", ClientStatus: "failed", CreateTime: 0},
+			}, nil
+		},
+		GetJobDeploymentFunc: func(context.Context, string, string) (types.JobDeployment, error) {
+			return types.JobDeployment{ID: "d1", JobVersion: 4, Status: "running"}, nil
+		},
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"job_id": "web", "namespace": "prod"}
+	res, err := tools.GetJobStatusHandler(mockClient, testLogger())(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var status tools.JobStatus
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &status))
+	assert.Equal(t, 4, status.Version)
+	assert.Equal(t, 2, status.Summary["api"].Running)
+	require.NotNil(t, status.Deployment)
+	assert.Equal(t, "d1", status.Deployment.ID)
+	assert.Equal(t, tools.JobAllocationHealth{Running: 2, Healthy: 1}, status.Health)
+	require.Len(t, status.Allocations, 2)
+	assert.Equal(t, "a2", status.Allocations[0].ID)
+	assert.Nil(t, status.Allocations[0].Healthy)
+	require.NotNil(t, status.Allocations[1].Healthy)
+	assert.True(t, *status.Allocations[1].Healthy)
+}
+
+func TestPurgeNodeHandler_refusesReadyNodeWithoutForce(t *testing.T) {
+	purged := 0
+	mockClient := &mocks.MockNomadClient{
+		GetNodeFunc: func(context.Context, string) (types.Node, error) {
+			return types.Node{ID: "n1", Status: "ready"}, nil
+		},
+		PurgeNodeFunc: func(_ context.Context, nodeID string) (types.NodePurgeResponse, error) {
+			purged++
+			return types.NodePurgeResponse{NodeID: nodeID, EvalIDs: []string{"e1"}}, nil
+		},
+	}
+	handler := tools.PurgeNodeHandler(mockClient, testLogger())
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"node_id": "n1"}
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "still ready")
+	assert.Zero(t, purged)
+
+	req.Params.Arguments = map[string]interface{}{"node_id": "n1", "force": true}
+	res, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, `"e1"`)
+	assert.Equal(t, 1, purged)
+}
+
+func TestAgentSelfHandler_dropsStatsUnlessAsked(t *testing.T) {
+	mockClient := &mocks.MockNomadClient{
+		GetAgentSelfFunc: func(ctx context.Context) (types.AgentSelf, error) {
+			return types.AgentSelf{
+				Config: types.AgentConfig{Region: "global"},
+				Stats:  map[string]map[string]string{"raft": {"state": "Leader"}},
+			}, nil
+		},
+	}
+	handler := tools.AgentSelfHandler(mockClient, log.New(io.Discard, "", 0))
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{}}})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	require.Contains(t, text, `"Region": "global"`)
+	require.NotContains(t, text, "Leader")
+
+	result, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"include_stats": true}}})
+	require.NoError(t, err)
+	require.Contains(t, result.Content[0].(mcp.TextContent).Text, "Leader")
+}
+
+func TestSetAutopilotConfigurationHandler_mergesSettingsWithCheckAndSet(t *testing.T) {
+	current := types.AutopilotConfiguration{CleanupDeadServers: true, LastContactThreshold: "200ms", MaxTrailingLogs: 250, ServerStabilizationTime: "10s", ModifyIndex: 7}
+	var written types.AutopilotConfiguration
+	var writtenCAS bool
+	mockClient := &mocks.MockNomadClient{
+		GetAutopilotConfigFunc: func(ctx context.Context) (types.AutopilotConfiguration, error) {
+			return current, nil
+		},
+		SetAutopilotConfigFunc: func(ctx context.Context, config types.AutopilotConfiguration, cas bool) (bool, error) {
+			written, writtenCAS = config, cas
+			return true, nil
+		},
+	}
+	handler := tools.SetAutopilotConfigurationHandler(mockClient, log.New(io.Discard, "", 0))
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"cleanup_dead_servers":   false,
+		"last_contact_threshold": "500ms",
+	}}})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	require.True(t, writtenCAS)
+	require.Equal(t, uint64(7), written.ModifyIndex)
+	require.False(t, written.CleanupDeadServers)
+	require.Equal(t, "500ms", written.LastContactThreshold)
+	require.Equal(t, uint64(250), written.MaxTrailingLogs)
+	require.Equal(t, "10s", written.ServerStabilizationTime)
+
+	result, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"last_contact_threshold": "soon"}}})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+
+	result, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{}}})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+}
+
+func TestRaftRemovePeerHandler_requiresConfirmationAndRefusesLiveServers(t *testing.T) {
+	removed := ""
+	mockClient := &mocks.MockNomadClient{
+		GetRaftConfigurationFunc: func(ctx context.Context) (types.RaftConfiguration, error) {
+			return types.RaftConfiguration{Servers: []types.RaftOperator{
+				{ID: "s1", Address: "10.0.0.1:4647", Node: "s1.global", Leader: true, Voter: true},
+				{ID: "s2", Address: "10.0.0.2:4647", Node: "s2.global", Voter: true},
+				{ID: "s3", Address: "10.0.0.3:4647", Node: "s3.global", Voter: true},
+			}}, nil
+		},
+		ListAgentMembersFunc: func(ctx context.Context) ([]types.AgentMember, error) {
+			return []types.AgentMember{{Name: "s1.global", Status: "alive"}, {Name: "s2.global", Status: "alive"}, {Name: "s3.global", Status: "failed"}}, nil
+		},
+		RemoveRaftPeerFunc: func(ctx context.Context, id, address string) error {
+			removed = id
+			return nil
+		},
+	}
+	handler := tools.RaftRemovePeerHandler(mockClient, log.New(io.Discard, "", 0))
+	call := func(arguments map[string]interface{}) *mcp.CallToolResult {
+		result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: arguments}})
+		require.NoError(t, err)
+		return result
+	}
+
+	result := call(map[string]interface{}{"id": "s3", "confirm": "s2"})
+	require.True(t, result.IsError)
+	require.Contains(t, result.Content[0].(mcp.TextContent).Text, `confirm must repeat "s3"`)
+
+	result = call(map[string]interface{}{"id": "s1", "confirm": "s1"})
+	require.True(t, result.IsError)
+	require.Contains(t, result.Content[0].(mcp.TextContent).Text, "is the Raft leader")
+
+	result = call(map[string]interface{}{"id": "s2", "confirm": "s2"})
+	require.True(t, result.IsError)
+	require.Contains(t, result.Content[0].(mcp.TextContent).Text, "still alive")
+	require.Empty(t, removed)
+
+	result = call(map[string]interface{}{"id": "s3", "confirm": "s3"})
+	require.False(t, result.IsError)
+	require.Equal(t, "s3", removed)
+}
+
+func TestTransferLeadershipHandler_refusesNonVoters(t *testing.T) {
+	transferred := ""
+	mockClient := &mocks.MockNomadClient{
+		GetRaftConfigurationFunc: func(ctx context.Context) (types.RaftConfiguration, error) {
+			return types.RaftConfiguration{Servers: []types.RaftOperator{
+				{ID: "s1", Address: "10.0.0.1:4647", Node: "s1.global", Leader: true, Voter: true},
+				{ID: "s2", Address: "10.0.0.2:4647", Node: "s2.global", Voter: true},
+				{ID: "s4", Address: "10.0.0.4:4647", Node: "s4.global"},
+			}}, nil
+		},
+		TransferLeadershipFunc: func(ctx context.Context, id, address string) error {
+			transferred = address
+			return nil
+		},
+	}
+	handler := tools.TransferLeadershipHandler(mockClient, log.New(io.Discard, "", 0))
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"id": "s4", "confirm": "s4"}}})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	require.Contains(t, result.Content[0].(mcp.TextContent).Text, "not a voter")
+
+	result, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"address": "10.0.0.2:4647", "confirm": "10.0.0.2:4647"}}})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	require.Equal(t, "10.0.0.2:4647", transferred)
+}
+
+func TestSnapshotHandlers_saveAndRestoreInsideSnapshotDir(t *testing.T) {
+	dir := t.TempDir()
+	var restored []byte
+	mockClient := &mocks.MockNomadClient{
+		SaveSnapshotFunc: func(ctx context.Context, w io.Writer, stale bool) (int64, error) {
+			n, err := w.Write([]byte("snapshot-bytes"))
+			return int64(n), err
+		},
+		RestoreSnapshotFunc: func(ctx context.Context, r io.Reader) error {
+			restored, _ = io.ReadAll(r)
+			return nil
+		},
+	}
+	logger := log.New(io.Discard, "", 0)
+	save := tools.SnapshotSaveHandler(mockClient, dir, logger)
+	restore := tools.SnapshotRestoreHandler(mockClient, dir, logger)
+	call := func(handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), arguments map[string]interface{}) *mcp.CallToolResult {
+		result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: arguments}})
+		require.NoError(t, err)
+		return result
+	}
+
+	result := call(save, map[string]interface{}{"path": "../escape.snap"})
+	require.True(t, result.IsError)
+	require.Contains(t, result.Content[0].(mcp.TextContent).Text, "inside the snapshot directory")
+
+	result = call(save, map[string]interface{}{"path": "backups/nomad.snap"})
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	saved, err := os.ReadFile(filepath.Join(dir, "backups", "nomad.snap"))
+	require.NoError(t, err)
+	require.Equal(t, "snapshot-bytes", string(saved))
+
+	result = call(save, map[string]interface{}{"path": "backups/nomad.snap"})
+	require.True(t, result.IsError)
+	require.Contains(t, result.Content[0].(mcp.TextContent).Text, "overwrite=true")
+
+	result = call(restore, map[string]interface{}{"path": "backups/nomad.snap", "confirm": "nomad.snap"})
+	require.True(t, result.IsError)
+	require.Nil(t, restored)
+
+	result = call(restore, map[string]interface{}{"path": "backups/nomad.snap", "confirm": "backups/nomad.snap"})
+	require.False(t, result.IsError)
+	require.Equal(t, "snapshot-bytes", string(restored))
+}
+
+func TestGetLicenseHandler_reportsExpiryAndCommunityEdition(t *testing.T) {
+	expires := time.Now().Add(72 * time.Hour)
+	mockClient := &mocks.MockNomadClient{
+		GetLicenseFunc: func(ctx context.Context) (types.LicenseReply, error) {
+			return types.LicenseReply{License: &types.License{LicenseID: "lic-1", ExpirationTime: expires, Features: []string{"Sentinel Policies"}}}, nil
+		},
+	}
+	handler := tools.GetLicenseHandler(mockClient, log.New(io.Discard, "", 0))
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	var status tools.LicenseStatus
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &status))
+	require.Equal(t, "lic-1", status.LicenseID)
+	require.False(t, status.Expired)
+	require.Equal(t, "72h0m0s", status.ExpiresIn)
+
+	mockClient.GetLicenseFunc = func(ctx context.Context) (types.LicenseReply, error) {
+		return types.LicenseReply{}, utils.NewNomadHTTPError(501, "GET", "operator/license", []byte("Nomad Enterprise only endpoint"))
+	}
+	result, err = handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	require.Contains(t, result.Content[0].(mcp.TextContent).Text, "community edition")
+}
+
+func TestRunJobHandler_skipUnchangedDoesNotResubmit(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.ParseJobSpecFunc = func(_ context.Context, _ string) (map[string]interface{}, error) {
+		return map[string]interface{}{"ID": "web", "Namespace": "prod"}, nil
+	}
+	mock.PlanJobFunc = func(_ context.Context, job map[string]interface{}, namespace string) (types.JobPlan, error) {
+		assert.Equal(t, "prod", namespace)
+		return types.JobPlan{JobModifyIndex: 42, Diff: &types.JobDiff{
+			Type:       "Edited",
+			ID:         "web",
+			Fields:     []types.FieldDiff{{Type: "Edited", Name: "JobModifyIndex", Old: "41", New: "42"}, {Type: "None", Name: "Priority", Old: "50", New: "50"}},
+			TaskGroups: []types.TaskGroupDiff{{Type: "None", Name: "web", Tasks: []types.TaskDiff{{Type: "None", Name: "app"}}}},
+		}}, nil
+	}
+	mock.RunJobFunc = func(context.Context, string, string, bool) (types.JobRegisterResponse, error) {
+		t.Fatal("an unchanged job must not be submitted")
+		return types.JobRegisterResponse{}, nil
+	}
+	mock.EnforceRunJobFunc = func(context.Context, string, string, bool, int) (types.JobRegisterResponse, error) {
+		t.Fatal("an unchanged job must not be submitted")
+		return types.JobRegisterResponse{}, nil
+	}
+
+	h := tools.RunJobHandler(mock, nil, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_spec":       `job "web" {}`,
+		"skip_unchanged": true,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "Job web is unchanged (JobModifyIndex 42)")
+}
+
+func TestRunJobHandler_skipUnchangedSubmitsAtPlannedIndex(t *testing.T) {
+	t.Parallel()
+
+	var gotIndex int
+	mock := &mocks.MockNomadClient{}
+	mock.ParseJobSpecFunc = func(_ context.Context, _ string) (map[string]interface{}, error) {
+		return map[string]interface{}{"ID": "web"}, nil
+	}
+	mock.PlanJobFunc = func(_ context.Context, job map[string]interface{}, namespace string) (types.JobPlan, error) {
+		assert.Equal(t, "staging", namespace)
+		assert.Equal(t, "staging", job["Namespace"])
+		return types.JobPlan{JobModifyIndex: 42, Diff: &types.JobDiff{
+			Type: "Edited",
+			TaskGroups: []types.TaskGroupDiff{{Type: "Edited", Name: "web", Tasks: []types.TaskDiff{{
+				Type:    "Edited",
+				Name:    "app",
+				Objects: []types.ObjectDiff{{Type: "Edited", Name: "Config", Fields: []types.FieldDiff{{Type: "Edited", Name: "image", Old: "app:1", New: "app:2"}}}},
+			}}}},
+		}}, nil
+	}
+	mock.EnforceRunJobFunc = func(_ context.Context, _, namespace string, _ bool, jobModifyIndex int) (types.JobRegisterResponse, error) {
+		assert.Equal(t, "staging", namespace)
+		gotIndex = jobModifyIndex
+		return types.JobRegisterResponse{EvalID: "e1"}, nil
+	}
+
+	h := tools.RunJobHandler(mock, nil, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_spec":       `{"ID":"web"}`,
+		"namespace":      "staging",
+		"skip_unchanged": true,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, 42, gotIndex)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "e1")
+}
+
+func TestRunJobHandler_surfacesNomadWarnings(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.RunJobFunc = func(context.Context, string, string, bool) (types.JobRegisterResponse, error) {
+		return types.JobRegisterResponse{EvalID: "e1", JobModifyIndex: 5, Warnings: "1 warning:\n\n* Task \"app\": driver config field \"port_map\" is deprecated"}, nil
+	}
+
+	res, err := tools.RunJobHandler(mock, nil, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_spec": `{"ID":"web"}`,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	require.Len(t, res.Content, 2)
+
+	var result types.JobRegisterResponse
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &result))
+	assert.Equal(t, "e1", result.EvalID)
+	warnings := res.Content[1].(mcp.TextContent).Text
+	assert.True(t, strings.HasPrefix(warnings, "WARNINGS from Nomad:"))
+	assert.Contains(t, warnings, "port_map")
+}
+
+func TestRevertAndScaleJobHandlers_surfaceNomadWarnings(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{
+		RevertJobFunc: func(context.Context, string, string, int, types.JobRevertOptions) (types.JobRegisterResponse, error) {
+			return types.JobRegisterResponse{EvalID: "e1", Warnings: "template change_mode \"signal\" needs change_signal"}, nil
+		},
+		ScaleTaskGroupFunc: func(context.Context, string, string, int, string) (types.JobRegisterResponse, error) {
+			return types.JobRegisterResponse{EvalID: "e2", Warnings: "  count exceeds the scaling policy max of 5\n"}, nil
+		},
+	}
+
+	res, err := tools.RevertJobHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":  "web",
+		"version": float64(2),
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	require.Len(t, res.Content, 2)
+	assert.Equal(t, "WARNINGS from Nomad:\ntemplate change_mode \"signal\" needs change_signal", res.Content[1].(mcp.TextContent).Text)
+
+	res, err = tools.ScaleJobHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id": "web",
+		"group":  "app",
+		"count":  float64(6),
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	require.Len(t, res.Content, 2)
+	assert.Equal(t, "WARNINGS from Nomad:\ncount exceeds the scaling policy max of 5", res.Content[1].(mcp.TextContent).Text)
+}
+
+func TestStopJobHandler_omitsWarningsBlockWithoutWarnings(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.StopJobFunc = func(context.Context, string, string, types.JobStopOptions) (types.JobDeregisterResponse, error) {
+		return types.JobDeregisterResponse{EvalID: "e2"}, nil
+	}
+
+	res, err := tools.StopJobHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id": "web",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	require.Len(t, res.Content, 1)
+	assert.NotContains(t, res.Content[0].(mcp.TextContent).Text, "AllocationsStopped")
+}
+
+func TestDispatchJobHandler_decodesPayloadAndPassesMeta(t *testing.T) {
+	t.Parallel()
+
+	var got types.JobDispatchOptions
+	mock := &mocks.MockNomadClient{}
+	mock.DispatchJobFunc = func(_ context.Context, jobID, namespace string, opts types.JobDispatchOptions) (types.JobDispatchResponse, error) {
+		assert.Equal(t, "resize", jobID)
+		assert.Equal(t, "media", namespace)
+		got = opts
+		return types.JobDispatchResponse{DispatchedJobID: "resize/dispatch-1-a", EvalID: "e1"}, nil
+	}
+	h := tools.DispatchJobHandler(mock, testLogger())
+
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":            "resize",
+		"namespace":         "media",
+		"payload":           "AAEC",
+		"payload_encoding":  "base64",
+		"meta":              map[string]interface{}{"width": "640"},
+		"idempotency_token": "req-1",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, types.JobDispatchOptions{Payload: []byte{0, 1, 2}, Meta: map[string]string{"width": "640"}, IdempotencyToken: "req-1"}, got)
+
+	var result types.JobDispatchResponse
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &result))
+	assert.Equal(t, "resize/dispatch-1-a", result.DispatchedJobID)
+	assert.Equal(t, "e1", result.EvalID)
+
+	res, err = h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":           "resize",
+		"payload":          "not base64!",
+		"payload_encoding": "base64",
+	}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "payload is not valid base64")
+}
+
+func TestForcePeriodicJobHandler_launchesInNamespace(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{
+		ForcePeriodicFunc: func(_ context.Context, jobID, namespace string) (types.PeriodicForceResponse, error) {
+			assert.Equal(t, "backup", jobID)
+			assert.Equal(t, "ops", namespace)
+			return types.PeriodicForceResponse{EvalID: "e5", EvalCreateIndex: 40}, nil
+		},
+	}
+	h := tools.ForcePeriodicJobHandler(mock, testLogger())
+
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":    "backup",
+		"namespace": "ops",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	var result types.PeriodicForceResponse
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &result))
+	assert.Equal(t, "e5", result.EvalID)
+
+	res, err = h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+}
+
+func TestDiffJobSpecHandler_ignoresOrderBookkeepingAndDefaults(t *testing.T) {
+	t.Parallel()
+
+	registered := map[string]interface{}{
+		"ID": "web", "Priority": float64(50), "Region": "global", "Version": float64(4), "JobModifyIndex": float64(42),
+		"Meta": map[string]interface{}{"owner": "team-a", "tier": "gold"},
+		"TaskGroups": []interface{}{
+			map[string]interface{}{"Name": "api", "Count": float64(2), "Tasks": []interface{}{
+				map[string]interface{}{"Name": "app", "Config": map[string]interface{}{"image": "app:1"}, "Env": nil},
+			}},
+			map[string]interface{}{"Name": "cache", "Count": float64(1), "Constraints": []interface{}{}},
+		},
+	}
+	spec := map[string]interface{}{
+		"ID": "web", "Priority": float64(50),
+		"Meta": map[string]interface{}{"owner": "team-a"},
+		"TaskGroups": []interface{}{
+			map[string]interface{}{"Name": "cache", "Count": float64(1)},
+			map[string]interface{}{"Name": "api", "Count": float64(2), "Tasks": []interface{}{
+				map[string]interface{}{"Name": "app", "Config": map[string]interface{}{"image": "app:2"}},
+			}},
+		},
+	}
+	mock := &mocks.MockNomadClient{
+		GetJobDefinitionFunc: func(_ context.Context, jobID, namespace string) (map[string]interface{}, error) {
+			assert.Equal(t, "web", jobID)
+			assert.Equal(t, "prod", namespace)
+			return registered, nil
+		},
+		CanonicalizeJobSpecFunc: func(_ context.Context, jobSpec string) (map[string]interface{}, error) {
+			assert.Equal(t, `job "web" {}`, jobSpec)
+			return spec, nil
+		},
+	}
+
+	res, err := tools.DiffJobSpecHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":    "web",
+		"namespace": "prod",
+		"job_spec":  `job "web" {}`,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var diff tools.JobSpecDiff
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &diff))
+	assert.False(t, diff.Identical)
+	assert.Equal(t, 42, diff.JobModifyIndex)
+	assert.Equal(t, []tools.JobSpecChange{
+		{Path: "Meta.tier", Type: "Deleted", Old: "gold"},
+		{Path: "TaskGroups[api].Tasks[app].Config.image", Type: "Edited", Old: "app:1", New: "app:2"},
+	}, diff.Changes)
+	assert.Equal(t, []string{"Region"}, diff.Unset)
+}
+
+func TestKillRandomAllocationHandler_onlyKillsAllowlistedRunningAllocations(t *testing.T) {
+	allowlist, err := tools.ParseChaosAllowlist([]string{"staging/*", "default/web"})
+	require.NoError(t, err)
+	_, err = tools.ParseChaosAllowlist([]string{"web"})
+	require.Error(t, err)
+
+	var stopped []string
+	mock := &mocks.MockNomadClient{
+		ListAllocationsFunc: func(_ context.Context, namespace, jobID string) ([]types.Allocation, error) {
+			require.Equal(t, "default", namespace)
+			require.Equal(t, "web", jobID)
+			return []types.Allocation{
+				{ID: "a1", TaskGroup: "app", ClientStatus: "running", DesiredStatus: "run"},
+				{ID: "a2", TaskGroup: "app", ClientStatus: "running", DesiredStatus: "run"},
+				{ID: "a3", TaskGroup: "app", ClientStatus: "complete", DesiredStatus: "stop"},
+				{ID: "a4", TaskGroup: "cache", ClientStatus: "running", DesiredStatus: "run"},
+			}, nil
+		},
+		StopAllocationFunc: func(_ context.Context, allocID string) error {
+			stopped = append(stopped, allocID)
+			return nil
+		},
+	}
+	h := tools.KillRandomAllocationHandler(mock, allowlist, testLogger())
+	call := func(args map[string]interface{}) (*mcp.CallToolResult, tools.ChaosKillReport) {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		res, err := h(context.Background(), req)
+		require.NoError(t, err)
+		var report tools.ChaosKillReport
+		if !res.IsError {
+			require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+		}
+		return res, report
+	}
+
+	res, _ := call(map[string]interface{}{"job_id": "api"})
+	require.True(t, res.IsError)
+	require.Contains(t, res.Content[0].(mcp.TextContent).Text, "allowlist")
+
+	_, report := call(map[string]interface{}{"job_id": "web", "task_group": "app", "count": float64(5), "dry_run": true})
+	require.Equal(t, 2, report.Running)
+	require.Len(t, report.Allocations, 2)
+	require.Empty(t, stopped)
+
+	_, report = call(map[string]interface{}{"job_id": "web", "task_group": "app"})
+	require.Len(t, report.Allocations, 1)
+	require.Equal(t, []string{report.Allocations[0].ID}, stopped)
+	require.Contains(t, []string{"a1", "a2"}, stopped[0])
+}
+
+func TestGetJobVersionsHandler_rendersDiffsBetweenVersions(t *testing.T) {
+	mock := &mocks.MockNomadClient{
+		GetJobVersionDiffsFunc: func(_ context.Context, jobID, namespace string) (types.JobVersionsResponse, error) {
+			require.Equal(t, "web", jobID)
+			require.Equal(t, "prod", namespace)
+			return types.JobVersionsResponse{
+				Versions: []types.Job{
+					{ID: "web", Version: 2, Stable: true, SubmitTime: 1700000000000000000},
+					{ID: "web", Version: 1},
+					{ID: "web", Version: 0},
+				},
+				Diffs: []types.JobDiff{
+					{Type: "Edited", ID: "web", TaskGroups: []types.TaskGroupDiff{{Type: "Edited", Name: "app",
+						Fields: []types.FieldDiff{{Type: "Edited", Name: "Count", Old: "2", New: "3"}}}}},
+					{Type: "Edited", ID: "web", Fields: []types.FieldDiff{{Type: "Edited", Name: "Version", Old: "0", New: "1"}}},
+				},
+			}, nil
+		},
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"job_id": "web", "namespace": "prod"}
+	res, err := tools.GetJobVersionsHandler(mock, testLogger())(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var report tools.JobVersionsReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+	require.Len(t, report.Versions, 3)
+	require.Equal(t, []string{`+/- Job: "web"`, `  +/- Task Group: "app"`, `    +/- Count: "2" => "3"`}, report.Versions[0].Changes)
+	require.NotNil(t, report.Versions[0].Diff)
+	require.Equal(t, "2023-11-14T22:13:20Z", report.Versions[0].SubmitTime)
+	require.Contains(t, report.Summary[0], "in task groups app (Edited)")
+	require.Contains(t, report.Summary[1], "same job as version 0")
+	require.Contains(t, report.Summary[2], "oldest version")
+	require.Nil(t, report.Versions[2].Diff)
+}
+
+func TestDevAgentHandlers_startPointsClientAtAgentAndStopRestores(t *testing.T) {
+	// A stand-in for nomad that runs until interrupted.
+	bin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "nomad"), []byte("#!/bin/sh\nexec sleep 60\n"), 0o755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	mock := &mocks.MockNomadClient{
+		GetLeaderFunc: func(context.Context) (string, error) { return "127.0.0.1:4647", nil },
+	}
+	mock.SetAddress("http://nomad.example:4646")
+	forgotten := &addressStateCounter{}
+	agent := tools.NewDevAgent(mock, "nomad", testLogger(), forgotten)
+	defer agent.Close()
+
+	res, err := tools.StopDevAgentHandler(agent, testLogger())(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"timeout": "5s"}
+	res, err = tools.StartDevAgentHandler(agent, testLogger())(context.Background(), req)
+	require.NoError(t, err)
+	if res.IsError && strings.Contains(res.Content[0].(mcp.TextContent).Text, "already in use") {
+		t.Skip("127.0.0.1:4646 is in use on this machine")
+	}
+	require.False(t, res.IsError, res.Content[0].(mcp.TextContent).Text)
+	var status tools.DevAgentStatus
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &status))
+	require.True(t, status.Running)
+	require.Equal(t, "http://127.0.0.1:4646", mock.GetAddress())
+	require.Equal(t, "http://nomad.example:4646", status.PreviousAddress)
+	require.Equal(t, 1, forgotten.calls)
+
+	res, err = tools.StopDevAgentHandler(agent, testLogger())(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	require.Equal(t, "http://nomad.example:4646", mock.GetAddress())
+	require.Equal(t, 2, forgotten.calls)
+}
+
+// addressStateCounter counts how often the dev agent switched the client's address.
+type addressStateCounter struct{ calls int }
+
+func (c *addressStateCounter) ForgetAddress() { c.calls++ }
+
+func TestGetJobSourceHandler_fallsBackToJobSpecWithoutSubmission(t *testing.T) {
+	mock := &mocks.MockNomadClient{
+		GetJobFunc: func(_ context.Context, jobID, namespace string) (types.Job, error) {
+			return types.Job{ID: jobID, Version: 3}, nil
+		},
+		GetJobSubmissionFunc: func(_ context.Context, jobID, namespace string, version int) (types.JobSubmission, error) {
+			if version == 3 {
+				return types.JobSubmission{Source: "job \"web\" {}", Format: "hcl2", VariableFlags: map[string]string{"image": "v2"}}, nil
+			}
+			return types.JobSubmission{}, &utils.NomadHTTPError{StatusCode: http.StatusNotFound}
+		},
+		GetJobVersionSpecFunc: func(_ context.Context, jobID, namespace string, version int) (map[string]interface{}, error) {
+			require.Equal(t, 1, version)
+			return map[string]interface{}{"ID": jobID, "Version": float64(1), "JobModifyIndex": float64(40), "Priority": float64(50)}, nil
+		},
+	}
+	handler := tools.GetJobSourceHandler(mock, testLogger())
+	call := func(args map[string]interface{}) tools.JobSource {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		res, err := handler(context.Background(), req)
+		require.NoError(t, err)
+		require.False(t, res.IsError)
+		var source tools.JobSource
+		require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &source))
+		return source
+	}
+
+	source := call(map[string]interface{}{"job_id": "web"})
+	assert.Equal(t, "submission", source.Origin)
+	assert.Equal(t, 3, source.Version)
+	assert.Equal(t, "hcl2", source.Format)
+	assert.Equal(t, map[string]string{"image": "v2"}, source.VariableFlags)
+
+	source = call(map[string]interface{}{"job_id": "web", "version": float64(1)})
+	assert.Equal(t, "job_spec", source.Origin)
+	assert.Equal(t, "json", source.Format)
+	assert.NotEmpty(t, source.Note)
+	var spec map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(source.Source), &spec))
+	assert.Equal(t, "web", spec["Job"]["ID"])
+	assert.NotContains(t, spec["Job"], "JobModifyIndex")
+}
+
+func TestRestartJobHandler_restartsRunningAllocationsInBatches(t *testing.T) {
+	var restarted []string
+	mock := &mocks.MockNomadClient{
+		ListAllocationsFunc: func(_ context.Context, namespace, jobID string) ([]types.Allocation, error) {
+			require.Equal(t, "prod", namespace)
+			require.Equal(t, "web", jobID)
+			return []types.Allocation{
+				{ID: "a1", TaskGroup: "app", ClientStatus: "running", DesiredStatus: "run", TaskStates: map[string]types.TaskState{"server": {}}},
+				{ID: "a2", TaskGroup: "app", ClientStatus: "running", DesiredStatus: "run", TaskStates: map[string]types.TaskState{"server": {}, "sidecar": {}}},
+				{ID: "a3", TaskGroup: "app", ClientStatus: "complete", DesiredStatus: "stop"},
+				{ID: "a4", TaskGroup: "app", ClientStatus: "running", DesiredStatus: "run", TaskStates: map[string]types.TaskState{"server": {}}},
+			}, nil
+		},
+		RestartAllocationFunc: func(_ context.Context, allocID, task string) error {
+			if allocID == "a4" {
+				return errors.New("node unreachable")
+			}
+			restarted = append(restarted, allocID+"/"+task)
+			return nil
+		},
+	}
+	h := tools.RestartJobHandler(mock, testLogger())
+	call := func(args map[string]interface{}) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		res, err := h(context.Background(), req)
+		require.NoError(t, err)
+		return res
+	}
+
+	res := call(map[string]interface{}{"job_id": "web", "namespace": "prod", "task": "sidecar"})
+	require.False(t, res.IsError)
+	var report tools.JobRestartReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+	require.Equal(t, 1, report.Running)
+	require.Equal(t, []string{"a2/sidecar"}, restarted)
+	require.Contains(t, report.Summary, "at once")
+
+	restarted = nil
+	res = call(map[string]interface{}{"job_id": "web", "namespace": "prod", "batch_size": float64(2)})
+	require.True(t, res.IsError)
+	require.Contains(t, res.Content[0].(mcp.TextContent).Text, "Failed to restart allocation a4 after restarting 2")
+	require.Equal(t, []string{"a1/", "a2/"}, restarted)
+
+	res = call(map[string]interface{}{"job_id": "web", "namespace": "prod", "batch_size": float64(0)})
+	require.True(t, res.IsError)
+}
+
+func TestRestartJobHandler_restartsOneAtATimeAndStopsBeforeTheDeadline(t *testing.T) {
+	t.Parallel()
+
+	var restarted []string
+	mock := &mocks.MockNomadClient{
+		ListAllocationsFunc: func(_ context.Context, _, _ string) ([]types.Allocation, error) {
+			return []types.Allocation{
+				{ID: "a1", ClientStatus: "running", DesiredStatus: "run"},
+				{ID: "a2", ClientStatus: "running", DesiredStatus: "run"},
+				{ID: "a3", ClientStatus: "running", DesiredStatus: "run"},
+			}, nil
+		},
+		RestartAllocationFunc: func(_ context.Context, allocID, _ string) error {
+			restarted = append(restarted, allocID)
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	res, err := tools.RestartJobHandler(mock, testLogger())(ctx, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":     "web",
+		"batch_wait": "1m",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, res.Content[0].(mcp.TextContent).Text)
+	require.NoError(t, ctx.Err())
+
+	var report tools.JobRestartReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+	assert.Equal(t, 1, report.BatchSize)
+	assert.Equal(t, []string{"a1"}, restarted)
+	assert.Equal(t, []string{"a2", "a3"}, report.Remaining)
+	assert.Contains(t, report.Summary, "stopped before batch 2 of 3")
+}
+
+func TestCreateQuotaHandler_buildsLimits(t *testing.T) {
+	t.Parallel()
+
+	var got types.QuotaSpec
+	mock := &mocks.MockNomadClient{}
+	mock.UpsertQuotaFunc = func(_ context.Context, quota types.QuotaSpec) error {
+		got = quota
+		return nil
+	}
+
+	h := tools.CreateQuotaHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"name": "team-a",
+		"limits": []interface{}{
+			map[string]interface{}{"region": "global", "cpu": float64(4000), "memory_mb": float64(-1), "variables_limit": float64(10)},
+		},
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	require.Len(t, got.Limits, 1)
+	assert.Equal(t, "global", got.Limits[0].Region)
+	assert.Equal(t, &types.QuotaResources{CPU: 4000, MemoryMB: -1}, got.Limits[0].RegionLimit)
+	require.NotNil(t, got.Limits[0].VariablesLimit)
+	assert.Equal(t, 10, *got.Limits[0].VariablesLimit)
+
+	res, err = h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"name":   "team-a",
+		"limits": []interface{}{map[string]interface{}{"region": "global", "cpu": float64(-2)}},
+	}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "limits[0].cpu must be a whole number of at least -1")
+}
+
+func TestQuotaUsageHandler_reportsUtilizationPerRegion(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.ListQuotasFunc = func(context.Context) ([]types.QuotaSpec, error) {
+		return []types.QuotaSpec{{Name: "team-a", Limits: []types.QuotaLimit{
+			{Region: "global", RegionLimit: &types.QuotaResources{CPU: 4000, MemoryMB: 2048}},
+			{Region: "eu", RegionLimit: &types.QuotaResources{CPU: 1000}},
+		}}}, nil
+	}
+	mock.ListQuotaUsagesFunc = func(context.Context) ([]types.QuotaUsage, error) {
+		return []types.QuotaUsage{{Name: "team-a", Used: map[string]*types.QuotaLimit{
+			"h1": {Region: "global", RegionLimit: &types.QuotaResources{CPU: 1000, MemoryMB: 2048}},
+		}}}, nil
+	}
+
+	h := tools.QuotaUsageHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var reports []tools.QuotaUsageReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &reports))
+	require.Len(t, reports, 1)
+	require.Len(t, reports[0].Regions, 2)
+	assert.Equal(t, "eu", reports[0].Regions[0].Region)
+	assert.Equal(t, map[string]float64{"CPU": 0}, reports[0].Regions[0].Utilization)
+	assert.Equal(t, map[string]float64{"CPU": 25, "MemoryMB": 100}, reports[0].Regions[1].Utilization)
+}
+
+func TestListQuotasHandler_explainsCommunityEdition(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.ListQuotasFunc = func(context.Context) ([]types.QuotaSpec, error) {
+		return nil, utils.NewNomadHTTPError(501, "GET", "quotas", []byte("Nomad Enterprise only endpoint"))
+	}
+
+	h := tools.ListQuotasHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "resource quotas only exist on Nomad Enterprise")
+}
+
+func TestCreateNodePoolHandler_setsMetaAndSchedulerConfiguration(t *testing.T) {
+	t.Parallel()
+
+	var got types.NodePool
+	mock := &mocks.MockNomadClient{}
+	mock.UpsertNodePoolFunc = func(_ context.Context, pool types.NodePool) error {
+		got = pool
+		return nil
+	}
+
+	h := tools.CreateNodePoolHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"name":                    "gpu",
+		"meta":                    map[string]interface{}{"team": "ml"},
+		"memory_oversubscription": false,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, "gpu", got.Name)
+	assert.Equal(t, map[string]string{"team": "ml"}, got.Meta)
+	require.NotNil(t, got.SchedulerConfiguration)
+	assert.Empty(t, got.SchedulerConfiguration.SchedulerAlgorithm)
+	require.NotNil(t, got.SchedulerConfiguration.MemoryOversubscriptionEnabled)
+	assert.False(t, *got.SchedulerConfiguration.MemoryOversubscriptionEnabled)
+}
+
+func TestDeleteNodePoolHandler_refusesBuiltInPools(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.DeleteNodePoolFunc = func(context.Context, string) error {
+		t.Fatal("built-in pools must not be deleted")
+		return nil
+	}
+
+	h := tools.DeleteNodePoolHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"name": "default"}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "the built-in default node pool cannot be deleted")
+}
+
+// operationCallerOf is the journal's Caller for token.
+func operationCallerOf(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestUndoOperationHandler_refusesOtherCallersOperations(t *testing.T) {
+	t.Parallel()
+
+	journal, err := tools.OpenOperationJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+	require.NoError(t, err)
+	defer journal.Close()
+	require.NoError(t, journal.Record(tools.Operation{
+		ID:     "op1",
+		Time:   time.Now(),
+		Caller: operationCallerOf("alice"),
+		Tool:   "delete_variable",
+		Target: &tools.OperationTarget{Kind: "variable", ID: "app/config", Namespace: "prod"},
+		Before: &tools.OperationReference{Found: true, ModifyIndex: 9, Items: map[string]string{"db_password": "s3cret"}},
+		After:  &tools.OperationReference{},
+	}))
+
+	mock := &mocks.MockNomadClient{}
+	mock.CreateVariableFunc = func(_ context.Context, _ types.Variable, _ string, _ int, _ string) error {
+		t.Error("another caller's operation was undone")
+		return nil
+	}
+
+	h := tools.UndoOperationHandler(journal, mock, testLogger())
+	res, err := h(utils.WithToken(context.Background(), "bob"), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"operation_id": "op1",
+		"confirm":      "op1",
+	}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "operation op1 is not in the journal")
+}
+
+func TestUndoOperationHandler_revertsJobAndRefusesDrift(t *testing.T) {
+	t.Parallel()
+
+	journal, err := tools.OpenOperationJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+	require.NoError(t, err)
+	defer journal.Close()
+	before, after := 3, 4
+	require.NoError(t, journal.Record(tools.Operation{
+		ID:     "op1",
+		Time:   time.Now(),
+		Caller: operationCallerOf(""),
+		Tool:   "run_job",
+		Target: &tools.OperationTarget{Kind: "job", ID: "web", Namespace: "prod"},
+		Before: &tools.OperationReference{Found: true, Version: &before},
+		After:  &tools.OperationReference{Found: true, Version: &after},
+	}))
+
+	current := 5
+	var reverted []int
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobFunc = func(_ context.Context, jobID, namespace string) (types.Job, error) {
+		return types.Job{ID: jobID, Namespace: namespace, Version: current}, nil
+	}
+	mock.RevertJobFunc = func(_ context.Context, jobID, namespace string, version int, opts types.JobRevertOptions) (types.JobRegisterResponse, error) {
+		assert.Equal(t, "web", jobID)
+		assert.Equal(t, "prod", namespace)
+		require.NotNil(t, opts.EnforcePriorVersion)
+		reverted = append(reverted, version, *opts.EnforcePriorVersion)
+		return types.JobRegisterResponse{}, nil
+	}
+
+	h := tools.UndoOperationHandler(journal, mock, testLogger())
+	call := func(arguments map[string]interface{}) *mcp.CallToolResult {
+		res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: arguments}})
+		require.NoError(t, err)
+		return res
+	}
+
+	res := call(map[string]interface{}{"operation_id": "op1", "confirm": "op"})
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, `confirm must repeat "op1" exactly`)
+
+	res = call(map[string]interface{}{"operation_id": "op1", "confirm": "op1"})
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "changed since the operation (now version 5, version 4 right after it)")
+	assert.Empty(t, reverted)
+
+	current = 4
+	res = call(map[string]interface{}{"operation_id": "op1", "confirm": "op1"})
+	require.False(t, res.IsError)
+	assert.Equal(t, []int{3, 4}, reverted)
+
+	require.NoError(t, journal.Record(tools.Operation{ID: "op2", Time: time.Now(), Tool: "undo_operation", Arguments: map[string]interface{}{"operation_id": "op1"}}))
+	res = call(map[string]interface{}{"operation_id": "op1", "confirm": "op1"})
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "already undone by operation op2")
+}
+
+func TestUndoOperationHandler_restoresDeletedVariable(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, err := tools.OpenOperationJournal(path)
+	require.NoError(t, err)
+	defer journal.Close()
+	require.NoError(t, journal.SetItemsKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))))
+	require.NoError(t, journal.Record(tools.Operation{
+		ID:     "op1",
+		Time:   time.Now(),
+		Caller: operationCallerOf(""),
+		Tool:   "delete_variable",
+		Target: &tools.OperationTarget{Kind: "variable", ID: "app/config", Namespace: "prod"},
+		Before: &tools.OperationReference{Found: true, ModifyIndex: 9, Items: map[string]string{"db_password": "s3cret"}},
+		After:  &tools.OperationReference{},
+	}))
+
+	var gotValue string
+	var gotCAS int
+	mock := &mocks.MockNomadClient{}
+	mock.CreateVariableFunc = func(_ context.Context, variable types.Variable, namespace string, cas int, _ string) error {
+		assert.Equal(t, "app/config", variable.Path)
+		assert.Equal(t, "prod", namespace)
+		gotValue, gotCAS = variable.Value, cas
+		return nil
+	}
+
+	h := tools.UndoOperationHandler(journal, mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"operation_id": "op1",
+		"confirm":      "op1",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.JSONEq(t, `{"Items":{"db_password":"s3cret"}}`, gotValue)
+	assert.Equal(t, utils.VariableCASMustNotExist, gotCAS)
+
+	list := tools.ListRecentOperationsHandler(journal, testLogger())
+	res, err = list(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.NotContains(t, res.Content[0].(mcp.TextContent).Text, "s3cret")
+	assert.NotContains(t, res.Content[0].(mcp.TextContent).Text, "SealedItems")
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "s3cret")
+	assert.Contains(t, string(raw), "SealedItems")
+}
+
+func TestOperationJournal_keepsNoVariableCopyWithoutAKey(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, err := tools.OpenOperationJournal(path)
+	require.NoError(t, err)
+	defer journal.Close()
+	require.Error(t, journal.SetItemsKey("c2hvcnQ="))
+	require.NoError(t, journal.Record(tools.Operation{
+		ID:     "op1",
+		Time:   time.Now(),
+		Caller: operationCallerOf(""),
+		Tool:   "delete_variable",
+		Target: &tools.OperationTarget{Kind: "variable", ID: "app/config", Namespace: "prod"},
+		Before: &tools.OperationReference{Found: true, ModifyIndex: 9, Items: map[string]string{"db_password": "s3cret"}},
+		After:  &tools.OperationReference{},
+	}))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "s3cret")
+
+	h := tools.UndoOperationHandler(journal, &mocks.MockNomadClient{}, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"operation_id": "op1",
+		"confirm":      "op1",
+	}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "MCP_NOMAD_JOURNAL_KEY")
+}
+
+func TestGetJobScaleStatusHandler_includesPoliciesPerGroup(t *testing.T) {
+	t.Parallel()
+
+	minCount, maxCount := int64(1), int64(10)
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobScaleStatusFunc = func(_ context.Context, jobID, namespace string) (types.JobScaleStatus, error) {
+		assert.Equal(t, "prod", namespace)
+		return types.JobScaleStatus{JobID: jobID, Namespace: namespace, TaskGroups: map[string]types.TaskGroupScaleStatus{
+			"web": {Desired: 3, Running: 3, Healthy: 3},
+		}}, nil
+	}
+	mock.ListScalingPoliciesFunc = func(_ context.Context, namespace, jobID, policyType string) ([]types.ScalingPolicyListStub, error) {
+		assert.Equal(t, "prod", namespace)
+		assert.Equal(t, "web", jobID)
+		assert.Empty(t, policyType)
+		return []types.ScalingPolicyListStub{
+			{ID: "p1", Type: "horizontal", Target: map[string]string{"Job": "web", "Group": "web"}},
+			{ID: "p2", Type: "horizontal", Target: map[string]string{"Job": "web-canary", "Group": "web"}},
+		}, nil
+	}
+	mock.GetScalingPolicyFunc = func(_ context.Context, policyID string) (types.ScalingPolicy, error) {
+		require.Equal(t, "p1", policyID)
+		return types.ScalingPolicy{ID: policyID, Type: "horizontal", Enabled: true, Min: &minCount, Max: &maxCount,
+			Target: map[string]string{"Namespace": "prod", "Job": "web", "Group": "web"},
+			Policy: map[string]interface{}{"cooldown": "1m"}}, nil
+	}
+
+	h := tools.GetJobScaleStatusHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":    "web",
+		"namespace": "prod",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	require.Len(t, res.Content, 1)
+
+	var report tools.JobScaleStatusReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+	assert.Equal(t, 3, report.TaskGroups["web"].Healthy)
+	require.Len(t, report.Policies["web"], 1)
+	assert.Equal(t, "p1", report.Policies["web"][0].ID)
+	assert.EqualValues(t, 10, *report.Policies["web"][0].Max)
+}
+
+func TestGetJobScaleStatusHandler_reportsUnreadablePolicies(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.ListScalingPoliciesFunc = func(context.Context, string, string, string) ([]types.ScalingPolicyListStub, error) {
+		return nil, errors.New("Permission denied")
+	}
+
+	h := tools.GetJobScaleStatusHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"job_id": "web"}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	require.Len(t, res.Content, 2)
+	assert.Contains(t, res.Content[1].(mcp.TextContent).Text, "Could not read the scaling policies of job web: Permission denied")
+}
+
+func TestDiagnoseNodeConnectivityHandler_classifiesNodes(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	event := func(message string, ago time.Duration) types.NodeEvent {
+		return types.NodeEvent{Message: message, Subsystem: "Cluster", Timestamp: now.Add(-ago)}
+	}
+	nodes := map[string]types.NodeConnectivity{
+		"n-dead": {ID: "n-dead", Name: "dead", Status: "down", StatusUpdatedAt: now.Add(-3 * time.Hour).Unix(),
+			Events: []types.NodeEvent{event("Node heartbeat missed", 3*time.Hour)}},
+		"n-flap": {ID: "n-flap", Name: "flap", Status: "ready", StatusUpdatedAt: now.Add(-time.Minute).Unix(),
+			Events: []types.NodeEvent{
+				event("Node heartbeat missed", 2*time.Hour), event("Node reregistered by heartbeat", 2*time.Hour-time.Minute),
+				event("Node heartbeat missed", time.Hour), event("Node reregistered by heartbeat", time.Hour-time.Minute),
+				event("Node heartbeat missed", 48*time.Hour),
+			}},
+		"n-ok": {ID: "n-ok", Name: "ok", Status: "ready", Events: []types.NodeEvent{event("Node registered", 72*time.Hour)}},
+	}
+	mock := &mocks.MockNomadClient{
+		ListNodesFunc: func(ctx context.Context, status string) ([]types.NodeSummary, error) {
+			return []types.NodeSummary{{ID: "n-ok", Status: "ready"}, {ID: "n-flap", Status: "ready"}, {ID: "n-dead", Status: "down"}}, nil
+		},
+		GetNodeConnectivityFunc: func(ctx context.Context, nodeID string) (types.NodeConnectivity, error) {
+			return nodes[nodeID], nil
+		},
+		GetAgentSelfFunc: func(ctx context.Context) (types.AgentSelf, error) {
+			return types.AgentSelf{Config: types.AgentConfig{Server: &types.AgentServer{
+				Enabled: true, MinHeartbeatTTL: 20 * time.Second, HeartbeatGrace: 15 * time.Second,
+			}}}, nil
+		},
+	}
+	handler := tools.DiagnoseNodeConnectivityHandler(mock, testLogger())
+
+	res, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	var diagnosis tools.NodeConnectivityDiagnosis
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &diagnosis))
+
+	assert.Equal(t, "server configuration", diagnosis.HeartbeatTTL.Source)
+	assert.Equal(t, "20s", diagnosis.HeartbeatTTL.TTL)
+	assert.Equal(t, "55s", diagnosis.HeartbeatTTL.DownAfter)
+	assert.Equal(t, 2, diagnosis.HeartbeatTTL.LiveNodes)
+	assert.Equal(t, 1, diagnosis.HealthyNodes)
+	require.Len(t, diagnosis.Nodes, 2)
+	assert.Equal(t, "down", diagnosis.Nodes[0].Diagnosis)
+	assert.Equal(t, "flapping", diagnosis.Nodes[1].Diagnosis)
+	// The miss two days ago is outside the default 24h window.
+	assert.Equal(t, 2, diagnosis.Nodes[1].MissedHeartbeats)
+	assert.Equal(t, 2, diagnosis.Nodes[1].Reregistrations)
+
+	res, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"node_id": "n-ok", "since": "7d"}}})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &diagnosis))
+	require.Len(t, diagnosis.Nodes, 1)
+	assert.Equal(t, "healthy", diagnosis.Nodes[0].Diagnosis)
+	assert.Equal(t, 1, diagnosis.Nodes[0].Reregistrations)
+}
+
+func TestExplainAllocationPlacementHandler_ranksCandidates(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{
+		GetAllocationMetricsFunc: func(ctx context.Context, allocID string) (types.AllocationPlacement, error) {
+			require.Equal(t, "alloc-1", allocID)
+			return types.AllocationPlacement{
+				ID: "alloc-1", JobID: "web", TaskGroup: "app", NodeID: "node-b",
+				Metrics: &types.AllocMetric{
+					NodesInPool: 5, NodesEvaluated: 5, NodesFiltered: 2, NodesExhausted: 1,
+					NodesAvailable:     map[string]int{"dc1": 5},
+					ConstraintFiltered: map[string]int{"${attr.kernel.name} = linux": 2},
+					DimensionExhausted: map[string]int{"memory": 1},
+					AllocationTime:     1500 * time.Microsecond,
+					ScoreMetaData: []types.NodeScoreMeta{
+						{NodeID: "node-a", NormScore: 0.41234, Scores: map[string]float64{"binpack": 0.5, "job-anti-affinity": -0.2}},
+						{NodeID: "node-b", NormScore: 0.8, Scores: map[string]float64{"binpack": 0.8}},
+					},
+				},
+			}, nil
+		},
+		ListNodesFunc: func(ctx context.Context, status string) ([]types.NodeSummary, error) {
+			return []types.NodeSummary{{ID: "node-a", Name: "alpha"}, {ID: "node-b", Name: "bravo"}}, nil
+		},
+	}
+	handler := tools.ExplainAllocationPlacementHandler(mock, testLogger())
+
+	res, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"allocation_id": "alloc-1"}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	var explanation tools.AllocationPlacementExplanation
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &explanation))
+
+	assert.Equal(t, "bravo", explanation.NodeName)
+	require.Len(t, explanation.Candidates, 2)
+	assert.Equal(t, "node-b", explanation.Candidates[0].NodeID)
+	assert.True(t, explanation.Candidates[0].Chosen)
+	assert.Equal(t, 0.412, explanation.Candidates[1].NormScore)
+	assert.Equal(t, "1.5ms", explanation.AllocationTime)
+	text := strings.Join(explanation.Explanation, "\n")
+	assert.Contains(t, text, "2 nodes were filtered out by ${attr.kernel.name} = linux=2")
+	assert.Contains(t, text, "lacked resources on memory=1")
+	assert.Contains(t, text, "bravo ranked 1 of 2 scored candidates with a final score of 0.8")
+	assert.Contains(t, text, "Compared with alpha (0.412): binpack +0.3, job-anti-affinity +0.2")
+
+	mock.GetAllocationMetricsFunc = func(ctx context.Context, allocID string) (types.AllocationPlacement, error) {
+		return types.AllocationPlacement{ID: allocID}, nil
+	}
+	res, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"allocation_id": "alloc-2"}}})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+}
+
+func TestSimulatePriorityChangeHandler_reportsPreemptions(t *testing.T) {
+	t.Parallel()
+
+	var planned map[string]interface{}
+	mock := &mocks.MockNomadClient{
+		GetJobDefinitionFunc: func(ctx context.Context, jobID, namespace string) (map[string]interface{}, error) {
+			return map[string]interface{}{"ID": jobID, "Type": "service", "Priority": float64(50)}, nil
+		},
+		PlanJobFunc: func(ctx context.Context, job map[string]interface{}, namespace string) (types.JobPlan, error) {
+			planned = job
+			return types.JobPlan{
+				JobModifyIndex: 42,
+				Annotations: &types.PlanAnnotations{
+					DesiredTGUpdates: map[string]types.DesiredUpdates{"api": {Place: 2, Preemptions: 3}},
+					PreemptedAllocs: []types.Allocation{
+						{ID: "a1", Name: "batch.work[0]", Namespace: "default", JobID: "batch"},
+						{ID: "a2", Name: "batch.work[1]", Namespace: "default", JobID: "batch"},
+						{ID: "a3", Name: "cron.run[0]", Namespace: "default", JobID: "cron"},
+					},
+				},
+			}, nil
+		},
+		GetJobFunc: func(ctx context.Context, jobID, namespace string) (types.Job, error) {
+			if jobID == "cron" {
+				return types.Job{}, errors.New("permission denied")
+			}
+			return types.Job{ID: jobID, Priority: 20}, nil
+		},
+		GetSchedulerConfigFunc: func(ctx context.Context) (types.SchedulerConfiguration, error) {
+			return types.SchedulerConfiguration{PreemptionConfig: types.PreemptionConfig{ServiceSchedulerEnabled: true}}, nil
+		},
+	}
+	handler := tools.SimulatePriorityChangeHandler(mock, testLogger())
+
+	res, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"job_id": "api", "priority": float64(80)}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, 80, planned["Priority"])
+
+	var simulation tools.PriorityChangeSimulation
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &simulation))
+	assert.Equal(t, 50, simulation.CurrentPriority)
+	assert.Equal(t, 42, simulation.JobModifyIndex)
+	require.NotNil(t, simulation.PreemptionEnabled)
+	assert.True(t, *simulation.PreemptionEnabled)
+	assert.Equal(t, map[string]int64{"api": 3}, simulation.Preemptions)
+	require.Len(t, simulation.Preempted, 3)
+	require.NotNil(t, simulation.Preempted[0].JobPriority)
+	assert.Equal(t, 20, *simulation.Preempted[0].JobPriority)
+	assert.Nil(t, simulation.Preempted[2].JobPriority)
+	assert.Contains(t, simulation.Summary[1], "Placing 2 allocations would preempt 3 allocations of 2 lower-priority jobs")
+
+	mock.GetSchedulerConfigFunc = func(ctx context.Context) (types.SchedulerConfiguration, error) {
+		return types.SchedulerConfiguration{PreemptionConfig: types.PreemptionConfig{SystemSchedulerEnabled: true}}, nil
+	}
+	mock.PlanJobFunc = func(ctx context.Context, job map[string]interface{}, namespace string) (types.JobPlan, error) {
+		return types.JobPlan{Annotations: &types.PlanAnnotations{DesiredTGUpdates: map[string]types.DesiredUpdates{"api": {Place: 1}}}}, nil
+	}
+	res, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"job_id": "api", "priority": float64(80)}}})
+	require.NoError(t, err)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "Preemption is disabled for the service scheduler")
+
+	res, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"job_id": "api", "priority": 0.5}}})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+}
+
+func TestGetServiceHandler_reportsCheckHealth(t *testing.T) {
+	t.Parallel()
+
+	checkCalls := 0
+	mock := &mocks.MockNomadClient{
+		GetServiceFunc: func(ctx context.Context, name, namespace string) ([]types.ServiceRegistration, error) {
+			assert.Equal(t, "prod", namespace)
+			return []types.ServiceRegistration{
+				{ID: "reg-2", ServiceName: name, AllocID: "alloc-b", Address: "10.0.0.2", Port: 8080},
+				{ID: "reg-1", ServiceName: name, AllocID: "alloc-a", Address: "10.0.0.1", Port: 8080},
+				{ID: "reg-3", ServiceName: name, AllocID: "alloc-c", Address: "10.0.0.3", Port: 8080},
+			}, nil
+		},
+		GetAllocationChecksFunc: func(ctx context.Context, allocID string) (map[string]types.AllocCheckStatus, error) {
+			checkCalls++
+			switch allocID {
+			case "alloc-a":
+				return map[string]types.AllocCheckStatus{
+					"c1": {Check: "http", Service: "web", Status: "success"},
+					"c2": {Check: "other", Service: "metrics", Status: "failure"},
+				}, nil
+			case "alloc-b":
+				return map[string]types.AllocCheckStatus{"c3": {Check: "http", Service: "web", Status: "failure", Output: "connection refused"}}, nil
+			}
+			return nil, errors.New("node unreachable")
+		},
+	}
+	handler := tools.GetServiceHandler(mock, testLogger())
+
+	res, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"service_name": "web", "namespace": "prod"}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	var report tools.ServiceReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+
+	assert.Equal(t, 1, report.Passing)
+	assert.Equal(t, 1, report.Failing)
+	require.Len(t, report.Instances, 3)
+	assert.Equal(t, "reg-1", report.Instances[0].ID)
+	assert.Equal(t, "passing", report.Instances[0].Health)
+	require.Len(t, report.Instances[0].Checks, 1)
+	assert.Equal(t, "failing", report.Instances[1].Health)
+	assert.Equal(t, "node unreachable", report.Instances[2].CheckError)
+	assert.Empty(t, report.Instances[2].Health)
+
+	checkCalls = 0
+	res, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"service_name": "web", "namespace": "prod", "include_checks": false}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Zero(t, checkCalls)
+}
+
+func TestListServicesHandler_flattensNamespaces(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{
+		ListServicesFunc: func(ctx context.Context, namespace string) ([]types.ServiceRegistrationListStub, error) {
+			assert.Equal(t, "*", namespace)
+			return []types.ServiceRegistrationListStub{
+				{Namespace: "prod", Services: []types.ServiceRegistrationStub{{ServiceName: "web", Tags: []string{"v2"}}, {ServiceName: "api"}}},
+				{Namespace: "dev", Services: []types.ServiceRegistrationStub{{ServiceName: "web"}}},
+			}, nil
+		},
+	}
+
+	res, err := tools.ListServicesHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"namespace": "*"}}})
+	require.NoError(t, err)
+	var services []tools.ServiceSummary
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &services))
+	assert.Equal(t, []tools.ServiceSummary{
+		{Namespace: "dev", ServiceName: "web"},
+		{Namespace: "prod", ServiceName: "api"},
+		{Namespace: "prod", ServiceName: "web", Tags: []string{"v2"}},
+	}, services)
+}
+
+func TestStopJobHandler_passesOptionsAndMonitorsAllocations(t *testing.T) {
+	t.Parallel()
+
+	polls := 0
+	mock := &mocks.MockNomadClient{
+		StopJobFunc: func(_ context.Context, jobID, namespace string, opts types.JobStopOptions) (types.JobDeregisterResponse, error) {
+			assert.Equal(t, "web", jobID)
+			assert.Equal(t, "prod", namespace)
+			assert.Equal(t, types.JobStopOptions{Global: true, NoShutdownDelay: true}, opts)
+			return types.JobDeregisterResponse{EvalID: "eval-1", JobModifyIndex: 42}, nil
+		},
+		ListJobAllocationsFunc: func(_ context.Context, jobID, namespace string) ([]types.Allocation, error) {
+			polls++
+			return []types.Allocation{
+				{ID: "a1", DesiredStatus: "stop", ClientStatus: "complete"},
+				{ID: "a2", DesiredStatus: "stop", ClientStatus: "failed"},
+			}, nil
+		},
+	}
+
+	res, err := tools.StopJobHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":            "web",
+		"namespace":         "prod",
+		"global":            true,
+		"no_shutdown_delay": true,
+		"monitor":           true,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &result))
+	assert.Equal(t, "eval-1", result["EvalID"])
+	assert.Equal(t, true, result["AllocationsStopped"])
+	assert.Nil(t, result["RunningAllocations"])
+	assert.Equal(t, 1, polls)
+
+	res, err = tools.StopJobHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":          "web",
+		"monitor":         true,
+		"monitor_timeout": "soon",
+	}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+}
+
+func TestStopJobHandler_monitorReturnsBeforeTheCallDeadline(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{
+		StopJobFunc: func(_ context.Context, _, _ string, _ types.JobStopOptions) (types.JobDeregisterResponse, error) {
+			return types.JobDeregisterResponse{EvalID: "eval-1"}, nil
+		},
+		ListJobAllocationsFunc: func(ctx context.Context, _, _ string) ([]types.Allocation, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return []types.Allocation{{ID: "a1", DesiredStatus: "stop", ClientStatus: "running"}}, nil
+		},
+	}
+
+	// Shorter than the default monitor_timeout, as with the default -write-timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), 6*time.Second)
+	defer cancel()
+	res, err := tools.StopJobHandler(mock, testLogger())(ctx, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":  "web",
+		"monitor": true,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, res.Content[0].(mcp.TextContent).Text)
+	require.NoError(t, ctx.Err())
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &result))
+	assert.Equal(t, false, result["AllocationsStopped"])
+	assert.Equal(t, []interface{}{"a1"}, result["RunningAllocations"])
+}
+
+func TestRetryFailedAllocationsHandler_forcesRescheduleAndMonitors(t *testing.T) {
+	t.Parallel()
+
+	evaluated := false
+	mock := &mocks.MockNomadClient{
+		ListJobAllocationsFunc: func(_ context.Context, jobID, namespace string) ([]types.Allocation, error) {
+			assert.Equal(t, "prod", namespace)
+			allocs := []types.Allocation{
+				{ID: "a1", Name: "web[0]", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "failed"},
+				{ID: "a2", Name: "web[1]", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "failed", NextAllocation: "a0"},
+				{ID: "a3", Name: "web[2]", TaskGroup: "web", DesiredStatus: "stop", ClientStatus: "failed"},
+				{ID: "a4", Name: "web[3]", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "running"},
+			}
+			if evaluated {
+				allocs[0].NextAllocation = "a5"
+				allocs = append(allocs, types.Allocation{ID: "a5", Name: "web[0]", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "running"})
+			}
+			return allocs, nil
+		},
+		CreateJobEvaluationFunc: func(_ context.Context, jobID, namespace string, forceReschedule bool) (types.JobRegisterResponse, error) {
+			assert.Equal(t, "web", jobID)
+			assert.True(t, forceReschedule)
+			evaluated = true
+			return types.JobRegisterResponse{EvalID: "eval-1"}, nil
+		},
+	}
+
+	res, err := tools.RetryFailedAllocationsHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":    "web",
+		"namespace": "prod",
+		"monitor":   true,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	var retry tools.FailedAllocationRetry
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &retry))
+	assert.Equal(t, "eval-1", retry.EvalID)
+	require.Len(t, retry.Allocations, 1)
+	assert.Equal(t, "a5", retry.Allocations[0].ReplacedBy)
+	assert.Equal(t, "running", retry.Allocations[0].ReplacementStatus)
+	assert.Equal(t, 1, retry.Running)
+	assert.Contains(t, retry.Summary, "replacements are running")
+}
+
+func TestRetryFailedAllocationsHandler_monitorReturnsBeforeTheCallDeadline(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{
+		ListJobAllocationsFunc: func(ctx context.Context, _, _ string) ([]types.Allocation, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return []types.Allocation{{ID: "a1", Name: "web[0]", TaskGroup: "web", DesiredStatus: "run", ClientStatus: "failed"}}, nil
+		},
+		CreateJobEvaluationFunc: func(_ context.Context, _, _ string, _ bool) (types.JobRegisterResponse, error) {
+			return types.JobRegisterResponse{EvalID: "eval-1"}, nil
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 6*time.Second)
+	defer cancel()
+	res, err := tools.RetryFailedAllocationsHandler(mock, testLogger())(ctx, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":  "web",
+		"monitor": true,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, res.Content[0].(mcp.TextContent).Text)
+	require.NoError(t, ctx.Err())
+	var retry tools.FailedAllocationRetry
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &retry))
+	assert.Equal(t, "eval-1", retry.EvalID)
+	assert.Contains(t, retry.Summary, "0 of 1 failed allocations were replaced")
+}
+
+func TestRetryFailedAllocationsHandler_skipsEvaluationWithoutFailures(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{
+		CreateJobEvaluationFunc: func(context.Context, string, string, bool) (types.JobRegisterResponse, error) {
+			t.Fatal("no evaluation expected")
+			return types.JobRegisterResponse{}, nil
+		},
+	}
+
+	res, err := tools.RetryFailedAllocationsHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"job_id": "web"}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "nothing was evaluated")
+}
+
+func TestPlanJobHandler_rendersDiffAndFailedPlacements(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{
+		ParseJobSpecFunc: func(_ context.Context, jobSpec string) (map[string]interface{}, error) {
+			return map[string]interface{}{"ID": "web", "Namespace": "prod"}, nil
+		},
+		PlanJobFunc: func(_ context.Context, job map[string]interface{}, namespace string) (types.JobPlan, error) {
+			assert.Equal(t, "prod", namespace)
+			return types.JobPlan{
+				JobModifyIndex: 42,
+				Diff: &types.JobDiff{Type: "Edited", ID: "web", Fields: []types.FieldDiff{{Type: "Edited", Name: "Version", Old: "1", New: "2"}}, TaskGroups: []types.TaskGroupDiff{{
+					Type:    "Edited",
+					Name:    "app",
+					Fields:  []types.FieldDiff{{Type: "Edited", Name: "Count", Old: "2", New: "3"}},
+					Updates: map[string]int{"create": 1, "in-place update": 2, "ignore": 0},
+					Tasks: []types.TaskDiff{{Type: "Edited", Name: "server", Annotations: []string{"forces create/destroy update"}, Objects: []types.ObjectDiff{{
+						Type: "Edited", Name: "Config", Fields: []types.FieldDiff{{Type: "Edited", Name: "image", Old: "nginx:1.24", New: "nginx:1.25"}},
+					}}}},
+				}}},
+				Annotations: &types.PlanAnnotations{DesiredTGUpdates: map[string]types.DesiredUpdates{"app": {Place: 1, InPlaceUpdate: 2}}},
+				FailedTGAllocs: map[string]interface{}{"app": map[string]interface{}{
+					"NodesEvaluated": 3, "NodesFiltered": 2, "NodesExhausted": 1, "CoalescedFailures": 1,
+					"ConstraintFiltered": map[string]interface{}{"${attr.kernel.name} = windows": 2},
+					"DimensionExhausted": map[string]interface{}{"memory": 1},
+				}},
+			}, nil
+		},
+	}
+
+	res, err := tools.PlanJobHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"job_spec": "job \"web\" {}"}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	var report tools.JobPlanReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+
+	assert.True(t, report.Changed)
+	assert.Equal(t, []string{
+		`+/- Job: "web"`,
+		`  +/- Task Group: "app" (1 create, 2 in-place update)`,
+		`    +/- Count: "2" => "3"`,
+		`    +/- Task: "server" (forces create/destroy update)`,
+		`      +/- Config {`,
+		`        +/- image: "nginx:1.24" => "nginx:1.25"`,
+		`      }`,
+	}, report.Changes)
+	require.Len(t, report.FailedPlacements, 1)
+	assert.Equal(t, 2, report.FailedPlacements[0].Unplaced)
+	assert.Contains(t, report.Summary[1], "WARNING")
+	assert.Contains(t, report.Summary[2], "filtered by ${attr.kernel.name} = windows=2, 1 exhausted on memory=1")
+	assert.Contains(t, report.Summary[len(report.Summary)-1], "job_modify_index 42")
+}
+
+func TestRevertJobHandler_passesVersionAndTokens(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{
+		RevertJobFunc: func(_ context.Context, jobID, namespace string, version int, opts types.JobRevertOptions) (types.JobRegisterResponse, error) {
+			assert.Equal(t, "web", jobID)
+			assert.Equal(t, "prod", namespace)
+			assert.Equal(t, 3, version)
+			require.NotNil(t, opts.EnforcePriorVersion)
+			assert.Equal(t, 5, *opts.EnforcePriorVersion)
+			assert.Equal(t, "consul-secret", opts.ConsulToken)
+			assert.Empty(t, opts.VaultToken)
+			return types.JobRegisterResponse{EvalID: "eval-7"}, nil
+		},
+	}
+	handler := tools.RevertJobHandler(mock, testLogger())
+
+	res, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id":                "web",
+		"namespace":             "prod",
+		"version":               float64(3),
+		"enforce_prior_version": float64(5),
+		"consul_token":          "consul-secret",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, "Job web reverted to version 3 successfully (evaluation eval-7)", res.Content[0].(mcp.TextContent).Text)
+
+	res, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"job_id": "web", "version": 1.5}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+}
+
+func TestListDatacentersHandler_countsNodesPerDatacenter(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.ListNodesFunc = func(_ context.Context, _ string) ([]types.NodeSummary, error) {
+		return []types.NodeSummary{
+			{ID: "a", Datacenter: "dc2", Status: "ready"},
+			{ID: "b", Datacenter: "dc1", Status: "ready"},
+			{ID: "c", Datacenter: "dc1", Status: "down"},
+		}, nil
+	}
+
+	res, err := tools.ListDatacentersHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var dcs []types.DatacenterSummary
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &dcs))
+	assert.Equal(t, []types.DatacenterSummary{
+		{Datacenter: "dc1", Nodes: 2, Ready: 1, Down: 1},
+		{Datacenter: "dc2", Nodes: 1, Ready: 1},
+	}, dcs)
+}
+
+func TestListRegionsHandler_returnsParsedRegions(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.ListRegionsFunc = func(_ context.Context) ([]string, error) {
+		return []string{"global", "eu"}, nil
+	}
+
+	res, err := tools.ListRegionsHandler(mock, testLogger())(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var regions []string
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &regions))
+	assert.Equal(t, []string{"global", "eu"}, regions)
+}
+
+func TestBootstrapNamespaceHandler_createsNamespacePolicyAndToken(t *testing.T) {
+	t.Parallel()
+
+	var gotNamespace types.Namespace
+	var gotPolicy types.ACLPolicy
+	var gotToken types.ACLToken
+	mock := &mocks.MockNomadClient{}
+	mock.GetNamespaceFunc = func(_ context.Context, _ string) (types.Namespace, error) {
+		return types.Namespace{}, &utils.NomadHTTPError{StatusCode: http.StatusNotFound}
+	}
+	mock.GetACLPolicyFunc = func(_ context.Context, _ string) (types.ACLPolicy, error) {
+		return types.ACLPolicy{}, &utils.NomadHTTPError{StatusCode: http.StatusNotFound}
+	}
+	mock.CreateNamespaceFunc = func(_ context.Context, ns types.Namespace) error {
+		gotNamespace = ns
+		return nil
+	}
+	mock.CreateACLPolicyFunc = func(_ context.Context, policy types.ACLPolicy) error {
+		gotPolicy = policy
+		return nil
+	}
+	mock.CreateACLTokenFunc = func(_ context.Context, token types.ACLToken) (types.ACLToken, error) {
+		gotToken = token
+		token.AccessorID = "acc-1"
+		token.SecretID = "secret-1"
+		return token, nil
+	}
+
+	h := tools.BootstrapNamespaceHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"name":         "team-a",
+		"policy":       "read",
+		"create_token": true,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	assert.Equal(t, "team-a", gotNamespace.Name)
+	assert.Equal(t, "team-a-read", gotPolicy.Name)
+	assert.Contains(t, gotPolicy.Rules, `namespace "team-a"`)
+	assert.Contains(t, gotPolicy.Rules, `policy = "read"`)
+	assert.Equal(t, []string{"team-a-read"}, gotToken.Policies)
+	assert.Equal(t, "client", gotToken.Type)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "secret-1")
+}
+
+func TestBootstrapNamespaceHandler_refusesToReplaceExistingObjects(t *testing.T) {
+	t.Parallel()
+
+	var created []string
+	mock := &mocks.MockNomadClient{}
+	mock.GetNamespaceFunc = func(_ context.Context, name string) (types.Namespace, error) {
+		return types.Namespace{Name: name, Description: "owned by team A"}, nil
+	}
+	mock.GetACLPolicyFunc = func(_ context.Context, _ string) (types.ACLPolicy, error) {
+		return types.ACLPolicy{}, &utils.NomadHTTPError{StatusCode: http.StatusNotFound}
+	}
+	mock.CreateNamespaceFunc = func(_ context.Context, ns types.Namespace) error {
+		created = append(created, "namespace "+ns.Name)
+		return nil
+	}
+	mock.CreateACLPolicyFunc = func(_ context.Context, policy types.ACLPolicy) error {
+		created = append(created, "policy "+policy.Name)
+		return nil
+	}
+	h := tools.BootstrapNamespaceHandler(mock, testLogger())
+
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"name": "team-a",
+	}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "namespace team-a already exists")
+	assert.Empty(t, created)
+
+	res, err = h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"name":      "team-a",
+		"overwrite": true,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, []string{"namespace team-a", "policy team-a-write"}, created)
+}
+
+func TestFindJobsByMetaHandler_matchesAcrossNamespaces(t *testing.T) {
+	t.Parallel()
+
+	var listedNamespace string
+	mock := &mocks.MockNomadClient{}
+	mock.ListJobsFunc = func(_ context.Context, namespace, _ string) ([]types.JobSummary, error) {
+		listedNamespace = namespace
+		return []types.JobSummary{
+			{ID: "api", Namespace: "prod"},
+			{ID: "worker", Namespace: "dev"},
+			{ID: "legacy", Namespace: "prod"},
+		}, nil
+	}
+	meta := map[string]map[string]string{
+		"prod/api":    {"owner": "teamX", "tier": "1"},
+		"dev/worker":  {"owner": "teamX"},
+		"prod/legacy": {"owner": "teamY", "tier": "1"},
+	}
+	mock.GetJobFunc = func(_ context.Context, jobID, namespace string) (types.Job, error) {
+		return types.Job{ID: jobID, Meta: meta[namespace+"/"+jobID]}, nil
+	}
+
+	h := tools.FindJobsByMetaHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"meta": "owner=teamX, tier",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, "*", listedNamespace)
+
+	var jobs []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &jobs))
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "api", jobs[0]["ID"])
+	assert.Equal(t, "prod", jobs[0]["Namespace"])
+}
+
+func TestRunJobHandler_serializedSubmissionEnforcesCurrentIndex(t *testing.T) {
+	t.Parallel()
+
+	var gotIndex int
+	var gotSpec string
+	mock := &mocks.MockNomadClient{}
+	mock.ParseJobSpecFunc = func(_ context.Context, _ string) (map[string]interface{}, error) {
+		return map[string]interface{}{"ID": "web", "Namespace": "prod"}, nil
+	}
+	mock.GetJobFunc = func(_ context.Context, jobID, namespace string) (types.Job, error) {
+		require.Equal(t, "web", jobID)
+		require.Equal(t, "prod", namespace)
+		return types.Job{ID: jobID, JobModifyIndex: 42}, nil
+	}
+	mock.EnforceRunJobFunc = func(_ context.Context, jobSpec, _ string, _ bool, jobModifyIndex int) (types.JobRegisterResponse, error) {
+		gotSpec, gotIndex = jobSpec, jobModifyIndex
+		return types.JobRegisterResponse{EvalID: "e1"}, nil
+	}
+	mock.RunJobFunc = func(context.Context, string, string, bool) (types.JobRegisterResponse, error) {
+		t.Fatal("RunJob must not be used when submissions are serialized")
+		return types.JobRegisterResponse{}, nil
+	}
+
+	h := tools.RunJobHandler(mock, tools.NewJobSubmissionLocks(), testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_spec": `job "web" {}`,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, 42, gotIndex)
+	assert.JSONEq(t, `{"Job":{"ID":"web","Namespace":"prod"},"Submission":{"Source":"job \"web\" {}","Format":"hcl2"}}`, gotSpec)
+}
+
+func TestRunJobHandler_reportsConcurrentModification(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.ParseJobSpecFunc = func(_ context.Context, _ string) (map[string]interface{}, error) {
+		return map[string]interface{}{"ID": "web"}, nil
+	}
+	mock.EnforceRunJobFunc = func(_ context.Context, _, _ string, _ bool, jobModifyIndex int) (types.JobRegisterResponse, error) {
+		assert.Equal(t, 7, jobModifyIndex)
+		return types.JobRegisterResponse{}, errors.New("nomad API error PUT jobs: HTTP 500 (Enforcing job modify index 7: job exists with conflicting job modify index: 9)")
+	}
+
+	h := tools.RunJobHandler(mock, nil, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_spec":         `{"ID":"web"}`,
+		"job_modify_index": float64(7),
+	}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "was modified concurrently")
+}
+
+func TestJobSubmissionLocks_queuesPerJob(t *testing.T) {
+	t.Parallel()
+
+	locks := tools.NewJobSubmissionLocks()
+	unlock, err := locks.Lock(context.Background(), "prod", "web")
+	require.NoError(t, err)
+
+	// A different job is not blocked.
+	unlockOther, err := locks.Lock(context.Background(), "prod", "api")
+	require.NoError(t, err)
+	unlockOther()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = locks.Lock(ctx, "prod", "web")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock()
+	unlock, err = locks.Lock(context.Background(), "prod", "web")
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestGetJobHandler_projectsRequestedFields(t *testing.T) {
+	t.Parallel()
+
+	mock := &mocks.MockNomadClient{}
+	mock.GetJobFunc = func(_ context.Context, jobID, _ string) (types.Job, error) {
+		return types.Job{
+			ID:     jobID,
+			Status: "running",
+			TaskGroups: []types.TaskGroup{
+				{Name: "web", Tasks: []types.Task{{Name: "nginx", Config: map[string]interface{}{"image": "nginx:1.25"}}}},
+				{Name: "db", Tasks: []types.Task{{Name: "postgres", Config: map[string]interface{}{"image": "postgres:16"}}}},
+			},
+		}, nil
+	}
+
+	h := tools.GetJobHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id": "app",
+		"fields": []interface{}{"status", "TaskGroups.Name", "$.TaskGroups[1].Tasks[*].Config.image", "Missing"},
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.JSONEq(t, `{
+		"status": "running",
+		"TaskGroups.Name": ["web", "db"],
+		"$.TaskGroups[1].Tasks[*].Config.image": ["postgres:16"],
+		"Missing": null
+	}`, res.Content[0].(mcp.TextContent).Text)
+
+	res, err = h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"job_id": "app",
+		"fields": "TaskGroups[x]",
+	}}})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+}
+
+func TestListAllocationsHandler_filtersBySinceAndClientStatus(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	mock := &mocks.MockNomadClient{}
+	mock.ListAllocationsFunc = func(_ context.Context, _, _ string) ([]types.Allocation, error) {
+		return []types.Allocation{
+			{ID: "recent-failed", ClientStatus: "failed", ModifyTime: now.Add(-30 * time.Minute).UnixNano()},
+			{ID: "old-failed", ClientStatus: "failed", ModifyTime: now.Add(-3 * time.Hour).UnixNano()},
+			{ID: "recent-running", ClientStatus: "running", ModifyTime: now.Add(-10 * time.Minute).UnixNano()},
+		}, nil
+	}
+
+	h := tools.ListAllocationsHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"since":         "1h",
+		"client_status": "failed",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var allocs []types.Allocation
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &allocs))
+	require.Len(t, allocs, 1)
+	assert.Equal(t, "recent-failed", allocs[0].ID)
+
+	res, err = h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"since": "yesterday",
+	}}})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+}
+
+func TestListDeploymentsHandler_sinceUsesEvaluationIndexes(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	mock := &mocks.MockNomadClient{}
+	mock.ListDeploymentsFunc = func(_ context.Context, _ string) ([]types.DeploymentSummary, error) {
+		return []types.DeploymentSummary{
+			{ID: "old", ModifyIndex: 90},
+			{ID: "new", ModifyIndex: 130},
+		}, nil
+	}
+	mock.ListEvaluationsFunc = func(_ context.Context, _, _, _ string) ([]types.Evaluation, error) {
+		return []types.Evaluation{
+			{ID: "e1", CreateIndex: 100, CreateTime: now.Add(-3 * 24 * time.Hour).UnixNano()},
+			{ID: "e2", CreateIndex: 125, CreateTime: now.Add(-20 * time.Hour).UnixNano()},
+			{ID: "e3", CreateIndex: 140, CreateTime: now.Add(-time.Hour).UnixNano()},
+		}, nil
+	}
+
+	h := tools.ListDeploymentsHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"since": "1d",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	var deployments []types.DeploymentSummary
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &deployments))
+	require.Len(t, deployments, 1)
+	assert.Equal(t, "new", deployments[0].ID)
+}
+
+func TestDrainNodeHandler_preflightRequiresForce(t *testing.T) {
+	t.Parallel()
+
+	drained := false
+	mock := &mocks.MockNomadClient{}
+	mock.ListNodeAllocationsFunc = func(_ context.Context, nodeID string) ([]types.Allocation, error) {
+		return []types.Allocation{
+			{ID: "a1", JobID: "db", Namespace: "prod", JobType: "service", TaskGroup: "db", NodeID: nodeID, DesiredStatus: "run", ClientStatus: "running"},
+			{ID: "a2", JobID: "web", Namespace: "prod", JobType: "service", TaskGroup: "web", NodeID: nodeID, DesiredStatus: "run", ClientStatus: "running"},
+			{ID: "a3", JobID: "agent", Namespace: "prod", JobType: "system", TaskGroup: "agent", NodeID: nodeID, DesiredStatus: "run", ClientStatus: "running"},
+		}, nil
+	}
+	mock.ListJobAllocationsFunc = func(_ context.Context, jobID, _ string) ([]types.Allocation, error) {
+		allocs := []types.Allocation{{ID: jobID + "-here", TaskGroup: jobID, NodeID: "n1", DesiredStatus: "run", ClientStatus: "running"}}
+		if jobID == "web" {
+			allocs = append(allocs, types.Allocation{ID: "web-there", TaskGroup: "web", NodeID: "n2", DesiredStatus: "run", ClientStatus: "running"})
+		}
+		return allocs, nil
+	}
+	mock.DrainNodeFunc = func(_ context.Context, _ string, _ bool, _ int64) (string, error) {
+		drained = true
+		return "drain enabled", nil
+	}
+
+	h := tools.DrainNodeHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"node_id": "n1",
+		"enable":  true,
+	}}})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.False(t, drained)
+	text := res.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"JobID": "db"`)
+	assert.NotContains(t, text, `"JobID": "web"`)
+	assert.NotContains(t, text, `"JobID": "agent"`)
+
+	res, err = h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"node_id": "n1",
+		"enable":  true,
+		"force":   true,
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.True(t, drained)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, "single instance")
+}
+
+func TestEnterNodeMaintenanceHandler_runsChecklist(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	mock := &mocks.MockNomadClient{}
+	mock.EligibilityNodeFunc = func(_ context.Context, nodeID string, eligible bool) (types.NodeEligibilityUpdate, error) {
+		calls = append(calls, fmt.Sprintf("eligible=%v", eligible))
+		return types.NodeEligibilityUpdate{NodeID: nodeID}, nil
+	}
+	mock.DrainNodeFunc = func(_ context.Context, _ string, enable bool, deadline int64) (string, error) {
+		calls = append(calls, fmt.Sprintf("drain=%v deadline=%d", enable, deadline))
+		return "Node drain enabled with deadline 600 seconds", nil
+	}
+
+	h := tools.EnterNodeMaintenanceHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"node_id":  "n1",
+		"deadline": float64(600),
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, []string{"eligible=false", "drain=true deadline=600"}, calls)
+
+	var report tools.MaintenanceReport
+	require.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &report))
+	assert.True(t, report.Complete)
+	require.Len(t, report.Steps, 4)
+	assert.Equal(t, "wait for migrations", report.Steps[3].Step)
+	assert.Equal(t, "done", report.Steps[3].Status)
+}
+
+func TestExitNodeMaintenanceHandler_stopsDrainAndRestoresEligibility(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	mock := &mocks.MockNomadClient{}
+	mock.GetNodeFunc = func(_ context.Context, nodeID string) (types.Node, error) {
+		return types.Node{ID: nodeID, Drain: true}, nil
+	}
+	mock.DrainNodeFunc = func(_ context.Context, _ string, enable bool, _ int64) (string, error) {
+		calls = append(calls, fmt.Sprintf("drain=%v", enable))
+		return "Node drain disabled", nil
+	}
+	mock.EligibilityNodeFunc = func(_ context.Context, nodeID string, eligible bool) (types.NodeEligibilityUpdate, error) {
+		calls = append(calls, fmt.Sprintf("eligible=%v", eligible))
+		return types.NodeEligibilityUpdate{NodeID: nodeID, Status: "ready", SchedulingEligibility: types.NodeSchedulingEligible}, nil
+	}
+
+	h := tools.ExitNodeMaintenanceHandler(mock, testLogger())
+	res, err := h(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"node_id": "n1",
+	}}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, []string{"drain=false", "eligible=true"}, calls)
+}
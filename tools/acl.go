@@ -13,15 +13,17 @@ import (
 )
 
 // RegisterACLTools registers all ACL-related tools
-func RegisterACLTools(s *server.MCPServer, nomadClient utils.ACLToolsDeps, logger *log.Logger) {
+func RegisterACLTools(s *server.MCPServer, nomadClient utils.ACLToolsDeps, bootstrapGuard *ACLBootstrapGuard, logger *log.Logger) {
 	// ACL Token tools
 	listACLTokensTool := mcp.NewTool("list_acl_tokens",
 		mcp.WithDescription("List all ACL tokens"),
+		mcp.WithReadOnlyHintAnnotation(true),
 	)
 	s.AddTool(listACLTokensTool, ListACLTokensHandler(nomadClient, logger))
 
 	getACLTokenTool := mcp.NewTool("get_acl_token",
 		mcp.WithDescription("Get details of a specific ACL token"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("accessor_id",
 			mcp.Required(),
 			mcp.Description("Accessor ID of the token to get"),
@@ -61,11 +63,13 @@ func RegisterACLTools(s *server.MCPServer, nomadClient utils.ACLToolsDeps, logge
 	// ACL Policy tools
 	listACLPoliciesTool := mcp.NewTool("list_acl_policies",
 		mcp.WithDescription("List all ACL policies"),
+		mcp.WithReadOnlyHintAnnotation(true),
 	)
 	s.AddTool(listACLPoliciesTool, ListACLPoliciesHandler(nomadClient, logger))
 
 	getACLPolicyTool := mcp.NewTool("get_acl_policy",
 		mcp.WithDescription("Get details of a specific ACL policy"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("name",
 			mcp.Required(),
 			mcp.Description("Name of the policy to get"),
@@ -101,11 +105,13 @@ func RegisterACLTools(s *server.MCPServer, nomadClient utils.ACLToolsDeps, logge
 	// ACL Role tools
 	listACLRolesTool := mcp.NewTool("list_acl_roles",
 		mcp.WithDescription("List all ACL roles"),
+		mcp.WithReadOnlyHintAnnotation(true),
 	)
 	s.AddTool(listACLRolesTool, ListACLRolesHandler(nomadClient, logger))
 
 	getACLRoleTool := mcp.NewTool("get_acl_role",
 		mcp.WithDescription("Get details of a specific ACL role"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("id",
 			mcp.Required(),
 			mcp.Description("ID of the role to get"),
@@ -138,11 +144,44 @@ func RegisterACLTools(s *server.MCPServer, nomadClient utils.ACLToolsDeps, logge
 	)
 	s.AddTool(deleteACLRoleTool, DeleteACLRoleHandler(nomadClient, logger))
 
+	explainTokenAccessTool := mcp.NewTool("explain_token_access",
+		mcp.WithDescription("Merge the policies of an ACL token, including those of its roles, into its effective permissions and optionally answer whether it may perform an operation"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("accessor_id",
+			mcp.Required(),
+			mcp.Description("Accessor ID of the token to explain"),
+		),
+		mcp.WithString("operation",
+			mcp.Description("Operation to check: a namespace capability (e.g. submit-job, read-logs, alloc-exec), variables:<read|write|list|destroy>, or <agent|node|operator|plugin|quota>:<list|read|write>"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace the operation targets (default: default)"),
+		),
+		mcp.WithString("variable_path",
+			mcp.Description("Variable path for variables operations"),
+		),
+	)
+	s.AddTool(explainTokenAccessTool, ExplainTokenAccessHandler(nomadClient, logger))
+
 	// Bootstrap ACL token tool
 	bootstrapACLTokenTool := mcp.NewTool("bootstrap_acl_token",
-		mcp.WithDescription("Bootstrap the ACL system and get the initial management token"),
+		mcp.WithDescription("Bootstrap the ACL system and get the initial management token. The token secret is returned only once and masked in every other tool result"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithBoolean("confirm",
+			mcp.Required(),
+			mcp.Description("Must be true: bootstrapping can only be done once per cluster"),
+		),
+		mcp.WithString("scope",
+			mcp.Description("Use the management token for the calls of this session only (session) or for every session without its own token (server) (default: session)"),
+			mcp.Enum("session", "server"),
+		),
 	)
-	s.AddTool(bootstrapACLTokenTool, BootstrapACLTokenHandler(nomadClient, logger))
+	s.AddTool(bootstrapACLTokenTool, BootstrapACLTokenHandler(nomadClient, bootstrapGuard, logger))
+
+	rollbackACLBootstrapTool := mcp.NewTool("rollback_acl_bootstrap",
+		mcp.WithDescription("Switch back to the token in use before this session's bootstrap_acl_token call. The management token stays valid in Nomad"),
+	)
+	s.AddTool(rollbackACLBootstrapTool, RollbackACLBootstrapHandler(bootstrapGuard, logger))
 }
 
 // ListACLTokensHandler handles the list_acl_tokens tool request
@@ -150,7 +189,7 @@ func ListACLTokensHandler(nomadClient utils.ACLToolsDeps, logger *log.Logger) fu
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		tokens, err := nomadClient.ListACLTokens(ctx)
 		if err != nil {
-			logger.Printf("Error listing ACL tokens: %v", err)
+			requestLogf(ctx, logger, "Error listing ACL tokens: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to list ACL tokens", err), nil
 		}
 
@@ -178,7 +217,7 @@ func GetACLTokenHandler(nomadClient utils.ACLToolsDeps, logger *log.Logger) func
 
 		token, err := nomadClient.GetACLToken(ctx, accessorID)
 		if err != nil {
-			logger.Printf("Error getting ACL token: %v", err)
+			requestLogf(ctx, logger, "Error getting ACL token: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get ACL token", err), nil
 		}
 
@@ -232,7 +271,7 @@ func CreateACLTokenHandler(nomadClient utils.ACLToolsDeps, logger *log.Logger) f
 
 		createdToken, err := nomadClient.CreateACLToken(ctx, token)
 		if err != nil {
-			logger.Printf("Error creating ACL token: %v", err)
+			requestLogf(ctx, logger, "Error creating ACL token: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to create ACL token", err), nil
 		}
 
@@ -260,7 +299,7 @@ func DeleteACLTokenHandler(nomadClient utils.ACLToolsDeps, logger *log.Logger) f
 
 		err := nomadClient.DeleteACLToken(ctx, accessorID)
 		if err != nil {
-			logger.Printf("Error deleting ACL token: %v", err)
+			requestLogf(ctx, logger, "Error deleting ACL token: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to delete ACL token", err), nil
 		}
 
@@ -273,7 +312,7 @@ func ListACLPoliciesHandler(nomadClient utils.ACLToolsDeps, logger *log.Logger)
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		policies, err := nomadClient.ListACLPolicies(ctx)
 		if err != nil {
-			logger.Printf("Error listing ACL policies: %v", err)
+			requestLogf(ctx, logger, "Error listing ACL policies: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to list ACL policies", err), nil
 		}
 
@@ -301,7 +340,7 @@ func GetACLPolicyHandler(nomadClient utils.ACLToolsDeps, logger *log.Logger) fun
 
 		policy, err := nomadClient.GetACLPolicy(ctx, name)
 		if err != nil {
-			logger.Printf("Error getting ACL policy: %v", err)
+			requestLogf(ctx, logger, "Error getting ACL policy: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get ACL policy", err), nil
 		}
 
@@ -345,7 +384,7 @@ func CreateACLPolicyHandler(nomadClient utils.ACLToolsDeps, logger *log.Logger)
 
 		err := nomadClient.CreateACLPolicy(ctx, policy)
 		if err != nil {
-			logger.Printf("Error creating ACL policy: %v", err)
+			requestLogf(ctx, logger, "Error creating ACL policy: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to create ACL policy", err), nil
 		}
 
@@ -373,7 +412,7 @@ func DeleteACLPolicyHandler(nomadClient utils.ACLToolsDeps, logger *log.Logger)
 
 		err := nomadClient.DeleteACLPolicy(ctx, name)
 		if err != nil {
-			logger.Printf("Error deleting ACL policy: %v", err)
+			requestLogf(ctx, logger, "Error deleting ACL policy: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to delete ACL policy", err), nil
 		}
 
@@ -386,7 +425,7 @@ func ListACLRolesHandler(nomadClient utils.ACLToolsDeps, logger *log.Logger) fun
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		roles, err := nomadClient.ListACLRoles(ctx)
 		if err != nil {
-			logger.Printf("Error listing ACL roles: %v", err)
+			requestLogf(ctx, logger, "Error listing ACL roles: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to list ACL roles", err), nil
 		}
 
@@ -414,7 +453,7 @@ func GetACLRoleHandler(nomadClient utils.ACLToolsDeps, logger *log.Logger) func(
 
 		role, err := nomadClient.GetACLRole(ctx, id)
 		if err != nil {
-			logger.Printf("Error getting ACL role: %v", err)
+			requestLogf(ctx, logger, "Error getting ACL role: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get ACL role", err), nil
 		}
 
@@ -490,7 +529,7 @@ func CreateACLRoleHandler(nomadClient utils.ACLToolsDeps, logger *log.Logger) fu
 
 		role, err := nomadClient.CreateACLRole(ctx, role)
 		if err != nil {
-			logger.Printf("Error creating ACL role: %v", err)
+			requestLogf(ctx, logger, "Error creating ACL role: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to create ACL role", err), nil
 		}
 
@@ -518,31 +557,10 @@ func DeleteACLRoleHandler(nomadClient utils.ACLToolsDeps, logger *log.Logger) fu
 
 		err := nomadClient.DeleteACLRole(ctx, id)
 		if err != nil {
-			logger.Printf("Error deleting ACL role: %v", err)
+			requestLogf(ctx, logger, "Error deleting ACL role: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to delete ACL role", err), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("ACL role %s deleted successfully", id)), nil
 	}
 }
-
-// BootstrapACLTokenHandler handles the bootstrap_acl_token tool request
-func BootstrapACLTokenHandler(nomadClient utils.ACLToolsDeps, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		token, err := nomadClient.BootstrapACLToken(ctx)
-		if err != nil {
-			logger.Printf("Error bootstrapping ACL token: %v", err)
-			return mcp.NewToolResultErrorFromErr("Failed to bootstrap ACL token", err), nil
-		}
-
-		// Save the token in the client
-		nomadClient.SetToken(token.SecretID)
-
-		tokenJSON, err := json.MarshalIndent(token, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format token details", err), nil
-		}
-
-		return mcp.NewToolResultText(string(tokenJSON)), nil
-	}
-}
@@ -0,0 +1,431 @@
+// File: tools/acl_access.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// namespacePolicyCapabilities expands a namespace policy disposition the way Nomad does.
+var namespacePolicyCapabilities = map[string][]string{
+	"deny":  {"deny"},
+	"read":  {"list-jobs", "parse-job", "read-job", "csi-list-volume", "csi-read-volume", "read-job-scaling", "list-scaling-policies", "read-scaling-policy"},
+	"scale": {"list-scaling-policies", "read-scaling-policy", "read-job-scaling", "scale-job"},
+	"write": {
+		"list-jobs", "parse-job", "read-job", "csi-list-volume", "csi-read-volume", "read-job-scaling", "list-scaling-policies", "read-scaling-policy",
+		"scale-job", "submit-job", "dispatch-job", "read-logs", "read-fs", "alloc-exec", "alloc-lifecycle", "csi-mount-volume", "csi-write-volume", "submit-recommendation",
+	},
+}
+
+// namespacePolicyVariables are the variable capabilities a namespace policy grants on every path.
+var namespacePolicyVariables = map[string][]string{
+	"read":  {"read", "list"},
+	"write": {"write", "read", "destroy", "list"},
+}
+
+// aclCoarseScopes are the rule blocks that take a single policy disposition.
+var aclCoarseScopes = []string{"agent", "node", "operator", "plugin", "quota"}
+
+// aclDispositionRank orders coarse dispositions; deny always wins when policies are merged.
+var aclDispositionRank = map[string]int{"list": 1, "read": 2, "write": 3, "deny": 4}
+
+// NamespaceAccess is the merged access a token has to namespaces matching a rule
+type NamespaceAccess struct {
+	Namespace    string               `json:"Namespace"` // name or glob as written in the rules
+	Capabilities []string             `json:"Capabilities"`
+	Variables    []VariablePathAccess `json:"Variables,omitempty"`
+}
+
+// VariablePathAccess is the merged access to variables matching a path rule
+type VariablePathAccess struct {
+	Path         string   `json:"Path"`
+	Capabilities []string `json:"Capabilities"`
+}
+
+// AccessDecision answers whether a token may perform one operation
+type AccessDecision struct {
+	Operation    string   `json:"Operation"`
+	Namespace    string   `json:"Namespace,omitempty"`
+	VariablePath string   `json:"VariablePath,omitempty"`
+	Allowed      bool     `json:"Allowed"`
+	Reason       string   `json:"Reason"`
+	MatchedRule  string   `json:"MatchedRule,omitempty"`
+	GrantedBy    []string `json:"GrantedBy,omitempty"` // policies that grant (or deny) the operation
+}
+
+// TokenAccessReport is the explain_token_access response
+type TokenAccessReport struct {
+	AccessorID      string            `json:"AccessorID"`
+	Name            string            `json:"Name"`
+	Type            string            `json:"Type"`
+	Roles           []string          `json:"Roles,omitempty"`
+	Policies        []string          `json:"Policies"`
+	MissingPolicies []string          `json:"MissingPolicies,omitempty"`
+	Namespaces      []NamespaceAccess `json:"Namespaces"`
+	Scopes          map[string]string `json:"Scopes"`
+	Unevaluated     []string          `json:"Unevaluated,omitempty"` // rule blocks this tool does not interpret
+	Decision        *AccessDecision   `json:"Decision,omitempty"`
+}
+
+// aclGrants maps a capability (or disposition) to the policies granting it.
+type aclGrants map[string][]string
+
+func (g aclGrants) add(policy string, capabilities ...string) {
+	for _, c := range capabilities {
+		if !slices.Contains(g[c], policy) {
+			g[c] = append(g[c], policy)
+		}
+	}
+}
+
+type aclNamespaceGrants struct {
+	capabilities aclGrants
+	variables    map[string]aclGrants
+}
+
+// mergedACL is the union of a token's policies, keeping track of which policy granted what.
+type mergedACL struct {
+	namespaces  map[string]*aclNamespaceGrants
+	scopes      map[string]aclGrants
+	unevaluated []string
+}
+
+// ExplainTokenAccessHandler returns a handler that explains the effective permissions of a token
+func ExplainTokenAccessHandler(client utils.ACLAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		accessorID, ok := arguments["accessor_id"].(string)
+		if !ok || accessorID == "" {
+			return mcp.NewToolResultError("accessor_id is required"), nil
+		}
+		operation, _ := arguments["operation"].(string)
+		variablePath, _ := arguments["variable_path"].(string)
+		if strings.HasPrefix(operation, "variables:") && variablePath == "" {
+			return mcp.NewToolResultError("variable_path is required for variables operations"), nil
+		}
+
+		token, err := client.GetACLToken(ctx, accessorID)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting ACL token: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get ACL token", err), nil
+		}
+
+		report := TokenAccessReport{
+			AccessorID: token.AccessorID,
+			Name:       token.Name,
+			Type:       token.Type,
+			Policies:   []string{},
+			Namespaces: []NamespaceAccess{},
+			Scopes:     map[string]string{},
+		}
+
+		acl := &mergedACL{namespaces: map[string]*aclNamespaceGrants{}, scopes: map[string]aclGrants{}}
+		if token.Type != "management" {
+			policyNames, roles, err := tokenPolicyNames(ctx, client, token)
+			if err != nil {
+				requestLogf(ctx, logger, "Error resolving token roles: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to resolve token roles", err), nil
+			}
+			report.Roles = roles
+
+			for _, name := range policyNames {
+				policy, err := client.GetACLPolicy(ctx, name)
+				if isNotFound(err) {
+					report.MissingPolicies = append(report.MissingPolicies, name)
+					continue
+				}
+				if err != nil {
+					requestLogf(ctx, logger, "Error getting ACL policy %s: %v", name, err)
+					return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Failed to get ACL policy %s", name), err), nil
+				}
+				rules, err := parseACLRules(policy.Rules)
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("Failed to parse rules of ACL policy %s: %v", name, err)), nil
+				}
+				acl.merge(name, rules)
+				report.Policies = append(report.Policies, name)
+			}
+			report.Namespaces = acl.namespaceAccess()
+			report.Scopes = acl.scopeAccess()
+			report.Unevaluated = acl.unevaluated
+		}
+
+		if operation != "" {
+			namespace := utils.EffectiveToolNamespace(arguments)
+			decision, err := acl.decide(token.Type == "management", operation, namespace, variablePath)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			report.Decision = &decision
+		}
+
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format token access", err), nil
+		}
+
+		return mcp.NewToolResultText(string(reportJSON)), nil
+	}
+}
+
+// tokenPolicyNames returns the token's policies plus those of its roles, and the role names.
+func tokenPolicyNames(ctx context.Context, client utils.ACLAPI, token types.ACLToken) ([]string, []string, error) {
+	names := slices.Clone(token.Policies)
+	var roles []string
+	for _, link := range token.Roles {
+		role, err := client.GetACLRole(ctx, link.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("role %s: %w", link.ID, err)
+		}
+		roles = append(roles, role.Name)
+		for _, p := range role.Policies {
+			name := p["Name"]
+			if name == "" {
+				name = p["name"]
+			}
+			if name != "" && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, roles, nil
+}
+
+// merge adds the rules of one policy.
+func (a *mergedACL) merge(policy string, rules aclBlock) {
+	for _, block := range rules.Blocks {
+		switch {
+		case block.Type == "namespace" && len(block.Labels) == 1:
+			ns := a.namespaces[block.Labels[0]]
+			if ns == nil {
+				ns = &aclNamespaceGrants{capabilities: aclGrants{}, variables: map[string]aclGrants{}}
+				a.namespaces[block.Labels[0]] = ns
+			}
+			disposition := block.stringAttr("policy")
+			ns.capabilities.add(policy, namespacePolicyCapabilities[disposition]...)
+			ns.capabilities.add(policy, block.stringListAttr("capabilities")...)
+			if implied, ok := namespacePolicyVariables[disposition]; ok {
+				ns.variableGrants("*").add(policy, implied...)
+			}
+			for _, variables := range block.Blocks {
+				if variables.Type != "variables" {
+					continue
+				}
+				for _, path := range variables.Blocks {
+					if path.Type == "path" && len(path.Labels) == 1 {
+						ns.variableGrants(path.Labels[0]).add(policy, expandVariableCapabilities(path.stringListAttr("capabilities"))...)
+					}
+				}
+			}
+		case slices.Contains(aclCoarseScopes, block.Type):
+			if disposition := block.stringAttr("policy"); disposition != "" {
+				if a.scopes[block.Type] == nil {
+					a.scopes[block.Type] = aclGrants{}
+				}
+				a.scopes[block.Type].add(policy, disposition)
+			}
+		default:
+			name := block.Type
+			if len(block.Labels) > 0 {
+				name += " " + strings.Join(block.Labels, " ")
+			}
+			if !slices.Contains(a.unevaluated, name) {
+				a.unevaluated = append(a.unevaluated, name)
+			}
+		}
+	}
+}
+
+func (n *aclNamespaceGrants) variableGrants(path string) aclGrants {
+	if n.variables[path] == nil {
+		n.variables[path] = aclGrants{}
+	}
+	return n.variables[path]
+}
+
+// expandVariableCapabilities applies Nomad's variable capability rules: deny overrides
+// everything and read implies list.
+func expandVariableCapabilities(capabilities []string) []string {
+	if slices.Contains(capabilities, "deny") {
+		return []string{"deny"}
+	}
+	if slices.Contains(capabilities, "read") && !slices.Contains(capabilities, "list") {
+		capabilities = append(capabilities, "list")
+	}
+	return capabilities
+}
+
+func (a *mergedACL) namespaceAccess() []NamespaceAccess {
+	access := []NamespaceAccess{}
+	for _, pattern := range slices.Sorted(maps.Keys(a.namespaces)) {
+		ns := a.namespaces[pattern]
+		entry := NamespaceAccess{Namespace: pattern, Capabilities: effectiveCapabilities(ns.capabilities)}
+		for _, path := range slices.Sorted(maps.Keys(ns.variables)) {
+			entry.Variables = append(entry.Variables, VariablePathAccess{Path: path, Capabilities: effectiveCapabilities(ns.variables[path])})
+		}
+		access = append(access, entry)
+	}
+	return access
+}
+
+func (a *mergedACL) scopeAccess() map[string]string {
+	scopes := map[string]string{}
+	for scope, grants := range a.scopes {
+		scopes[scope], _ = strongestDisposition(grants)
+	}
+	return scopes
+}
+
+// effectiveCapabilities lists granted capabilities; a deny anywhere leaves only deny.
+func effectiveCapabilities(grants aclGrants) []string {
+	if _, denied := grants["deny"]; denied {
+		return []string{"deny"}
+	}
+	return slices.Sorted(maps.Keys(grants))
+}
+
+// strongestDisposition merges coarse dispositions the way Nomad does (deny > write > read > list).
+func strongestDisposition(grants aclGrants) (string, []string) {
+	best := ""
+	for disposition := range grants {
+		if aclDispositionRank[disposition] > aclDispositionRank[best] {
+			best = disposition
+		}
+	}
+	return best, grants[best]
+}
+
+// closestACLMatch finds the rule for name: an exact rule wins, otherwise the matching glob
+// with the fewest characters left to the wildcards, as Nomad picks it.
+func closestACLMatch(patterns []string, name string) (string, bool) {
+	if slices.Contains(patterns, name) {
+		return name, true
+	}
+	best, bestScore := "", -1
+	for _, pattern := range slices.Sorted(slices.Values(patterns)) {
+		if !strings.Contains(pattern, "*") || !aclGlobMatch(pattern, name) {
+			continue
+		}
+		score := len(name) - len(strings.ReplaceAll(pattern, "*", ""))
+		if bestScore < 0 || score < bestScore {
+			best, bestScore = pattern, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// aclGlobMatch matches a pattern where * stands for any run of characters, including /.
+func aclGlobMatch(pattern, name string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	rest := name[len(parts[0]):]
+	for i, part := range parts[1:] {
+		if i == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	return rest == ""
+}
+
+// decide answers an operation: a namespace capability (submit-job), variables:<capability>
+// on a path, or <scope>:<list|read|write> for agent, node, operator, plugin and quota.
+func (a *mergedACL) decide(management bool, operation, namespace, variablePath string) (AccessDecision, error) {
+	decision := AccessDecision{Operation: operation}
+	scope, level, scoped := strings.Cut(operation, ":")
+
+	switch {
+	case scoped && scope == "variables":
+		decision.Namespace, decision.VariablePath = namespace, variablePath
+	case scoped:
+		if !slices.Contains(aclCoarseScopes, scope) || aclDispositionRank[level] == 0 || level == "deny" {
+			return decision, fmt.Errorf("unsupported operation %q: use a namespace capability, variables:<capability> or <%s>:<list|read|write>", operation, strings.Join(aclCoarseScopes, "|"))
+		}
+	default:
+		decision.Namespace = namespace
+	}
+
+	if management {
+		decision.Allowed = true
+		decision.Reason = "management tokens bypass ACL policies"
+		return decision, nil
+	}
+
+	if scoped && scope != "variables" {
+		disposition, policies := strongestDisposition(a.scopes[scope])
+		decision.MatchedRule = scope
+		decision.GrantedBy = policies
+		switch {
+		case disposition == "":
+			decision.Reason = fmt.Sprintf("no policy has a %s rule", scope)
+			decision.MatchedRule = ""
+		case disposition == "deny":
+			decision.Reason = fmt.Sprintf("%s access is denied", scope)
+		case aclDispositionRank[disposition] >= aclDispositionRank[level]:
+			decision.Allowed = true
+			decision.Reason = fmt.Sprintf("%s policy %q includes %s", scope, disposition, level)
+		default:
+			decision.Reason = fmt.Sprintf("%s policy %q does not include %s", scope, disposition, level)
+		}
+		return decision, nil
+	}
+
+	pattern, ok := closestACLMatch(slices.Collect(maps.Keys(a.namespaces)), namespace)
+	if !ok {
+		decision.Reason = fmt.Sprintf("no namespace rule matches namespace %s", namespace)
+		return decision, nil
+	}
+	ns := a.namespaces[pattern]
+	decision.MatchedRule = fmt.Sprintf("namespace %q", pattern)
+
+	grants, capability := ns.capabilities, operation
+	if scoped {
+		capability = level
+		if denied, ok := ns.capabilities["deny"]; ok {
+			decision.GrantedBy = denied
+			decision.Reason = fmt.Sprintf("namespace rule %q denies all access", pattern)
+			return decision, nil
+		}
+		path, ok := closestACLMatch(slices.Collect(maps.Keys(ns.variables)), variablePath)
+		if !ok {
+			decision.Reason = fmt.Sprintf("no variables path rule in namespace rule %q matches %s", pattern, variablePath)
+			return decision, nil
+		}
+		grants = ns.variables[path]
+		decision.MatchedRule += fmt.Sprintf(", variables path %q", path)
+	}
+
+	switch {
+	case grants["deny"] != nil:
+		decision.GrantedBy = grants["deny"]
+		decision.Reason = fmt.Sprintf("%s denies access", decision.MatchedRule)
+	case grants[capability] != nil:
+		decision.Allowed = true
+		decision.GrantedBy = grants[capability]
+		decision.Reason = fmt.Sprintf("%s grants %s", decision.MatchedRule, capability)
+	default:
+		decision.Reason = fmt.Sprintf("%s does not grant %s", decision.MatchedRule, capability)
+	}
+	return decision, nil
+}
@@ -0,0 +1,185 @@
+// File: tools/acl_bootstrap.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sync"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ACLBootstrapGuard keeps the management token of an ACL bootstrap from leaking beyond the
+// call that created it. The token applies to the session that bootstrapped, or to the whole
+// server when asked, in which case the server's previous token is kept for rollback. Its
+// secret is masked in every later tool result.
+type ACLBootstrapGuard struct {
+	client utils.NomadACLTokenBootstrapper
+
+	mu       sync.Mutex
+	sessions map[string]string
+	// previous is the server token replaced by a server-wide bootstrap of serverSession.
+	previous      *string
+	serverSession string
+	redactor      *Redactor
+	secrets       []RedactionRule
+}
+
+// NewACLBootstrapGuard returns a guard switching the tokens of client.
+func NewACLBootstrapGuard(client utils.NomadACLTokenBootstrapper) *ACLBootstrapGuard {
+	return &ACLBootstrapGuard{client: client, sessions: map[string]string{}}
+}
+
+// ForgetSession drops the bootstrap token applied to session. A server-wide token stays.
+func (g *ACLBootstrapGuard) ForgetSession(session string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.sessions, session)
+}
+
+// apply records a bootstrapped token for the session in ctx, or for the server.
+func (g *ACLBootstrapGuard) apply(ctx context.Context, token types.ACLToken, serverWide bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	secrets := append(g.secrets, RedactionRule{Pattern: regexp.QuoteMeta(token.SecretID), Replacement: "<redacted bootstrap token>"})
+	redactor, err := NewRedactor(secrets)
+	if err != nil {
+		return err
+	}
+	g.secrets, g.redactor = secrets, redactor
+
+	if !serverWide {
+		g.sessions[sessionID(ctx)] = token.SecretID
+		return nil
+	}
+	if g.previous == nil {
+		previous := g.client.GetToken()
+		g.previous = &previous
+	}
+	g.serverSession = sessionID(ctx)
+	g.client.SetToken(token.SecretID)
+	return nil
+}
+
+// rollback undoes the bootstrap of the session in ctx and describes what it did.
+func (g *ACLBootstrapGuard) rollback(ctx context.Context) (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	session := sessionID(ctx)
+	if _, ok := g.sessions[session]; ok {
+		delete(g.sessions, session)
+		return "This session no longer uses the bootstrap token; its calls use the token they used before the bootstrap.", true
+	}
+	if g.previous != nil && g.serverSession == session {
+		g.client.SetToken(*g.previous)
+		g.previous = nil
+		return "The server token in use before the bootstrap is restored for every session.", true
+	}
+	return "", false
+}
+
+func (g *ACLBootstrapGuard) sessionToken(ctx context.Context) (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	token, ok := g.sessions[sessionID(ctx)]
+	return token, ok
+}
+
+func (g *ACLBootstrapGuard) currentRedactor() *Redactor {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.redactor
+}
+
+// ACLBootstrapMiddleware returns a tool middleware that runs the calls of a session that
+// bootstrapped ACLs with the bootstrap token, and masks bootstrap secrets in the results of
+// every tool except bootstrap_acl_token itself.
+func ACLBootstrapMiddleware(guard *ACLBootstrapGuard) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if token, ok := guard.sessionToken(ctx); ok {
+				ctx = utils.WithToken(ctx, token)
+			}
+			result, err := next(ctx, request)
+			if redactor := guard.currentRedactor(); redactor != nil && result != nil && request.Params.Name != "bootstrap_acl_token" {
+				redactor.redactResult(result)
+			}
+			return result, err
+		}
+	}
+}
+
+// ACLBootstrapResult is the result of bootstrap_acl_token
+type ACLBootstrapResult struct {
+	Token   types.ACLToken `json:"Token"`
+	Scope   string         `json:"Scope"`
+	Summary string         `json:"Summary"`
+}
+
+// BootstrapACLTokenHandler handles the bootstrap_acl_token tool request
+func BootstrapACLTokenHandler(nomadClient utils.ACLAPI, guard *ACLBootstrapGuard, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		if confirm, _ := arguments["confirm"].(bool); !confirm {
+			return mcp.NewToolResultError("bootstrapping ACLs creates the cluster's initial management token and can only be done once; call again with confirm set to true to proceed"), nil
+		}
+		scope, _ := arguments["scope"].(string)
+		switch scope {
+		case "":
+			scope = "session"
+		case "session", "server":
+		default:
+			return mcp.NewToolResultError("scope must be session or server"), nil
+		}
+
+		token, err := nomadClient.BootstrapACLToken(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error bootstrapping ACL token: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to bootstrap ACL token", err), nil
+		}
+		if err := guard.apply(ctx, token, scope == "server"); err != nil {
+			requestLogf(ctx, logger, "Error applying ACL bootstrap token: %v", err)
+			return mcp.NewToolResultErrorFromErr("ACLs were bootstrapped but the token could not be applied", err), nil
+		}
+		requestLogf(ctx, logger, "ACLs bootstrapped: management token %s applied to the %s", token.AccessorID, scope)
+
+		result := ACLBootstrapResult{Token: token, Scope: scope}
+		if scope == "server" {
+			result.Summary = "The management token now authenticates every session without its own token. "
+		} else {
+			result.Summary = "The management token now authenticates the calls of this session only. "
+		}
+		result.Summary += fmt.Sprintf("Store SecretID now: it is shown only in this result and masked in every other tool result. rollback_acl_bootstrap switches back to the previous token; the token %s itself stays valid in Nomad.", token.AccessorID)
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format token details", err), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// RollbackACLBootstrapHandler returns a handler that stops using the bootstrap token of the
+// calling session
+func RollbackACLBootstrapHandler(guard *ACLBootstrapGuard, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		summary, ok := guard.rollback(ctx)
+		if !ok {
+			return mcp.NewToolResultError("this session has no ACL bootstrap to roll back"), nil
+		}
+		requestLogf(ctx, logger, "ACL bootstrap token rolled back")
+		return mcp.NewToolResultText(summary), nil
+	}
+}
@@ -0,0 +1,278 @@
+// File: tools/acl_rules.go
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// aclBlock is one block of an ACL policy document, e.g. namespace "default" { ... }.
+// The document itself is parsed as an unnamed block.
+type aclBlock struct {
+	Type   string
+	Labels []string
+	Attrs  map[string]interface{} // string, bool, float64 or []interface{}
+	Blocks []aclBlock
+}
+
+// aclLabeledBlocks are the block types whose JSON form nests the label as an object key.
+var aclLabeledBlocks = map[string]bool{"namespace": true, "host_volume": true, "node_pool": true, "path": true}
+
+// parseACLRules parses the HCL or JSON rules of an ACL policy. Only the subset of HCL used
+// by ACL policies is understood: blocks with string labels, string/bool/number attributes,
+// lists and comments.
+func parseACLRules(rules string) (aclBlock, error) {
+	if strings.HasPrefix(strings.TrimSpace(rules), "{") {
+		var doc map[string]interface{}
+		if err := json.Unmarshal([]byte(rules), &doc); err != nil {
+			return aclBlock{}, err
+		}
+		return aclBlockFromJSON("", nil, doc), nil
+	}
+
+	tokens, err := lexACLRules(rules)
+	if err != nil {
+		return aclBlock{}, err
+	}
+	p := &aclParser{tokens: tokens}
+	root, err := p.body("")
+	if err != nil {
+		return aclBlock{}, err
+	}
+	if !p.done() {
+		return aclBlock{}, fmt.Errorf("line %d: unexpected %q", p.peek().line, p.peek().text)
+	}
+	return root, nil
+}
+
+func aclBlockFromJSON(blockType string, labels []string, body map[string]interface{}) aclBlock {
+	block := aclBlock{Type: blockType, Labels: labels, Attrs: map[string]interface{}{}}
+	for key, value := range body {
+		bodies := []map[string]interface{}{}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			bodies = append(bodies, v)
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					bodies = append(bodies, m)
+				}
+			}
+			if len(bodies) == 0 {
+				block.Attrs[key] = v
+			}
+		default:
+			block.Attrs[key] = v
+		}
+
+		for _, b := range bodies {
+			if !aclLabeledBlocks[key] {
+				block.Blocks = append(block.Blocks, aclBlockFromJSON(key, nil, b))
+				continue
+			}
+			for label, inner := range b {
+				if innerBody, ok := inner.(map[string]interface{}); ok {
+					block.Blocks = append(block.Blocks, aclBlockFromJSON(key, []string{label}, innerBody))
+				}
+			}
+		}
+	}
+	return block
+}
+
+type aclToken struct {
+	kind string // ident, string, number or punct
+	text string
+	line int
+}
+
+func lexACLRules(src string) ([]aclToken, error) {
+	var tokens []aclToken
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#' || (c == '/' && i+1 < len(src) && src[i+1] == '/'):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case strings.ContainsRune("{}[]=,", rune(c)):
+			tokens = append(tokens, aclToken{kind: "punct", text: string(c), line: line})
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' {
+				if src[j] == '\\' {
+					j++
+				}
+				if j < len(src) && src[j] == '\n' {
+					return nil, fmt.Errorf("line %d: unterminated string", line)
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			text, err := strconv.Unquote(src[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid string: %v", line, err)
+			}
+			tokens = append(tokens, aclToken{kind: "string", text: text, line: line})
+			i = j + 1
+		case unicode.IsLetter(rune(c)) || c == '_':
+			j := i
+			for j < len(src) && (unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j])) || src[j] == '_' || src[j] == '-') {
+				j++
+			}
+			tokens = append(tokens, aclToken{kind: "ident", text: src[i:j], line: line})
+			i = j
+		case unicode.IsDigit(rune(c)) || c == '-':
+			j := i + 1
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, aclToken{kind: "number", text: src[i:j], line: line})
+			i = j
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character %q", line, c)
+		}
+	}
+	return tokens, nil
+}
+
+type aclParser struct {
+	tokens []aclToken
+	pos    int
+}
+
+func (p *aclParser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *aclParser) peek() aclToken {
+	if p.done() {
+		line := 0
+		if len(p.tokens) > 0 {
+			line = p.tokens[len(p.tokens)-1].line
+		}
+		return aclToken{kind: "eof", text: "end of rules", line: line}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *aclParser) next() aclToken {
+	t := p.peek()
+	if !p.done() {
+		p.pos++
+	}
+	return t
+}
+
+func (p *aclParser) expect(text string) error {
+	if t := p.next(); t.text != text || (t.kind != "punct") {
+		return fmt.Errorf("line %d: expected %q, found %q", t.line, text, t.text)
+	}
+	return nil
+}
+
+// body parses attributes and nested blocks until the closing brace (or the end of the
+// document for the root block).
+func (p *aclParser) body(blockType string, labels ...string) (aclBlock, error) {
+	block := aclBlock{Type: blockType, Labels: labels, Attrs: map[string]interface{}{}}
+	for {
+		t := p.peek()
+		if t.kind == "eof" || (t.kind == "punct" && t.text == "}") {
+			return block, nil
+		}
+		if t.kind != "ident" && t.kind != "string" {
+			return block, fmt.Errorf("line %d: expected an attribute or block name, found %q", t.line, t.text)
+		}
+		name := p.next().text
+
+		if next := p.peek(); next.kind == "punct" && next.text == "=" {
+			p.next()
+			value, err := p.value()
+			if err != nil {
+				return block, err
+			}
+			block.Attrs[name] = value
+			continue
+		}
+
+		var childLabels []string
+		for p.peek().kind == "string" {
+			childLabels = append(childLabels, p.next().text)
+		}
+		if err := p.expect("{"); err != nil {
+			return block, err
+		}
+		child, err := p.body(name, childLabels...)
+		if err != nil {
+			return block, err
+		}
+		if err := p.expect("}"); err != nil {
+			return block, err
+		}
+		block.Blocks = append(block.Blocks, child)
+	}
+}
+
+func (p *aclParser) value() (interface{}, error) {
+	t := p.next()
+	switch {
+	case t.kind == "string":
+		return t.text, nil
+	case t.kind == "number":
+		return strconv.ParseFloat(t.text, 64)
+	case t.kind == "ident" && (t.text == "true" || t.text == "false"):
+		return t.text == "true", nil
+	case t.kind == "punct" && t.text == "[":
+		list := []interface{}{}
+		for {
+			if next := p.peek(); next.kind == "punct" && next.text == "]" {
+				p.next()
+				return list, nil
+			}
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+			if next := p.peek(); next.kind == "punct" && next.text == "," {
+				p.next()
+			}
+		}
+	}
+	return nil, fmt.Errorf("line %d: unexpected value %q", t.line, t.text)
+}
+
+// stringAttr returns a string attribute, or "" when absent.
+func (b aclBlock) stringAttr(name string) string {
+	s, _ := b.Attrs[name].(string)
+	return s
+}
+
+// stringListAttr returns the string items of a list attribute.
+func (b aclBlock) stringListAttr(name string) []string {
+	list, _ := b.Attrs[name].([]interface{})
+	var out []string
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+// File: tools/agent.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RegisterAgentTools registers the tools that introspect the Nomad agent the server talks to
+func RegisterAgentTools(s *server.MCPServer, nomadClient utils.AgentToolsAPI, logger *log.Logger) {
+	agentMembersTool := mcp.NewTool("agent_members",
+		mcp.WithDescription("List the servers in the gossip pool of the agent's region with their address, status, protocol versions and tags (role, region, datacenter, build)"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+	s.AddTool(agentMembersTool, AgentMembersHandler(nomadClient, logger))
+
+	agentSelfTool := mcp.NewTool("agent_self",
+		mcp.WithDescription("Get the configuration of the agent the server talks to: region, datacenter, server and client settings, ACL and TLS status and version, plus its gossip membership. Secrets in the agent configuration are not returned"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithBoolean("include_stats",
+			mcp.Description("Include the agent's runtime, Raft, Serf and client stats (default: false)"),
+		),
+	)
+	s.AddTool(agentSelfTool, AgentSelfHandler(nomadClient, logger))
+
+	agentHealthTool := mcp.NewTool("agent_health",
+		mcp.WithDescription("Check whether the agent's client and server are healthy. An unhealthy agent is reported with ok=false and a message rather than as an error"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+	s.AddTool(agentHealthTool, AgentHealthHandler(nomadClient, logger))
+}
+
+// AgentMembersHandler returns a handler for listing the agent's gossip members
+func AgentMembersHandler(client utils.AgentToolsAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		members, err := client.GetAgentMembers(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing agent members: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list agent members", err), nil
+		}
+
+		membersJSON, err := json.MarshalIndent(members, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format agent members", err), nil
+		}
+
+		return mcp.NewToolResultText(string(membersJSON)), nil
+	}
+}
+
+// AgentSelfHandler returns a handler for describing the agent's configuration
+func AgentSelfHandler(client utils.AgentToolsAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+		includeStats, _ := arguments["include_stats"].(bool)
+
+		self, err := client.GetAgentSelf(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting agent configuration: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get agent configuration", err), nil
+		}
+		if !includeStats {
+			self.Stats = nil
+		}
+
+		selfJSON, err := json.MarshalIndent(self, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format agent configuration", err), nil
+		}
+
+		return mcp.NewToolResultText(string(selfJSON)), nil
+	}
+}
+
+// AgentHealthHandler returns a handler for checking the agent's health
+func AgentHealthHandler(client utils.AgentToolsAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		health, err := client.GetAgentHealth(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting agent health: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get agent health", err), nil
+		}
+
+		healthJSON, err := json.MarshalIndent(health, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format agent health", err), nil
+		}
+
+		return mcp.NewToolResultText(string(healthJSON)), nil
+	}
+}
@@ -0,0 +1,190 @@
+// File: tools/alerts.go
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+// alertRuleGroups is the top level of a Prometheus rule file
+type alertRuleGroups struct {
+	Groups []alertRuleGroup `yaml:"groups"`
+}
+
+type alertRuleGroup struct {
+	Name  string      `yaml:"name"`
+	Rules []alertRule `yaml:"rules"`
+}
+
+type alertRule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// RegisterAlertTools registers tools that generate monitoring configuration for jobs
+func RegisterAlertTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log.Logger) {
+	// Generate alert rules tool
+	generateAlertRulesTool := mcp.NewTool("generate_alert_rules",
+		mcp.WithDescription("Generate Prometheus alerting rules (YAML) for a job using Nomad's telemetry metrics: task restarts, OOM kills and allocations pending too long. Requires publish_allocation_metrics on clients"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job to alert on"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithString("job_label",
+			mcp.Description("The Prometheus label holding the Nomad job name. Prometheus renames Nomad's job label to exported_job unless the scrape sets honor_labels (default: exported_job)"),
+		),
+		mcp.WithNumber("restart_threshold",
+			mcp.Description("Alert when tasks restart more than this many times within the window (default: 3)"),
+		),
+		mcp.WithString("window",
+			mcp.Description("The window restarts and OOM kills are counted over, e.g. 15m or 1h (default: 15m)"),
+		),
+		mcp.WithString("pending_for",
+			mcp.Description("Alert when allocations stay queued or starting for this long, e.g. 10m (default: 10m)"),
+		),
+	)
+	s.AddTool(generateAlertRulesTool, GenerateAlertRulesHandler(nomadClient, logger))
+}
+
+// GenerateAlertRulesHandler returns a handler for generating Prometheus alert rules for a job
+func GenerateAlertRulesHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		jobLabel := "exported_job"
+		if l, ok := arguments["job_label"].(string); ok && l != "" {
+			jobLabel = l
+		}
+
+		restartThreshold := 3
+		if t, ok := arguments["restart_threshold"].(float64); ok {
+			if t < 0 {
+				return mcp.NewToolResultError("restart_threshold must not be negative"), nil
+			}
+			restartThreshold = int(t)
+		}
+
+		window, err := alertDurationArgument(arguments, "window", 15*time.Minute)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		pendingFor, err := alertDurationArgument(arguments, "pending_for", 10*time.Minute)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Make sure the rules target a job that exists rather than silently never firing.
+		if _, err := client.GetJob(ctx, jobID, namespace); err != nil {
+			requestLogf(ctx, logger, "Error getting job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
+		}
+
+		rules, err := yaml.Marshal(jobAlertRules(jobID, namespace, jobLabel, restartThreshold, window, pendingFor))
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format alert rules", err), nil
+		}
+
+		return mcp.NewToolResultText(string(rules)), nil
+	}
+}
+
+// jobAlertRules builds the rule group for one job. Allocation metrics come from clients with
+// publish_allocation_metrics enabled; job summary metrics come from the leader.
+func jobAlertRules(jobID, namespace, jobLabel string, restartThreshold int, window, pendingFor time.Duration) alertRuleGroups {
+	selector := fmt.Sprintf("{%s=%s,namespace=%s}", jobLabel, strconv.Quote(jobID), strconv.Quote(namespace))
+	labels := func(severity string) map[string]string {
+		return map[string]string{"severity": severity, "nomad_job": jobID, "nomad_namespace": namespace}
+	}
+	promWindow := prometheusDuration(window)
+
+	return alertRuleGroups{Groups: []alertRuleGroup{{
+		Name: fmt.Sprintf("nomad-job-%s-%s", namespace, jobID),
+		Rules: []alertRule{
+			{
+				Alert:  "NomadJobTaskRestarts",
+				Expr:   fmt.Sprintf("sum by (task_group, task) (increase(nomad_client_allocs_restart%s[%s])) > %d", selector, promWindow, restartThreshold),
+				Labels: labels("warning"),
+				Annotations: map[string]string{
+					"summary":     fmt.Sprintf("Tasks of Nomad job %s are restarting", jobID),
+					"description": fmt.Sprintf("Task {{ $labels.task }} in group {{ $labels.task_group }} of job %s (namespace %s) restarted {{ $value }} times in the last %s.", jobID, namespace, promWindow),
+				},
+			},
+			{
+				Alert:  "NomadJobTaskOOMKilled",
+				Expr:   fmt.Sprintf("sum by (task_group, task) (increase(nomad_client_allocs_oom_killed%s[%s])) > 0", selector, promWindow),
+				Labels: labels("critical"),
+				Annotations: map[string]string{
+					"summary":     fmt.Sprintf("Tasks of Nomad job %s were OOM killed", jobID),
+					"description": fmt.Sprintf("Task {{ $labels.task }} in group {{ $labels.task_group }} of job %s (namespace %s) was killed for exceeding its memory limit in the last %s.", jobID, namespace, promWindow),
+				},
+			},
+			{
+				Alert:  "NomadJobAllocationsPending",
+				Expr:   fmt.Sprintf("sum by (task_group) (nomad_nomad_job_summary_queued%s + nomad_nomad_job_summary_starting%s) > 0", selector, selector),
+				For:    prometheusDuration(pendingFor),
+				Labels: labels("warning"),
+				Annotations: map[string]string{
+					"summary":     fmt.Sprintf("Allocations of Nomad job %s are not starting", jobID),
+					"description": fmt.Sprintf("{{ $value }} allocations of group {{ $labels.task_group }} in job %s (namespace %s) have been queued or starting for more than %s.", jobID, namespace, prometheusDuration(pendingFor)),
+				},
+			},
+		},
+	}}}
+}
+
+// alertDurationArgument parses a positive duration argument, falling back to def.
+func alertDurationArgument(arguments map[string]interface{}, name string, def time.Duration) (time.Duration, error) {
+	value, _ := arguments[name].(string)
+	if strings.TrimSpace(value) == "" {
+		return def, nil
+	}
+	d, err := parseRelativeDuration(value)
+	if err != nil || d < time.Second {
+		return 0, fmt.Errorf("%s must be a duration of at least 1s such as 15m or 1h, got %q", name, value)
+	}
+	return d, nil
+}
+
+// prometheusDuration formats d in Prometheus' duration syntax, e.g. 1h30m or 45s.
+func prometheusDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	var b strings.Builder
+	for _, unit := range []struct {
+		size   time.Duration
+		suffix string
+	}{{24 * time.Hour, "d"}, {time.Hour, "h"}, {time.Minute, "m"}, {time.Second, "s"}} {
+		if n := d / unit.size; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, unit.suffix)
+			d -= n * unit.size
+		}
+	}
+	if b.Len() == 0 {
+		return "0s"
+	}
+	return b.String()
+}
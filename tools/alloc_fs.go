@@ -0,0 +1,221 @@
+// File: tools/alloc_fs.go
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"unicode/utf8"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	defaultAllocFileBytes = 64 << 10
+	maxAllocFileBytes     = 1 << 20
+)
+
+// RegisterAllocFSTools registers the tools that browse an allocation's filesystem
+func RegisterAllocFSTools(s *server.MCPServer, nomadClient utils.AllocFSAPI, logger *log.Logger) {
+	allocFSListTool := mcp.NewTool("alloc_fs_list",
+		mcp.WithDescription("List a directory of an allocation's filesystem, e.g. alloc/logs, <task>/local for rendered templates and artifacts, or <task>/secrets"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("allocation_id",
+			mcp.Required(),
+			mcp.Description("The ID of the allocation"),
+		),
+		mcp.WithString("path",
+			mcp.Description("The directory to list, relative to the allocation directory (default: /)"),
+		),
+	)
+	s.AddTool(allocFSListTool, AllocFSListHandler(nomadClient, logger))
+
+	allocFSStatTool := mcp.NewTool("alloc_fs_stat",
+		mcp.WithDescription("Get the size, mode, modification time and content type of a file in an allocation's filesystem"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("allocation_id",
+			mcp.Required(),
+			mcp.Description("The ID of the allocation"),
+		),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("The file, relative to the allocation directory, e.g. web/local/config.yml"),
+		),
+	)
+	s.AddTool(allocFSStatTool, AllocFSStatHandler(nomadClient, logger))
+
+	allocFSCatTool := mcp.NewTool("alloc_fs_cat",
+		mcp.WithDescription("Read a file from an allocation's filesystem. Text is returned as is, binary files as a base64 resource. Large files are read in windows: pass the reported next offset to continue"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("allocation_id",
+			mcp.Required(),
+			mcp.Description("The ID of the allocation"),
+		),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("The file, relative to the allocation directory, e.g. web/local/config.yml"),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Byte offset to start reading at (default: 0)"),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description("Maximum number of bytes to read (default: 65536, max: 1048576)"),
+		),
+	)
+	s.AddTool(allocFSCatTool, AllocFSCatHandler(nomadClient, logger))
+}
+
+// allocFileArguments returns the allocation_id and path arguments shared by the fs tools.
+func allocFileArguments(arguments map[string]interface{}, pathRequired bool) (string, string, error) {
+	allocationID, _ := arguments["allocation_id"].(string)
+	if allocationID == "" {
+		return "", "", fmt.Errorf("allocation_id is required")
+	}
+	path, _ := arguments["path"].(string)
+	if path == "" {
+		if pathRequired {
+			return "", "", fmt.Errorf("path is required")
+		}
+		path = "/"
+	}
+	return allocationID, path, nil
+}
+
+// AllocFSListHandler returns a handler for listing a directory of an allocation
+func AllocFSListHandler(client utils.AllocFSAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		allocationID, path, err := allocFileArguments(arguments, false)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		files, err := client.ListAllocationFiles(ctx, allocationID, path)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing allocation files: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list allocation files", err), nil
+		}
+
+		filesJSON, err := json.MarshalIndent(files, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format allocation files", err), nil
+		}
+
+		return mcp.NewToolResultText(string(filesJSON)), nil
+	}
+}
+
+// AllocFSStatHandler returns a handler for describing a file of an allocation
+func AllocFSStatHandler(client utils.AllocFSAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		allocationID, path, err := allocFileArguments(arguments, true)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		file, err := client.StatAllocationFile(ctx, allocationID, path)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting allocation file info: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to stat allocation file", err), nil
+		}
+
+		fileJSON, err := json.MarshalIndent(file, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format allocation file info", err), nil
+		}
+
+		return mcp.NewToolResultText(string(fileJSON)), nil
+	}
+}
+
+// AllocFSCatHandler returns a handler for reading a file of an allocation
+func AllocFSCatHandler(client utils.AllocFSAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		allocationID, path, err := allocFileArguments(arguments, true)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var offset int64
+		if o, ok := arguments["offset"].(float64); ok {
+			if o < 0 {
+				return mcp.NewToolResultError("offset must not be negative"), nil
+			}
+			offset = int64(o)
+		}
+		maxBytes := int64(defaultAllocFileBytes)
+		if m, ok := arguments["max_bytes"].(float64); ok {
+			if m < 1 {
+				return mcp.NewToolResultError("max_bytes must be at least 1"), nil
+			}
+			maxBytes = min(int64(m), maxAllocFileBytes)
+		}
+
+		file, err := client.StatAllocationFile(ctx, allocationID, path)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting allocation file info: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to stat allocation file", err), nil
+		}
+		if file.IsDir {
+			return mcp.NewToolResultError(fmt.Sprintf("%s is a directory; use alloc_fs_list", path)), nil
+		}
+		if offset >= file.Size {
+			if offset > 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("offset %d is past the end of %s (%d bytes)", offset, path, file.Size)), nil
+			}
+			return mcp.NewToolResultText(""), nil
+		}
+
+		// Small files are read whole (client/fs/cat), larger ones one window at a time
+		var limit int64
+		if offset > 0 || file.Size > maxBytes {
+			limit = min(maxBytes, file.Size-offset)
+		}
+		content, err := client.ReadAllocationFile(ctx, allocationID, path, offset, limit)
+		if err != nil {
+			requestLogf(ctx, logger, "Error reading allocation file: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to read allocation file", err), nil
+		}
+
+		var result *mcp.CallToolResult
+		if utf8.Valid(content) {
+			result = mcp.NewToolResultText(string(content))
+		} else {
+			mimeType := file.ContentType
+			if mimeType == "" {
+				mimeType = "application/octet-stream"
+			}
+			result = mcp.NewToolResultResource(fmt.Sprintf("%s (%d bytes, binary)", path, len(content)), mcp.BlobResourceContents{
+				URI:      fmt.Sprintf("nomad://allocation/%s/fs/%s", allocationID, path),
+				MIMEType: mimeType,
+				Blob:     base64.StdEncoding.EncodeToString(content),
+			})
+		}
+
+		switch end := offset + int64(len(content)); {
+		case end < file.Size:
+			result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("Showing bytes %d-%d of %d; pass offset=%d to continue", offset, end, file.Size, end)))
+		case offset > 0:
+			result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("Showing bytes %d-%d of %d (end of file)", offset, end, file.Size)))
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,318 @@
+// File: tools/alloc_placement.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// PlacementCandidate is a node the scheduler scored for an allocation
+type PlacementCandidate struct {
+	Rank      int                `json:"Rank"`
+	NodeID    string             `json:"NodeID"`
+	NodeName  string             `json:"NodeName,omitempty"`
+	Chosen    bool               `json:"Chosen,omitempty"`
+	NormScore float64            `json:"NormScore"`
+	Scores    map[string]float64 `json:"Scores"`
+}
+
+// AllocationPlacementExplanation is the result of explain_allocation_placement: the
+// scheduler's metrics for an allocation and why it landed on its node
+type AllocationPlacementExplanation struct {
+	AllocationID       string               `json:"AllocationID"`
+	Name               string               `json:"Name"`
+	JobID              string               `json:"JobID"`
+	Namespace          string               `json:"Namespace"`
+	TaskGroup          string               `json:"TaskGroup"`
+	EvalID             string               `json:"EvalID"`
+	NodeID             string               `json:"NodeID"`
+	NodeName           string               `json:"NodeName"`
+	NodesInPool        int                  `json:"NodesInPool"`
+	NodesAvailable     map[string]int       `json:"NodesAvailable,omitempty"`
+	NodesEvaluated     int                  `json:"NodesEvaluated"`
+	NodesFiltered      int                  `json:"NodesFiltered"`
+	ClassFiltered      map[string]int       `json:"ClassFiltered,omitempty"`
+	ConstraintFiltered map[string]int       `json:"ConstraintFiltered,omitempty"`
+	NodesExhausted     int                  `json:"NodesExhausted"`
+	ClassExhausted     map[string]int       `json:"ClassExhausted,omitempty"`
+	DimensionExhausted map[string]int       `json:"DimensionExhausted,omitempty"`
+	QuotaExhausted     []string             `json:"QuotaExhausted,omitempty"`
+	AllocationTime     string               `json:"AllocationTime"`
+	Candidates         []PlacementCandidate `json:"Candidates"`
+	Explanation        []string             `json:"Explanation"`
+}
+
+// RegisterAllocationPlacementTools registers the allocation placement explanation tool
+func RegisterAllocationPlacementTools(s *server.MCPServer, nomadClient utils.PlacementMetricsAPI, logger *log.Logger) {
+	explainAllocationPlacementTool := mcp.NewTool("explain_allocation_placement",
+		mcp.WithDescription("Explain why an allocation landed on its node from the scheduler's placement metrics, like nomad alloc status -verbose: how many nodes were evaluated, filtered by constraints or exhausted, and the per-node scores (binpack, anti-affinity, affinity, spread, reschedule penalty) of the best candidates"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("allocation_id",
+			mcp.Required(),
+			mcp.Description("The ID of the allocation"),
+		),
+	)
+	s.AddTool(explainAllocationPlacementTool, ExplainAllocationPlacementHandler(nomadClient, logger))
+}
+
+// ExplainAllocationPlacementHandler returns a handler explaining an allocation's placement
+func ExplainAllocationPlacementHandler(client utils.PlacementMetricsAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		allocID, _ := arguments["allocation_id"].(string)
+		if allocID == "" {
+			return mcp.NewToolResultError("allocation_id is required"), nil
+		}
+
+		placement, err := client.GetAllocationMetrics(ctx, allocID)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting allocation: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get allocation", err), nil
+		}
+		if placement.Metrics == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("allocation %s has no placement metrics", allocID)), nil
+		}
+
+		// Node names only label the candidates, so a failed lookup leaves them out.
+		nodeNames := map[string]string{}
+		if nodes, err := client.ListNodes(ctx, ""); err != nil {
+			requestLogf(ctx, logger, "Error listing nodes: %v", err)
+		} else {
+			for _, node := range nodes {
+				nodeNames[node.ID] = node.Name
+			}
+		}
+
+		explanation := explainAllocationPlacement(placement, nodeNames)
+		explanationJSON, err := json.MarshalIndent(explanation, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format placement explanation", err), nil
+		}
+
+		return mcp.NewToolResultText(string(explanationJSON)), nil
+	}
+}
+
+// explainAllocationPlacement ranks the scored candidates and describes the placement.
+func explainAllocationPlacement(placement types.AllocationPlacement, nodeNames map[string]string) AllocationPlacementExplanation {
+	metrics := placement.Metrics
+	explanation := AllocationPlacementExplanation{
+		AllocationID:       placement.ID,
+		Name:               placement.Name,
+		JobID:              placement.JobID,
+		Namespace:          placement.Namespace,
+		TaskGroup:          placement.TaskGroup,
+		EvalID:             placement.EvalID,
+		NodeID:             placement.NodeID,
+		NodeName:           placement.NodeName,
+		NodesInPool:        metrics.NodesInPool,
+		NodesAvailable:     metrics.NodesAvailable,
+		NodesEvaluated:     metrics.NodesEvaluated,
+		NodesFiltered:      metrics.NodesFiltered,
+		ClassFiltered:      metrics.ClassFiltered,
+		ConstraintFiltered: metrics.ConstraintFiltered,
+		NodesExhausted:     metrics.NodesExhausted,
+		ClassExhausted:     metrics.ClassExhausted,
+		DimensionExhausted: metrics.DimensionExhausted,
+		QuotaExhausted:     metrics.QuotaExhausted,
+		AllocationTime:     metrics.AllocationTime.String(),
+		Candidates:         []PlacementCandidate{},
+	}
+	if explanation.NodeName == "" {
+		explanation.NodeName = nodeNames[placement.NodeID]
+	}
+
+	scored := append([]types.NodeScoreMeta(nil), metrics.ScoreMetaData...)
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].NormScore > scored[j].NormScore })
+	var chosen *PlacementCandidate
+	for i, meta := range scored {
+		explanation.Candidates = append(explanation.Candidates, PlacementCandidate{
+			Rank:      i + 1,
+			NodeID:    meta.NodeID,
+			NodeName:  nodeNames[meta.NodeID],
+			Chosen:    meta.NodeID == placement.NodeID,
+			NormScore: roundScore(meta.NormScore),
+			Scores:    roundScores(meta.Scores),
+		})
+	}
+	for i := range explanation.Candidates {
+		if explanation.Candidates[i].Chosen {
+			chosen = &explanation.Candidates[i]
+		}
+	}
+
+	explanation.Explanation = placementNarrative(explanation, chosen)
+	return explanation
+}
+
+// placementNarrative turns the metrics into sentences, from the nodes considered down to the
+// scores that decided between the best candidates.
+func placementNarrative(e AllocationPlacementExplanation, chosen *PlacementCandidate) []string {
+	node := e.NodeName
+	if node == "" {
+		node = e.NodeID
+	}
+
+	lines := []string{fmt.Sprintf("The scheduler evaluated %d of the %d nodes in the node pool%s in %s.", e.NodesEvaluated, e.NodesInPool, datacenterCounts(e.NodesAvailable), e.AllocationTime)}
+	if e.NodesFiltered > 0 {
+		lines = append(lines, fmt.Sprintf("%d nodes were filtered out%s.", e.NodesFiltered, countsSuffix("by", e.ConstraintFiltered, e.ClassFiltered)))
+	}
+	if e.NodesExhausted > 0 {
+		lines = append(lines, fmt.Sprintf("%d nodes passed the filters but lacked resources%s.", e.NodesExhausted, countsSuffix("on", e.DimensionExhausted)))
+	}
+	if len(e.QuotaExhausted) > 0 {
+		lines = append(lines, fmt.Sprintf("Quota limits were reached: %s.", strings.Join(e.QuotaExhausted, ", ")))
+	}
+
+	switch {
+	case len(e.Candidates) == 0:
+		lines = append(lines, fmt.Sprintf("No node scores were recorded; the allocation was placed on %s.", node))
+	case chosen == nil:
+		lines = append(lines, fmt.Sprintf("The allocation was placed on %s, which is not among the %d best scored candidates Nomad records; it may have been placed in place by a job update, or the candidate list was truncated.", node, len(e.Candidates)))
+	default:
+		lines = append(lines, fmt.Sprintf("%s ranked %d of %d scored candidates with a final score of %g (%s).", node, chosen.Rank, len(e.Candidates), chosen.NormScore, describeScores(chosen.Scores)))
+		if runnerUp := nextCandidate(e.Candidates, chosen); runnerUp != nil {
+			name := runnerUp.NodeName
+			if name == "" {
+				name = runnerUp.NodeID
+			}
+			lines = append(lines, fmt.Sprintf("Compared with %s (%g): %s.", name, runnerUp.NormScore, scoreDifferences(chosen.Scores, runnerUp.Scores)))
+		}
+	}
+	return lines
+}
+
+// nextCandidate returns the best candidate other than chosen.
+func nextCandidate(candidates []PlacementCandidate, chosen *PlacementCandidate) *PlacementCandidate {
+	for i := range candidates {
+		if candidates[i].NodeID != chosen.NodeID {
+			return &candidates[i]
+		}
+	}
+	return nil
+}
+
+// datacenterCounts describes the available nodes per datacenter.
+func datacenterCounts(available map[string]int) string {
+	if len(available) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (available per datacenter: %s)", joinCounts(available))
+}
+
+// countsSuffix describes the reasons nodes were filtered or exhausted.
+func countsSuffix(preposition string, counts ...map[string]int) string {
+	merged := map[string]int{}
+	for _, c := range counts {
+		for reason, n := range c {
+			merged[reason] += n
+		}
+	}
+	if len(merged) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" %s %s", preposition, joinCounts(merged))
+}
+
+// joinCounts formats counts as "key=n" pairs, the largest first.
+func joinCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%d", key, counts[key]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeScores lists the scoring components of a candidate, strongest first.
+func describeScores(scores map[string]float64) string {
+	if len(scores) == 0 {
+		return "no component scores"
+	}
+	names := sortedScoreNames(scores, func(a, b string) bool { return math.Abs(scores[a]) > math.Abs(scores[b]) })
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s %g", name, scores[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// scoreDifferences lists how the chosen node's component scores differ from another
+// candidate's, the largest difference first.
+func scoreDifferences(chosen, other map[string]float64) string {
+	diffs := map[string]float64{}
+	for name, score := range chosen {
+		diffs[name] = score - other[name]
+	}
+	for name, score := range other {
+		if _, ok := chosen[name]; !ok {
+			diffs[name] = -score
+		}
+	}
+	names := sortedScoreNames(diffs, func(a, b string) bool { return math.Abs(diffs[a]) > math.Abs(diffs[b]) })
+	parts := []string{}
+	for _, name := range names {
+		if diff := roundScore(diffs[name]); diff != 0 {
+			parts = append(parts, fmt.Sprintf("%s %+g", name, diff))
+		}
+	}
+	if len(parts) == 0 {
+		return "the component scores are equal"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sortedScoreNames returns the names of scores ordered by less, then by name.
+func sortedScoreNames(scores map[string]float64, less func(a, b string) bool) []string {
+	names := make([]string, 0, len(scores))
+	for name := range scores {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if less(names[i], names[j]) {
+			return true
+		}
+		if less(names[j], names[i]) {
+			return false
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// roundScore rounds a score to three decimals, as nomad alloc status prints them.
+func roundScore(score float64) float64 {
+	return math.Round(score*1000) / 1000
+}
+
+// roundScores rounds every component score.
+func roundScores(scores map[string]float64) map[string]float64 {
+	rounded := make(map[string]float64, len(scores))
+	for name, score := range scores {
+		rounded[name] = roundScore(score)
+	}
+	return rounded
+}
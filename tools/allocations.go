@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
+	"slices"
 	"strings"
 
+	"github.com/kocierik/mcp-nomad/types"
 	"github.com/kocierik/mcp-nomad/utils"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -17,22 +20,30 @@ func RegisterAllocationTools(s *server.MCPServer, nomadClient utils.AllocationAP
 	// List allocations tool
 	listAllocationsTool := mcp.NewTool("list_allocations",
 		mcp.WithDescription("List all allocations in Nomad"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("namespace",
 			mcp.Description("The namespace to list allocations from (default: default)"),
 		),
 		mcp.WithString("job_id",
 			mcp.Description("If set, list allocations via GET /v1/job/:job_id/allocations (namespace respected); otherwise GET /v1/allocations"),
 		),
+		mcp.WithString("client_status",
+			mcp.Description("Only return allocations with this client status, e.g. failed to report recent failures"),
+			mcp.Enum("pending", "running", "complete", "failed", "lost", "unknown"),
+		),
+		sinceOption("allocations created or updated"),
 	)
 	s.AddTool(listAllocationsTool, ListAllocationsHandler(nomadClient, logger))
 
 	// Get allocation tool
 	getAllocationTool := mcp.NewTool("get_allocation",
 		mcp.WithDescription("Get allocation details by ID"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("allocation_id",
 			mcp.Required(),
 			mcp.Description("The ID of the allocation to retrieve"),
 		),
+		fieldsOption(),
 	)
 	s.AddTool(getAllocationTool, GetAllocationHandler(nomadClient, logger))
 
@@ -45,6 +56,54 @@ func RegisterAllocationTools(s *server.MCPServer, nomadClient utils.AllocationAP
 		),
 	)
 	s.AddTool(stopAllocationTool, StopAllocationHandler(nomadClient, logger))
+
+	// Restart job tool
+	restartJobTool := mcp.NewTool("restart_job",
+		mcp.WithDescription("Restart the tasks of a job's running allocations in place, like nomad job restart. Allocations are restarted in batches of batch_size, waiting batch_wait between batches; a long rolling restart is bounded by the server's write timeout"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job to restart"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithString("task_group",
+			mcp.Description("Only restart allocations of this task group"),
+		),
+		mcp.WithString("task",
+			mcp.Description("Only restart this task; allocations without it are skipped (default: all tasks)"),
+		),
+		mcp.WithNumber("batch_size",
+			mcp.Description("Number of allocations restarted at a time (default: 1)"),
+		),
+		mcp.WithString("batch_wait",
+			mcp.Description("How long to wait between batches, e.g. 30s (default: no wait)"),
+		),
+	)
+	s.AddTool(restartJobTool, RestartJobHandler(nomadClient, logger))
+
+	// Exec allocation tool
+	execAllocationTool := mcp.NewTool("exec_allocation",
+		mcp.WithDescription("Run a command inside a running task of an allocation (like nomad alloc exec, without a TTY) and return its stdout, stderr and exit code. The command runs until it exits or the call times out"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("allocation_id",
+			mcp.Required(),
+			mcp.Description("The ID of the allocation to run the command in"),
+		),
+		mcp.WithArray("command",
+			mcp.Required(),
+			mcp.Description("The command and its arguments, e.g. [\"cat\", \"/local/config.yml\"]; use [\"/bin/sh\", \"-c\", \"...\"] for shell syntax"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("task",
+			mcp.Description("The task to run the command in (default: the allocation's only task)"),
+		),
+		mcp.WithString("stdin",
+			mcp.Description("Data written to the command's standard input before it is closed"),
+		),
+	)
+	s.AddTool(execAllocationTool, ExecAllocationHandler(nomadClient, logger))
 }
 
 // ListAllocationsHandler returns a handler for listing allocations
@@ -60,13 +119,32 @@ func ListAllocationsHandler(client utils.AllocationAPI, logger *log.Logger) func
 		if j, ok := arguments["job_id"].(string); ok {
 			jobID = strings.TrimSpace(j)
 		}
+		clientStatus, _ := arguments["client_status"].(string)
+		cutoff, err := sinceArgument(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 
 		allocations, err := client.ListAllocations(ctx, namespace, jobID)
 		if err != nil {
-			logger.Printf("Error listing allocations: %v", err)
+			requestLogf(ctx, logger, "Error listing allocations: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to list allocations", err), nil
 		}
 
+		if clientStatus != "" || !cutoff.IsZero() {
+			filtered := []types.Allocation{}
+			for _, alloc := range allocations {
+				if clientStatus != "" && alloc.ClientStatus != clientStatus {
+					continue
+				}
+				if !cutoff.IsZero() && alloc.ModifyTime < cutoff.UnixNano() {
+					continue
+				}
+				filtered = append(filtered, alloc)
+			}
+			allocations = filtered
+		}
+
 		allocationsJSON, err := json.MarshalIndent(allocations, "", "  ")
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Failed to format allocations", err), nil
@@ -89,13 +167,23 @@ func GetAllocationHandler(client utils.AllocationAPI, logger *log.Logger) func(c
 			return mcp.NewToolResultError("allocation_id is required"), nil
 		}
 
+		fields, err := fieldsArgument(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		allocation, err := client.GetAllocation(ctx, allocID)
 		if err != nil {
-			logger.Printf("Error getting allocation: %v", err)
+			requestLogf(ctx, logger, "Error getting allocation: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get allocation", err), nil
 		}
 
-		allocationJSON, err := json.MarshalIndent(allocation, "", "  ")
+		projected, err := projectFields(allocation, fields)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to project fields", err), nil
+		}
+
+		allocationJSON, err := json.MarshalIndent(projected, "", "  ")
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Failed to format allocation", err), nil
 		}
@@ -119,10 +207,73 @@ func StopAllocationHandler(client utils.AllocationAPI, logger *log.Logger) func(
 
 		err := client.StopAllocation(ctx, allocationID)
 		if err != nil {
-			logger.Printf("Error stopping allocation: %v", err)
+			requestLogf(ctx, logger, "Error stopping allocation: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to stop allocation", err), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("Allocation %s stopped successfully", allocationID)), nil
 	}
 }
+
+// maxExecOutputBytes caps what exec_allocation keeps of stdout and stderr each.
+const maxExecOutputBytes = 1 << 20
+
+// ExecAllocationHandler returns a handler for running a command inside an allocation's task
+func ExecAllocationHandler(client utils.AllocationAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		allocationID, ok := arguments["allocation_id"].(string)
+		if !ok || allocationID == "" {
+			return mcp.NewToolResultError("allocation_id is required"), nil
+		}
+		command, err := stringListArgument(arguments, "command")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if len(command) == 0 {
+			return mcp.NewToolResultError("command is required"), nil
+		}
+		stdin, _ := arguments["stdin"].(string)
+
+		task, _ := arguments["task"].(string)
+		if task == "" {
+			alloc, err := client.GetAllocation(ctx, allocationID)
+			if err != nil {
+				requestLogf(ctx, logger, "Error getting allocation: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to get allocation", err), nil
+			}
+			if len(alloc.TaskStates) == 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("task is required: allocation %s has no task states yet", allocationID)), nil
+			}
+			if len(alloc.TaskStates) > 1 {
+				return mcp.NewToolResultError(fmt.Sprintf("task is required: allocation %s has tasks %s", allocationID, strings.Join(slices.Sorted(maps.Keys(alloc.TaskStates)), ", "))), nil
+			}
+			for name := range alloc.TaskStates {
+				task = name
+			}
+		}
+
+		result, err := client.ExecAllocation(ctx, types.ExecRequest{
+			AllocID:        allocationID,
+			Task:           task,
+			Command:        command,
+			Stdin:          stdin,
+			MaxOutputBytes: maxExecOutputBytes,
+		})
+		if err != nil {
+			requestLogf(ctx, logger, "Error executing command in allocation: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to execute command", err), nil
+		}
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format command result", err), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
@@ -0,0 +1,284 @@
+// File: tools/artifacts.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// artifactCheckTimeout bounds each artifact reachability probe.
+const artifactCheckTimeout = 10 * time.Second
+
+// maxArtifactRedirects is how many redirects a probe follows, like net/http's default.
+const maxArtifactRedirects = 10
+
+// errArtifactRedirectBlocked stops a probe redirected to a host outside the allowlist.
+var errArtifactRedirectBlocked = errors.New("redirect to a host outside the artifact allowlist")
+
+// errArtifactAddressBlocked stops a probe to an internal address that is not allowlisted.
+var errArtifactAddressBlocked = errors.New("internal address not in the artifact allowlist")
+
+// artifactHTTPClient returns the client performing the reachability probes. It never sends
+// the Nomad token and never uses a proxy. Every redirect is checked against allowedHosts so
+// an allowed host cannot send the probe elsewhere, and every connection is checked against
+// the address it actually dials, so a name that resolves to an internal address on a later
+// lookup (DNS rebinding) is still refused.
+func artifactHTTPClient(allowedHosts []string) *http.Client {
+	dialer := &net.Dialer{Timeout: artifactCheckTimeout}
+	return &http.Client{
+		Timeout: artifactCheckTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return nil, err
+				}
+				checked := *dialer
+				checked.Control = func(_, resolved string, _ syscall.RawConn) error {
+					ip, _, err := net.SplitHostPort(resolved)
+					if err != nil {
+						return err
+					}
+					return artifactAddressAllowed(host, net.ParseIP(ip), allowedHosts)
+				}
+				return checked.DialContext(ctx, network, address)
+			},
+			DisableKeepAlives:   true,
+			TLSHandshakeTimeout: artifactCheckTimeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxArtifactRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxArtifactRedirects)
+			}
+			if !artifactHostAllowed(req.URL.Hostname(), allowedHosts) {
+				return fmt.Errorf("%w: %s", errArtifactRedirectBlocked, req.URL.Hostname())
+			}
+			return checkArtifactAddresses(req.Context(), req.URL.Hostname(), allowedHosts)
+		},
+	}
+}
+
+// getterQueryParams are go-getter options that are stripped before the source is downloaded.
+var getterQueryParams = []string{"checksum", "archive", "filename"}
+
+// ArtifactCheck is the reachability result for one artifact stanza
+type ArtifactCheck struct {
+	Group      string `json:"Group"`
+	Task       string `json:"Task"`
+	Source     string `json:"Source"`
+	URL        string `json:"URL,omitempty"`
+	Status     string `json:"Status"` // ok, unreachable, blocked or skipped
+	HTTPStatus int    `json:"HTTPStatus,omitempty"`
+	Error      string `json:"Error,omitempty"`
+}
+
+// RegisterArtifactTools registers artifact-related tools. allowedHosts limits which hosts
+// check_job_artifacts may contact; an empty list allows any host with a public address.
+// Loopback, private and link-local addresses are only contacted when listed.
+func RegisterArtifactTools(s *server.MCPServer, nomadClient utils.JobAPI, allowedHosts []string, logger *log.Logger) {
+	// Check job artifacts tool
+	checkJobArtifactsTool := mcp.NewTool("check_job_artifacts",
+		mcp.WithDescription("Extract artifact stanzas from a job spec and verify their HTTP(S) sources resolve (HEAD request) before the job is submitted"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_spec",
+			mcp.Required(),
+			mcp.Description("The job specification in HCL or JSON format"),
+		),
+	)
+	s.AddTool(checkJobArtifactsTool, CheckJobArtifactsHandler(nomadClient, allowedHosts, logger))
+}
+
+// CheckJobArtifactsHandler returns a handler for checking artifact reachability
+func CheckJobArtifactsHandler(client utils.JobAPI, allowedHosts []string, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobSpec, ok := arguments["job_spec"].(string)
+		if !ok || jobSpec == "" {
+			return mcp.NewToolResultError("job_spec is required"), nil
+		}
+
+		job, err := decodeJobSpec(ctx, client, jobSpec)
+		if err != nil {
+			requestLogf(ctx, logger, "Error parsing job spec: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to parse job spec", err), nil
+		}
+
+		checks := []ArtifactCheck{}
+		for _, tg := range job.TaskGroups {
+			for _, task := range tg.Tasks {
+				for _, artifact := range task.Artifacts {
+					check := checkArtifact(ctx, artifact, allowedHosts)
+					check.Group = tg.Name
+					check.Task = task.Name
+					checks = append(checks, check)
+				}
+			}
+		}
+
+		checksJSON, err := json.MarshalIndent(checks, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format artifact checks", err), nil
+		}
+
+		return mcp.NewToolResultText(string(checksJSON)), nil
+	}
+}
+
+// checkArtifact probes one artifact source. Only http(s) sources are checked; other
+// go-getter schemes (git, s3, gcs, ...) are reported as skipped.
+func checkArtifact(ctx context.Context, artifact types.TaskArtifact, allowedHosts []string) ArtifactCheck {
+	check := ArtifactCheck{Source: artifact.GetterSource}
+
+	source := artifact.GetterSource
+	if forced, rest, ok := strings.Cut(source, "::"); ok {
+		if forced != "http" && forced != "https" {
+			check.Status = "skipped"
+			check.Error = fmt.Sprintf("getter %q is not checked", forced)
+			return check
+		}
+		source = rest
+	}
+
+	u, err := url.Parse(source)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		check.Status = "skipped"
+		check.Error = "only http and https sources are checked"
+		return check
+	}
+
+	query := u.Query()
+	for _, param := range getterQueryParams {
+		query.Del(param)
+	}
+	u.RawQuery = query.Encode()
+	check.URL = u.String()
+
+	if !artifactHostAllowed(u.Hostname(), allowedHosts) {
+		check.Status = "blocked"
+		check.Error = fmt.Sprintf("host %s is not in the artifact allowlist", u.Hostname())
+		return check
+	}
+
+	if err := checkArtifactAddresses(ctx, u.Hostname(), allowedHosts); err != nil {
+		check.Status = "blocked"
+		if !errors.Is(err, errArtifactAddressBlocked) {
+			check.Status = "unreachable"
+		}
+		check.Error = err.Error()
+		return check
+	}
+
+	status, err := probeArtifactURL(ctx, artifactHTTPClient(allowedHosts), check.URL, artifact.GetterHeaders)
+	check.HTTPStatus = status
+	switch {
+	case errors.Is(err, errArtifactRedirectBlocked), errors.Is(err, errArtifactAddressBlocked):
+		check.Status = "blocked"
+		check.HTTPStatus = 0
+		check.Error = err.Error()
+	case err != nil:
+		check.Status = "unreachable"
+		check.Error = err.Error()
+	case status >= 400:
+		check.Status = "unreachable"
+		check.Error = http.StatusText(status)
+	default:
+		check.Status = "ok"
+	}
+	return check
+}
+
+// probeArtifactURL sends a HEAD request, falling back to a one-byte ranged GET for
+// servers that do not implement HEAD.
+func probeArtifactURL(ctx context.Context, client *http.Client, target string, headers map[string]string) (int, error) {
+	status, err := doArtifactRequest(ctx, client, http.MethodHead, target, headers)
+	if err != nil || (status != http.StatusMethodNotAllowed && status != http.StatusNotImplemented) {
+		return status, err
+	}
+	return doArtifactRequest(ctx, client, http.MethodGet, target, headers)
+}
+
+func doArtifactRequest(ctx context.Context, client *http.Client, method, target string, headers map[string]string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// checkArtifactAddresses resolves host and refuses it when any of its addresses is internal
+// and not allowlisted.
+func checkArtifactAddresses(ctx context.Context, host string, allowedHosts []string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if err := artifactAddressAllowed(host, addr.IP, allowedHosts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// artifactAddressAllowed refuses a loopback, private, link-local (169.254.169.254 included)
+// or unspecified address unless host or the address itself is in allowedHosts.
+func artifactAddressAllowed(host string, ip net.IP, allowedHosts []string) error {
+	if ip == nil {
+		return fmt.Errorf("%w: %s has no IP address", errArtifactAddressBlocked, host)
+	}
+	internal := ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+	if !internal || artifactHostListed(host, allowedHosts) || artifactHostListed(ip.String(), allowedHosts) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s resolves to %s", errArtifactAddressBlocked, host, ip)
+}
+
+// artifactHostAllowed reports whether host passes the allowlist; an empty list allows any host.
+func artifactHostAllowed(host string, allowedHosts []string) bool {
+	return len(allowedHosts) == 0 || artifactHostListed(host, allowedHosts)
+}
+
+// artifactHostListed matches a host against exact entries or ".suffix" domain entries.
+func artifactHostListed(host string, allowedHosts []string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == "" {
+			continue
+		}
+		if host == allowed || (strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			return true
+		}
+	}
+	return false
+}
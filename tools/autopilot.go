@@ -0,0 +1,185 @@
+// File: tools/autopilot.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RegisterAutopilotTools registers the tools that read and tune autopilot
+func RegisterAutopilotTools(s *server.MCPServer, nomadClient utils.AutopilotAPI, logger *log.Logger) {
+	getAutopilotConfigurationTool := mcp.NewTool("get_autopilot_configuration",
+		mcp.WithDescription("Get the autopilot configuration: dead server cleanup, last contact threshold, max trailing logs, min quorum, server stabilization time and the Enterprise upgrade and redundancy zone settings"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+	s.AddTool(getAutopilotConfigurationTool, GetAutopilotConfigurationHandler(nomadClient, logger))
+
+	setAutopilotConfigurationTool := mcp.NewTool("set_autopilot_configuration",
+		mcp.WithDescription("Change autopilot settings. Settings that are not passed keep their current value; the update is a check-and-set against the configuration read first, so a concurrent change makes it fail instead of being overwritten"),
+		mcp.WithBoolean("cleanup_dead_servers",
+			mcp.Description("Remove dead servers from the Raft peer set when a new server joins"),
+		),
+		mcp.WithString("last_contact_threshold",
+			mcp.Description("How long a server may go without contact with the leader before it is unhealthy, e.g. 200ms"),
+		),
+		mcp.WithNumber("max_trailing_logs",
+			mcp.Description("How many Raft log entries a server may trail the leader by before it is unhealthy"),
+		),
+		mcp.WithNumber("min_quorum",
+			mcp.Description("Minimum number of servers before autopilot prunes dead servers"),
+		),
+		mcp.WithString("server_stabilization_time",
+			mcp.Description("How long a new server must be healthy before it is promoted to voter, e.g. 10s"),
+		),
+		mcp.WithBoolean("enable_redundancy_zones",
+			mcp.Description("Use redundancy zones (Nomad Enterprise)"),
+		),
+		mcp.WithBoolean("disable_upgrade_migration",
+			mcp.Description("Disable automated upgrade migrations (Nomad Enterprise)"),
+		),
+		mcp.WithBoolean("enable_custom_upgrades",
+			mcp.Description("Use the upgrade_version tag instead of the Nomad version for upgrade migrations (Nomad Enterprise)"),
+		),
+	)
+	s.AddTool(setAutopilotConfigurationTool, SetAutopilotConfigurationHandler(nomadClient, logger))
+
+	getAutopilotHealthTool := mcp.NewTool("get_autopilot_health",
+		mcp.WithDescription("Get autopilot's view of server health: overall health, failure tolerance and, per server, Serf status, voter status, last contact, last Raft index and how long it has been stable"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+	s.AddTool(getAutopilotHealthTool, GetAutopilotHealthHandler(nomadClient, logger))
+}
+
+// GetAutopilotConfigurationHandler returns a handler for reading the autopilot configuration
+func GetAutopilotConfigurationHandler(client utils.AutopilotAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		config, err := client.GetAutopilotConfiguration(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting autopilot configuration: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get autopilot configuration", err), nil
+		}
+
+		configJSON, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format autopilot configuration", err), nil
+		}
+
+		return mcp.NewToolResultText(string(configJSON)), nil
+	}
+}
+
+// applyAutopilotArguments merges the settings passed to set_autopilot_configuration into
+// config and reports whether any was passed.
+func applyAutopilotArguments(config *types.AutopilotConfiguration, arguments map[string]interface{}) (bool, error) {
+	changed := false
+	for name, field := range map[string]*bool{
+		"cleanup_dead_servers":      &config.CleanupDeadServers,
+		"enable_redundancy_zones":   &config.EnableRedundancyZones,
+		"disable_upgrade_migration": &config.DisableUpgradeMigration,
+		"enable_custom_upgrades":    &config.EnableCustomUpgrades,
+	} {
+		if value, ok := arguments[name].(bool); ok {
+			*field = value
+			changed = true
+		}
+	}
+	for name, field := range map[string]*string{
+		"last_contact_threshold":    &config.LastContactThreshold,
+		"server_stabilization_time": &config.ServerStabilizationTime,
+	} {
+		if value, ok := arguments[name].(string); ok && value != "" {
+			if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+				return false, fmt.Errorf("%s must be a positive duration such as 200ms or 10s", name)
+			}
+			*field = value
+			changed = true
+		}
+	}
+	if value, ok := arguments["max_trailing_logs"].(float64); ok {
+		if value < 1 {
+			return false, fmt.Errorf("max_trailing_logs must be at least 1")
+		}
+		config.MaxTrailingLogs = uint64(value)
+		changed = true
+	}
+	if value, ok := arguments["min_quorum"].(float64); ok {
+		if value < 0 {
+			return false, fmt.Errorf("min_quorum must not be negative")
+		}
+		config.MinQuorum = uint(value)
+		changed = true
+	}
+	return changed, nil
+}
+
+// SetAutopilotConfigurationHandler returns a handler for changing autopilot settings
+func SetAutopilotConfigurationHandler(client utils.AutopilotAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		config, err := client.GetAutopilotConfiguration(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting autopilot configuration: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get autopilot configuration", err), nil
+		}
+
+		changed, err := applyAutopilotArguments(&config, arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if !changed {
+			return mcp.NewToolResultError("at least one autopilot setting is required"), nil
+		}
+
+		applied, err := client.SetAutopilotConfiguration(ctx, config, true)
+		if err != nil {
+			requestLogf(ctx, logger, "Error setting autopilot configuration: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to set autopilot configuration", err), nil
+		}
+		if !applied {
+			return mcp.NewToolResultError(fmt.Sprintf("Autopilot configuration changed since index %d; read it again and retry", config.ModifyIndex)), nil
+		}
+
+		updated, err := client.GetAutopilotConfiguration(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting autopilot configuration: %v", err)
+			return mcp.NewToolResultText("Autopilot configuration updated successfully"), nil
+		}
+
+		configJSON, err := json.MarshalIndent(updated, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format autopilot configuration", err), nil
+		}
+
+		return mcp.NewToolResultText(string(configJSON)), nil
+	}
+}
+
+// GetAutopilotHealthHandler returns a handler for reading autopilot's server health
+func GetAutopilotHealthHandler(client utils.AutopilotAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		health, err := client.GetAutopilotHealth(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting autopilot health: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get autopilot health", err), nil
+		}
+
+		healthJSON, err := json.MarshalIndent(health, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format autopilot health", err), nil
+		}
+
+		return mcp.NewToolResultText(string(healthJSON)), nil
+	}
+}
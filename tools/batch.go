@@ -0,0 +1,563 @@
+// File: tools/batch.go
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultBatchHistoryWindow is the get_batch_job_history window when since is not set.
+const defaultBatchHistoryWindow = 7 * 24 * time.Hour
+
+// BatchJobHistory aggregates the finished runs of a batch job, or of the children of a
+// periodic or parameterized job, over a time window
+type BatchJobHistory struct {
+	JobID                 string   `json:"JobID"`
+	Namespace             string   `json:"Namespace"`
+	Kind                  string   `json:"Kind"` // batch, periodic or parameterized
+	Runs                  int      `json:"Runs"`
+	Completed             int      `json:"Completed"`
+	Failed                int      `json:"Failed"`
+	Lost                  int      `json:"Lost"`
+	SuccessRate           *float64 `json:"SuccessRate,omitempty"`
+	AverageRuntime        string   `json:"AverageRuntime,omitempty"`
+	AverageRuntimeSeconds float64  `json:"AverageRuntimeSeconds,omitempty"`
+	LastRunAt             string   `json:"LastRunAt,omitempty"`
+	LastStatus            string   `json:"LastStatus,omitempty"`
+	Errors                []string `json:"Errors,omitempty"`
+}
+
+// RegisterBatchJobTools registers tools for batch, periodic and dispatched jobs
+func RegisterBatchJobTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log.Logger) {
+	// Get batch job history tool
+	getBatchJobHistoryTool := mcp.NewTool("get_batch_job_history",
+		mcp.WithDescription("Aggregate the completed and failed allocations of batch jobs, including the children of periodic and parameterized jobs, over a time window and report the success rate and average runtime per job"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Description("Only report this batch job, or the periodic or parameterized job whose children should be aggregated"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace to scan, or * for all namespaces (default: default)"),
+		),
+		sinceOption("runs finished"),
+	)
+	s.AddTool(getBatchJobHistoryTool, GetBatchJobHistoryHandler(nomadClient, logger))
+
+	// List dispatched jobs tool
+	listDispatchedJobsTool := mcp.NewTool("list_dispatched_jobs",
+		mcp.WithDescription("List the dispatched children of a parameterized job with their status and submit time, newest first"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the parameterized job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithString("status",
+			mcp.Description("Only list children with this status"),
+			mcp.Enum("pending", "running", "dead", ""),
+		),
+		sinceOption("children dispatched"),
+	)
+	s.AddTool(listDispatchedJobsTool, ListDispatchedJobsHandler(nomadClient, logger))
+
+	// Purge dispatched jobs tool
+	purgeDispatchedJobsTool := mcp.NewTool("purge_dispatched_jobs",
+		mcp.WithDescription("Purge the completed (dead) dispatched children of a parameterized job that were dispatched before a threshold (dry-run by default)"),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the parameterized job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithString("older_than",
+			mcp.Description("Only purge children dispatched before this duration ago, e.g. 24h or 7d (default: 24h)"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Only report the children that would be purged (default: true)"),
+		),
+	)
+	s.AddTool(purgeDispatchedJobsTool, PurgeDispatchedJobsHandler(nomadClient, logger))
+
+	// Dispatch job tool
+	dispatchJobTool := mcp.NewTool("dispatch_job",
+		mcp.WithDescription("Dispatch a parameterized job, creating a child job with the given payload and metadata, and return the ID of the dispatched job and of its evaluation"),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the parameterized job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithString("payload",
+			mcp.Description("The payload passed to the dispatched job, at most 16 KiB"),
+		),
+		mcp.WithString("payload_encoding",
+			mcp.Description("How payload is encoded: plain text or base64 for binary data (default: plain)"),
+			mcp.Enum("plain", "base64"),
+		),
+		mcp.WithObject("meta",
+			mcp.Description("Metadata of the dispatched job as string key/value pairs; must include the job's required meta keys"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("idempotency_token",
+			mcp.Description("Skip the dispatch while a child dispatched with the same token exists, so a retried call does not run the job twice"),
+		),
+	)
+	s.AddTool(dispatchJobTool, DispatchJobHandler(nomadClient, logger))
+
+	// Force periodic job tool
+	forcePeriodicJobTool := mcp.NewTool("force_periodic_job",
+		mcp.WithDescription("Launch a periodic job now instead of waiting for its next scheduled time, even when prohibit_overlap would skip the launch, and return the evaluation of the launched child job"),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the periodic job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+	)
+	s.AddTool(forcePeriodicJobTool, ForcePeriodicJobHandler(nomadClient, logger))
+}
+
+// GetBatchJobHistoryHandler returns a handler for aggregating batch job runs
+func GetBatchJobHistoryHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		namespace := utils.EffectiveToolNamespace(arguments)
+		jobID, _ := arguments["job_id"].(string)
+
+		cutoff, err := sinceArgument(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if cutoff.IsZero() {
+			cutoff = time.Now().Add(-defaultBatchHistoryWindow)
+		}
+
+		stubs, err := client.ListJobs(ctx, namespace, "")
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing jobs: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list jobs", err), nil
+		}
+
+		// Children are reported under their periodic or parameterized parent.
+		histories := map[string]*BatchJobHistory{}
+		var members []types.JobSummary
+		var memberKeys []string
+		for _, stub := range stubs {
+			if stub.Type != "batch" {
+				continue
+			}
+			if stub.Namespace == "" {
+				stub.Namespace = namespace
+			}
+			rootID := stub.ID
+			if stub.ParentID != "" {
+				rootID = stub.ParentID
+			}
+			if jobID != "" && rootID != jobID {
+				continue
+			}
+
+			key := stub.Namespace + "/" + rootID
+			history, ok := histories[key]
+			if !ok {
+				history = &BatchJobHistory{JobID: rootID, Namespace: stub.Namespace, Kind: "batch"}
+				histories[key] = history
+			}
+			switch {
+			case stub.Periodic:
+				history.Kind = "periodic"
+			case stub.ParameterizedJob:
+				history.Kind = "parameterized"
+			case stub.ParentID != "" && history.Kind == "batch":
+				history.Kind = "periodic"
+				if strings.HasPrefix(stub.ID, rootID+"/dispatch-") {
+					history.Kind = "parameterized"
+				}
+			}
+
+			// Periodic and parameterized parents never run allocations themselves.
+			if stub.Periodic || stub.ParameterizedJob {
+				continue
+			}
+			members = append(members, stub)
+			memberKeys = append(memberKeys, key)
+		}
+		if jobID != "" && len(histories) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("no batch job %s found", jobID)), nil
+		}
+
+		allocs := make([][]types.Allocation, len(members))
+		errs := make([]error, len(members))
+		forEachConcurrently(len(members), func(i int) {
+			allocs[i], errs[i] = client.ListJobAllocations(ctx, members[i].ID, members[i].Namespace)
+		})
+
+		runtimes := map[string][]time.Duration{}
+		lastRuns := map[string]time.Time{}
+		for i, member := range members {
+			history := histories[memberKeys[i]]
+			if errs[i] != nil {
+				requestLogf(ctx, logger, "Error listing allocations for job %s: %v", member.ID, errs[i])
+				history.Errors = append(history.Errors, fmt.Sprintf("%s: %v", member.ID, errs[i]))
+				continue
+			}
+
+			ran := false
+			for _, alloc := range allocs[i] {
+				switch alloc.ClientStatus {
+				case "complete", "failed", "lost":
+				default:
+					continue
+				}
+				start, finish := allocationRunWindow(alloc)
+				if finish.Before(cutoff) {
+					continue
+				}
+
+				ran = true
+				switch alloc.ClientStatus {
+				case "complete":
+					history.Completed++
+				case "failed":
+					history.Failed++
+				case "lost":
+					history.Lost++
+				}
+				if !start.IsZero() && finish.After(start) {
+					runtimes[memberKeys[i]] = append(runtimes[memberKeys[i]], finish.Sub(start))
+				}
+				if finish.After(lastRuns[memberKeys[i]]) {
+					lastRuns[memberKeys[i]] = finish
+					history.LastStatus = alloc.ClientStatus
+				}
+			}
+			if ran {
+				history.Runs++
+			}
+		}
+
+		result := make([]BatchJobHistory, 0, len(histories))
+		for key, history := range histories {
+			if finished := history.Completed + history.Failed + history.Lost; finished > 0 {
+				rate := float64(history.Completed) / float64(finished)
+				history.SuccessRate = &rate
+			}
+			if durations := runtimes[key]; len(durations) > 0 {
+				var total time.Duration
+				for _, d := range durations {
+					total += d
+				}
+				average := total / time.Duration(len(durations))
+				history.AverageRuntime = average.Round(time.Second).String()
+				history.AverageRuntimeSeconds = average.Seconds()
+			}
+			if last, ok := lastRuns[key]; ok {
+				history.LastRunAt = last.UTC().Format(time.RFC3339)
+			}
+			result = append(result, *history)
+		}
+		sort.Slice(result, func(i, j int) bool {
+			if result[i].Namespace != result[j].Namespace {
+				return result[i].Namespace < result[j].Namespace
+			}
+			return result[i].JobID < result[j].JobID
+		})
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format batch job history", err), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// DispatchedJob is one child of a parameterized job
+type DispatchedJob struct {
+	ID         string `json:"ID"`
+	Namespace  string `json:"Namespace"`
+	Status     string `json:"Status"`
+	SubmitTime string `json:"SubmitTime,omitempty"`
+	Running    int    `json:"Running"`
+	Complete   int    `json:"Complete"`
+	Failed     int    `json:"Failed"`
+	Purged     bool   `json:"Purged,omitempty"`
+	Error      string `json:"Error,omitempty"`
+}
+
+// ListDispatchedJobsHandler returns a handler for listing the children of a parameterized job
+func ListDispatchedJobsHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+		status, _ := arguments["status"].(string)
+
+		cutoff, err := sinceArgument(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		children, err := listDispatchedJobs(ctx, client, jobID, namespace, status)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing dispatched jobs for %s: %v", jobID, err)
+			return mcp.NewToolResultErrorFromErr("Failed to list dispatched jobs", err), nil
+		}
+
+		result := []DispatchedJob{}
+		for _, child := range children {
+			if !cutoff.IsZero() && time.Unix(0, child.SubmitTime).Before(cutoff) {
+				continue
+			}
+			result = append(result, dispatchedJob(child, namespace))
+		}
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format dispatched jobs", err), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// PurgeDispatchedJobsHandler returns a handler for purging old dispatched children
+func PurgeDispatchedJobsHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		olderThan := 24 * time.Hour
+		if o, ok := arguments["older_than"].(string); ok && o != "" {
+			d, err := parseRelativeDuration(o)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("older_than must be a positive duration such as 24h or 7d, got %q", o)), nil
+			}
+			olderThan = d
+		}
+
+		dryRun := true
+		if d, ok := arguments["dry_run"].(bool); ok {
+			dryRun = d
+		}
+
+		children, err := listDispatchedJobs(ctx, client, jobID, namespace, "dead")
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing dispatched jobs for %s: %v", jobID, err)
+			return mcp.NewToolResultErrorFromErr("Failed to list dispatched jobs", err), nil
+		}
+
+		cutoff := time.Now().Add(-olderThan)
+		candidates := []DispatchedJob{}
+		for _, child := range children {
+			if child.SubmitTime == 0 || !time.Unix(0, child.SubmitTime).Before(cutoff) {
+				continue
+			}
+
+			candidate := dispatchedJob(child, namespace)
+			if !dryRun {
+				if _, err := client.StopJob(ctx, child.ID, candidate.Namespace, types.JobStopOptions{Purge: true}); err != nil {
+					requestLogf(ctx, logger, "Error purging dispatched job %s in namespace %s: %v", child.ID, candidate.Namespace, err)
+					candidate.Error = err.Error()
+				} else {
+					candidate.Purged = true
+				}
+			}
+			candidates = append(candidates, candidate)
+		}
+
+		result := map[string]interface{}{
+			"dry_run":    dryRun,
+			"job_id":     jobID,
+			"namespace":  namespace,
+			"older_than": olderThan.String(),
+			"jobs":       candidates,
+		}
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format result", err), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// DispatchJobHandler returns a handler for dispatching a parameterized job
+func DispatchJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		var opts types.JobDispatchOptions
+		if payload, _ := arguments["payload"].(string); payload != "" {
+			switch encoding, _ := arguments["payload_encoding"].(string); encoding {
+			case "", "plain":
+				opts.Payload = []byte(payload)
+			case "base64":
+				decoded, err := base64.StdEncoding.DecodeString(payload)
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("payload is not valid base64: %v", err)), nil
+				}
+				opts.Payload = decoded
+			default:
+				return mcp.NewToolResultError(fmt.Sprintf("payload_encoding must be plain or base64, got %q", encoding)), nil
+			}
+		}
+		if raw, ok := arguments["meta"].(map[string]interface{}); ok && len(raw) > 0 {
+			opts.Meta = make(map[string]string, len(raw))
+			for key, value := range raw {
+				text, ok := value.(string)
+				if !ok {
+					return mcp.NewToolResultError(fmt.Sprintf("meta.%s must be a string", key)), nil
+				}
+				opts.Meta[key] = text
+			}
+		}
+		opts.IdempotencyToken, _ = arguments["idempotency_token"].(string)
+
+		result, err := client.DispatchJob(ctx, jobID, namespace, opts)
+		if err != nil {
+			requestLogf(ctx, logger, "Error dispatching job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to dispatch job", err), nil
+		}
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format result", err), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// ForcePeriodicJobHandler returns a handler for launching a periodic job immediately
+func ForcePeriodicJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		result, err := client.ForceNewPeriodicInstance(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error forcing periodic job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to force periodic job", err), nil
+		}
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format result", err), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// listDispatchedJobs returns the dispatched children of a parameterized job, newest first.
+// The status filter is applied again here because not every Nomad version honors it.
+func listDispatchedJobs(ctx context.Context, client utils.JobAPI, jobID, namespace, status string) ([]types.JobSummary, error) {
+	stubs, err := client.ListJobs(ctx, namespace, status)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []types.JobSummary{}
+	for _, stub := range stubs {
+		if stub.ParentID != jobID || !strings.HasPrefix(stub.ID, jobID+"/dispatch-") {
+			continue
+		}
+		if status != "" && stub.Status != "" && stub.Status != status {
+			continue
+		}
+		children = append(children, stub)
+	}
+	sort.SliceStable(children, func(i, j int) bool { return children[i].SubmitTime > children[j].SubmitTime })
+	return children, nil
+}
+
+func dispatchedJob(stub types.JobSummary, namespace string) DispatchedJob {
+	child := DispatchedJob{ID: stub.ID, Namespace: stub.Namespace, Status: stub.Status}
+	if child.Namespace == "" {
+		child.Namespace = namespace
+	}
+	if stub.SubmitTime != 0 {
+		child.SubmitTime = time.Unix(0, stub.SubmitTime).UTC().Format(time.RFC3339)
+	}
+	for _, summary := range stub.Summary {
+		child.Running += summary.Running
+		child.Complete += summary.Complete
+		child.Failed += summary.Failed + summary.Lost
+	}
+	return child
+}
+
+// allocationRunWindow returns when an allocation's tasks started and finished. The
+// allocation create and modify times stand in when no task recorded them.
+func allocationRunWindow(alloc types.Allocation) (time.Time, time.Time) {
+	var start, finish time.Time
+	for _, state := range alloc.TaskStates {
+		if state.StartedAt != nil && !state.StartedAt.IsZero() && (start.IsZero() || state.StartedAt.Before(start)) {
+			start = *state.StartedAt
+		}
+		if state.FinishedAt != nil && state.FinishedAt.After(finish) {
+			finish = *state.FinishedAt
+		}
+	}
+	if start.IsZero() && alloc.CreateTime != 0 {
+		start = time.Unix(0, alloc.CreateTime)
+	}
+	if finish.IsZero() && alloc.ModifyTime != 0 {
+		finish = time.Unix(0, alloc.ModifyTime)
+	}
+	return start, finish
+}
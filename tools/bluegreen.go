@@ -0,0 +1,346 @@
+// File: tools/bluegreen.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// blueGreenPollInterval is how often deploy_blue_green checks the green job's health.
+	blueGreenPollInterval = 5 * time.Second
+	// blueGreenHeadroom is kept from the call's deadline for the steps after the health wait.
+	blueGreenHeadroom = 30 * time.Second
+	// blueGreenStopTimeout bounds stopping a job, which must happen even once the call has
+	// been cancelled.
+	blueGreenStopTimeout = 30 * time.Second
+)
+
+// serverManagedJobFields are set by Nomad on registered jobs and dropped from a clone.
+var serverManagedJobFields = []string{"Status", "StatusDescription", "Stable", "Version", "SubmitTime", "CreateIndex", "ModifyIndex", "JobModifyIndex", "Stop"}
+
+// BlueGreenReport is the checklist returned by deploy_blue_green
+type BlueGreenReport struct {
+	BlueJobID  string            `json:"BlueJobID"`
+	GreenJobID string            `json:"GreenJobID"`
+	Namespace  string            `json:"Namespace"`
+	Complete   bool              `json:"Complete"`
+	Steps      []MaintenanceStep `json:"Steps"`
+	Warnings   []string          `json:"Warnings,omitempty"`
+}
+
+func (r *BlueGreenReport) add(step, status, detail string) {
+	r.Steps = append(r.Steps, MaintenanceStep{Step: step, Status: status, Detail: detail})
+}
+
+// warn records the warnings Nomad returned for a registration, once each.
+func (r *BlueGreenReport) warn(warnings string) {
+	warnings = strings.TrimSpace(warnings)
+	if warnings == "" {
+		return
+	}
+	for _, seen := range r.Warnings {
+		if seen == warnings {
+			return
+		}
+	}
+	r.Warnings = append(r.Warnings, warnings)
+}
+
+// DeployBlueGreenHandler returns a handler that runs a blue/green cutover: register a copy of
+// the job under a second ID, wait for it to become healthy, move the live service tags to it
+// and stop the old copy
+func DeployBlueGreenHandler(client utils.JobAPI, submissions *JobSubmissionLocks, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		blueID, ok := arguments["job_id"].(string)
+		if !ok || blueID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		suffix := "-green"
+		if s, ok := arguments["suffix"].(string); ok && s != "" {
+			suffix = s
+		}
+		var liveTags []string
+		if tags, ok := arguments["live_tags"].(string); ok {
+			for _, tag := range strings.Split(tags, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					liveTags = append(liveTags, tag)
+				}
+			}
+		}
+		healthTimeout := 5 * time.Minute
+		if h, ok := arguments["health_timeout"].(string); ok && h != "" {
+			d, err := parseRelativeDuration(h)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("health_timeout: %v", err)), nil
+			}
+			healthTimeout = d
+		}
+		// Give up waiting before the call times out, so the green job is still rolled back.
+		if deadline, ok := ctx.Deadline(); ok {
+			if remaining := time.Until(deadline) - blueGreenHeadroom; remaining < healthTimeout {
+				requestLogf(ctx, logger, "Capping health_timeout of deploy_blue_green at %s to fit the call's timeout", max(remaining, 0).Round(time.Second))
+				healthTimeout = max(remaining, 0)
+			}
+		}
+		keepBlue, _ := arguments["keep_blue"].(bool)
+
+		// Alternate between the two IDs so repeated cutovers do not keep growing the suffix.
+		greenID := blueID + suffix
+		if trimmed := strings.TrimSuffix(blueID, suffix); trimmed != blueID && trimmed != "" {
+			greenID = trimmed
+		}
+		report := BlueGreenReport{BlueJobID: blueID, GreenJobID: greenID, Namespace: namespace, Steps: []MaintenanceStep{}}
+
+		blue, err := client.GetJobDefinition(ctx, blueID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
+		}
+
+		green := blue
+		if jobSpec, ok := arguments["job_spec"].(string); ok && jobSpec != "" {
+			green, err = client.ParseJobSpec(ctx, jobSpec)
+			if err != nil {
+				requestLogf(ctx, logger, "Error parsing job spec: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to parse job spec", err), nil
+			}
+		}
+		green, err = cloneJobDefinition(green)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to copy job", err), nil
+		}
+		green["ID"] = greenID
+		green["Namespace"] = namespace
+		// Keep the blue job's name so services rendered from NOMAD_JOB_NAME stay the same.
+		if name, ok := blue["Name"]; ok {
+			green["Name"] = name
+		}
+		setJobServiceTags(green, nil, liveTags)
+
+		result, err := registerJobDefinition(ctx, client, submissions, green, namespace)
+		report.warn(result.Warnings)
+		if err != nil {
+			requestLogf(ctx, logger, "Error registering green job: %v", err)
+			report.add("register green", "failed", err.Error())
+			return blueGreenResult(report, true)
+		}
+		report.add("register green", "done", fmt.Sprintf("registered %s with %d allocation(s) and without live tags", greenID, jobDefinitionCount(green)))
+
+		if err := waitForJobHealthy(ctx, client, greenID, namespace, result.JobModifyIndex, jobDefinitionCount(green), healthTimeout); err != nil {
+			report.add("wait for green health", "failed", err.Error())
+			if stopErr := stopBlueGreenJob(ctx, client, greenID, namespace); stopErr != nil {
+				requestLogf(ctx, logger, "Error stopping green job: %v", stopErr)
+				report.add("roll back green", "failed", stopErr.Error())
+			} else {
+				report.add("roll back green", "done", fmt.Sprintf("stopped %s; %s still serves traffic", greenID, blueID))
+			}
+			return blueGreenResult(report, true)
+		}
+		report.add("wait for green health", "done", fmt.Sprintf("%s is healthy", greenID))
+
+		if len(liveTags) == 0 {
+			report.add("promote green", "skipped", "no live_tags given; both jobs serve the same services until blue is stopped")
+			report.add("demote blue", "skipped", "no live_tags given")
+		} else {
+			setJobServiceTags(green, liveTags, nil)
+			promoted, err := registerJobDefinition(ctx, client, submissions, green, namespace)
+			report.warn(promoted.Warnings)
+			if err != nil {
+				requestLogf(ctx, logger, "Error promoting green job: %v", err)
+				report.add("promote green", "failed", err.Error())
+				return blueGreenResult(report, true)
+			}
+			report.add("promote green", "done", fmt.Sprintf("added %s to the services of %s", strings.Join(liveTags, ", "), greenID))
+
+			blueCopy, err := cloneJobDefinition(blue)
+			if err == nil {
+				setJobServiceTags(blueCopy, nil, liveTags)
+				var demoted types.JobRegisterResponse
+				demoted, err = registerJobDefinition(ctx, client, submissions, blueCopy, namespace)
+				report.warn(demoted.Warnings)
+			}
+			if err != nil {
+				requestLogf(ctx, logger, "Error demoting blue job: %v", err)
+				report.add("demote blue", "failed", err.Error())
+				return blueGreenResult(report, true)
+			}
+			report.add("demote blue", "done", fmt.Sprintf("removed %s from the services of %s", strings.Join(liveTags, ", "), blueID))
+		}
+
+		if keepBlue {
+			report.add("stop blue", "skipped", fmt.Sprintf("keep_blue is set; stop %s once the cutover is confirmed", blueID))
+		} else {
+			if err := stopBlueGreenJob(ctx, client, blueID, namespace); err != nil {
+				requestLogf(ctx, logger, "Error stopping blue job: %v", err)
+				report.add("stop blue", "failed", err.Error())
+				return blueGreenResult(report, true)
+			}
+			report.add("stop blue", "done", fmt.Sprintf("stopped %s; run deploy_blue_green on %s for the next release", blueID, greenID))
+		}
+		report.Complete = true
+
+		return blueGreenResult(report, false)
+	}
+}
+
+// stopBlueGreenJob stops a job even when ctx is already cancelled, e.g. by the call's
+// timeout, so a cutover is never left half done with both jobs running.
+func stopBlueGreenJob(ctx context.Context, client utils.JobAPI, jobID, namespace string) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), blueGreenStopTimeout)
+	defer cancel()
+	_, err := client.StopJob(ctx, jobID, namespace, types.JobStopOptions{})
+	return err
+}
+
+// cloneJobDefinition deep-copies a raw job and drops the fields Nomad manages.
+func cloneJobDefinition(job map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+	var clone map[string]interface{}
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+	for _, field := range serverManagedJobFields {
+		delete(clone, field)
+	}
+	return clone, nil
+}
+
+// registerJobDefinition registers a raw job with a check-and-set on its current index.
+func registerJobDefinition(ctx context.Context, client utils.JobAPI, submissions *JobSubmissionLocks, job map[string]interface{}, namespace string) (types.JobRegisterResponse, error) {
+	jobSpec, err := json.Marshal(job)
+	if err != nil {
+		return types.JobRegisterResponse{}, fmt.Errorf("error marshaling job: %v", err)
+	}
+	return runJobWithIndex(ctx, client, submissions, string(jobSpec), namespace, true, 0, false)
+}
+
+// setJobServiceTags adds and removes tags on every group and task service of a raw job.
+func setJobServiceTags(job map[string]interface{}, add, remove []string) {
+	update := func(services interface{}) {
+		list, _ := services.([]interface{})
+		for _, raw := range list {
+			service, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			existing, _ := service["Tags"].([]interface{})
+			tags := []interface{}{}
+			seen := map[string]bool{}
+			for _, t := range existing {
+				tag, _ := t.(string)
+				if !slices.Contains(remove, tag) && !seen[tag] {
+					tags = append(tags, t)
+					seen[tag] = true
+				}
+			}
+			for _, tag := range add {
+				if !seen[tag] {
+					tags = append(tags, tag)
+					seen[tag] = true
+				}
+			}
+			service["Tags"] = tags
+		}
+	}
+
+	groups, _ := job["TaskGroups"].([]interface{})
+	for _, raw := range groups {
+		tg, _ := raw.(map[string]interface{})
+		update(tg["Services"])
+		tasks, _ := tg["Tasks"].([]interface{})
+		for _, rawTask := range tasks {
+			task, _ := rawTask.(map[string]interface{})
+			update(task["Services"])
+		}
+	}
+}
+
+// jobDefinitionCount sums the group counts of a raw job.
+func jobDefinitionCount(job map[string]interface{}) int {
+	total := 0
+	groups, _ := job["TaskGroups"].([]interface{})
+	for _, raw := range groups {
+		tg, _ := raw.(map[string]interface{})
+		if count, ok := tg["Count"].(float64); ok {
+			total += int(count)
+		} else {
+			total++
+		}
+	}
+	return total
+}
+
+// waitForJobHealthy polls until the job's deployment for jobModifyIndex succeeds. Jobs without
+// an update block get no deployment; for them every expected allocation must be running.
+func waitForJobHealthy(ctx context.Context, client utils.JobAPI, jobID, namespace string, jobModifyIndex, expected int, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		deployment, err := client.GetJobDeployment(ctx, jobID, namespace)
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+		switch {
+		case err == nil && deployment.ID != "" && deployment.JobModifyIndex >= jobModifyIndex:
+			switch deployment.Status {
+			case "successful":
+				return nil
+			case "failed", "cancelled":
+				return fmt.Errorf("deployment %s %s: %s", deployment.ID, deployment.Status, deployment.StatusDescription)
+			}
+		case err != nil || deployment.ID == "":
+			allocs, err := client.ListJobAllocations(ctx, jobID, namespace)
+			if err != nil {
+				return err
+			}
+			running := 0
+			for _, alloc := range allocs {
+				if alloc.DesiredStatus == "run" && alloc.ClientStatus == "running" {
+					running++
+				}
+			}
+			if running >= expected {
+				return nil
+			}
+		}
+
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("%s not healthy after %s", jobID, timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(blueGreenPollInterval):
+		}
+	}
+}
+
+func blueGreenResult(report BlueGreenReport, isError bool) (*mcp.CallToolResult, error) {
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format blue/green report", err), nil
+	}
+	if isError {
+		return withNomadWarnings(mcp.NewToolResultError(string(reportJSON)), report.Warnings...), nil
+	}
+	return withNomadWarnings(mcp.NewToolResultText(string(reportJSON)), report.Warnings...), nil
+}
@@ -0,0 +1,189 @@
+// File: tools/cache.go
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// refreshArgumentDescription documents the refresh argument added to read-only tools.
+const refreshArgumentDescription = "Bypass the per-session result cache and read fresh data from Nomad (default: false)"
+
+// resultCacheArguments are left out of the cache key: they do not change what Nomad returns.
+var resultCacheArguments = []string{"refresh", "query"}
+
+// uncachedTools are read-only tools whose result is never the same twice, or changes with
+// calls made by other sessions.
+var uncachedTools = map[string]bool{"subscribe_events": true, "list_recent_operations": true}
+
+// uncachedCall reports whether a read-only call streams live data and must not be cached:
+// an uncached tool, or any tool asked to follow its output.
+func uncachedCall(request mcp.CallToolRequest) bool {
+	follow, _ := request.GetArguments()["follow"].(bool)
+	return follow || uncachedTools[request.Params.Name]
+}
+
+type cachedResult struct {
+	result  *mcp.CallToolResult
+	expires time.Time
+	scope   resultScope
+}
+
+// ResultCache remembers successful read-only tool results per MCP session for a short TTL,
+// so a repeated call with the same arguments does not reach Nomad again.
+type ResultCache struct {
+	ttl      time.Duration
+	mu       sync.Mutex
+	sessions map[string]map[string]cachedResult
+}
+
+// NewResultCache returns a cache keeping results for ttl.
+func NewResultCache(ttl time.Duration) *ResultCache {
+	return &ResultCache{ttl: ttl, sessions: map[string]map[string]cachedResult{}}
+}
+
+func (c *ResultCache) get(session, key string) (*mcp.CallToolResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.sessions[session][key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return cloneToolResult(entry.result), true
+}
+
+func (c *ResultCache) put(session, key string, scope resultScope, result *mcp.CallToolResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for id, entries := range c.sessions {
+		for k, entry := range entries {
+			if now.After(entry.expires) {
+				delete(entries, k)
+			}
+		}
+		if len(entries) == 0 {
+			delete(c.sessions, id)
+		}
+	}
+	if c.sessions[session] == nil {
+		c.sessions[session] = map[string]cachedResult{}
+	}
+	c.sessions[session][key] = cachedResult{result: cloneToolResult(result), expires: now.Add(c.ttl), scope: scope}
+}
+
+// invalidate drops every cached result of a session.
+func (c *ResultCache) invalidate(session string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sessions, session)
+}
+
+// ForgetSession drops the cached results of session.
+func (c *ResultCache) ForgetSession(session string) {
+	c.invalidate(session)
+}
+
+// ForgetAddress drops the cached results of every session, which came from the cluster the
+// client talked to before.
+func (c *ResultCache) ForgetAddress() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.sessions)
+}
+
+// cloneToolResult copies the parts of a result that later middleware modifies in place
+// (content blocks and _meta), so cached entries are never shared with a response.
+func cloneToolResult(result *mcp.CallToolResult) *mcp.CallToolResult {
+	clone := *result
+	clone.Content = append([]mcp.Content(nil), result.Content...)
+	if result.Meta != nil {
+		meta := *result.Meta
+		meta.AdditionalFields = maps.Clone(result.Meta.AdditionalFields)
+		clone.Meta = &meta
+	}
+	return &clone
+}
+
+// resultCacheKey identifies a call by caller token, region, tool and arguments, so callers
+// with different ACLs never share results. json.Marshal sorts map keys, so argument order
+// does not matter.
+func resultCacheKey(ctx context.Context, request mcp.CallToolRequest) (string, bool) {
+	arguments, _ := request.Params.Arguments.(map[string]interface{})
+	keyed := maps.Clone(arguments)
+	for _, name := range resultCacheArguments {
+		delete(keyed, name)
+	}
+	argumentsJSON, err := json.Marshal(keyed)
+	if err != nil {
+		return "", false
+	}
+	token := sha256.Sum256([]byte(utils.TokenFromContext(ctx)))
+	key, err := json.Marshal([]string{hex.EncodeToString(token[:]), utils.RegionFromContext(ctx), request.Params.Name, string(argumentsJSON)})
+	if err != nil {
+		return "", false
+	}
+	return string(key), true
+}
+
+// ResultCacheMiddleware returns a tool middleware that serves repeated read-only tool calls
+// from cache. A refresh=true argument skips the cached result and stores the new one, and
+// any mutating tool call empties the session's cache so later reads see its effect.
+// Changes made elsewhere are dropped by WatchEvents.
+// Cached results carry cached=true in their _meta. A nil cache disables caching.
+func ResultCacheMiddleware(cache *ResultCache) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			srv := server.ServerFromContext(ctx)
+			if cache == nil || srv == nil || uncachedCall(request) {
+				return next(ctx, request)
+			}
+			session := sessionID(ctx)
+			if tool := srv.GetTool(request.Params.Name); tool == nil || !isReadOnlyTool(tool.Tool) {
+				defer cache.invalidate(session)
+				return next(ctx, request)
+			}
+			key, ok := resultCacheKey(ctx, request)
+			if !ok {
+				return next(ctx, request)
+			}
+
+			if refresh, _ := request.GetArguments()["refresh"].(bool); !refresh {
+				if result, ok := cache.get(session, key); ok {
+					if result.Meta == nil {
+						result.Meta = &mcp.Meta{}
+					}
+					if result.Meta.AdditionalFields == nil {
+						result.Meta.AdditionalFields = map[string]any{}
+					}
+					result.Meta.AdditionalFields["cached"] = true
+					return result, nil
+				}
+			}
+
+			result, err := next(ctx, request)
+			if err == nil && result != nil && !result.IsError {
+				cache.put(session, key, newResultScope(request), result)
+			}
+			return result, err
+		}
+	}
+}
+
+// AddRefreshArgument adds an optional refresh argument to every registered read-only tool.
+// ResultCacheMiddleware evaluates it; call this after all tools are registered.
+func AddRefreshArgument(s *server.MCPServer) {
+	addReadOnlyToolArgument(s, "refresh", map[string]any{
+		"type":        "boolean",
+		"description": refreshArgumentDescription,
+	})
+}
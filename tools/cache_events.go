@@ -0,0 +1,155 @@
+// File: tools/cache_events.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	cacheEventsMinBackoff = time.Second
+	cacheEventsMaxBackoff = time.Minute
+)
+
+// cacheEventTopics are the event topics whose objects cached tool results show.
+var cacheEventTopics = []string{"Job", "Evaluation", "Deployment", "Allocation"}
+
+// resultScopeArguments are the arguments naming the objects a cached result is about.
+var resultScopeArguments = []string{"job_id", "other_job_id", "evaluation_id", "deployment_id", "allocation_id", "node_id"}
+
+// resultScope records which objects a cached result is about, so an event only drops the
+// results that may show the object it changed.
+type resultScope struct {
+	namespace string
+	// ids maps a scope argument, e.g. job_id, to its value; empty for listings and reports
+	ids map[string]string
+}
+
+func newResultScope(request mcp.CallToolRequest) resultScope {
+	arguments, _ := request.Params.Arguments.(map[string]interface{})
+	scope := resultScope{namespace: utils.EffectiveToolNamespace(arguments), ids: map[string]string{}}
+	for _, name := range resultScopeArguments {
+		if id, _ := arguments[name].(string); id != "" {
+			scope.ids[name] = id
+		}
+	}
+	// other_job_id is a second job, matched like job_id.
+	if id, ok := scope.ids["other_job_id"]; ok {
+		delete(scope.ids, "other_job_id")
+		if _, ok := scope.ids["job_id"]; !ok {
+			scope.ids["job_id"] = id
+		}
+	}
+	return scope
+}
+
+// affectedBy reports whether a result may show an object changed by event. Results about no
+// particular object are always affected.
+func (s resultScope) affectedBy(event cacheEvent) bool {
+	if len(s.ids) == 0 {
+		return true
+	}
+	for name, id := range s.ids {
+		if event.ids[name] != id {
+			continue
+		}
+		// Job IDs are only unique within a namespace; the other IDs are UUIDs.
+		if name != "job_id" || s.namespace == "*" || s.namespace == event.namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheEvent is an event reduced to the IDs of the objects it touches.
+type cacheEvent struct {
+	namespace string
+	ids       map[string]string
+}
+
+// eventObject holds the fields of a Job, Evaluation, Deployment or Allocation event payload
+// that link it to other objects.
+type eventObject struct {
+	ID           string `json:"ID"`
+	JobID        string `json:"JobID"`
+	EvalID       string `json:"EvalID"`
+	DeploymentID string `json:"DeploymentID"`
+	NodeID       string `json:"NodeID"`
+}
+
+func newCacheEvent(event types.Event) cacheEvent {
+	var object eventObject
+	var payload map[string]json.RawMessage
+	if json.Unmarshal(event.Payload, &payload) == nil {
+		// The payload wraps the object under its topic, e.g. {"Allocation": {...}}.
+		_ = json.Unmarshal(payload[event.Topic], &object)
+	}
+
+	ids := map[string]string{"job_id": object.JobID, "evaluation_id": object.EvalID, "deployment_id": object.DeploymentID, "node_id": object.NodeID}
+	switch event.Topic {
+	case "Job":
+		ids["job_id"] = event.Key
+	case "Evaluation":
+		ids["evaluation_id"] = event.Key
+	case "Deployment":
+		ids["deployment_id"] = event.Key
+	case "Allocation":
+		ids["allocation_id"] = event.Key
+	}
+	return cacheEvent{namespace: event.Namespace, ids: ids}
+}
+
+// invalidateEvent drops the cached results of every session that may show an object event
+// changed.
+func (c *ResultCache) invalidateEvent(event types.Event) {
+	changed := newCacheEvent(event)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, entries := range c.sessions {
+		for key, entry := range entries {
+			if entry.scope.affectedBy(changed) {
+				delete(entries, key)
+			}
+		}
+	}
+}
+
+// WatchEvents follows the Job, Evaluation, Deployment and Allocation events of every
+// namespace until ctx ends and drops the cached results they make stale, so changes made
+// outside this session, or outside this server, are not served from cache. It reconnects,
+// resuming after the last index seen, when the stream fails.
+func (c *ResultCache) WatchEvents(ctx context.Context, client utils.EventAPI, logger *log.Logger) {
+	var index uint64
+	backoff := cacheEventsMinBackoff
+	for ctx.Err() == nil {
+		request := types.EventStreamRequest{Topics: cacheEventTopics, Namespace: "*"}
+		if index > 0 {
+			request.Index = index + 1
+		}
+		err := client.StreamEvents(ctx, request, func(frame types.EventFrame) error {
+			for _, event := range frame.Events {
+				c.invalidateEvent(event)
+			}
+			index, backoff = frame.Index, cacheEventsMinBackoff
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Printf("Error watching events for the result cache, retrying in %s: %v", backoff, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, cacheEventsMaxBackoff)
+	}
+}
@@ -0,0 +1,250 @@
+// File: tools/capabilities.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolRequirements records tools that need more than a baseline Nomad cluster.
+var toolRequirements = map[string]struct {
+	MinNomadVersion string
+	Enterprise      bool
+}{
+	"subscribe_events":            {MinNomadVersion: "1.0.0"},
+	"list_evaluations":            {MinNomadVersion: "1.2.0"},
+	"get_job_services":            {MinNomadVersion: "1.3.0"},
+	"list_services":               {MinNomadVersion: "1.3.0"},
+	"get_service":                 {MinNomadVersion: "1.3.0"},
+	"delete_service_registration": {MinNomadVersion: "1.3.0"},
+	"get_job_submission":          {MinNomadVersion: "1.6.0"},
+	"list_variables":              {MinNomadVersion: "1.4.0"},
+	"get_variable":                {MinNomadVersion: "1.4.0"},
+	"create_variable":             {MinNomadVersion: "1.4.0"},
+	"delete_variable":             {MinNomadVersion: "1.4.0"},
+	"list_acl_roles":              {MinNomadVersion: "1.4.0"},
+	"get_acl_role":                {MinNomadVersion: "1.4.0"},
+	"create_acl_role":             {MinNomadVersion: "1.4.0"},
+	"delete_acl_role":             {MinNomadVersion: "1.4.0"},
+	"list_node_pools":             {MinNomadVersion: "1.6.0"},
+	"get_node_pool":               {MinNomadVersion: "1.6.0"},
+	"create_node_pool":            {MinNomadVersion: "1.6.0"},
+	"delete_node_pool":            {MinNomadVersion: "1.6.0"},
+	"list_node_pool_nodes":        {MinNomadVersion: "1.6.0"},
+	"transfer_leadership":         {MinNomadVersion: "1.7.0"},
+	"get_license":                 {Enterprise: true},
+	"put_license":                 {Enterprise: true},
+	"list_sentinel_policies":      {Enterprise: true},
+	"get_sentinel_policy":         {Enterprise: true},
+	"create_sentinel_policy":      {Enterprise: true},
+	"delete_sentinel_policy":      {Enterprise: true},
+	"list_quotas":                 {Enterprise: true},
+	"get_quota":                   {Enterprise: true},
+	"create_quota":                {Enterprise: true},
+	"delete_quota":                {Enterprise: true},
+	"quota_usage":                 {Enterprise: true},
+}
+
+// ToolCategories maps tool names to the category they were registered under.
+type ToolCategories map[string]string
+
+// Track runs register and records every tool it added under category.
+func (c ToolCategories) Track(s *server.MCPServer, category string, register func()) {
+	before := s.ListTools()
+	register()
+	for name := range s.ListTools() {
+		if _, ok := before[name]; !ok {
+			c[name] = category
+		}
+	}
+}
+
+// CapabilityConfig is the server configuration that affects which tools are usable.
+type CapabilityConfig struct {
+	SandboxNamespace string
+}
+
+// ToolCapability describes one registered tool in system://capabilities
+type ToolCapability struct {
+	Name            string `json:"Name"`
+	Category        string `json:"Category"`
+	Access          string `json:"Access"` // read or write
+	MinNomadVersion string `json:"MinNomadVersion,omitempty"`
+	Enterprise      bool   `json:"Enterprise,omitempty"`
+	// SupportedByCluster is set when the cluster version is known and the tool has a minimum.
+	SupportedByCluster *bool  `json:"SupportedByCluster,omitempty"`
+	Enabled            bool   `json:"Enabled"`
+	DisabledReason     string `json:"DisabledReason,omitempty"`
+}
+
+// RegisterCapabilityResources registers system://capabilities and docs://tools. Both are
+// built from the server's tool registry on every read so they always match what clients can
+// call, and are keyed to the Nomad version reported by the connected agent.
+func RegisterCapabilityResources(s *server.MCPServer, nomadClient utils.AgentAPI, categories ToolCategories, config CapabilityConfig, logger *log.Logger) {
+	capabilitiesResource := mcp.NewResource(
+		"system://capabilities",
+		"Tool Capabilities",
+		mcp.WithResourceDescription("Every registered tool with its category, read/write classification, Nomad requirements and whether the current configuration enables it"),
+		mcp.WithMIMEType("application/json"),
+	)
+
+	s.AddResource(capabilitiesResource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		capabilities := listToolCapabilities(s, categories, config, detectNomadVersion(ctx, nomadClient, logger))
+
+		capabilitiesJSON, err := json.MarshalIndent(capabilities, "", "  ")
+		if err != nil {
+			requestLogf(ctx, logger, "Error formatting capabilities: %v", err)
+			return nil, err
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      "system://capabilities",
+				MIMEType: "application/json",
+				Text:     string(capabilitiesJSON),
+			},
+		}, nil
+	})
+
+	toolsDocsResource := mcp.NewResource(
+		"docs://tools",
+		"Tools Reference",
+		mcp.WithResourceDescription("Generated reference of the registered tools and resources for the connected Nomad version"),
+		mcp.WithMIMEType("text/markdown"),
+	)
+
+	s.AddResource(toolsDocsResource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		version := detectNomadVersion(ctx, nomadClient, logger)
+		capabilities := listToolCapabilities(s, categories, config, version)
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      "docs://tools",
+				MIMEType: "text/markdown",
+				Text:     renderToolsReference(s, capabilities, version),
+			},
+		}, nil
+	})
+}
+
+// detectNomadVersion returns the agent's Nomad version, or "" when it cannot be determined.
+func detectNomadVersion(ctx context.Context, nomadClient utils.AgentAPI, logger *log.Logger) string {
+	version, err := nomadClient.GetNomadVersion(ctx)
+	if err != nil {
+		requestLogf(ctx, logger, "Error detecting Nomad version: %v", err)
+		return ""
+	}
+	return version
+}
+
+func listToolCapabilities(s *server.MCPServer, categories ToolCategories, config CapabilityConfig, nomadVersion string) []ToolCapability {
+	clusterVersion, knownVersion := utils.ParseVersion(nomadVersion)
+
+	capabilities := []ToolCapability{}
+	for name, tool := range s.ListTools() {
+		capability := ToolCapability{
+			Name:     name,
+			Category: categories[name],
+			Access:   "write",
+			Enabled:  true,
+		}
+		if capability.Category == "" {
+			capability.Category = "other"
+		}
+		if isReadOnlyTool(tool.Tool) {
+			capability.Access = "read"
+		}
+		if req, ok := toolRequirements[name]; ok {
+			capability.MinNomadVersion = req.MinNomadVersion
+			capability.Enterprise = req.Enterprise
+			if minVersion, ok := utils.ParseVersion(req.MinNomadVersion); ok && knownVersion {
+				supported := utils.CompareVersions(clusterVersion, minVersion) >= 0
+				capability.SupportedByCluster = &supported
+			}
+		}
+		if config.SandboxNamespace != "" && sandboxRefuses(tool.Tool) {
+			capability.Enabled = false
+			capability.DisabledReason = "not namespace-scoped; mutating tools are confined to namespace " + config.SandboxNamespace
+		}
+		capabilities = append(capabilities, capability)
+	}
+
+	sort.Slice(capabilities, func(i, j int) bool {
+		if capabilities[i].Category != capabilities[j].Category {
+			return capabilities[i].Category < capabilities[j].Category
+		}
+		return capabilities[i].Name < capabilities[j].Name
+	})
+	return capabilities
+}
+
+// renderToolsReference renders the markdown behind docs://tools.
+func renderToolsReference(s *server.MCPServer, capabilities []ToolCapability, nomadVersion string) string {
+	var b strings.Builder
+
+	b.WriteString("# Nomad MCP Tools\n\n")
+	if nomadVersion != "" {
+		fmt.Fprintf(&b, "Generated for Nomad %s.\n", nomadVersion)
+	} else {
+		b.WriteString("Generated without a detected Nomad version; version requirements are not checked.\n")
+	}
+
+	tools := s.ListTools()
+	category := ""
+	for _, capability := range capabilities {
+		if capability.Category != category {
+			category = capability.Category
+			fmt.Fprintf(&b, "\n## %s\n\n", category)
+		}
+
+		fmt.Fprintf(&b, "- **%s** (%s)", capability.Name, capability.Access)
+		if tool, ok := tools[capability.Name]; ok && tool.Tool.Description != "" {
+			fmt.Fprintf(&b, ": %s", tool.Tool.Description)
+		}
+		b.WriteString("\n")
+
+		var notes []string
+		if capability.MinNomadVersion != "" {
+			notes = append(notes, "requires Nomad "+capability.MinNomadVersion)
+		}
+		if capability.Enterprise {
+			notes = append(notes, "requires Nomad Enterprise")
+		}
+		if capability.SupportedByCluster != nil && !*capability.SupportedByCluster {
+			notes = append(notes, "not supported by the connected cluster")
+		}
+		if !capability.Enabled {
+			notes = append(notes, "disabled: "+capability.DisabledReason)
+		}
+		if len(notes) > 0 {
+			fmt.Fprintf(&b, "  - %s\n", strings.Join(notes, "; "))
+		}
+	}
+
+	resources := s.ListResources()
+	uris := make([]string, 0, len(resources))
+	for uri := range resources {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+
+	b.WriteString("\n## Resources\n\n")
+	for _, uri := range uris {
+		resource := resources[uri].Resource
+		fmt.Fprintf(&b, "- `%s`", uri)
+		if resource.Description != "" {
+			fmt.Fprintf(&b, ": %s", resource.Description)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,164 @@
+// File: tools/chaos.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ChaosAllowlist lists the jobs chaos tools may target, as namespace/job pairs. A job of *
+// allows every job of the namespace.
+type ChaosAllowlist map[string]map[string]bool
+
+// ParseChaosAllowlist parses namespace/job entries such as staging/* or default/web.
+func ParseChaosAllowlist(entries []string) (ChaosAllowlist, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no namespace/job entries; chaos tools need at least one target")
+	}
+	allowlist := ChaosAllowlist{}
+	for _, entry := range entries {
+		namespace, job, ok := strings.Cut(entry, "/")
+		namespace, job = strings.TrimSpace(namespace), strings.TrimSpace(job)
+		if !ok || namespace == "" || job == "" || namespace == "*" {
+			return nil, fmt.Errorf("invalid entry %q: want namespace/job or namespace/*", entry)
+		}
+		if allowlist[namespace] == nil {
+			allowlist[namespace] = map[string]bool{}
+		}
+		allowlist[namespace][job] = true
+	}
+	return allowlist, nil
+}
+
+// Allows reports whether chaos tools may target job in namespace.
+func (a ChaosAllowlist) Allows(namespace, job string) bool {
+	jobs := a[namespace]
+	return jobs["*"] || jobs[job]
+}
+
+// ChaosKilledAllocation is an allocation kill_random_allocation stopped, or would stop
+type ChaosKilledAllocation struct {
+	ID        string `json:"ID"`
+	Name      string `json:"Name"`
+	TaskGroup string `json:"TaskGroup"`
+	NodeID    string `json:"NodeID"`
+}
+
+// ChaosKillReport is the result of kill_random_allocation
+type ChaosKillReport struct {
+	JobID       string                  `json:"JobID"`
+	Namespace   string                  `json:"Namespace"`
+	TaskGroup   string                  `json:"TaskGroup,omitempty"`
+	DryRun      bool                    `json:"DryRun"`
+	Running     int                     `json:"Running"`
+	Allocations []ChaosKilledAllocation `json:"Allocations"`
+	Summary     string                  `json:"Summary"`
+}
+
+// RegisterChaosTools registers the fault injection tools for resilience game days. They are
+// only registered with -enable-chaos-tools and only act on allowlisted jobs.
+func RegisterChaosTools(s *server.MCPServer, nomadClient utils.AllocationAPI, allowlist ChaosAllowlist, logger *log.Logger) {
+	killRandomAllocationTool := mcp.NewTool("kill_random_allocation",
+		mcp.WithDescription("Chaos testing: stop randomly chosen running allocations of an allowlisted job, so the scheduler has to replace them. Use dry_run to see which would be picked"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The job whose allocations to kill; it must be in the server's chaos allowlist"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithString("task_group",
+			mcp.Description("Only pick allocations of this task group"),
+		),
+		mcp.WithNumber("count",
+			mcp.Description("Number of allocations to kill (default: 1); fewer are killed when fewer are running"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Pick the allocations without stopping them (default: false)"),
+		),
+	)
+	s.AddTool(killRandomAllocationTool, KillRandomAllocationHandler(nomadClient, allowlist, logger))
+}
+
+// KillRandomAllocationHandler returns a handler that stops random running allocations of a job
+func KillRandomAllocationHandler(client utils.AllocationAPI, allowlist ChaosAllowlist, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, _ := arguments["job_id"].(string)
+		if jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+		if !allowlist.Allows(namespace, jobID) {
+			return mcp.NewToolResultError(fmt.Sprintf("job %s in namespace %s is not in the chaos allowlist of this server", jobID, namespace)), nil
+		}
+		taskGroup, _ := arguments["task_group"].(string)
+		count := 1
+		if n, ok := arguments["count"].(float64); ok {
+			if n < 1 || n != float64(int(n)) {
+				return mcp.NewToolResultError("count must be a positive integer"), nil
+			}
+			count = int(n)
+		}
+		dryRun, _ := arguments["dry_run"].(bool)
+
+		allocs, err := client.ListAllocations(ctx, namespace, jobID)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing job allocations: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list job allocations", err), nil
+		}
+
+		running := []types.Allocation{}
+		for _, alloc := range allocs {
+			if alloc.ClientStatus == "running" && alloc.DesiredStatus == "run" && (taskGroup == "" || alloc.TaskGroup == taskGroup) {
+				running = append(running, alloc)
+			}
+		}
+		rand.Shuffle(len(running), func(i, j int) { running[i], running[j] = running[j], running[i] })
+		if count > len(running) {
+			count = len(running)
+		}
+
+		report := ChaosKillReport{JobID: jobID, Namespace: namespace, TaskGroup: taskGroup, DryRun: dryRun, Running: len(running), Allocations: []ChaosKilledAllocation{}}
+		for _, alloc := range running[:count] {
+			if !dryRun {
+				if err := client.StopAllocation(ctx, alloc.ID); err != nil {
+					requestLogf(ctx, logger, "Error stopping allocation: %v", err)
+					return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Failed to stop allocation %s after killing %d", alloc.ID, len(report.Allocations)), err), nil
+				}
+				requestLogf(ctx, logger, "Chaos: stopped allocation %s of job %s in namespace %s", alloc.ID, jobID, namespace)
+			}
+			report.Allocations = append(report.Allocations, ChaosKilledAllocation{ID: alloc.ID, Name: alloc.Name, TaskGroup: alloc.TaskGroup, NodeID: alloc.NodeID})
+		}
+
+		switch {
+		case len(report.Allocations) == 0:
+			report.Summary = fmt.Sprintf("Job %s has no running allocations to kill.", jobID)
+		case dryRun:
+			report.Summary = fmt.Sprintf("Would stop %d of %d running allocations; nothing was stopped.", len(report.Allocations), report.Running)
+		default:
+			report.Summary = fmt.Sprintf("Stopped %d of %d running allocations; the scheduler replaces them. Follow with get_job_status to watch the recovery.", len(report.Allocations), report.Running)
+		}
+
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format chaos report", err), nil
+		}
+
+		return mcp.NewToolResultText(string(reportJSON)), nil
+	}
+}
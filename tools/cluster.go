@@ -16,34 +16,44 @@ func RegisterClusterTools(s *server.MCPServer, nomadClient utils.ClusterToolsAPI
 	// Get cluster leader tool
 	getClusterLeaderTool := mcp.NewTool("get_cluster_leader",
 		mcp.WithDescription("Get the current leader and the information relative the Nomad peers"),
+		mcp.WithReadOnlyHintAnnotation(true),
 	)
 	s.AddTool(getClusterLeaderTool, GetClusterLeaderHandler(nomadClient, logger))
 
 	// List cluster peers tool
 	listClusterPeersTool := mcp.NewTool("list_cluster_peers",
 		mcp.WithDescription("List the IP peers in the Nomad cluster"),
+		mcp.WithReadOnlyHintAnnotation(true),
 	)
 	s.AddTool(listClusterPeersTool, ListClusterPeersHandler(nomadClient, logger))
 
 	// List regions tool
 	listRegionsTool := mcp.NewTool("list_regions",
 		mcp.WithDescription("List all available regions in the Nomad cluster"),
+		mcp.WithReadOnlyHintAnnotation(true),
 	)
 	s.AddTool(listRegionsTool, ListRegionsHandler(nomadClient, logger))
+
+	// Check server quorum tool
+	checkServerQuorumTool := mcp.NewTool("check_server_quorum",
+		mcp.WithDescription("Cross-check server gossip membership, the Raft configuration and autopilot health to find non-voting, failed or left-behind servers and quorum risk, with a red/amber/green verdict"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+	s.AddTool(checkServerQuorumTool, CheckServerQuorumHandler(nomadClient, logger))
 }
 
 func GetClusterLeaderHandler(client utils.ClusterToolsAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		body, err := client.MakeRequest(ctx, "GET", "operator/raft/configuration", nil, nil)
 		if err != nil {
-			logger.Printf("Error getting cluster configuration: %v", err)
+			requestLogf(ctx, logger, "Error getting cluster configuration: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get cluster configuration", err), nil
 		}
 
 		// Parse the response
 		var config map[string]interface{}
 		if err := json.Unmarshal(body, &config); err != nil {
-			logger.Printf("Error parsing cluster configuration: %v", err)
+			requestLogf(ctx, logger, "Error parsing cluster configuration: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to parse cluster configuration", err), nil
 		}
 
@@ -61,7 +71,7 @@ func GetClusterLeaderHandler(client utils.ClusterToolsAPI, logger *log.Logger) f
 		for _, serverRaw := range serversArray {
 			serverMap, ok := serverRaw.(map[string]interface{})
 			if !ok {
-				logger.Printf("Server is not a map: %v", serverRaw)
+				requestLogf(ctx, logger, "Server is not a map: %v", serverRaw)
 				continue
 			}
 
@@ -99,14 +109,14 @@ func ListClusterPeersHandler(client utils.ClusterToolsAPI, logger *log.Logger) f
 		body, err := client.ListClusterPeers(ctx)
 
 		if err != nil {
-			logger.Printf("Error getting cluster configuration: %v", err)
+			requestLogf(ctx, logger, "Error getting cluster configuration: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get cluster configuration", err), nil
 		}
 
 		// Parse the response to find peers
 		var config map[string]interface{}
 		if err := json.Unmarshal(body, &config); err != nil {
-			logger.Printf("Error parsing cluster configuration: %v", err)
+			requestLogf(ctx, logger, "Error parsing cluster configuration: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to parse cluster configuration", err), nil
 		}
 
@@ -138,12 +148,17 @@ func ListClusterPeersHandler(client utils.ClusterToolsAPI, logger *log.Logger) f
 // ListRegionsHandler returns a handler for listing regions
 func ListRegionsHandler(client utils.ClusterToolsAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		body, err := client.MakeRequest(ctx, "GET", "regions", nil, nil)
+		regions, err := client.ListRegions(ctx)
 		if err != nil {
-			logger.Printf("Error listing regions: %v", err)
+			requestLogf(ctx, logger, "Error listing regions: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to list regions", err), nil
 		}
 
-		return mcp.NewToolResultText(string(body)), nil
+		regionsJSON, err := json.MarshalIndent(regions, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format regions", err), nil
+		}
+
+		return mcp.NewToolResultText(string(regionsJSON)), nil
 	}
 }
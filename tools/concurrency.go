@@ -0,0 +1,102 @@
+// File: tools/concurrency.go
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxFetchConcurrency bounds the Nomad calls a tool makes at once when it looks up many objects.
+const maxFetchConcurrency = 8
+
+// forEachConcurrently calls fn for every index below n, at most maxFetchConcurrency at a time,
+// and returns once every call has returned.
+func forEachConcurrently(n int, fn func(i int)) {
+	sem := make(chan struct{}, maxFetchConcurrency)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// sessionSemaphore bounds the running calls of one session. users counts holders and
+// waiters, so the semaphore is dropped once nobody uses it.
+type sessionSemaphore struct {
+	slots chan struct{}
+	users int
+}
+
+// SessionConcurrencyLimiter caps the tool calls each MCP session runs at once. Transports
+// without sessions share one limit.
+type SessionConcurrencyLimiter struct {
+	limit    int
+	mu       sync.Mutex
+	sessions map[string]*sessionSemaphore
+}
+
+// NewSessionConcurrencyLimiter returns a limiter allowing limit concurrent calls per session.
+func NewSessionConcurrencyLimiter(limit int) *SessionConcurrencyLimiter {
+	return &SessionConcurrencyLimiter{limit: limit, sessions: map[string]*sessionSemaphore{}}
+}
+
+// acquire waits for a free slot of session and returns the func releasing it.
+func (l *SessionConcurrencyLimiter) acquire(ctx context.Context, session string) (func(), error) {
+	l.mu.Lock()
+	semaphore := l.sessions[session]
+	if semaphore == nil {
+		semaphore = &sessionSemaphore{slots: make(chan struct{}, l.limit)}
+		l.sessions[session] = semaphore
+	}
+	semaphore.users++
+	l.mu.Unlock()
+
+	leave := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		semaphore.users--
+		if semaphore.users == 0 {
+			delete(l.sessions, session)
+		}
+	}
+
+	select {
+	case semaphore.slots <- struct{}{}:
+		return func() {
+			<-semaphore.slots
+			leave()
+		}, nil
+	case <-ctx.Done():
+		leave()
+		return nil, ctx.Err()
+	}
+}
+
+// SessionConcurrencyMiddleware returns a tool middleware that queues a session's tool calls
+// beyond the limiter's limit until an earlier one finishes. Calls whose context ends while
+// queued fail without running. A nil limiter disables the limit.
+func SessionConcurrencyMiddleware(limiter *SessionConcurrencyLimiter) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if limiter == nil {
+				return next(ctx, request)
+			}
+			release, err := limiter.acquire(ctx, sessionID(ctx))
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("%s was not started: the session already runs %d tool calls (%v)", request.Params.Name, limiter.limit, err)), nil
+			}
+			defer release()
+			return next(ctx, request)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// File: tools/connect.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Nomad's sidecar proxy resources when a job does not set sidecar_task resources.
+const (
+	defaultSidecarCPU      = 250
+	defaultSidecarMemoryMB = 128
+)
+
+// ConnectService is one Consul Connect service of a task group
+type ConnectService struct {
+	Group           string                   `json:"Group"`
+	Service         string                   `json:"Service"`
+	Mode            string                   `json:"Mode"` // sidecar, native or gateway
+	PortLabel       string                   `json:"PortLabel,omitempty"`
+	SidecarTask     string                   `json:"SidecarTask,omitempty"`
+	SidecarCPU      int                      `json:"SidecarCPU,omitempty"`
+	SidecarMemoryMB int                      `json:"SidecarMemoryMB,omitempty"`
+	Gateway         []string                 `json:"Gateway,omitempty"`
+	Upstreams       []types.ConsulUpstream   `json:"Upstreams"`
+	ExposedPaths    []types.ConsulExposePath `json:"ExposedPaths"`
+}
+
+// ConnectTopology is the get_job_connect_topology response
+type ConnectTopology struct {
+	JobID    string           `json:"JobID"`
+	Services []ConnectService `json:"Services"`
+	Issues   []string         `json:"Issues"`
+}
+
+// RegisterConnectTools registers Consul Connect inspection tools
+func RegisterConnectTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log.Logger) {
+	// Get job connect topology tool
+	getJobConnectTopologyTool := mcp.NewTool("get_job_connect_topology",
+		mcp.WithDescription("Report the Consul Connect topology of a job: sidecar proxies and their resources, upstreams and exposed paths per service, and flag missing sidecar resources, non-bridge networking and upstream port clashes"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Description("The ID of a registered job to inspect"),
+		),
+		mcp.WithString("job_spec",
+			mcp.Description("A job specification in HCL or JSON format to inspect instead of a registered job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+	)
+	s.AddTool(getJobConnectTopologyTool, GetJobConnectTopologyHandler(nomadClient, logger))
+}
+
+// GetJobConnectTopologyHandler returns a handler for inspecting a job's Connect services
+func GetJobConnectTopologyHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		job, err := jobFromArguments(ctx, client, arguments)
+		if err != nil {
+			requestLogf(ctx, logger, "Error loading job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to load job", err), nil
+		}
+
+		topologyJSON, err := json.MarshalIndent(jobConnectTopology(job), "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format connect topology", err), nil
+		}
+
+		return mcp.NewToolResultText(string(topologyJSON)), nil
+	}
+}
+
+func jobConnectTopology(job types.Job) ConnectTopology {
+	topology := ConnectTopology{JobID: job.ID, Services: []ConnectService{}, Issues: []string{}}
+
+	for _, tg := range job.TaskGroups {
+		bindPorts := map[int]string{}
+		hasSidecar := false
+
+		for _, svc := range tg.Services {
+			if svc.Connect == nil {
+				continue
+			}
+			service := ConnectService{
+				Group:        tg.Name,
+				Service:      svc.Name,
+				PortLabel:    svc.PortLabel,
+				Upstreams:    []types.ConsulUpstream{},
+				ExposedPaths: []types.ConsulExposePath{},
+			}
+
+			switch {
+			case svc.Connect.Native:
+				service.Mode = "native"
+			case svc.Connect.Gateway != nil:
+				service.Mode = "gateway"
+				for kind, config := range svc.Connect.Gateway {
+					if config != nil && kind != "Proxy" {
+						service.Gateway = append(service.Gateway, strings.ToLower(kind))
+					}
+				}
+				sort.Strings(service.Gateway)
+			case svc.Connect.SidecarService != nil:
+				service.Mode = "sidecar"
+				hasSidecar = true
+				topology.Issues = append(topology.Issues, connectSidecarDetails(tg, svc, &service)...)
+				if proxy := svc.Connect.SidecarService.Proxy; proxy != nil {
+					for _, upstream := range proxy.Upstreams {
+						if other, ok := bindPorts[upstream.LocalBindPort]; ok && upstream.LocalBindPort != 0 {
+							topology.Issues = append(topology.Issues, fmt.Sprintf("group %s: upstreams %s and %s both bind local port %d", tg.Name, other, upstream.DestinationName, upstream.LocalBindPort))
+						}
+						bindPorts[upstream.LocalBindPort] = upstream.DestinationName
+						service.Upstreams = append(service.Upstreams, upstream)
+					}
+					if proxy.Expose != nil {
+						for _, path := range proxy.Expose.Paths {
+							if !groupHasPortLabel(tg, path.ListenerPort) {
+								topology.Issues = append(topology.Issues, fmt.Sprintf("group %s: exposed path %s listens on port %q, which the group network does not define", tg.Name, path.Path, path.ListenerPort))
+							}
+							service.ExposedPaths = append(service.ExposedPaths, path)
+						}
+					}
+				}
+			default:
+				continue
+			}
+
+			topology.Services = append(topology.Services, service)
+		}
+
+		if hasSidecar && !groupUsesBridgeNetworking(tg) {
+			topology.Issues = append(topology.Issues, fmt.Sprintf("group %s: Connect sidecars require network mode \"bridge\" (or a CNI network)", tg.Name))
+		}
+	}
+
+	return topology
+}
+
+// connectSidecarDetails fills in the proxy task and its resources. Registered jobs carry the
+// injected connect-proxy task; job specs only carry sidecar_task overrides.
+func connectSidecarDetails(tg types.TaskGroup, svc types.Service, service *ConnectService) []string {
+	service.SidecarTask = "connect-proxy-" + svc.Name
+	service.SidecarCPU = defaultSidecarCPU
+	service.SidecarMemoryMB = defaultSidecarMemoryMB
+
+	for _, task := range tg.Tasks {
+		if task.Kind == "connect-proxy:"+svc.Name {
+			service.SidecarTask = task.Name
+			service.SidecarCPU = task.Resources.CPU
+			service.SidecarMemoryMB = task.Resources.MemoryMB
+		}
+	}
+
+	override := svc.Connect.SidecarTask
+	if override != nil && override.Name != "" {
+		service.SidecarTask = override.Name
+	}
+	if override == nil || override.Resources == nil {
+		return []string{fmt.Sprintf("group %s: service %s sets no sidecar_task resources, so its proxy gets Nomad's defaults (%d MHz CPU, %d MB memory); size it for the service's traffic", tg.Name, svc.Name, defaultSidecarCPU, defaultSidecarMemoryMB)}
+	}
+	if override.Resources.CPU > 0 {
+		service.SidecarCPU = override.Resources.CPU
+	}
+	if override.Resources.MemoryMB > 0 {
+		service.SidecarMemoryMB = override.Resources.MemoryMB
+	}
+	return nil
+}
+
+func groupUsesBridgeNetworking(tg types.TaskGroup) bool {
+	for _, network := range tg.Networks {
+		if network.Mode == "bridge" || strings.HasPrefix(network.Mode, "cni/") {
+			return true
+		}
+	}
+	return false
+}
+
+func groupHasPortLabel(tg types.TaskGroup, label string) bool {
+	for _, network := range tg.Networks {
+		for _, port := range append(append([]types.Port{}, network.DynamicPorts...), network.ReservedPorts...) {
+			if port.Label == label {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+// File: tools/coverage.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// CoverageGap is an eligible node missing a running allocation of a system job
+type CoverageGap struct {
+	NodeID     string   `json:"NodeID"`
+	Name       string   `json:"Name"`
+	Datacenter string   `json:"Datacenter"`
+	NodePool   string   `json:"NodePool,omitempty"`
+	TaskGroups []string `json:"TaskGroups"`
+}
+
+// CoverageBucket counts eligible and covered nodes in one datacenter and node pool
+type CoverageBucket struct {
+	Datacenter string `json:"Datacenter"`
+	NodePool   string `json:"NodePool,omitempty"`
+	Eligible   int    `json:"Eligible"`
+	Covered    int    `json:"Covered"`
+}
+
+// SystemJobCoverage is the coverage report for one system job
+type SystemJobCoverage struct {
+	JobID         string           `json:"JobID"`
+	Namespace     string           `json:"Namespace"`
+	EligibleNodes int              `json:"EligibleNodes"`
+	CoveredNodes  int              `json:"CoveredNodes"`
+	Buckets       []CoverageBucket `json:"Buckets"`
+	Gaps          []CoverageGap    `json:"Gaps"`
+	Error         string           `json:"Error,omitempty"`
+}
+
+// CheckSystemJobCoverageHandler returns a handler that verifies system jobs run on every
+// node they are eligible for
+func CheckSystemJobCoverageHandler(client utils.PlacementAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		namespace := "*"
+		if ns, ok := arguments["namespace"].(string); ok && ns != "" {
+			namespace = ns
+		}
+		jobID, _ := arguments["job_id"].(string)
+
+		summaries, err := client.ListJobs(ctx, namespace, "")
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing jobs: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list jobs", err), nil
+		}
+
+		nodes, err := fetchNodeDetails(ctx, client)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing nodes: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list nodes", err), nil
+		}
+
+		reports := []SystemJobCoverage{}
+		for _, summary := range summaries {
+			if summary.Type != "system" || summary.Status == "dead" || (jobID != "" && summary.ID != jobID) {
+				continue
+			}
+			jobNamespace := summary.Namespace
+			if jobNamespace == "" {
+				jobNamespace = namespace
+			}
+			reports = append(reports, systemJobCoverage(ctx, client, summary.ID, jobNamespace, nodes))
+		}
+		if jobID != "" && len(reports) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("no running system job %s found", jobID)), nil
+		}
+
+		reportsJSON, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format coverage", err), nil
+		}
+
+		return mcp.NewToolResultText(string(reportsJSON)), nil
+	}
+}
+
+// systemJobCoverage compares the nodes a system job is eligible for with the nodes that
+// have a live allocation of each eligible task group.
+func systemJobCoverage(ctx context.Context, client utils.PlacementAPI, jobID, namespace string, nodes []types.NodeDetail) SystemJobCoverage {
+	report := SystemJobCoverage{JobID: jobID, Namespace: namespace, Buckets: []CoverageBucket{}, Gaps: []CoverageGap{}}
+
+	job, err := client.GetJob(ctx, jobID, namespace)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	allocs, err := client.ListJobAllocations(ctx, jobID, namespace)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	running := map[string]bool{}
+	for _, alloc := range allocs {
+		if alloc.DesiredStatus == "run" && alloc.ClientStatus == "running" {
+			running[alloc.NodeID+"/"+alloc.TaskGroup] = true
+		}
+	}
+
+	buckets := map[[2]string]*CoverageBucket{}
+	for _, match := range matchJobNodes(job, nodes).Eligible {
+		key := [2]string{match.Datacenter, match.NodePool}
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &CoverageBucket{Datacenter: match.Datacenter, NodePool: match.NodePool}
+			buckets[key] = bucket
+		}
+		bucket.Eligible++
+		report.EligibleNodes++
+
+		var missing []string
+		for _, group := range match.Groups {
+			if !running[match.NodeID+"/"+group] {
+				missing = append(missing, group)
+			}
+		}
+		if len(missing) > 0 {
+			report.Gaps = append(report.Gaps, CoverageGap{
+				NodeID:     match.NodeID,
+				Name:       match.Name,
+				Datacenter: match.Datacenter,
+				NodePool:   match.NodePool,
+				TaskGroups: missing,
+			})
+			continue
+		}
+		bucket.Covered++
+		report.CoveredNodes++
+	}
+
+	for _, bucket := range buckets {
+		report.Buckets = append(report.Buckets, *bucket)
+	}
+	sort.Slice(report.Buckets, func(i, j int) bool {
+		if report.Buckets[i].Datacenter != report.Buckets[j].Datacenter {
+			return report.Buckets[i].Datacenter < report.Buckets[j].Datacenter
+		}
+		return report.Buckets[i].NodePool < report.Buckets[j].NodePool
+	})
+	sort.Slice(report.Gaps, func(i, j int) bool { return report.Gaps[i].Name < report.Gaps[j].Name })
+	return report
+}
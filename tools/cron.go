@@ -0,0 +1,173 @@
+// File: tools/cron.go
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears bounds the search for the next run of a schedule; a schedule with no run
+// in that time, such as one for February 30, never fires.
+const cronSearchYears = 5
+
+// cronDescriptors are the shorthand schedules accepted in place of the five fields.
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronFields are the five fields of a schedule with their allowed ranges.
+var cronFields = []struct {
+	Name     string
+	Min, Max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	// 7 is accepted as Sunday, like 0.
+	{"day of week", 0, 7},
+}
+
+// CronSchedule is a parsed five-field cron expression (minute, hour, day of month, month,
+// day of week), evaluated in the location of the times given to Next.
+type CronSchedule struct {
+	minutes, hours, days, months, weekdays uint64
+	// As in cron, a day matches either day field when both are restricted.
+	daysRestricted, weekdaysRestricted bool
+}
+
+// ParseCronSchedule parses a cron expression. Each field is *, a value, a range a-b, or a
+// comma-separated list of them, optionally with a /step; @hourly, @daily, @weekly,
+// @monthly and @yearly are accepted too.
+func ParseCronSchedule(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[expr]; ok {
+		expr = descriptor
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron schedule %q must have %d fields (minute hour day-of-month month day-of-week)", expr, len(cronFields))
+	}
+
+	sets := make([]uint64, len(fields))
+	for i, field := range fields {
+		set, err := parseCronField(field, cronFields[i].Min, cronFields[i].Max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s field %q: %v", cronFields[i].Name, field, err)
+		}
+		sets[i] = set
+	}
+	schedule := &CronSchedule{
+		minutes:            sets[0],
+		hours:              sets[1],
+		days:               sets[2],
+		months:             sets[3],
+		weekdays:           sets[4],
+		daysRestricted:     !strings.HasPrefix(fields[2], "*"),
+		weekdaysRestricted: !strings.HasPrefix(fields[4], "*"),
+	}
+	if schedule.weekdays&(1<<7) != 0 {
+		schedule.weekdays |= 1
+	}
+	return schedule, nil
+}
+
+// parseCronField returns the set of values a field matches as a bit set.
+func parseCronField(field string, lo, hi int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("step %q must be a positive number", part[i+1:])
+			}
+			rangePart, step = part[:i], n
+		}
+
+		start, end := lo, hi
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = cronValue(bounds[0], lo, hi); err != nil {
+				return 0, err
+			}
+			if end, err = cronValue(bounds[1], lo, hi); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("range %s is reversed", rangePart)
+			}
+		default:
+			value, err := cronValue(rangePart, lo, hi)
+			if err != nil {
+				return 0, err
+			}
+			start = value
+			// A single value with a step, e.g. 5/15, runs from the value to the maximum.
+			end = value
+			if step > 1 {
+				end = hi
+			}
+		}
+		for v := start; v <= end; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// cronValue parses one number of a field and checks its range.
+func cronValue(text string, lo, hi int) (int, error) {
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", text)
+	}
+	if value < lo || value > hi {
+		return 0, fmt.Errorf("%d is outside %d-%d", value, lo, hi)
+	}
+	return value, nil
+}
+
+// matchesDay reports whether the schedule runs on the day of t.
+func (c *CronSchedule) matchesDay(t time.Time) bool {
+	day := c.days&(1<<t.Day()) != 0
+	weekday := c.weekdays&(1<<int(t.Weekday())) != 0
+	if c.daysRestricted && c.weekdaysRestricted {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+// Next returns the first time after t the schedule fires, in t's location, or the zero time
+// if it never fires.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchYears, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case c.months&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hours&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case c.minutes&(1<<t.Minute()) == 0:
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
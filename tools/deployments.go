@@ -4,37 +4,112 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/kocierik/mcp-nomad/types"
 	"github.com/kocierik/mcp-nomad/utils"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 // RegisterDeploymentTools registers all deployment-related tools
-func RegisterDeploymentTools(s *server.MCPServer, nomadClient utils.DeploymentAPI, logger *log.Logger) {
+func RegisterDeploymentTools(s *server.MCPServer, nomadClient utils.DeploymentToolsDeps, logger *log.Logger) {
 	// List deployments tool
 	listDeploymentsTool := mcp.NewTool("list_deployments",
 		mcp.WithDescription("List all deployments"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("namespace",
 			mcp.Description("The namespace to list deployments from (default: default)"),
 		),
+		sinceOption("deployments updated"),
 	)
 	s.AddTool(listDeploymentsTool, ListDeploymentsHandler(nomadClient, logger))
 
 	// Get deployment tool
 	getDeploymentTool := mcp.NewTool("get_deployment",
 		mcp.WithDescription("Get deployment details by ID"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("deployment_id",
 			mcp.Required(),
 			mcp.Description("The ID of the deployment to retrieve"),
 		),
 	)
 	s.AddTool(getDeploymentTool, GetDeploymentHandler(nomadClient, logger))
+
+	// Explain deployment evaluations tool
+	explainDeploymentEvaluationsTool := mcp.NewTool("explain_deployment_evaluations",
+		mcp.WithDescription("Correlate a deployment with the evaluations of its job version, follow blocked and follow-up evaluation chains, and narrate the scheduler's decisions, including re-evaluation loops where blocked evaluations keep failing to place"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("deployment_id",
+			mcp.Required(),
+			mcp.Description("The ID of the deployment to explain"),
+		),
+	)
+	s.AddTool(explainDeploymentEvaluationsTool, ExplainDeploymentEvaluationsHandler(nomadClient, logger))
+
+	// Promote deployment tool
+	promoteDeploymentTool := mcp.NewTool("promote_deployment",
+		mcp.WithDescription("Promote the canaries of a deployment so the rollout continues to the remaining allocations"),
+		mcp.WithString("deployment_id",
+			mcp.Required(),
+			mcp.Description("The ID of the deployment to promote"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the deployment (default: default)"),
+		),
+		mcp.WithArray("groups",
+			mcp.Description("Task groups to promote (default: all task groups)"),
+			mcp.WithStringItems(),
+		),
+	)
+	s.AddTool(promoteDeploymentTool, PromoteDeploymentHandler(nomadClient, logger))
+
+	// Fail deployment tool
+	failDeploymentTool := mcp.NewTool("fail_deployment",
+		mcp.WithDescription("Mark a deployment as failed; Nomad rolls the job back to its last stable version when the update block sets auto_revert"),
+		mcp.WithString("deployment_id",
+			mcp.Required(),
+			mcp.Description("The ID of the deployment to fail"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the deployment (default: default)"),
+		),
+	)
+	s.AddTool(failDeploymentTool, FailDeploymentHandler(nomadClient, logger))
+
+	// Pause deployment tool
+	pauseDeploymentTool := mcp.NewTool("pause_deployment",
+		mcp.WithDescription("Pause a deployment: no new allocations are placed for it until it is resumed"),
+		mcp.WithString("deployment_id",
+			mcp.Required(),
+			mcp.Description("The ID of the deployment to pause"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the deployment (default: default)"),
+		),
+	)
+	s.AddTool(pauseDeploymentTool, PauseDeploymentHandler(nomadClient, true, logger))
+
+	// Resume deployment tool
+	resumeDeploymentTool := mcp.NewTool("resume_deployment",
+		mcp.WithDescription("Resume a paused deployment"),
+		mcp.WithString("deployment_id",
+			mcp.Required(),
+			mcp.Description("The ID of the deployment to resume"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the deployment (default: default)"),
+		),
+	)
+	s.AddTool(resumeDeploymentTool, PauseDeploymentHandler(nomadClient, false, logger))
 }
 
 // ListDeploymentsHandler returns a handler for listing deployments
-func ListDeploymentsHandler(client utils.DeploymentAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func ListDeploymentsHandler(client utils.DeploymentToolsDeps, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		arguments, ok := request.Params.Arguments.(map[string]interface{})
 		if !ok {
@@ -42,13 +117,35 @@ func ListDeploymentsHandler(client utils.DeploymentAPI, logger *log.Logger) func
 		}
 
 		namespace := utils.EffectiveToolNamespace(arguments)
+		cutoff, err := sinceArgument(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 
 		deployments, err := client.ListDeployments(ctx, namespace)
 		if err != nil {
-			logger.Printf("Error listing deployments: %v", err)
+			requestLogf(ctx, logger, "Error listing deployments: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to list deployments", err), nil
 		}
 
+		if !cutoff.IsZero() {
+			// Deployments carry no timestamps, so the window is converted to a Raft index.
+			evaluations, err := client.ListEvaluations(ctx, namespace, "", "")
+			if err != nil {
+				requestLogf(ctx, logger, "Error listing evaluations: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to list evaluations", err), nil
+			}
+			minIndex, ok := sinceIndex(evaluations, cutoff)
+
+			recent := []types.DeploymentSummary{}
+			for _, deployment := range deployments {
+				if ok && deployment.ModifyIndex >= minIndex {
+					recent = append(recent, deployment)
+				}
+			}
+			deployments = recent
+		}
+
 		deploymentsJSON, err := json.MarshalIndent(deployments, "", "  ")
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Failed to format deployments", err), nil
@@ -73,7 +170,7 @@ func GetDeploymentHandler(client utils.DeploymentAPI, logger *log.Logger) func(c
 
 		deployment, err := client.GetDeployment(ctx, deploymentID)
 		if err != nil {
-			logger.Printf("Error getting deployment: %v", err)
+			requestLogf(ctx, logger, "Error getting deployment: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get deployment", err), nil
 		}
 
@@ -85,3 +182,342 @@ func GetDeploymentHandler(client utils.DeploymentAPI, logger *log.Logger) func(c
 		return mcp.NewToolResultText(string(deploymentJSON)), nil
 	}
 }
+
+// PromoteDeploymentHandler returns a handler for promoting a deployment's canaries
+func PromoteDeploymentHandler(client utils.DeploymentControlAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		deploymentID, ok := arguments["deployment_id"].(string)
+		if !ok || deploymentID == "" {
+			return mcp.NewToolResultError("deployment_id is required"), nil
+		}
+		groups, err := stringListArgument(arguments, "groups")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		response, err := client.PromoteDeployment(ctx, deploymentID, utils.EffectiveToolNamespace(arguments), groups)
+		if err != nil {
+			requestLogf(ctx, logger, "Error promoting deployment: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to promote deployment", err), nil
+		}
+		return deploymentUpdateResult(response)
+	}
+}
+
+// FailDeploymentHandler returns a handler for failing a deployment
+func FailDeploymentHandler(client utils.DeploymentControlAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		deploymentID, ok := arguments["deployment_id"].(string)
+		if !ok || deploymentID == "" {
+			return mcp.NewToolResultError("deployment_id is required"), nil
+		}
+
+		response, err := client.FailDeployment(ctx, deploymentID, utils.EffectiveToolNamespace(arguments))
+		if err != nil {
+			requestLogf(ctx, logger, "Error failing deployment: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to fail deployment", err), nil
+		}
+		return deploymentUpdateResult(response)
+	}
+}
+
+// PauseDeploymentHandler returns a handler for pausing a deployment, or resuming it when
+// pause is false
+func PauseDeploymentHandler(client utils.DeploymentControlAPI, pause bool, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	action := "pause"
+	if !pause {
+		action = "resume"
+	}
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		deploymentID, ok := arguments["deployment_id"].(string)
+		if !ok || deploymentID == "" {
+			return mcp.NewToolResultError("deployment_id is required"), nil
+		}
+
+		response, err := client.PauseDeployment(ctx, deploymentID, utils.EffectiveToolNamespace(arguments), pause)
+		if err != nil {
+			requestLogf(ctx, logger, "Error updating deployment (%s): %v", action, err)
+			return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Failed to %s deployment", action), err), nil
+		}
+		return deploymentUpdateResult(response)
+	}
+}
+
+func deploymentUpdateResult(response types.DeploymentUpdateResponse) (*mcp.CallToolResult, error) {
+	responseJSON, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format result", err), nil
+	}
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// sinceIndex converts a cutoff time to a Raft index: the lowest CreateIndex of the evaluations
+// created at or after cutoff. Nomad has no time-to-index API and evaluations record both, so
+// the result is approximate once older evaluations are garbage collected. ok is false when no
+// evaluation was created since cutoff, i.e. nothing was scheduled in the window.
+func sinceIndex(evaluations []types.Evaluation, cutoff time.Time) (uint64, bool) {
+	var minIndex uint64
+	found := false
+	for _, eval := range evaluations {
+		if eval.CreateTime < cutoff.UnixNano() {
+			continue
+		}
+		if index := uint64(eval.CreateIndex); !found || index < minIndex {
+			minIndex, found = index, true
+		}
+	}
+	return minIndex, found
+}
+
+// evaluationTriggers describes Nomad's evaluation triggers for explain_deployment_evaluations.
+var evaluationTriggers = map[string]string{
+	"job-register":           "the job being registered",
+	"job-deregister":         "the job being stopped",
+	"periodic-job":           "a periodic launch",
+	"node-drain":             "a node drain",
+	"node-update":            "a node status or eligibility change",
+	"alloc-stop":             "an allocation being stopped",
+	"alloc-failure":          "an allocation failing and being rescheduled",
+	"deployment-watcher":     "the deployment watcher reacting to allocation health or a promotion",
+	"rolling-update":         "the next rolling update batch",
+	"queued-allocs":          "allocations still queued for capacity",
+	"failed-follow-up":       "a follow-up to a failed evaluation",
+	"max-plan-attempts":      "the plan being rejected too many times",
+	"preemption":             "allocations being preempted",
+	"job-scaling":            "the job being scaled",
+	"reconnect":              "a disconnected client reconnecting",
+	"max-disconnect-timeout": "a disconnected client exceeding its timeout",
+}
+
+// EvaluationStep is one scheduler decision in a deployment's evaluation history
+type EvaluationStep struct {
+	EvalID      string `json:"EvalID"`
+	TriggeredBy string `json:"TriggeredBy"`
+	Status      string `json:"Status"`
+	CreateTime  string `json:"CreateTime,omitempty"`
+	Explanation string `json:"Explanation"`
+}
+
+// DeploymentEvaluations is the explain_deployment_evaluations response
+type DeploymentEvaluations struct {
+	DeploymentID string           `json:"DeploymentID"`
+	JobID        string           `json:"JobID"`
+	JobVersion   int              `json:"JobVersion"`
+	Status       string           `json:"Status"`
+	Steps        []EvaluationStep `json:"Steps"`
+	BlockedLoops int              `json:"BlockedLoops"`
+	Narrative    string           `json:"Narrative"`
+}
+
+// ExplainDeploymentEvaluationsHandler returns a handler for narrating a deployment's evaluations
+func ExplainDeploymentEvaluationsHandler(client utils.DeploymentToolsDeps, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		deploymentID, ok := arguments["deployment_id"].(string)
+		if !ok || deploymentID == "" {
+			return mcp.NewToolResultError("deployment_id is required"), nil
+		}
+
+		deployment, err := client.GetDeployment(ctx, deploymentID)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting deployment: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get deployment", err), nil
+		}
+
+		evaluations, err := client.ListEvaluations(ctx, deployment.Namespace, "", deployment.JobID)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing evaluations: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list evaluations", err), nil
+		}
+
+		explanation := explainDeploymentEvaluations(deployment, deploymentEvaluations(deployment, evaluations))
+
+		explanationJSON, err := json.MarshalIndent(explanation, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format explanation", err), nil
+		}
+
+		return mcp.NewToolResultText(string(explanationJSON)), nil
+	}
+}
+
+// deploymentEvaluations picks the evaluations behind a deployment: those created by its
+// deployment watcher or for its job version, plus every evaluation linked to them through
+// previous, next or blocked references. The result is ordered by CreateIndex.
+func deploymentEvaluations(deployment types.Deployment, evaluations []types.Evaluation) []types.Evaluation {
+	related := map[string]bool{}
+	for _, eval := range evaluations {
+		if eval.DeploymentID == deployment.ID || (deployment.JobModifyIndex != 0 && eval.JobModifyIndex == deployment.JobModifyIndex) {
+			related[eval.ID] = true
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, eval := range evaluations {
+			if related[eval.ID] {
+				for _, linked := range []string{eval.PreviousEvalID, eval.NextEvalID, eval.BlockedEvalID} {
+					if linked != "" && !related[linked] {
+						related[linked] = true
+						changed = true
+					}
+				}
+				continue
+			}
+			if related[eval.PreviousEvalID] || related[eval.NextEvalID] || related[eval.BlockedEvalID] {
+				related[eval.ID] = true
+				changed = true
+			}
+		}
+	}
+
+	chain := []types.Evaluation{}
+	for _, eval := range evaluations {
+		if related[eval.ID] {
+			chain = append(chain, eval)
+		}
+	}
+	sort.Slice(chain, func(i, j int) bool { return chain[i].CreateIndex < chain[j].CreateIndex })
+	return chain
+}
+
+// explainDeploymentEvaluations turns an ordered evaluation chain into steps and a narrative.
+// A blocked loop is a retry of queued allocations that itself had to block again.
+func explainDeploymentEvaluations(deployment types.Deployment, evaluations []types.Evaluation) DeploymentEvaluations {
+	explanation := DeploymentEvaluations{
+		DeploymentID: deployment.ID,
+		JobID:        deployment.JobID,
+		JobVersion:   deployment.JobVersion,
+		Status:       deployment.Status,
+		Steps:        []EvaluationStep{},
+	}
+
+	var narrative []string
+	for _, eval := range evaluations {
+		step := EvaluationStep{
+			EvalID:      eval.ID,
+			TriggeredBy: eval.TriggeredBy,
+			Status:      eval.Status,
+			Explanation: explainEvaluation(eval),
+		}
+		if eval.CreateTime != 0 {
+			step.CreateTime = time.Unix(0, eval.CreateTime).UTC().Format(time.RFC3339)
+		}
+		if eval.TriggeredBy == "queued-allocs" && eval.BlockedEvalID != "" {
+			explanation.BlockedLoops++
+		}
+		explanation.Steps = append(explanation.Steps, step)
+		narrative = append(narrative, step.Explanation)
+	}
+
+	if len(evaluations) == 0 {
+		narrative = append(narrative, "No evaluations for this deployment remain; they may have been garbage collected.")
+	}
+	if explanation.BlockedLoops > 0 {
+		narrative = append(narrative, fmt.Sprintf("The scheduler retried queued allocations %d time(s) and each retry blocked again: the cluster still lacks a feasible node with enough capacity, so check the placement failures above.", explanation.BlockedLoops))
+	}
+	outcome := fmt.Sprintf("The deployment is %s", deployment.Status)
+	if deployment.StatusDescription != "" {
+		outcome += ": " + deployment.StatusDescription
+	}
+	narrative = append(narrative, strings.TrimSuffix(outcome, ".")+".")
+
+	explanation.Narrative = strings.Join(narrative, " ")
+	return explanation
+}
+
+// explainEvaluation describes one evaluation in a sentence or two.
+func explainEvaluation(eval types.Evaluation) string {
+	trigger, ok := evaluationTriggers[eval.TriggeredBy]
+	if !ok {
+		trigger = eval.TriggeredBy
+	}
+	shortID := eval.ID
+	if len(shortID) > 8 {
+		shortID = shortID[:8]
+	}
+	text := fmt.Sprintf("Evaluation %s was triggered by %s", shortID, trigger)
+
+	switch eval.Status {
+	case "complete":
+		if len(eval.FailedTGAllocs) == 0 {
+			text += " and placed or updated everything it planned."
+		} else {
+			text += " but could not place " + describePlacementFailures(eval.FailedTGAllocs) + "."
+		}
+	case "blocked":
+		text += " and is blocked until capacity frees up"
+		if eval.EscapedComputedClass {
+			text += "; its constraints escape node classes, so any node change unblocks it."
+		} else {
+			text += "; only changes to eligible node classes unblock it."
+		}
+	case "failed":
+		text += " and failed"
+		if eval.StatusDescription != "" {
+			text += ": " + eval.StatusDescription
+		}
+		text += "."
+	case "canceled":
+		text += " and was canceled because a newer evaluation superseded it."
+	default:
+		text += fmt.Sprintf(" and is %s.", eval.Status)
+	}
+
+	if eval.BlockedEvalID != "" {
+		text += fmt.Sprintf(" It created blocked evaluation %s to retry the unplaced allocations.", eval.BlockedEvalID)
+	}
+	if eval.NextEvalID != "" {
+		text += fmt.Sprintf(" It scheduled follow-up evaluation %s.", eval.NextEvalID)
+	}
+	return text
+}
+
+// describePlacementFailures summarises FailedTGAllocs: groups and exhausted dimensions.
+func describePlacementFailures(failed map[string]interface{}) string {
+	groups := make([]string, 0, len(failed))
+	for group := range failed {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	parts := make([]string, 0, len(groups))
+	for _, group := range groups {
+		part := "group " + group
+		metric, _ := failed[group].(map[string]interface{})
+		var reasons []string
+		for _, key := range []string{"DimensionExhausted", "ConstraintFiltered", "ClassFiltered"} {
+			counts, _ := metric[key].(map[string]interface{})
+			names := make([]string, 0, len(counts))
+			for name := range counts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			reasons = append(reasons, names...)
+		}
+		if len(reasons) > 0 {
+			part += " (" + strings.Join(reasons, ", ") + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
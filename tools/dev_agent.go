@@ -0,0 +1,255 @@
+// File: tools/dev_agent.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// devAgentListen is where nomad agent -dev serves its HTTP API when bound to loopback.
+	devAgentListen  = "127.0.0.1:4646"
+	devAgentAddress = "http://" + devAgentListen
+	// devAgentStopGrace is how long a stopping dev agent may take to shut down before it is killed.
+	devAgentStopGrace = 15 * time.Second
+	devAgentPoll      = 500 * time.Millisecond
+)
+
+// DevAgentStatus is the result of start_dev_agent and stop_dev_agent
+type DevAgentStatus struct {
+	Running         bool   `json:"Running"`
+	PID             int    `json:"PID,omitempty"`
+	Address         string `json:"Address"`
+	PreviousAddress string `json:"PreviousAddress,omitempty"`
+	Leader          string `json:"Leader,omitempty"`
+	LogFile         string `json:"LogFile,omitempty"`
+	Summary         string `json:"Summary"`
+}
+
+// AddressState is server state about the Nomad cluster the client talks to, dropped when the
+// dev agent points the client at another address.
+type AddressState interface {
+	ForgetAddress()
+}
+
+// DevAgent runs a local `nomad agent -dev` as a child process of the server and points the
+// client at it while it runs.
+type DevAgent struct {
+	client utils.DevAgentAPI
+	binary string
+	logger *log.Logger
+	states []AddressState
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	exited   chan struct{}
+	previous string
+	logFile  string
+}
+
+// NewDevAgent returns a dev agent runner starting binary, usually "nomad" from PATH. states are
+// told whenever the client is pointed at another address.
+func NewDevAgent(client utils.DevAgentAPI, binary string, logger *log.Logger, states ...AddressState) *DevAgent {
+	return &DevAgent{client: client, binary: binary, logger: logger, states: states}
+}
+
+// setAddress points the client at address and drops the state kept about the previous one.
+func (a *DevAgent) setAddress(address string) {
+	a.client.SetAddress(address)
+	for _, state := range a.states {
+		state.ForgetAddress()
+	}
+}
+
+// Start launches the dev agent, points the client at it and waits up to timeout for it to
+// elect itself leader.
+func (a *DevAgent) Start(ctx context.Context, timeout time.Duration) (DevAgentStatus, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cmd != nil {
+		return a.status("The dev agent is already running."), nil
+	}
+
+	path, err := exec.LookPath(a.binary)
+	if err != nil {
+		return DevAgentStatus{}, fmt.Errorf("cannot find the nomad binary: %w", err)
+	}
+	// Otherwise the readiness check below would talk to whatever already listens there.
+	if conn, err := net.DialTimeout("tcp", devAgentListen, time.Second); err == nil {
+		conn.Close()
+		return DevAgentStatus{}, fmt.Errorf("%s is already in use, e.g. by another Nomad agent", devAgentListen)
+	}
+
+	logFile, err := os.CreateTemp("", "nomad-dev-agent-*.log")
+	if err != nil {
+		return DevAgentStatus{}, err
+	}
+	// Not tied to ctx: the agent outlives the tool call that starts it.
+	cmd := exec.Command(path, "agent", "-dev", "-bind=127.0.0.1")
+	cmd.Stdout, cmd.Stderr = logFile, logFile
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return DevAgentStatus{}, fmt.Errorf("cannot start %s: %w", path, err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		logFile.Close()
+		close(exited)
+	}()
+
+	a.cmd, a.exited, a.logFile = cmd, exited, logFile.Name()
+	a.previous = a.client.GetAddress()
+	a.setAddress(devAgentAddress)
+	a.logger.Printf("Started nomad agent -dev (pid %d), logging to %s", cmd.Process.Pid, a.logFile)
+
+	leader, err := a.waitForLeader(ctx, timeout)
+	if err != nil {
+		a.stopLocked()
+		return DevAgentStatus{}, fmt.Errorf("%v; the agent was stopped, see %s", err, logFile.Name())
+	}
+	status := a.status(fmt.Sprintf("The dev agent is running and every tool now talks to it at %s. It keeps its state in memory only; stop_dev_agent shuts it down and points the server back at %s.", devAgentAddress, a.previous))
+	status.Leader = leader
+	return status, nil
+}
+
+func (a *DevAgent) waitForLeader(ctx context.Context, timeout time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		if leader, err := a.client.GetLeader(ctx); err == nil && leader != "" {
+			return leader, nil
+		}
+		if !time.Now().Before(deadline) {
+			return "", fmt.Errorf("the dev agent elected no leader within %s", timeout)
+		}
+		select {
+		case <-a.exited:
+			return "", errors.New("the dev agent exited during startup")
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(devAgentPoll):
+		}
+	}
+}
+
+// Stop shuts the dev agent down and points the client back at its previous address.
+func (a *DevAgent) Stop() (DevAgentStatus, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cmd == nil {
+		return DevAgentStatus{}, errors.New("no dev agent was started by this server")
+	}
+	logFile := a.logFile
+	a.stopLocked()
+	status := a.status(fmt.Sprintf("The dev agent was stopped and its state is gone; tools talk to %s again.", a.client.GetAddress()))
+	status.LogFile = logFile
+	return status, nil
+}
+
+// Close stops the dev agent, if any, when the server exits.
+func (a *DevAgent) Close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cmd != nil {
+		a.stopLocked()
+	}
+}
+
+func (a *DevAgent) stopLocked() {
+	_ = a.cmd.Process.Signal(os.Interrupt)
+	select {
+	case <-a.exited:
+	case <-time.After(devAgentStopGrace):
+		_ = a.cmd.Process.Kill()
+		<-a.exited
+	}
+	a.logger.Printf("Stopped nomad agent -dev (pid %d)", a.cmd.Process.Pid)
+	a.setAddress(a.previous)
+	a.cmd, a.exited, a.logFile = nil, nil, ""
+}
+
+func (a *DevAgent) status(summary string) DevAgentStatus {
+	status := DevAgentStatus{Address: a.client.GetAddress(), Summary: summary}
+	if a.cmd != nil {
+		status.Running, status.PID, status.LogFile = true, a.cmd.Process.Pid, a.logFile
+		status.PreviousAddress = a.previous
+	}
+	return status
+}
+
+// RegisterDevAgentTools registers the tools running a local Nomad dev agent. They are only
+// registered with -enable-dev-agent.
+func RegisterDevAgentTools(s *server.MCPServer, agent *DevAgent, logger *log.Logger) {
+	startDevAgentTool := mcp.NewTool("start_dev_agent",
+		mcp.WithDescription("Start a local Nomad agent in dev mode (nomad agent -dev, bound to 127.0.0.1) on the machine running this server and point every tool at it until stop_dev_agent. Its state lives in memory only; for demos and tests"),
+		mcp.WithString("timeout",
+			mcp.Description("How long to wait for the agent to elect itself leader, e.g. 30s (default: 30s)"),
+		),
+	)
+	s.AddTool(startDevAgentTool, StartDevAgentHandler(agent, logger))
+
+	stopDevAgentTool := mcp.NewTool("stop_dev_agent",
+		mcp.WithDescription("Stop the dev agent started by start_dev_agent, losing its state, and point the tools back at the configured Nomad address"),
+		mcp.WithDestructiveHintAnnotation(true),
+	)
+	s.AddTool(stopDevAgentTool, StopDevAgentHandler(agent, logger))
+}
+
+// StartDevAgentHandler returns a handler that starts the local dev agent
+func StartDevAgentHandler(agent *DevAgent, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		timeout := 30 * time.Second
+		if t, _ := arguments["timeout"].(string); strings.TrimSpace(t) != "" {
+			parsed, err := time.ParseDuration(strings.TrimSpace(t))
+			if err != nil || parsed <= 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("timeout must be a positive duration, e.g. 30s: %q", t)), nil
+			}
+			timeout = parsed
+		}
+
+		status, err := agent.Start(ctx, timeout)
+		if err != nil {
+			requestLogf(ctx, logger, "Error starting dev agent: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to start dev agent", err), nil
+		}
+		return devAgentResult(status)
+	}
+}
+
+// StopDevAgentHandler returns a handler that stops the local dev agent
+func StopDevAgentHandler(agent *DevAgent, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		status, err := agent.Stop()
+		if err != nil {
+			requestLogf(ctx, logger, "Error stopping dev agent: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to stop dev agent", err), nil
+		}
+		return devAgentResult(status)
+	}
+}
+
+func devAgentResult(status DevAgentStatus) (*mcp.CallToolResult, error) {
+	statusJSON, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format dev agent status", err), nil
+	}
+	return mcp.NewToolResultText(string(statusJSON)), nil
+}
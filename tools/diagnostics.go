@@ -0,0 +1,40 @@
+// File: tools/diagnostics.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RegisterDiagnosticTools registers connection troubleshooting tools
+func RegisterDiagnosticTools(s *server.MCPServer, nomadClient utils.DiagnosticsAPI, logger *log.Logger) {
+	// Diagnose connection tool
+	diagnoseConnectionTool := mcp.NewTool("diagnose_connection",
+		mcp.WithDescription("Check that the MCP server can use Nomad: API reachability and leader, TLS certificate validity, ACL token validity (acl/token/self), and which API families return 403 for the configured token. Returns a readiness matrix with the issues found"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+	s.AddTool(diagnoseConnectionTool, DiagnoseConnectionHandler(nomadClient, logger))
+}
+
+// DiagnoseConnectionHandler returns a handler for diagnosing the Nomad connection
+func DiagnoseConnectionHandler(client utils.DiagnosticsAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		diagnosis, err := client.DiagnoseConnection(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error diagnosing connection: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to diagnose connection", err), nil
+		}
+
+		diagnosisJSON, err := json.MarshalIndent(diagnosis, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format diagnosis", err), nil
+		}
+
+		return mcp.NewToolResultText(string(diagnosisJSON)), nil
+	}
+}
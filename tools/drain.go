@@ -0,0 +1,103 @@
+// File: tools/drain.go
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+)
+
+// DrainRisk is a task group that would have no running allocation while a node drains
+type DrainRisk struct {
+	JobID     string `json:"JobID"`
+	Namespace string `json:"Namespace"`
+	JobType   string `json:"JobType,omitempty"`
+	TaskGroup string `json:"TaskGroup"`
+	OnNode    int    `json:"OnNode"`
+	Elsewhere int    `json:"Elsewhere"`
+	Reason    string `json:"Reason"`
+}
+
+// isLiveAllocation reports whether an allocation is running or about to run.
+func isLiveAllocation(alloc types.Allocation) bool {
+	return alloc.DesiredStatus == "run" && (alloc.ClientStatus == "running" || alloc.ClientStatus == "pending")
+}
+
+// drainPreflight finds task groups whose live allocations all run on nodeID, so draining the
+// node would take them down until replacements are placed. System jobs are skipped: they run
+// on every node by design.
+func drainPreflight(ctx context.Context, client utils.NodeToolsDeps, nodeID string) ([]DrainRisk, error) {
+	allocs, err := client.ListNodeAllocations(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	type groupKey struct{ namespace, jobID, group string }
+	onNode := map[groupKey]int{}
+	jobTypes := map[groupKey]string{}
+	for _, alloc := range allocs {
+		if !isLiveAllocation(alloc) || alloc.JobType == "system" || alloc.JobType == "sysbatch" {
+			continue
+		}
+		namespace := alloc.Namespace
+		if namespace == "" {
+			namespace = utils.NomadDefaultNamespace
+		}
+		key := groupKey{namespace, alloc.JobID, alloc.TaskGroup}
+		onNode[key]++
+		jobTypes[key] = alloc.JobType
+	}
+
+	jobAllocs := map[string][]types.Allocation{}
+	risks := []DrainRisk{}
+	for key, count := range onNode {
+		risk := DrainRisk{
+			JobID:     key.jobID,
+			Namespace: key.namespace,
+			JobType:   jobTypes[key],
+			TaskGroup: key.group,
+			OnNode:    count,
+		}
+
+		jobKey := key.namespace + "/" + key.jobID
+		all, ok := jobAllocs[jobKey]
+		if !ok {
+			all, err = client.ListJobAllocations(ctx, key.jobID, key.namespace)
+			if err != nil {
+				risk.Reason = fmt.Sprintf("could not check allocations on other nodes: %v", err)
+				risks = append(risks, risk)
+				continue
+			}
+			jobAllocs[jobKey] = all
+		}
+
+		for _, alloc := range all {
+			if alloc.TaskGroup == key.group && alloc.NodeID != nodeID && isLiveAllocation(alloc) {
+				risk.Elsewhere++
+			}
+		}
+		if risk.Elsewhere > 0 {
+			continue
+		}
+		if count == 1 {
+			risk.Reason = "single instance: its only allocation runs on this node"
+		} else {
+			risk.Reason = fmt.Sprintf("all %d allocations run on this node", count)
+		}
+		risks = append(risks, risk)
+	}
+
+	sort.Slice(risks, func(i, j int) bool {
+		if risks[i].Namespace != risks[j].Namespace {
+			return risks[i].Namespace < risks[j].Namespace
+		}
+		if risks[i].JobID != risks[j].JobID {
+			return risks[i].JobID < risks[j].JobID
+		}
+		return risks[i].TaskGroup < risks[j].TaskGroup
+	})
+	return risks, nil
+}
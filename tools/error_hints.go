@@ -0,0 +1,125 @@
+// File: tools/error_hints.go
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ErrorHint tells the caller how a failed tool call can be corrected
+type ErrorHint struct {
+	// Kind is namespace, region, token_expired or token_invalid
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	// Retry holds the arguments to change when retrying, when the fix is that simple
+	Retry map[string]any `json:"retry,omitempty"`
+}
+
+// ErrorHintsMiddleware returns a tool middleware that adds machine-actionable hints to error
+// results caused by a wrong namespace, a wrong region or an expired or unknown ACL token.
+// Hints are attached to the result _meta as "hints" and appended as a text block. Looking
+// up a hint costs at most one extra Nomad request and only happens after a failure.
+func ErrorHintsMiddleware(client utils.ErrorHintAPI) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil || !result.IsError {
+				return result, err
+			}
+
+			hints := errorHints(ctx, client, request, errorResultText(result))
+			if len(hints) == 0 {
+				return result, nil
+			}
+
+			if result.Meta == nil {
+				result.Meta = &mcp.Meta{}
+			}
+			if result.Meta.AdditionalFields == nil {
+				result.Meta.AdditionalFields = map[string]any{}
+			}
+			result.Meta.AdditionalFields["hints"] = hints
+			for _, hint := range hints {
+				result.Content = append(result.Content, mcp.NewTextContent("Hint: "+hint.Message))
+			}
+			return result, nil
+		}
+	}
+}
+
+// errorResultText returns the first text block of an error result.
+func errorResultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+// errorHints recognizes the failures a caller can fix by itself from Nomad's error text.
+func errorHints(ctx context.Context, client utils.ErrorHintAPI, request mcp.CallToolRequest, text string) []ErrorHint {
+	arguments := request.GetArguments()
+	switch {
+	case strings.Contains(text, "ACL token expired"):
+		return []ErrorHint{{Kind: "token_expired", Message: "The ACL token has expired; supply a new token (NOMAD_TOKEN or the Authorization header) and retry"}}
+	case strings.Contains(text, "ACL token not found"):
+		return []ErrorHint{{Kind: "token_invalid", Message: "Nomad does not know the ACL token; check that it belongs to this cluster and has not been deleted"}}
+	case strings.Contains(text, "No path to region"):
+		return regionHints(ctx, client)
+	case strings.Contains(text, "HTTP 404"):
+		jobID, _ := arguments["job_id"].(string)
+		if jobID == "" || utils.EffectiveToolNamespace(arguments) == "*" {
+			return nil
+		}
+		return namespaceHints(ctx, client, jobID, utils.EffectiveToolNamespace(arguments))
+	}
+	return nil
+}
+
+// namespaceHints looks for a job that was not found in the requested namespace in all the
+// namespaces the token can read.
+func namespaceHints(ctx context.Context, client utils.ErrorHintAPI, jobID, namespace string) []ErrorHint {
+	jobs, err := client.ListJobs(ctx, "*", "")
+	if err != nil {
+		return nil
+	}
+
+	var namespaces []string
+	for _, job := range jobs {
+		if job.ID == jobID && job.Namespace != namespace {
+			namespaces = append(namespaces, job.Namespace)
+		}
+	}
+	slices.Sort(namespaces)
+
+	hints := make([]ErrorHint, 0, len(namespaces))
+	for _, ns := range namespaces {
+		hints = append(hints, ErrorHint{
+			Kind:    "namespace",
+			Message: fmt.Sprintf("Job %s exists in namespace %s; retry with namespace=%s", jobID, ns, ns),
+			Retry:   map[string]any{"namespace": ns},
+		})
+	}
+	return hints
+}
+
+// regionHints lists the regions Nomad knows; every server can answer that without
+// forwarding, so it works while the requested region is unreachable.
+func regionHints(ctx context.Context, client utils.ErrorHintAPI) []ErrorHint {
+	regions, err := client.ListRegions(ctx)
+	if err != nil || len(regions) == 0 {
+		return nil
+	}
+	slices.Sort(regions)
+
+	message := fmt.Sprintf("Region %q is not part of this federation; known regions: %s. Retry after set_session_defaults with one of them",
+		utils.RegionFromContext(ctx), strings.Join(regions, ", "))
+	return []ErrorHint{{Kind: "region", Message: message, Retry: map[string]any{"region": regions[0]}}}
+}
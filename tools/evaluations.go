@@ -0,0 +1,277 @@
+// File: tools/evaluations.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RegisterEvaluationTools registers all evaluation-related tools
+func RegisterEvaluationTools(s *server.MCPServer, nomadClient utils.EvaluationAPI, logger *log.Logger) {
+	// List evaluations tool
+	listEvaluationsTool := mcp.NewTool("list_evaluations",
+		mcp.WithDescription("List evaluations, filtered server-side by status, job and trigger type. Use status=blocked or status=failed to surface stuck scheduling work, and aggregate=true to see which triggers drive scheduler load"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("status",
+			mcp.Description("Only return evaluations with this status"),
+			mcp.Enum("blocked", "failed", "pending", "complete", "canceled"),
+		),
+		mcp.WithString("job_id",
+			mcp.Description("Only return evaluations for this job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace to list evaluations from (default: default, * for all)"),
+		),
+		mcp.WithArray("triggered_by",
+			mcp.Description("Only return evaluations with one of these triggers, e.g. job-register, node-update, deployment-watcher, periodic-job, alloc-failure"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("min_priority",
+			mcp.Description("Only return evaluations with at least this priority"),
+		),
+		mcp.WithBoolean("aggregate",
+			mcp.Description("Return counts per trigger type (with status breakdown and priority range) instead of the evaluations, for scheduler load analysis (default: false)"),
+		),
+		mcp.WithString("filter",
+			mcp.Description("A Nomad filter expression applied server-side in addition to the other filters, e.g. NodeID == \"<id>\" or DeploymentID is not empty"),
+		),
+		sinceOption("evaluations created or updated"),
+	)
+	s.AddTool(listEvaluationsTool, ListEvaluationsHandler(nomadClient, logger))
+
+	// Get evaluation tool
+	getEvaluationTool := mcp.NewTool("get_evaluation",
+		mcp.WithDescription("Get evaluation details by ID, including placement failures and queued allocations"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("evaluation_id",
+			mcp.Required(),
+			mcp.Description("The ID of the evaluation to retrieve"),
+		),
+	)
+	s.AddTool(getEvaluationTool, GetEvaluationHandler(nomadClient, logger))
+
+	// Get evaluation allocations tool
+	getEvaluationAllocationsTool := mcp.NewTool("get_evaluation_allocations",
+		mcp.WithDescription("List the allocations an evaluation placed or updated"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("evaluation_id",
+			mcp.Required(),
+			mcp.Description("The ID of the evaluation"),
+		),
+	)
+	s.AddTool(getEvaluationAllocationsTool, GetEvaluationAllocationsHandler(nomadClient, logger))
+
+	// Delete evaluation tool
+	deleteEvaluationTool := mcp.NewTool("delete_evaluation",
+		mcp.WithDescription("Delete an evaluation from the scheduler's state, e.g. to clear a backlog of stuck evaluations. Nomad only allows this with a management token while the scheduler is paused"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("evaluation_id",
+			mcp.Required(),
+			mcp.Description("The ID of the evaluation to delete"),
+		),
+	)
+	s.AddTool(deleteEvaluationTool, DeleteEvaluationHandler(nomadClient, logger))
+}
+
+// ListEvaluationsHandler returns a handler for listing evaluations
+func ListEvaluationsHandler(client utils.EvaluationAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		namespace := utils.EffectiveToolNamespace(arguments)
+		status, _ := arguments["status"].(string)
+		jobID, _ := arguments["job_id"].(string)
+		cutoff, err := sinceArgument(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		triggeredBy, err := stringListArgument(arguments, "triggered_by")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		minPriority := 0
+		if p, ok := arguments["min_priority"].(float64); ok {
+			minPriority = int(p)
+		}
+		aggregate, _ := arguments["aggregate"].(bool)
+
+		expression, _ := arguments["filter"].(string)
+
+		filter := types.EvaluationFilter{Status: status, JobID: jobID, TriggeredBy: triggeredBy, Expression: expression}
+		evaluations, err := client.FilterEvaluations(ctx, namespace, filter)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing evaluations: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list evaluations", err), nil
+		}
+
+		if !cutoff.IsZero() {
+			recent := []types.Evaluation{}
+			for _, eval := range evaluations {
+				if eval.ModifyTime >= cutoff.UnixNano() {
+					recent = append(recent, eval)
+				}
+			}
+			evaluations = recent
+		}
+
+		// The filter language has no ordering operators, so priority is applied here.
+		if minPriority > 0 {
+			prioritized := []types.Evaluation{}
+			for _, eval := range evaluations {
+				if eval.Priority >= minPriority {
+					prioritized = append(prioritized, eval)
+				}
+			}
+			evaluations = prioritized
+		}
+
+		if aggregate {
+			loadJSON, err := json.MarshalIndent(aggregateEvaluationTriggers(evaluations), "", "  ")
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("Failed to format evaluation load", err), nil
+			}
+			return mcp.NewToolResultText(string(loadJSON)), nil
+		}
+
+		evaluationsJSON, err := json.MarshalIndent(evaluations, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format evaluations", err), nil
+		}
+
+		return mcp.NewToolResultText(string(evaluationsJSON)), nil
+	}
+}
+
+// GetEvaluationHandler returns a handler for getting evaluation details
+func GetEvaluationHandler(client utils.EvaluationAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		evalID, ok := arguments["evaluation_id"].(string)
+		if !ok || evalID == "" {
+			return mcp.NewToolResultError("evaluation_id is required"), nil
+		}
+
+		evaluation, err := client.GetEvaluation(ctx, evalID)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting evaluation: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get evaluation", err), nil
+		}
+
+		evaluationJSON, err := json.MarshalIndent(evaluation, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format evaluation", err), nil
+		}
+
+		return mcp.NewToolResultText(string(evaluationJSON)), nil
+	}
+}
+
+// GetEvaluationAllocationsHandler returns a handler for listing an evaluation's allocations
+func GetEvaluationAllocationsHandler(client utils.EvaluationAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		evalID, ok := arguments["evaluation_id"].(string)
+		if !ok || evalID == "" {
+			return mcp.NewToolResultError("evaluation_id is required"), nil
+		}
+
+		allocations, err := client.ListEvaluationAllocations(ctx, evalID)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing evaluation allocations: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get evaluation allocations", err), nil
+		}
+
+		allocationsJSON, err := json.MarshalIndent(allocations, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format allocations", err), nil
+		}
+
+		return mcp.NewToolResultText(string(allocationsJSON)), nil
+	}
+}
+
+// DeleteEvaluationHandler returns a handler for deleting an evaluation
+func DeleteEvaluationHandler(client utils.EvaluationAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		evalID, ok := arguments["evaluation_id"].(string)
+		if !ok || evalID == "" {
+			return mcp.NewToolResultError("evaluation_id is required"), nil
+		}
+
+		if err := client.DeleteEvaluations(ctx, []string{evalID}); err != nil {
+			requestLogf(ctx, logger, "Error deleting evaluation: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to delete evaluation", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Evaluation %s deleted successfully", evalID)), nil
+	}
+}
+
+// EvaluationTriggerLoad summarizes the evaluations created by one trigger type
+type EvaluationTriggerLoad struct {
+	TriggeredBy string         `json:"TriggeredBy"`
+	Count       int            `json:"Count"`
+	ByStatus    map[string]int `json:"ByStatus"`
+	Jobs        int            `json:"Jobs"` // distinct jobs
+	MinPriority int            `json:"MinPriority"`
+	MaxPriority int            `json:"MaxPriority"`
+}
+
+// aggregateEvaluationTriggers groups evaluations by trigger, busiest first.
+func aggregateEvaluationTriggers(evaluations []types.Evaluation) []EvaluationTriggerLoad {
+	byTrigger := map[string]*EvaluationTriggerLoad{}
+	jobs := map[string]map[string]bool{}
+	for _, eval := range evaluations {
+		load, ok := byTrigger[eval.TriggeredBy]
+		if !ok {
+			load = &EvaluationTriggerLoad{TriggeredBy: eval.TriggeredBy, ByStatus: map[string]int{}, MinPriority: eval.Priority, MaxPriority: eval.Priority}
+			byTrigger[eval.TriggeredBy] = load
+			jobs[eval.TriggeredBy] = map[string]bool{}
+		}
+		load.Count++
+		load.ByStatus[eval.Status]++
+		load.MinPriority = min(load.MinPriority, eval.Priority)
+		load.MaxPriority = max(load.MaxPriority, eval.Priority)
+		if eval.JobID != "" {
+			jobs[eval.TriggeredBy][eval.JobID] = true
+		}
+	}
+
+	loads := make([]EvaluationTriggerLoad, 0, len(byTrigger))
+	for trigger, load := range byTrigger {
+		load.Jobs = len(jobs[trigger])
+		loads = append(loads, *load)
+	}
+	sort.Slice(loads, func(i, j int) bool {
+		if loads[i].Count != loads[j].Count {
+			return loads[i].Count > loads[j].Count
+		}
+		return loads[i].TriggeredBy < loads[j].TriggeredBy
+	})
+	return loads
+}
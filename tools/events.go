@@ -0,0 +1,197 @@
+// File: tools/events.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	defaultEventWindow    = 10 * time.Second
+	maxEventWindow        = 5 * time.Minute
+	defaultMaxEvents      = 100
+	maxMaxEvents          = 1000
+	recentEventsWindow    = 2 * time.Second
+	recentEventsResources = 100
+)
+
+// EventSubscription is the result of subscribe_events
+type EventSubscription struct {
+	Topics    []string      `json:"Topics"`
+	Namespace string        `json:"Namespace"`
+	Events    []types.Event `json:"Events"`
+	// LastIndex is the index of the last frame received; pass it as index to continue
+	LastIndex uint64 `json:"LastIndex,omitempty"`
+	// Truncated is set when max_events ended the subscription before the listen window
+	Truncated bool `json:"Truncated,omitempty"`
+}
+
+// RegisterEventTools registers the event stream tool and the nomad://events resource
+func RegisterEventTools(s *server.MCPServer, nomadClient utils.EventAPI, logger *log.Logger) {
+	subscribeEventsTool := mcp.NewTool("subscribe_events",
+		mcp.WithDescription("Listen to the Nomad event stream for a while and return the events received. Each frame is also sent as a progress notification (or a log message when the call has no progress token) as it arrives. The listen window is cut short by the server's read timeout"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithArray("topics",
+			mcp.Description("Topics to subscribe to as Topic or Topic:key, e.g. Job, Job:web, Deployment, Evaluation, Allocation, Node, NodePool, Service, ACLToken or * (default: all topics)"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace to receive events from (default: default, * for all)"),
+		),
+		mcp.WithNumber("index",
+			mcp.Description("Raft index to start from; events still buffered by the server since this index are replayed (default: only new events)"),
+		),
+		mcp.WithNumber("duration",
+			mcp.Description("Seconds to listen for (default: 10, max: 300)"),
+		),
+		mcp.WithNumber("max_events",
+			mcp.Description("Stop after this many events (default: 100, max: 1000)"),
+		),
+		mcp.WithBoolean("include_payload",
+			mcp.Description("Include the full object each event carries (default: false)"),
+		),
+	)
+	s.AddTool(subscribeEventsTool, SubscribeEventsHandler(nomadClient, logger))
+
+	eventsResource := mcp.NewResource(
+		"nomad://events",
+		"Recent Nomad events",
+		mcp.WithResourceDescription("The most recent events still buffered by the Nomad event stream, in all namespaces, without payloads"),
+		mcp.WithMIMEType("application/json"),
+	)
+	s.AddResource(eventsResource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		events, err := recentEvents(ctx, nomadClient)
+		if err != nil {
+			requestLogf(ctx, logger, "Error reading recent events: %v", err)
+			return nil, err
+		}
+
+		eventsJSON, err := json.MarshalIndent(events, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to format events: %v", err)
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      "nomad://events",
+				MIMEType: "application/json",
+				Text:     string(eventsJSON),
+			},
+		}, nil
+	})
+}
+
+// recentEvents replays the server's event buffer for a short window and keeps the latest
+// events.
+func recentEvents(ctx context.Context, client utils.EventAPI) ([]types.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, recentEventsWindow)
+	defer cancel()
+
+	events := []types.Event{}
+	err := client.StreamEvents(ctx, types.EventStreamRequest{Namespace: "*", Index: 1}, func(frame types.EventFrame) error {
+		for _, event := range frame.Events {
+			event.Payload = nil
+			events = append(events, event)
+		}
+		if len(events) > recentEventsResources {
+			events = events[len(events)-recentEventsResources:]
+		}
+		return nil
+	})
+	return events, err
+}
+
+// SubscribeEventsHandler returns a handler that collects events from the event stream
+func SubscribeEventsHandler(client utils.EventAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		topics, err := stringListArgument(arguments, "topics")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		var index uint64
+		if i, ok := arguments["index"].(float64); ok {
+			if i < 0 {
+				return mcp.NewToolResultError("index must not be negative"), nil
+			}
+			index = uint64(i)
+		}
+
+		window, err := streamWindow(ctx, arguments, defaultEventWindow, maxEventWindow)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		maxEvents := defaultMaxEvents
+		if m, ok := arguments["max_events"].(float64); ok {
+			if m < 1 {
+				return mcp.NewToolResultError("max_events must be at least 1"), nil
+			}
+			maxEvents = min(int(m), maxMaxEvents)
+		}
+		includePayload, _ := arguments["include_payload"].(bool)
+
+		subscription := EventSubscription{Topics: topics, Namespace: namespace, Events: []types.Event{}}
+		if len(subscription.Topics) == 0 {
+			subscription.Topics = []string{"*"}
+		}
+
+		listenCtx, cancel := context.WithTimeout(ctx, window)
+		defer cancel()
+
+		streamRequest := types.EventStreamRequest{Topics: topics, Namespace: namespace, Index: index}
+		err = client.StreamEvents(listenCtx, streamRequest, func(frame types.EventFrame) error {
+			subscription.LastIndex = frame.Index
+			for _, event := range frame.Events {
+				if !includePayload {
+					event.Payload = nil
+				}
+				subscription.Events = append(subscription.Events, event)
+				if len(subscription.Events) == maxEvents {
+					subscription.Truncated = true
+					break
+				}
+			}
+			notifyEventFrame(ctx, request, len(subscription.Events), maxEvents, frame)
+			if subscription.Truncated {
+				return utils.ErrStopStream
+			}
+			return nil
+		})
+		if err != nil {
+			requestLogf(ctx, logger, "Error streaming events: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to subscribe to events", err), nil
+		}
+
+		subscriptionJSON, err := json.MarshalIndent(subscription, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format events", err), nil
+		}
+
+		return mcp.NewToolResultText(string(subscriptionJSON)), nil
+	}
+}
+
+// notifyEventFrame surfaces a frame to the client while subscribe_events is still running.
+func notifyEventFrame(ctx context.Context, request mcp.CallToolRequest, received, maxEvents int, frame types.EventFrame) {
+	summaries := make([]string, len(frame.Events))
+	for i, event := range frame.Events {
+		summaries[i] = fmt.Sprintf("%s %s %s", event.Topic, event.Type, event.Key)
+	}
+	notifyToolProgress(ctx, request, received, maxEvents, fmt.Sprintf("index %d: %v", frame.Index, summaries), "nomad-events",
+		map[string]any{"Index": frame.Index, "Events": summaries})
+}
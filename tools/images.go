@@ -0,0 +1,417 @@
+// File: tools/images.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	dockerHubRegistry = "docker.io"
+	dockerHubAPIHost  = "registry-1.docker.io"
+)
+
+// manifestAcceptTypes covers single-arch and multi-arch manifests so HEAD reports the
+// digest Docker would pull.
+var manifestAcceptTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// registryHTTPClient queries container registries anonymously.
+var registryHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// ImageReference is one container image used by a job task
+type ImageReference struct {
+	Group      string `json:"Group"`
+	Task       string `json:"Task"`
+	Driver     string `json:"Driver"`
+	Image      string `json:"Image"`
+	Registry   string `json:"Registry"`
+	Repository string `json:"Repository"`
+	Tag        string `json:"Tag,omitempty"`
+	Digest     string `json:"Digest,omitempty"`
+	UsesLatest bool   `json:"UsesLatest"`
+	Exists     *bool  `json:"Exists,omitempty"`
+	Error      string `json:"Error,omitempty"`
+}
+
+// RegisterImageTools registers container image inspection tools
+func RegisterImageTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log.Logger) {
+	// Inspect job images tool
+	inspectJobImagesTool := mcp.NewTool("inspect_job_images",
+		mcp.WithDescription("List the container images referenced by a job's tasks, flag :latest usage, and optionally confirm tags exist in their registries (manifest HEAD) and report digests"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Description("The ID of a registered job to inspect"),
+		),
+		mcp.WithString("job_spec",
+			mcp.Description("A job specification in HCL or JSON format to inspect instead of a registered job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithBoolean("check_registry",
+			mcp.Description("Query each image's registry anonymously to confirm the tag exists and resolve its digest (default: false)"),
+		),
+	)
+	s.AddTool(inspectJobImagesTool, InspectJobImagesHandler(nomadClient, logger))
+}
+
+// InspectJobImagesHandler returns a handler for inspecting job container images
+func InspectJobImagesHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		checkRegistry, _ := arguments["check_registry"].(bool)
+
+		job, err := jobFromArguments(ctx, client, arguments)
+		if err != nil {
+			requestLogf(ctx, logger, "Error loading job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to load job", err), nil
+		}
+
+		images := []ImageReference{}
+		for _, tg := range job.TaskGroups {
+			for _, task := range tg.Tasks {
+				image, ok := task.Config["image"].(string)
+				if !ok || image == "" {
+					continue
+				}
+				ref := parseImageReference(image)
+				ref.Group = tg.Name
+				ref.Task = task.Name
+				ref.Driver = task.Driver
+				if checkRegistry {
+					resolveImageManifest(ctx, &ref)
+				}
+				images = append(images, ref)
+			}
+		}
+
+		imagesJSON, err := json.MarshalIndent(images, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format images", err), nil
+		}
+
+		return mcp.NewToolResultText(string(imagesJSON)), nil
+	}
+}
+
+// ImageUpdate is the update_job_image response: the image change, the scheduler's plan for it
+// and, when submitted, the registration result
+type ImageUpdate struct {
+	JobID          string                          `json:"JobID"`
+	Namespace      string                          `json:"Namespace"`
+	Group          string                          `json:"Group"`
+	Task           string                          `json:"Task"`
+	OldImage       string                          `json:"OldImage"`
+	NewImage       string                          `json:"NewImage"`
+	JobModifyIndex int                             `json:"JobModifyIndex"`
+	Updates        map[string]types.DesiredUpdates `json:"Updates,omitempty"`
+	Diff           *types.JobDiff                  `json:"Diff,omitempty"`
+	FailedTGAllocs map[string]interface{}          `json:"FailedTGAllocs,omitempty"`
+	Warnings       string                          `json:"Warnings,omitempty"`
+	Submitted      bool                            `json:"Submitted"`
+	Result         *types.JobRegisterResponse      `json:"Result,omitempty"`
+}
+
+// UpdateJobImageHandler returns a handler that changes the image tag of one task, plans the
+// change and optionally registers it
+func UpdateJobImageHandler(client utils.JobAPI, submissions *JobSubmissionLocks, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		taskName, ok := arguments["task"].(string)
+		if !ok || taskName == "" {
+			return mcp.NewToolResultError("task is required"), nil
+		}
+		tag, ok := arguments["tag"].(string)
+		tag = strings.TrimSpace(tag)
+		if !ok || tag == "" {
+			return mcp.NewToolResultError("tag is required"), nil
+		}
+		if strings.ContainsAny(tag, "/@: ") {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid tag %q: pass only the tag, e.g. 1.4.2", tag)), nil
+		}
+		groupName, _ := arguments["group"].(string)
+		submit, _ := arguments["submit"].(bool)
+		detach, _ := arguments["detach"].(bool)
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		job, err := client.GetJobDefinition(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
+		}
+
+		update := ImageUpdate{JobID: jobID, Namespace: namespace, Task: taskName}
+		config, err := findTaskConfig(job, groupName, taskName, &update.Group)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		image, _ := config["image"].(string)
+		if image == "" {
+			return mcp.NewToolResultError(fmt.Sprintf("task %s has no image in its config", taskName)), nil
+		}
+		update.OldImage = image
+		update.NewImage = replaceImageTag(image, tag)
+		if update.NewImage == update.OldImage {
+			return mcp.NewToolResultError(fmt.Sprintf("task %s already uses %s", taskName, image)), nil
+		}
+		config["image"] = update.NewImage
+
+		plan, err := client.PlanJob(ctx, job, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error planning job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to plan job", err), nil
+		}
+		update.JobModifyIndex = plan.JobModifyIndex
+		update.Diff = plan.Diff
+		update.FailedTGAllocs = plan.FailedTGAllocs
+		update.Warnings = plan.Warnings
+		if plan.Annotations != nil {
+			update.Updates = plan.Annotations.DesiredTGUpdates
+		}
+
+		if submit {
+			if len(plan.FailedTGAllocs) > 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("not submitting: the plan cannot place allocations for %d task groups; review FailedTGAllocs with submit=false", len(plan.FailedTGAllocs))), nil
+			}
+			jobSpec, err := json.Marshal(job)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("Failed to encode job", err), nil
+			}
+			// Register at the planned index so a change made since the plan is not overwritten.
+			result, err := runJobWithIndex(ctx, client, submissions, string(jobSpec), namespace, detach, plan.JobModifyIndex, true)
+			if err != nil {
+				requestLogf(ctx, logger, "Error running job: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to run job", err), nil
+			}
+			update.Result = &result
+			update.Submitted = true
+		}
+
+		warnings := update.Warnings
+		if update.Result != nil && update.Result.Warnings != "" {
+			warnings = update.Result.Warnings
+		}
+		return jobResultWithWarnings(update, warnings)
+	}
+}
+
+// findTaskConfig returns the driver config of the named task in a raw API job. When group is
+// empty the task name must be unique across groups; the matched group is stored in matched.
+func findTaskConfig(job map[string]interface{}, group, task string, matched *string) (map[string]interface{}, error) {
+	var config map[string]interface{}
+	var groups []string
+	taskGroups, _ := job["TaskGroups"].([]interface{})
+	for _, rawGroup := range taskGroups {
+		tg, _ := rawGroup.(map[string]interface{})
+		name, _ := tg["Name"].(string)
+		if group != "" && name != group {
+			continue
+		}
+		tasks, _ := tg["Tasks"].([]interface{})
+		for _, rawTask := range tasks {
+			t, _ := rawTask.(map[string]interface{})
+			if t["Name"] != task {
+				continue
+			}
+			groups = append(groups, name)
+			config, _ = t["Config"].(map[string]interface{})
+			*matched = name
+		}
+	}
+
+	switch {
+	case len(groups) == 0 && group != "":
+		return nil, fmt.Errorf("task %s not found in group %s", task, group)
+	case len(groups) == 0:
+		return nil, fmt.Errorf("task %s not found", task)
+	case len(groups) > 1:
+		return nil, fmt.Errorf("task %s exists in groups %s; set group", task, strings.Join(groups, ", "))
+	case config == nil:
+		return nil, fmt.Errorf("task %s has no driver config", task)
+	}
+	return config, nil
+}
+
+// replaceImageTag swaps the tag of an image reference, dropping any digest pin, e.g.
+// registry:5000/app:1.0@sha256:... becomes registry:5000/app:<tag>.
+func replaceImageTag(image, tag string) string {
+	name, _, _ := strings.Cut(image, "@")
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		name = name[:colon]
+	}
+	return name + ":" + tag
+}
+
+// parseImageReference splits an image reference the way Docker does: the first path
+// component is a registry only if it looks like a host, and Docker Hub official images
+// live under library/.
+func parseImageReference(image string) ImageReference {
+	ref := ImageReference{Image: image, Registry: dockerHubRegistry}
+
+	name := image
+	if at := strings.Index(name, "@"); at >= 0 {
+		ref.Digest = name[at+1:]
+		name = name[:at]
+	}
+
+	if slash := strings.Index(name, "/"); slash >= 0 {
+		first := name[:slash]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			ref.Registry = first
+			name = name[slash+1:]
+		}
+	}
+
+	if colon := strings.LastIndex(name, ":"); colon >= 0 {
+		ref.Tag = name[colon+1:]
+		name = name[:colon]
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	ref.UsesLatest = ref.Tag == "latest" && ref.Digest == ""
+
+	if ref.Registry == dockerHubRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.Repository = name
+
+	return ref
+}
+
+// resolveImageManifest HEADs the image manifest, records whether it exists and its digest.
+func resolveImageManifest(ctx context.Context, ref *ImageReference) {
+	host := ref.Registry
+	if host == dockerHubRegistry {
+		host = dockerHubAPIHost
+	}
+	reference := ref.Tag
+	if ref.Digest != "" {
+		reference = ref.Digest
+	}
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, ref.Repository, reference)
+
+	resp, err := headManifest(ctx, manifestURL, "")
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		var token string
+		token, err = fetchRegistryToken(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err == nil {
+			resp, err = headManifest(ctx, manifestURL, token)
+		}
+	}
+	if err != nil {
+		ref.Error = err.Error()
+		return
+	}
+
+	exists := resp.StatusCode == http.StatusOK
+	switch {
+	case exists:
+		if digest := resp.Header.Get("Docker-Content-Digest"); digest != "" {
+			ref.Digest = digest
+		}
+		ref.Exists = &exists
+	case resp.StatusCode == http.StatusNotFound:
+		ref.Exists = &exists
+	default:
+		ref.Error = fmt.Sprintf("registry returned %s", resp.Status)
+	}
+}
+
+func headManifest(ctx context.Context, manifestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestAcceptTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := registryHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// fetchRegistryToken answers a Bearer challenge with an anonymous pull token.
+func fetchRegistryToken(ctx context.Context, challenge string) (string, error) {
+	scheme, params, ok := strings.Cut(challenge, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("registry requires unsupported authentication %q", challenge)
+	}
+
+	values := map[string]string{}
+	for _, part := range strings.Split(params, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			values[k] = strings.Trim(v, `"`)
+		}
+	}
+	realm, err := url.Parse(values["realm"])
+	if err != nil || realm.Scheme == "" {
+		return "", fmt.Errorf("invalid registry auth realm %q", values["realm"])
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if values[key] != "" {
+			query.Set(key, values[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := registryHTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token request returned %s", resp.Status)
+	}
+
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("error decoding registry token: %v", err)
+	}
+	if tokenResp.Token != "" {
+		return tokenResp.Token, nil
+	}
+	return tokenResp.AccessToken, nil
+}
@@ -0,0 +1,312 @@
+// File: tools/job_diff.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// jobDiffIgnoredFields change on every registration or are set by Nomad, so they are left
+// out of a canonical job.
+var jobDiffIgnoredFields = append([]string{"NomadTokenID", "ParentID", "Dispatched"}, serverManagedJobFields...)
+
+// jobFreeFormFields hold user-chosen keys, e.g. meta and env values or driver config. A key
+// one side lacks is a deletion, not a field left to Nomad's default.
+var jobFreeFormFields = map[string]bool{"Meta": true, "Env": true, "Config": true, "Options": true}
+
+// JobSpecChange is one field that differs between the registered job and the compared job
+type JobSpecChange struct {
+	Path string `json:"Path"`
+	Type string `json:"Type"` // Added, Deleted or Edited
+	Old  string `json:"Old,omitempty"`
+	New  string `json:"New,omitempty"`
+}
+
+// JobSpecDiff is the result of diff_job_spec
+type JobSpecDiff struct {
+	JobID          string          `json:"JobID"`
+	Namespace      string          `json:"Namespace"`
+	Against        string          `json:"Against"`
+	JobModifyIndex int             `json:"JobModifyIndex"`
+	Identical      bool            `json:"Identical"`
+	Changes        []JobSpecChange `json:"Changes"`
+	// Unset are the fields of the registered job the compared job leaves out, which Nomad
+	// fills with its defaults on registration. They are not counted as changes.
+	Unset   []string `json:"Unset,omitempty"`
+	Summary string   `json:"Summary"`
+}
+
+// DiffJobSpecHandler returns a handler that compares a registered job with a job spec or
+// another registered job after canonicalizing both
+func DiffJobSpecHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, _ := arguments["job_id"].(string)
+		if jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+		jobSpec, _ := arguments["job_spec"].(string)
+		otherJobID, _ := arguments["other_job_id"].(string)
+		if (jobSpec == "") == (otherJobID == "") {
+			return mcp.NewToolResultError("exactly one of job_spec or other_job_id is required"), nil
+		}
+
+		registered, err := client.GetJobDefinition(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
+		}
+
+		diff := JobSpecDiff{JobID: jobID, Namespace: namespace}
+		if index, ok := registered["JobModifyIndex"].(float64); ok {
+			diff.JobModifyIndex = int(index)
+		}
+		var other map[string]interface{}
+		if jobSpec != "" {
+			diff.Against = "job spec"
+			if other, err = client.CanonicalizeJobSpec(ctx, jobSpec); err != nil {
+				requestLogf(ctx, logger, "Error parsing job: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to parse job", err), nil
+			}
+		} else {
+			diff.Against = "job " + otherJobID
+			if other, err = client.GetJobDefinition(ctx, otherJobID, namespace); err != nil {
+				requestLogf(ctx, logger, "Error getting job: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
+			}
+		}
+
+		diff.Changes, diff.Unset = diffCanonicalJobs(canonicalJob(registered), canonicalJob(other), jobSpec != "")
+		diff.Identical = len(diff.Changes) == 0
+		diff.Summary = jobSpecDiffSummary(diff)
+
+		diffJSON, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format job diff", err), nil
+		}
+
+		return mcp.NewToolResultText(string(diffJSON)), nil
+	}
+}
+
+func jobSpecDiffSummary(diff JobSpecDiff) string {
+	summary := fmt.Sprintf("Job %s (JobModifyIndex %d) and the %s are the same once canonicalized.", diff.JobID, diff.JobModifyIndex, diff.Against)
+	if !diff.Identical {
+		summary = fmt.Sprintf("%d fields differ between job %s (JobModifyIndex %d) and the %s.", len(diff.Changes), diff.JobID, diff.JobModifyIndex, diff.Against)
+	}
+	if len(diff.Unset) > 0 {
+		summary += fmt.Sprintf(" %d fields of the registered job are not set by the %s and keep Nomad's defaults; see Unset.", len(diff.Unset), diff.Against)
+	}
+	return summary
+}
+
+// canonicalJob normalizes a raw API job so two versions of it compare equal unless they
+// really differ: bookkeeping fields are dropped, null values and empty lists and objects
+// count as unset, and lists of objects are put in a stable order.
+func canonicalJob(job map[string]interface{}) map[string]interface{} {
+	canonical, _ := canonicalValue(job).(map[string]interface{})
+	if canonical == nil {
+		return map[string]interface{}{}
+	}
+	for _, field := range jobDiffIgnoredFields {
+		delete(canonical, field)
+	}
+	return canonical
+}
+
+func canonicalValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		canonical := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if item = canonicalValue(item); item != nil {
+				canonical[key] = item
+			}
+		}
+		if len(canonical) == 0 {
+			return nil
+		}
+		return canonical
+	case []interface{}:
+		canonical := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if item = canonicalValue(item); item != nil {
+				canonical = append(canonical, item)
+			}
+		}
+		if len(canonical) == 0 {
+			return nil
+		}
+		// Lists of objects (task groups, services, constraints, ...) have no meaningful
+		// order; lists of strings such as args keep theirs.
+		if _, ok := canonical[0].(map[string]interface{}); ok {
+			sort.SliceStable(canonical, func(i, j int) bool {
+				return listItemSortKey(canonical[i]) < listItemSortKey(canonical[j])
+			})
+		}
+		return canonical
+	}
+	return value
+}
+
+// listItemKey is the name that identifies an object in a list, e.g. a task group or a port
+// label, or "" when the object has none.
+func listItemKey(item interface{}) string {
+	object, _ := item.(map[string]interface{})
+	for _, field := range []string{"Name", "Label"} {
+		if key, _ := object[field].(string); key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+func listItemSortKey(item interface{}) string {
+	if key := listItemKey(item); key != "" {
+		return key
+	}
+	return renderJobValue(item)
+}
+
+// keyedList indexes a list of objects by their names, or returns nil when an object has
+// no name or two share one.
+func keyedList(list []interface{}) map[string]interface{} {
+	keyed := make(map[string]interface{}, len(list))
+	for _, item := range list {
+		key := listItemKey(item)
+		if _, dup := keyed[key]; key == "" || dup {
+			return nil
+		}
+		keyed[key] = item
+	}
+	return keyed
+}
+
+// diffCanonicalJobs lists the fields that differ from one job to another, by path such as
+// TaskGroups[web].Tasks[app].Config.image. With defaultsUnset, fields the second job leaves
+// out where Nomad would apply a default are returned as unset instead of as deletions.
+func diffCanonicalJobs(from, to map[string]interface{}, defaultsUnset bool) ([]JobSpecChange, []string) {
+	d := jobDiffer{defaultsUnset: defaultsUnset, changes: []JobSpecChange{}}
+	d.diffObjects("", from, to, false)
+	return d.changes, d.unset
+}
+
+type jobDiffer struct {
+	defaultsUnset bool
+	changes       []JobSpecChange
+	unset         []string
+}
+
+func (d *jobDiffer) diffObjects(path string, from, to map[string]interface{}, freeForm bool) {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+		fromValue, inFrom := from[key]
+		toValue, inTo := to[key]
+		switch {
+		case !inTo && !freeForm && !jobFreeFormFields[key] && d.defaultsUnset:
+			d.unset = append(d.unset, fieldPath)
+		default:
+			d.diffValues(fieldPath, fromValue, toValue, inFrom, inTo, freeForm || jobFreeFormFields[key])
+		}
+	}
+}
+
+func (d *jobDiffer) diffValues(path string, from, to interface{}, inFrom, inTo, freeForm bool) {
+	switch {
+	case !inFrom:
+		d.changes = append(d.changes, JobSpecChange{Path: path, Type: "Added", New: renderJobScalar(to)})
+		return
+	case !inTo:
+		d.changes = append(d.changes, JobSpecChange{Path: path, Type: "Deleted", Old: renderJobScalar(from)})
+		return
+	}
+
+	fromObject, fromIsObject := from.(map[string]interface{})
+	toObject, toIsObject := to.(map[string]interface{})
+	if fromIsObject && toIsObject {
+		d.diffObjects(path, fromObject, toObject, freeForm)
+		return
+	}
+
+	fromList, fromIsList := from.([]interface{})
+	toList, toIsList := to.([]interface{})
+	if fromIsList && toIsList {
+		fromKeyed, toKeyed := keyedList(fromList), keyedList(toList)
+		if fromKeyed != nil && toKeyed != nil {
+			d.diffKeyedLists(path, fromKeyed, toKeyed, freeForm)
+			return
+		}
+	}
+
+	if renderJobValue(from) != renderJobValue(to) {
+		d.changes = append(d.changes, JobSpecChange{Path: path, Type: "Edited", Old: renderJobValue(from), New: renderJobValue(to)})
+	}
+}
+
+// diffKeyedLists compares lists of named objects by name, so a task group added in front
+// of the others does not show up as every group changing.
+func (d *jobDiffer) diffKeyedLists(path string, from, to map[string]interface{}, freeForm bool) {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fromItem, inFrom := from[key]
+		toItem, inTo := to[key]
+		d.diffValues(fmt.Sprintf("%s[%s]", path, key), fromItem, toItem, inFrom, inTo, freeForm)
+	}
+}
+
+// renderJobScalar renders a value for an added or deleted field; whole objects and lists
+// are only named by their path.
+func renderJobScalar(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return ""
+	}
+	return renderJobValue(value)
+}
+
+func renderJobValue(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSpace(string(data))
+}
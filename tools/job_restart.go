@@ -0,0 +1,151 @@
+// File: tools/job_restart.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RestartedAllocation is an allocation restart_job restarted
+type RestartedAllocation struct {
+	ID        string `json:"ID"`
+	Name      string `json:"Name"`
+	TaskGroup string `json:"TaskGroup"`
+	NodeID    string `json:"NodeID"`
+	Batch     int    `json:"Batch"`
+}
+
+// JobRestartReport is the result of restart_job
+type JobRestartReport struct {
+	JobID     string                `json:"JobID"`
+	Namespace string                `json:"Namespace"`
+	TaskGroup string                `json:"TaskGroup,omitempty"`
+	Task      string                `json:"Task,omitempty"`
+	BatchSize int                   `json:"BatchSize"`
+	BatchWait string                `json:"BatchWait,omitempty"`
+	Running   int                   `json:"Running"`
+	Restarted []RestartedAllocation `json:"Restarted"`
+	// Remaining are the allocations left alone because the call ran out of time
+	Remaining []string `json:"Remaining,omitempty"`
+	Summary   string   `json:"Summary"`
+}
+
+// RestartJobHandler returns a handler that restarts the running allocations of a job in
+// batches, one allocation at a time by default like nomad job restart. A batch_wait that would
+// run past the call's deadline ends the restart early with a report of what was restarted.
+func RestartJobHandler(client utils.AllocationAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, _ := arguments["job_id"].(string)
+		if jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+		taskGroup, _ := arguments["task_group"].(string)
+		task, _ := arguments["task"].(string)
+		batchSize := 1
+		if n, ok := arguments["batch_size"].(float64); ok {
+			if n < 1 || n != float64(int(n)) {
+				return mcp.NewToolResultError("batch_size must be a positive integer"), nil
+			}
+			batchSize = int(n)
+		}
+		batchWait, err := durationArgument(arguments, "batch_wait", 0)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		allocs, err := client.ListAllocations(ctx, namespace, jobID)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing job allocations: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list job allocations", err), nil
+		}
+
+		running := []types.Allocation{}
+		for _, alloc := range allocs {
+			if alloc.ClientStatus != "running" || alloc.DesiredStatus != "run" || (taskGroup != "" && alloc.TaskGroup != taskGroup) {
+				continue
+			}
+			if _, ok := alloc.TaskStates[task]; task != "" && !ok {
+				continue
+			}
+			running = append(running, alloc)
+		}
+		batchSize = max(min(batchSize, len(running)), 1)
+
+		report := JobRestartReport{JobID: jobID, Namespace: namespace, TaskGroup: taskGroup, Task: task, BatchSize: batchSize, Running: len(running), Restarted: []RestartedAllocation{}}
+		if batchWait > 0 {
+			report.BatchWait = batchWait.String()
+		}
+		batches := (len(running) + batchSize - 1) / batchSize
+		stoppedBefore := 0
+		for i, alloc := range running {
+			batch := i/batchSize + 1
+			if i > 0 && i%batchSize == 0 && batchWait > 0 {
+				if !waitBetweenBatches(ctx, batchWait) {
+					stoppedBefore = batch
+					for _, left := range running[i:] {
+						report.Remaining = append(report.Remaining, left.ID)
+					}
+					break
+				}
+			}
+			if err := client.RestartAllocation(ctx, alloc.ID, task); err != nil {
+				requestLogf(ctx, logger, "Error restarting allocation: %v", err)
+				return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Failed to restart allocation %s after restarting %d", alloc.ID, len(report.Restarted)), err), nil
+			}
+			report.Restarted = append(report.Restarted, RestartedAllocation{ID: alloc.ID, Name: alloc.Name, TaskGroup: alloc.TaskGroup, NodeID: alloc.NodeID, Batch: batch})
+			if (i+1)%batchSize == 0 || i+1 == len(running) {
+				notifyToolProgress(ctx, request, i+1, len(running), fmt.Sprintf("restarted batch %d of %d", batch, batches), "nomad-restart",
+					map[string]any{"Batch": batch, "Restarted": i + 1})
+			}
+		}
+
+		what := "allocations"
+		if task != "" {
+			what = fmt.Sprintf("allocations (task %s only)", task)
+		}
+		switch {
+		case stoppedBefore > 0:
+			report.Summary = fmt.Sprintf("Restarted %d of %d running %s, then stopped before batch %d of %d because the next batch_wait would outlast the call's timeout; the Remaining allocations were not restarted.", len(report.Restarted), len(running), what, stoppedBefore, batches)
+		case len(running) == 0:
+			report.Summary = fmt.Sprintf("Job %s has no running allocations to restart.", jobID)
+		case batches == 1:
+			report.Summary = fmt.Sprintf("Restarted %d running %s at once. Follow with get_job_status to check the tasks came back.", len(report.Restarted), what)
+		default:
+			report.Summary = fmt.Sprintf("Restarted %d running %s in %d batches. Follow with get_job_status to check the tasks came back.", len(report.Restarted), what, batches)
+		}
+
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format restart report", err), nil
+		}
+
+		return mcp.NewToolResultText(string(reportJSON)), nil
+	}
+}
+
+// waitBetweenBatches waits d before the next batch of a rolling restart. It returns false at
+// once when the wait would end too close to the call's deadline, or when the call ends.
+func waitBetweenBatches(ctx context.Context, d time.Duration) bool {
+	if monitorWindow(ctx, d) < d {
+		return false
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
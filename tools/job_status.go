@@ -0,0 +1,189 @@
+// File: tools/job_status.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// JobStatus is the get_job_status response
+type JobStatus struct {
+	ID            string                       `json:"ID"`
+	Name          string                       `json:"Name"`
+	Namespace     string                       `json:"Namespace"`
+	Type          string                       `json:"Type"`
+	Priority      int                          `json:"Priority"`
+	Status        string                       `json:"Status"`
+	Version       int                          `json:"Version"`
+	Datacenters   []string                     `json:"Datacenters"`
+	NodePool      string                       `json:"NodePool,omitempty"`
+	Periodic      bool                         `json:"Periodic,omitempty"`
+	Parameterized bool                         `json:"Parameterized,omitempty"`
+	Summary       map[string]types.TaskSummary `json:"Summary"`
+	Deployment    *JobStatusDeployment         `json:"LatestDeployment,omitempty"`
+	Health        JobAllocationHealth          `json:"AllocationHealth"`
+	Allocations   []JobStatusAllocation        `json:"Allocations"`
+}
+
+// JobStatusDeployment is the latest deployment as shown by get_job_status
+type JobStatusDeployment struct {
+	ID                string                            `json:"ID"`
+	JobVersion        int                               `json:"JobVersion"`
+	Status            string                            `json:"Status"`
+	StatusDescription string                            `json:"StatusDescription,omitempty"`
+	TaskGroups        map[string]*types.DeploymentState `json:"TaskGroups,omitempty"`
+}
+
+// JobAllocationHealth counts the job's allocations that should be running by health
+type JobAllocationHealth struct {
+	Running   int `json:"Running"`
+	Pending   int `json:"Pending"`
+	Healthy   int `json:"Healthy"`
+	Unhealthy int `json:"Unhealthy"`
+	Failed    int `json:"Failed"`
+	Lost      int `json:"Lost"`
+}
+
+// JobStatusAllocation is one row of get_job_status's allocation table
+type JobStatusAllocation struct {
+	ID            string `json:"ID"`
+	NodeID        string `json:"NodeID"`
+	TaskGroup     string `json:"TaskGroup"`
+	DesiredStatus string `json:"DesiredStatus"`
+	ClientStatus  string `json:"ClientStatus"`
+	// Healthy is the deployment health; it is left out while unknown
+	Healthy    *bool `json:"Healthy,omitempty"`
+	Canary     bool  `json:"Canary,omitempty"`
+	CreateTime int64 `json:"CreateTime"`
+	ModifyTime int64 `json:"ModifyTime"`
+}
+
+// GetJobStatusHandler returns a handler for summarizing a job's status
+func GetJobStatusHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+		allAllocations, _ := arguments["all_allocations"].(bool)
+
+		job, err := client.GetJob(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
+		}
+
+		summary, err := client.GetJobSummary(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting job summary: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job summary", err), nil
+		}
+
+		allocations, err := client.ListJobAllocations(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting job allocations: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job allocations", err), nil
+		}
+
+		status := jobStatus(job, summary, allocations, allAllocations)
+
+		// Batch, system and jobs without an update block have no deployment
+		deployment, err := client.GetJobDeployment(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting latest deployment for job %s: %v", jobID, err)
+		} else if deployment.ID != "" {
+			status.Deployment = &JobStatusDeployment{
+				ID:                deployment.ID,
+				JobVersion:        deployment.JobVersion,
+				Status:            deployment.Status,
+				StatusDescription: deployment.StatusDescription,
+				TaskGroups:        deployment.TaskGroups,
+			}
+		}
+
+		statusJSON, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format job status", err), nil
+		}
+
+		return mcp.NewToolResultText(string(statusJSON)), nil
+	}
+}
+
+// jobStatus merges a job, its summary and its allocations. Health is counted over the
+// allocations Nomad wants running; the table keeps only those unless all is set. Rows are
+// ordered newest first, like `nomad job status`.
+func jobStatus(job types.Job, summary types.JobSummary, allocations []types.Allocation, all bool) JobStatus {
+	status := JobStatus{
+		ID:            job.ID,
+		Name:          job.Name,
+		Namespace:     job.Namespace,
+		Type:          job.Type,
+		Priority:      job.Priority,
+		Status:        job.Status,
+		Version:       job.Version,
+		Datacenters:   job.Datacenters,
+		NodePool:      job.NodePool,
+		Periodic:      job.Periodic != nil,
+		Parameterized: job.Parameterized != nil,
+		Summary:       summary.Summary,
+		Allocations:   []JobStatusAllocation{},
+	}
+
+	for _, alloc := range allocations {
+		wanted := alloc.DesiredStatus == "run"
+		if wanted {
+			switch alloc.ClientStatus {
+			case "running":
+				status.Health.Running++
+			case "pending":
+				status.Health.Pending++
+			case "failed":
+				status.Health.Failed++
+			case "lost":
+				status.Health.Lost++
+			}
+		}
+
+		row := JobStatusAllocation{
+			ID:            alloc.ID,
+			NodeID:        alloc.NodeID,
+			TaskGroup:     alloc.TaskGroup,
+			DesiredStatus: alloc.DesiredStatus,
+			ClientStatus:  alloc.ClientStatus,
+			CreateTime:    alloc.CreateTime,
+			ModifyTime:    alloc.ModifyTime,
+		}
+		if ds := alloc.DeploymentStatus; ds != nil && ds.Timestamp != nil {
+			healthy := ds.Healthy
+			row.Healthy = &healthy
+			row.Canary = ds.Canary
+			if wanted && healthy {
+				status.Health.Healthy++
+			} else if wanted {
+				status.Health.Unhealthy++
+			}
+		}
+
+		if wanted || all {
+			status.Allocations = append(status.Allocations, row)
+		}
+	}
+
+	sort.SliceStable(status.Allocations, func(i, j int) bool {
+		return status.Allocations[i].CreateTime > status.Allocations[j].CreateTime
+	})
+	return status
+}
@@ -0,0 +1,129 @@
+// File: tools/job_versions.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// JobVersion is one version of a job in get_job_versions, with what changed since the
+// version before it
+type JobVersion struct {
+	Version        int    `json:"Version"`
+	Stable         bool   `json:"Stable"`
+	SubmitTime     string `json:"SubmitTime,omitempty"`
+	JobModifyIndex int    `json:"JobModifyIndex"`
+	// Changes renders Diff like nomad job history -p; empty for the oldest version shown
+	Changes []string       `json:"Changes"`
+	Diff    *types.JobDiff `json:"Diff,omitempty"`
+	Job     *types.Job     `json:"Job,omitempty"`
+}
+
+// JobVersionsReport is the result of get_job_versions
+type JobVersionsReport struct {
+	JobID     string       `json:"JobID"`
+	Namespace string       `json:"Namespace"`
+	Versions  []JobVersion `json:"Versions"`
+	Summary   []string     `json:"Summary"`
+}
+
+// GetJobVersionsHandler returns a handler listing the versions of a job with the changes
+// between them
+func GetJobVersionsHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, _ := arguments["job_id"].(string)
+		if jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+		limit := 10
+		if n, ok := arguments["limit"].(float64); ok {
+			if n < 1 {
+				return mcp.NewToolResultError("limit must be positive"), nil
+			}
+			limit = int(n)
+		}
+		includeSpecs, _ := arguments["include_specs"].(bool)
+
+		history, err := client.GetJobVersionDiffs(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting job versions: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job versions", err), nil
+		}
+
+		report := JobVersionsReport{JobID: jobID, Namespace: namespace, Versions: []JobVersion{}, Summary: []string{}}
+		for i, job := range history.Versions {
+			if i == limit {
+				break
+			}
+			version := JobVersion{Version: job.Version, Stable: job.Stable, JobModifyIndex: job.JobModifyIndex, Changes: []string{}}
+			if job.SubmitTime > 0 {
+				version.SubmitTime = time.Unix(0, job.SubmitTime).UTC().Format(time.RFC3339)
+			}
+			if i < len(history.Diffs) && i+1 < len(history.Versions) {
+				diff := history.Diffs[i]
+				version.Diff = &diff
+				version.Changes = formatJobDiff(&diff)
+			}
+			if includeSpecs {
+				version.Job = &history.Versions[i]
+			}
+			report.Versions = append(report.Versions, version)
+			report.Summary = append(report.Summary, jobVersionSummary(version, history.Versions, i))
+		}
+		if len(history.Versions) > limit {
+			report.Summary = append(report.Summary, fmt.Sprintf("%d older versions are not shown; raise limit to see them.", len(history.Versions)-limit))
+		}
+
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format job versions", err), nil
+		}
+
+		return mcp.NewToolResultText(string(reportJSON)), nil
+	}
+}
+
+// jobVersionSummary describes a version and how it differs from the previous one in a line.
+func jobVersionSummary(version JobVersion, versions []types.Job, i int) string {
+	line := fmt.Sprintf("Version %d", version.Version)
+	if version.SubmitTime != "" {
+		line += " submitted " + version.SubmitTime
+	}
+	if version.Stable {
+		line += " (stable)"
+	}
+	switch {
+	case i+1 >= len(versions):
+		return line + ": the oldest version Nomad keeps."
+	case version.Diff == nil:
+		return line + fmt.Sprintf(": no diff against version %d.", versions[i+1].Version)
+	case !jobPlanChanges(version.Diff):
+		return line + fmt.Sprintf(": same job as version %d.", versions[i+1].Version)
+	}
+
+	groups := []string{}
+	for _, group := range version.Diff.TaskGroups {
+		if group.Type != "None" {
+			groups = append(groups, fmt.Sprintf("%s (%s)", group.Name, group.Type))
+		}
+	}
+	line += fmt.Sprintf(": %d changed lines against version %d", len(version.Changes), versions[i+1].Version)
+	if len(groups) > 0 {
+		line += " in task groups " + strings.Join(groups, ", ")
+	}
+	return line + "."
+}
@@ -3,8 +3,12 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/kocierik/mcp-nomad/types"
 	"github.com/kocierik/mcp-nomad/utils"
@@ -12,11 +16,13 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// RegisterJobTools registers all job-related tools
-func RegisterJobTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log.Logger) {
+// RegisterJobTools registers all job-related tools. When submissions is non-nil, run_job
+// serializes submissions per job and registers with a JobModifyIndex check-and-set.
+func RegisterJobTools(s *server.MCPServer, nomadClient utils.JobAPI, submissions *JobSubmissionLocks, logger *log.Logger) {
 	// List jobs tool
 	listJobsTool := mcp.NewTool("list_jobs",
 		mcp.WithDescription("List all jobs in Nomad"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("namespace",
 			mcp.Description("The namespace to list jobs from (default: default)"),
 		),
@@ -24,12 +30,19 @@ func RegisterJobTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log
 			mcp.Description("Filter jobs by status (pending, running, dead)"),
 			mcp.Enum("pending", "running", "dead", ""),
 		),
+		mcp.WithBoolean("include_rollout",
+			mcp.Description("Annotate each job with its latest deployment status and pending evaluation count (default: false)"),
+		),
+		mcp.WithBoolean("all_regions",
+			mcp.Description(allRegionsDescription),
+		),
 	)
 	s.AddTool(listJobsTool, ListJobsHandler(nomadClient, logger))
 
 	// Get job tool
 	getJobTool := mcp.NewTool("get_job",
 		mcp.WithDescription("Get job details by ID"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("job_id",
 			mcp.Required(),
 			mcp.Description("The ID of the job to retrieve"),
@@ -37,9 +50,120 @@ func RegisterJobTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log
 		mcp.WithString("namespace",
 			mcp.Description("The namespace of the job (default: default)"),
 		),
+		fieldsOption(),
 	)
 	s.AddTool(getJobTool, GetJobHandler(nomadClient, logger))
 
+	// Get job status tool
+	getJobStatusTool := mcp.NewTool("get_job_status",
+		mcp.WithDescription("Get a job's status in one answer, like `nomad job status`: the job, its task group summary, its latest deployment and the health of its allocations"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithBoolean("all_allocations",
+			mcp.Description("Also list stopped and completed allocations (default: false, only allocations that should be running)"),
+		),
+	)
+	s.AddTool(getJobStatusTool, GetJobStatusHandler(nomadClient, logger))
+
+	// Get job submission tool
+	getJobSubmissionTool := mcp.NewTool("get_job_submission",
+		mcp.WithDescription("Get the original source (HCL or JSON) a job version was registered from, with its format and the variables passed with it. Only jobs registered with their source (e.g. nomad job run, run_job) have one"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithNumber("version",
+			mcp.Description("The job version to get the source of (default: the current version)"),
+		),
+	)
+	s.AddTool(getJobSubmissionTool, GetJobSubmissionHandler(nomadClient, logger))
+
+	// Get job source tool
+	getJobSourceTool := mcp.NewTool("get_job_source",
+		mcp.WithDescription("Get the source of a job version: the original HCL or JSON submission with its variables when Nomad stored one, otherwise the job's JSON spec as Nomad stores it. Origin tells which one was returned"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithNumber("version",
+			mcp.Description("The job version to get the source of (default: the current version)"),
+		),
+	)
+	s.AddTool(getJobSourceTool, GetJobSourceHandler(nomadClient, logger))
+
+	// Get job versions tool
+	getJobVersionsTool := mcp.NewTool("get_job_versions",
+		mcp.WithDescription("List the versions of a job, newest first, with what changed from each version to the next as a readable diff and as Nomad's structured diff, like `nomad job history -p`"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of versions to return (default: 10)"),
+		),
+		mcp.WithBoolean("include_specs",
+			mcp.Description("Include the full job of each version (default: false)"),
+		),
+	)
+	s.AddTool(getJobVersionsTool, GetJobVersionsHandler(nomadClient, logger))
+
+	// Get task group tool
+	getTaskGroupTool := mcp.NewTool("get_task_group",
+		mcp.WithDescription("Get a single task group definition from a job (tasks, networks, services, volumes, policies)"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job"),
+		),
+		mcp.WithString("group",
+			mcp.Required(),
+			mcp.Description("The name of the task group"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+	)
+	s.AddTool(getTaskGroupTool, GetTaskGroupHandler(nomadClient, logger))
+
+	// Get task tool
+	getTaskTool := mcp.NewTool("get_task",
+		mcp.WithDescription("Get a single task definition from a job (driver config, resources, templates, services)"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job"),
+		),
+		mcp.WithString("task",
+			mcp.Required(),
+			mcp.Description("The name of the task"),
+		),
+		mcp.WithString("group",
+			mcp.Description("The task group containing the task (required only when several groups have a task with this name)"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+	)
+	s.AddTool(getTaskTool, GetTaskHandler(nomadClient, logger))
+
 	// Run job tool
 	runJobTool := mcp.NewTool("run_job",
 		mcp.WithDescription("Run a new job or update an existing job"),
@@ -47,15 +171,116 @@ func RegisterJobTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log
 			mcp.Required(),
 			mcp.Description("The job specification in HCL or JSON format"),
 		),
+		mcp.WithString("namespace",
+			mcp.Description("Override the namespace declared in the job spec"),
+		),
 		mcp.WithBoolean("detach",
 			mcp.Description("Return immediately instead of monitoring deployment"),
 		),
+		mcp.WithNumber("job_modify_index",
+			mcp.Description("Only register if the job's current JobModifyIndex equals this value (0 requires that the job does not exist yet)"),
+		),
+		mcp.WithBoolean("skip_unchanged",
+			mcp.Description("Plan the job first and do not register it when the plan shows no changes besides index and version bookkeeping, so resubmitting the same spec does not create a new job version (default: false)"),
+		),
+	)
+	s.AddTool(runJobTool, RunJobHandler(nomadClient, submissions, logger))
+
+	// Plan job tool
+	planJobTool := mcp.NewTool("plan_job",
+		mcp.WithDescription("Dry-run a job specification like nomad job plan: show what would change against the registered job, the scheduler's placements and updates per task group, and the allocations it could not place. Nothing is submitted"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_spec",
+			mcp.Required(),
+			mcp.Description("The job specification in HCL or JSON format"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Override the namespace declared in the job spec"),
+		),
+	)
+	s.AddTool(planJobTool, PlanJobHandler(nomadClient, logger))
+
+	// Diff job spec tool
+	diffJobSpecTool := mcp.NewTool("diff_job_spec",
+		mcp.WithDescription("Compare a registered job with a job specification or another registered job field by field, without planning. Both sides are canonicalized first (HCL defaults filled in by Nomad, bookkeeping fields dropped, lists ordered by name), so field order and defaults do not show up as changes"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the registered job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the jobs (default: default)"),
+		),
+		mcp.WithString("job_spec",
+			mcp.Description("The job specification in HCL or JSON format to compare against"),
+		),
+		mcp.WithString("other_job_id",
+			mcp.Description("A registered job to compare against instead of job_spec, e.g. the green copy of a blue/green pair"),
+		),
+	)
+	s.AddTool(diffJobSpecTool, DiffJobSpecHandler(nomadClient, logger))
+
+	// Update job image tool
+	updateJobImageTool := mcp.NewTool("update_job_image",
+		mcp.WithDescription("Change the image tag of one task in a registered job and plan the change, returning the diff and scheduler annotations. With submit=true the job is registered at the planned JobModifyIndex so concurrent changes are not overwritten"),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job to update"),
+		),
+		mcp.WithString("task",
+			mcp.Required(),
+			mcp.Description("The name of the task whose image changes"),
+		),
+		mcp.WithString("tag",
+			mcp.Required(),
+			mcp.Description("The new image tag, e.g. 1.4.2; any digest pin on the current image is dropped"),
+		),
+		mcp.WithString("group",
+			mcp.Description("The task group of the task, required when several groups have a task with that name"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithBoolean("submit",
+			mcp.Description("Register the updated job after planning it (default: false, plan only)"),
+		),
+		mcp.WithBoolean("detach",
+			mcp.Description("Return immediately instead of monitoring deployment when submitting"),
+		),
+	)
+	s.AddTool(updateJobImageTool, UpdateJobImageHandler(nomadClient, submissions, logger))
+
+	// Deploy blue/green tool
+	deployBlueGreenTool := mcp.NewTool("deploy_blue_green",
+		mcp.WithDescription("Blue/green cutover: register a copy of a job under a second ID (suffix -green, or without the suffix when the job already has it), wait for it to become healthy, move the live service tags from the old job to the new one and stop the old job. An unhealthy green job is stopped and the old job is left untouched"),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job currently serving traffic (blue)"),
+		),
+		mcp.WithString("job_spec",
+			mcp.Description("The new version of the job in HCL or JSON format; its ID is replaced by the green ID (default: a copy of the blue job)"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithString("live_tags",
+			mcp.Description("Comma-separated service tags that route traffic, e.g. live or traefik.enable=true. They are withheld from green until it is healthy, then moved from blue to green"),
+		),
+		mcp.WithString("suffix",
+			mcp.Description("The suffix that distinguishes the green job ID (default: -green)"),
+		),
+		mcp.WithString("health_timeout",
+			mcp.Description("How long to wait for the green job to become healthy, e.g. 5m (default: 5m; shortened to end 30s before the call times out)"),
+		),
+		mcp.WithBoolean("keep_blue",
+			mcp.Description("Leave the blue job running after the cutover (default: false)"),
+		),
 	)
-	s.AddTool(runJobTool, RunJobHandler(nomadClient, logger))
+	s.AddTool(deployBlueGreenTool, DeployBlueGreenHandler(nomadClient, submissions, logger))
 
 	// Stop job tool
 	stopJobTool := mcp.NewTool("stop_job",
-		mcp.WithDescription("Stop a running job"),
+		mcp.WithDescription("Stop a running job and return the ID of the evaluation that stops it; with monitor, also wait until all of its allocations have stopped"),
 		mcp.WithString("job_id",
 			mcp.Required(),
 			mcp.Description("The ID of the job to stop"),
@@ -66,9 +291,82 @@ func RegisterJobTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log
 		mcp.WithBoolean("purge",
 			mcp.Description("Purge the job from Nomad instead of just stopping it"),
 		),
+		mcp.WithBoolean("global",
+			mcp.Description("Stop the job in every region of a multi-region job (default: false)"),
+		),
+		mcp.WithBoolean("no_shutdown_delay",
+			mcp.Description("Ignore the shutdown_delay of the job's groups and tasks so they are stopped at once (default: false)"),
+		),
+		mcp.WithBoolean("monitor",
+			mcp.Description("Wait until every allocation of the job has stopped, up to monitor_timeout (default: false)"),
+		),
+		mcp.WithString("monitor_timeout",
+			mcp.Description("With monitor, how long to wait for the allocations to stop, e.g. 2m (default: 5m, cut short to return before the call's timeout)"),
+		),
 	)
 	s.AddTool(stopJobTool, StopJobHandler(nomadClient, logger))
 
+	// Revert job tool
+	revertJobTool := mcp.NewTool("revert_job",
+		mcp.WithDescription("Revert a job to an earlier version, like nomad job revert. The version is registered again as a new version; list versions with get_job_versions"),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job to revert"),
+		),
+		mcp.WithNumber("version",
+			mcp.Required(),
+			mcp.Description("The job version to revert to"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithNumber("enforce_prior_version",
+			mcp.Description("Only revert while the job is still at this version, so a change made meanwhile is not overwritten"),
+		),
+		mcp.WithString("consul_token",
+			mcp.Description("Consul token proving access to the Consul policies of the reverted version, if the cluster requires one"),
+		),
+		mcp.WithString("vault_token",
+			mcp.Description("Vault token proving access to the Vault policies of the reverted version, if the cluster requires one"),
+		),
+	)
+	s.AddTool(revertJobTool, RevertJobHandler(nomadClient, logger))
+
+	// Evaluate job tool
+	evaluateJobTool := mcp.NewTool("evaluate_job",
+		mcp.WithDescription("Force a new evaluation of a job, like nomad job eval, so the scheduler reconciles its allocations again"),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job to evaluate"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithBoolean("force_reschedule",
+			mcp.Description("Reschedule failed allocations even if their reschedule policy is exhausted or delayed (default: false)"),
+		),
+	)
+	s.AddTool(evaluateJobTool, EvaluateJobHandler(nomadClient, logger))
+
+	// Retry failed allocations tool
+	retryFailedAllocationsTool := mcp.NewTool("retry_failed_allocations",
+		mcp.WithDescription("Reschedule the failed allocations of a job that have no replacement by forcing an evaluation with force_reschedule, and optionally wait to see whether they get replaced"),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithBoolean("monitor",
+			mcp.Description("Wait until every failed allocation has a running replacement, up to monitor_timeout (default: false)"),
+		),
+		mcp.WithString("monitor_timeout",
+			mcp.Description("With monitor, how long to wait for the replacements, e.g. 2m (default: 5m, cut short to return before the call's timeout)"),
+		),
+	)
+	s.AddTool(retryFailedAllocationsTool, RetryFailedAllocationsHandler(nomadClient, logger))
+
 	// Scale job tool
 	scaleJobTool := mcp.NewTool("scale_job",
 		mcp.WithDescription("Scale a job's task group"),
@@ -93,6 +391,7 @@ func RegisterJobTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log
 	// Get job allocations tool
 	getJobAllocationsTool := mcp.NewTool("get_job_allocations",
 		mcp.WithDescription("Get allocations for a job"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("job_id",
 			mcp.Required(),
 			mcp.Description("The ID of the job to get allocations for"),
@@ -106,6 +405,7 @@ func RegisterJobTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log
 	// Get job evaluations tool
 	getJobEvaluationsTool := mcp.NewTool("get_job_evaluations",
 		mcp.WithDescription("Get evaluations for a job"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("job_id",
 			mcp.Required(),
 			mcp.Description("The ID of the job to get evaluations for"),
@@ -119,6 +419,7 @@ func RegisterJobTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log
 	// Get job deployments tool
 	getJobDeploymentsTool := mcp.NewTool("get_job_deployments",
 		mcp.WithDescription("Get deployments for a job"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("job_id",
 			mcp.Required(),
 			mcp.Description("The ID of the job to get deployments for"),
@@ -132,6 +433,7 @@ func RegisterJobTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log
 	// Get job summary tool
 	getJobSummaryTool := mcp.NewTool("get_job_summary",
 		mcp.WithDescription("Get summary for a job"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("job_id",
 			mcp.Required(),
 			mcp.Description("The ID of the job to get summary for"),
@@ -144,7 +446,8 @@ func RegisterJobTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log
 
 	// Get job services tool
 	getJobServicesTool := mcp.NewTool("get_job_services",
-		mcp.WithDescription("Get services for a job"),
+		mcp.WithDescription("Get the Nomad service registrations of a job's allocations, with their address and port"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("job_id",
 			mcp.Required(),
 			mcp.Description("The ID of the job to get services for"),
@@ -154,98 +457,623 @@ func RegisterJobTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log
 		),
 	)
 	s.AddTool(getJobServicesTool, GetJobServicesHandler(nomadClient, logger))
+
+	// Purge dead jobs tool
+	purgeDeadJobsTool := mcp.NewTool("purge_dead_jobs",
+		mcp.WithDescription("Find dead jobs whose last submission is older than a threshold and purge them (dry-run by default)"),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace to scan, or * for all namespaces (default: default)"),
+		),
+		mcp.WithString("older_than",
+			mcp.Description("Only purge jobs last submitted before this duration ago, e.g. 24h or 7d (default: 24h)"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Only report the jobs that would be purged (default: true)"),
+		),
+	)
+	s.AddTool(purgeDeadJobsTool, PurgeDeadJobsHandler(nomadClient, logger))
+
+	// Find jobs by meta tool
+	findJobsByMetaTool := mcp.NewTool("find_jobs_by_meta",
+		mcp.WithDescription("Find jobs whose Meta matches every given key, e.g. owner=teamX, across namespaces. Useful for ownership lookups during incidents"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("meta",
+			mcp.Required(),
+			mcp.Description("Comma-separated key=value pairs to match; a bare key (or key=*) only requires the key to be set"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace to search (default: * for all namespaces)"),
+		),
+		mcp.WithString("status",
+			mcp.Description("Only search jobs with this status"),
+			mcp.Enum("pending", "running", "dead", ""),
+		),
+	)
+	s.AddTool(findJobsByMetaTool, FindJobsByMetaHandler(nomadClient, logger))
+}
+
+// ListJobsHandler returns a handler for listing jobs
+func ListJobsHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		statusFilter := ""
+		if s, ok := arguments["status"].(string); ok && s != "" {
+			statusFilter = s
+		}
+
+		includeRollout := false
+		if r, ok := arguments["include_rollout"].(bool); ok {
+			includeRollout = r
+		}
+
+		if allRegions, _ := arguments["all_regions"].(bool); allRegions {
+			results, err := fanOutRegions(ctx, client, func(ctx context.Context) ([]enhancedJobDetail, error) {
+				return listJobDetails(ctx, client, namespace, statusFilter, includeRollout, logger)
+			})
+			if err != nil {
+				requestLogf(ctx, logger, "Error listing regions: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to list regions", err), nil
+			}
+			jobs, failures := mergeRegionResults(results, func(region string, job enhancedJobDetail) enhancedJobDetail {
+				job.Region = region
+				return job
+			})
+			return regionFanOutResult(ctx, jobs, failures, len(results), "jobs", logger)
+		}
+
+		detailedJobs, err := listJobDetails(ctx, client, namespace, statusFilter, includeRollout, logger)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing initial jobs: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list jobs", err), nil
+		}
+
+		jobsJSON, err := json.MarshalIndent(detailedJobs, "", "  ")
+		if err != nil {
+			requestLogf(ctx, logger, "Error marshalling detailed job list: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to format detailed job list", err), nil
+		}
+
+		return mcp.NewToolResultText(string(jobsJSON)), nil
+	}
+}
+
+// enhancedJobDetail is one list_jobs entry.
+type enhancedJobDetail struct {
+	ID                string                   `json:"ID"`
+	ParentID          string                   `json:"ParentID"`
+	Name              string                   `json:"Name"`
+	Type              string                   `json:"Type"`
+	Priority          int                      `json:"Priority"`
+	Status            string                   `json:"Status"`
+	StatusDescription string                   `json:"StatusDescription"`
+	JobSummary        *types.JobSummaryDetails `json:"JobSummary"`
+	CreateIndex       int                      `json:"CreateIndex"`
+	ModifyIndex       int                      `json:"ModifyIndex"`
+	JobModifyIndex    int                      `json:"JobModifyIndex"`
+	LatestDeployment  *jobRolloutDeployment    `json:"LatestDeployment,omitempty"`
+	PendingEvals      *int                     `json:"PendingEvaluations,omitempty"`
+	Region            string                   `json:"Region,omitempty"` // set by all_regions
+}
+
+// listJobDetails lists the jobs of a namespace with their summaries, and their rollout
+// annotations when includeRollout is set. Jobs that cannot be read are skipped.
+func listJobDetails(ctx context.Context, client utils.JobAPI, namespace, statusFilter string, includeRollout bool, logger *log.Logger) ([]enhancedJobDetail, error) {
+	initialJobStubs, err := client.ListJobs(ctx, namespace, statusFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var detailedJobs []enhancedJobDetail
+
+	var jobNamespaces []string
+
+	for _, stub := range initialJobStubs {
+		jobID := stub.ID
+		// A listing of all namespaces (*) reads each job in its own namespace.
+		jobNamespace := stub.Namespace
+		if jobNamespace == "" {
+			jobNamespace = namespace
+		}
+
+		fullJob, errJob := client.GetJob(ctx, jobID, jobNamespace)
+		if errJob != nil {
+			requestLogf(ctx, logger, "Error getting full details for job %s in namespace %s: %v. Skipping this job.", jobID, jobNamespace, errJob)
+			continue
+		}
+
+		item := enhancedJobDetail{
+			ID:                fullJob.ID,
+			ParentID:          fullJob.ParentID,
+			Name:              fullJob.Name,
+			Type:              fullJob.Type,
+			Priority:          fullJob.Priority,
+			Status:            fullJob.Status,
+			StatusDescription: "",
+			CreateIndex:       fullJob.CreateIndex,
+			ModifyIndex:       fullJob.ModifyIndex,
+			JobModifyIndex:    fullJob.JobModifyIndex,
+			JobSummary:        nil,
+		}
+
+		basicSummaryValue, errSummary := client.GetJobSummary(ctx, jobID, jobNamespace)
+		if errSummary == nil {
+			detailedSummaryForOutput := types.JobSummaryDetails{
+				JobID:       fullJob.ID,
+				Namespace:   jobNamespace,
+				Summary:     basicSummaryValue.Summary,
+				Children:    basicSummaryValue.Children,
+				CreateIndex: basicSummaryValue.CreateIndex,
+				ModifyIndex: basicSummaryValue.ModifyIndex,
+			}
+			item.JobSummary = &detailedSummaryForOutput
+		} else {
+			requestLogf(ctx, logger, "Error getting summary for job %s in namespace %s: %v. JobSummary will be null.", jobID, jobNamespace, errSummary)
+		}
+
+		detailedJobs = append(detailedJobs, item)
+		jobNamespaces = append(jobNamespaces, jobNamespace)
+	}
+
+	if includeRollout {
+		jobIDs := make([]string, len(detailedJobs))
+		for i, job := range detailedJobs {
+			jobIDs[i] = job.ID
+		}
+		rollouts := fetchJobRollouts(ctx, client, jobIDs, jobNamespaces, logger)
+		for i := range detailedJobs {
+			detailedJobs[i].LatestDeployment = rollouts[i].deployment
+			detailedJobs[i].PendingEvals = rollouts[i].pendingEvals
+		}
+	}
+
+	return detailedJobs, nil
+}
+
+// jobRolloutDeployment is the compact deployment view attached to list_jobs entries.
+type jobRolloutDeployment struct {
+	ID                string `json:"ID"`
+	Status            string `json:"Status"`
+	StatusDescription string `json:"StatusDescription"`
+	JobVersion        int    `json:"JobVersion"`
+}
+
+// jobRollout holds the per-job rollout annotations gathered by fetchJobRollouts.
+type jobRollout struct {
+	deployment *jobRolloutDeployment
+	// pendingEvals is nil when the evaluations could not be listed
+	pendingEvals *int
+}
+
+// fetchJobRollouts concurrently looks up the latest deployment and pending evaluations for each job,
+// in the namespace of the same index. Results are index-aligned with jobIDs; lookup failures are
+// logged and leave the annotation empty.
+func fetchJobRollouts(ctx context.Context, client utils.JobAPI, jobIDs, namespaces []string, logger *log.Logger) []jobRollout {
+	rollouts := make([]jobRollout, len(jobIDs))
+	forEachConcurrently(len(jobIDs), func(i int) {
+		jobID, namespace := jobIDs[i], namespaces[i]
+		deployment, err := client.GetJobDeployment(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting latest deployment for job %s in namespace %s: %v", jobID, namespace, err)
+		} else if deployment.ID != "" {
+			rollouts[i].deployment = &jobRolloutDeployment{
+				ID:                deployment.ID,
+				Status:            deployment.Status,
+				StatusDescription: deployment.StatusDescription,
+				JobVersion:        deployment.JobVersion,
+			}
+		}
+
+		evaluations, err := client.ListJobEvaluations(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing evaluations for job %s in namespace %s: %v", jobID, namespace, err)
+			return
+		}
+		pending := 0
+		for _, eval := range evaluations {
+			if eval.Status == "pending" {
+				pending++
+			}
+		}
+		rollouts[i].pendingEvals = &pending
+	})
+	return rollouts
+}
+
+// GetJobHandler returns a handler for getting job details
+func GetJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+
+		fields, err := fieldsArgument(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		job, err := client.GetJob(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
+		}
+
+		projected, err := projectFields(job, fields)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to project fields", err), nil
+		}
+
+		jobJSON, err := json.MarshalIndent(projected, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format job", err), nil
+		}
+
+		return mcp.NewToolResultText(string(jobJSON)), nil
+	}
+}
+
+// JobSource is the result of get_job_submission
+type JobSource struct {
+	JobID     string `json:"JobID"`
+	Namespace string `json:"Namespace"`
+	Version   int    `json:"Version"`
+	// Format is hcl2, hcl1 or json
+	Format        string            `json:"Format"`
+	Source        string            `json:"Source"`
+	VariableFlags map[string]string `json:"VariableFlags,omitempty"`
+	Variables     string            `json:"Variables,omitempty"`
+	// Origin is set by get_job_source: submission, or job_spec when Nomad stored no
+	// submission and Source is the job as the API returns it
+	Origin string `json:"Origin,omitempty"`
+	Note   string `json:"Note,omitempty"`
+}
+
+// jobSourceFormat returns the format Nomad recorded for a submission, or detects it from
+// the source when Nomad left it empty or reported an unknown one.
+func jobSourceFormat(submission types.JobSubmission) string {
+	switch format := strings.ToLower(submission.Format); format {
+	case "hcl2", "hcl1", "json":
+		return format
+	case "hcl":
+		return "hcl2"
+	}
+	source := strings.TrimSpace(submission.Source)
+	if strings.HasPrefix(source, "{") && json.Valid([]byte(source)) {
+		return "json"
+	}
+	return "hcl2"
+}
+
+// GetJobSubmissionHandler returns a handler for retrieving the source of a job version
+func GetJobSubmissionHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		var version int
+		if v, ok := arguments["version"].(float64); ok {
+			if v < 0 {
+				return mcp.NewToolResultError("version must not be negative"), nil
+			}
+			version = int(v)
+		} else {
+			job, err := client.GetJob(ctx, jobID, namespace)
+			if err != nil {
+				requestLogf(ctx, logger, "Error getting job: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
+			}
+			version = job.Version
+		}
+
+		submission, err := client.GetJobSubmission(ctx, jobID, namespace, version)
+		if err != nil {
+			if isNotFound(err) {
+				return mcp.NewToolResultError(fmt.Sprintf("No source is stored for version %d of job %s: it was registered without one (e.g. as API JSON) or the version no longer exists", version, jobID)), nil
+			}
+			requestLogf(ctx, logger, "Error getting job submission: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job submission", err), nil
+		}
+
+		source := JobSource{
+			JobID:         jobID,
+			Namespace:     namespace,
+			Version:       version,
+			Format:        jobSourceFormat(submission),
+			Source:        submission.Source,
+			VariableFlags: submission.VariableFlags,
+			Variables:     submission.Variables,
+		}
+
+		sourceJSON, err := json.MarshalIndent(source, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format job submission", err), nil
+		}
+
+		return mcp.NewToolResultText(string(sourceJSON)), nil
+	}
+}
+
+// GetJobSourceHandler returns a handler for the source of a job version: the submission
+// it was registered from, or its JSON spec when Nomad has none
+func GetJobSourceHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		var version int
+		if v, ok := arguments["version"].(float64); ok {
+			if v < 0 {
+				return mcp.NewToolResultError("version must not be negative"), nil
+			}
+			version = int(v)
+		} else {
+			job, err := client.GetJob(ctx, jobID, namespace)
+			if err != nil {
+				requestLogf(ctx, logger, "Error getting job: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
+			}
+			version = job.Version
+		}
+
+		source := JobSource{JobID: jobID, Namespace: namespace, Version: version}
+		submission, err := client.GetJobSubmission(ctx, jobID, namespace, version)
+		var unsupported *utils.UnsupportedFeatureError
+		switch {
+		case err == nil && submission.Source != "":
+			source.Origin = "submission"
+			source.Format = jobSourceFormat(submission)
+			source.Source = submission.Source
+			source.VariableFlags = submission.VariableFlags
+			source.Variables = submission.Variables
+		case err == nil || isNotFound(err) || errors.As(err, &unsupported):
+			spec, err := client.GetJobVersionSpec(ctx, jobID, namespace, version)
+			if err != nil {
+				requestLogf(ctx, logger, "Error getting job version: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to get job version", err), nil
+			}
+			spec, err = cloneJobDefinition(spec)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("Failed to format job spec", err), nil
+			}
+			specJSON, err := json.MarshalIndent(map[string]interface{}{"Job": spec}, "", "  ")
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("Failed to format job spec", err), nil
+			}
+			source.Origin = "job_spec"
+			source.Format = "json"
+			source.Source = string(specJSON)
+			source.Note = fmt.Sprintf("Nomad stored no submission for version %d (the job was registered without its source, e.g. as API JSON, or Nomad is older than 1.6). Source is the job as Nomad stores it, with defaults filled in and HCL variables already resolved; run_job accepts it as is.", version)
+		default:
+			requestLogf(ctx, logger, "Error getting job submission: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job submission", err), nil
+		}
+
+		sourceJSON, err := json.MarshalIndent(source, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format job source", err), nil
+		}
+
+		return mcp.NewToolResultText(string(sourceJSON)), nil
+	}
+}
+
+// GetTaskGroupHandler returns a handler for extracting one task group from a job
+func GetTaskGroupHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+
+		groupName, ok := arguments["group"].(string)
+		if !ok || groupName == "" {
+			return mcp.NewToolResultError("group is required"), nil
+		}
+
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		job, err := client.GetJob(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
+		}
+
+		var names []string
+		for _, tg := range job.TaskGroups {
+			if tg.Name == groupName {
+				groupJSON, err := json.MarshalIndent(tg, "", "  ")
+				if err != nil {
+					return mcp.NewToolResultErrorFromErr("Failed to format task group", err), nil
+				}
+				return mcp.NewToolResultText(string(groupJSON)), nil
+			}
+			names = append(names, tg.Name)
+		}
+
+		return mcp.NewToolResultError(fmt.Sprintf("task group %q not found in job %s (groups: %s)", groupName, jobID, strings.Join(names, ", "))), nil
+	}
+}
+
+// taskDefinition is the get_task response: the task plus the group it belongs to.
+type taskDefinition struct {
+	Group string     `json:"Group"`
+	Task  types.Task `json:"Task"`
 }
 
-// ListJobsHandler returns a handler for listing jobs
-func ListJobsHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// GetTaskHandler returns a handler for extracting one task from a job
+func GetTaskHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		arguments, ok := request.Params.Arguments.(map[string]interface{})
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments"), nil
 		}
 
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+
+		taskName, ok := arguments["task"].(string)
+		if !ok || taskName == "" {
+			return mcp.NewToolResultError("task is required"), nil
+		}
+
+		groupName, _ := arguments["group"].(string)
 		namespace := utils.EffectiveToolNamespace(arguments)
 
-		statusFilter := ""
-		if s, ok := arguments["status"].(string); ok && s != "" {
-			statusFilter = s
+		job, err := client.GetJob(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
 		}
 
-		initialJobStubs, err := client.ListJobs(ctx, namespace, statusFilter)
+		var matches []taskDefinition
+		var names []string
+		for _, tg := range job.TaskGroups {
+			if groupName != "" && tg.Name != groupName {
+				continue
+			}
+			for _, task := range tg.Tasks {
+				if task.Name == taskName {
+					matches = append(matches, taskDefinition{Group: tg.Name, Task: task})
+				}
+				names = append(names, tg.Name+"/"+task.Name)
+			}
+		}
+
+		if len(matches) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("task %q not found in job %s (tasks: %s)", taskName, jobID, strings.Join(names, ", "))), nil
+		}
+		if len(matches) > 1 {
+			groups := make([]string, 0, len(matches))
+			for _, m := range matches {
+				groups = append(groups, m.Group)
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("task %q exists in several groups (%s); pass group to choose one", taskName, strings.Join(groups, ", "))), nil
+		}
+
+		taskJSON, err := json.MarshalIndent(matches[0], "", "  ")
 		if err != nil {
-			logger.Printf("Error listing initial jobs: %v", err)
-			return mcp.NewToolResultErrorFromErr("Failed to list jobs", err), nil
+			return mcp.NewToolResultErrorFromErr("Failed to format task", err), nil
+		}
+
+		return mcp.NewToolResultText(string(taskJSON)), nil
+	}
+}
+
+// RunJobHandler returns a handler for running a job
+func RunJobHandler(client utils.JobAPI, submissions *JobSubmissionLocks, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
 		}
 
-		type EnhancedJobDetail struct {
-			ID                string                   `json:"ID"`
-			ParentID          string                   `json:"ParentID"`
-			Name              string                   `json:"Name"`
-			Type              string                   `json:"Type"`
-			Priority          int                      `json:"Priority"`
-			Status            string                   `json:"Status"`
-			StatusDescription string                   `json:"StatusDescription"`
-			JobSummary        *types.JobSummaryDetails `json:"JobSummary"`
-			CreateIndex       int                      `json:"CreateIndex"`
-			ModifyIndex       int                      `json:"ModifyIndex"`
-			JobModifyIndex    int                      `json:"JobModifyIndex"`
+		jobSpec, ok := arguments["job_spec"].(string)
+		if !ok || jobSpec == "" {
+			return mcp.NewToolResultError("job_spec is required"), nil
 		}
 
-		var detailedJobs []EnhancedJobDetail
+		// Only an explicit argument overrides the spec; NOMAD_NAMESPACE must not rewrite submitted jobs.
+		namespace := ""
+		if ns, ok := arguments["namespace"].(string); ok {
+			namespace = strings.TrimSpace(ns)
+		}
 
-		for _, stub := range initialJobStubs {
-			jobID := stub.ID
+		detach := false
+		if d, ok := arguments["detach"].(bool); ok {
+			detach = d
+		}
 
-			fullJob, errJob := client.GetJob(ctx, jobID, namespace)
-			if errJob != nil {
-				logger.Printf("Error getting full details for job %s in namespace %s: %v. Skipping this job.", jobID, namespace, errJob)
-				continue
+		var result types.JobRegisterResponse
+		var err error
+		jobModifyIndex, enforceIndex := arguments["job_modify_index"].(float64)
+		if skip, _ := arguments["skip_unchanged"].(bool); skip {
+			job, err := client.ParseJobSpec(ctx, jobSpec)
+			if err != nil {
+				requestLogf(ctx, logger, "Error parsing job: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to parse job", err), nil
 			}
-
-			item := EnhancedJobDetail{
-				ID:                fullJob.ID,
-				ParentID:          fullJob.ParentID,
-				Name:              fullJob.Name,
-				Type:              fullJob.Type,
-				Priority:          fullJob.Priority,
-				Status:            fullJob.Status,
-				StatusDescription: "",
-				CreateIndex:       fullJob.CreateIndex,
-				ModifyIndex:       fullJob.ModifyIndex,
-				JobModifyIndex:    fullJob.JobModifyIndex,
-				JobSummary:        nil,
-			}
-
-			basicSummaryValue, errSummary := client.GetJobSummary(ctx, jobID, namespace)
-			if errSummary == nil {
-				detailedSummaryForOutput := types.JobSummaryDetails{
-					JobID:       fullJob.ID,
-					Namespace:   namespace,
-					Summary:     basicSummaryValue.Summary,
-					Children:    basicSummaryValue.Children,
-					CreateIndex: basicSummaryValue.CreateIndex,
-					ModifyIndex: basicSummaryValue.ModifyIndex,
-				}
-				item.JobSummary = &detailedSummaryForOutput
+			planNamespace := namespace
+			if planNamespace != "" {
+				job["Namespace"] = planNamespace
 			} else {
-				logger.Printf("Error getting summary for job %s in namespace %s: %v. JobSummary will be null.", jobID, namespace, errSummary)
+				planNamespace, _ = job["Namespace"].(string)
+			}
+			plan, err := client.PlanJob(ctx, job, planNamespace)
+			if err != nil {
+				requestLogf(ctx, logger, "Error planning job: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to plan job", err), nil
+			}
+			if !jobPlanChanges(plan.Diff) {
+				jobID, _ := job["ID"].(string)
+				return mcp.NewToolResultText(fmt.Sprintf("Job %s is unchanged (JobModifyIndex %d); nothing was submitted", jobID, plan.JobModifyIndex)), nil
+			}
+			// Register at the planned index so a change made since the plan is not overwritten.
+			if !enforceIndex {
+				jobModifyIndex, enforceIndex = float64(plan.JobModifyIndex), true
 			}
-
-			detailedJobs = append(detailedJobs, item)
 		}
-
-		jobsJSON, err := json.MarshalIndent(detailedJobs, "", "  ")
+		if submissions == nil && !enforceIndex {
+			result, err = client.RunJob(ctx, jobSpec, namespace, detach)
+		} else {
+			result, err = runJobWithIndex(ctx, client, submissions, jobSpec, namespace, detach, int(jobModifyIndex), enforceIndex)
+		}
 		if err != nil {
-			logger.Printf("Error marshalling detailed job list: %v", err)
-			return mcp.NewToolResultErrorFromErr("Failed to format detailed job list", err), nil
+			requestLogf(ctx, logger, "Error running job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to run job", err), nil
 		}
 
-		return mcp.NewToolResultText(string(jobsJSON)), nil
+		return jobResultWithWarnings(result, result.Warnings)
 	}
 }
 
-// GetJobHandler returns a handler for getting job details
-func GetJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// StopJobResult is the result of stop_job
+type StopJobResult struct {
+	types.JobDeregisterResponse
+	// AllocationsStopped and RunningAllocations are only set with monitor
+	AllocationsStopped *bool    `json:"AllocationsStopped,omitempty"`
+	RunningAllocations []string `json:"RunningAllocations,omitempty"`
+}
+
+// StopJobHandler returns a handler for stopping a job
+func StopJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		arguments, ok := request.Params.Arguments.(map[string]interface{})
 		if !ok {
@@ -259,56 +1087,85 @@ func GetJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Context
 
 		namespace := utils.EffectiveToolNamespace(arguments)
 
-		job, err := client.GetJob(ctx, jobID, namespace)
+		var opts types.JobStopOptions
+		opts.Purge, _ = arguments["purge"].(bool)
+		opts.Global, _ = arguments["global"].(bool)
+		opts.NoShutdownDelay, _ = arguments["no_shutdown_delay"].(bool)
+
+		monitor, _ := arguments["monitor"].(bool)
+		monitorTimeout, err := monitorTimeoutArgument(arguments)
 		if err != nil {
-			logger.Printf("Error getting job: %v", err)
-			return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		jobJSON, err := json.MarshalIndent(job, "", "  ")
+		result, err := client.StopJob(ctx, jobID, namespace, opts)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format job", err), nil
+			requestLogf(ctx, logger, "Error stopping job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to stop job", err), nil
 		}
 
-		return mcp.NewToolResultText(string(jobJSON)), nil
+		stopped := StopJobResult{JobDeregisterResponse: result}
+		if monitor {
+			running, err := waitForJobAllocationsStopped(ctx, client, jobID, namespace, monitorWindow(ctx, monitorTimeout))
+			if err != nil {
+				requestLogf(ctx, logger, "Error monitoring job allocations: %v", err)
+				return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Job %s was stopped (evaluation %s) but its allocations could not be monitored", jobID, result.EvalID), err), nil
+			}
+			allStopped := len(running) == 0
+			stopped.AllocationsStopped = &allStopped
+			stopped.RunningAllocations = running
+		}
+
+		return jobResultWithWarnings(stopped, result.Warnings)
 	}
 }
 
-// RunJobHandler returns a handler for running a job
-func RunJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// RevertJobHandler returns a handler for reverting a job to an earlier version
+func RevertJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		arguments, ok := request.Params.Arguments.(map[string]interface{})
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments"), nil
 		}
 
-		jobSpec, ok := arguments["job_spec"].(string)
-		if !ok || jobSpec == "" {
-			return mcp.NewToolResultError("job_spec is required"), nil
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
 		}
-
-		detach := false
-		if d, ok := arguments["detach"].(bool); ok {
-			detach = d
+		version, ok := arguments["version"].(float64)
+		if !ok {
+			return mcp.NewToolResultError("version is required"), nil
+		}
+		if version < 0 || version != math.Trunc(version) {
+			return mcp.NewToolResultError("version must be a whole number of at least 0"), nil
 		}
 
-		result, err := client.RunJob(ctx, jobSpec, detach)
-		if err != nil {
-			logger.Printf("Error running job: %v", err)
-			return mcp.NewToolResultErrorFromErr("Failed to run job", err), nil
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		var opts types.JobRevertOptions
+		if prior, ok := arguments["enforce_prior_version"].(float64); ok {
+			priorVersion := int(prior)
+			opts.EnforcePriorVersion = &priorVersion
 		}
+		opts.ConsulToken, _ = arguments["consul_token"].(string)
+		opts.VaultToken, _ = arguments["vault_token"].(string)
 
-		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		result, err := client.RevertJob(ctx, jobID, namespace, int(version), opts)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format result", err), nil
+			requestLogf(ctx, logger, "Error reverting job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to revert job", err), nil
 		}
 
-		return mcp.NewToolResultText(string(resultJSON)), nil
+		message := fmt.Sprintf("Job %s reverted to version %d successfully", jobID, int(version))
+		if result.EvalID != "" {
+			message += fmt.Sprintf(" (evaluation %s)", result.EvalID)
+		}
+		return withNomadWarnings(mcp.NewToolResultText(message), result.Warnings), nil
 	}
 }
 
-// StopJobHandler returns a handler for stopping a job
-func StopJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// EvaluateJobHandler returns a handler for forcing a new evaluation of a job
+func EvaluateJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		arguments, ok := request.Params.Arguments.(map[string]interface{})
 		if !ok {
@@ -321,24 +1178,76 @@ func StopJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Contex
 		}
 
 		namespace := utils.EffectiveToolNamespace(arguments)
+		forceReschedule, _ := arguments["force_reschedule"].(bool)
 
-		purge := false
-		if p, ok := arguments["purge"].(bool); ok {
-			purge = p
-		}
-
-		result, err := client.StopJob(ctx, jobID, namespace, purge)
+		result, err := client.CreateJobEvaluation(ctx, jobID, namespace, forceReschedule)
 		if err != nil {
-			logger.Printf("Error stopping job: %v", err)
-			return mcp.NewToolResultErrorFromErr("Failed to stop job", err), nil
+			requestLogf(ctx, logger, "Error evaluating job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to evaluate job", err), nil
 		}
 
-		resultJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format result", err), nil
+		return jobResultWithWarnings(result, result.Warnings)
+	}
+}
+
+// defaultMonitorTimeout bounds how long stop_job and retry_failed_allocations wait for
+// allocations with monitor.
+const defaultMonitorTimeout = 5 * time.Minute
+
+// monitorPollInterval is how often a monitoring job tool checks the job's allocations.
+const monitorPollInterval = 2 * time.Second
+
+// monitorDeadlineMargin is kept between the end of a monitor wait and the call's deadline, so
+// the result is returned before TimeoutMiddleware gives up on the call.
+const monitorDeadlineMargin = 5 * time.Second
+
+// monitorWindow cuts a monitor timeout short by the call's deadline. A window of 0 checks the
+// allocations once.
+func monitorWindow(ctx context.Context, timeout time.Duration) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = max(min(timeout, time.Until(deadline)-monitorDeadlineMargin), 0)
+	}
+	return timeout
+}
+
+// monitorTimeoutArgument reads the monitor_timeout argument of a monitoring job tool.
+func monitorTimeoutArgument(arguments map[string]interface{}) (time.Duration, error) {
+	t, _ := arguments["monitor_timeout"].(string)
+	if t == "" {
+		return defaultMonitorTimeout, nil
+	}
+	timeout, err := time.ParseDuration(t)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("monitor_timeout must be a positive duration, e.g. 2m: %q", t)
+	}
+	return timeout, nil
+}
+
+// waitForJobAllocationsStopped polls the job's allocations until none is pending or running,
+// or timeout elapses. It returns the IDs of the allocations still running; a job purged
+// before its allocations could be listed counts as stopped.
+func waitForJobAllocationsStopped(ctx context.Context, client utils.JobAPI, jobID, namespace string, timeout time.Duration) ([]string, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		allocs, err := client.ListJobAllocations(ctx, jobID, namespace)
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+		var running []string
+		for _, alloc := range allocs {
+			if alloc.ClientStatus == "running" || alloc.ClientStatus == "pending" {
+				running = append(running, alloc.ID)
+			}
+		}
+		if len(running) == 0 || !time.Now().Before(deadline) {
+			return running, nil
 		}
 
-		return mcp.NewToolResultText(string(resultJSON)), nil
+		select {
+		case <-ctx.Done():
+			return running, nil
+		case <-time.After(min(monitorPollInterval, time.Until(deadline))):
+		}
 	}
 }
 
@@ -367,9 +1276,9 @@ func ScaleJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Conte
 
 		namespace := utils.EffectiveToolNamespace(arguments)
 
-		err := client.ScaleTaskGroup(ctx, jobID, group, int(count), namespace)
+		scaled, err := client.ScaleTaskGroup(ctx, jobID, group, int(count), namespace)
 		if err != nil {
-			logger.Printf("Error scaling job: %v", err)
+			requestLogf(ctx, logger, "Error scaling job: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to scale job", err), nil
 		}
 
@@ -382,7 +1291,7 @@ func ScaleJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Conte
 			return mcp.NewToolResultErrorFromErr("Failed to format result", err), nil
 		}
 
-		return mcp.NewToolResultText(string(resultJSON)), nil
+		return withNomadWarnings(mcp.NewToolResultText(string(resultJSON)), scaled.Warnings), nil
 	}
 }
 
@@ -403,7 +1312,7 @@ func GetJobAllocationsHandler(client utils.JobAPI, logger *log.Logger) func(cont
 
 		allocations, err := client.ListJobAllocations(ctx, jobID, namespace)
 		if err != nil {
-			logger.Printf("Error getting job allocations: %v", err)
+			requestLogf(ctx, logger, "Error getting job allocations: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get job allocations", err), nil
 		}
 
@@ -433,7 +1342,7 @@ func GetJobEvaluationsHandler(client utils.JobAPI, logger *log.Logger) func(cont
 
 		evaluations, err := client.ListJobEvaluations(ctx, jobID, namespace)
 		if err != nil {
-			logger.Printf("Error getting job evaluations: %v", err)
+			requestLogf(ctx, logger, "Error getting job evaluations: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get job evaluations", err), nil
 		}
 
@@ -463,7 +1372,7 @@ func GetJobDeploymentsHandler(client utils.JobAPI, logger *log.Logger) func(cont
 
 		deployments, err := client.ListJobDeployments(ctx, jobID, namespace)
 		if err != nil {
-			logger.Printf("Error getting job deployments: %v", err)
+			requestLogf(ctx, logger, "Error getting job deployments: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get job deployments", err), nil
 		}
 
@@ -493,7 +1402,7 @@ func GetJobSummaryHandler(client utils.JobAPI, logger *log.Logger) func(context.
 
 		summary, err := client.GetJobSummary(ctx, jobID, namespace)
 		if err != nil {
-			logger.Printf("Error getting job summary: %v", err)
+			requestLogf(ctx, logger, "Error getting job summary: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get job summary", err), nil
 		}
 
@@ -523,7 +1432,7 @@ func GetJobServicesHandler(client utils.JobAPI, logger *log.Logger) func(context
 
 		services, err := client.ListJobServices(ctx, jobID, namespace)
 		if err != nil {
-			logger.Printf("Error getting job services: %v", err)
+			requestLogf(ctx, logger, "Error getting job services: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get job services", err), nil
 		}
 
@@ -535,3 +1444,356 @@ func GetJobServicesHandler(client utils.JobAPI, logger *log.Logger) func(context
 		return mcp.NewToolResultText(string(servicesJSON)), nil
 	}
 }
+
+// PurgeDeadJobsHandler returns a handler for purging dead jobs older than a threshold
+func PurgeDeadJobsHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		olderThan := 24 * time.Hour
+		if o, ok := arguments["older_than"].(string); ok && o != "" {
+			d, err := parseRelativeDuration(o)
+			if err != nil || d <= 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("older_than must be a positive duration such as 24h or 7d, got %q", o)), nil
+			}
+			olderThan = d
+		}
+
+		dryRun := true
+		if d, ok := arguments["dry_run"].(bool); ok {
+			dryRun = d
+		}
+
+		jobs, err := client.ListJobs(ctx, namespace, "dead")
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing dead jobs: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list dead jobs", err), nil
+		}
+
+		type purgeCandidate struct {
+			ID         string `json:"ID"`
+			Namespace  string `json:"Namespace"`
+			Type       string `json:"Type"`
+			SubmitTime string `json:"SubmitTime"`
+			Purged     bool   `json:"Purged"`
+			Error      string `json:"Error,omitempty"`
+		}
+
+		cutoff := time.Now().Add(-olderThan)
+		candidates := []purgeCandidate{}
+		for _, job := range jobs {
+			// The status filter is not honored by every Nomad version, so check it again here.
+			if job.Status != "" && job.Status != "dead" {
+				continue
+			}
+			if job.SubmitTime == 0 || !time.Unix(0, job.SubmitTime).Before(cutoff) {
+				continue
+			}
+
+			jobNamespace := job.Namespace
+			if jobNamespace == "" {
+				jobNamespace = namespace
+			}
+
+			candidate := purgeCandidate{
+				ID:         job.ID,
+				Namespace:  jobNamespace,
+				Type:       job.Type,
+				SubmitTime: time.Unix(0, job.SubmitTime).UTC().Format(time.RFC3339),
+			}
+
+			if !dryRun {
+				if _, err := client.StopJob(ctx, job.ID, jobNamespace, types.JobStopOptions{Purge: true}); err != nil {
+					requestLogf(ctx, logger, "Error purging job %s in namespace %s: %v", job.ID, jobNamespace, err)
+					candidate.Error = err.Error()
+				} else {
+					candidate.Purged = true
+				}
+			}
+
+			candidates = append(candidates, candidate)
+		}
+
+		result := map[string]interface{}{
+			"dry_run":    dryRun,
+			"namespace":  namespace,
+			"older_than": olderThan.String(),
+			"jobs":       candidates,
+		}
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format result", err), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// jobMetaMatch is one find_jobs_by_meta result.
+type jobMetaMatch struct {
+	ID        string            `json:"ID"`
+	Namespace string            `json:"Namespace"`
+	Type      string            `json:"Type"`
+	Status    string            `json:"Status"`
+	Meta      map[string]string `json:"Meta"`
+}
+
+// parseMetaSelector parses "k=v,k2" into required values; "" means the key only has to exist.
+func parseMetaSelector(selector string) (map[string]string, error) {
+	wanted := map[string]string{}
+	for _, pair := range strings.Split(selector, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if key == "" {
+			return nil, fmt.Errorf("invalid meta selector %q", pair)
+		}
+		if value == "*" {
+			value = ""
+		}
+		wanted[key] = value
+	}
+	if len(wanted) == 0 {
+		return nil, fmt.Errorf("meta must contain at least one key")
+	}
+	return wanted, nil
+}
+
+func metaMatches(meta, wanted map[string]string) bool {
+	for key, value := range wanted {
+		got, ok := meta[key]
+		if !ok || (value != "" && got != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindJobsByMetaHandler returns a handler for finding jobs by Meta keys
+func FindJobsByMetaHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		selector, ok := arguments["meta"].(string)
+		if !ok || selector == "" {
+			return mcp.NewToolResultError("meta is required"), nil
+		}
+		wanted, err := parseMetaSelector(selector)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		namespace := "*"
+		if _, ok := arguments["namespace"]; ok {
+			namespace = utils.EffectiveToolNamespace(arguments)
+		}
+		status, _ := arguments["status"].(string)
+
+		stubs, err := client.ListJobs(ctx, namespace, status)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing jobs: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list jobs", err), nil
+		}
+
+		// Job list stubs carry no Meta, so each job is fetched.
+		jobs := make([]types.Job, len(stubs))
+		errs := make([]error, len(stubs))
+		forEachConcurrently(len(stubs), func(i int) {
+			ns := stubs[i].Namespace
+			if ns == "" {
+				ns = namespace
+			}
+			jobs[i], errs[i] = client.GetJob(ctx, stubs[i].ID, ns)
+		})
+
+		matches := []jobMetaMatch{}
+		for i, job := range jobs {
+			if errs[i] != nil {
+				requestLogf(ctx, logger, "Error getting job %s: %v", stubs[i].ID, errs[i])
+				continue
+			}
+			if !metaMatches(job.Meta, wanted) {
+				continue
+			}
+			matches = append(matches, jobMetaMatch{
+				ID:        job.ID,
+				Namespace: stubs[i].Namespace,
+				Type:      job.Type,
+				Status:    job.Status,
+				Meta:      job.Meta,
+			})
+		}
+
+		matchesJSON, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format jobs", err), nil
+		}
+
+		return mcp.NewToolResultText(string(matchesJSON)), nil
+	}
+}
+
+// decodeJobSpec parses a JSON or HCL job spec through Nomad and decodes it into a typed job.
+func decodeJobSpec(ctx context.Context, client utils.JobAPI, jobSpec string) (types.Job, error) {
+	jobData, err := client.ParseJobSpec(ctx, jobSpec)
+	if err != nil {
+		return types.Job{}, err
+	}
+
+	var job types.Job
+	raw, err := json.Marshal(jobData)
+	if err != nil {
+		return types.Job{}, err
+	}
+	if err := json.Unmarshal(raw, &job); err != nil {
+		return types.Job{}, fmt.Errorf("error decoding job spec: %v", err)
+	}
+	return job, nil
+}
+
+// jobFromArguments loads the job an inspection tool targets: the job_spec argument when
+// present, otherwise the registered job named by job_id.
+func jobFromArguments(ctx context.Context, client utils.JobAPI, arguments map[string]interface{}) (types.Job, error) {
+	if jobSpec, ok := arguments["job_spec"].(string); ok && jobSpec != "" {
+		return decodeJobSpec(ctx, client, jobSpec)
+	}
+	jobID, ok := arguments["job_id"].(string)
+	if !ok || jobID == "" {
+		return types.Job{}, fmt.Errorf("job_id or job_spec is required")
+	}
+	return client.GetJob(ctx, jobID, utils.EffectiveToolNamespace(arguments))
+}
+
+// runJobWithIndex registers a job with a JobModifyIndex check-and-set. Without an explicit
+// index, the job's current index is read while holding its submission lock, so an update made
+// outside this server between the read and the register is rejected rather than overwritten.
+// registerRequestFor returns what to register for a parsed job: the bare job, or a register
+// request carrying the job's submission when the spec was HCL (or already was a register
+// request with one), so Nomad keeps the source the job was written in.
+func registerRequestFor(jobSpec string, jobData map[string]interface{}) interface{} {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(jobSpec), &decoded); err != nil {
+		return map[string]interface{}{"Job": jobData, "Submission": types.JobSubmission{Source: jobSpec, Format: "hcl2"}}
+	}
+	if submission, ok := decoded["Submission"]; ok {
+		return map[string]interface{}{"Job": jobData, "Submission": submission}
+	}
+	return jobData
+}
+
+// jobPlanBookkeepingFields change on every registration, so a diff touching only them is
+// not a change to the job.
+var jobPlanBookkeepingFields = map[string]bool{
+	"CreateIndex":    true,
+	"ModifyIndex":    true,
+	"JobModifyIndex": true,
+	"Version":        true,
+	"SubmitTime":     true,
+}
+
+// jobPlanChanges reports whether a plan diff changes anything besides index and version
+// bookkeeping. A missing diff counts as a change, since there is nothing to compare against.
+func jobPlanChanges(diff *types.JobDiff) bool {
+	if diff == nil {
+		return true
+	}
+	switch diff.Type {
+	case "None":
+		return false
+	case "Added", "Deleted":
+		return true
+	}
+	if fieldDiffsChange(diff.Fields) || objectDiffsChange(diff.Objects) {
+		return true
+	}
+	for _, group := range diff.TaskGroups {
+		if group.Type == "Added" || group.Type == "Deleted" || fieldDiffsChange(group.Fields) || objectDiffsChange(group.Objects) {
+			return true
+		}
+		for _, task := range group.Tasks {
+			if task.Type == "Added" || task.Type == "Deleted" || fieldDiffsChange(task.Fields) || objectDiffsChange(task.Objects) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func fieldDiffsChange(fields []types.FieldDiff) bool {
+	for _, field := range fields {
+		if field.Type != "None" && !jobPlanBookkeepingFields[field.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+func objectDiffsChange(objects []types.ObjectDiff) bool {
+	for _, object := range objects {
+		if object.Type == "Added" || object.Type == "Deleted" || fieldDiffsChange(object.Fields) || objectDiffsChange(object.Objects) {
+			return true
+		}
+	}
+	return false
+}
+
+func runJobWithIndex(ctx context.Context, client utils.JobAPI, submissions *JobSubmissionLocks, jobSpec, namespace string, detach bool, jobModifyIndex int, enforceIndex bool) (types.JobRegisterResponse, error) {
+	jobData, err := client.ParseJobSpec(ctx, jobSpec)
+	if err != nil {
+		return types.JobRegisterResponse{}, err
+	}
+	jobID, _ := jobData["ID"].(string)
+	if jobID == "" {
+		return types.JobRegisterResponse{}, fmt.Errorf("job spec has no ID")
+	}
+	jobNamespace := namespace
+	if jobNamespace == "" {
+		jobNamespace, _ = jobData["Namespace"].(string)
+	}
+	if jobNamespace == "" {
+		jobNamespace = utils.NomadDefaultNamespace
+	}
+
+	if submissions != nil {
+		unlock, err := submissions.Lock(ctx, jobNamespace, jobID)
+		if err != nil {
+			return types.JobRegisterResponse{}, fmt.Errorf("waiting for another submission of job %s: %w", jobID, err)
+		}
+		defer unlock()
+	}
+
+	if !enforceIndex {
+		current, err := client.GetJob(ctx, jobID, jobNamespace)
+		switch {
+		case err == nil:
+			jobModifyIndex = current.JobModifyIndex
+		case isNotFound(err):
+			jobModifyIndex = 0
+		default:
+			return types.JobRegisterResponse{}, err
+		}
+	}
+
+	// Submit the already parsed job so HCL is not sent to the parse endpoint twice.
+	parsedSpec, err := json.Marshal(registerRequestFor(jobSpec, jobData))
+	if err != nil {
+		return types.JobRegisterResponse{}, fmt.Errorf("error marshaling job: %v", err)
+	}
+	result, err := client.EnforceRunJob(ctx, string(parsedSpec), namespace, detach, jobModifyIndex)
+	if isJobModifyIndexConflict(err) {
+		return types.JobRegisterResponse{}, fmt.Errorf("job %s was modified concurrently (expected JobModifyIndex %d); re-read the job and resubmit: %w", jobID, jobModifyIndex, err)
+	}
+	return result, err
+}
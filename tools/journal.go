@@ -0,0 +1,520 @@
+// File: tools/journal.go
+package tools
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	defaultRecentOperations = 50
+	maxRecentOperations     = 500
+	// maxJournaledStringBytes bounds string arguments copied into the journal; longer values
+	// such as job specs or licenses are only recorded by size.
+	maxJournaledStringBytes = 128
+	// maxJournalLineBytes bounds one journal entry when reading the file back.
+	maxJournalLineBytes = 1 << 20
+)
+
+// journaledSizeOnlyArguments carry data that may be secret, such as variable values, exec
+// input, dispatch payloads and licenses; the journal only records their size.
+var journaledSizeOnlyArguments = map[string]bool{"value": true, "stdin": true, "payload": true, "meta": true, "license": true}
+
+// journaledVariableTools take the path of a variable, recorded as their target.
+var journaledVariableTools = map[string]bool{"create_variable": true, "delete_variable": true}
+
+// hclJobID matches the job block of an HCL job spec.
+var hclJobID = regexp.MustCompile(`(?m)^\s*job\s+"([^"]+)"`)
+
+// OperationTarget is the Nomad object a journaled operation acted on
+type OperationTarget struct {
+	Kind      string `json:"Kind"`
+	ID        string `json:"ID"`
+	Namespace string `json:"Namespace,omitempty"`
+}
+
+// OperationReference is the state of an operation's target before or after it ran
+type OperationReference struct {
+	Found          bool   `json:"Found"`
+	Status         string `json:"Status,omitempty"`
+	Version        *int   `json:"Version,omitempty"`
+	JobModifyIndex int    `json:"JobModifyIndex,omitempty"`
+	Eligibility    string `json:"Eligibility,omitempty"`
+	Drain          *bool  `json:"Drain,omitempty"`
+	ModifyIndex    uint64 `json:"ModifyIndex,omitempty"`
+	// Items is the content of a variable about to be deleted, kept so undo_operation can
+	// restore it. It is only written to the file encrypted, as SealedItems, and never
+	// returned by list_recent_operations.
+	Items map[string]string `json:"-"`
+	// SealedItems is Items encrypted with AES-256-GCM under the journal key.
+	SealedItems string `json:"SealedItems,omitempty"`
+}
+
+// Operation is one mutating tool call recorded in the operation journal
+type Operation struct {
+	ID      string    `json:"ID"`
+	Time    time.Time `json:"Time"`
+	Tool    string    `json:"Tool"`
+	Session string    `json:"Session,omitempty"`
+	// Caller is the SHA-256 of the Nomad token the call ran with; list_recent_operations only
+	// shows a caller its own operations.
+	Caller    string                 `json:"Caller,omitempty"`
+	RequestID string                 `json:"RequestID,omitempty"`
+	Arguments map[string]interface{} `json:"Arguments,omitempty"`
+	Target    *OperationTarget       `json:"Target,omitempty"`
+	Before    *OperationReference    `json:"Before,omitempty"`
+	After     *OperationReference    `json:"After,omitempty"`
+	Failed    bool                   `json:"Failed,omitempty"`
+	Error     string                 `json:"Error,omitempty"`
+}
+
+// OperationFilter selects operations returned by OperationJournal.Recent
+type OperationFilter struct {
+	Since  time.Time
+	Tool   string
+	Target string
+	// Caller, when set, only selects operations made with this token hash
+	Caller string
+	Limit  int
+}
+
+// OperationJournal appends mutating operations to a JSON-lines file, so they survive server
+// restarts and can be listed later. A plain append-only file needs no embedded database, and
+// a line cut short by a crash only loses that entry. The file is never rotated or truncated;
+// entries are kept until the operator removes them.
+type OperationJournal struct {
+	mu   sync.Mutex
+	file *os.File
+	// aead encrypts the copies of deleted variables; without it no copy is kept
+	aead cipher.AEAD
+}
+
+// OpenOperationJournal opens or creates the journal file at path, readable by its owner only.
+func OpenOperationJournal(path string) (*OperationJournal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &OperationJournal{file: file}, nil
+}
+
+// SetItemsKey makes the journal keep an encrypted copy of every deleted variable, so
+// undo_operation can restore it. key is the base64 encoding of a 32-byte AES-256 key; an empty
+// key keeps no copies.
+func (j *OperationJournal) SetItemsKey(key string) error {
+	if key == "" {
+		j.aead = nil
+		return nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return fmt.Errorf("key is not base64: %w", err)
+	}
+	if len(raw) != 32 {
+		return fmt.Errorf("key must be 32 bytes, got %d", len(raw))
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return err
+	}
+	j.aead, err = cipher.NewGCM(block)
+	return err
+}
+
+// keepsItems reports whether deleted variables are copied into the journal.
+func (j *OperationJournal) keepsItems() bool {
+	return j.aead != nil
+}
+
+// sealItems encrypts items, bound to the operation ID.
+func (j *OperationJournal) sealItems(id string, items map[string]string) (string, error) {
+	plain, err := json.Marshal(items)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, j.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(j.aead.Seal(nonce, nonce, plain, []byte(id))), nil
+}
+
+// openItems decrypts the SealedItems of the operation with the given ID.
+func (j *OperationJournal) openItems(id, sealed string) (map[string]string, error) {
+	if j.aead == nil {
+		return nil, errJournalItemsLocked
+	}
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < j.aead.NonceSize() {
+		return nil, errJournalItemsLocked
+	}
+	plain, err := j.aead.Open(nil, raw[:j.aead.NonceSize()], raw[j.aead.NonceSize():], []byte(id))
+	if err != nil {
+		return nil, errJournalItemsLocked
+	}
+	var items map[string]string
+	if err := json.Unmarshal(plain, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// errJournalItemsLocked reports a journaled variable copy that the current key cannot decrypt.
+var errJournalItemsLocked = errors.New("the journaled copy cannot be decrypted with MCP_NOMAD_JOURNAL_KEY")
+
+// Close closes the journal file.
+func (j *OperationJournal) Close() error {
+	return j.file.Close()
+}
+
+// Record appends op to the journal. The variable items of op.Before are written encrypted,
+// or dropped when the journal has no key.
+func (j *OperationJournal) Record(op Operation) error {
+	if op.Before != nil && op.Before.Items != nil {
+		before := *op.Before
+		before.Items = nil
+		if j.keepsItems() {
+			sealed, err := j.sealItems(op.ID, op.Before.Items)
+			if err != nil {
+				return err
+			}
+			before.SealedItems = sealed
+		}
+		op.Before = &before
+	}
+	line, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err = j.file.Write(append(line, '\n'))
+	return err
+}
+
+// scan calls fn with every journaled operation, oldest first. Lines that cannot be decoded,
+// e.g. a write cut short by a crash, are skipped.
+func (j *OperationJournal) scan(fn func(op Operation)) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.file.Seek(0, 0); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(j.file)
+	scanner.Buffer(make([]byte, 64<<10), maxJournalLineBytes)
+	for scanner.Scan() {
+		var op Operation
+		if err := json.Unmarshal(scanner.Bytes(), &op); err != nil {
+			continue
+		}
+		fn(op)
+	}
+	return scanner.Err()
+}
+
+// Recent returns the journaled operations matching filter, newest first.
+func (j *OperationJournal) Recent(filter OperationFilter) ([]Operation, error) {
+	var matched []Operation
+	err := j.scan(func(op Operation) {
+		if op.Time.Before(filter.Since) || (filter.Tool != "" && op.Tool != filter.Tool) {
+			return
+		}
+		if filter.Target != "" && (op.Target == nil || op.Target.ID != filter.Target) {
+			return
+		}
+		if filter.Caller != "" && op.Caller != filter.Caller {
+			return
+		}
+		matched = append(matched, op)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	operations := make([]Operation, 0, min(len(matched), filter.Limit))
+	for i := len(matched) - 1; i >= 0 && len(operations) < filter.Limit; i-- {
+		operations = append(operations, matched[i])
+	}
+	return operations, nil
+}
+
+// Find returns the operation with the given ID and the ID of the successful undo_operation
+// call that reverted it, if any. A journaled variable copy is decrypted into Before.Items;
+// when that fails Items stays nil and SealedItems set.
+func (j *OperationJournal) Find(id string) (Operation, string, bool, error) {
+	var found Operation
+	var ok bool
+	var undoneBy string
+	err := j.scan(func(op Operation) {
+		switch {
+		case op.ID == id:
+			found, ok = op, true
+		case op.Tool == "undo_operation" && !op.Failed && op.Arguments["operation_id"] == id:
+			undoneBy = op.ID
+		}
+	})
+	if ok && found.Before != nil && found.Before.SealedItems != "" {
+		if items, err := j.openItems(found.ID, found.Before.SealedItems); err == nil {
+			found.Before.Items = items
+		}
+	}
+	return found, undoneBy, ok, err
+}
+
+// journaledArguments copies the arguments of a call for the journal. Short strings, numbers
+// and booleans are kept; long strings and structured values, which may hold job specs or
+// variable items, are replaced by a size note, as are arguments that may carry secrets, and
+// *_token arguments are never written.
+func journaledArguments(arguments map[string]interface{}) map[string]interface{} {
+	if len(arguments) == 0 {
+		return nil
+	}
+	journaled := make(map[string]interface{}, len(arguments))
+	for name, value := range arguments {
+		switch v := value.(type) {
+		case string:
+			if strings.HasSuffix(name, "_token") && v != "" {
+				journaled[name] = "<redacted>"
+			} else if len(v) > maxJournaledStringBytes || journaledSizeOnlyArguments[name] {
+				journaled[name] = fmt.Sprintf("<%d bytes>", len(v))
+			} else {
+				journaled[name] = v
+			}
+		case bool, float64, nil:
+			journaled[name] = v
+		default:
+			journaled[name] = "<omitted>"
+		}
+	}
+	return journaled
+}
+
+// operationCaller identifies the token a call ran with without keeping the token itself.
+func operationCaller(ctx context.Context) string {
+	token := sha256.Sum256([]byte(utils.TokenFromContext(ctx)))
+	return hex.EncodeToString(token[:])
+}
+
+// operationTarget works out the job, node or variable a call acts on from its arguments:
+// job_id, node_id, the ID of a submitted job_spec, or the path of a variable tool.
+func operationTarget(tool string, arguments map[string]interface{}) *OperationTarget {
+	if path, _ := arguments["path"].(string); path != "" && journaledVariableTools[tool] {
+		return &OperationTarget{Kind: "variable", ID: path, Namespace: operationNamespace(arguments, "")}
+	}
+	if jobID, _ := arguments["job_id"].(string); jobID != "" {
+		return &OperationTarget{Kind: "job", ID: jobID, Namespace: operationNamespace(arguments, "")}
+	}
+	if nodeID, _ := arguments["node_id"].(string); nodeID != "" {
+		return &OperationTarget{Kind: "node", ID: nodeID}
+	}
+	jobSpec, _ := arguments["job_spec"].(string)
+	if jobSpec == "" {
+		return nil
+	}
+	var job struct {
+		ID        string
+		Namespace string
+		Job       *struct {
+			ID        string
+			Namespace string
+		}
+	}
+	if err := json.Unmarshal([]byte(jobSpec), &job); err == nil {
+		if job.Job != nil {
+			job.ID, job.Namespace = job.Job.ID, job.Job.Namespace
+		}
+		if job.ID == "" {
+			return nil
+		}
+		return &OperationTarget{Kind: "job", ID: job.ID, Namespace: operationNamespace(arguments, job.Namespace)}
+	}
+	if m := hclJobID.FindStringSubmatch(jobSpec); m != nil {
+		return &OperationTarget{Kind: "job", ID: m[1], Namespace: operationNamespace(arguments, "")}
+	}
+	return nil
+}
+
+// operationNamespace is the namespace argument of a call, else the namespace declared by the
+// job, else the default namespace.
+func operationNamespace(arguments map[string]interface{}, declared string) string {
+	if ns, _ := arguments["namespace"].(string); ns != "" {
+		return ns
+	}
+	if declared != "" {
+		return declared
+	}
+	return utils.NomadDefaultNamespace
+}
+
+// lookupOperationReference reads the current state of target. It returns nil when the state
+// cannot be read, and a reference with Found=false when the object does not exist. A
+// variable's items are only kept with keepItems.
+func lookupOperationReference(ctx context.Context, client utils.JournalAPI, target *OperationTarget, keepItems bool) *OperationReference {
+	switch target.Kind {
+	case "job":
+		job, err := client.GetJob(ctx, target.ID, target.Namespace)
+		if isNotFound(err) {
+			return &OperationReference{}
+		}
+		if err != nil {
+			return nil
+		}
+		return &OperationReference{Found: true, Status: job.Status, Version: &job.Version, JobModifyIndex: job.JobModifyIndex}
+	case "node":
+		node, err := client.GetNodeDetail(ctx, target.ID)
+		if isNotFound(err) {
+			return &OperationReference{}
+		}
+		if err != nil {
+			return nil
+		}
+		return &OperationReference{Found: true, Status: node.Status, Eligibility: node.SchedulingEligibility, Drain: &node.Drain}
+	case "variable":
+		variable, err := client.GetVariable(ctx, target.ID, target.Namespace)
+		if isNotFound(err) {
+			return &OperationReference{}
+		}
+		if err != nil {
+			return nil
+		}
+		reference := &OperationReference{Found: true, ModifyIndex: variable.ModifyIndex}
+		if keepItems {
+			reference.Items = variable.Items
+		}
+		return reference
+	}
+	return nil
+}
+
+// OperationJournalMiddleware returns a tool middleware that records every mutating tool call
+// in journal, together with the state of the job, node or variable it acted on before and
+// after the call. Read-only tools are not recorded. A failure to write the journal is logged and does
+// not fail the call.
+func OperationJournalMiddleware(journal *OperationJournal, client utils.JournalAPI, logger *log.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			srv := server.ServerFromContext(ctx)
+			if srv == nil {
+				return next(ctx, request)
+			}
+			if tool := srv.GetTool(request.Params.Name); tool == nil || isReadOnlyTool(tool.Tool) {
+				return next(ctx, request)
+			}
+
+			arguments := request.GetArguments()
+			op := Operation{
+				ID:        utils.NewRequestID(),
+				Time:      time.Now().UTC(),
+				Tool:      request.Params.Name,
+				Session:   sessionID(ctx),
+				Caller:    operationCaller(ctx),
+				RequestID: utils.RequestIDFromContext(ctx),
+				Arguments: journaledArguments(arguments),
+				Target:    operationTarget(request.Params.Name, arguments),
+			}
+			if op.Target != nil {
+				// Keep a deleted variable's content, encrypted, so the deletion can be undone.
+				op.Before = lookupOperationReference(ctx, client, op.Target, op.Tool == "delete_variable" && journal.keepsItems())
+			}
+
+			result, err := next(ctx, request)
+
+			switch {
+			case err != nil:
+				op.Failed, op.Error = true, err.Error()
+			case result != nil && result.IsError:
+				op.Failed, op.Error = true, errorResultText(result)
+			}
+			if op.Target != nil && !op.Failed {
+				op.After = lookupOperationReference(ctx, client, op.Target, false)
+			}
+			if recordErr := journal.Record(op); recordErr != nil {
+				requestLogf(ctx, logger, "Error recording operation %s: %v", op.Tool, recordErr)
+			}
+			return result, err
+		}
+	}
+}
+
+// RegisterJournalTools registers the tools listing and undoing journaled operations
+func RegisterJournalTools(s *server.MCPServer, journal *OperationJournal, nomadClient utils.UndoAPI, logger *log.Logger) {
+	listRecentOperationsTool := mcp.NewTool("list_recent_operations",
+		mcp.WithDescription("List the mutating operations this server performed with the caller's Nomad token (job submissions, stops, scaling, node drains, ...), newest first, with the state of the affected job or node before and after each one. The journal is kept across server restarts and sessions"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		sinceOption("operations"),
+		mcp.WithString("tool",
+			mcp.Description("Only return operations of this tool, e.g. run_job"),
+		),
+		mcp.WithString("target",
+			mcp.Description("Only return operations on this job or node ID"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of operations to return (default: 50, max: 500)"),
+		),
+	)
+	s.AddTool(listRecentOperationsTool, ListRecentOperationsHandler(journal, logger))
+
+	s.AddTool(undoOperationTool(), UndoOperationHandler(journal, nomadClient, logger))
+}
+
+// ListRecentOperationsHandler returns a handler for listing journaled operations
+func ListRecentOperationsHandler(journal *OperationJournal, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		cutoff, err := sinceArgument(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		filter := OperationFilter{Since: cutoff, Caller: operationCaller(ctx), Limit: defaultRecentOperations}
+		filter.Tool, _ = arguments["tool"].(string)
+		filter.Target, _ = arguments["target"].(string)
+		if l, ok := arguments["limit"].(float64); ok {
+			if l < 1 {
+				return mcp.NewToolResultError("limit must be at least 1"), nil
+			}
+			filter.Limit = min(int(l), maxRecentOperations)
+		}
+
+		operations, err := journal.Recent(filter)
+		if err != nil {
+			requestLogf(ctx, logger, "Error reading operation journal: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to read operation journal", err), nil
+		}
+		for _, op := range operations {
+			if op.Before != nil {
+				op.Before.Items, op.Before.SealedItems = nil, ""
+			}
+		}
+
+		operationsJSON, err := json.MarshalIndent(operations, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format operations", err), nil
+		}
+
+		return mcp.NewToolResultText(string(operationsJSON)), nil
+	}
+}
@@ -0,0 +1,122 @@
+// File: tools/license.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// enterpriseOnlyLicenseMessage explains a license call refused by a community edition cluster.
+const enterpriseOnlyLicenseMessage = "This cluster runs the Nomad community edition; licenses only exist on Nomad Enterprise"
+
+// LicenseStatus is the result of get_license and put_license
+type LicenseStatus struct {
+	types.License
+	// Expired is set once ExpirationTime has passed; the cluster keeps running until TerminationTime
+	Expired bool `json:"Expired"`
+	// ExpiresIn is the time left until ExpirationTime, rounded to the hour
+	ExpiresIn string   `json:"ExpiresIn,omitempty"`
+	Warnings  []string `json:"Warnings,omitempty"`
+}
+
+// RegisterLicenseTools registers the Nomad Enterprise license tools
+func RegisterLicenseTools(s *server.MCPServer, nomadClient utils.LicenseAPI, logger *log.Logger) {
+	getLicenseTool := mcp.NewTool("get_license",
+		mcp.WithDescription("Get the Nomad Enterprise license: customer, product, issue, expiration and termination times, time left and licensed features (Nomad Enterprise)"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+	s.AddTool(getLicenseTool, GetLicenseHandler(nomadClient, logger))
+
+	putLicenseTool := mcp.NewTool("put_license",
+		mcp.WithDescription("Install a Nomad Enterprise license and return the license now in effect (Nomad Enterprise)"),
+		mcp.WithString("license",
+			mcp.Required(),
+			mcp.Description("The license blob"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Install the license even when it was issued before the current one (default: false)"),
+		),
+	)
+	s.AddTool(putLicenseTool, PutLicenseHandler(nomadClient, logger))
+}
+
+// licenseStatus summarizes a license reply at now.
+func licenseStatus(reply types.LicenseReply, now time.Time) LicenseStatus {
+	status := LicenseStatus{Warnings: reply.Warnings}
+	if reply.License == nil {
+		return status
+	}
+	status.License = *reply.License
+	if !status.ExpirationTime.IsZero() {
+		left := status.ExpirationTime.Sub(now)
+		status.Expired = left <= 0
+		if !status.Expired {
+			status.ExpiresIn = left.Round(time.Hour).String()
+		}
+	}
+	return status
+}
+
+// licenseResult formats the license in effect.
+func licenseResult(reply types.LicenseReply) (*mcp.CallToolResult, error) {
+	statusJSON, err := json.MarshalIndent(licenseStatus(reply, time.Now()), "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format license", err), nil
+	}
+	return mcp.NewToolResultText(string(statusJSON)), nil
+}
+
+// GetLicenseHandler returns a handler for reading the Enterprise license
+func GetLicenseHandler(client utils.LicenseAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		reply, err := client.GetLicense(ctx)
+		if err != nil {
+			if utils.IsEnterpriseOnly(err) {
+				return mcp.NewToolResultError(enterpriseOnlyLicenseMessage), nil
+			}
+			requestLogf(ctx, logger, "Error getting license: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get license", err), nil
+		}
+
+		return licenseResult(reply)
+	}
+}
+
+// PutLicenseHandler returns a handler for installing an Enterprise license
+func PutLicenseHandler(client utils.LicenseAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		license, _ := arguments["license"].(string)
+		if license == "" {
+			return mcp.NewToolResultError("license is required"), nil
+		}
+		force, _ := arguments["force"].(bool)
+
+		if err := client.PutLicense(ctx, license, force); err != nil {
+			if utils.IsEnterpriseOnly(err) {
+				return mcp.NewToolResultError(enterpriseOnlyLicenseMessage), nil
+			}
+			requestLogf(ctx, logger, "Error putting license: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to put license", err), nil
+		}
+
+		reply, err := client.GetLicense(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting license: %v", err)
+			return mcp.NewToolResultText("License installed successfully"), nil
+		}
+
+		return licenseResult(reply)
+	}
+}
@@ -3,8 +3,11 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"time"
 
+	"github.com/kocierik/mcp-nomad/types"
 	"github.com/kocierik/mcp-nomad/utils"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -15,6 +18,7 @@ func RegisterLogTools(s *server.MCPServer, nomadClient utils.LogAPI, logger *log
 	// Get allocation logs tool
 	getAllocationLogsTool := mcp.NewTool("get_allocation_logs",
 		mcp.WithDescription("Get logs from a specific task in an allocation"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("allocation_id",
 			mcp.Required(),
 			mcp.Description("The ID of the allocation"),
@@ -28,13 +32,29 @@ func RegisterLogTools(s *server.MCPServer, nomadClient utils.LogAPI, logger *log
 			mcp.Enum("stdout", "stderr"),
 		),
 		mcp.WithBoolean("follow",
-			mcp.Description("Whether to follow/tail the logs (default: false)"),
+			mcp.Description("Stream new log output for duration seconds instead of reading once; chunks are also sent as progress notifications (or log messages) as they arrive (default: false)"),
 		),
 		mcp.WithNumber("tail",
-			mcp.Description("Number of lines to show from the end (default: 100, 0 means use default)"),
+			mcp.Description(fmt.Sprintf("Number of lines to show from the end (default: %d, 0 means use default)", nomadClient.GetDefaultTailLines())),
+		),
+		mcp.WithNumber("tail_bytes",
+			mcp.Description(fmt.Sprintf("Show exactly this many bytes from the end instead of tail lines, for logs without meaningful line breaks (max: %d). The result carries offset, next_offset and size like limit", nomadClient.GetMaxLogBytes())),
 		),
 		mcp.WithNumber("offset",
-			mcp.Description("The offset to start reading from (ignored if tail is specified)"),
+			mcp.Description("The offset to start reading from (ignored if tail is specified); with follow or limit, pass the returned next_offset to continue"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Read one page of at most this many bytes at an exact byte offset instead of tail lines (max: %d). The result carries offset, next_offset, previous_offset and size to page forward or backward", nomadClient.GetMaxLogBytes())),
+		),
+		mcp.WithString("origin",
+			mcp.Description("With limit, whether offset counts from the start or the end of the log (default: start)"),
+			mcp.Enum("start", "end"),
+		),
+		mcp.WithNumber("duration",
+			mcp.Description("With follow, seconds to stream for (default: 10, max: 300; cut short by the server's read timeout)"),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description(fmt.Sprintf("With follow, stop after this many bytes of output (default: %d, max: %d)", min(defaultLogFollowBytes, nomadClient.GetMaxLogBytes()), nomadClient.GetMaxLogBytes())),
 		),
 	)
 	s.AddTool(getAllocationLogsTool, GetAllocationLogsHandler(nomadClient, logger))
@@ -78,9 +98,46 @@ func GetAllocationLogsHandler(client utils.LogAPI, logger *log.Logger) func(cont
 			offset = int64(o)
 		}
 
+		tailBytes := int64(0)
+		if tb, ok := arguments["tail_bytes"].(float64); ok {
+			if tb < 1 {
+				return mcp.NewToolResultError("tail_bytes must be at least 1"), nil
+			}
+			if tail > 0 {
+				return mcp.NewToolResultError("tail and tail_bytes cannot be combined"), nil
+			}
+			tailBytes = min(int64(tb), client.GetMaxLogBytes())
+		}
+
+		if follow {
+			stream := types.LogStreamRequest{AllocID: allocID, Task: task, Type: logType}
+			if tailBytes > 0 {
+				stream.Origin, stream.Offset = "end", tailBytes
+			}
+			return followAllocationLogs(ctx, client, request, stream, tail, offset, logger)
+		}
+		if tailBytes > 0 {
+			stream := types.LogStreamRequest{AllocID: allocID, Task: task, Type: logType, Origin: "end", Offset: tailBytes}
+			return pageAllocationLogs(ctx, client, stream, tailBytes, logger)
+		}
+		if l, ok := arguments["limit"].(float64); ok {
+			if l < 1 {
+				return mcp.NewToolResultError("limit must be at least 1"), nil
+			}
+			if offset < 0 {
+				return mcp.NewToolResultError("offset must not be negative"), nil
+			}
+			origin, _ := arguments["origin"].(string)
+			if origin == "" {
+				origin = "start"
+			}
+			stream := types.LogStreamRequest{AllocID: allocID, Task: task, Type: logType, Origin: origin, Offset: offset}
+			return pageAllocationLogs(ctx, client, stream, min(int64(l), client.GetMaxLogBytes()), logger)
+		}
+
 		logs, err := client.GetAllocationLogs(ctx, allocID, task, logType, follow, tail, offset)
 		if err != nil {
-			logger.Printf("Error getting allocation logs: %v", err)
+			requestLogf(ctx, logger, "Error getting allocation logs: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get allocation logs", err), nil
 		}
 
@@ -96,3 +153,116 @@ func GetAllocationLogsHandler(client utils.LogAPI, logger *log.Logger) func(cont
 		return mcp.NewToolResultText(string(resultJSON)), nil
 	}
 }
+
+// The byte caps of reading and following logs come from the client's GetMaxLogBytes.
+const (
+	defaultLogFollowWindow = 10 * time.Second
+	maxLogFollowWindow     = 5 * time.Minute
+	defaultLogFollowBytes  = 64 << 10
+)
+
+// pageAllocationLogs reads one page of a task log at exact byte offsets. previous_offset
+// is where the page before this one starts and is left out on the first page.
+func pageAllocationLogs(ctx context.Context, client utils.LogAPI, stream types.LogStreamRequest, limit int64, logger *log.Logger) (*mcp.CallToolResult, error) {
+	page, err := client.ReadAllocationLogs(ctx, stream, limit)
+	if err != nil {
+		requestLogf(ctx, logger, "Error reading allocation logs: %v", err)
+		return mcp.NewToolResultErrorFromErr("Failed to get allocation logs", err), nil
+	}
+
+	result := map[string]interface{}{
+		"logs":        string(page.Data),
+		"offset":      page.Offset,
+		"next_offset": page.NextOffset,
+		"size":        page.Size,
+	}
+	if page.Offset > 0 {
+		result["previous_offset"] = max(page.Offset-limit, 0)
+	}
+
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format logs", err), nil
+	}
+
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// followAllocationLogs streams a task log for the call's duration. Without an offset it
+// starts at the end of the log (tail lines back, estimated like GetAllocationLogs, or the
+// stream's tail_bytes offset), so only recent and new output is returned.
+func followAllocationLogs(ctx context.Context, client utils.LogAPI, request mcp.CallToolRequest, stream types.LogStreamRequest, tail, offset int64, logger *log.Logger) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	window, err := streamWindow(ctx, arguments, defaultLogFollowWindow, maxLogFollowWindow)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	maxLogBytes := int(client.GetMaxLogBytes())
+	maxBytes := min(defaultLogFollowBytes, maxLogBytes)
+	if m, ok := arguments["max_bytes"].(float64); ok {
+		if m < 1 {
+			return mcp.NewToolResultError("max_bytes must be at least 1"), nil
+		}
+		maxBytes = min(int(m), maxLogBytes)
+	}
+
+	switch {
+	case stream.Origin != "":
+		// Already positioned, by tail_bytes
+	case tail > 0:
+		stream.Origin, stream.Offset = "end", tail*200
+	case offset > 0:
+		stream.Origin, stream.Offset = "start", offset
+	default:
+		stream.Origin = "end"
+	}
+
+	var logs []byte
+	nextOffset := offset
+	truncated := false
+	fileEvents := []string{}
+
+	listenCtx, cancel := context.WithTimeout(ctx, window)
+	defer cancel()
+
+	err = client.StreamAllocationLogs(listenCtx, stream, func(frame types.LogFrame) error {
+		if frame.FileEvent != "" {
+			fileEvents = append(fileEvents, fmt.Sprintf("%s: %s", frame.File, frame.FileEvent))
+			return nil
+		}
+		data := frame.Data
+		nextOffset = frame.Offset
+		if room := maxBytes - len(logs); len(data) > room {
+			nextOffset -= int64(len(data) - room)
+			data = data[:room]
+			truncated = true
+		}
+		logs = append(logs, data...)
+		notifyToolProgress(ctx, request, len(logs), maxBytes, string(data), "nomad-logs",
+			map[string]any{"allocation_id": stream.AllocID, "task": stream.Task, "type": stream.Type, "data": string(data)})
+		if truncated {
+			return utils.ErrStopStream
+		}
+		return nil
+	})
+	if err != nil {
+		requestLogf(ctx, logger, "Error following allocation logs: %v", err)
+		return mcp.NewToolResultErrorFromErr("Failed to follow allocation logs", err), nil
+	}
+
+	result := map[string]interface{}{
+		"logs":        string(logs),
+		"next_offset": nextOffset,
+		"truncated":   truncated,
+	}
+	if len(fileEvents) > 0 {
+		result["file_events"] = fileEvents
+	}
+
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format logs", err), nil
+	}
+
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
@@ -0,0 +1,203 @@
+// File: tools/maintenance.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maintenancePollInterval is how often enter_node_maintenance checks migration progress.
+const maintenancePollInterval = 5 * time.Second
+
+// MaintenanceStep is one item of the node maintenance checklist
+type MaintenanceStep struct {
+	Step   string `json:"Step"`
+	Status string `json:"Status"` // done, skipped, pending or failed
+	Detail string `json:"Detail,omitempty"`
+}
+
+// MaintenanceReport is the checklist returned by the node maintenance tools
+type MaintenanceReport struct {
+	NodeID               string            `json:"NodeID"`
+	Complete             bool              `json:"Complete"`
+	Steps                []MaintenanceStep `json:"Steps"`
+	Warnings             []DrainRisk       `json:"Warnings,omitempty"`
+	RemainingAllocations []string          `json:"RemainingAllocations,omitempty"`
+}
+
+func (r *MaintenanceReport) add(step, status, detail string) {
+	r.Steps = append(r.Steps, MaintenanceStep{Step: step, Status: status, Detail: detail})
+}
+
+// EnterNodeMaintenanceHandler returns a handler that marks a node ineligible, drains it and
+// waits for its allocations to migrate
+func EnterNodeMaintenanceHandler(client utils.NodeToolsDeps, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		nodeID, ok := arguments["node_id"].(string)
+		if !ok || nodeID == "" {
+			return mcp.NewToolResultError("node_id is required"), nil
+		}
+
+		deadline := int64(3600)
+		if d, ok := arguments["deadline"].(float64); ok {
+			deadline = int64(d)
+		}
+		force, _ := arguments["force"].(bool)
+
+		waitTimeout := 90 * time.Second
+		if w, ok := arguments["wait_timeout"].(string); ok && w != "" {
+			d, err := parseRelativeDuration(w)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("wait_timeout: %v", err)), nil
+			}
+			waitTimeout = d
+		}
+
+		report := MaintenanceReport{NodeID: nodeID}
+
+		risks, err := drainPreflight(ctx, client, nodeID)
+		if err != nil {
+			requestLogf(ctx, logger, "Error running drain preflight: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to run drain preflight", err), nil
+		}
+		report.Warnings = risks
+		if len(risks) > 0 && !force {
+			report.add("preflight", "failed", fmt.Sprintf("%d task group(s) would lose all running allocations; retry with force=true to proceed", len(risks)))
+			return maintenanceResult(report, true)
+		}
+		report.add("preflight", "done", fmt.Sprintf("%d at-risk task group(s)", len(risks)))
+
+		if _, err := client.EligibilityNode(ctx, nodeID, false); err != nil {
+			requestLogf(ctx, logger, "Error marking node ineligible: %v", err)
+			report.add("mark ineligible", "failed", err.Error())
+			return maintenanceResult(report, true)
+		}
+		report.add("mark ineligible", "done", "no new allocations will be placed on the node")
+
+		message, err := client.DrainNode(ctx, nodeID, true, deadline)
+		if err != nil {
+			requestLogf(ctx, logger, "Error draining node: %v", err)
+			report.add("drain", "failed", err.Error())
+			return maintenanceResult(report, true)
+		}
+		report.add("drain", "done", message)
+
+		remaining, err := waitForNodeMigrations(ctx, client, nodeID, waitTimeout)
+		switch {
+		case err != nil:
+			report.add("wait for migrations", "failed", err.Error())
+		case len(remaining) > 0:
+			report.add("wait for migrations", "pending", fmt.Sprintf("%d allocation(s) still running after %s; the drain continues in the background", len(remaining), waitTimeout))
+		default:
+			report.add("wait for migrations", "done", "no service or batch allocations remain on the node")
+			report.Complete = true
+		}
+		report.RemainingAllocations = remaining
+
+		return maintenanceResult(report, false)
+	}
+}
+
+// ExitNodeMaintenanceHandler returns a handler that stops any drain and makes a node
+// eligible for scheduling again
+func ExitNodeMaintenanceHandler(client utils.NodeToolsDeps, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		nodeID, ok := arguments["node_id"].(string)
+		if !ok || nodeID == "" {
+			return mcp.NewToolResultError("node_id is required"), nil
+		}
+
+		report := MaintenanceReport{NodeID: nodeID}
+
+		node, err := client.GetNode(ctx, nodeID)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting node: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get node", err), nil
+		}
+
+		if node.Drain {
+			message, err := client.DrainNode(ctx, nodeID, false, 0)
+			if err != nil {
+				requestLogf(ctx, logger, "Error disabling drain: %v", err)
+				report.add("stop drain", "failed", err.Error())
+				return maintenanceResult(report, true)
+			}
+			report.add("stop drain", "done", message)
+		} else {
+			report.add("stop drain", "skipped", "node is not draining")
+		}
+
+		update, err := client.EligibilityNode(ctx, nodeID, true)
+		if err != nil {
+			requestLogf(ctx, logger, "Error marking node eligible: %v", err)
+			report.add("mark eligible", "failed", err.Error())
+			return maintenanceResult(report, true)
+		}
+		report.add("mark eligible", "done", fmt.Sprintf("node status %s, scheduling eligibility %s", update.Status, update.SchedulingEligibility))
+		report.Complete = true
+
+		return maintenanceResult(report, false)
+	}
+}
+
+// waitForNodeMigrations polls the node until no live allocations other than system jobs
+// remain, or timeout elapses. It returns the IDs of the allocations still running.
+func waitForNodeMigrations(ctx context.Context, client utils.NodeAPI, nodeID string, timeout time.Duration) ([]string, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		allocs, err := client.ListNodeAllocations(ctx, nodeID)
+		if err != nil {
+			return nil, err
+		}
+		remaining := remainingDrainAllocations(allocs)
+		if len(remaining) == 0 || !time.Now().Before(deadline) {
+			return remaining, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return remaining, nil
+		case <-time.After(maintenancePollInterval):
+		}
+	}
+}
+
+// remainingDrainAllocations lists live allocations a drain still has to migrate. System
+// job allocations stay until the end of the drain and are not waited for.
+func remainingDrainAllocations(allocs []types.Allocation) []string {
+	var remaining []string
+	for _, alloc := range allocs {
+		if isLiveAllocation(alloc) && alloc.JobType != "system" && alloc.JobType != "sysbatch" {
+			remaining = append(remaining, alloc.ID)
+		}
+	}
+	return remaining
+}
+
+func maintenanceResult(report MaintenanceReport, isError bool) (*mcp.CallToolResult, error) {
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format maintenance report", err), nil
+	}
+	if isError {
+		return mcp.NewToolResultError(string(reportJSON)), nil
+	}
+	return mcp.NewToolResultText(string(reportJSON)), nil
+}
@@ -0,0 +1,291 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RequestIDMiddleware returns a tool middleware that assigns every tool call a request ID.
+// The ID travels in the context to Nomad (see utils.RequestIDHeader), prefixes the call's
+// start/finish log lines and the handler's own (see requestLogf), is attached to the result
+// _meta, and is appended to error results.
+func RequestIDMiddleware(logger *log.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			requestID := utils.NewRequestID()
+			ctx = utils.WithRequestID(ctx, requestID)
+
+			start := time.Now()
+			logger.Printf("[request_id=%s] tool %s started", requestID, request.Params.Name)
+
+			result, err := next(ctx, request)
+
+			elapsed := time.Since(start).Round(time.Millisecond)
+			switch {
+			case err != nil:
+				logger.Printf("[request_id=%s] tool %s failed after %s: %v", requestID, request.Params.Name, elapsed, err)
+			case result != nil && result.IsError:
+				logger.Printf("[request_id=%s] tool %s returned an error after %s", requestID, request.Params.Name, elapsed)
+			default:
+				logger.Printf("[request_id=%s] tool %s completed in %s", requestID, request.Params.Name, elapsed)
+			}
+
+			if result != nil {
+				if result.Meta == nil {
+					result.Meta = &mcp.Meta{}
+				}
+				if result.Meta.AdditionalFields == nil {
+					result.Meta.AdditionalFields = map[string]any{}
+				}
+				result.Meta.AdditionalFields["request_id"] = requestID
+
+				if result.IsError {
+					appendRequestIDToError(result, requestID)
+				}
+			}
+
+			return result, err
+		}
+	}
+}
+
+// requestLogf logs like logger.Printf, prefixed with the request ID of ctx when it belongs
+// to a tool call, so a handler's log lines can be matched with the call's start and finish.
+func requestLogf(ctx context.Context, logger *log.Logger, format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	if requestID := utils.RequestIDFromContext(ctx); requestID != "" {
+		message = fmt.Sprintf("[request_id=%s] %s", requestID, message)
+	}
+	_ = logger.Output(2, message)
+}
+
+// appendRequestIDToError tags the first text block of an error result with the request ID,
+// unless a Nomad error already carried it.
+func appendRequestIDToError(result *mcp.CallToolResult, requestID string) {
+	for i, c := range result.Content {
+		text, ok := c.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		if !strings.Contains(text.Text, requestID) {
+			text.Text = fmt.Sprintf("%s (request_id: %s)", text.Text, requestID)
+			result.Content[i] = text
+		}
+		return
+	}
+}
+
+// SandboxNamespaceMiddleware returns a tool middleware that confines every mutating tool call
+// to the given namespace. Tools not annotated read-only have their namespace argument rewritten
+// to the sandbox namespace; mutating tools that take no namespace (cluster-scoped operations
+// such as node drains or ACL changes) are refused. Session-scoped tools such as
+// set_session_defaults are left alone. An empty namespace disables the sandbox.
+func SandboxNamespaceMiddleware(namespace string) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			srv := server.ServerFromContext(ctx)
+			if namespace == "" || srv == nil {
+				return next(ctx, request)
+			}
+			tool := srv.GetTool(request.Params.Name)
+			if tool == nil || isReadOnlyTool(tool.Tool) || sessionScopedTools[request.Params.Name] {
+				return next(ctx, request)
+			}
+
+			if sandboxRefuses(tool.Tool) {
+				return mcp.NewToolResultError(fmt.Sprintf("%s is disabled in sandbox mode: it is not namespace-scoped and mutating operations are confined to namespace %q", request.Params.Name, namespace)), nil
+			}
+
+			arguments, _ := request.Params.Arguments.(map[string]interface{})
+			rewritten := make(map[string]interface{}, len(arguments)+1)
+			for k, v := range arguments {
+				rewritten[k] = v
+			}
+			rewritten["namespace"] = namespace
+			request.Params.Arguments = rewritten
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// sandboxRefuses reports whether sandbox mode blocks a tool: it mutates state but cannot be
+// pinned to a namespace.
+func sandboxRefuses(tool mcp.Tool) bool {
+	if isReadOnlyTool(tool) || sessionScopedTools[tool.Name] {
+		return false
+	}
+	_, namespaced := tool.InputSchema.Properties["namespace"]
+	return !namespaced
+}
+
+// ToolTimeouts holds the maximum execution time of tool calls. PerTool entries take
+// precedence over the Read/Write category defaults; a zero duration means no limit.
+type ToolTimeouts struct {
+	Read    time.Duration
+	Write   time.Duration
+	PerTool map[string]time.Duration
+}
+
+// ParseToolTimeouts parses a comma-separated list of tool=duration pairs,
+// e.g. "get_allocation_logs=60s,run_job=5m".
+func ParseToolTimeouts(spec string) (map[string]time.Duration, error) {
+	timeouts := map[string]time.Duration{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid tool timeout %q: expected tool=duration", entry)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid duration for tool %s: %q", strings.TrimSpace(name), value)
+		}
+		timeouts[strings.TrimSpace(name)] = d
+	}
+	return timeouts, nil
+}
+
+// timeoutFor picks the limit for a tool: its own entry, else the category default.
+// Tools that cannot be looked up are treated as mutating.
+func (t ToolTimeouts) timeoutFor(ctx context.Context, name string) time.Duration {
+	if d, ok := t.PerTool[name]; ok {
+		return d
+	}
+	if srv := server.ServerFromContext(ctx); srv != nil {
+		if tool := srv.GetTool(name); tool != nil && isReadOnlyTool(tool.Tool) {
+			return t.Read
+		}
+	}
+	return t.Write
+}
+
+// TimeoutMiddleware returns a tool middleware that bounds every tool call with a context
+// deadline. The call's result is abandoned once the deadline passes, so a handler that
+// ignores cancellation cannot hang the session.
+func TimeoutMiddleware(timeouts ToolTimeouts) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			limit := timeouts.timeoutFor(ctx, request.Params.Name)
+			if limit <= 0 {
+				return next(ctx, request)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, limit)
+			defer cancel()
+
+			type outcome struct {
+				result *mcp.CallToolResult
+				err    error
+			}
+			done := make(chan outcome, 1)
+			go func() {
+				result, err := next(ctx, request)
+				done <- outcome{result, err}
+			}()
+
+			select {
+			case out := <-done:
+				if ctx.Err() == context.DeadlineExceeded && (out.err != nil || (out.result != nil && out.result.IsError)) {
+					return mcp.NewToolResultError(fmt.Sprintf("%s timed out after %s", request.Params.Name, limit)), nil
+				}
+				return out.result, out.err
+			case <-ctx.Done():
+				if ctx.Err() == context.DeadlineExceeded {
+					return mcp.NewToolResultError(fmt.Sprintf("%s timed out after %s", request.Params.Name, limit)), nil
+				}
+				return nil, ctx.Err()
+			}
+		}
+	}
+}
+
+// isReadOnlyTool reports whether a tool is annotated as not modifying cluster state.
+func isReadOnlyTool(tool mcp.Tool) bool {
+	return tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+}
+
+// resultPage is the JSON shape of one content block produced by PaginateLargeResults.
+type resultPage struct {
+	Page  int               `json:"page"`
+	Pages int               `json:"pages"`
+	Items []json.RawMessage `json:"items"`
+}
+
+// PaginateLargeResults returns a tool middleware that splits oversized JSON array results
+// into several text content blocks of at most maxBytes each (one item minimum per block),
+// so HTTP clients that truncate single huge strings still receive every item.
+// Non-array and error results are passed through untouched. maxBytes <= 0 disables paging.
+func PaginateLargeResults(maxBytes int) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil || result.IsError || maxBytes <= 0 {
+				return result, err
+			}
+
+			var content []mcp.Content
+			for _, c := range result.Content {
+				text, ok := c.(mcp.TextContent)
+				if !ok || len(text.Text) <= maxBytes {
+					content = append(content, c)
+					continue
+				}
+				pages, ok := paginateJSONArray(text.Text, maxBytes)
+				if !ok {
+					content = append(content, c)
+					continue
+				}
+				content = append(content, pages...)
+			}
+			result.Content = content
+
+			return result, nil
+		}
+	}
+}
+
+// paginateJSONArray splits a JSON array document into page blocks. It reports false when
+// the text is not a non-empty JSON array.
+func paginateJSONArray(text string, maxBytes int) ([]mcp.Content, bool) {
+	var items []json.RawMessage
+	if err := json.Unmarshal([]byte(text), &items); err != nil {
+		return nil, false
+	}
+
+	var groups [][]json.RawMessage
+	var current []json.RawMessage
+	size := 0
+	for _, item := range items {
+		if len(current) > 0 && size+len(item) > maxBytes {
+			groups = append(groups, current)
+			current, size = nil, 0
+		}
+		current = append(current, item)
+		size += len(item)
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+
+	pages := make([]mcp.Content, 0, len(groups))
+	for i, group := range groups {
+		pageJSON, err := json.Marshal(resultPage{Page: i + 1, Pages: len(groups), Items: group})
+		if err != nil {
+			return nil, false
+		}
+		pages = append(pages, mcp.NewTextContent(string(pageJSON)))
+	}
+	return pages, len(pages) > 0
+}
@@ -14,10 +14,11 @@ import (
 )
 
 // RegisterNamespaceTools registers all namespace-related tools
-func RegisterNamespaceTools(s *server.MCPServer, nomadClient utils.NamespaceAPI, logger *log.Logger) {
+func RegisterNamespaceTools(s *server.MCPServer, nomadClient utils.NamespaceToolsDeps, logger *log.Logger) {
 	// List namespaces tool
 	listNamespacesTool := mcp.NewTool("list_namespaces",
 		mcp.WithDescription("List all namespaces in Nomad"),
+		mcp.WithReadOnlyHintAnnotation(true),
 	)
 	s.AddTool(listNamespacesTool, ListNamespacesHandler(nomadClient, logger))
 
@@ -36,13 +37,48 @@ func RegisterNamespaceTools(s *server.MCPServer, nomadClient utils.NamespaceAPI,
 
 	// Delete namespace tool
 	deleteNamespaceTool := mcp.NewTool("delete_namespace",
-		mcp.WithDescription("Delete a namespace"),
+		mcp.WithDescription("Delete a namespace. A preflight lists the jobs, variables and volumes left in it and refuses to delete unless cascade=true, which purges the jobs and deletes the variables first. Volumes are never removed and must be deleted explicitly"),
 		mcp.WithString("name",
 			mcp.Required(),
 			mcp.Description("The name of the namespace to delete"),
 		),
+		mcp.WithBoolean("cascade",
+			mcp.Description("Purge every job and delete every variable in the namespace before deleting it (default: false)"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Only report what is left in the namespace (default: false)"),
+		),
 	)
 	s.AddTool(deleteNamespaceTool, DeleteNamespaceHandler(nomadClient, logger))
+
+	// Bootstrap namespace tool
+	bootstrapNamespaceTool := mcp.NewTool("bootstrap_namespace",
+		mcp.WithDescription("Create a namespace, an ACL policy scoped to it and optionally a client token holding that policy, returning every created object. Refuses when the namespace or policy already exists unless overwrite is set"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the namespace to create"),
+		),
+		mcp.WithString("description",
+			mcp.Description("Description of the namespace"),
+		),
+		mcp.WithString("policy",
+			mcp.Description("Namespace capability granted by the policy (default: write)"),
+			mcp.Enum("read", "write", "scale"),
+		),
+		mcp.WithString("policy_name",
+			mcp.Description("Name of the ACL policy to create (default: <name>-<policy>)"),
+		),
+		mcp.WithBoolean("create_token",
+			mcp.Description("Also create a client token bound to the policy (default: false)"),
+		),
+		mcp.WithString("token_name",
+			mcp.Description("Name of the created token (default: <name>-token)"),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("Replace an existing namespace or policy of the same name, blanking the namespace's quota, capabilities and meta (default: false)"),
+		),
+	)
+	s.AddTool(bootstrapNamespaceTool, BootstrapNamespaceHandler(nomadClient, logger))
 }
 
 // ListNamespacesHandler returns a handler for listing namespaces
@@ -50,7 +86,7 @@ func ListNamespacesHandler(client utils.NamespaceAPI, logger *log.Logger) func(c
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		namespaces, err := client.ListNamespaces(ctx)
 		if err != nil {
-			logger.Printf("Error listing namespaces: %v", err)
+			requestLogf(ctx, logger, "Error listing namespaces: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to list namespaces", err), nil
 		}
 
@@ -88,7 +124,7 @@ func CreateNamespaceHandler(client utils.NamespaceAPI, logger *log.Logger) func(
 
 		err := client.CreateNamespace(ctx, namespace)
 		if err != nil {
-			logger.Printf("Error creating namespace: %v", err)
+			requestLogf(ctx, logger, "Error creating namespace: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to create namespace", err), nil
 		}
 
@@ -106,7 +142,7 @@ func CreateNamespaceHandler(client utils.NamespaceAPI, logger *log.Logger) func(
 }
 
 // DeleteNamespaceHandler returns a handler for deleting a namespace
-func DeleteNamespaceHandler(client utils.NamespaceAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func DeleteNamespaceHandler(client utils.NamespaceToolsDeps, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		arguments, ok := request.Params.Arguments.(map[string]interface{})
 		if !ok {
@@ -117,15 +153,172 @@ func DeleteNamespaceHandler(client utils.NamespaceAPI, logger *log.Logger) func(
 		if !ok || name == "" {
 			return mcp.NewToolResultError("name is required"), nil
 		}
+		if name == utils.NomadDefaultNamespace {
+			return mcp.NewToolResultError("the default namespace cannot be deleted"), nil
+		}
+		cascade, _ := arguments["cascade"].(bool)
+		dryRun, _ := arguments["dry_run"].(bool)
 
-		err := client.DeleteNamespace(ctx, name)
+		report := NamespaceDeletionReport{Namespace: name}
+		report.Removed.Jobs, report.Removed.Variables = []string{}, []string{}
+		residue, err := namespaceResidue(ctx, client, name)
 		if err != nil {
-			logger.Printf("Error deleting namespace: %v", err)
-			return mcp.NewToolResultErrorFromErr("Failed to delete namespace", err), nil
+			requestLogf(ctx, logger, "Error inspecting namespace: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to inspect namespace", err), nil
 		}
+		report.Residue = residue
+		empty := len(report.Residue.Jobs) == 0 && len(report.Residue.Variables) == 0 && len(report.Residue.Volumes) == 0
 
-		result := map[string]string{
-			"message": fmt.Sprintf("Successfully deleted namespace %s", name),
+		switch {
+		case dryRun:
+			return namespaceDeletionResult(report, false)
+		case !empty && !cascade:
+			report.Failures = append(report.Failures, "namespace is not empty; retry with cascade=true to purge its jobs and delete its variables")
+			return namespaceDeletionResult(report, true)
+		case len(report.Residue.Volumes) > 0:
+			report.Failures = append(report.Failures, fmt.Sprintf("namespace still has %d volume(s); delete or deregister them first, cascade does not remove volumes", len(report.Residue.Volumes)))
+			return namespaceDeletionResult(report, true)
+		}
+
+		if cascade {
+			for _, job := range report.Residue.Jobs {
+				if _, err := client.StopJob(ctx, job.ID, name, types.JobStopOptions{Purge: true}); err != nil && !isNotFound(err) {
+					requestLogf(ctx, logger, "Error purging job %s: %v", job.ID, err)
+					report.Failures = append(report.Failures, fmt.Sprintf("job %s: %v", job.ID, err))
+					continue
+				}
+				report.Removed.Jobs = append(report.Removed.Jobs, job.ID)
+			}
+			for _, path := range report.Residue.Variables {
+				if err := client.DeleteVariable(ctx, path, name, 0); err != nil && !isNotFound(err) {
+					requestLogf(ctx, logger, "Error deleting variable %s: %v", path, err)
+					report.Failures = append(report.Failures, fmt.Sprintf("variable %s: %v", path, err))
+					continue
+				}
+				report.Removed.Variables = append(report.Removed.Variables, path)
+			}
+			if len(report.Failures) > 0 {
+				return namespaceDeletionResult(report, true)
+			}
+		}
+
+		if err := client.DeleteNamespace(ctx, name); err != nil {
+			requestLogf(ctx, logger, "Error deleting namespace: %v", err)
+			report.Failures = append(report.Failures, err.Error())
+			return namespaceDeletionResult(report, true)
+		}
+		report.Deleted = true
+
+		return namespaceDeletionResult(report, false)
+	}
+}
+
+// namespaceBootstrap is the bootstrap_namespace response.
+type namespaceBootstrap struct {
+	Namespace types.Namespace `json:"Namespace"`
+	Policy    types.ACLPolicy `json:"Policy"`
+	Token     *types.ACLToken `json:"Token,omitempty"`
+}
+
+// namespacePolicyRules renders an ACL policy granting one namespace capability.
+func namespacePolicyRules(namespace, policy string) string {
+	return fmt.Sprintf("namespace %q {\n  policy = %q\n}\n", namespace, policy)
+}
+
+// BootstrapNamespaceHandler returns a handler that creates a namespace with a scoped policy and token
+func BootstrapNamespaceHandler(client utils.NamespaceToolsDeps, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		name, ok := arguments["name"].(string)
+		if !ok || name == "" {
+			return mcp.NewToolResultError("name is required"), nil
+		}
+
+		description, _ := arguments["description"].(string)
+
+		capability := "write"
+		if p, ok := arguments["policy"].(string); ok && p != "" {
+			capability = p
+		}
+		switch capability {
+		case "read", "write", "scale":
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("policy must be read, write or scale, got %q", capability)), nil
+		}
+
+		policyName := fmt.Sprintf("%s-%s", name, capability)
+		if p, ok := arguments["policy_name"].(string); ok && p != "" {
+			policyName = p
+		}
+
+		createToken, _ := arguments["create_token"].(bool)
+		tokenName := fmt.Sprintf("%s-token", name)
+		if t, ok := arguments["token_name"].(string); ok && t != "" {
+			tokenName = t
+		}
+
+		// Nomad creates or updates on both endpoints, so an existing namespace or policy would
+		// silently be replaced.
+		if overwrite, _ := arguments["overwrite"].(bool); !overwrite {
+			existing := []string{}
+			_, err := client.GetNamespace(ctx, name)
+			switch {
+			case err == nil:
+				existing = append(existing, fmt.Sprintf("namespace %s", name))
+			case !isNotFound(err):
+				requestLogf(ctx, logger, "Error checking namespace: %v", err)
+				return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Failed to check whether namespace %s exists", name), err), nil
+			}
+			_, err = client.GetACLPolicy(ctx, policyName)
+			switch {
+			case err == nil:
+				existing = append(existing, fmt.Sprintf("ACL policy %s", policyName))
+			case !isNotFound(err):
+				requestLogf(ctx, logger, "Error checking ACL policy: %v", err)
+				return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Failed to check whether ACL policy %s exists", policyName), err), nil
+			}
+			switch len(existing) {
+			case 1:
+				return mcp.NewToolResultError(fmt.Sprintf("%s already exists; pass overwrite=true to replace it", existing[0])), nil
+			case 2:
+				return mcp.NewToolResultError(fmt.Sprintf("%s and %s already exist; pass overwrite=true to replace them", existing[0], existing[1])), nil
+			}
+		}
+
+		result := namespaceBootstrap{
+			Namespace: types.Namespace{Name: name, Description: description},
+			Policy: types.ACLPolicy{
+				Name:        policyName,
+				Description: fmt.Sprintf("%s access to namespace %s", capability, name),
+				Rules:       namespacePolicyRules(name, capability),
+			},
+		}
+
+		if err := client.CreateNamespace(ctx, result.Namespace); err != nil {
+			requestLogf(ctx, logger, "Error creating namespace: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to create namespace", err), nil
+		}
+
+		if err := client.CreateACLPolicy(ctx, result.Policy); err != nil {
+			requestLogf(ctx, logger, "Error creating ACL policy: %v", err)
+			return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Namespace %s was created but creating ACL policy %s failed", name, policyName), err), nil
+		}
+
+		if createToken {
+			token, err := client.CreateACLToken(ctx, types.ACLToken{
+				Name:     tokenName,
+				Type:     "client",
+				Policies: []string{policyName},
+			})
+			if err != nil {
+				requestLogf(ctx, logger, "Error creating ACL token: %v", err)
+				return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Namespace %s and ACL policy %s were created but creating the token failed", name, policyName), err), nil
+			}
+			result.Token = &token
 		}
 
 		resultJSON, err := json.MarshalIndent(result, "", "  ")
@@ -136,3 +329,72 @@ func DeleteNamespaceHandler(client utils.NamespaceAPI, logger *log.Logger) func(
 		return mcp.NewToolResultText(string(resultJSON)), nil
 	}
 }
+
+// ResidualJob is a job left in a namespace being deleted
+type ResidualJob struct {
+	ID     string `json:"ID"`
+	Type   string `json:"Type"`
+	Status string `json:"Status"`
+	// Blocking jobs are not dead; Nomad refuses to delete a namespace that has them.
+	Blocking bool `json:"Blocking"`
+}
+
+// NamespaceResidue lists the objects still stored in a namespace
+type NamespaceResidue struct {
+	Jobs      []ResidualJob      `json:"Jobs"`
+	Variables []string           `json:"Variables"`
+	Volumes   []types.VolumeStub `json:"Volumes"`
+}
+
+// NamespaceDeletionReport is the delete_namespace response
+type NamespaceDeletionReport struct {
+	Namespace string           `json:"Namespace"`
+	Residue   NamespaceResidue `json:"Residue"`
+	Removed   struct {
+		Jobs      []string `json:"Jobs"`
+		Variables []string `json:"Variables"`
+	} `json:"Removed"`
+	Deleted  bool     `json:"Deleted"`
+	Failures []string `json:"Failures,omitempty"`
+}
+
+// namespaceResidue lists the jobs, variables and volumes in a namespace. Dead jobs do not block
+// the deletion but would be left behind in a namespace that no longer exists.
+func namespaceResidue(ctx context.Context, client utils.NamespaceToolsDeps, namespace string) (NamespaceResidue, error) {
+	residue := NamespaceResidue{Jobs: []ResidualJob{}, Variables: []string{}, Volumes: []types.VolumeStub{}}
+
+	jobs, err := client.ListJobs(ctx, namespace, "")
+	if err != nil {
+		return residue, fmt.Errorf("error listing jobs: %w", err)
+	}
+	for _, job := range jobs {
+		residue.Jobs = append(residue.Jobs, ResidualJob{ID: job.ID, Type: job.Type, Status: job.Status, Blocking: job.Status != "dead"})
+	}
+
+	variables, err := client.ListVariables(ctx, namespace, "", "", 0, "")
+	if err != nil {
+		return residue, fmt.Errorf("error listing variables: %w", err)
+	}
+	for _, v := range variables {
+		residue.Variables = append(residue.Variables, v.Path)
+	}
+
+	volumes, err := client.ListNamespaceVolumes(ctx, namespace)
+	if err != nil {
+		return residue, fmt.Errorf("error listing volumes: %w", err)
+	}
+	residue.Volumes = append(residue.Volumes, volumes...)
+
+	return residue, nil
+}
+
+func namespaceDeletionResult(report NamespaceDeletionReport, isError bool) (*mcp.CallToolResult, error) {
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format namespace deletion report", err), nil
+	}
+	if isError {
+		return mcp.NewToolResultError(string(reportJSON)), nil
+	}
+	return mcp.NewToolResultText(string(reportJSON)), nil
+}
@@ -0,0 +1,278 @@
+// File: tools/node_connectivity.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Nomad's heartbeat defaults, used when the agent queried is not a server.
+const (
+	nomadDefaultHeartbeatGrace         = 10 * time.Second
+	nomadDefaultMinHeartbeatTTL        = 10 * time.Second
+	nomadDefaultMaxHeartbeatsPerSecond = 50.0
+	// defaultConnectivityWindow is how far back node events are read without since.
+	defaultConnectivityWindow = 24 * time.Hour
+)
+
+// Messages of the node events servers record about heartbeats.
+const (
+	nodeEventHeartbeatMissed = "Node heartbeat missed"
+	nodeEventReregistered    = "Node reregistered by heartbeat"
+	nodeEventRegistered      = "Node registered"
+)
+
+// Diagnoses of diagnose_node_connectivity, most severe first.
+var connectivityDiagnoses = []string{"down", "disconnected", "flapping", "recovered", "initializing", "healthy"}
+
+// HeartbeatTTLEstimate is the heartbeat TTL servers give clients. Servers spread heartbeats
+// by picking a random TTL between TTL and MaxTTL, and mark a node down once it has been
+// silent for its TTL plus the grace period.
+type HeartbeatTTLEstimate struct {
+	Source                 string  `json:"Source"`
+	MinHeartbeatTTL        string  `json:"MinHeartbeatTTL"`
+	MaxHeartbeatsPerSecond float64 `json:"MaxHeartbeatsPerSecond"`
+	HeartbeatGrace         string  `json:"HeartbeatGrace"`
+	LiveNodes              int     `json:"LiveNodes"`
+	TTL                    string  `json:"TTL"`
+	MaxTTL                 string  `json:"MaxTTL"`
+	// DownAfter is the longest a node can go without heartbeating before it is marked down
+	DownAfter string `json:"DownAfter"`
+}
+
+// NodeConnectivityReport is the heartbeat history of one node and what it suggests
+type NodeConnectivityReport struct {
+	ID                  string            `json:"ID"`
+	Name                string            `json:"Name"`
+	Datacenter          string            `json:"Datacenter,omitempty"`
+	HTTPAddr            string            `json:"HTTPAddr,omitempty"`
+	Status              string            `json:"Status"`
+	StatusDescription   string            `json:"StatusDescription,omitempty"`
+	StatusUpdatedAt     time.Time         `json:"StatusUpdatedAt"`
+	StatusAge           string            `json:"StatusAge"`
+	MissedHeartbeats    int               `json:"MissedHeartbeats"`
+	Reregistrations     int               `json:"Reregistrations"`
+	LastMissedHeartbeat *time.Time        `json:"LastMissedHeartbeat,omitempty"`
+	Events              []types.NodeEvent `json:"Events,omitempty"`
+	Diagnosis           string            `json:"Diagnosis"`
+	Explanation         string            `json:"Explanation"`
+}
+
+// NodeConnectivityDiagnosis is the result of diagnose_node_connectivity
+type NodeConnectivityDiagnosis struct {
+	Since        time.Time                `json:"Since"`
+	HeartbeatTTL HeartbeatTTLEstimate     `json:"HeartbeatTTL"`
+	Nodes        []NodeConnectivityReport `json:"Nodes"`
+	// HealthyNodes counts the healthy nodes left out of Nodes
+	HealthyNodes int `json:"HealthyNodes,omitempty"`
+}
+
+// RegisterNodeConnectivityTools registers the node heartbeat diagnostics tool
+func RegisterNodeConnectivityTools(s *server.MCPServer, nomadClient utils.NodeConnectivityAPI, logger *log.Logger) {
+	diagnoseNodeConnectivityTool := mcp.NewTool("diagnose_node_connectivity",
+		mcp.WithDescription("Diagnose client heartbeats: the heartbeat TTL servers give clients, when each node's status last changed, and the missed heartbeats and re-registrations in its recent events, to tell network flaps from genuinely dead clients"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("node_id",
+			mcp.Description("The node to diagnose (default: every node that missed a heartbeat or is not ready)"),
+		),
+		mcp.WithString("since",
+			mcp.Description("How far back to read node events, e.g. 30m, 2h or 7d (default: 24h)"),
+		),
+		mcp.WithBoolean("include_healthy",
+			mcp.Description("Also report nodes without missed heartbeats when diagnosing every node (default: false)"),
+		),
+	)
+	s.AddTool(diagnoseNodeConnectivityTool, DiagnoseNodeConnectivityHandler(nomadClient, logger))
+}
+
+// DiagnoseNodeConnectivityHandler returns a handler for diagnosing node heartbeats
+func DiagnoseNodeConnectivityHandler(client utils.NodeConnectivityAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		now := time.Now()
+		cutoff, err := sinceArgument(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if cutoff.IsZero() {
+			cutoff = now.Add(-defaultConnectivityWindow)
+		}
+		nodeID, _ := arguments["node_id"].(string)
+		includeHealthy, _ := arguments["include_healthy"].(bool)
+
+		nodes, err := client.ListNodes(ctx, "")
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing nodes: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list nodes", err), nil
+		}
+		liveNodes := 0
+		for _, node := range nodes {
+			if node.Status != "down" {
+				liveNodes++
+			}
+		}
+
+		diagnosis := NodeConnectivityDiagnosis{
+			Since:        cutoff.UTC(),
+			HeartbeatTTL: heartbeatTTLEstimate(ctx, client, liveNodes),
+			Nodes:        []NodeConnectivityReport{},
+		}
+
+		ids := []string{nodeID}
+		if nodeID == "" {
+			ids = make([]string, 0, len(nodes))
+			for _, node := range nodes {
+				ids = append(ids, node.ID)
+			}
+		}
+		for _, id := range ids {
+			node, err := client.GetNodeConnectivity(ctx, id)
+			if err != nil {
+				requestLogf(ctx, logger, "Error getting node %s: %v", id, err)
+				return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Failed to get node %s", id), err), nil
+			}
+			report := nodeConnectivityReport(node, cutoff, now)
+			if nodeID == "" && !includeHealthy && report.Diagnosis == "healthy" {
+				diagnosis.HealthyNodes++
+				continue
+			}
+			diagnosis.Nodes = append(diagnosis.Nodes, report)
+		}
+		sort.SliceStable(diagnosis.Nodes, func(i, j int) bool {
+			a, b := diagnosis.Nodes[i], diagnosis.Nodes[j]
+			if a.Diagnosis != b.Diagnosis {
+				return diagnosisRank(a.Diagnosis) < diagnosisRank(b.Diagnosis)
+			}
+			return a.Name < b.Name
+		})
+
+		diagnosisJSON, err := json.MarshalIndent(diagnosis, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format node connectivity", err), nil
+		}
+
+		return mcp.NewToolResultText(string(diagnosisJSON)), nil
+	}
+}
+
+// heartbeatTTLEstimate works out the heartbeat TTL the way servers do: the number of live
+// nodes divided by the heartbeat rate servers accept, but at least the minimum TTL.
+func heartbeatTTLEstimate(ctx context.Context, client utils.NodeConnectivityAPI, liveNodes int) HeartbeatTTLEstimate {
+	minTTL, grace, rate := nomadDefaultMinHeartbeatTTL, nomadDefaultHeartbeatGrace, nomadDefaultMaxHeartbeatsPerSecond
+	source := "Nomad defaults; the agent queried is not a server"
+	if self, err := client.GetAgentSelf(ctx); err != nil {
+		source = fmt.Sprintf("Nomad defaults; the agent configuration could not be read: %v", err)
+	} else if srv := self.Config.Server; srv != nil && srv.Enabled {
+		source = "server configuration"
+		if srv.MinHeartbeatTTL > 0 {
+			minTTL = srv.MinHeartbeatTTL
+		}
+		if srv.HeartbeatGrace > 0 {
+			grace = srv.HeartbeatGrace
+		}
+		if srv.MaxHeartbeatsPerSecond > 0 {
+			rate = srv.MaxHeartbeatsPerSecond
+		}
+	}
+
+	ttl := max(minTTL, time.Duration(float64(liveNodes)/rate*float64(time.Second)))
+	return HeartbeatTTLEstimate{
+		Source:                 source,
+		MinHeartbeatTTL:        minTTL.String(),
+		MaxHeartbeatsPerSecond: rate,
+		HeartbeatGrace:         grace.String(),
+		LiveNodes:              liveNodes,
+		TTL:                    ttl.Round(time.Second).String(),
+		MaxTTL:                 (2 * ttl).Round(time.Second).String(),
+		DownAfter:              (2*ttl + grace).Round(time.Second).String(),
+	}
+}
+
+// nodeConnectivityReport counts the heartbeat events of node since cutoff and diagnoses it.
+func nodeConnectivityReport(node types.NodeConnectivity, cutoff, now time.Time) NodeConnectivityReport {
+	report := NodeConnectivityReport{
+		ID:                node.ID,
+		Name:              node.Name,
+		Datacenter:        node.Datacenter,
+		HTTPAddr:          node.HTTPAddr,
+		Status:            node.Status,
+		StatusDescription: node.StatusDescription,
+	}
+	if node.StatusUpdatedAt > 0 {
+		report.StatusUpdatedAt = time.Unix(node.StatusUpdatedAt, 0).UTC()
+		report.StatusAge = now.Sub(report.StatusUpdatedAt).Round(time.Second).String()
+	}
+
+	for _, event := range node.Events {
+		if event.Timestamp.Before(cutoff) {
+			continue
+		}
+		switch event.Message {
+		case nodeEventHeartbeatMissed:
+			report.MissedHeartbeats++
+			missed := event.Timestamp.UTC()
+			report.LastMissedHeartbeat = &missed
+		case nodeEventReregistered, nodeEventRegistered:
+			report.Reregistrations++
+		default:
+			continue
+		}
+		report.Events = append(report.Events, event)
+	}
+
+	report.Diagnosis, report.Explanation = diagnoseConnectivity(report)
+	return report
+}
+
+// diagnoseConnectivity classifies a node from its status and heartbeat history.
+func diagnoseConnectivity(report NodeConnectivityReport) (string, string) {
+	since := ""
+	if report.StatusAge != "" {
+		since = fmt.Sprintf(" for %s", report.StatusAge)
+	}
+	flapped := ""
+	if report.MissedHeartbeats > 1 {
+		flapped = fmt.Sprintf(" It missed %d heartbeats in the window, so it was flapping before it went away.", report.MissedHeartbeats)
+	}
+
+	switch report.Status {
+	case "down":
+		return "down", fmt.Sprintf("The node has been down%s and has not heartbeated since: the client is stopped or dead, or cut off from the servers for good. Check the client host and its nomad service.%s", since, flapped)
+	case "disconnected":
+		return "disconnected", fmt.Sprintf("The node has missed its heartbeats%s; allocations with max_client_disconnect are kept as unknown until it reconnects or the window expires.%s", since, flapped)
+	case "initializing":
+		return "initializing", "The node is registering and has not finished fingerprinting yet."
+	}
+
+	switch report.MissedHeartbeats {
+	case 0:
+		return "healthy", "The node heartbeats normally."
+	case 1:
+		return "recovered", fmt.Sprintf("The node missed one heartbeat at %s and re-registered: a transient network problem or a paused client.", report.LastMissedHeartbeat.Format(time.RFC3339))
+	default:
+		return "flapping", fmt.Sprintf("The node missed %d heartbeats in the window and re-registered each time: it is alive but its connection to the servers is unstable. Look for packet loss or latency between client and servers, an overloaded client or servers, or a heartbeat TTL too short for the RPC latency.", report.MissedHeartbeats)
+	}
+}
+
+// diagnosisRank orders diagnoses by severity.
+func diagnosisRank(diagnosis string) int {
+	for i, d := range connectivityDiagnoses {
+		if d == diagnosis {
+			return i
+		}
+	}
+	return len(connectivityDiagnoses)
+}
@@ -0,0 +1,236 @@
+// File: tools/node_pools.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// builtInNodePools are created by Nomad and cannot be deleted.
+var builtInNodePools = map[string]bool{"all": true, "default": true}
+
+// RegisterNodePoolTools registers the node pool tools
+func RegisterNodePoolTools(s *server.MCPServer, nomadClient utils.NodePoolAPI, logger *log.Logger) {
+	listNodePoolsTool := mcp.NewTool("list_node_pools",
+		mcp.WithDescription("List the node pools of the cluster, including the built-in all and default pools"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("prefix",
+			mcp.Description("Only list node pools whose name starts with this prefix"),
+		),
+	)
+	s.AddTool(listNodePoolsTool, ListNodePoolsHandler(nomadClient, logger))
+
+	getNodePoolTool := mcp.NewTool("get_node_pool",
+		mcp.WithDescription("Get a node pool with its metadata and scheduler configuration"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the node pool"),
+		),
+	)
+	s.AddTool(getNodePoolTool, GetNodePoolHandler(nomadClient, logger))
+
+	createNodePoolTool := mcp.NewTool("create_node_pool",
+		mcp.WithDescription("Create a node pool, or update the one with the same name. Clients join a pool through the node_pool setting of their agent configuration"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the node pool"),
+		),
+		mcp.WithString("description",
+			mcp.Description("Description of the node pool"),
+		),
+		mcp.WithObject("meta",
+			mcp.Description("Metadata of the node pool as string key/value pairs"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("scheduler_algorithm",
+			mcp.Description("Scheduler algorithm for jobs in the pool, overriding the cluster setting (Nomad Enterprise)"),
+			mcp.Enum("binpack", "spread"),
+		),
+		mcp.WithBoolean("memory_oversubscription",
+			mcp.Description("Allow memory oversubscription for jobs in the pool, overriding the cluster setting (Nomad Enterprise)"),
+		),
+	)
+	s.AddTool(createNodePoolTool, CreateNodePoolHandler(nomadClient, logger))
+
+	deleteNodePoolTool := mcp.NewTool("delete_node_pool",
+		mcp.WithDescription("Delete a node pool. Nomad refuses while nodes or jobs still use it; the built-in all and default pools cannot be deleted"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the node pool"),
+		),
+	)
+	s.AddTool(deleteNodePoolTool, DeleteNodePoolHandler(nomadClient, logger))
+
+	listNodePoolNodesTool := mcp.NewTool("list_node_pool_nodes",
+		mcp.WithDescription("List the nodes in a node pool with their status, eligibility and drain state"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the node pool (all lists every node)"),
+		),
+	)
+	s.AddTool(listNodePoolNodesTool, ListNodePoolNodesHandler(nomadClient, logger))
+}
+
+// ListNodePoolsHandler returns a handler for listing node pools
+func ListNodePoolsHandler(client utils.NodePoolAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+		prefix, _ := arguments["prefix"].(string)
+
+		pools, err := client.ListNodePools(ctx, prefix)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing node pools: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list node pools", err), nil
+		}
+
+		poolsJSON, err := json.MarshalIndent(pools, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format node pools", err), nil
+		}
+
+		return mcp.NewToolResultText(string(poolsJSON)), nil
+	}
+}
+
+// GetNodePoolHandler returns a handler for getting a node pool
+func GetNodePoolHandler(client utils.NodePoolAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		name, _ := arguments["name"].(string)
+		if name == "" {
+			return mcp.NewToolResultError("name is required"), nil
+		}
+
+		pool, err := client.GetNodePool(ctx, name)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting node pool: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get node pool", err), nil
+		}
+
+		poolJSON, err := json.MarshalIndent(pool, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format node pool", err), nil
+		}
+
+		return mcp.NewToolResultText(string(poolJSON)), nil
+	}
+}
+
+// CreateNodePoolHandler returns a handler for creating or updating a node pool
+func CreateNodePoolHandler(client utils.NodePoolAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		name, _ := arguments["name"].(string)
+		if name == "" {
+			return mcp.NewToolResultError("name is required"), nil
+		}
+		if name == "all" {
+			return mcp.NewToolResultError("the built-in all node pool cannot be modified"), nil
+		}
+
+		pool := types.NodePool{Name: name}
+		pool.Description, _ = arguments["description"].(string)
+		if raw, ok := arguments["meta"].(map[string]interface{}); ok && len(raw) > 0 {
+			pool.Meta = make(map[string]string, len(raw))
+			for key, value := range raw {
+				text, ok := value.(string)
+				if !ok {
+					return mcp.NewToolResultError(fmt.Sprintf("meta.%s must be a string", key)), nil
+				}
+				pool.Meta[key] = text
+			}
+		}
+
+		algorithm, _ := arguments["scheduler_algorithm"].(string)
+		oversubscription, hasOversubscription := arguments["memory_oversubscription"].(bool)
+		if algorithm != "" || hasOversubscription {
+			pool.SchedulerConfiguration = &types.NodePoolSchedulerConfiguration{SchedulerAlgorithm: algorithm}
+			if hasOversubscription {
+				pool.SchedulerConfiguration.MemoryOversubscriptionEnabled = &oversubscription
+			}
+		}
+
+		if err := client.UpsertNodePool(ctx, pool); err != nil {
+			requestLogf(ctx, logger, "Error creating node pool: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to create node pool", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Node pool %s created successfully", name)), nil
+	}
+}
+
+// DeleteNodePoolHandler returns a handler for deleting a node pool
+func DeleteNodePoolHandler(client utils.NodePoolAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		name, _ := arguments["name"].(string)
+		if name == "" {
+			return mcp.NewToolResultError("name is required"), nil
+		}
+		if builtInNodePools[name] {
+			return mcp.NewToolResultError(fmt.Sprintf("the built-in %s node pool cannot be deleted", name)), nil
+		}
+
+		if err := client.DeleteNodePool(ctx, name); err != nil {
+			requestLogf(ctx, logger, "Error deleting node pool: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to delete node pool", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Node pool %s deleted successfully", name)), nil
+	}
+}
+
+// ListNodePoolNodesHandler returns a handler for listing the nodes of a node pool
+func ListNodePoolNodesHandler(client utils.NodePoolAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		name, _ := arguments["name"].(string)
+		if name == "" {
+			return mcp.NewToolResultError("name is required"), nil
+		}
+
+		nodes, err := client.ListNodePoolNodes(ctx, name)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing node pool nodes: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list node pool nodes", err), nil
+		}
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+		nodesJSON, err := json.MarshalIndent(nodes, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format node pool nodes", err), nil
+		}
+
+		return mcp.NewToolResultText(string(nodesJSON)), nil
+	}
+}
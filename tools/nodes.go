@@ -4,38 +4,47 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"sort"
 
+	"github.com/kocierik/mcp-nomad/types"
 	"github.com/kocierik/mcp-nomad/utils"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 // RegisterNodeTools registers all node-related tools
-func RegisterNodeTools(s *server.MCPServer, nomadClient utils.NodeAPI, logger *log.Logger) {
+func RegisterNodeTools(s *server.MCPServer, nomadClient utils.NodeToolsDeps, logger *log.Logger) {
 	// List nodes tool
 	listNodesTool := mcp.NewTool("list_nodes",
 		mcp.WithDescription("List all nodes in the Nomad cluster"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("status",
 			mcp.Description("Filter nodes by status"),
 			mcp.Enum("ready", "down", ""),
 		),
+		mcp.WithBoolean("all_regions",
+			mcp.Description(allRegionsDescription),
+		),
 	)
 	s.AddTool(listNodesTool, ListNodesHandler(nomadClient, logger))
 
 	// Get node tool
 	getNodeTool := mcp.NewTool("get_node",
 		mcp.WithDescription("Get details for a specific node"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("node_id",
 			mcp.Required(),
 			mcp.Description("The ID of the node to retrieve"),
 		),
+		fieldsOption(),
 	)
 	s.AddTool(getNodeTool, GetNodeHandler(nomadClient, logger))
 
 	// Drain node tool
 	drainNodeTool := mcp.NewTool("drain_node",
-		mcp.WithDescription("Enable or disable drain mode for a node"),
+		mcp.WithDescription("Enable or disable drain mode for a node. Before enabling, a preflight looks for services and jobs whose only running allocations are on the node and refuses to drain unless force=true"),
 		mcp.WithString("node_id",
 			mcp.Required(),
 			mcp.Description("The ID of the node to drain"),
@@ -47,22 +56,107 @@ func RegisterNodeTools(s *server.MCPServer, nomadClient utils.NodeAPI, logger *l
 		mcp.WithNumber("deadline",
 			mcp.Description("Deadline in seconds for the drain operation (default: -1, no deadline)"),
 		),
+		mcp.WithBoolean("preflight",
+			mcp.Description("Check for task groups that would lose their only running allocations before enabling drain (default: true)"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Drain even if the preflight finds at-risk task groups; they are returned as warnings (default: false)"),
+		),
 	)
 	s.AddTool(drainNodeTool, DrainNodeHandler(nomadClient, logger))
 
+	// Enter node maintenance tool
+	enterNodeMaintenanceTool := mcp.NewTool("enter_node_maintenance",
+		mcp.WithDescription("Put a node into maintenance: run the drain preflight, mark it ineligible, drain it with a deadline and wait for its allocations to migrate. Returns a checklist of each step"),
+		mcp.WithString("node_id",
+			mcp.Required(),
+			mcp.Description("The ID of the node to put into maintenance"),
+		),
+		mcp.WithNumber("deadline",
+			mcp.Description("Drain deadline in seconds after which remaining allocations are stopped (default: 3600)"),
+		),
+		mcp.WithString("wait_timeout",
+			mcp.Description("How long to wait for migrations before returning, e.g. 90s or 10m; the drain keeps going afterwards (default: 90s)"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Proceed even if the preflight finds task groups that run only on this node (default: false)"),
+		),
+	)
+	s.AddTool(enterNodeMaintenanceTool, EnterNodeMaintenanceHandler(nomadClient, logger))
+
+	// Exit node maintenance tool
+	exitNodeMaintenanceTool := mcp.NewTool("exit_node_maintenance",
+		mcp.WithDescription("Take a node out of maintenance: stop any drain in progress and make it eligible for scheduling again"),
+		mcp.WithString("node_id",
+			mcp.Required(),
+			mcp.Description("The ID of the node to restore"),
+		),
+	)
+	s.AddTool(exitNodeMaintenanceTool, ExitNodeMaintenanceHandler(nomadClient, logger))
+
+	// Plan node upgrade waves tool
+	planNodeUpgradeWavesTool := mcp.NewTool("plan_node_upgrade_waves",
+		mcp.WithDescription("Partition ready client nodes into ordered drain waves for OS or Nomad upgrades. Each wave migrates no more of a service group's allocations than its migrate max_parallel and count allow, and groups with anti-affinity (distinct_hosts, spread, negative affinity) lose at most one allocation per wave"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("datacenter",
+			mcp.Description("Only plan nodes in this datacenter"),
+		),
+		mcp.WithString("node_pool",
+			mcp.Description("Only plan nodes in this node pool"),
+		),
+		mcp.WithString("node_class",
+			mcp.Description("Only plan nodes of this node class"),
+		),
+		mcp.WithNumber("max_wave_size",
+			mcp.Description("The most nodes drained together in one wave (default: 10% of the planned nodes, at least 1)"),
+		),
+	)
+	s.AddTool(planNodeUpgradeWavesTool, PlanNodeUpgradeWavesHandler(nomadClient, logger))
+
 	// Eligibility node tool
 	eligibilityNodeTool := mcp.NewTool("eligibility_node",
-		mcp.WithDescription("Set eligibility for a node"),
+		mcp.WithDescription("Set scheduling eligibility for a node and return its updated status and any created evaluations"),
 		mcp.WithString("node_id",
 			mcp.Required(),
 			mcp.Description("The ID of the node to set eligibility for"),
 		),
-		mcp.WithString("eligible",
+		mcp.WithBoolean("eligible",
 			mcp.Required(),
-			mcp.Description("The eligibility status to set (eligible or ineligible)"),
+			mcp.Description("true to make the node eligible for scheduling, false to mark it ineligible"),
 		),
 	)
 	s.AddTool(eligibilityNodeTool, EligibilityNodeHandler(nomadClient, logger))
+
+	// Purge node tool
+	purgeNodeTool := mcp.NewTool("purge_node",
+		mcp.WithDescription("Remove a decommissioned client node and its allocations from the cluster state. Nodes that are still ready are refused unless force is set, since a running client registers itself again"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("node_id",
+			mcp.Required(),
+			mcp.Description("The ID of the node to purge"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Purge the node even if it is still ready (default: false)"),
+		),
+	)
+	s.AddTool(purgeNodeTool, PurgeNodeHandler(nomadClient, logger))
+
+	// GC node tool
+	gcNodeTool := mcp.NewTool("gc_node",
+		mcp.WithDescription("Ask a client node to garbage collect its terminal allocations and their directories to free disk space"),
+		mcp.WithString("node_id",
+			mcp.Required(),
+			mcp.Description("The ID of the node to garbage collect"),
+		),
+	)
+	s.AddTool(gcNodeTool, GCNodeHandler(nomadClient, logger))
+
+	// List datacenters tool
+	listDatacentersTool := mcp.NewTool("list_datacenters",
+		mcp.WithDescription("List the datacenters that have client nodes, with node counts per datacenter"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+	s.AddTool(listDatacentersTool, ListDatacentersHandler(nomadClient, logger))
 }
 
 // ListNodesHandler returns a handler for listing nodes
@@ -78,9 +172,23 @@ func ListNodesHandler(client utils.NodeAPI, logger *log.Logger) func(context.Con
 			status = s
 		}
 
+		if allRegions, _ := arguments["all_regions"].(bool); allRegions {
+			results, err := fanOutRegions(ctx, client, func(ctx context.Context) ([]types.NodeSummary, error) {
+				return client.ListNodes(ctx, status)
+			})
+			if err != nil {
+				requestLogf(ctx, logger, "Error listing regions: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to list regions", err), nil
+			}
+			nodes, failures := mergeRegionResults(results, func(region string, node types.NodeSummary) regionNodeSummary {
+				return regionNodeSummary{Region: region, NodeSummary: node}
+			})
+			return regionFanOutResult(ctx, nodes, failures, len(results), "nodes", logger)
+		}
+
 		nodes, err := client.ListNodes(ctx, status)
 		if err != nil {
-			logger.Printf("Error listing nodes: %v", err)
+			requestLogf(ctx, logger, "Error listing nodes: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to list nodes", err), nil
 		}
 
@@ -93,6 +201,12 @@ func ListNodesHandler(client utils.NodeAPI, logger *log.Logger) func(context.Con
 	}
 }
 
+// regionNodeSummary is a list_nodes entry tagged with its region by all_regions.
+type regionNodeSummary struct {
+	Region string `json:"Region"`
+	types.NodeSummary
+}
+
 // GetNodeHandler returns a handler for getting node details
 func GetNodeHandler(client utils.NodeAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -106,13 +220,23 @@ func GetNodeHandler(client utils.NodeAPI, logger *log.Logger) func(context.Conte
 			return mcp.NewToolResultError("node_id is required"), nil
 		}
 
+		fields, err := fieldsArgument(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		node, err := client.GetNode(ctx, nodeID)
 		if err != nil {
-			logger.Printf("Error getting node: %v", err)
+			requestLogf(ctx, logger, "Error getting node: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get node", err), nil
 		}
 
-		nodeJSON, err := json.MarshalIndent(node, "", "  ")
+		projected, err := projectFields(node, fields)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to project fields", err), nil
+		}
+
+		nodeJSON, err := json.MarshalIndent(projected, "", "  ")
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Failed to format node", err), nil
 		}
@@ -122,7 +246,7 @@ func GetNodeHandler(client utils.NodeAPI, logger *log.Logger) func(context.Conte
 }
 
 // DrainNodeHandler returns a handler for draining a node
-func DrainNodeHandler(client utils.NodeAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func DrainNodeHandler(client utils.NodeToolsDeps, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		arguments, ok := request.Params.Arguments.(map[string]interface{})
 		if !ok {
@@ -144,15 +268,41 @@ func DrainNodeHandler(client utils.NodeAPI, logger *log.Logger) func(context.Con
 			deadline = int64(d)
 		}
 
+		preflight := true
+		if p, ok := arguments["preflight"].(bool); ok {
+			preflight = p
+		}
+		force, _ := arguments["force"].(bool)
+
+		var risks []DrainRisk
+		if enable && preflight {
+			var err error
+			risks, err = drainPreflight(ctx, client, nodeID)
+			if err != nil {
+				requestLogf(ctx, logger, "Error running drain preflight: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to run drain preflight", err), nil
+			}
+			if len(risks) > 0 && !force {
+				risksJSON, err := json.MarshalIndent(risks, "", "  ")
+				if err != nil {
+					return mcp.NewToolResultErrorFromErr("Failed to format drain preflight", err), nil
+				}
+				return mcp.NewToolResultError(fmt.Sprintf("Refusing to drain node %s: %d task group(s) would lose all running allocations. Move them first or retry with force=true.\n%s", nodeID, len(risks), risksJSON)), nil
+			}
+		}
+
 		result, err := client.DrainNode(ctx, nodeID, enable, deadline)
 		if err != nil {
-			logger.Printf("Error draining node: %v", err)
+			requestLogf(ctx, logger, "Error draining node: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to drain node", err), nil
 		}
 
-		response := map[string]string{
+		response := map[string]interface{}{
 			"message": result,
 		}
+		if len(risks) > 0 {
+			response["warnings"] = risks
+		}
 
 		responseJSON, err := json.MarshalIndent(response, "", "  ")
 		if err != nil {
@@ -176,16 +326,32 @@ func EligibilityNodeHandler(client utils.NodeAPI, logger *log.Logger) func(conte
 			return mcp.NewToolResultError("node_id is required"), nil
 		}
 
-		eligible, ok := arguments["eligible"].(string)
-		if !ok || eligible == "" {
+		var eligible bool
+		switch v := arguments["eligible"].(type) {
+		case bool:
+			eligible = v
+		case string:
+			// Older callers passed Nomad's enum values directly.
+			switch v {
+			case types.NodeSchedulingEligible:
+				eligible = true
+			case types.NodeSchedulingIneligible:
+				eligible = false
+			default:
+				return mcp.NewToolResultError(fmt.Sprintf("eligible must be a boolean (or %q / %q), got %q", types.NodeSchedulingEligible, types.NodeSchedulingIneligible, v)), nil
+			}
+		default:
 			return mcp.NewToolResultError("eligible is required"), nil
 		}
 
 		node, err := client.EligibilityNode(ctx, nodeID, eligible)
 		if err != nil {
-			logger.Printf("Error setting node eligibility: %v", err)
+			requestLogf(ctx, logger, "Error setting node eligibility: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to set node eligibility", err), nil
 		}
+		if node.StatusError != "" {
+			requestLogf(ctx, logger, "Eligibility of node %s was changed but the node could not be read back: %s", nodeID, node.StatusError)
+		}
 
 		nodeJSON, err := json.MarshalIndent(node, "", "  ")
 		if err != nil {
@@ -195,3 +361,107 @@ func EligibilityNodeHandler(client utils.NodeAPI, logger *log.Logger) func(conte
 		return mcp.NewToolResultText(string(nodeJSON)), nil
 	}
 }
+
+// PurgeNodeHandler returns a handler for purging a node
+func PurgeNodeHandler(client utils.NodeAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		nodeID, ok := arguments["node_id"].(string)
+		if !ok || nodeID == "" {
+			return mcp.NewToolResultError("node_id is required"), nil
+		}
+		force, _ := arguments["force"].(bool)
+
+		if !force {
+			node, err := client.GetNode(ctx, nodeID)
+			if err != nil {
+				requestLogf(ctx, logger, "Error getting node: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to get node", err), nil
+			}
+			if node.Status == "ready" {
+				return mcp.NewToolResultError(fmt.Sprintf("node %s is still ready; drain and stop it first, or pass force=true", nodeID)), nil
+			}
+		}
+
+		response, err := client.PurgeNode(ctx, nodeID)
+		if err != nil {
+			requestLogf(ctx, logger, "Error purging node: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to purge node", err), nil
+		}
+
+		responseJSON, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format result", err), nil
+		}
+
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+}
+
+// GCNodeHandler returns a handler for garbage collecting a client node
+func GCNodeHandler(client utils.NodeAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		nodeID, ok := arguments["node_id"].(string)
+		if !ok || nodeID == "" {
+			return mcp.NewToolResultError("node_id is required"), nil
+		}
+
+		if err := client.GCNode(ctx, nodeID); err != nil {
+			requestLogf(ctx, logger, "Error garbage collecting node: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to garbage collect node", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Node %s garbage collected successfully", nodeID)), nil
+	}
+}
+
+// ListDatacentersHandler returns a handler for listing datacenters derived from the node list
+func ListDatacentersHandler(client utils.NodeAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		nodes, err := client.ListNodes(ctx, "")
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing nodes: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list nodes", err), nil
+		}
+
+		byName := map[string]*types.DatacenterSummary{}
+		for _, node := range nodes {
+			dc, ok := byName[node.Datacenter]
+			if !ok {
+				dc = &types.DatacenterSummary{Datacenter: node.Datacenter}
+				byName[node.Datacenter] = dc
+			}
+			dc.Nodes++
+			switch node.Status {
+			case "ready":
+				dc.Ready++
+			case "down":
+				dc.Down++
+			}
+		}
+
+		datacenters := make([]types.DatacenterSummary, 0, len(byName))
+		for _, dc := range byName {
+			datacenters = append(datacenters, *dc)
+		}
+		sort.Slice(datacenters, func(i, j int) bool {
+			return datacenters[i].Datacenter < datacenters[j].Datacenter
+		})
+
+		datacentersJSON, err := json.MarshalIndent(datacenters, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format datacenters", err), nil
+		}
+
+		return mcp.NewToolResultText(string(datacentersJSON)), nil
+	}
+}
@@ -0,0 +1,224 @@
+// File: tools/permissions.go
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// permissionDeniedPattern finds the Nomad API path of an HTTP 403 in a tool error.
+var permissionDeniedPattern = regexp.MustCompile(`nomad API error [A-Z]+ ([^\s?:]+)[^:]*: HTTP 403`)
+
+// permissionScope is an endpoint family in a namespace ("" for cluster-scoped calls).
+type permissionScope struct {
+	Family    string
+	Namespace string
+}
+
+type permissionDenial struct {
+	Tool    string
+	Path    string
+	At      time.Time
+	Expires time.Time
+}
+
+// PermissionTracker remembers, per MCP session and token, the endpoint families Nomad
+// answered with HTTP 403, and which tools call them. Tools known to hit a denied family are
+// refused without reaching Nomad and their descriptions say so, until the record expires.
+type PermissionTracker struct {
+	ttl          time.Duration
+	mu           sync.Mutex
+	denials      map[string]map[permissionScope]permissionDenial
+	toolFamilies map[string]map[string]bool
+}
+
+// NewPermissionTracker returns a tracker remembering denials for ttl.
+func NewPermissionTracker(ttl time.Duration) *PermissionTracker {
+	return &PermissionTracker{
+		ttl:          ttl,
+		denials:      map[string]map[permissionScope]permissionDenial{},
+		toolFamilies: map[string]map[string]bool{},
+	}
+}
+
+// ForgetSession drops the denials recorded for session, for every token it used.
+func (t *PermissionTracker) ForgetSession(session string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key := range t.denials {
+		if strings.HasPrefix(key, session+"/") {
+			delete(t.denials, key)
+		}
+	}
+}
+
+// permissionKey identifies the caller: a new token in the same session starts over.
+func permissionKey(ctx context.Context) string {
+	token := sha256.Sum256([]byte(utils.TokenFromContext(ctx)))
+	return sessionID(ctx) + "/" + hex.EncodeToString(token[:])
+}
+
+// endpointFamily groups API paths the way ACL capabilities do: by their first segment,
+// singular, and by the second one under client/.
+func endpointFamily(path string) string {
+	segments := strings.Split(path, "/")
+	if segments[0] == "client" && len(segments) > 1 {
+		return "client/" + segments[1]
+	}
+	family := segments[0]
+	if strings.HasSuffix(family, "s") && !strings.HasSuffix(family, "ss") && !strings.HasSuffix(family, "us") {
+		family = strings.TrimSuffix(family, "s")
+	}
+	return family
+}
+
+// callNamespace is the namespace a call is scoped to, or "" for tools without one.
+func callNamespace(ctx context.Context, request mcp.CallToolRequest) string {
+	arguments := request.GetArguments()
+	if srv := server.ServerFromContext(ctx); srv != nil {
+		if tool := srv.GetTool(request.Params.Name); tool != nil {
+			if _, ok := tool.Tool.InputSchema.Properties["namespace"]; ok {
+				return utils.EffectiveToolNamespace(arguments)
+			}
+			return ""
+		}
+	}
+	if _, ok := arguments["namespace"]; ok {
+		return utils.EffectiveToolNamespace(arguments)
+	}
+	return ""
+}
+
+// denied returns the live denial covering a call of tool in namespace, if any.
+func (t *PermissionTracker) denied(key, tool, namespace string) (permissionScope, permissionDenial, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	for family := range t.toolFamilies[tool] {
+		scope := permissionScope{Family: family, Namespace: namespace}
+		if denial, ok := t.denials[key][scope]; ok && now.Before(denial.Expires) {
+			return scope, denial, true
+		}
+	}
+	return permissionScope{}, permissionDenial{}, false
+}
+
+// record stores a denial and reports whether it is new for this caller.
+func (t *PermissionTracker) record(key, tool, path, namespace string) (permissionScope, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	scope := permissionScope{Family: endpointFamily(path), Namespace: namespace}
+	if t.toolFamilies[tool] == nil {
+		t.toolFamilies[tool] = map[string]bool{}
+	}
+	t.toolFamilies[tool][scope.Family] = true
+	if t.denials[key] == nil {
+		t.denials[key] = map[permissionScope]permissionDenial{}
+	}
+	previous, seen := t.denials[key][scope]
+	t.denials[key][scope] = permissionDenial{Tool: tool, Path: path, At: now, Expires: now.Add(t.ttl)}
+	return scope, !seen || now.After(previous.Expires)
+}
+
+// deniedFamilies returns the denied families each tool is known to call, for one caller.
+func (t *PermissionTracker) deniedFamilies(key string) map[string][]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	byTool := map[string][]string{}
+	for scope, denial := range t.denials[key] {
+		if now.After(denial.Expires) {
+			continue
+		}
+		for tool, families := range t.toolFamilies {
+			if families[scope.Family] {
+				byTool[tool] = append(byTool[tool], describePermissionScope(scope))
+			}
+		}
+	}
+	for tool := range byTool {
+		slices.Sort(byTool[tool])
+		byTool[tool] = slices.Compact(byTool[tool])
+	}
+	return byTool
+}
+
+func describePermissionScope(scope permissionScope) string {
+	if scope.Namespace == "" {
+		return scope.Family
+	}
+	return fmt.Sprintf("%s (namespace %s)", scope.Family, scope.Namespace)
+}
+
+// PermissionProbeMiddleware returns a tool middleware that records HTTP 403 answers in
+// tracker and refuses later calls of tools known to need a denied endpoint family in the
+// same namespace, so a session does not repeat calls its token cannot make. The first
+// denial of a family tells the client that the tool list changed, so it picks up the
+// annotated descriptions. A nil tracker disables probing.
+func PermissionProbeMiddleware(tracker *PermissionTracker) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if tracker == nil {
+				return next(ctx, request)
+			}
+			key := permissionKey(ctx)
+			namespace := callNamespace(ctx, request)
+
+			if scope, denial, ok := tracker.denied(key, request.Params.Name, namespace); ok {
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"Skipped: the current token lacks the capability for %s endpoints; Nomad denied %s from %s at %s. Use a token whose policy grants it (this note expires at %s)",
+					describePermissionScope(scope), denial.Path, denial.Tool, denial.At.UTC().Format(time.RFC3339), denial.Expires.UTC().Format(time.RFC3339))), nil
+			}
+
+			result, err := next(ctx, request)
+			if err != nil || result == nil || !result.IsError {
+				return result, err
+			}
+			match := permissionDeniedPattern.FindStringSubmatch(errorResultText(result))
+			if match == nil {
+				return result, nil
+			}
+
+			scope, first := tracker.record(key, request.Params.Name, match[1], namespace)
+			result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf(
+				"The current token lacks the capability for %s endpoints; calls that need it are skipped for the next %s", describePermissionScope(scope), tracker.ttl)))
+			if first {
+				if srv := server.ServerFromContext(ctx); srv != nil {
+					_ = srv.SendNotificationToClient(ctx, "notifications/tools/list_changed", nil)
+				}
+			}
+			return result, nil
+		}
+	}
+}
+
+// ToolFilter returns a tools/list filter that notes, in the description of every tool known
+// to call a denied endpoint family, that the caller's token lacks the capability.
+func (t *PermissionTracker) ToolFilter() server.ToolFilterFunc {
+	return func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+		denied := t.deniedFamilies(permissionKey(ctx))
+		if len(denied) == 0 {
+			return tools
+		}
+		annotated := make([]mcp.Tool, len(tools))
+		for i, tool := range tools {
+			if families, ok := denied[tool.Name]; ok {
+				tool.Description = fmt.Sprintf("%s. Note: the current token was denied access to %s endpoints", strings.TrimSuffix(tool.Description, "."), strings.Join(families, ", "))
+			}
+			annotated[i] = tool
+		}
+		return annotated
+	}
+}
@@ -0,0 +1,479 @@
+// File: tools/placement.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// NodeMatch is the client-side placement verdict for one node
+type NodeMatch struct {
+	NodeID        string   `json:"NodeID"`
+	Name          string   `json:"Name"`
+	Datacenter    string   `json:"Datacenter"`
+	NodePool      string   `json:"NodePool,omitempty"`
+	Groups        []string `json:"Groups,omitempty"`
+	AffinityScore float64  `json:"AffinityScore,omitempty"`
+	FilteredBy    []string `json:"FilteredBy,omitempty"`
+}
+
+// JobNodeMatch is the match_nodes_for_job response
+type JobNodeMatch struct {
+	JobID              string      `json:"JobID"`
+	Eligible           []NodeMatch `json:"Eligible"`
+	Ineligible         []NodeMatch `json:"Ineligible"`
+	IgnoredConstraints []string    `json:"IgnoredConstraints,omitempty"`
+}
+
+// RegisterPlacementTools registers tools that evaluate job placement against nodes
+func RegisterPlacementTools(s *server.MCPServer, nomadClient utils.PlacementAPI, logger *log.Logger) {
+	// Match nodes for job tool
+	matchNodesForJobTool := mcp.NewTool("match_nodes_for_job",
+		mcp.WithDescription("Evaluate a job's datacenters, node pool, constraints and affinities against current node attributes and list eligible nodes and the constraint that filtered each ineligible node"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Description("The ID of a registered job to evaluate"),
+		),
+		mcp.WithString("job_spec",
+			mcp.Description("A job specification in HCL or JSON format to evaluate instead of a registered job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+	)
+	s.AddTool(matchNodesForJobTool, MatchNodesForJobHandler(nomadClient, logger))
+
+	// Check system job coverage tool
+	checkSystemJobCoverageTool := mcp.NewTool("check_system_job_coverage",
+		mcp.WithDescription("Verify each running system job has a running allocation on every node it is eligible for (datacenters, node pool and constraints) and list the nodes missing one, with counts per datacenter and node pool"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Description("Only check this system job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace to check, or * for all namespaces (default: *)"),
+		),
+	)
+	s.AddTool(checkSystemJobCoverageTool, CheckSystemJobCoverageHandler(nomadClient, logger))
+
+	// List static ports tool
+	listStaticPortsTool := mcp.NewTool("list_static_ports",
+		mcp.WithDescription("Inventory static port reservations across jobs and nodes: flag task groups reserving the same port, ports blocked by a node's reserved_ports, and nodes close to exhausting their dynamic port range"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace to scan, or * for all namespaces (default: *)"),
+		),
+		mcp.WithString("node_id",
+			mcp.Description("Only report this node (ID or prefix)"),
+		),
+		mcp.WithNumber("exhaustion_threshold",
+			mcp.Description("Flag nodes using at least this fraction of their dynamic port range (default: 0.8)"),
+		),
+	)
+	s.AddTool(listStaticPortsTool, ListStaticPortsHandler(nomadClient, logger))
+
+	// Analyze job spread tool
+	analyzeJobSpreadTool := mcp.NewTool("analyze_job_spread",
+		mcp.WithDescription("Show how a job's running allocations are distributed across datacenters, node classes and nodes, compare it with the job's spread and affinity blocks, and flag skew or allocations concentrated on one node"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job to analyze"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithNumber("skew_threshold",
+			mcp.Description("Flag spreads whose actual share deviates from the target by more than this many percentage points (default: 20)"),
+		),
+	)
+	s.AddTool(analyzeJobSpreadTool, AnalyzeJobSpreadHandler(nomadClient, logger))
+}
+
+// MatchNodesForJobHandler returns a handler for matching a job against cluster nodes
+func MatchNodesForJobHandler(client utils.PlacementAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		job, err := jobFromArguments(ctx, client, arguments)
+		if err != nil {
+			requestLogf(ctx, logger, "Error loading job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to load job", err), nil
+		}
+
+		nodes, err := fetchNodeDetails(ctx, client)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing nodes: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list nodes", err), nil
+		}
+
+		result := matchJobNodes(job, nodes)
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format node matches", err), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// fetchNodeDetails lists every node and concurrently loads its scheduling details.
+func fetchNodeDetails(ctx context.Context, client utils.NodeAPI) ([]types.NodeDetail, error) {
+	summaries, err := client.ListNodes(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]types.NodeDetail, len(summaries))
+	errs := make([]error, len(summaries))
+	forEachConcurrently(len(summaries), func(i int) {
+		details[i], errs[i] = client.GetNodeDetail(ctx, summaries[i].ID)
+	})
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("error getting node %s: %v", summaries[i].ID, err)
+		}
+	}
+	return details, nil
+}
+
+// fetchNodeAllocations lists the allocations of each node concurrently. Results and errors
+// are indexed like nodes.
+func fetchNodeAllocations(ctx context.Context, client utils.NodeAPI, nodes []types.NodeDetail) ([][]types.Allocation, []error) {
+	allocs := make([][]types.Allocation, len(nodes))
+	errs := make([]error, len(nodes))
+	forEachConcurrently(len(nodes), func(i int) {
+		allocs[i], errs[i] = client.ListNodeAllocations(ctx, nodes[i].ID)
+	})
+	return allocs, errs
+}
+
+// matchJobNodes applies the scheduler's feasibility checks to each node. A node is eligible
+// when it passes the job-level checks and every constraint of at least one task group.
+func matchJobNodes(job types.Job, nodes []types.NodeDetail) JobNodeMatch {
+	result := JobNodeMatch{JobID: job.ID, Eligible: []NodeMatch{}, Ineligible: []NodeMatch{}}
+
+	ignored := map[string]bool{}
+	collect := func(constraints []types.Constraint) []types.Constraint {
+		var kept []types.Constraint
+		for _, c := range constraints {
+			if c.Operand == "distinct_hosts" || c.Operand == "distinct_property" {
+				ignored[describeConstraint(c)] = true
+				continue
+			}
+			kept = append(kept, c)
+		}
+		return kept
+	}
+
+	jobConstraints := collect(job.Constraints)
+	groupConstraints := make([][]types.Constraint, len(job.TaskGroups))
+	for i, tg := range job.TaskGroups {
+		constraints := append([]types.Constraint(nil), tg.Constraints...)
+		for _, task := range tg.Tasks {
+			constraints = append(constraints, task.Constraints...)
+		}
+		groupConstraints[i] = collect(constraints)
+	}
+
+	for _, node := range nodes {
+		match := NodeMatch{NodeID: node.ID, Name: node.Name, Datacenter: node.Datacenter, NodePool: node.NodePool}
+
+		if reason := nodeJobFilter(job, node); reason != "" {
+			match.FilteredBy = []string{reason}
+			result.Ineligible = append(result.Ineligible, match)
+			continue
+		}
+		if c, ok := firstFailingConstraint(jobConstraints, node); !ok {
+			match.FilteredBy = []string{"constraint " + describeConstraint(c)}
+			result.Ineligible = append(result.Ineligible, match)
+			continue
+		}
+
+		affinities := append([]types.Affinity(nil), job.Affinities...)
+		for i, tg := range job.TaskGroups {
+			if c, ok := firstFailingConstraint(groupConstraints[i], node); !ok {
+				match.FilteredBy = append(match.FilteredBy, fmt.Sprintf("group %s: constraint %s", tg.Name, describeConstraint(c)))
+				continue
+			}
+			match.Groups = append(match.Groups, tg.Name)
+			affinities = append(affinities, tg.Affinities...)
+			for _, task := range tg.Tasks {
+				affinities = append(affinities, task.Affinities...)
+			}
+		}
+
+		if len(match.Groups) == 0 {
+			result.Ineligible = append(result.Ineligible, match)
+			continue
+		}
+		match.AffinityScore = affinityScore(affinities, node)
+		result.Eligible = append(result.Eligible, match)
+	}
+
+	sort.SliceStable(result.Eligible, func(i, j int) bool {
+		return result.Eligible[i].AffinityScore > result.Eligible[j].AffinityScore
+	})
+	for c := range ignored {
+		result.IgnoredConstraints = append(result.IgnoredConstraints, c)
+	}
+	sort.Strings(result.IgnoredConstraints)
+
+	return result
+}
+
+// nodeJobFilter reports why a node cannot run any part of the job before constraints are
+// considered: readiness, eligibility, datacenter and node pool.
+func nodeJobFilter(job types.Job, node types.NodeDetail) string {
+	if node.Status != "ready" {
+		return fmt.Sprintf("node status is %s", node.Status)
+	}
+	if node.SchedulingEligibility != "" && node.SchedulingEligibility != types.NodeSchedulingEligible {
+		return "node is ineligible for scheduling"
+	}
+
+	datacenters := job.Datacenters
+	if len(datacenters) == 0 {
+		datacenters = []string{"*"}
+	}
+	inDatacenter := false
+	for _, pattern := range datacenters {
+		if ok, _ := path.Match(pattern, node.Datacenter); ok {
+			inDatacenter = true
+			break
+		}
+	}
+	if !inDatacenter {
+		return fmt.Sprintf("datacenter %s not in %s", node.Datacenter, strings.Join(datacenters, ", "))
+	}
+
+	pool := job.NodePool
+	if pool == "" {
+		pool = "default"
+	}
+	if pool != "all" && node.NodePool != "" && node.NodePool != pool {
+		return fmt.Sprintf("node pool %s does not match %s", node.NodePool, pool)
+	}
+	return ""
+}
+
+func firstFailingConstraint(constraints []types.Constraint, node types.NodeDetail) (types.Constraint, bool) {
+	for _, c := range constraints {
+		if !constraintMatches(c, node) {
+			return c, false
+		}
+	}
+	return types.Constraint{}, true
+}
+
+// affinityScore is the weighted share of matching affinities, from -1 to 1.
+func affinityScore(affinities []types.Affinity, node types.NodeDetail) float64 {
+	total, matched := 0, 0
+	for _, a := range affinities {
+		weight := a.Weight
+		if weight < 0 {
+			total -= weight
+		} else {
+			total += weight
+		}
+		if constraintMatches(types.Constraint{LTarget: a.LTarget, RTarget: a.RTarget, Operand: a.Operand}, node) {
+			matched += weight
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(matched) / float64(total)
+}
+
+func describeConstraint(c types.Constraint) string {
+	operand := c.Operand
+	if operand == "" {
+		operand = "="
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", c.LTarget, operand, c.RTarget))
+}
+
+// resolveNodeTarget interpolates a constraint target against a node. Literals resolve to
+// themselves; unknown or missing node values report false.
+func resolveNodeTarget(target string, node types.NodeDetail) (string, bool) {
+	if !strings.HasPrefix(target, "${") || !strings.HasSuffix(target, "}") {
+		return target, true
+	}
+	key := target[2 : len(target)-1]
+
+	switch {
+	case strings.HasPrefix(key, "attr."):
+		v, ok := node.Attributes[strings.TrimPrefix(key, "attr.")]
+		return v, ok
+	case strings.HasPrefix(key, "meta."):
+		v, ok := node.Meta[strings.TrimPrefix(key, "meta.")]
+		return v, ok
+	}
+
+	switch key {
+	case "node.unique.id":
+		return node.ID, true
+	case "node.unique.name":
+		return node.Name, true
+	case "node.datacenter":
+		return node.Datacenter, true
+	case "node.class":
+		return node.NodeClass, node.NodeClass != ""
+	case "node.pool":
+		return node.NodePool, node.NodePool != ""
+	}
+	return "", false
+}
+
+// constraintMatches evaluates one constraint operand the way Nomad's feasibility checker does.
+func constraintMatches(c types.Constraint, node types.NodeDetail) bool {
+	lVal, lOK := resolveNodeTarget(c.LTarget, node)
+	rVal, rOK := resolveNodeTarget(c.RTarget, node)
+
+	switch c.Operand {
+	case "is_set":
+		return lOK
+	case "is_not_set":
+		return !lOK
+	case "!=", "not":
+		return !(lOK && rOK && lVal == rVal)
+	}
+
+	if !lOK || !rOK {
+		return false
+	}
+
+	switch c.Operand {
+	case "", "=", "==", "is":
+		return lVal == rVal
+	case "<", "<=", ">", ">=":
+		return compareOrdered(lVal, rVal, c.Operand)
+	case "regexp":
+		re, err := regexp.Compile(rVal)
+		return err == nil && re.MatchString(lVal)
+	case "version", "semver":
+		return versionConstraintMatches(lVal, rVal)
+	case "set_contains", "set_contains_all":
+		have := splitSet(lVal)
+		for want := range splitSet(rVal) {
+			if !have[want] {
+				return false
+			}
+		}
+		return true
+	case "set_contains_any":
+		have := splitSet(lVal)
+		for want := range splitSet(rVal) {
+			if have[want] {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func splitSet(value string) map[string]bool {
+	set := map[string]bool{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			set[item] = true
+		}
+	}
+	return set
+}
+
+// compareOrdered compares numerically when both sides are numbers, lexically otherwise.
+func compareOrdered(lVal, rVal, operand string) bool {
+	cmp := strings.Compare(lVal, rVal)
+	lNum, lErr := strconv.ParseFloat(lVal, 64)
+	rNum, rErr := strconv.ParseFloat(rVal, 64)
+	if lErr == nil && rErr == nil {
+		switch {
+		case lNum < rNum:
+			cmp = -1
+		case lNum > rNum:
+			cmp = 1
+		default:
+			cmp = 0
+		}
+	}
+
+	switch operand {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+// versionConstraintMatches checks a version against comma-separated requirements such as
+// ">= 1.2.0, < 2.0" or "~> 1.4".
+func versionConstraintMatches(version, requirements string) bool {
+	have, ok := utils.ParseVersion(version)
+	if !ok {
+		return false
+	}
+	for _, req := range strings.Split(requirements, ",") {
+		req = strings.TrimSpace(req)
+		operator := "="
+		for _, op := range []string{">=", "<=", "!=", "~>", "=", ">", "<"} {
+			if strings.HasPrefix(req, op) {
+				operator = op
+				req = strings.TrimSpace(strings.TrimPrefix(req, op))
+				break
+			}
+		}
+		want, ok := utils.ParseVersion(req)
+		if !ok {
+			return false
+		}
+
+		cmp := utils.CompareVersions(have, want)
+		switch operator {
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		case "~>":
+			// Pessimistic: at least want, below the next release of the second-to-last segment.
+			upper := append([]int(nil), want[:max(len(want)-1, 1)]...)
+			upper[len(upper)-1]++
+			ok = cmp >= 0 && (len(want) == 1 || utils.CompareVersions(have, upper) < 0)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,277 @@
+// File: tools/plan.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// FailedPlacement is a task group a plan cannot fully place, with why the nodes were rejected
+type FailedPlacement struct {
+	TaskGroup      string         `json:"TaskGroup"`
+	Unplaced       int            `json:"Unplaced"`
+	NodesEvaluated int            `json:"NodesEvaluated"`
+	NodesFiltered  int            `json:"NodesFiltered"`
+	NodesExhausted int            `json:"NodesExhausted"`
+	Filtered       map[string]int `json:"Filtered,omitempty"`
+	Exhausted      map[string]int `json:"Exhausted,omitempty"`
+	QuotaExhausted []string       `json:"QuotaExhausted,omitempty"`
+}
+
+// JobPlanReport is the result of plan_job
+type JobPlanReport struct {
+	JobID              string                          `json:"JobID"`
+	Namespace          string                          `json:"Namespace"`
+	JobModifyIndex     int                             `json:"JobModifyIndex"`
+	Changed            bool                            `json:"Changed"`
+	Changes            []string                        `json:"Changes"`
+	Updates            map[string]types.DesiredUpdates `json:"Updates,omitempty"`
+	FailedPlacements   []FailedPlacement               `json:"FailedPlacements,omitempty"`
+	Warnings           string                          `json:"Warnings,omitempty"`
+	NextPeriodicLaunch string                          `json:"NextPeriodicLaunch,omitempty"`
+	Summary            []string                        `json:"Summary"`
+}
+
+// PlanJobHandler returns a handler for planning a job specification
+func PlanJobHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobSpec, ok := arguments["job_spec"].(string)
+		if !ok || jobSpec == "" {
+			return mcp.NewToolResultError("job_spec is required"), nil
+		}
+
+		job, err := client.ParseJobSpec(ctx, jobSpec)
+		if err != nil {
+			requestLogf(ctx, logger, "Error parsing job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to parse job", err), nil
+		}
+		// Only an explicit argument overrides the spec, as with run_job.
+		namespace := ""
+		if ns, ok := arguments["namespace"].(string); ok {
+			namespace = strings.TrimSpace(ns)
+		}
+		if namespace != "" {
+			job["Namespace"] = namespace
+		} else {
+			namespace, _ = job["Namespace"].(string)
+		}
+		if namespace == "" {
+			namespace = utils.NomadDefaultNamespace
+		}
+
+		plan, err := client.PlanJob(ctx, job, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error planning job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to plan job", err), nil
+		}
+
+		report := JobPlanReport{
+			Namespace:        namespace,
+			JobModifyIndex:   plan.JobModifyIndex,
+			Changed:          jobPlanChanges(plan.Diff),
+			Changes:          formatJobDiff(plan.Diff),
+			FailedPlacements: failedPlacements(plan.FailedTGAllocs),
+			Warnings:         plan.Warnings,
+		}
+		report.JobID, _ = job["ID"].(string)
+		if plan.Annotations != nil {
+			report.Updates = plan.Annotations.DesiredTGUpdates
+		}
+		if plan.NextPeriodicLaunch != "" && !strings.HasPrefix(plan.NextPeriodicLaunch, "0001-01-01") {
+			report.NextPeriodicLaunch = plan.NextPeriodicLaunch
+		}
+		report.Summary = jobPlanSummary(report)
+
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format plan", err), nil
+		}
+
+		return withNomadWarnings(mcp.NewToolResultText(string(reportJSON)), report.Warnings), nil
+	}
+}
+
+// failedPlacements decodes the allocation metrics of the task groups a plan cannot place.
+func failedPlacements(failed map[string]interface{}) []FailedPlacement {
+	placements := make([]FailedPlacement, 0, len(failed))
+	for group, raw := range failed {
+		var metric types.AllocMetric
+		if data, err := json.Marshal(raw); err == nil {
+			_ = json.Unmarshal(data, &metric)
+		}
+		placement := FailedPlacement{
+			TaskGroup:      group,
+			Unplaced:       metric.CoalescedFailures + 1,
+			NodesEvaluated: metric.NodesEvaluated,
+			NodesFiltered:  metric.NodesFiltered,
+			NodesExhausted: metric.NodesExhausted,
+			Exhausted:      metric.DimensionExhausted,
+			QuotaExhausted: metric.QuotaExhausted,
+		}
+		for _, counts := range []map[string]int{metric.ConstraintFiltered, metric.ClassFiltered} {
+			for reason, n := range counts {
+				if placement.Filtered == nil {
+					placement.Filtered = map[string]int{}
+				}
+				placement.Filtered[reason] += n
+			}
+		}
+		placements = append(placements, placement)
+	}
+	sort.Slice(placements, func(i, j int) bool { return placements[i].TaskGroup < placements[j].TaskGroup })
+	return placements
+}
+
+// jobPlanSummary describes the outcome of a plan like the end of nomad job plan.
+func jobPlanSummary(report JobPlanReport) []string {
+	var lines []string
+	if report.Changed {
+		lines = append(lines, fmt.Sprintf("Job %s would change; see Changes.", report.JobID))
+	} else {
+		lines = append(lines, fmt.Sprintf("Job %s has no changes besides index and version bookkeeping.", report.JobID))
+	}
+
+	if len(report.FailedPlacements) == 0 {
+		lines = append(lines, "Scheduler dry-run: all tasks successfully allocated.")
+	} else {
+		lines = append(lines, "Scheduler dry-run: WARNING: failed to place all allocations.")
+		for _, failed := range report.FailedPlacements {
+			line := fmt.Sprintf("Task group %q: %d allocations unplaced; %d of %d evaluated nodes filtered%s, %d exhausted%s.",
+				failed.TaskGroup, failed.Unplaced, failed.NodesFiltered, failed.NodesEvaluated, countsSuffix("by", failed.Filtered), failed.NodesExhausted, countsSuffix("on", failed.Exhausted))
+			if len(failed.QuotaExhausted) > 0 {
+				line += fmt.Sprintf(" Quota limits reached: %s.", strings.Join(failed.QuotaExhausted, ", "))
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	if report.NextPeriodicLaunch != "" {
+		lines = append(lines, fmt.Sprintf("The next periodic launch would be at %s.", report.NextPeriodicLaunch))
+	}
+	if report.Warnings != "" {
+		lines = append(lines, "Warnings: "+strings.TrimSpace(report.Warnings))
+	}
+	lines = append(lines, fmt.Sprintf("Submit with run_job and job_modify_index %d to register the job only if it has not changed since this plan.", report.JobModifyIndex))
+	return lines
+}
+
+// formatJobDiff renders a plan diff the way nomad job plan prints it: one line per changed
+// job, group, task, object and field, marked + (added), - (deleted) or +/- (edited) and
+// indented by nesting. Unchanged entries and bookkeeping fields are left out.
+func formatJobDiff(diff *types.JobDiff) []string {
+	lines := []string{}
+	if diff == nil || !jobPlanChanges(diff) {
+		return lines
+	}
+	lines = append(lines, fmt.Sprintf("%sJob: %q", diffMarker(diff.Type), diff.ID))
+	lines = appendFieldDiffs(lines, diff.Fields, 1)
+	lines = appendObjectDiffs(lines, diff.Objects, 1)
+	for _, group := range diff.TaskGroups {
+		if group.Type == "None" && len(group.Updates) == 0 {
+			continue
+		}
+		line := fmt.Sprintf("%s%sTask Group: %q", diffIndent(1), diffMarker(group.Type), group.Name)
+		if updates := formatGroupUpdates(group.Updates); updates != "" {
+			line += " (" + updates + ")"
+		}
+		lines = append(lines, line+diffAnnotations(group.Annotations))
+		lines = appendFieldDiffs(lines, group.Fields, 2)
+		lines = appendObjectDiffs(lines, group.Objects, 2)
+		for _, task := range group.Tasks {
+			if task.Type == "None" {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("%s%sTask: %q%s", diffIndent(2), diffMarker(task.Type), task.Name, diffAnnotations(task.Annotations)))
+			lines = appendFieldDiffs(lines, task.Fields, 3)
+			lines = appendObjectDiffs(lines, task.Objects, 3)
+		}
+	}
+	return lines
+}
+
+func appendFieldDiffs(lines []string, fields []types.FieldDiff, depth int) []string {
+	for _, field := range fields {
+		if jobPlanBookkeepingFields[field.Name] {
+			continue
+		}
+		var line string
+		switch field.Type {
+		case "Added":
+			line = fmt.Sprintf("%s: %q", field.Name, field.New)
+		case "Deleted":
+			line = fmt.Sprintf("%s: %q", field.Name, field.Old)
+		case "Edited":
+			line = fmt.Sprintf("%s: %q => %q", field.Name, field.Old, field.New)
+		default:
+			continue
+		}
+		lines = append(lines, diffIndent(depth)+diffMarker(field.Type)+line+diffAnnotations(field.Annotations))
+	}
+	return lines
+}
+
+func appendObjectDiffs(lines []string, objects []types.ObjectDiff, depth int) []string {
+	for _, object := range objects {
+		if object.Type == "None" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s%s%s {%s", diffIndent(depth), diffMarker(object.Type), object.Name, diffAnnotations(object.Annotations)))
+		lines = appendFieldDiffs(lines, object.Fields, depth+1)
+		lines = appendObjectDiffs(lines, object.Objects, depth+1)
+		lines = append(lines, diffIndent(depth)+"}")
+	}
+	return lines
+}
+
+// formatGroupUpdates lists the scheduler's planned updates of a task group, e.g.
+// "1 create, 2 in-place update".
+func formatGroupUpdates(updates map[string]int) string {
+	kinds := make([]string, 0, len(updates))
+	for kind, n := range updates {
+		if n > 0 {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		parts = append(parts, fmt.Sprintf("%d %s", updates[kind], kind))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func diffMarker(diffType string) string {
+	switch diffType {
+	case "Added":
+		return "+ "
+	case "Deleted":
+		return "- "
+	case "Edited":
+		return "+/- "
+	}
+	return ""
+}
+
+func diffIndent(depth int) string {
+	return strings.Repeat("  ", depth)
+}
+
+func diffAnnotations(annotations []string) string {
+	if len(annotations) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(annotations, ", ") + ")"
+}
@@ -0,0 +1,305 @@
+// File: tools/ports.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Nomad's dynamic port range when a client does not configure one.
+const (
+	defaultMinDynamicPort = 20000
+	defaultMaxDynamicPort = 32000
+)
+
+// StaticPortReservation is a static port a job's task group asks for on every node it runs on
+type StaticPortReservation struct {
+	Port      int    `json:"Port"`
+	Namespace string `json:"Namespace"`
+	JobID     string `json:"JobID"`
+	Group     string `json:"Group"`
+	Label     string `json:"Label"`
+}
+
+// PortConflict is a static port reserved by more than one task group, which therefore can
+// never share a node
+type PortConflict struct {
+	Port         int                     `json:"Port"`
+	Reservations []StaticPortReservation `json:"Reservations"`
+}
+
+// NodePortUsage is the port usage of one client node
+type NodePortUsage struct {
+	NodeID            string                  `json:"NodeID"`
+	Name              string                  `json:"Name"`
+	Datacenter        string                  `json:"Datacenter"`
+	StaticPorts       []StaticPortReservation `json:"StaticPorts"`
+	ReservedHostPorts string                  `json:"ReservedHostPorts,omitempty"`
+	DynamicPortRange  string                  `json:"DynamicPortRange"`
+	DynamicPortsUsed  int                     `json:"DynamicPortsUsed"`
+	DynamicPortsFree  int                     `json:"DynamicPortsFree"`
+	Issues            []string                `json:"Issues,omitempty"`
+}
+
+// StaticPortInventory is the list_static_ports response
+type StaticPortInventory struct {
+	Reservations []StaticPortReservation `json:"Reservations"`
+	Conflicts    []PortConflict          `json:"Conflicts"`
+	Nodes        []NodePortUsage         `json:"Nodes"`
+	Errors       []string                `json:"Errors,omitempty"`
+}
+
+// ListStaticPortsHandler returns a handler that inventories static port reservations
+func ListStaticPortsHandler(client utils.PlacementAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		namespace := "*"
+		if ns, ok := arguments["namespace"].(string); ok && ns != "" {
+			namespace = ns
+		}
+		nodeID, _ := arguments["node_id"].(string)
+
+		threshold := 0.8
+		if t, ok := arguments["exhaustion_threshold"].(float64); ok {
+			if t <= 0 || t > 1 {
+				return mcp.NewToolResultError("exhaustion_threshold must be between 0 and 1"), nil
+			}
+			threshold = t
+		}
+
+		stubs, err := client.ListJobs(ctx, namespace, "")
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing jobs: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list jobs", err), nil
+		}
+
+		nodes, err := fetchNodeDetails(ctx, client)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing nodes: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list nodes", err), nil
+		}
+		if nodeID != "" {
+			var selected []types.NodeDetail
+			for _, node := range nodes {
+				if node.ID == nodeID || strings.HasPrefix(node.ID, nodeID) {
+					selected = append(selected, node)
+				}
+			}
+			if len(selected) == 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("node %s not found", nodeID)), nil
+			}
+			nodes = selected
+		}
+
+		inventory := StaticPortInventory{Reservations: []StaticPortReservation{}, Conflicts: []PortConflict{}, Nodes: []NodePortUsage{}}
+
+		jobs := fetchLiveJobs(ctx, client, stubs, namespace, &inventory.Errors, logger)
+		byGroup := map[string][]StaticPortReservation{}
+		byPort := map[int][]StaticPortReservation{}
+		for _, job := range jobs {
+			for _, reservation := range jobStaticPorts(job) {
+				inventory.Reservations = append(inventory.Reservations, reservation)
+				key := reservation.Namespace + "/" + reservation.JobID + "/" + reservation.Group
+				byGroup[key] = append(byGroup[key], reservation)
+				byPort[reservation.Port] = append(byPort[reservation.Port], reservation)
+			}
+		}
+		sort.Slice(inventory.Reservations, func(i, j int) bool { return inventory.Reservations[i].Port < inventory.Reservations[j].Port })
+
+		for port, reservations := range byPort {
+			groups := map[string]bool{}
+			for _, r := range reservations {
+				groups[r.Namespace+"/"+r.JobID+"/"+r.Group] = true
+			}
+			if len(groups) > 1 {
+				inventory.Conflicts = append(inventory.Conflicts, PortConflict{Port: port, Reservations: reservations})
+			}
+		}
+		sort.Slice(inventory.Conflicts, func(i, j int) bool { return inventory.Conflicts[i].Port < inventory.Conflicts[j].Port })
+
+		allocs, errs := fetchNodeAllocations(ctx, client, nodes)
+
+		for i, node := range nodes {
+			if errs[i] != nil {
+				requestLogf(ctx, logger, "Error listing allocations for node %s: %v", node.ID, errs[i])
+				inventory.Errors = append(inventory.Errors, fmt.Sprintf("node %s: %v", node.ID, errs[i]))
+				continue
+			}
+			usage := nodePortUsage(node, allocs[i], jobs, byGroup, threshold)
+			if len(usage.StaticPorts) > 0 || len(usage.Issues) > 0 || nodeID != "" {
+				inventory.Nodes = append(inventory.Nodes, usage)
+			}
+		}
+		sort.Slice(inventory.Nodes, func(i, j int) bool { return inventory.Nodes[i].Name < inventory.Nodes[j].Name })
+
+		inventoryJSON, err := json.MarshalIndent(inventory, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format port inventory", err), nil
+		}
+
+		return mcp.NewToolResultText(string(inventoryJSON)), nil
+	}
+}
+
+// fetchLiveJobs loads every job that is not dead. Failures are recorded and skipped.
+func fetchLiveJobs(ctx context.Context, client utils.JobAPI, stubs []types.JobSummary, namespace string, failures *[]string, logger *log.Logger) []types.Job {
+	var live []types.JobSummary
+	for _, stub := range stubs {
+		if stub.Status != "dead" {
+			live = append(live, stub)
+		}
+	}
+
+	jobs := make([]types.Job, len(live))
+	errs := make([]error, len(live))
+	forEachConcurrently(len(live), func(i int) {
+		ns := live[i].Namespace
+		if ns == "" {
+			ns = namespace
+		}
+		jobs[i], errs[i] = client.GetJob(ctx, live[i].ID, ns)
+		if jobs[i].Namespace == "" {
+			jobs[i].Namespace = ns
+		}
+	})
+
+	loaded := make([]types.Job, 0, len(jobs))
+	for i, job := range jobs {
+		if errs[i] != nil {
+			requestLogf(ctx, logger, "Error getting job %s: %v", live[i].ID, errs[i])
+			*failures = append(*failures, fmt.Sprintf("job %s: %v", live[i].ID, errs[i]))
+			continue
+		}
+		loaded = append(loaded, job)
+	}
+	return loaded
+}
+
+// jobStaticPorts lists the static ports of a job's group and task networks.
+func jobStaticPorts(job types.Job) []StaticPortReservation {
+	var reservations []StaticPortReservation
+	add := func(group string, ports []types.Port) {
+		for _, port := range ports {
+			if port.Value > 0 {
+				reservations = append(reservations, StaticPortReservation{Port: port.Value, Namespace: job.Namespace, JobID: job.ID, Group: group, Label: port.Label})
+			}
+		}
+	}
+	for _, tg := range job.TaskGroups {
+		for _, network := range tg.Networks {
+			add(tg.Name, network.ReservedPorts)
+		}
+		for _, task := range tg.Tasks {
+			for _, network := range task.Resources.Networks {
+				add(tg.Name, network.ReservedPorts)
+			}
+		}
+	}
+	return reservations
+}
+
+// nodePortUsage attributes static ports to a node through its live allocations, counts the
+// dynamic ports in use, and flags reserved host ports that block jobs able to target the node.
+func nodePortUsage(node types.NodeDetail, allocs []types.Allocation, jobs []types.Job, byGroup map[string][]StaticPortReservation, threshold float64) NodePortUsage {
+	minPort, maxPort := defaultMinDynamicPort, defaultMaxDynamicPort
+	if node.NodeResources != nil && node.NodeResources.MinDynamicPort > 0 && node.NodeResources.MaxDynamicPort >= node.NodeResources.MinDynamicPort {
+		minPort, maxPort = node.NodeResources.MinDynamicPort, node.NodeResources.MaxDynamicPort
+	}
+	usage := NodePortUsage{
+		NodeID:           node.ID,
+		Name:             node.Name,
+		Datacenter:       node.Datacenter,
+		StaticPorts:      []StaticPortReservation{},
+		DynamicPortRange: fmt.Sprintf("%d-%d", minPort, maxPort),
+	}
+
+	holders := map[int]string{}
+	for _, alloc := range allocs {
+		if !isLiveAllocation(alloc) {
+			continue
+		}
+		static := map[int]bool{}
+		for _, reservation := range byGroup[alloc.Namespace+"/"+alloc.JobID+"/"+alloc.TaskGroup] {
+			static[reservation.Port] = true
+			if holder, ok := holders[reservation.Port]; ok {
+				usage.Issues = append(usage.Issues, fmt.Sprintf("static port %d is reserved by both %s and %s/%s on this node", reservation.Port, holder, reservation.JobID, reservation.Group))
+			}
+			holders[reservation.Port] = reservation.JobID + "/" + reservation.Group
+			usage.StaticPorts = append(usage.StaticPorts, reservation)
+		}
+		if alloc.AllocatedResources == nil {
+			continue
+		}
+		for _, port := range alloc.AllocatedResources.Shared.Ports {
+			if !static[port.Value] && port.Value >= minPort && port.Value <= maxPort {
+				usage.DynamicPortsUsed++
+			}
+		}
+	}
+	sort.Slice(usage.StaticPorts, func(i, j int) bool { return usage.StaticPorts[i].Port < usage.StaticPorts[j].Port })
+
+	size := maxPort - minPort + 1
+	usage.DynamicPortsFree = size - usage.DynamicPortsUsed
+	if float64(usage.DynamicPortsUsed) >= threshold*float64(size) {
+		usage.Issues = append(usage.Issues, fmt.Sprintf("%d of %d dynamic ports are in use", usage.DynamicPortsUsed, size))
+	}
+
+	if node.ReservedResources != nil && node.ReservedResources.Networks.ReservedHostPorts != "" {
+		usage.ReservedHostPorts = node.ReservedResources.Networks.ReservedHostPorts
+		reserved := parsePortRanges(usage.ReservedHostPorts)
+		for _, job := range jobs {
+			if nodeJobFilter(job, node) != "" {
+				continue
+			}
+			for _, reservation := range jobStaticPorts(job) {
+				if reserved(reservation.Port) {
+					usage.Issues = append(usage.Issues, fmt.Sprintf("static port %d of %s/%s is reserved by the node's client config, so the group can never be placed here", reservation.Port, reservation.JobID, reservation.Group))
+				}
+			}
+		}
+	}
+	return usage
+}
+
+// parsePortRanges parses a reserved_ports value such as "22,80,8000-8100" into a matcher.
+// Malformed entries are ignored.
+func parsePortRanges(spec string) func(int) bool {
+	type portRange struct{ from, to int }
+	var ranges []portRange
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		from, to, isRange := strings.Cut(part, "-")
+		lo, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			continue
+		}
+		hi := lo
+		if isRange {
+			if hi, err = strconv.Atoi(strings.TrimSpace(to)); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, portRange{lo, hi})
+	}
+	return func(port int) bool {
+		for _, r := range ranges {
+			if port >= r.from && port <= r.to {
+				return true
+			}
+		}
+		return false
+	}
+}
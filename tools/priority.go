@@ -0,0 +1,217 @@
+// File: tools/priority.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// preemptionPriorityDelta is how much higher a job's priority must be than another's for the
+// scheduler to preempt the other job's allocations.
+const preemptionPriorityDelta = 10
+
+// PreemptedAllocation is an allocation a priority change would preempt
+type PreemptedAllocation struct {
+	ID          string `json:"ID"`
+	Name        string `json:"Name"`
+	Namespace   string `json:"Namespace"`
+	JobID       string `json:"JobID"`
+	TaskGroup   string `json:"TaskGroup"`
+	NodeID      string `json:"NodeID"`
+	JobPriority *int   `json:"JobPriority,omitempty"`
+}
+
+// PriorityChangeSimulation is the result of simulate_priority_change
+type PriorityChangeSimulation struct {
+	JobID             string                 `json:"JobID"`
+	Namespace         string                 `json:"Namespace"`
+	Type              string                 `json:"Type"`
+	CurrentPriority   int                    `json:"CurrentPriority"`
+	NewPriority       int                    `json:"NewPriority"`
+	JobModifyIndex    int                    `json:"JobModifyIndex"`
+	PreemptionEnabled *bool                  `json:"PreemptionEnabled,omitempty"`
+	Placements        map[string]int64       `json:"Placements,omitempty"`
+	Preemptions       map[string]int64       `json:"Preemptions,omitempty"`
+	Preempted         []PreemptedAllocation  `json:"Preempted"`
+	FailedTGAllocs    map[string]interface{} `json:"FailedTGAllocs,omitempty"`
+	Warnings          string                 `json:"Warnings,omitempty"`
+	Summary           []string               `json:"Summary"`
+}
+
+// RegisterPriorityTools registers the job priority simulation tool
+func RegisterPriorityTools(s *server.MCPServer, nomadClient utils.PrioritySimulationAPI, logger *log.Logger) {
+	simulatePriorityChangeTool := mcp.NewTool("simulate_priority_change",
+		mcp.WithDescription("Plan a registered job at a new priority without submitting it and report the allocations of lower-priority jobs the scheduler would preempt to place it, with whether preemption is enabled for the job's scheduler. Nothing is changed"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job"),
+		),
+		mcp.WithNumber("priority",
+			mcp.Required(),
+			mcp.Description("The priority to simulate, usually 1 to 100"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+	)
+	s.AddTool(simulatePriorityChangeTool, SimulatePriorityChangeHandler(nomadClient, logger))
+}
+
+// SimulatePriorityChangeHandler returns a handler that plans a job at another priority
+func SimulatePriorityChangeHandler(client utils.PrioritySimulationAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, _ := arguments["job_id"].(string)
+		if jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		rawPriority, ok := arguments["priority"].(float64)
+		if !ok {
+			return mcp.NewToolResultError("priority is required"), nil
+		}
+		if rawPriority < 1 || rawPriority != math.Trunc(rawPriority) {
+			return mcp.NewToolResultError("priority must be a whole number of at least 1"), nil
+		}
+		priority := int(rawPriority)
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		job, err := client.GetJobDefinition(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
+		}
+		simulation := PriorityChangeSimulation{JobID: jobID, Namespace: namespace, NewPriority: priority, Preempted: []PreemptedAllocation{}}
+		simulation.Type, _ = job["Type"].(string)
+		if current, ok := job["Priority"].(float64); ok {
+			simulation.CurrentPriority = int(current)
+		}
+
+		job["Priority"] = priority
+		plan, err := client.PlanJob(ctx, job, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error planning job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to plan job", err), nil
+		}
+		simulation.JobModifyIndex = plan.JobModifyIndex
+		simulation.FailedTGAllocs = plan.FailedTGAllocs
+		simulation.Warnings = plan.Warnings
+
+		if config, err := client.GetSchedulerConfig(ctx); err != nil {
+			requestLogf(ctx, logger, "Error getting scheduler configuration: %v", err)
+		} else {
+			enabled := config.PreemptionConfig.Enabled(simulation.Type)
+			simulation.PreemptionEnabled = &enabled
+		}
+
+		if plan.Annotations != nil {
+			for group, updates := range plan.Annotations.DesiredTGUpdates {
+				if updates.Place > 0 {
+					if simulation.Placements == nil {
+						simulation.Placements = map[string]int64{}
+					}
+					simulation.Placements[group] = updates.Place
+				}
+				if updates.Preemptions > 0 {
+					if simulation.Preemptions == nil {
+						simulation.Preemptions = map[string]int64{}
+					}
+					simulation.Preemptions[group] = updates.Preemptions
+				}
+			}
+			simulation.Preempted = preemptedAllocations(ctx, client, plan.Annotations.PreemptedAllocs)
+		}
+		simulation.Summary = priorityChangeSummary(simulation)
+
+		simulationJSON, err := json.MarshalIndent(simulation, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format simulation", err), nil
+		}
+
+		return withNomadWarnings(mcp.NewToolResultText(string(simulationJSON)), simulation.Warnings), nil
+	}
+}
+
+// preemptedAllocations lists the allocations a plan would preempt with the priority of their
+// jobs. A job that cannot be read is reported without its priority.
+func preemptedAllocations(ctx context.Context, client utils.PrioritySimulationAPI, allocs []types.Allocation) []PreemptedAllocation {
+	priorities := map[string]*int{}
+	preempted := make([]PreemptedAllocation, 0, len(allocs))
+	for _, alloc := range allocs {
+		key := alloc.Namespace + "/" + alloc.JobID
+		priority, seen := priorities[key]
+		if !seen {
+			if job, err := client.GetJob(ctx, alloc.JobID, alloc.Namespace); err == nil {
+				priority = &job.Priority
+			}
+			priorities[key] = priority
+		}
+		preempted = append(preempted, PreemptedAllocation{
+			ID:          alloc.ID,
+			Name:        alloc.Name,
+			Namespace:   alloc.Namespace,
+			JobID:       alloc.JobID,
+			TaskGroup:   alloc.TaskGroup,
+			NodeID:      alloc.NodeID,
+			JobPriority: priority,
+		})
+	}
+	sort.Slice(preempted, func(i, j int) bool {
+		if preempted[i].JobID != preempted[j].JobID {
+			return preempted[i].JobID < preempted[j].JobID
+		}
+		return preempted[i].Name < preempted[j].Name
+	})
+	return preempted
+}
+
+// priorityChangeSummary describes what the simulated priority would do.
+func priorityChangeSummary(sim PriorityChangeSimulation) []string {
+	lines := []string{fmt.Sprintf("Planned job %s at priority %d (currently %d); nothing was submitted.", sim.JobID, sim.NewPriority, sim.CurrentPriority)}
+
+	var placements int64
+	for _, n := range sim.Placements {
+		placements += n
+	}
+	jobs := map[string]bool{}
+	for _, alloc := range sim.Preempted {
+		jobs[alloc.Namespace+"/"+alloc.JobID] = true
+	}
+
+	switch {
+	case len(sim.Preempted) > 0:
+		lines = append(lines, fmt.Sprintf("Placing %d allocations would preempt %d allocations of %d lower-priority jobs.", placements, len(sim.Preempted), len(jobs)))
+	case sim.PreemptionEnabled != nil && !*sim.PreemptionEnabled:
+		lines = append(lines, fmt.Sprintf("Preemption is disabled for the %s scheduler, so no allocation would be preempted; see the PreemptionConfig of the scheduler configuration.", schedulerName(sim.Type)))
+	case placements == 0:
+		lines = append(lines, "The job has no allocations left to place, so the new priority would not preempt anything now; it only applies to future placements and evaluation ordering.")
+	default:
+		lines = append(lines, fmt.Sprintf("Placing %d allocations would not preempt any allocation.", placements))
+	}
+	if len(sim.FailedTGAllocs) > 0 {
+		lines = append(lines, fmt.Sprintf("%d task groups would still fail to place; see FailedTGAllocs.", len(sim.FailedTGAllocs)))
+	}
+	lines = append(lines, fmt.Sprintf("Only allocations of jobs with a priority at least %d below the new priority (%d or less) can be preempted.", preemptionPriorityDelta, sim.NewPriority-preemptionPriorityDelta))
+	return lines
+}
+
+// schedulerName is the scheduler that handles jobType.
+func schedulerName(jobType string) string {
+	if jobType == "" {
+		return "service"
+	}
+	return jobType
+}
@@ -0,0 +1,60 @@
+// File: tools/progress.go
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// notifyToolProgress surfaces partial output of a running tool call: as a progress
+// notification with message when the call carries a progress token, otherwise as a log
+// message from logger carrying data. Clients without a session (or that cannot receive
+// notifications) only get the final result.
+func notifyToolProgress(ctx context.Context, request mcp.CallToolRequest, progress, total int, message, logger string, data any) {
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return
+	}
+
+	if request.Params.Meta != nil && request.Params.Meta.ProgressToken != nil {
+		_ = srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": request.Params.Meta.ProgressToken,
+			"progress":      progress,
+			"total":         total,
+			"message":       message,
+		})
+		return
+	}
+	_ = srv.SendNotificationToClient(ctx, "notifications/message", map[string]any{
+		"level":  "info",
+		"logger": logger,
+		"data":   data,
+	})
+}
+
+// streamDeadlineMargin is kept between the end of a streaming window and the call's
+// deadline, so the collected output is returned before TimeoutMiddleware gives up on the call.
+const streamDeadlineMargin = time.Second
+
+// streamWindow returns how long a streaming tool listens: the duration argument in seconds
+// (default def, capped at limit), cut short by the call's deadline.
+func streamWindow(ctx context.Context, arguments map[string]interface{}, def, limit time.Duration) (time.Duration, error) {
+	window := def
+	if d, ok := arguments["duration"].(float64); ok {
+		if d <= 0 {
+			return 0, fmt.Errorf("duration must be positive")
+		}
+		window = min(time.Duration(d*float64(time.Second)), limit)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		window = min(window, time.Until(deadline)-streamDeadlineMargin)
+		if window <= 0 {
+			return 0, fmt.Errorf("no time left to listen before the call's timeout")
+		}
+	}
+	return window, nil
+}
@@ -0,0 +1,202 @@
+// File: tools/projection.go
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// fieldsOption adds the fields projection argument to get_* tools.
+func fieldsOption() mcp.ToolOption {
+	return mcp.WithArray("fields",
+		mcp.Description("Only return these fields, as dot paths or JSONPath (e.g. Status, TaskGroups.Name, $.TaskGroups[0].Tasks[*].Config.image). A path through an array collects the value from every element"),
+		mcp.WithStringItems(),
+	)
+}
+
+// fieldPathSegment is one step of a field path: an object key, an array index, or *.
+type fieldPathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// stringListArgument reads an argument given as a list of strings or a comma-separated
+// string, dropping blank entries.
+func stringListArgument(arguments map[string]interface{}, name string) ([]string, error) {
+	var values []string
+	switch v := arguments[name].(type) {
+	case nil:
+	case string:
+		values = strings.Split(v, ",")
+	case []interface{}:
+		for _, item := range v {
+			value, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a list of strings", name)
+			}
+			values = append(values, value)
+		}
+	default:
+		return nil, fmt.Errorf("%s must be a list of strings", name)
+	}
+
+	var cleaned []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			cleaned = append(cleaned, value)
+		}
+	}
+	return cleaned, nil
+}
+
+// fieldsArgument reads and validates the fields argument, given as a list of strings or a
+// comma-separated string.
+func fieldsArgument(arguments map[string]interface{}) ([]string, error) {
+	fields, err := stringListArgument(arguments, "fields")
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		if _, err := parseFieldPath(f); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// projectFields returns v unchanged when fields is empty, otherwise an object keyed by each
+// requested path holding the value found there (null when the path does not exist).
+func projectFields(v interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	projected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		segments, err := parseFieldPath(field)
+		if err != nil {
+			return nil, err
+		}
+		value, _ := extractFieldPath(doc, segments)
+		projected[field] = value
+	}
+	return projected, nil
+}
+
+// parseFieldPath parses "A.B[0].C", "A[*].B" or "$.A['b.c']" into segments.
+func parseFieldPath(path string) ([]fieldPathSegment, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	var segments []fieldPathSegment
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid field path %q: unclosed [", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case inner == "*":
+				segments = append(segments, fieldPathSegment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				segments = append(segments, fieldPathSegment{key: inner[1 : len(inner)-1]})
+			default:
+				i, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid field path %q: bad index %q", path, inner)
+				}
+				segments = append(segments, fieldPathSegment{index: i, isIndex: true})
+			}
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if key := rest[:end]; key == "*" {
+				segments = append(segments, fieldPathSegment{wildcard: true})
+			} else {
+				segments = append(segments, fieldPathSegment{key: key})
+			}
+			rest = rest[end:]
+		}
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid field path %q: empty", path)
+	}
+	return segments, nil
+}
+
+// extractFieldPath walks segments through a decoded JSON document. Keys applied to an array
+// are mapped over its elements; keys match case-insensitively when there is no exact match.
+func extractFieldPath(node interface{}, segments []fieldPathSegment) (interface{}, bool) {
+	if len(segments) == 0 {
+		return node, true
+	}
+	seg := segments[0]
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if seg.wildcard {
+			out := map[string]interface{}{}
+			for k, v := range n {
+				if value, ok := extractFieldPath(v, segments[1:]); ok {
+					out[k] = value
+				}
+			}
+			return out, true
+		}
+		if seg.isIndex {
+			return nil, false
+		}
+		if v, ok := n[seg.key]; ok {
+			return extractFieldPath(v, segments[1:])
+		}
+		for k, v := range n {
+			if strings.EqualFold(k, seg.key) {
+				return extractFieldPath(v, segments[1:])
+			}
+		}
+		return nil, false
+	case []interface{}:
+		if seg.isIndex {
+			i := seg.index
+			if i < 0 {
+				i += len(n)
+			}
+			if i < 0 || i >= len(n) {
+				return nil, false
+			}
+			return extractFieldPath(n[i], segments[1:])
+		}
+		rest := segments
+		if seg.wildcard {
+			rest = segments[1:]
+		}
+		out := []interface{}{}
+		for _, e := range n {
+			if value, ok := extractFieldPath(e, rest); ok {
+				out = append(out, value)
+			}
+		}
+		return out, true
+	}
+	return nil, false
+}
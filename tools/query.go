@@ -0,0 +1,143 @@
+// File: tools/query.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/itchyny/gojq"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// queryArgumentDescription documents the query argument added to read-only tools.
+const queryArgumentDescription = "A jq expression applied server-side to the JSON result, e.g. `map(select(.Status == \"running\")) | length` or `[.[] | {ID, Status}]`. Several outputs are returned as an array"
+
+// AddQueryArgument adds an optional query argument to every registered read-only tool.
+// QueryMiddleware evaluates it; call this after all tools are registered.
+func AddQueryArgument(s *server.MCPServer) {
+	addReadOnlyToolArgument(s, "query", map[string]any{
+		"type":        "string",
+		"description": queryArgumentDescription,
+	})
+}
+
+// addReadOnlyToolArgument adds an argument schema to every registered read-only tool that
+// does not already declare it.
+func addReadOnlyToolArgument(s *server.MCPServer, name string, schema map[string]any) {
+	var updated []server.ServerTool
+	for _, tool := range s.ListTools() {
+		if !isReadOnlyTool(tool.Tool) {
+			continue
+		}
+		if _, exists := tool.Tool.InputSchema.Properties[name]; exists {
+			continue
+		}
+
+		t := tool.Tool
+		properties := make(map[string]any, len(t.InputSchema.Properties)+1)
+		for k, v := range t.InputSchema.Properties {
+			properties[k] = v
+		}
+		properties[name] = schema
+		t.InputSchema.Properties = properties
+		updated = append(updated, server.ServerTool{Tool: t, Handler: tool.Handler})
+	}
+	if len(updated) > 0 {
+		s.AddTools(updated...)
+	}
+}
+
+// QueryMiddleware returns a tool middleware that applies the query argument of read-only
+// tools to their JSON result with gojq, so aggregation and filtering happen before the
+// payload reaches the model.
+func QueryMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			arguments, _ := request.Params.Arguments.(map[string]interface{})
+			query, _ := arguments["query"].(string)
+			srv := server.ServerFromContext(ctx)
+			if query == "" || srv == nil {
+				return next(ctx, request)
+			}
+			if tool := srv.GetTool(request.Params.Name); tool == nil || !isReadOnlyTool(tool.Tool) {
+				return next(ctx, request)
+			}
+
+			code, err := compileQuery(query)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid query: %v", err)), nil
+			}
+
+			result, err := next(ctx, request)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+
+			queried := false
+			for i, c := range result.Content {
+				text, ok := c.(mcp.TextContent)
+				if !ok {
+					continue
+				}
+				var input interface{}
+				if err := json.Unmarshal([]byte(text.Text), &input); err != nil {
+					continue
+				}
+				output, err := runQuery(ctx, code, input)
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("query failed: %v", err)), nil
+				}
+				text.Text = output
+				result.Content[i] = text
+				queried = true
+			}
+			if !queried {
+				return mcp.NewToolResultError(fmt.Sprintf("query requires a JSON result, but %s did not return one", request.Params.Name)), nil
+			}
+
+			return result, nil
+		}
+	}
+}
+
+func compileQuery(query string) (*gojq.Code, error) {
+	parsed, err := gojq.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	return gojq.Compile(parsed)
+}
+
+// runQuery evaluates code against input. A single output is returned as is; zero or
+// several outputs are returned as a JSON array.
+func runQuery(ctx context.Context, code *gojq.Code, input interface{}) (string, error) {
+	outputs := []interface{}{}
+	iter := code.RunWithContext(ctx, input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			var haltErr *gojq.HaltError
+			if errors.As(err, &haltErr) && haltErr.Value() == nil {
+				break
+			}
+			return "", err
+		}
+		outputs = append(outputs, v)
+	}
+
+	var value interface{} = outputs
+	if len(outputs) == 1 {
+		value = outputs[0]
+	}
+	outputJSON, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(outputJSON), nil
+}
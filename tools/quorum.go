@@ -0,0 +1,188 @@
+// File: tools/quorum.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// QuorumServer is one server as seen by Raft, gossip and autopilot
+type QuorumServer struct {
+	ID          string `json:"ID"`
+	Name        string `json:"Name"`
+	Address     string `json:"Address"`
+	InRaft      bool   `json:"InRaft"`
+	Voter       bool   `json:"Voter"`
+	Leader      bool   `json:"Leader"`
+	SerfStatus  string `json:"SerfStatus,omitempty"`
+	Healthy     *bool  `json:"Healthy,omitempty"`
+	LastContact string `json:"LastContact,omitempty"`
+}
+
+// QuorumReport is the check_server_quorum response. Verdict is green, amber or red.
+type QuorumReport struct {
+	Verdict          string         `json:"Verdict"`
+	Voters           int            `json:"Voters"`
+	Quorum           int            `json:"Quorum"`
+	HealthyVoters    int            `json:"HealthyVoters"`
+	FailureTolerance int            `json:"FailureTolerance"`
+	Leader           string         `json:"Leader,omitempty"`
+	Servers          []QuorumServer `json:"Servers"`
+	Issues           []string       `json:"Issues"`
+	AutopilotError   string         `json:"AutopilotError,omitempty"`
+}
+
+// CheckServerQuorumHandler returns a handler that cross-checks server membership, the Raft
+// configuration and autopilot health
+func CheckServerQuorumHandler(client utils.ServerHealthAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		config, err := client.GetRaftConfiguration(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting raft configuration: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get raft configuration", err), nil
+		}
+
+		members, err := client.ListAgentMembers(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing agent members: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list agent members", err), nil
+		}
+
+		// Autopilot is optional: older clusters and restricted tokens may not expose it.
+		health, autopilotErr := client.GetAutopilotHealth(ctx)
+		if autopilotErr != nil {
+			requestLogf(ctx, logger, "Error getting autopilot health: %v", autopilotErr)
+		}
+
+		report := checkServerQuorum(config, members, health, autopilotErr == nil)
+		if autopilotErr != nil {
+			report.AutopilotError = autopilotErr.Error()
+		}
+
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format quorum report", err), nil
+		}
+
+		return mcp.NewToolResultText(string(reportJSON)), nil
+	}
+}
+
+// checkServerQuorum matches Raft peers to gossip members (by server ID, then RPC address)
+// and autopilot entries (by ID), then grades the cluster: red when the healthy voters no
+// longer form a quorum or there is no leader, amber when any server needs attention or the
+// cluster cannot lose a voter, green otherwise.
+func checkServerQuorum(config types.RaftConfiguration, members []types.AgentMember, health types.AutopilotHealth, haveAutopilot bool) QuorumReport {
+	report := QuorumReport{Servers: []QuorumServer{}, Issues: []string{}}
+
+	autopilot := map[string]types.AutopilotServerHealth{}
+	for _, server := range health.Servers {
+		autopilot[server.ID] = server
+	}
+
+	matched := map[int]bool{}
+	regions := map[string]bool{}
+	for _, peer := range config.Servers {
+		server := QuorumServer{ID: peer.ID, Name: peer.Node, Address: peer.Address, InRaft: true, Voter: peer.Voter, Leader: peer.Leader}
+
+		if i, ok := findServerMember(members, peer); ok {
+			matched[i] = true
+			server.SerfStatus = members[i].Status
+			if region := members[i].Tags["region"]; region != "" {
+				regions[region] = true
+			}
+		}
+		if ap, ok := autopilot[peer.ID]; ok && haveAutopilot {
+			healthy := ap.Healthy
+			server.Healthy = &healthy
+			server.LastContact = ap.LastContact
+		}
+
+		switch {
+		case server.SerfStatus == "":
+			report.Issues = append(report.Issues, fmt.Sprintf("server %s (%s) is in the Raft configuration but not in the gossip pool; it was likely left behind and should be removed with raft remove-peer", peer.Node, peer.Address))
+		case server.SerfStatus != "alive":
+			report.Issues = append(report.Issues, fmt.Sprintf("server %s is %s in the gossip pool but still a Raft peer", peer.Node, server.SerfStatus))
+		}
+		if !peer.Voter {
+			report.Issues = append(report.Issues, fmt.Sprintf("server %s is a non-voter and does not count towards quorum", peer.Node))
+		}
+		if server.Healthy != nil && !*server.Healthy {
+			report.Issues = append(report.Issues, fmt.Sprintf("autopilot reports server %s as unhealthy (last contact %s)", peer.Node, server.LastContact))
+		}
+
+		if peer.Leader {
+			report.Leader = peer.Node
+		}
+		if peer.Voter {
+			report.Voters++
+			if server.SerfStatus == "alive" && (server.Healthy == nil || *server.Healthy) {
+				report.HealthyVoters++
+			}
+		}
+		report.Servers = append(report.Servers, server)
+	}
+
+	for i, member := range members {
+		if matched[i] || member.Status != "alive" || (len(regions) > 0 && !regions[member.Tags["region"]]) {
+			continue
+		}
+		report.Servers = append(report.Servers, QuorumServer{ID: member.Tags["id"], Name: member.Name, Address: member.Addr, SerfStatus: member.Status})
+		report.Issues = append(report.Issues, fmt.Sprintf("server %s is alive in the gossip pool but missing from the Raft configuration", member.Name))
+	}
+
+	report.Quorum = report.Voters/2 + 1
+	report.FailureTolerance = report.HealthyVoters - report.Quorum
+	if report.FailureTolerance < 0 {
+		report.FailureTolerance = 0
+	}
+	if report.Voters > 0 && report.Voters%2 == 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf("%d voters tolerate no more failures than %d; use an odd number of servers", report.Voters, report.Voters-1))
+	}
+	if report.Leader == "" {
+		report.Issues = append(report.Issues, "the Raft configuration reports no leader")
+	}
+
+	switch {
+	case report.Leader == "" || report.HealthyVoters < report.Quorum:
+		report.Verdict = "red"
+		report.Issues = append(report.Issues, fmt.Sprintf("only %d of %d voters are healthy; a quorum needs %d", report.HealthyVoters, report.Voters, report.Quorum))
+	case len(report.Issues) > 0 || report.HealthyVoters == report.Quorum:
+		report.Verdict = "amber"
+		if report.HealthyVoters == report.Quorum {
+			report.Issues = append(report.Issues, "losing one more voter would lose quorum")
+		}
+	default:
+		report.Verdict = "green"
+	}
+
+	sort.SliceStable(report.Servers, func(i, j int) bool { return report.Servers[i].Name < report.Servers[j].Name })
+	return report
+}
+
+// findServerMember finds the gossip member for a Raft peer. Member ports are Serf ports, so
+// addresses are compared using the RPC port tag.
+func findServerMember(members []types.AgentMember, peer types.RaftOperator) (int, bool) {
+	for i, member := range members {
+		if member.Tags["id"] != "" && member.Tags["id"] == peer.ID {
+			return i, true
+		}
+	}
+	for i, member := range members {
+		if port, ok := member.Tags["port"]; ok && net.JoinHostPort(member.Addr, port) == peer.Address {
+			return i, true
+		}
+		if member.Name == peer.Node {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,379 @@
+// File: tools/quotas.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// enterpriseOnlyQuotaMessage explains a quota call refused by a community edition cluster.
+const enterpriseOnlyQuotaMessage = "This cluster runs the Nomad community edition; resource quotas only exist on Nomad Enterprise"
+
+// quotaLimitFields maps the limit object arguments of create_quota to the resources they set.
+var quotaLimitFields = []struct {
+	Name string
+	Set  func(*types.QuotaResources, int)
+}{
+	{"cpu", func(r *types.QuotaResources, v int) { r.CPU = v }},
+	{"cores", func(r *types.QuotaResources, v int) { r.Cores = v }},
+	{"memory_mb", func(r *types.QuotaResources, v int) { r.MemoryMB = v }},
+	{"memory_max_mb", func(r *types.QuotaResources, v int) { r.MemoryMaxMB = v }},
+	{"disk_mb", func(r *types.QuotaResources, v int) { r.DiskMB = v }},
+}
+
+// QuotaUsageReport is the result of quota_usage: a quota's limits next to what its
+// namespaces use, per region
+type QuotaUsageReport struct {
+	Name    string             `json:"Name"`
+	Regions []QuotaRegionUsage `json:"Regions"`
+}
+
+// QuotaRegionUsage compares the limit of a quota in one region with its usage
+type QuotaRegionUsage struct {
+	Region           string                `json:"Region"`
+	Limit            *types.QuotaResources `json:"Limit,omitempty"`
+	Used             *types.QuotaResources `json:"Used,omitempty"`
+	VariablesLimitMB *int                  `json:"VariablesLimitMB,omitempty"`
+	VariablesUsedMB  *int                  `json:"VariablesUsedMB,omitempty"`
+	// Utilization is the used share of each limited resource, in percent
+	Utilization map[string]float64 `json:"Utilization,omitempty"`
+}
+
+// RegisterQuotaTools registers the Nomad Enterprise resource quota tools
+func RegisterQuotaTools(s *server.MCPServer, nomadClient utils.QuotaAPI, logger *log.Logger) {
+	listQuotasTool := mcp.NewTool("list_quotas",
+		mcp.WithDescription("List the resource quota specifications (Nomad Enterprise)"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+	s.AddTool(listQuotasTool, ListQuotasHandler(nomadClient, logger))
+
+	getQuotaTool := mcp.NewTool("get_quota",
+		mcp.WithDescription("Get a resource quota specification with its per-region limits (Nomad Enterprise)"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the quota"),
+		),
+	)
+	s.AddTool(getQuotaTool, GetQuotaHandler(nomadClient, logger))
+
+	createQuotaTool := mcp.NewTool("create_quota",
+		mcp.WithDescription("Create a resource quota specification, or replace the one with the same name. Attach it to namespaces with their quota setting (Nomad Enterprise)"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the quota"),
+		),
+		mcp.WithString("description",
+			mcp.Description("Description of the quota"),
+		),
+		mcp.WithArray("limits",
+			mcp.Required(),
+			mcp.Description("One limit per region. Resource values of 0 mean unlimited and -1 forbids the resource"),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"region":          map[string]any{"type": "string", "description": "The region the limit applies to"},
+					"cpu":             map[string]any{"type": "number", "description": "CPU in MHz"},
+					"cores":           map[string]any{"type": "number", "description": "Reserved CPU cores"},
+					"memory_mb":       map[string]any{"type": "number", "description": "Memory in MB"},
+					"memory_max_mb":   map[string]any{"type": "number", "description": "Memory oversubscription limit in MB"},
+					"disk_mb":         map[string]any{"type": "number", "description": "Ephemeral disk in MB"},
+					"variables_limit": map[string]any{"type": "number", "description": "Total size of variables in MiB"},
+				},
+				"required": []string{"region"},
+			}),
+		),
+	)
+	s.AddTool(createQuotaTool, CreateQuotaHandler(nomadClient, logger))
+
+	deleteQuotaTool := mcp.NewTool("delete_quota",
+		mcp.WithDescription("Delete a resource quota specification. Nomad refuses while a namespace still uses it (Nomad Enterprise)"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the quota"),
+		),
+	)
+	s.AddTool(deleteQuotaTool, DeleteQuotaHandler(nomadClient, logger))
+
+	quotaUsageTool := mcp.NewTool("quota_usage",
+		mcp.WithDescription("Compare the limits of resource quotas with what their namespaces currently use, per region, with the used share of each limited resource (Nomad Enterprise)"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("name",
+			mcp.Description("The quota to report on (default: all quotas)"),
+		),
+	)
+	s.AddTool(quotaUsageTool, QuotaUsageHandler(nomadClient, logger))
+}
+
+// quotaErrorResult turns a failed quota call into a tool result, explaining community
+// edition clusters instead of surfacing their raw error.
+func quotaErrorResult(ctx context.Context, err error, action string, logger *log.Logger) *mcp.CallToolResult {
+	if utils.IsEnterpriseOnly(err) {
+		return mcp.NewToolResultError(enterpriseOnlyQuotaMessage)
+	}
+	requestLogf(ctx, logger, "Error trying to %s: %v", action, err)
+	return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Failed to %s", action), err)
+}
+
+// ListQuotasHandler returns a handler for listing quota specifications
+func ListQuotasHandler(client utils.QuotaAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		quotas, err := client.ListQuotas(ctx)
+		if err != nil {
+			return quotaErrorResult(ctx, err, "list quotas", logger), nil
+		}
+
+		quotasJSON, err := json.MarshalIndent(quotas, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format quotas", err), nil
+		}
+
+		return mcp.NewToolResultText(string(quotasJSON)), nil
+	}
+}
+
+// GetQuotaHandler returns a handler for getting a quota specification
+func GetQuotaHandler(client utils.QuotaAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		name, _ := arguments["name"].(string)
+		if name == "" {
+			return mcp.NewToolResultError("name is required"), nil
+		}
+
+		quota, err := client.GetQuota(ctx, name)
+		if err != nil {
+			return quotaErrorResult(ctx, err, "get quota", logger), nil
+		}
+
+		quotaJSON, err := json.MarshalIndent(quota, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format quota", err), nil
+		}
+
+		return mcp.NewToolResultText(string(quotaJSON)), nil
+	}
+}
+
+// quotaLimitsArgument decodes the limits argument of create_quota.
+func quotaLimitsArgument(arguments map[string]interface{}) ([]types.QuotaLimit, error) {
+	raw, _ := arguments["limits"].([]interface{})
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("limits is required")
+	}
+
+	limits := make([]types.QuotaLimit, 0, len(raw))
+	seen := map[string]bool{}
+	for i, item := range raw {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("limits[%d] must be an object", i)
+		}
+		region, _ := entry["region"].(string)
+		if region == "" {
+			return nil, fmt.Errorf("limits[%d].region is required", i)
+		}
+		if seen[region] {
+			return nil, fmt.Errorf("limits has more than one entry for region %s", region)
+		}
+		seen[region] = true
+
+		limit := types.QuotaLimit{Region: region, RegionLimit: &types.QuotaResources{}}
+		for _, field := range quotaLimitFields {
+			value, err := quotaLimitValue(entry, field.Name, i)
+			if err != nil {
+				return nil, err
+			}
+			if value != nil {
+				field.Set(limit.RegionLimit, *value)
+			}
+		}
+		variablesLimit, err := quotaLimitValue(entry, "variables_limit", i)
+		if err != nil {
+			return nil, err
+		}
+		limit.VariablesLimit = variablesLimit
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
+
+// quotaLimitValue returns the named value of a limit object, or nil when it is not set.
+// Values must be whole numbers of at least -1.
+func quotaLimitValue(entry map[string]interface{}, name string, i int) (*int, error) {
+	raw, ok := entry[name]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	value, ok := raw.(float64)
+	if !ok || value < -1 || value != math.Trunc(value) {
+		return nil, fmt.Errorf("limits[%d].%s must be a whole number of at least -1", i, name)
+	}
+	v := int(value)
+	return &v, nil
+}
+
+// CreateQuotaHandler returns a handler for creating or replacing a quota specification
+func CreateQuotaHandler(client utils.QuotaAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		name, _ := arguments["name"].(string)
+		if name == "" {
+			return mcp.NewToolResultError("name is required"), nil
+		}
+		limits, err := quotaLimitsArgument(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		description, _ := arguments["description"].(string)
+
+		quota := types.QuotaSpec{Name: name, Description: description, Limits: limits}
+		if err := client.UpsertQuota(ctx, quota); err != nil {
+			return quotaErrorResult(ctx, err, "create quota", logger), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Quota %s created successfully", name)), nil
+	}
+}
+
+// DeleteQuotaHandler returns a handler for deleting a quota specification
+func DeleteQuotaHandler(client utils.QuotaAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		name, _ := arguments["name"].(string)
+		if name == "" {
+			return mcp.NewToolResultError("name is required"), nil
+		}
+
+		if err := client.DeleteQuota(ctx, name); err != nil {
+			return quotaErrorResult(ctx, err, "delete quota", logger), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Quota %s deleted successfully", name)), nil
+	}
+}
+
+// QuotaUsageHandler returns a handler comparing quota limits with their usage
+func QuotaUsageHandler(client utils.QuotaAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		var quotas []types.QuotaSpec
+		var usages []types.QuotaUsage
+		if name, _ := arguments["name"].(string); name != "" {
+			quota, err := client.GetQuota(ctx, name)
+			if err != nil {
+				return quotaErrorResult(ctx, err, "get quota", logger), nil
+			}
+			usage, err := client.GetQuotaUsage(ctx, name)
+			if err != nil {
+				return quotaErrorResult(ctx, err, "get quota usage", logger), nil
+			}
+			quotas, usages = []types.QuotaSpec{quota}, []types.QuotaUsage{usage}
+		} else {
+			var err error
+			if quotas, err = client.ListQuotas(ctx); err != nil {
+				return quotaErrorResult(ctx, err, "list quotas", logger), nil
+			}
+			if usages, err = client.ListQuotaUsages(ctx); err != nil {
+				return quotaErrorResult(ctx, err, "list quota usages", logger), nil
+			}
+		}
+
+		usageByName := make(map[string]types.QuotaUsage, len(usages))
+		for _, usage := range usages {
+			usageByName[usage.Name] = usage
+		}
+		reports := make([]QuotaUsageReport, 0, len(quotas))
+		for _, quota := range quotas {
+			reports = append(reports, quotaUsageReport(quota, usageByName[quota.Name]))
+		}
+
+		reportsJSON, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format quota usage", err), nil
+		}
+
+		return mcp.NewToolResultText(string(reportsJSON)), nil
+	}
+}
+
+// quotaUsageReport matches the usage of a quota to its limits by region.
+func quotaUsageReport(quota types.QuotaSpec, usage types.QuotaUsage) QuotaUsageReport {
+	usedByRegion := map[string]*types.QuotaLimit{}
+	for _, used := range usage.Used {
+		if used != nil {
+			usedByRegion[used.Region] = used
+		}
+	}
+
+	report := QuotaUsageReport{Name: quota.Name, Regions: []QuotaRegionUsage{}}
+	for _, limit := range quota.Limits {
+		region := QuotaRegionUsage{Region: limit.Region, Limit: limit.RegionLimit, VariablesLimitMB: limit.VariablesLimit}
+		if used := usedByRegion[limit.Region]; used != nil {
+			region.Used = used.RegionLimit
+			region.VariablesUsedMB = used.VariablesLimit
+		}
+		region.Utilization = quotaUtilization(region)
+		report.Regions = append(report.Regions, region)
+	}
+	sort.Slice(report.Regions, func(i, j int) bool { return report.Regions[i].Region < report.Regions[j].Region })
+	return report
+}
+
+// quotaUtilization returns the used share of each resource with a positive limit.
+func quotaUtilization(region QuotaRegionUsage) map[string]float64 {
+	utilization := map[string]float64{}
+	share := func(name string, used, limit int) {
+		if limit > 0 {
+			utilization[name] = math.Round(float64(used)/float64(limit)*1000) / 10
+		}
+	}
+	if region.Limit != nil {
+		used := types.QuotaResources{}
+		if region.Used != nil {
+			used = *region.Used
+		}
+		share("CPU", used.CPU, region.Limit.CPU)
+		share("Cores", used.Cores, region.Limit.Cores)
+		share("MemoryMB", used.MemoryMB, region.Limit.MemoryMB)
+		share("MemoryMaxMB", used.MemoryMaxMB, region.Limit.MemoryMaxMB)
+		share("DiskMB", used.DiskMB, region.Limit.DiskMB)
+	}
+	if region.VariablesLimitMB != nil {
+		used := 0
+		if region.VariablesUsedMB != nil {
+			used = *region.VariablesUsedMB
+		}
+		share("VariablesMB", used, *region.VariablesLimitMB)
+	}
+	if len(utilization) == 0 {
+		return nil
+	}
+	return utilization
+}
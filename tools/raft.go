@@ -0,0 +1,171 @@
+// File: tools/raft.go
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RegisterRaftTools registers the tools that change the Raft peer set
+func RegisterRaftTools(s *server.MCPServer, nomadClient utils.RaftPeerAPI, logger *log.Logger) {
+	raftRemovePeerTool := mcp.NewTool("raft_remove_peer",
+		mcp.WithDescription("Remove a server from the Raft peer set, e.g. a failed server that autopilot cannot clean up. Dangerous: removing a healthy server can cost quorum. The leader is never removed, and a server still alive in the gossip pool only with force. Check the peers with check_server_quorum first"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("id",
+			mcp.Description("The Raft ID of the peer to remove"),
+		),
+		mcp.WithString("address",
+			mcp.Description("The Raft address (ip:port) of the peer to remove, for peers without an ID"),
+		),
+		mcp.WithString("confirm",
+			mcp.Required(),
+			mcp.Description("Must repeat the id or address passed, to confirm the removal"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Remove the peer even though its server is still alive in the gossip pool (default: false)"),
+		),
+	)
+	s.AddTool(raftRemovePeerTool, RaftRemovePeerHandler(nomadClient, logger))
+
+	transferLeadershipTool := mcp.NewTool("transfer_leadership",
+		mcp.WithDescription("Make another voting server the Raft leader, e.g. before taking the current leader down. Dangerous: the cluster briefly has no leader and rejects writes while leadership moves"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("id",
+			mcp.Description("The Raft ID of the voter to transfer leadership to"),
+		),
+		mcp.WithString("address",
+			mcp.Description("The Raft address (ip:port) of the voter to transfer leadership to"),
+		),
+		mcp.WithString("confirm",
+			mcp.Required(),
+			mcp.Description("Must repeat the id or address passed, to confirm the transfer"),
+		),
+	)
+	s.AddTool(transferLeadershipTool, TransferLeadershipHandler(nomadClient, logger))
+}
+
+// raftPeerArguments returns the id or address selecting a peer and checks that confirm
+// repeats it.
+func raftPeerArguments(arguments map[string]interface{}) (string, string, error) {
+	id, _ := arguments["id"].(string)
+	address, _ := arguments["address"].(string)
+	switch {
+	case id == "" && address == "":
+		return "", "", fmt.Errorf("id or address is required")
+	case id != "" && address != "":
+		return "", "", fmt.Errorf("pass either id or address, not both")
+	}
+
+	target := id + address
+	confirm, _ := arguments["confirm"].(string)
+	if confirm != target {
+		return "", "", fmt.Errorf("confirm must repeat %q exactly", target)
+	}
+	return id, address, nil
+}
+
+// findRaftPeer returns the peer of config with the given ID or address.
+func findRaftPeer(config types.RaftConfiguration, id, address string) (types.RaftOperator, error) {
+	peers := make([]string, 0, len(config.Servers))
+	for _, peer := range config.Servers {
+		if (id != "" && peer.ID == id) || (address != "" && peer.Address == address) {
+			return peer, nil
+		}
+		peers = append(peers, fmt.Sprintf("%s (%s)", peer.ID, peer.Address))
+	}
+	return types.RaftOperator{}, fmt.Errorf("no Raft peer %s; peers: %s", id+address, strings.Join(peers, ", "))
+}
+
+// RaftRemovePeerHandler returns a handler for removing a Raft peer
+func RaftRemovePeerHandler(client utils.RaftPeerAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		id, address, err := raftPeerArguments(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		force, _ := arguments["force"].(bool)
+
+		config, err := client.GetRaftConfiguration(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting raft configuration: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get raft configuration", err), nil
+		}
+		peer, err := findRaftPeer(config, id, address)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if peer.Leader {
+			return mcp.NewToolResultError(fmt.Sprintf("%s is the Raft leader; transfer leadership before removing it", peer.Node)), nil
+		}
+
+		if !force {
+			members, err := client.ListAgentMembers(ctx)
+			if err != nil {
+				requestLogf(ctx, logger, "Error listing agent members: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to list agent members", err), nil
+			}
+			for _, member := range members {
+				if member.Name == peer.Node && member.Status == "alive" {
+					return mcp.NewToolResultError(fmt.Sprintf("%s is still alive in the gossip pool; stop it first or pass force=true", peer.Node)), nil
+				}
+			}
+		}
+
+		if err := client.RemoveRaftPeer(ctx, id, address); err != nil {
+			requestLogf(ctx, logger, "Error removing raft peer: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to remove raft peer", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Raft peer %s (%s) removed successfully", peer.Node, peer.Address)), nil
+	}
+}
+
+// TransferLeadershipHandler returns a handler for moving Raft leadership to another voter
+func TransferLeadershipHandler(client utils.RaftPeerAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		id, address, err := raftPeerArguments(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		config, err := client.GetRaftConfiguration(ctx)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting raft configuration: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get raft configuration", err), nil
+		}
+		peer, err := findRaftPeer(config, id, address)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if peer.Leader {
+			return mcp.NewToolResultError(fmt.Sprintf("%s is already the Raft leader", peer.Node)), nil
+		}
+		if !peer.Voter {
+			return mcp.NewToolResultError(fmt.Sprintf("%s is not a voter and cannot become leader", peer.Node)), nil
+		}
+
+		if err := client.TransferRaftLeadership(ctx, id, address); err != nil {
+			requestLogf(ctx, logger, "Error transferring raft leadership: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to transfer leadership", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Leadership transferred to %s (%s) successfully", peer.Node, peer.Address)), nil
+	}
+}
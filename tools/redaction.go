@@ -0,0 +1,253 @@
+// File: tools/redaction.go
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultRedactionReplacement replaces redacted values when a rule sets no replacement.
+const defaultRedactionReplacement = "<redacted>"
+
+// keyValuePattern finds key=value and key: value pairs in plain text such as HCL, env files
+// and logs. The value may be quoted.
+var keyValuePattern = regexp.MustCompile(`([A-Za-z0-9_.\-]+)("?\s*[=:]\s*)("[^"]*"|'[^']*'|[^\s,;"'}]+)`)
+
+// RedactionRule masks secrets in outputs. A Key rule masks the values of JSON object keys
+// and key=value pairs whose key matches; a Pattern rule masks every match in text.
+type RedactionRule struct {
+	Name        string `yaml:"name" json:"name"`
+	Key         string `yaml:"key" json:"key"`
+	Pattern     string `yaml:"pattern" json:"pattern"`
+	Replacement string `yaml:"replacement" json:"replacement"`
+}
+
+type compiledRedactionRule struct {
+	key         *regexp.Regexp
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// Redactor applies redaction rules to tool and resource outputs.
+type Redactor struct {
+	rules []compiledRedactionRule
+}
+
+// NewRedactor compiles rules. Each rule needs exactly one of key and pattern.
+func NewRedactor(rules []RedactionRule) (*Redactor, error) {
+	redactor := &Redactor{}
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if (rule.Key == "") == (rule.Pattern == "") {
+			return nil, fmt.Errorf("redaction rule %s: set exactly one of key and pattern", name)
+		}
+		compiled := compiledRedactionRule{replacement: rule.Replacement}
+		if compiled.replacement == "" {
+			compiled.replacement = defaultRedactionReplacement
+		}
+		expr, target := rule.Key, &compiled.key
+		if rule.Pattern != "" {
+			expr, target = rule.Pattern, &compiled.pattern
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("redaction rule %s: %v", name, err)
+		}
+		*target = re
+		redactor.rules = append(redactor.rules, compiled)
+	}
+	return redactor, nil
+}
+
+// LoadRedactionRules reads a YAML (or JSON) file holding a list of rules under "rules".
+func LoadRedactionRules(path string) (*Redactor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Rules []RedactionRule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	return NewRedactor(config.Rules)
+}
+
+// redactKey returns the replacement for the value of key, if a key rule matches it.
+func (r *Redactor) redactKey(key string) (string, bool) {
+	for _, rule := range r.rules {
+		if rule.key != nil && rule.key.MatchString(key) {
+			return rule.replacement, true
+		}
+	}
+	return "", false
+}
+
+// RedactText masks secrets in text. JSON documents are redacted structurally (a document
+// that changed is re-encoded with sorted keys); anything else is treated as plain text.
+func (r *Redactor) RedactText(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		decoder := json.NewDecoder(strings.NewReader(trimmed))
+		decoder.UseNumber()
+		var document any
+		if err := decoder.Decode(&document); err == nil {
+			if redacted, changed := r.redactValue(document); changed {
+				indent := ""
+				if strings.Contains(trimmed, "\n") {
+					indent = "  "
+				}
+				if out, err := marshalRedacted(redacted, indent); err == nil {
+					return out
+				}
+			}
+			return text
+		}
+	}
+	return r.redactPlain(text)
+}
+
+// redactPlain masks key=value pairs with a matching key, then pattern matches.
+func (r *Redactor) redactPlain(text string) string {
+	text = keyValuePattern.ReplaceAllStringFunc(text, func(pair string) string {
+		parts := keyValuePattern.FindStringSubmatch(pair)
+		replacement, ok := r.redactKey(parts[1])
+		if !ok {
+			return pair
+		}
+		value := parts[3]
+		if quote := value[:1]; (quote == `"` || quote == "'") && len(value) > 1 {
+			replacement = quote + replacement + quote
+		}
+		return parts[1] + parts[2] + replacement
+	})
+	for _, rule := range r.rules {
+		if rule.pattern != nil {
+			text = rule.pattern.ReplaceAllLiteralString(text, rule.replacement)
+		}
+	}
+	return text
+}
+
+// redactValue walks a decoded JSON value and reports whether anything was masked.
+func (r *Redactor) redactValue(value any) (any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		changed := false
+		for key, item := range v {
+			if replacement, ok := r.redactKey(key); ok && item != nil {
+				v[key] = replacement
+				changed = true
+				continue
+			}
+			if redacted, itemChanged := r.redactValue(item); itemChanged {
+				v[key] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+	case []any:
+		changed := false
+		for i, item := range v {
+			if redacted, itemChanged := r.redactValue(item); itemChanged {
+				v[i] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+	case string:
+		redacted := r.redactPlain(v)
+		return redacted, redacted != v
+	}
+	return value, false
+}
+
+// marshalRedacted encodes a redacted document without escaping <, > and & so replacements
+// stay readable.
+func marshalRedacted(document any, indent string) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(document); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// redactResult masks secrets in every text block, embedded text resource and the
+// structured content of a result.
+func (r *Redactor) redactResult(result *mcp.CallToolResult) {
+	content := make([]mcp.Content, len(result.Content))
+	for i, block := range result.Content {
+		switch c := block.(type) {
+		case mcp.TextContent:
+			c.Text = r.RedactText(c.Text)
+			block = c
+		case mcp.EmbeddedResource:
+			if text, ok := c.Resource.(mcp.TextResourceContents); ok {
+				text.Text = r.RedactText(text.Text)
+				c.Resource = text
+				block = c
+			}
+		}
+		content[i] = block
+	}
+	result.Content = content
+
+	if result.StructuredContent != nil {
+		if raw, err := json.Marshal(result.StructuredContent); err == nil {
+			var structured any
+			if err := json.Unmarshal([]byte(r.RedactText(string(raw))), &structured); err == nil {
+				result.StructuredContent = structured
+			}
+		}
+	}
+}
+
+// RedactionMiddleware returns a tool middleware that applies the redactor to every tool
+// result. A nil redactor disables redaction.
+func RedactionMiddleware(redactor *Redactor) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if redactor != nil && result != nil {
+				redactor.redactResult(result)
+			}
+			return result, err
+		}
+	}
+}
+
+// RedactionResourceMiddleware returns a resource middleware that applies the redactor to
+// the text of every resource read. A nil redactor disables redaction.
+func RedactionResourceMiddleware(redactor *Redactor) server.ResourceHandlerMiddleware {
+	return func(next server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+		return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			contents, err := next(ctx, request)
+			if redactor == nil {
+				return contents, err
+			}
+			for i, content := range contents {
+				if text, ok := content.(mcp.TextResourceContents); ok {
+					text.Text = redactor.RedactText(text.Text)
+					contents[i] = text
+				}
+			}
+			return contents, err
+		}
+	}
+}
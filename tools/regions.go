@@ -0,0 +1,86 @@
+// File: tools/regions.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// allRegionsDescription documents the all_regions argument of fan-out read tools.
+const allRegionsDescription = "Query every region of the federation concurrently and merge the results, each tagged with its Region (default: false)"
+
+// regionResult is the outcome of one region's fetch in fanOutRegions.
+type regionResult[T any] struct {
+	Region string
+	Items  []T
+	Err    error
+}
+
+// fanOutRegions lists the cluster's regions and runs fetch once per region concurrently,
+// with utils.WithRegion set on its context. Results are sorted by region.
+func fanOutRegions[T any](ctx context.Context, client utils.RegionAPI, fetch func(ctx context.Context) ([]T, error)) ([]regionResult[T], error) {
+	regions, err := client.ListRegions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(regions)
+
+	results := make([]regionResult[T], len(regions))
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func(i int, region string) {
+			defer wg.Done()
+			items, err := fetch(utils.WithRegion(ctx, region))
+			results[i] = regionResult[T]{Region: region, Items: items, Err: err}
+		}(i, region)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// mergeRegionResults flattens fan-out results with tag, and describes the failed regions.
+func mergeRegionResults[T, R any](results []regionResult[T], tag func(region string, item T) R) ([]R, []string) {
+	merged := []R{}
+	var failures []string
+	for _, r := range results {
+		if r.Err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", r.Region, r.Err))
+			continue
+		}
+		for _, item := range r.Items {
+			merged = append(merged, tag(r.Region, item))
+		}
+	}
+	return merged, failures
+}
+
+// regionFanOutResult formats merged fan-out items. Regions that failed are reported in a
+// second text block so the JSON array stays intact; the call fails only when every one of
+// the regions did.
+func regionFanOutResult(ctx context.Context, items interface{}, failures []string, regions int, what string, logger *log.Logger) (*mcp.CallToolResult, error) {
+	if len(failures) > 0 {
+		requestLogf(ctx, logger, "Error listing %s in some regions: %s", what, strings.Join(failures, "; "))
+		if len(failures) == regions {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list %s in any region: %s", what, strings.Join(failures, "; "))), nil
+		}
+	}
+
+	itemsJSON, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Failed to format %s", what), err), nil
+	}
+	result := mcp.NewToolResultText(string(itemsJSON))
+	if len(failures) > 0 {
+		result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("Could not list %s in every region: %s", what, strings.Join(failures, "; "))))
+	}
+	return result, nil
+}
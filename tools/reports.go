@@ -0,0 +1,339 @@
+// File: tools/reports.go
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+// reportWebhookTimeout bounds one delivery of a report to its webhook.
+const reportWebhookTimeout = 10 * time.Second
+
+// reportName matches the names of scheduled reports, which become part of a resource URI.
+var reportName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ScheduledReport is a read-only tool call run on a cron schedule
+type ScheduledReport struct {
+	Name      string                 `yaml:"name"`
+	Schedule  string                 `yaml:"schedule"`
+	Tool      string                 `yaml:"tool"`
+	Arguments map[string]interface{} `yaml:"arguments"`
+	// Webhook, if set, receives every run as a JSON POST
+	Webhook string `yaml:"webhook"`
+
+	cron *CronSchedule
+}
+
+// ReportRun is the outcome of one run of a scheduled report
+type ReportRun struct {
+	Report  string    `json:"Report"`
+	Tool    string    `json:"Tool"`
+	Time    time.Time `json:"Time"`
+	IsError bool      `json:"IsError,omitempty"`
+	Text    string    `json:"Text"`
+}
+
+// ReportStatus is the content of a report resource: its schedule and latest run
+type ReportStatus struct {
+	Report    string                 `json:"Report"`
+	Tool      string                 `json:"Tool"`
+	Arguments map[string]interface{} `json:"Arguments,omitempty"`
+	Schedule  string                 `json:"Schedule"`
+	NextRun   time.Time              `json:"NextRun"`
+	LastRun   *ReportRun             `json:"LastRun,omitempty"`
+}
+
+// reportWebhookPayload is posted to a report's webhook. text makes it readable by chat
+// incoming webhooks as is.
+type reportWebhookPayload struct {
+	Text   string    `json:"text"`
+	Report ReportRun `json:"report"`
+}
+
+// LoadReportSchedule reads a YAML (or JSON) file holding a list of reports under "reports"
+// and checks their names, schedules and webhooks.
+func LoadReportSchedule(path string) ([]ScheduledReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Reports []ScheduledReport `yaml:"reports"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+
+	seen := map[string]bool{}
+	for i := range config.Reports {
+		report := &config.Reports[i]
+		if !reportName.MatchString(report.Name) {
+			return nil, fmt.Errorf("report %d: name %q must be lowercase letters, digits, - and _", i, report.Name)
+		}
+		if seen[report.Name] {
+			return nil, fmt.Errorf("report %s is defined more than once", report.Name)
+		}
+		seen[report.Name] = true
+		if report.Tool == "" {
+			return nil, fmt.Errorf("report %s: tool is required", report.Name)
+		}
+		if report.cron, err = ParseCronSchedule(report.Schedule); err != nil {
+			return nil, fmt.Errorf("report %s: %v", report.Name, err)
+		}
+		if report.cron.Next(time.Now()).IsZero() {
+			return nil, fmt.Errorf("report %s: schedule %q never fires", report.Name, report.Schedule)
+		}
+		if report.Webhook != "" {
+			if u, err := url.Parse(report.Webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("report %s: webhook must be an http or https URL", report.Name)
+			}
+		}
+	}
+	return config.Reports, nil
+}
+
+// ReportScheduler runs scheduled reports through the server's tool middleware, so their
+// results are redacted and time-limited like any client call, keeps the latest run of each
+// and posts runs to webhooks.
+type ReportScheduler struct {
+	server  *server.MCPServer
+	reports []ScheduledReport
+	client  *http.Client
+	logger  *log.Logger
+	calls   atomic.Int64
+
+	mu     sync.Mutex
+	latest map[string]ReportRun
+}
+
+// NewReportScheduler returns a scheduler for reports on s. Every report must name a
+// read-only tool registered on s, so a schedule can never change the cluster.
+func NewReportScheduler(s *server.MCPServer, reports []ScheduledReport, logger *log.Logger) (*ReportScheduler, error) {
+	for i := range reports {
+		report := &reports[i]
+		tool := s.GetTool(report.Tool)
+		if tool == nil {
+			return nil, fmt.Errorf("report %s: unknown tool %s", report.Name, report.Tool)
+		}
+		if !isReadOnlyTool(tool.Tool) {
+			return nil, fmt.Errorf("report %s: %s is not a read-only tool", report.Name, report.Tool)
+		}
+		if report.cron == nil {
+			cron, err := ParseCronSchedule(report.Schedule)
+			if err != nil {
+				return nil, fmt.Errorf("report %s: %v", report.Name, err)
+			}
+			report.cron = cron
+		}
+	}
+	return &ReportScheduler{
+		server:  s,
+		reports: reports,
+		client:  &http.Client{Timeout: reportWebhookTimeout},
+		logger:  logger,
+		latest:  map[string]ReportRun{},
+	}, nil
+}
+
+// Run runs every report at its scheduled times until ctx is done.
+func (r *ReportScheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, report := range r.reports {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.runSchedule(ctx, report)
+		}()
+	}
+	wg.Wait()
+}
+
+// runSchedule waits for each run time of report and runs it.
+func (r *ReportScheduler) runSchedule(ctx context.Context, report ScheduledReport) {
+	for {
+		next := report.cron.Next(time.Now())
+		if next.IsZero() {
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			r.run(ctx, report)
+		}
+	}
+}
+
+// RunReport runs the named report now, as if its schedule had fired.
+func (r *ReportScheduler) RunReport(ctx context.Context, name string) (ReportRun, error) {
+	for _, report := range r.reports {
+		if report.Name == name {
+			return r.run(ctx, report), nil
+		}
+	}
+	return ReportRun{}, fmt.Errorf("report %s is not scheduled", name)
+}
+
+// run calls the tool of report, stores the run and delivers it to the webhook.
+func (r *ReportScheduler) run(ctx context.Context, report ScheduledReport) ReportRun {
+	run := ReportRun{Report: report.Name, Tool: report.Tool, Time: time.Now().UTC()}
+	result, err := r.callTool(ctx, report)
+	if err != nil {
+		run.IsError, run.Text = true, err.Error()
+	} else {
+		run.IsError, run.Text = result.IsError, resultText(result)
+	}
+	if run.IsError {
+		r.logger.Printf("Scheduled report %s failed: %s", report.Name, run.Text)
+	}
+
+	r.mu.Lock()
+	r.latest[report.Name] = run
+	r.mu.Unlock()
+	r.server.SendNotificationToAllClients(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": reportURI(report.Name)})
+
+	if report.Webhook != "" {
+		if err := r.postWebhook(ctx, report.Webhook, run); err != nil {
+			r.logger.Printf("Error posting report %s to its webhook: %v", report.Name, err)
+		}
+	}
+	return run
+}
+
+// callTool sends a tools/call request for report through the server.
+func (r *ReportScheduler) callTool(ctx context.Context, report ScheduledReport) (*mcp.CallToolResult, error) {
+	arguments := report.Arguments
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	message, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      r.calls.Add(1),
+		"method":  string(mcp.MethodToolsCall),
+		"params":  map[string]interface{}{"name": report.Tool, "arguments": arguments},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch response := r.server.HandleMessage(ctx, message).(type) {
+	case mcp.JSONRPCResponse:
+		result, ok := response.Result.(*mcp.CallToolResult)
+		if !ok {
+			return nil, fmt.Errorf("unexpected result %T", response.Result)
+		}
+		return result, nil
+	case mcp.JSONRPCError:
+		return nil, fmt.Errorf("%s", response.Error.Message)
+	default:
+		return nil, fmt.Errorf("unexpected response %T", response)
+	}
+}
+
+// resultText joins the text blocks of a tool result.
+func resultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// postWebhook delivers run to webhook as JSON.
+func (r *ReportScheduler) postWebhook(ctx context.Context, webhook string, run ReportRun) error {
+	status := "succeeded"
+	if run.IsError {
+		status = "failed"
+	}
+	body, err := json.Marshal(reportWebhookPayload{
+		Text:   fmt.Sprintf("Report %s (%s) %s at %s:\n%s", run.Report, run.Tool, status, run.Time.Format(time.RFC3339), run.Text),
+		Report: run,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// status returns the schedule and latest run of report.
+func (r *ReportScheduler) status(report ScheduledReport) ReportStatus {
+	status := ReportStatus{
+		Report:    report.Name,
+		Tool:      report.Tool,
+		Arguments: report.Arguments,
+		Schedule:  report.Schedule,
+		NextRun:   report.cron.Next(time.Now()),
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if run, ok := r.latest[report.Name]; ok {
+		status.LastRun = &run
+	}
+	return status
+}
+
+// reportURI is the URI of the resource holding a report's latest run.
+func reportURI(name string) string {
+	return "nomad://reports/" + name
+}
+
+// RegisterReportResources registers a resource per scheduled report holding its latest run
+func RegisterReportResources(s *server.MCPServer, scheduler *ReportScheduler, logger *log.Logger) {
+	for _, report := range scheduler.reports {
+		uri := reportURI(report.Name)
+		reportResource := mcp.NewResource(
+			uri,
+			fmt.Sprintf("Report %s", report.Name),
+			mcp.WithResourceDescription(fmt.Sprintf("The latest result of the scheduled %s report (%s), run on the schedule %q", report.Tool, report.Name, report.Schedule)),
+			mcp.WithMIMEType("application/json"),
+		)
+		s.AddResource(reportResource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			statusJSON, err := json.MarshalIndent(scheduler.status(report), "", "  ")
+			if err != nil {
+				requestLogf(ctx, logger, "Error formatting report %s: %v", report.Name, err)
+				return nil, fmt.Errorf("failed to format report: %v", err)
+			}
+
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{
+					URI:      uri,
+					MIMEType: "application/json",
+					Text:     string(statusJSON),
+				},
+			}, nil
+		})
+	}
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"log"
-	"os"
 	"time"
 
 	"github.com/kocierik/mcp-nomad/utils"
@@ -13,17 +13,18 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// RegisterResources registers all resources with the MCP server
-func RegisterResources(s *server.MCPServer, nomadClient utils.DynamicResourcesNomad, logger *log.Logger) {
+// RegisterResources registers all resources with the MCP server. docs holds the embedded
+// README.md and LICENSE served as docs:// resources.
+func RegisterResources(s *server.MCPServer, nomadClient utils.DynamicResourcesNomad, docs fs.FS, logger *log.Logger) {
 	// Register static resources
-	registerStaticResources(s, logger)
+	registerStaticResources(s, nomadClient, docs, logger)
 
 	// Register dynamic resources
 	registerDynamicResources(s, nomadClient, logger)
 }
 
 // registerStaticResources registers static resources
-func registerStaticResources(s *server.MCPServer, logger *log.Logger) {
+func registerStaticResources(s *server.MCPServer, nomadClient utils.AgentAPI, docs fs.FS, logger *log.Logger) {
 	// README resource
 	readmeResource := mcp.NewResource(
 		"docs://readme",
@@ -33,9 +34,9 @@ func registerStaticResources(s *server.MCPServer, logger *log.Logger) {
 	)
 
 	s.AddResource(readmeResource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-		content, err := os.ReadFile("README.md")
+		content, err := fs.ReadFile(docs, "README.md")
 		if err != nil {
-			logger.Printf("Error reading README: %v", err)
+			requestLogf(ctx, logger, "Error reading README: %v", err)
 			return nil, err
 		}
 
@@ -57,9 +58,9 @@ func registerStaticResources(s *server.MCPServer, logger *log.Logger) {
 	)
 
 	s.AddResource(licenseResource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-		content, err := os.ReadFile("LICENSE")
+		content, err := fs.ReadFile(docs, "LICENSE")
 		if err != nil {
-			logger.Printf("Error reading LICENSE: %v", err)
+			requestLogf(ctx, logger, "Error reading LICENSE: %v", err)
 			return nil, err
 		}
 
@@ -91,6 +92,11 @@ func registerStaticResources(s *server.MCPServer, logger *log.Logger) {
 				"prompts",
 			},
 		}
+		if version, err := nomadClient.GetNomadVersion(ctx); err == nil {
+			info["nomad_version"] = version
+		} else {
+			requestLogf(ctx, logger, "Error detecting Nomad version: %v", err)
+		}
 
 		infoJSON, err := json.MarshalIndent(info, "", "  ")
 		if err != nil {
@@ -125,7 +131,7 @@ func registerDynamicResources(s *server.MCPServer, nomadClient utils.DynamicReso
 
 		job, err := nomadClient.GetJob(ctx, jobID, "default")
 		if err != nil {
-			logger.Printf("Error getting job spec: %v", err)
+			requestLogf(ctx, logger, "Error getting job spec: %v", err)
 			return nil, err
 		}
 
@@ -159,7 +165,7 @@ func registerDynamicResources(s *server.MCPServer, nomadClient utils.DynamicReso
 
 		node, err := nomadClient.GetNode(ctx, nodeID)
 		if err != nil {
-			logger.Printf("Error getting node status: %v", err)
+			requestLogf(ctx, logger, "Error getting node status: %v", err)
 			return nil, err
 		}
 
@@ -194,7 +200,7 @@ func registerDynamicResources(s *server.MCPServer, nomadClient utils.DynamicReso
 		// Get the allocation to find the task name
 		alloc, err := nomadClient.GetAllocation(ctx, allocID)
 		if err != nil {
-			logger.Printf("Error getting allocation: %v", err)
+			requestLogf(ctx, logger, "Error getting allocation: %v", err)
 			return nil, err
 		}
 
@@ -211,7 +217,7 @@ func registerDynamicResources(s *server.MCPServer, nomadClient utils.DynamicReso
 
 		allocLogs, err := nomadClient.GetAllocationLogs(ctx, allocID, taskName, "stderr", false, 100, 0)
 		if err != nil {
-			logger.Printf("Error getting allocation logs: %v", err)
+			requestLogf(ctx, logger, "Error getting allocation logs: %v", err)
 			return nil, err
 		}
 
@@ -224,6 +230,44 @@ func registerDynamicResources(s *server.MCPServer, nomadClient utils.DynamicReso
 		}, nil
 	})
 
+	// Task logs resource
+	taskLogsTemplate := mcp.NewResourceTemplate(
+		"nomad://allocations/{alloc_id}/tasks/{task}/logs{?type}",
+		"Task Logs",
+		mcp.WithTemplateDescription("Returns the last 100 lines of one task's stdout or stderr (type=stdout|stderr, default: stdout)"),
+		mcp.WithTemplateMIMEType("text/plain"),
+	)
+
+	s.AddResourceTemplate(taskLogsTemplate, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		allocID := resourceTemplateArgument(request, "alloc_id")
+		task := resourceTemplateArgument(request, "task")
+		if allocID == "" || task == "" {
+			return nil, fmt.Errorf("invalid allocation ID or task in URI")
+		}
+		logType := resourceTemplateArgument(request, "type")
+		switch logType {
+		case "":
+			logType = "stdout"
+		case "stdout", "stderr":
+		default:
+			return nil, fmt.Errorf("invalid log type %q: must be stdout or stderr", logType)
+		}
+
+		taskLogs, err := nomadClient.GetAllocationLogs(ctx, allocID, task, logType, false, 100, 0)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting task logs: %v", err)
+			return nil, err
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "text/plain",
+				Text:     taskLogs,
+			},
+		}, nil
+	})
+
 	// Job history resource
 	jobHistoryTemplate := mcp.NewResourceTemplate(
 		"nomad://jobs/{job_id}/history",
@@ -241,7 +285,7 @@ func registerDynamicResources(s *server.MCPServer, nomadClient utils.DynamicReso
 		// Get job versions
 		versions, err := nomadClient.GetJobVersions(ctx, jobID, "default")
 		if err != nil {
-			logger.Printf("Error getting job versions: %v", err)
+			requestLogf(ctx, logger, "Error getting job versions: %v", err)
 			return nil, err
 		}
 
@@ -275,7 +319,7 @@ func registerDynamicResources(s *server.MCPServer, nomadClient utils.DynamicReso
 
 		node, err := nomadClient.GetNode(ctx, nodeID)
 		if err != nil {
-			logger.Printf("Error getting node resources: %v", err)
+			requestLogf(ctx, logger, "Error getting node resources: %v", err)
 			return nil, err
 		}
 
@@ -325,7 +369,7 @@ func registerDynamicResources(s *server.MCPServer, nomadClient utils.DynamicReso
 
 		alloc, err := nomadClient.GetAllocation(ctx, allocID)
 		if err != nil {
-			logger.Printf("Error getting allocation status: %v", err)
+			requestLogf(ctx, logger, "Error getting allocation status: %v", err)
 			return nil, err
 		}
 
@@ -345,6 +389,20 @@ func registerDynamicResources(s *server.MCPServer, nomadClient utils.DynamicReso
 }
 
 // extractIDFromURI extracts an ID from a URI using the given prefix and suffix
+// resourceTemplateArgument returns a variable matched from a resource template URI; the
+// server passes each one as a list of values.
+func resourceTemplateArgument(request mcp.ReadResourceRequest, name string) string {
+	switch value := request.Params.Arguments[name].(type) {
+	case string:
+		return value
+	case []string:
+		if len(value) > 0 {
+			return value[0]
+		}
+	}
+	return ""
+}
+
 func extractIDFromURI(uri, prefix, suffix string) string {
 	// Find the start of the ID
 	start := len(prefix)
@@ -0,0 +1,348 @@
+// File: tools/restarts.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxSimulatedEvents bounds a restart simulation so unlimited policies with fast failures
+// terminate; maxTimelineEvents bounds how much of it is returned.
+const (
+	maxSimulatedEvents = 10000
+	maxTimelineEvents  = 50
+)
+
+// RestartPolicySummary is a restart policy with human-readable durations
+type RestartPolicySummary struct {
+	Attempts int    `json:"Attempts"`
+	Interval string `json:"Interval"`
+	Delay    string `json:"Delay"`
+	Mode     string `json:"Mode"`
+	Source   string `json:"Source"` // task, group or default
+}
+
+// ReschedulePolicySummary is a reschedule policy with human-readable durations
+type ReschedulePolicySummary struct {
+	Attempts      int    `json:"Attempts"`
+	Interval      string `json:"Interval"`
+	Delay         string `json:"Delay"`
+	DelayFunction string `json:"DelayFunction"`
+	MaxDelay      string `json:"MaxDelay"`
+	Unlimited     bool   `json:"Unlimited"`
+	Source        string `json:"Source"` // group or default
+}
+
+// RestartTimelineEvent is one step of a restart simulation
+type RestartTimelineEvent struct {
+	At         string `json:"At"` // offset from the first placement
+	Event      string `json:"Event"`
+	Allocation int    `json:"Allocation"` // 1 for the original placement, then one per reschedule
+}
+
+// RestartSimulation is the simulated outcome of one task failing repeatedly
+type RestartSimulation struct {
+	Group      string                   `json:"Group"`
+	Task       string                   `json:"Task"`
+	Restart    RestartPolicySummary     `json:"Restart"`
+	Reschedule *ReschedulePolicySummary `json:"Reschedule,omitempty"`
+	// AllocationFailsAfter is how long one allocation survives before the restart policy
+	// fails it; empty when the policy keeps restarting it on the same node forever.
+	AllocationFailsAfter  string                 `json:"AllocationFailsAfter,omitempty"`
+	RestartsPerAllocation int                    `json:"RestartsPerAllocation"`
+	GivesUp               bool                   `json:"GivesUp"`
+	GivesUpAfter          string                 `json:"GivesUpAfter,omitempty"`
+	Restarts              int                    `json:"Restarts"`
+	Reschedules           int                    `json:"Reschedules"`
+	MaxRecoveryTime       string                 `json:"MaxRecoveryTime"` // longest gap before a restart or reschedule brings the task back
+	DowntimePercent       float64                `json:"DowntimePercent"`
+	Timeline              []RestartTimelineEvent `json:"Timeline"`
+	TimelineTruncated     bool                   `json:"TimelineTruncated,omitempty"`
+	Notes                 []string               `json:"Notes"`
+}
+
+// RegisterRestartPolicyTools registers restart and reschedule policy tools
+func RegisterRestartPolicyTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log.Logger) {
+	// Simulate restart policy tool
+	simulateRestartPolicyTool := mcp.NewTool("simulate_restart_policy",
+		mcp.WithDescription("Simulate a job's restart and reschedule policies against a hypothetical failure rate: when each allocation is failed, when Nomad stops rescheduling, and how long recovery takes. Edit a job_spec to try different policies"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Description("The ID of a registered job to simulate"),
+		),
+		mcp.WithString("job_spec",
+			mcp.Description("A job specification in HCL or JSON format to simulate instead of a registered job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithString("group",
+			mcp.Description("Only simulate this task group (default: all groups)"),
+		),
+		mcp.WithString("failure_interval",
+			mcp.Required(),
+			mcp.Description("How long the task runs before each failure, e.g. 30s or 10m"),
+		),
+		mcp.WithString("horizon",
+			mcp.Description("How far ahead to simulate, e.g. 6h or 7d (default: 24h)"),
+		),
+	)
+	s.AddTool(simulateRestartPolicyTool, SimulateRestartPolicyHandler(nomadClient, logger))
+}
+
+// SimulateRestartPolicyHandler returns a handler for simulating restart and reschedule policies
+func SimulateRestartPolicyHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		if v, _ := arguments["failure_interval"].(string); v == "" {
+			return mcp.NewToolResultError("failure_interval is required"), nil
+		}
+		failureInterval, err := alertDurationArgument(arguments, "failure_interval", 0)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		horizon, err := alertDurationArgument(arguments, "horizon", 24*time.Hour)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		groupName, _ := arguments["group"].(string)
+
+		job, err := jobFromArguments(ctx, client, arguments)
+		if err != nil {
+			requestLogf(ctx, logger, "Error loading job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to load job", err), nil
+		}
+
+		simulations := []RestartSimulation{}
+		for _, tg := range job.TaskGroups {
+			if groupName != "" && tg.Name != groupName {
+				continue
+			}
+			for _, task := range tg.Tasks {
+				simulations = append(simulations, simulateRestartPolicy(job.Type, tg, task, failureInterval, horizon))
+			}
+		}
+		if groupName != "" && len(simulations) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("job %s has no task group %q with tasks", job.ID, groupName)), nil
+		}
+
+		simulationsJSON, err := json.MarshalIndent(simulations, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format restart simulation", err), nil
+		}
+
+		return mcp.NewToolResultText(string(simulationsJSON)), nil
+	}
+}
+
+// effectiveRestartPolicy returns the policy the client applies to a task: the task's own
+// restart block, else the group's, else Nomad's default for the job type.
+func effectiveRestartPolicy(jobType string, tg types.TaskGroup, task types.Task) (types.RestartPolicy, string) {
+	if task.RestartPolicy != nil {
+		return *task.RestartPolicy, "task"
+	}
+	if tg.RestartPolicy != nil {
+		return *tg.RestartPolicy, "group"
+	}
+	if jobType == "batch" || jobType == "sysbatch" {
+		return types.RestartPolicy{Attempts: 3, Interval: int(24 * time.Hour), Delay: int(15 * time.Second), Mode: "fail"}, "default"
+	}
+	return types.RestartPolicy{Attempts: 2, Interval: int(30 * time.Minute), Delay: int(15 * time.Second), Mode: "fail"}, "default"
+}
+
+// effectiveReschedulePolicy returns the group's reschedule policy or Nomad's default for the
+// job type. System jobs are never rescheduled.
+func effectiveReschedulePolicy(jobType string, tg types.TaskGroup) (*types.ReschedulePolicy, string) {
+	switch {
+	case jobType == "system" || jobType == "sysbatch":
+		return nil, ""
+	case tg.ReschedulePolicy != nil:
+		return tg.ReschedulePolicy, "group"
+	case jobType == "batch":
+		return &types.ReschedulePolicy{Attempts: 1, Interval: int(24 * time.Hour), Delay: int(5 * time.Second), DelayFunction: "constant"}, "default"
+	default:
+		return &types.ReschedulePolicy{Delay: int(30 * time.Second), DelayFunction: "exponential", MaxDelay: int(time.Hour), Unlimited: true}, "default"
+	}
+}
+
+// rescheduleDelay is the wait before the nth reschedule (1-based) under the policy's delay
+// function, capped at MaxDelay.
+func rescheduleDelay(policy types.ReschedulePolicy, n int) time.Duration {
+	delay := time.Duration(policy.Delay)
+	switch policy.DelayFunction {
+	case "exponential":
+		for i := 1; i < n && (policy.MaxDelay == 0 || delay < time.Duration(policy.MaxDelay)); i++ {
+			delay *= 2
+		}
+	case "fibonacci":
+		prev := time.Duration(0)
+		for i := 1; i < n && (policy.MaxDelay == 0 || delay < time.Duration(policy.MaxDelay)); i++ {
+			prev, delay = delay, prev+delay
+		}
+	}
+	if policy.MaxDelay > 0 && delay > time.Duration(policy.MaxDelay) {
+		delay = time.Duration(policy.MaxDelay)
+	}
+	return delay
+}
+
+// simulateRestartPolicy replays a task that fails every failureInterval. Within an allocation
+// the client's restart tracker counts failures in the current interval window; once the count
+// exceeds Attempts the allocation fails (mode fail) or waits out the window (mode delay).
+// Failed allocations are rescheduled until the reschedule attempts in the trailing interval
+// are used up. Placement is assumed to be immediate and restart jitter is ignored.
+func simulateRestartPolicy(jobType string, tg types.TaskGroup, task types.Task, failureInterval, horizon time.Duration) RestartSimulation {
+	restart, restartSource := effectiveRestartPolicy(jobType, tg, task)
+	reschedule, rescheduleSource := effectiveReschedulePolicy(jobType, tg)
+
+	sim := RestartSimulation{
+		Group: tg.Name,
+		Task:  task.Name,
+		Restart: RestartPolicySummary{
+			Attempts: restart.Attempts,
+			Interval: prometheusDuration(time.Duration(restart.Interval)),
+			Delay:    prometheusDuration(time.Duration(restart.Delay)),
+			Mode:     restart.Mode,
+			Source:   restartSource,
+		},
+		Timeline: []RestartTimelineEvent{},
+		Notes:    []string{"Restart delays are jittered by up to 25% in Nomad; the simulation uses the configured delay"},
+	}
+	if reschedule != nil {
+		sim.Reschedule = &ReschedulePolicySummary{
+			Attempts:      reschedule.Attempts,
+			Interval:      prometheusDuration(time.Duration(reschedule.Interval)),
+			Delay:         prometheusDuration(time.Duration(reschedule.Delay)),
+			DelayFunction: reschedule.DelayFunction,
+			MaxDelay:      prometheusDuration(time.Duration(reschedule.MaxDelay)),
+			Unlimited:     reschedule.Unlimited,
+			Source:        rescheduleSource,
+		}
+	}
+
+	events := 0
+	record := func(at time.Duration, event string, alloc int) {
+		events++
+		if len(sim.Timeline) < maxTimelineEvents {
+			sim.Timeline = append(sim.Timeline, RestartTimelineEvent{At: prometheusDuration(at), Event: event, Allocation: alloc})
+		} else {
+			sim.TimelineTruncated = true
+		}
+	}
+
+	var (
+		now          time.Duration
+		down         time.Duration
+		maxRecovery  time.Duration
+		rescheduleAt []time.Duration
+		alloc        = 1
+	)
+	markDown := func(from, to time.Duration) {
+		if to > horizon {
+			to = horizon
+		}
+		down += to - from
+		if to-from > maxRecovery {
+			maxRecovery = to - from
+		}
+	}
+
+	for now < horizon && events < maxSimulatedEvents {
+		// Run one allocation until its restart policy fails it or the horizon is reached.
+		placedAt := now
+		windowStart, count, failed := now, 0, false
+		for events < maxSimulatedEvents {
+			now += failureInterval
+			if now >= horizon {
+				break
+			}
+			record(now, "task failed", alloc)
+			if now-windowStart > time.Duration(restart.Interval) {
+				windowStart, count = now, 0
+			}
+			count++
+			if count <= restart.Attempts {
+				sim.Restarts++
+				markDown(now, now+time.Duration(restart.Delay))
+				now += time.Duration(restart.Delay)
+				record(now, "task restarted", alloc)
+				continue
+			}
+			if restart.Mode == "delay" {
+				resume := windowStart + time.Duration(restart.Interval)
+				sim.Restarts++
+				markDown(now, resume)
+				now = resume
+				windowStart, count = now, 0
+				record(now, "task restarted after waiting out the restart interval", alloc)
+				continue
+			}
+			failed = true
+			break
+		}
+		if !failed {
+			break
+		}
+
+		record(now, "allocation failed", alloc)
+		if alloc == 1 {
+			sim.AllocationFailsAfter = prometheusDuration(now - placedAt)
+			sim.RestartsPerAllocation = sim.Restarts
+		}
+		if reschedule == nil {
+			sim.GivesUp, sim.GivesUpAfter = true, prometheusDuration(now)
+			record(now, "gave up: the job type is not rescheduled", alloc)
+			down += horizon - now
+			break
+		}
+		if !reschedule.Unlimited {
+			recent := 0
+			for _, at := range rescheduleAt {
+				if now-at < time.Duration(reschedule.Interval) {
+					recent++
+				}
+			}
+			if recent >= reschedule.Attempts {
+				sim.GivesUp, sim.GivesUpAfter = true, prometheusDuration(now)
+				record(now, fmt.Sprintf("gave up: %d reschedule attempts used within %s", recent, prometheusDuration(time.Duration(reschedule.Interval))), alloc)
+				down += horizon - now
+				break
+			}
+		}
+
+		sim.Reschedules++
+		delay := rescheduleDelay(*reschedule, sim.Reschedules)
+		markDown(now, now+delay)
+		now += delay
+		rescheduleAt = append(rescheduleAt, now)
+		alloc++
+		record(now, "allocation rescheduled", alloc)
+	}
+
+	if events >= maxSimulatedEvents {
+		sim.Notes = append(sim.Notes, fmt.Sprintf("Stopped after %d events before reaching the horizon", maxSimulatedEvents))
+	}
+	if sim.AllocationFailsAfter == "" {
+		sim.RestartsPerAllocation = sim.Restarts
+		sim.Notes = append(sim.Notes, "No allocation failed within the horizon: the restart policy keeps restarting the task on the same node at this failure rate")
+	}
+	if reschedule != nil && reschedule.Unlimited {
+		sim.Notes = append(sim.Notes, "Rescheduling is unlimited, so Nomad never gives up on the allocation")
+	}
+
+	sim.MaxRecoveryTime = prometheusDuration(maxRecovery)
+	sim.DowntimePercent = float64(int(float64(down)*1000/float64(horizon))) / 10
+	return sim
+}
@@ -0,0 +1,156 @@
+// File: tools/retry.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RetriedAllocation is a failed allocation retry_failed_allocations asked Nomad to replace
+type RetriedAllocation struct {
+	ID                string `json:"ID"`
+	Name              string `json:"Name"`
+	TaskGroup         string `json:"TaskGroup"`
+	NodeID            string `json:"NodeID"`
+	ReplacedBy        string `json:"ReplacedBy,omitempty"`
+	ReplacementStatus string `json:"ReplacementStatus,omitempty"`
+}
+
+// FailedAllocationRetry is the result of retry_failed_allocations
+type FailedAllocationRetry struct {
+	JobID       string              `json:"JobID"`
+	Namespace   string              `json:"Namespace"`
+	EvalID      string              `json:"EvalID,omitempty"`
+	Allocations []RetriedAllocation `json:"Allocations"`
+	// Replaced and Running are only counted with monitor
+	Replaced int    `json:"Replaced"`
+	Running  int    `json:"Running"`
+	Summary  string `json:"Summary"`
+	Warnings string `json:"Warnings,omitempty"`
+}
+
+// RetryFailedAllocationsHandler returns a handler that reschedules the failed allocations of a job
+func RetryFailedAllocationsHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+		monitor, _ := arguments["monitor"].(bool)
+		monitorTimeout, err := monitorTimeoutArgument(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		allocs, err := client.ListJobAllocations(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing job allocations: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list job allocations", err), nil
+		}
+
+		retry := FailedAllocationRetry{JobID: jobID, Namespace: namespace, Allocations: []RetriedAllocation{}}
+		for _, alloc := range allocs {
+			if isUnreplacedFailure(alloc) {
+				retry.Allocations = append(retry.Allocations, RetriedAllocation{ID: alloc.ID, Name: alloc.Name, TaskGroup: alloc.TaskGroup, NodeID: alloc.NodeID})
+			}
+		}
+		if len(retry.Allocations) == 0 {
+			retry.Summary = fmt.Sprintf("Job %s has no failed allocations waiting for a replacement; nothing was evaluated.", jobID)
+			return retryResult(retry)
+		}
+
+		eval, err := client.CreateJobEvaluation(ctx, jobID, namespace, true)
+		if err != nil {
+			requestLogf(ctx, logger, "Error evaluating job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to evaluate job", err), nil
+		}
+		retry.EvalID, retry.Warnings = eval.EvalID, eval.Warnings
+		if !monitor {
+			retry.Summary = fmt.Sprintf("Evaluation %s reschedules %d failed allocations; call again with monitor to wait for their replacements.", retry.EvalID, len(retry.Allocations))
+			return retryResult(retry)
+		}
+
+		monitorTimeout = monitorWindow(ctx, monitorTimeout)
+		if err := waitForReplacements(ctx, client, &retry, monitorTimeout); err != nil {
+			requestLogf(ctx, logger, "Error monitoring job allocations: %v", err)
+			return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Evaluation %s was created but the replacements could not be monitored", retry.EvalID), err), nil
+		}
+		switch {
+		case retry.Running == len(retry.Allocations):
+			retry.Summary = fmt.Sprintf("All %d failed allocations were replaced and their replacements are running.", len(retry.Allocations))
+		case retry.Replaced == len(retry.Allocations):
+			retry.Summary = fmt.Sprintf("All %d failed allocations were replaced; %d replacements are running after %s.", len(retry.Allocations), retry.Running, monitorTimeout)
+		default:
+			retry.Summary = fmt.Sprintf("%d of %d failed allocations were replaced after %s; check evaluation %s for placement failures.", retry.Replaced, len(retry.Allocations), monitorTimeout, retry.EvalID)
+		}
+		return retryResult(retry)
+	}
+}
+
+// isUnreplacedFailure reports whether alloc failed and the scheduler has not replaced it.
+func isUnreplacedFailure(alloc types.Allocation) bool {
+	return alloc.ClientStatus == "failed" && alloc.DesiredStatus != "stop" && alloc.NextAllocation == ""
+}
+
+// waitForReplacements polls the job's allocations until every retried allocation has a
+// running replacement, or timeout elapses, and records the replacements in retry.
+func waitForReplacements(ctx context.Context, client utils.JobAPI, retry *FailedAllocationRetry, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		allocs, err := client.ListJobAllocations(ctx, retry.JobID, retry.Namespace)
+		if err != nil {
+			return err
+		}
+		byID := make(map[string]types.Allocation, len(allocs))
+		for _, alloc := range allocs {
+			byID[alloc.ID] = alloc
+		}
+
+		retry.Replaced, retry.Running = 0, 0
+		for i := range retry.Allocations {
+			retried := &retry.Allocations[i]
+			retried.ReplacedBy = byID[retried.ID].NextAllocation
+			retried.ReplacementStatus = ""
+			if retried.ReplacedBy == "" {
+				continue
+			}
+			retry.Replaced++
+			if replacement, ok := byID[retried.ReplacedBy]; ok {
+				retried.ReplacementStatus = replacement.ClientStatus
+				if replacement.ClientStatus == "running" {
+					retry.Running++
+				}
+			}
+		}
+		if retry.Running == len(retry.Allocations) || !time.Now().Before(deadline) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(min(monitorPollInterval, time.Until(deadline))):
+		}
+	}
+}
+
+func retryResult(retry FailedAllocationRetry) (*mcp.CallToolResult, error) {
+	retryJSON, err := json.MarshalIndent(retry, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format retry", err), nil
+	}
+	return withNomadWarnings(mcp.NewToolResultText(string(retryJSON)), retry.Warnings), nil
+}
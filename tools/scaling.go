@@ -0,0 +1,188 @@
+// File: tools/scaling.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// JobScaleStatusReport is the result of get_job_scale_status: the scale status of each task
+// group next to the scaling policies the autoscaler applies to it
+type JobScaleStatusReport struct {
+	types.JobScaleStatus
+	// Policies are the scaling policies of the job, keyed by task group
+	Policies map[string][]types.ScalingPolicy `json:"Policies,omitempty"`
+}
+
+// RegisterScalingTools registers the scaling policy and scale status tools
+func RegisterScalingTools(s *server.MCPServer, nomadClient utils.ScalingAPI, logger *log.Logger) {
+	listScalingPoliciesTool := mcp.NewTool("list_scaling_policies",
+		mcp.WithDescription("List the scaling policies declared by job scaling blocks, with their target job, group and task and whether they are enabled"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace to list policies from (default: default, * for all)"),
+		),
+		mcp.WithString("job_id",
+			mcp.Description("Only list the policies of this job"),
+		),
+		mcp.WithString("type",
+			mcp.Description("Only list policies of this type; vertical policies need Nomad Enterprise"),
+			mcp.Enum("horizontal", "vertical_cpu", "vertical_mem"),
+		),
+	)
+	s.AddTool(listScalingPoliciesTool, ListScalingPoliciesHandler(nomadClient, logger))
+
+	getScalingPolicyTool := mcp.NewTool("get_scaling_policy",
+		mcp.WithDescription("Get a scaling policy with its min and max count and the autoscaler policy (checks, strategy, evaluation interval, cooldown)"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("policy_id",
+			mcp.Required(),
+			mcp.Description("The ID of the scaling policy"),
+		),
+	)
+	s.AddTool(getScalingPolicyTool, GetScalingPolicyHandler(nomadClient, logger))
+
+	getJobScaleStatusTool := mcp.NewTool("get_job_scale_status",
+		mcp.WithDescription("Get the scale status of a job: desired, placed, running and healthy counts and recent scaling events per task group, with the scaling policies that drive the autoscaler for each group"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The ID of the job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+	)
+	s.AddTool(getJobScaleStatusTool, GetJobScaleStatusHandler(nomadClient, logger))
+}
+
+// ListScalingPoliciesHandler returns a handler for listing scaling policies
+func ListScalingPoliciesHandler(client utils.ScalingAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		namespace := utils.EffectiveToolNamespace(arguments)
+		jobID, _ := arguments["job_id"].(string)
+		policyType, _ := arguments["type"].(string)
+
+		policies, err := client.ListScalingPolicies(ctx, namespace, jobID, policyType)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing scaling policies: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list scaling policies", err), nil
+		}
+
+		policiesJSON, err := json.MarshalIndent(policies, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format scaling policies", err), nil
+		}
+
+		return mcp.NewToolResultText(string(policiesJSON)), nil
+	}
+}
+
+// GetScalingPolicyHandler returns a handler for getting a scaling policy
+func GetScalingPolicyHandler(client utils.ScalingAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		policyID, _ := arguments["policy_id"].(string)
+		if policyID == "" {
+			return mcp.NewToolResultError("policy_id is required"), nil
+		}
+
+		policy, err := client.GetScalingPolicy(ctx, policyID)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting scaling policy: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get scaling policy", err), nil
+		}
+
+		policyJSON, err := json.MarshalIndent(policy, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format scaling policy", err), nil
+		}
+
+		return mcp.NewToolResultText(string(policyJSON)), nil
+	}
+}
+
+// GetJobScaleStatusHandler returns a handler for getting a job's scale status with its
+// scaling policies
+func GetJobScaleStatusHandler(client utils.ScalingAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, _ := arguments["job_id"].(string)
+		if jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		status, err := client.GetJobScaleStatus(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting job scale status: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job scale status", err), nil
+		}
+
+		report := JobScaleStatusReport{JobScaleStatus: status}
+		policies, policyErr := jobScalingPolicies(ctx, client, jobID, namespace)
+		if policyErr != nil {
+			requestLogf(ctx, logger, "Error reading scaling policies of job %s: %v", jobID, policyErr)
+		} else if len(policies) > 0 {
+			report.Policies = policies
+		}
+
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format job scale status", err), nil
+		}
+
+		result := mcp.NewToolResultText(string(reportJSON))
+		if policyErr != nil {
+			result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("Could not read the scaling policies of job %s: %v", jobID, policyErr)))
+		}
+		return result, nil
+	}
+}
+
+// jobScalingPolicies reads the full scaling policies of a job, keyed by task group.
+func jobScalingPolicies(ctx context.Context, client utils.ScalingAPI, jobID, namespace string) (map[string][]types.ScalingPolicy, error) {
+	stubs, err := client.ListScalingPolicies(ctx, namespace, jobID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	policies := map[string][]types.ScalingPolicy{}
+	for _, stub := range stubs {
+		// The job filter matches by prefix, so drop policies of other jobs.
+		if stub.Target["Job"] != jobID {
+			continue
+		}
+		policy, err := client.GetScalingPolicy(ctx, stub.ID)
+		if err != nil {
+			return nil, err
+		}
+		group := policy.Target["Group"]
+		policies[group] = append(policies[group], policy)
+	}
+	for _, groupPolicies := range policies {
+		sort.Slice(groupPolicies, func(i, j int) bool { return groupPolicies[i].Type < groupPolicies[j].Type })
+	}
+	return policies, nil
+}
@@ -17,12 +17,14 @@ func RegisterSentinelTools(s *server.MCPServer, client utils.SentinelAPI, logger
 	// List policies tool
 	listPoliciesTool := mcp.NewTool("list_sentinel_policies",
 		mcp.WithDescription("List all Sentinel policies"),
+		mcp.WithReadOnlyHintAnnotation(true),
 	)
 	s.AddTool(listPoliciesTool, ListSentinelPoliciesHandler(client, logger))
 
 	// Get policy tool
 	getPolicyTool := mcp.NewTool("get_sentinel_policy",
 		mcp.WithDescription("Get a specific Sentinel policy by name"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("name",
 			mcp.Required(),
 			mcp.Description("The name of the policy to retrieve"),
@@ -72,7 +74,7 @@ func ListSentinelPoliciesHandler(client utils.SentinelAPI, logger *log.Logger) f
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		policies, err := client.ListSentinelPolicies(ctx)
 		if err != nil {
-			logger.Printf("Error listing Sentinel policies: %v", err)
+			requestLogf(ctx, logger, "Error listing Sentinel policies: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to list Sentinel policies", err), nil
 		}
 
@@ -100,7 +102,7 @@ func GetSentinelPolicyHandler(client utils.SentinelAPI, logger *log.Logger) func
 
 		policy, err := client.GetSentinelPolicy(ctx, name)
 		if err != nil {
-			logger.Printf("Error getting Sentinel policy: %v", err)
+			requestLogf(ctx, logger, "Error getting Sentinel policy: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get Sentinel policy", err), nil
 		}
 
@@ -138,7 +140,7 @@ func CreateSentinelPolicyHandler(client utils.SentinelAPI, logger *log.Logger) f
 
 		err := client.CreateSentinelPolicy(ctx, policy)
 		if err != nil {
-			logger.Printf("Error creating Sentinel policy: %v", err)
+			requestLogf(ctx, logger, "Error creating Sentinel policy: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to create Sentinel policy", err), nil
 		}
 
@@ -170,7 +172,7 @@ func DeleteSentinelPolicyHandler(client utils.SentinelAPI, logger *log.Logger) f
 
 		err := client.DeleteSentinelPolicy(ctx, name)
 		if err != nil {
-			logger.Printf("Error deleting Sentinel policy: %v", err)
+			requestLogf(ctx, logger, "Error deleting Sentinel policy: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to delete Sentinel policy", err), nil
 		}
 
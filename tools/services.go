@@ -0,0 +1,248 @@
+// File: tools/services.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ServiceSummary is a service registered with Nomad service discovery in one namespace
+type ServiceSummary struct {
+	Namespace   string   `json:"Namespace"`
+	ServiceName string   `json:"ServiceName"`
+	Tags        []string `json:"Tags,omitempty"`
+}
+
+// ServiceInstance is a registration of a service with the results of its Nomad checks
+type ServiceInstance struct {
+	types.ServiceRegistration
+	// Health is passing when every check passes, failing when one fails, pending while
+	// checks have not run, and empty when the instance has no checks or they could not be read
+	Health     string                   `json:"Health,omitempty"`
+	Checks     []types.AllocCheckStatus `json:"Checks,omitempty"`
+	CheckError string                   `json:"CheckError,omitempty"`
+}
+
+// ServiceReport is the result of get_service
+type ServiceReport struct {
+	ServiceName string            `json:"ServiceName"`
+	Namespace   string            `json:"Namespace"`
+	Passing     int               `json:"Passing"`
+	Failing     int               `json:"Failing"`
+	Instances   []ServiceInstance `json:"Instances"`
+}
+
+// RegisterServiceTools registers the Nomad service discovery tools
+func RegisterServiceTools(s *server.MCPServer, nomadClient utils.ServiceAPI, logger *log.Logger) {
+	listServicesTool := mcp.NewTool("list_services",
+		mcp.WithDescription("List the services registered with Nomad's built-in service discovery (provider = \"nomad\") and their tags"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace to list services from (default: default, * for all)"),
+		),
+	)
+	s.AddTool(listServicesTool, ListServicesHandler(nomadClient, logger))
+
+	getServiceTool := mcp.NewTool("get_service",
+		mcp.WithDescription("Get the registered instances of a Nomad service (address, port, node, job and allocation) with the latest result of each instance's Nomad service checks"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("service_name",
+			mcp.Required(),
+			mcp.Description("The name of the service"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the service (default: default)"),
+		),
+		mcp.WithBoolean("include_checks",
+			mcp.Description("Read the check results from the client running each instance (default: true)"),
+		),
+	)
+	s.AddTool(getServiceTool, GetServiceHandler(nomadClient, logger))
+
+	deleteServiceRegistrationTool := mcp.NewTool("delete_service_registration",
+		mcp.WithDescription("Delete one registration of a Nomad service, e.g. a stale instance left by a lost client. A running allocation registers its services again"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("service_name",
+			mcp.Required(),
+			mcp.Description("The name of the service"),
+		),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("The ID of the registration, from get_service"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the service (default: default)"),
+		),
+	)
+	s.AddTool(deleteServiceRegistrationTool, DeleteServiceRegistrationHandler(nomadClient, logger))
+}
+
+// ListServicesHandler returns a handler for listing registered services
+func ListServicesHandler(client utils.ServiceAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		namespace := utils.EffectiveToolNamespace(arguments)
+		stubs, err := client.ListServices(ctx, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing services: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list services", err), nil
+		}
+
+		services := []ServiceSummary{}
+		for _, stub := range stubs {
+			for _, service := range stub.Services {
+				services = append(services, ServiceSummary{Namespace: stub.Namespace, ServiceName: service.ServiceName, Tags: service.Tags})
+			}
+		}
+		sort.Slice(services, func(i, j int) bool {
+			if services[i].Namespace != services[j].Namespace {
+				return services[i].Namespace < services[j].Namespace
+			}
+			return services[i].ServiceName < services[j].ServiceName
+		})
+
+		servicesJSON, err := json.MarshalIndent(services, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format services", err), nil
+		}
+
+		return mcp.NewToolResultText(string(servicesJSON)), nil
+	}
+}
+
+// GetServiceHandler returns a handler for getting the instances of a service
+func GetServiceHandler(client utils.ServiceAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		name, _ := arguments["service_name"].(string)
+		if name == "" {
+			return mcp.NewToolResultError("service_name is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+		includeChecks := true
+		if include, ok := arguments["include_checks"].(bool); ok {
+			includeChecks = include
+		}
+
+		registrations, err := client.GetService(ctx, name, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting service: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get service", err), nil
+		}
+		if len(registrations) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("service %s has no registrations in namespace %s", name, namespace)), nil
+		}
+
+		report := ServiceReport{ServiceName: name, Namespace: namespace, Instances: make([]ServiceInstance, 0, len(registrations))}
+		// Instances of one allocation share its check results.
+		allocChecks := map[string][]types.AllocCheckStatus{}
+		allocErrors := map[string]error{}
+		for _, registration := range registrations {
+			instance := ServiceInstance{ServiceRegistration: registration}
+			if includeChecks && registration.AllocID != "" {
+				if _, seen := allocChecks[registration.AllocID]; !seen {
+					allocChecks[registration.AllocID], allocErrors[registration.AllocID] = allocationServiceChecks(ctx, client, registration.AllocID)
+				}
+				if err := allocErrors[registration.AllocID]; err != nil {
+					instance.CheckError = err.Error()
+				}
+				for _, check := range allocChecks[registration.AllocID] {
+					if check.Service == name {
+						instance.Checks = append(instance.Checks, check)
+					}
+				}
+				instance.Health = serviceInstanceHealth(instance.Checks)
+			}
+			switch instance.Health {
+			case "passing":
+				report.Passing++
+			case "failing":
+				report.Failing++
+			}
+			report.Instances = append(report.Instances, instance)
+		}
+		sort.Slice(report.Instances, func(i, j int) bool { return report.Instances[i].ID < report.Instances[j].ID })
+
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format service", err), nil
+		}
+
+		return mcp.NewToolResultText(string(reportJSON)), nil
+	}
+}
+
+// allocationServiceChecks reads the check results of an allocation, ordered by check name.
+func allocationServiceChecks(ctx context.Context, client utils.ServiceAPI, allocID string) ([]types.AllocCheckStatus, error) {
+	results, err := client.GetAllocationChecks(ctx, allocID)
+	if err != nil {
+		return nil, err
+	}
+	checks := make([]types.AllocCheckStatus, 0, len(results))
+	for _, check := range results {
+		checks = append(checks, check)
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Check < checks[j].Check })
+	return checks, nil
+}
+
+// serviceInstanceHealth sums up the check results of an instance.
+func serviceInstanceHealth(checks []types.AllocCheckStatus) string {
+	if len(checks) == 0 {
+		return ""
+	}
+	health := "passing"
+	for _, check := range checks {
+		switch check.Status {
+		case "failure":
+			return "failing"
+		case "success":
+		default:
+			health = "pending"
+		}
+	}
+	return health
+}
+
+// DeleteServiceRegistrationHandler returns a handler for deleting a service registration
+func DeleteServiceRegistrationHandler(client utils.ServiceAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		name, _ := arguments["service_name"].(string)
+		if name == "" {
+			return mcp.NewToolResultError("service_name is required"), nil
+		}
+		id, _ := arguments["id"].(string)
+		if id == "" {
+			return mcp.NewToolResultError("id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		if err := client.DeleteService(ctx, name, id, namespace); err != nil {
+			requestLogf(ctx, logger, "Error deleting service registration: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to delete service registration", err), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Registration %s of service %s deleted successfully", id, name)), nil
+	}
+}
@@ -0,0 +1,171 @@
+// File: tools/session.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SessionDefaults are the values applied to tool calls of one MCP session that omit them
+type SessionDefaults struct {
+	Namespace string `json:"Namespace,omitempty"`
+	Region    string `json:"Region,omitempty"`
+}
+
+// SessionDefaultsStore holds the defaults of every MCP session. Transports without sessions
+// share the defaults stored under the empty session ID.
+type SessionDefaultsStore struct {
+	mu       sync.Mutex
+	sessions map[string]SessionDefaults
+}
+
+// NewSessionDefaultsStore returns an empty store.
+func NewSessionDefaultsStore() *SessionDefaultsStore {
+	return &SessionDefaultsStore{sessions: map[string]SessionDefaults{}}
+}
+
+// Get returns the defaults of the session in ctx.
+func (s *SessionDefaultsStore) Get(ctx context.Context) SessionDefaults {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[sessionID(ctx)]
+}
+
+// Set replaces the defaults of the session in ctx.
+func (s *SessionDefaultsStore) Set(ctx context.Context, defaults SessionDefaults) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if defaults == (SessionDefaults{}) {
+		delete(s.sessions, sessionID(ctx))
+		return
+	}
+	s.sessions[sessionID(ctx)] = defaults
+}
+
+// ForgetSession drops the defaults of session.
+func (s *SessionDefaultsStore) ForgetSession(session string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, session)
+}
+
+func sessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// sessionScopedTools change state of the MCP session only, never of Nomad, so their
+// namespace argument is not a namespace they act in.
+var sessionScopedTools = map[string]bool{"set_session_defaults": true}
+
+// SessionDefaultsMiddleware returns a tool middleware that applies the session's defaults:
+// tools taking a namespace argument get the default namespace when the call does not pass
+// the argument at all (an explicit "" still means the server default), and
+// Nomad API requests are forwarded to the default region (see utils.WithRegion).
+// Register it before SandboxNamespaceMiddleware so the sandbox still has the last word.
+func SessionDefaultsMiddleware(store *SessionDefaultsStore) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			defaults := store.Get(ctx)
+			ctx = utils.WithRegion(ctx, defaults.Region)
+
+			if defaults.Namespace == "" || sessionScopedTools[request.Params.Name] {
+				return next(ctx, request)
+			}
+			arguments, _ := request.Params.Arguments.(map[string]interface{})
+			if _, present := arguments["namespace"]; present {
+				return next(ctx, request)
+			}
+			if srv := server.ServerFromContext(ctx); srv != nil {
+				tool := srv.GetTool(request.Params.Name)
+				if tool == nil {
+					return next(ctx, request)
+				}
+				if _, namespaced := tool.Tool.InputSchema.Properties["namespace"]; !namespaced {
+					return next(ctx, request)
+				}
+			}
+
+			rewritten := make(map[string]interface{}, len(arguments)+1)
+			for k, v := range arguments {
+				rewritten[k] = v
+			}
+			rewritten["namespace"] = defaults.Namespace
+			request.Params.Arguments = rewritten
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// RegisterSessionTools registers the tools that manage per-session defaults
+func RegisterSessionTools(s *server.MCPServer, store *SessionDefaultsStore, logger *log.Logger) {
+	setSessionDefaultsTool := mcp.NewTool("set_session_defaults",
+		mcp.WithDescription("Set the namespace and region used by later tool calls of this session that do not pass them. Omitted fields keep their current value; an empty string clears one"),
+		mcp.WithString("namespace",
+			mcp.Description("Default namespace for tools that take a namespace argument"),
+		),
+		mcp.WithString("region",
+			mcp.Description("Default region Nomad API requests are forwarded to (overrides NOMAD_REGION)"),
+		),
+		mcp.WithBoolean("clear",
+			mcp.Description("Clear all defaults of this session (default: false)"),
+		),
+	)
+	s.AddTool(setSessionDefaultsTool, SetSessionDefaultsHandler(store, logger))
+
+	getSessionDefaultsTool := mcp.NewTool("get_session_defaults",
+		mcp.WithDescription("Get the namespace and region defaults of this session"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+	s.AddTool(getSessionDefaultsTool, GetSessionDefaultsHandler(store, logger))
+}
+
+// SetSessionDefaultsHandler returns a handler that updates the defaults of the calling session
+func SetSessionDefaultsHandler(store *SessionDefaultsStore, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		defaults := store.Get(ctx)
+		if reset, _ := arguments["clear"].(bool); reset {
+			defaults = SessionDefaults{}
+		}
+		if ns, ok := arguments["namespace"].(string); ok {
+			defaults.Namespace = strings.TrimSpace(ns)
+		}
+		if region, ok := arguments["region"].(string); ok {
+			defaults.Region = strings.TrimSpace(region)
+		}
+		store.Set(ctx, defaults)
+		requestLogf(ctx, logger, "Session defaults set: namespace=%q region=%q", defaults.Namespace, defaults.Region)
+
+		return sessionDefaultsResult(defaults)
+	}
+}
+
+// GetSessionDefaultsHandler returns a handler that reports the defaults of the calling session
+func GetSessionDefaultsHandler(store *SessionDefaultsStore, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return sessionDefaultsResult(store.Get(ctx))
+	}
+}
+
+func sessionDefaultsResult(defaults SessionDefaults) (*mcp.CallToolResult, error) {
+	defaultsJSON, err := json.MarshalIndent(defaults, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format session defaults", err), nil
+	}
+	return mcp.NewToolResultText(string(defaultsJSON)), nil
+}
@@ -0,0 +1,149 @@
+// File: tools/session_gc.go
+package tools
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SessionState is per-session state held by a middleware or tool, dropped when its session
+// goes away.
+type SessionState interface {
+	ForgetSession(session string)
+}
+
+type reaperSession struct {
+	lastActive time.Time
+	calls      map[uint64]context.CancelFunc
+}
+
+// SessionReaper tracks the in-flight tool calls of every MCP session, such as event
+// subscriptions, log follows and rollout pollers. When a session is unregistered (the client
+// disconnected or the transport expired it) its calls are cancelled and its state is
+// dropped; sessions idle for longer than the idle timeout lose their state too.
+type SessionReaper struct {
+	idleTimeout time.Duration
+	states      []SessionState
+	logger      *log.Logger
+	mu          sync.Mutex
+	sessions    map[string]*reaperSession
+	nextCall    uint64
+}
+
+// NewSessionReaper returns a reaper dropping states of ended sessions. An idleTimeout of 0
+// only reaps sessions when they are unregistered.
+func NewSessionReaper(idleTimeout time.Duration, logger *log.Logger, states ...SessionState) *SessionReaper {
+	return &SessionReaper{idleTimeout: idleTimeout, states: states, logger: logger, sessions: map[string]*reaperSession{}}
+}
+
+// begin registers a call of session and returns the context it must run with and a func
+// ending it.
+func (r *SessionReaper) begin(ctx context.Context, session string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry := r.sessions[session]
+	if entry == nil {
+		entry = &reaperSession{calls: map[uint64]context.CancelFunc{}}
+		r.sessions[session] = entry
+	}
+	entry.lastActive = time.Now()
+	r.nextCall++
+	id := r.nextCall
+	entry.calls[id] = cancel
+
+	return ctx, func() {
+		cancel()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if entry, ok := r.sessions[session]; ok {
+			delete(entry.calls, id)
+			entry.lastActive = time.Now()
+		}
+	}
+}
+
+// Reap cancels the in-flight calls of session and drops its state.
+func (r *SessionReaper) Reap(session string) {
+	r.mu.Lock()
+	entry := r.sessions[session]
+	delete(r.sessions, session)
+	r.mu.Unlock()
+
+	if entry != nil {
+		for _, cancel := range entry.calls {
+			cancel()
+		}
+		if len(entry.calls) > 0 {
+			r.logger.Printf("Session %s ended: cancelled %d running tool calls", session, len(entry.calls))
+		}
+	}
+	for _, state := range r.states {
+		state.ForgetSession(session)
+	}
+}
+
+// reapIdle drops the state of sessions without running calls that have been idle for longer
+// than the idle timeout.
+func (r *SessionReaper) reapIdle(now time.Time) {
+	r.mu.Lock()
+	var idle []string
+	for session, entry := range r.sessions {
+		if len(entry.calls) == 0 && now.Sub(entry.lastActive) > r.idleTimeout {
+			idle = append(idle, session)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, session := range idle {
+		r.Reap(session)
+	}
+}
+
+// Run sweeps idle sessions until ctx is done. It returns immediately without an idle
+// timeout.
+func (r *SessionReaper) Run(ctx context.Context) {
+	if r.idleTimeout <= 0 {
+		return
+	}
+	ticker := time.NewTicker(max(r.idleTimeout/2, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.reapIdle(now)
+		}
+	}
+}
+
+// Hooks returns server hooks that reap a session as soon as it is unregistered.
+func (r *SessionReaper) Hooks() *server.Hooks {
+	hooks := &server.Hooks{}
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		r.Reap(session.SessionID())
+	})
+	return hooks
+}
+
+// SessionReaperMiddleware returns a tool middleware that runs every call of a session under
+// a context the reaper cancels when the session ends.
+func SessionReaperMiddleware(reaper *SessionReaper) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			session := sessionID(ctx)
+			if session == "" {
+				return next(ctx, request)
+			}
+			ctx, end := reaper.begin(ctx, session)
+			defer end()
+			return next(ctx, request)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// File: tools/since.go
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// dayWeekUnits matches the d and w units time.ParseDuration does not know about.
+var dayWeekUnits = regexp.MustCompile(`(\d+(?:\.\d+)?)([dw])`)
+
+// parseRelativeDuration parses a non-negative Go duration that may also use d (24h) and
+// w (7d) units, e.g. 90m, 2h, 7d or 1d12h.
+func parseRelativeDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	expanded := dayWeekUnits.ReplaceAllStringFunc(value, func(m string) string {
+		n, _ := strconv.ParseFloat(m[:len(m)-1], 64)
+		hours := 24.0
+		if m[len(m)-1] == 'w' {
+			hours = 7 * 24
+		}
+		return strconv.FormatFloat(n*hours, 'f', -1, 64) + "h"
+	})
+
+	d, err := time.ParseDuration(expanded)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q: use a value such as 30m, 2h or 7d", value)
+	}
+	return d, nil
+}
+
+// durationArgument parses an optional duration argument such as 30s, 2h or 7d, falling back to
+// def when it is absent.
+func durationArgument(arguments map[string]interface{}, name string, def time.Duration) (time.Duration, error) {
+	value, _ := arguments[name].(string)
+	if strings.TrimSpace(value) == "" {
+		return def, nil
+	}
+	d, err := parseRelativeDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a duration such as 30s or 2m, got %q", name, value)
+	}
+	return d, nil
+}
+
+// sinceOption adds the since argument to list tools.
+func sinceOption(what string) mcp.ToolOption {
+	return mcp.WithString("since",
+		mcp.Description(fmt.Sprintf("Only return %s from this long ago until now, e.g. 30m, 2h or 7d", what)),
+	)
+}
+
+// sinceArgument returns the cutoff time for the since argument, or the zero time when it is
+// not set.
+func sinceArgument(arguments map[string]interface{}) (time.Time, error) {
+	since, _ := arguments["since"].(string)
+	if strings.TrimSpace(since) == "" {
+		return time.Time{}, nil
+	}
+	d, err := parseRelativeDuration(since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("since: %v", err)
+	}
+	return time.Now().Add(-d), nil
+}
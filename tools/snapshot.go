@@ -0,0 +1,185 @@
+// File: tools/snapshot.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SnapshotFile is the result of snapshot_save and snapshot_restore
+type SnapshotFile struct {
+	Path  string `json:"Path"`
+	Bytes int64  `json:"Bytes"`
+}
+
+// RegisterSnapshotTools registers the tools that save and restore cluster snapshots. Snapshot
+// files live in dir: paths are resolved inside it and cannot leave it.
+func RegisterSnapshotTools(s *server.MCPServer, nomadClient utils.SnapshotAPI, dir string, logger *log.Logger) {
+	snapshotSaveTool := mcp.NewTool("snapshot_save",
+		mcp.WithDescription("Save a snapshot of the cluster state (jobs, allocations, ACL tokens, variables, ...) to a file in the server's snapshot directory. The file holds secrets and is created readable by the owner only"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("File to write, relative to the snapshot directory, e.g. backups/nomad.snap"),
+		),
+		mcp.WithBoolean("stale",
+			mcp.Description("Let any server take the snapshot instead of only the leader, e.g. when there is no leader (default: false)"),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("Replace the file when it already exists (default: false)"),
+		),
+	)
+	s.AddTool(snapshotSaveTool, SnapshotSaveHandler(nomadClient, dir, logger))
+
+	snapshotRestoreTool := mcp.NewTool("snapshot_restore",
+		mcp.WithDescription("Restore the cluster state from a snapshot file in the server's snapshot directory. Dangerous: every change made since the snapshot was taken is lost"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Snapshot file to read, relative to the snapshot directory"),
+		),
+		mcp.WithString("confirm",
+			mcp.Required(),
+			mcp.Description("Must repeat path exactly, to confirm the restore"),
+		),
+	)
+	s.AddTool(snapshotRestoreTool, SnapshotRestoreHandler(nomadClient, dir, logger))
+}
+
+// snapshotPath resolves path to a name inside dir, for use with an os.Root of dir.
+func snapshotPath(dir, path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return "", fmt.Errorf("path must be inside the snapshot directory %s", dir)
+		}
+		path = rel
+	}
+	path = filepath.Clean(path)
+	if !filepath.IsLocal(path) {
+		return "", fmt.Errorf("path must be inside the snapshot directory %s", dir)
+	}
+	return path, nil
+}
+
+// SnapshotSaveHandler returns a handler for saving a cluster snapshot to a file
+func SnapshotSaveHandler(client utils.SnapshotAPI, dir string, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		argPath, _ := arguments["path"].(string)
+		path, err := snapshotPath(dir, argPath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		stale, _ := arguments["stale"].(bool)
+		overwrite, _ := arguments["overwrite"].(bool)
+
+		root, err := os.OpenRoot(dir)
+		if err != nil {
+			requestLogf(ctx, logger, "Error opening snapshot directory: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to open snapshot directory", err), nil
+		}
+		defer root.Close()
+
+		if _, err := root.Stat(path); err == nil && !overwrite {
+			return mcp.NewToolResultError(fmt.Sprintf("%s already exists; pass overwrite=true to replace it", argPath)), nil
+		}
+		if err := root.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to create snapshot directory", err), nil
+		}
+
+		// Write next to the target and rename, so a failed save never leaves a truncated snapshot.
+		partial := path + ".partial"
+		file, err := root.OpenFile(partial, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to create snapshot file", err), nil
+		}
+		size, err := client.SaveSnapshot(ctx, file, stale)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = root.Remove(partial)
+			requestLogf(ctx, logger, "Error saving snapshot: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to save snapshot", err), nil
+		}
+		if err := root.Rename(partial, path); err != nil {
+			_ = root.Remove(partial)
+			return mcp.NewToolResultErrorFromErr("Failed to save snapshot", err), nil
+		}
+
+		snapshotJSON, err := json.MarshalIndent(SnapshotFile{Path: filepath.Join(dir, path), Bytes: size}, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format snapshot", err), nil
+		}
+
+		return mcp.NewToolResultText(string(snapshotJSON)), nil
+	}
+}
+
+// SnapshotRestoreHandler returns a handler for restoring the cluster state from a file
+func SnapshotRestoreHandler(client utils.SnapshotAPI, dir string, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		argPath, _ := arguments["path"].(string)
+		path, err := snapshotPath(dir, argPath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if confirm, _ := arguments["confirm"].(string); confirm != argPath {
+			return mcp.NewToolResultError(fmt.Sprintf("confirm must repeat %q exactly", argPath)), nil
+		}
+
+		root, err := os.OpenRoot(dir)
+		if err != nil {
+			requestLogf(ctx, logger, "Error opening snapshot directory: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to open snapshot directory", err), nil
+		}
+		defer root.Close()
+
+		file, err := root.Open(path)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to open snapshot file", err), nil
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to open snapshot file", err), nil
+		}
+		if info.IsDir() {
+			return mcp.NewToolResultError(fmt.Sprintf("%s is a directory", argPath)), nil
+		}
+
+		if err := client.RestoreSnapshot(ctx, file); err != nil {
+			requestLogf(ctx, logger, "Error restoring snapshot: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to restore snapshot", err), nil
+		}
+
+		snapshotJSON, err := json.MarshalIndent(SnapshotFile{Path: filepath.Join(dir, path), Bytes: info.Size()}, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format snapshot", err), nil
+		}
+
+		return mcp.NewToolResultText(string(snapshotJSON)), nil
+	}
+}
@@ -0,0 +1,288 @@
+// File: tools/spread.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SpreadBucket is the share of a group's allocations with one attribute value
+type SpreadBucket struct {
+	Value         string   `json:"Value"`
+	Allocations   int      `json:"Allocations"`
+	Percent       float64  `json:"Percent"`
+	TargetPercent *float64 `json:"TargetPercent,omitempty"`
+}
+
+// SpreadAnalysis compares one spread block with the actual distribution
+type SpreadAnalysis struct {
+	Attribute    string         `json:"Attribute"`
+	Weight       int            `json:"Weight"`
+	Source       string         `json:"Source"` // job or group
+	Buckets      []SpreadBucket `json:"Buckets"`
+	MaxDeviation float64        `json:"MaxDeviation"` // percentage points
+	Skewed       bool           `json:"Skewed"`
+}
+
+// AffinityAnalysis reports how many allocations landed on nodes matching an affinity
+type AffinityAnalysis struct {
+	Affinity        string  `json:"Affinity"`
+	Weight          int     `json:"Weight"`
+	Matching        int     `json:"Matching"`
+	MatchingPercent float64 `json:"MatchingPercent"`
+	MatchingNodes   int     `json:"MatchingNodes"` // eligible nodes that match
+}
+
+// GroupSpread is the spread analysis of one task group
+type GroupSpread struct {
+	Group        string             `json:"Group"`
+	Allocations  int                `json:"Allocations"`
+	ByDatacenter []SpreadBucket     `json:"ByDatacenter"`
+	ByNodeClass  []SpreadBucket     `json:"ByNodeClass"`
+	ByNode       []SpreadBucket     `json:"ByNode"`
+	Spreads      []SpreadAnalysis   `json:"Spreads"`
+	Affinities   []AffinityAnalysis `json:"Affinities"`
+	Issues       []string           `json:"Issues"`
+}
+
+// JobSpreadReport is the analyze_job_spread response
+type JobSpreadReport struct {
+	JobID  string        `json:"JobID"`
+	Groups []GroupSpread `json:"Groups"`
+}
+
+// AnalyzeJobSpreadHandler returns a handler comparing a job's allocation distribution with
+// its spread and affinity blocks
+func AnalyzeJobSpreadHandler(client utils.PlacementAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		jobID, ok := arguments["job_id"].(string)
+		if !ok || jobID == "" {
+			return mcp.NewToolResultError("job_id is required"), nil
+		}
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		threshold := 20.0
+		if t, ok := arguments["skew_threshold"].(float64); ok {
+			if t <= 0 || t > 100 {
+				return mcp.NewToolResultError("skew_threshold must be between 0 and 100"), nil
+			}
+			threshold = t
+		}
+
+		job, err := client.GetJob(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error getting job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to get job", err), nil
+		}
+
+		allocs, err := client.ListJobAllocations(ctx, jobID, namespace)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing job allocations: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list job allocations", err), nil
+		}
+
+		nodes, err := fetchNodeDetails(ctx, client)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing nodes: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list nodes", err), nil
+		}
+
+		reportJSON, err := json.MarshalIndent(analyzeJobSpread(job, allocs, nodes, threshold), "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format spread analysis", err), nil
+		}
+
+		return mcp.NewToolResultText(string(reportJSON)), nil
+	}
+}
+
+func analyzeJobSpread(job types.Job, allocs []types.Allocation, nodes []types.NodeDetail, threshold float64) JobSpreadReport {
+	report := JobSpreadReport{JobID: job.ID, Groups: []GroupSpread{}}
+
+	nodesByID := map[string]types.NodeDetail{}
+	for _, node := range nodes {
+		nodesByID[node.ID] = node
+	}
+	eligible := map[string][]types.NodeDetail{}
+	for _, match := range matchJobNodes(job, nodes).Eligible {
+		for _, group := range match.Groups {
+			eligible[group] = append(eligible[group], nodesByID[match.NodeID])
+		}
+	}
+
+	for _, tg := range job.TaskGroups {
+		var placed []types.NodeDetail
+		for _, alloc := range allocs {
+			if alloc.TaskGroup != tg.Name || !isLiveAllocation(alloc) {
+				continue
+			}
+			node, ok := nodesByID[alloc.NodeID]
+			if !ok {
+				node = types.NodeDetail{ID: alloc.NodeID, Name: alloc.NodeID}
+			}
+			placed = append(placed, node)
+		}
+
+		group := GroupSpread{
+			Group:        tg.Name,
+			Allocations:  len(placed),
+			ByDatacenter: spreadBuckets(placed, func(n types.NodeDetail) string { return n.Datacenter }),
+			ByNodeClass:  spreadBuckets(placed, func(n types.NodeDetail) string { return n.NodeClass }),
+			ByNode:       spreadBuckets(placed, func(n types.NodeDetail) string { return n.Name }),
+			Spreads:      []SpreadAnalysis{},
+			Affinities:   []AffinityAnalysis{},
+			Issues:       []string{},
+		}
+
+		for _, spread := range job.Spreads {
+			group.Spreads = append(group.Spreads, analyzeSpread(spread, "job", placed, eligible[tg.Name], threshold))
+		}
+		for _, spread := range tg.Spreads {
+			group.Spreads = append(group.Spreads, analyzeSpread(spread, "group", placed, eligible[tg.Name], threshold))
+		}
+		for _, spread := range group.Spreads {
+			if spread.Skewed {
+				group.Issues = append(group.Issues, fmt.Sprintf("spread on %s deviates from its target by %.0f percentage points", spread.Attribute, spread.MaxDeviation))
+			}
+		}
+
+		for _, affinity := range append(append([]types.Affinity{}, job.Affinities...), tg.Affinities...) {
+			analysis := analyzeAffinity(affinity, placed, eligible[tg.Name])
+			group.Affinities = append(group.Affinities, analysis)
+			switch {
+			case analysis.MatchingNodes == 0:
+				group.Issues = append(group.Issues, fmt.Sprintf("no eligible node matches affinity %s", analysis.Affinity))
+			case affinity.Weight > 0 && len(placed) > 0 && analysis.MatchingPercent < 50:
+				group.Issues = append(group.Issues, fmt.Sprintf("only %.0f%% of allocations run on nodes matching affinity %s", analysis.MatchingPercent, analysis.Affinity))
+			case affinity.Weight < 0 && analysis.Matching > 0 && analysis.MatchingNodes < len(eligible[tg.Name]):
+				group.Issues = append(group.Issues, fmt.Sprintf("%d allocations run on nodes matching anti-affinity %s although other nodes are eligible", analysis.Matching, analysis.Affinity))
+			}
+		}
+
+		if len(placed) >= 2 && len(group.ByNode) > 0 && group.ByNode[0].Percent > 50 && len(eligible[tg.Name]) > 1 {
+			group.Issues = append(group.Issues, fmt.Sprintf("%d of %d allocations run on node %s; losing it takes down most of the group", group.ByNode[0].Allocations, len(placed), group.ByNode[0].Value))
+		}
+
+		report.Groups = append(report.Groups, group)
+	}
+	return report
+}
+
+// spreadBuckets counts nodes by key, largest bucket first.
+func spreadBuckets(nodes []types.NodeDetail, key func(types.NodeDetail) string) []SpreadBucket {
+	counts := map[string]int{}
+	for _, node := range nodes {
+		counts[key(node)]++
+	}
+	buckets := make([]SpreadBucket, 0, len(counts))
+	for value, count := range counts {
+		buckets = append(buckets, SpreadBucket{Value: value, Allocations: count, Percent: percentOf(count, len(nodes))})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Allocations != buckets[j].Allocations {
+			return buckets[i].Allocations > buckets[j].Allocations
+		}
+		return buckets[i].Value < buckets[j].Value
+	})
+	return buckets
+}
+
+// analyzeSpread compares the placed distribution of a spread attribute with its targets.
+// Without targets the scheduler aims for an even split across the values of eligible nodes;
+// values not named by targets share whatever percentage the targets leave.
+func analyzeSpread(spread types.Spread, source string, placed, eligible []types.NodeDetail, threshold float64) SpreadAnalysis {
+	analysis := SpreadAnalysis{Attribute: spread.Attribute, Weight: spread.Weight, Source: source}
+
+	value := func(node types.NodeDetail) string {
+		v, _ := resolveNodeTarget(spread.Attribute, node)
+		return v
+	}
+	buckets := spreadBuckets(placed, value)
+
+	values := map[string]bool{}
+	for _, node := range eligible {
+		values[value(node)] = true
+	}
+	for _, bucket := range buckets {
+		values[bucket.Value] = true
+	}
+
+	targets := map[string]float64{}
+	remaining := 100.0
+	for _, target := range spread.SpreadTarget {
+		targets[target.Value] = float64(target.Percent)
+		remaining -= float64(target.Percent)
+	}
+	var untargeted []string
+	for v := range values {
+		if _, ok := targets[v]; !ok {
+			untargeted = append(untargeted, v)
+		}
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	for _, v := range untargeted {
+		targets[v] = remaining / float64(len(untargeted))
+	}
+
+	present := map[string]bool{}
+	for _, bucket := range buckets {
+		present[bucket.Value] = true
+	}
+	for v := range targets {
+		if !present[v] {
+			buckets = append(buckets, SpreadBucket{Value: v})
+		}
+	}
+	for i := range buckets {
+		target := math.Round(targets[buckets[i].Value]*10) / 10
+		buckets[i].TargetPercent = &target
+		if len(placed) > 0 {
+			analysis.MaxDeviation = math.Max(analysis.MaxDeviation, math.Abs(buckets[i].Percent-target))
+		}
+	}
+	sort.SliceStable(buckets, func(i, j int) bool { return buckets[i].Allocations > buckets[j].Allocations })
+
+	analysis.Buckets = buckets
+	analysis.MaxDeviation = math.Round(analysis.MaxDeviation*10) / 10
+	analysis.Skewed = analysis.MaxDeviation > threshold
+	return analysis
+}
+
+func analyzeAffinity(affinity types.Affinity, placed, eligible []types.NodeDetail) AffinityAnalysis {
+	constraint := types.Constraint{LTarget: affinity.LTarget, RTarget: affinity.RTarget, Operand: affinity.Operand}
+	analysis := AffinityAnalysis{Affinity: describeConstraint(constraint), Weight: affinity.Weight}
+	for _, node := range placed {
+		if constraintMatches(constraint, node) {
+			analysis.Matching++
+		}
+	}
+	for _, node := range eligible {
+		if constraintMatches(constraint, node) {
+			analysis.MatchingNodes++
+		}
+	}
+	analysis.MatchingPercent = percentOf(analysis.Matching, len(placed))
+	return analysis
+}
+
+func percentOf(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
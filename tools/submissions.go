@@ -0,0 +1,77 @@
+// File: tools/submissions.go
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/kocierik/mcp-nomad/utils"
+)
+
+// JobSubmissionLocks serializes run_job calls per namespace/job so concurrent agents
+// submitting the same job through this server queue behind each other instead of racing.
+type JobSubmissionLocks struct {
+	mu    sync.Mutex
+	locks map[string]*jobSubmissionLock
+}
+
+type jobSubmissionLock struct {
+	slot  chan struct{}
+	users int
+}
+
+// NewJobSubmissionLocks returns an empty set of per-job submission locks
+func NewJobSubmissionLocks() *JobSubmissionLocks {
+	return &JobSubmissionLocks{locks: map[string]*jobSubmissionLock{}}
+}
+
+// Lock waits until no other submission holds namespace/jobID, or ctx is done. The returned
+// function releases the lock.
+func (l *JobSubmissionLocks) Lock(ctx context.Context, namespace, jobID string) (func(), error) {
+	key := namespace + "/" + jobID
+
+	l.mu.Lock()
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &jobSubmissionLock{slot: make(chan struct{}, 1)}
+		l.locks[key] = lock
+	}
+	lock.users++
+	l.mu.Unlock()
+
+	select {
+	case lock.slot <- struct{}{}:
+		return func() {
+			<-lock.slot
+			l.release(key, lock)
+		}, nil
+	case <-ctx.Done():
+		l.release(key, lock)
+		return nil, ctx.Err()
+	}
+}
+
+// release drops a waiter or holder and forgets the key once nobody uses it.
+func (l *JobSubmissionLocks) release(key string, lock *jobSubmissionLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock.users--
+	if lock.users == 0 {
+		delete(l.locks, key)
+	}
+}
+
+// isJobModifyIndexConflict reports whether Nomad rejected a registration because the job
+// changed since the index it was submitted against.
+func isJobModifyIndexConflict(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "conflicting job modify index")
+}
+
+// isNotFound reports whether err is a Nomad 404.
+func isNotFound(err error) bool {
+	var httpErr *utils.NomadHTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,215 @@
+// File: tools/surface.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+var (
+	// templateReference matches consul-template and Nomad template functions that read
+	// external configuration, e.g. {{ with nomadVar "nomad/jobs/web" }}.
+	templateReference = regexp.MustCompile(`\b(nomadVarListSafe|nomadVarList|nomadVarExists|nomadVar|secrets|secret|keyOrDefault|keyExists|key|safeLs|ls|safeTree|tree|nomadService|service)\s+"([^"]*)"`)
+
+	// templateEnvLine matches KEY=value lines rendered by env templates, including lines that
+	// open with template actions such as {{ with nomadVar "..." }}KEY={{ .value }}.
+	templateEnvLine = regexp.MustCompile(`(?m)^\s*(?:\{\{[^}]*\}\}\s*)*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=`)
+)
+
+// EnvVarSurface is one environment variable a task receives
+type EnvVarSurface struct {
+	Name   string `json:"Name"`
+	Value  string `json:"Value,omitempty"`
+	Source string `json:"Source"` // env, or the destination of the env template
+}
+
+// TemplateSurface is one template block and the configuration it reads
+type TemplateSurface struct {
+	DestPath       string   `json:"DestPath"`
+	SourcePath     string   `json:"SourcePath,omitempty"`
+	ChangeMode     string   `json:"ChangeMode,omitempty"`
+	Envvars        bool     `json:"Envvars,omitempty"`
+	NomadVariables []string `json:"NomadVariables,omitempty"`
+	VaultSecrets   []string `json:"VaultSecrets,omitempty"`
+	ConsulKeys     []string `json:"ConsulKeys,omitempty"`
+	Services       []string `json:"Services,omitempty"`
+}
+
+// TaskConfigSurface is the configuration surface of one task
+type TaskConfigSurface struct {
+	Group         string            `json:"Group"`
+	Task          string            `json:"Task"`
+	Env           []EnvVarSurface   `json:"Env"`
+	Templates     []TemplateSurface `json:"Templates"`
+	VaultPolicies []string          `json:"VaultPolicies,omitempty"`
+	VaultRole     string            `json:"VaultRole,omitempty"`
+}
+
+// JobConfigSurface is the list_job_config_surface response
+type JobConfigSurface struct {
+	JobID          string              `json:"JobID"`
+	Tasks          []TaskConfigSurface `json:"Tasks"`
+	NomadVariables []string            `json:"NomadVariables"`
+	VaultPolicies  []string            `json:"VaultPolicies"`
+	VaultSecrets   []string            `json:"VaultSecrets"`
+	ConsulKeys     []string            `json:"ConsulKeys"`
+}
+
+// RegisterConfigSurfaceTools registers tools that inventory a job's configuration inputs
+func RegisterConfigSurfaceTools(s *server.MCPServer, nomadClient utils.JobAPI, logger *log.Logger) {
+	// List job config surface tool
+	listJobConfigSurfaceTool := mcp.NewTool("list_job_config_surface",
+		mcp.WithDescription("Inventory a job's configuration surface per task: environment variables, template destinations, Vault policies and secrets, Nomad variable and Consul key references read by templates"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Description("The ID of a registered job to inspect"),
+		),
+		mcp.WithString("job_spec",
+			mcp.Description("A job specification in HCL or JSON format to inspect instead of a registered job"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace of the job (default: default)"),
+		),
+		mcp.WithBoolean("include_values",
+			mcp.Description("Include environment variable values, which may contain secrets (default: false)"),
+		),
+	)
+	s.AddTool(listJobConfigSurfaceTool, ListJobConfigSurfaceHandler(nomadClient, logger))
+}
+
+// ListJobConfigSurfaceHandler returns a handler for inventorying a job's configuration surface
+func ListJobConfigSurfaceHandler(client utils.JobAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		includeValues, _ := arguments["include_values"].(bool)
+
+		job, err := jobFromArguments(ctx, client, arguments)
+		if err != nil {
+			requestLogf(ctx, logger, "Error loading job: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to load job", err), nil
+		}
+
+		surfaceJSON, err := json.MarshalIndent(jobConfigSurface(job, includeValues), "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format configuration surface", err), nil
+		}
+
+		return mcp.NewToolResultText(string(surfaceJSON)), nil
+	}
+}
+
+func jobConfigSurface(job types.Job, includeValues bool) JobConfigSurface {
+	surface := JobConfigSurface{JobID: job.ID, Tasks: []TaskConfigSurface{}}
+	variables, policies, secrets, keys := map[string]bool{}, map[string]bool{}, map[string]bool{}, map[string]bool{}
+
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			taskSurface := TaskConfigSurface{Group: tg.Name, Task: task.Name, Env: []EnvVarSurface{}, Templates: []TemplateSurface{}}
+
+			for _, name := range sortedKeys(task.Env) {
+				env := EnvVarSurface{Name: name, Source: "env"}
+				if includeValues {
+					env.Value = task.Env[name]
+				}
+				taskSurface.Env = append(taskSurface.Env, env)
+			}
+
+			for _, tmpl := range task.Templates {
+				templateSurface := templateConfigSurface(tmpl)
+				taskSurface.Templates = append(taskSurface.Templates, templateSurface)
+				if tmpl.Envvars {
+					for _, match := range templateEnvLine.FindAllStringSubmatch(tmpl.EmbeddedTmpl, -1) {
+						taskSurface.Env = append(taskSurface.Env, EnvVarSurface{Name: match[1], Source: tmpl.DestPath})
+					}
+				}
+				for _, v := range templateSurface.NomadVariables {
+					variables[v] = true
+				}
+				for _, v := range templateSurface.VaultSecrets {
+					secrets[v] = true
+				}
+				for _, v := range templateSurface.ConsulKeys {
+					keys[v] = true
+				}
+			}
+
+			if task.Vault != nil {
+				taskSurface.VaultPolicies = task.Vault.Policies
+				taskSurface.VaultRole = task.Vault.Role
+				for _, policy := range task.Vault.Policies {
+					policies[policy] = true
+				}
+			}
+
+			surface.Tasks = append(surface.Tasks, taskSurface)
+		}
+	}
+
+	surface.NomadVariables = sortedSet(variables)
+	surface.VaultPolicies = sortedSet(policies)
+	surface.VaultSecrets = sortedSet(secrets)
+	surface.ConsulKeys = sortedSet(keys)
+	return surface
+}
+
+// templateConfigSurface lists the external paths a template reads.
+func templateConfigSurface(tmpl types.Template) TemplateSurface {
+	surface := TemplateSurface{
+		DestPath:   tmpl.DestPath,
+		SourcePath: tmpl.SourcePath,
+		ChangeMode: tmpl.ChangeMode,
+		Envvars:    tmpl.Envvars,
+	}
+
+	seen := map[string]bool{}
+	for _, match := range templateReference.FindAllStringSubmatch(tmpl.EmbeddedTmpl, -1) {
+		function, path := match[1], match[2]
+		if seen[function+" "+path] {
+			continue
+		}
+		seen[function+" "+path] = true
+
+		switch {
+		case strings.HasPrefix(function, "nomadVar"):
+			surface.NomadVariables = append(surface.NomadVariables, path)
+		case function == "secret" || function == "secrets":
+			surface.VaultSecrets = append(surface.VaultSecrets, path)
+		case function == "service" || function == "nomadService":
+			surface.Services = append(surface.Services, path)
+		default:
+			surface.ConsulKeys = append(surface.ConsulKeys, path)
+		}
+	}
+	return surface
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedSet(set map[string]bool) []string {
+	values := make([]string, 0, len(set))
+	for v := range set {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,206 @@
+// File: tools/undo.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// UndoResult is the result of undo_operation
+type UndoResult struct {
+	OperationID string           `json:"OperationID"`
+	Tool        string           `json:"Tool"`
+	Target      *OperationTarget `json:"Target"`
+	Actions     []string         `json:"Actions"`
+}
+
+// undoOperationTool describes undo_operation; it is registered with the journal tools.
+func undoOperationTool() mcp.Tool {
+	return mcp.NewTool("undo_operation",
+		mcp.WithDescription("Revert an operation recorded in the operation journal: a job change is reverted to the job version before it, a node's drain and scheduling eligibility are restored, and a deleted variable is recreated from its journaled copy. Only the caller's own operations can be undone. Refuses when the target changed since the operation unless force=true"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("operation_id",
+			mcp.Required(),
+			mcp.Description("The ID of the operation, from list_recent_operations"),
+		),
+		mcp.WithString("confirm",
+			mcp.Required(),
+			mcp.Description("Must repeat operation_id exactly, to confirm the undo"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Revert a job even if it was changed again after the operation (default: false)"),
+		),
+	)
+}
+
+// UndoOperationHandler returns a handler that reverts a journaled operation
+func UndoOperationHandler(journal *OperationJournal, client utils.UndoAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		id, _ := arguments["operation_id"].(string)
+		if id == "" {
+			return mcp.NewToolResultError("operation_id is required"), nil
+		}
+		if confirm, _ := arguments["confirm"].(string); confirm != id {
+			return mcp.NewToolResultError(fmt.Sprintf("confirm must repeat %q exactly", id)), nil
+		}
+		force, _ := arguments["force"].(bool)
+
+		op, undoneBy, found, err := journal.Find(id)
+		if err != nil {
+			requestLogf(ctx, logger, "Error reading operation journal: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to read operation journal", err), nil
+		}
+		switch {
+		// Another caller's operation is treated as unknown, like list_recent_operations does.
+		case !found || op.Caller != operationCaller(ctx):
+			return mcp.NewToolResultError(fmt.Sprintf("operation %s is not in the journal", id)), nil
+		case undoneBy != "":
+			return mcp.NewToolResultError(fmt.Sprintf("operation %s was already undone by operation %s", id, undoneBy)), nil
+		case op.Failed:
+			return mcp.NewToolResultError(fmt.Sprintf("operation %s failed, so there is nothing to undo", id)), nil
+		case op.Target == nil:
+			return mcp.NewToolResultError(fmt.Sprintf("undoing %s is not supported", op.Tool)), nil
+		case op.Before == nil:
+			return mcp.NewToolResultError(fmt.Sprintf("the state of %s %s before the operation was not recorded", op.Target.Kind, op.Target.ID)), nil
+		}
+
+		var actions []string
+		switch op.Target.Kind {
+		case "job":
+			actions, err = undoJobOperation(ctx, client, op, force)
+		case "node":
+			actions, err = undoNodeOperation(ctx, client, op)
+		case "variable":
+			actions, err = undoVariableOperation(ctx, client, op)
+		default:
+			err = fmt.Errorf("undoing %s is not supported", op.Tool)
+		}
+		if err != nil {
+			requestLogf(ctx, logger, "Error undoing operation %s: %v", id, err)
+			return mcp.NewToolResultErrorFromErr("Failed to undo operation", err), nil
+		}
+
+		resultJSON, err := json.MarshalIndent(UndoResult{OperationID: id, Tool: op.Tool, Target: op.Target, Actions: actions}, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format result", err), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// undoJobOperation reverts the job to the version it had before op. Nomad enforces the
+// version just read, so a concurrent change makes the revert fail instead of being lost.
+func undoJobOperation(ctx context.Context, client utils.UndoAPI, op Operation, force bool) ([]string, error) {
+	target := op.Target
+	if !op.Before.Found {
+		return nil, fmt.Errorf("job %s did not exist before the operation; stop it with stop_job instead", target.ID)
+	}
+	if op.Before.Version == nil {
+		return nil, fmt.Errorf("the version of job %s before the operation was not recorded", target.ID)
+	}
+	previous := *op.Before.Version
+	if op.After != nil && op.After.Version != nil && *op.After.Version == previous {
+		return nil, fmt.Errorf("the operation did not create a new version of job %s; nothing to revert", target.ID)
+	}
+
+	current, err := client.GetJob(ctx, target.ID, target.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if current.Version == previous {
+		return nil, fmt.Errorf("job %s is already at version %d", target.ID, previous)
+	}
+	if op.After != nil && op.After.Version != nil && current.Version != *op.After.Version && !force {
+		return nil, fmt.Errorf("job %s changed since the operation (now version %d, version %d right after it); pass force=true to revert to version %d anyway", target.ID, current.Version, *op.After.Version, previous)
+	}
+
+	result, err := client.RevertJob(ctx, target.ID, target.Namespace, previous, types.JobRevertOptions{EnforcePriorVersion: &current.Version})
+	if err != nil {
+		return nil, err
+	}
+	actions := []string{fmt.Sprintf("reverted job %s from version %d to version %d", target.ID, current.Version, previous)}
+	if warnings := strings.TrimSpace(result.Warnings); warnings != "" {
+		actions = append(actions, fmt.Sprintf("%s %s", nomadWarningsHeader, warnings))
+	}
+	return actions, nil
+}
+
+// undoNodeOperation stops a drain the node was not in before op and restores its scheduling
+// eligibility.
+func undoNodeOperation(ctx context.Context, client utils.UndoAPI, op Operation) ([]string, error) {
+	target := op.Target
+	if !op.Before.Found || op.Before.Eligibility == "" {
+		return nil, fmt.Errorf("the state of node %s before the operation was not recorded", target.ID)
+	}
+	current, err := client.GetNodeDetail(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []string
+	if op.Before.Drain != nil && !*op.Before.Drain && current.Drain {
+		if _, err := client.DrainNode(ctx, target.ID, false, 0); err != nil {
+			return nil, err
+		}
+		actions = append(actions, fmt.Sprintf("stopped the drain of node %s", target.ID))
+		// Stopping a drain leaves the node ineligible.
+		current.SchedulingEligibility = types.NodeSchedulingIneligible
+	}
+	if current.SchedulingEligibility != op.Before.Eligibility {
+		eligible := op.Before.Eligibility == types.NodeSchedulingEligible
+		if _, err := client.EligibilityNode(ctx, target.ID, eligible); err != nil {
+			return actions, err
+		}
+		actions = append(actions, fmt.Sprintf("marked node %s %s", target.ID, op.Before.Eligibility))
+	}
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("node %s already has the drain and eligibility it had before the operation", target.ID)
+	}
+	return actions, nil
+}
+
+// undoVariableOperation recreates a variable deleted by op from its journaled items. The
+// write fails if the path was recreated meanwhile.
+func undoVariableOperation(ctx context.Context, client utils.UndoAPI, op Operation) ([]string, error) {
+	target := op.Target
+	if op.Tool != "delete_variable" {
+		return nil, fmt.Errorf("undoing %s is not supported", op.Tool)
+	}
+	switch {
+	case !op.Before.Found:
+		return nil, fmt.Errorf("variable %s did not exist before the operation", target.ID)
+	case op.Before.Items == nil && op.Before.SealedItems != "":
+		return nil, fmt.Errorf("variable %s cannot be restored: %w", target.ID, errJournalItemsLocked)
+	case op.Before.Items == nil:
+		return nil, fmt.Errorf("variable %s has no journaled copy to restore; copies are only kept while MCP_NOMAD_JOURNAL_KEY is set", target.ID)
+	}
+
+	value, err := json.Marshal(map[string]interface{}{"Items": op.Before.Items})
+	if err != nil {
+		return nil, err
+	}
+	err = client.CreateVariable(ctx, types.Variable{Path: target.ID, Value: string(value)}, target.Namespace, utils.VariableCASMustNotExist, "")
+	var httpErr *utils.NomadHTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusConflict {
+		return nil, fmt.Errorf("variable %s exists again; not overwriting it", target.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []string{fmt.Sprintf("restored variable %s with %d items", target.ID, len(op.Before.Items))}, nil
+}
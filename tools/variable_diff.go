@@ -0,0 +1,175 @@
+// File: tools/variable_diff.go
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// VariableDifference is one path or item that is not the same on both sides
+type VariableDifference struct {
+	Path   string `json:"Path"`
+	Key    string `json:"Key,omitempty"`   // empty when the whole variable is missing
+	Status string `json:"Status"`          // missing_left, missing_right or differs
+	Left   string `json:"Left,omitempty"`  // value or hash, depending on compare
+	Right  string `json:"Right,omitempty"` // value or hash, depending on compare
+}
+
+// VariableDiffReport is the diff_variables response
+type VariableDiffReport struct {
+	Prefix      string               `json:"Prefix"`
+	Left        string               `json:"Left"`
+	Right       string               `json:"Right"`
+	Compare     string               `json:"Compare"`
+	Identical   bool                 `json:"Identical"`
+	Paths       int                  `json:"Paths"`
+	Differences []VariableDifference `json:"Differences"`
+}
+
+// DiffVariablesHandler returns a handler that compares the variables under a prefix
+func DiffVariablesHandler(client utils.VariableAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		prefix, _ := arguments["prefix"].(string)
+		compare := "hashes"
+		if c, ok := arguments["compare"].(string); ok && c != "" {
+			compare = c
+		}
+		if compare != "keys" && compare != "hashes" && compare != "values" {
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported compare %q: use keys, hashes or values", compare)), nil
+		}
+
+		namespace := utils.EffectiveToolNamespace(arguments)
+		otherNamespace, _ := arguments["other_namespace"].(string)
+		otherExport, _ := arguments["other_export"].(string)
+		if (otherNamespace == "") == (otherExport == "") {
+			return mcp.NewToolResultError("exactly one of other_namespace or other_export is required"), nil
+		}
+
+		left, err := exportVariables(ctx, client, namespace, prefix, false)
+		if err != nil {
+			requestLogf(ctx, logger, "Error reading variables: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to read variables", err), nil
+		}
+
+		var right VariableExport
+		rightName := ""
+		if otherNamespace != "" {
+			otherNamespace = utils.EffectiveToolNamespace(map[string]interface{}{"namespace": otherNamespace})
+			rightName = "namespace " + otherNamespace
+			if right, err = exportVariables(ctx, client, otherNamespace, prefix, false); err != nil {
+				requestLogf(ctx, logger, "Error reading variables: %v", err)
+				return mcp.NewToolResultErrorFromErr("Failed to read variables", err), nil
+			}
+		} else {
+			if err := json.Unmarshal([]byte(otherExport), &right); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("other_export is not an export_variables document: %v", err)), nil
+			}
+			if right.Redacted && compare != "keys" {
+				return mcp.NewToolResultError("other_export has redacted values; use compare=keys or export with redact_values=false"), nil
+			}
+			rightName = "export of namespace " + right.Namespace
+			if !right.ExportedAt.IsZero() {
+				rightName += " taken " + right.ExportedAt.Format("2006-01-02T15:04:05Z07:00")
+			}
+		}
+
+		report := diffVariables(left.Variables, right.Variables, prefix, compare)
+		report.Left = "namespace " + namespace
+		report.Right = rightName
+
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format variable diff", err), nil
+		}
+
+		return mcp.NewToolResultText(string(reportJSON)), nil
+	}
+}
+
+// diffVariables compares two sets of variables by path and item key. Variables outside
+// prefix are ignored, so an export of a wider prefix can be compared against a narrow one.
+func diffVariables(left, right []ExportedVariable, prefix, compare string) VariableDiffReport {
+	report := VariableDiffReport{Prefix: prefix, Compare: compare, Differences: []VariableDifference{}}
+
+	index := func(vars []ExportedVariable) map[string]map[string]string {
+		byPath := map[string]map[string]string{}
+		for _, v := range vars {
+			if strings.HasPrefix(v.Path, prefix) {
+				byPath[v.Path] = v.Items
+			}
+		}
+		return byPath
+	}
+	leftByPath, rightByPath := index(left), index(right)
+
+	paths := map[string]struct{}{}
+	for p := range leftByPath {
+		paths[p] = struct{}{}
+	}
+	for p := range rightByPath {
+		paths[p] = struct{}{}
+	}
+	report.Paths = len(paths)
+
+	shown := func(value string) string {
+		switch compare {
+		case "values":
+			return value
+		case "hashes":
+			sum := sha256.Sum256([]byte(value))
+			return hex.EncodeToString(sum[:])[:12]
+		}
+		return ""
+	}
+
+	for _, p := range slices.Sorted(maps.Keys(paths)) {
+		leftItems, inLeft := leftByPath[p]
+		rightItems, inRight := rightByPath[p]
+		switch {
+		case !inLeft:
+			report.Differences = append(report.Differences, VariableDifference{Path: p, Status: "missing_left"})
+			continue
+		case !inRight:
+			report.Differences = append(report.Differences, VariableDifference{Path: p, Status: "missing_right"})
+			continue
+		}
+
+		keys := map[string]struct{}{}
+		for k := range leftItems {
+			keys[k] = struct{}{}
+		}
+		for k := range rightItems {
+			keys[k] = struct{}{}
+		}
+		for _, k := range slices.Sorted(maps.Keys(keys)) {
+			lv, inLeft := leftItems[k]
+			rv, inRight := rightItems[k]
+			switch {
+			case !inLeft:
+				report.Differences = append(report.Differences, VariableDifference{Path: p, Key: k, Status: "missing_left", Right: shown(rv)})
+			case !inRight:
+				report.Differences = append(report.Differences, VariableDifference{Path: p, Key: k, Status: "missing_right", Left: shown(lv)})
+			case compare != "keys" && lv != rv:
+				report.Differences = append(report.Differences, VariableDifference{Path: p, Key: k, Status: "differs", Left: shown(lv), Right: shown(rv)})
+			}
+		}
+	}
+
+	report.Identical = len(report.Differences) == 0
+	return report
+}
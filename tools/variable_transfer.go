@@ -0,0 +1,349 @@
+// File: tools/variable_transfer.go
+package tools
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// redactedVariableValue replaces item values in exports made with redact_values.
+const redactedVariableValue = "<redacted>"
+
+// variableArchiveManifest is the name of the export metadata file inside an archive.
+const variableArchiveManifest = "manifest.json"
+
+// VariableExport is the document produced by export_variables and read by import_variables
+type VariableExport struct {
+	Namespace  string             `json:"Namespace"`
+	Prefix     string             `json:"Prefix"`
+	ExportedAt time.Time          `json:"ExportedAt"`
+	Redacted   bool               `json:"Redacted"`
+	Variables  []ExportedVariable `json:"Variables,omitempty"`
+}
+
+// ExportedVariable is one variable of an export
+type ExportedVariable struct {
+	Path        string            `json:"Path"`
+	Items       map[string]string `json:"Items"`
+	ModifyIndex uint64            `json:"ModifyIndex,omitempty"`
+}
+
+// VariableImportIssue explains why a variable was not written
+type VariableImportIssue struct {
+	Path   string `json:"Path"`
+	Reason string `json:"Reason"`
+}
+
+// VariableImportReport is the import_variables response
+type VariableImportReport struct {
+	Namespace string                `json:"Namespace"`
+	DryRun    bool                  `json:"DryRun"`
+	Created   []string              `json:"Created"`
+	Updated   []string              `json:"Updated"`
+	Unchanged []string              `json:"Unchanged"`
+	Skipped   []VariableImportIssue `json:"Skipped"`
+	Failed    []VariableImportIssue `json:"Failed"`
+}
+
+// ExportVariablesHandler returns a handler that exports the variables under a prefix
+func ExportVariablesHandler(client utils.VariableAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		namespace := utils.EffectiveToolNamespace(arguments)
+		prefix, _ := arguments["prefix"].(string)
+		redact := true
+		if r, ok := arguments["redact_values"].(bool); ok {
+			redact = r
+		}
+		format := "json"
+		if f, ok := arguments["format"].(string); ok && f != "" {
+			format = f
+		}
+		if format != "json" && format != "archive" {
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported format %q: use json or archive", format)), nil
+		}
+
+		export, err := exportVariables(ctx, client, namespace, prefix, redact)
+		if err != nil {
+			requestLogf(ctx, logger, "Error exporting variables: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to export variables", err), nil
+		}
+
+		if format == "archive" {
+			archive, err := variableArchive(export)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("Failed to build variable archive", err), nil
+			}
+			return mcp.NewToolResultResource(
+				fmt.Sprintf("Exported %d variables from namespace %s as a gzipped tar archive", len(export.Variables), namespace),
+				mcp.BlobResourceContents{
+					URI:      fmt.Sprintf("nomad://variables/%s/export.tar.gz", namespace),
+					MIMEType: "application/gzip",
+					Blob:     base64.StdEncoding.EncodeToString(archive),
+				},
+			), nil
+		}
+
+		exportJSON, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format variable export", err), nil
+		}
+
+		return mcp.NewToolResultText(string(exportJSON)), nil
+	}
+}
+
+// ImportVariablesHandler returns a handler that writes the variables of an export
+func ImportVariablesHandler(client utils.VariableAPI, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		var export VariableExport
+		variablesJSON, _ := arguments["variables"].(string)
+		archive, _ := arguments["archive"].(string)
+		switch {
+		case variablesJSON != "" && archive != "":
+			return mcp.NewToolResultError("pass either variables or archive, not both"), nil
+		case variablesJSON != "":
+			if err := json.Unmarshal([]byte(variablesJSON), &export); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("variables is not an export_variables document: %v", err)), nil
+			}
+		case archive != "":
+			data, err := base64.StdEncoding.DecodeString(archive)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("archive is not base64: %v", err)), nil
+			}
+			if export, err = readVariableArchive(data); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid variable archive: %v", err)), nil
+			}
+		default:
+			return mcp.NewToolResultError("variables or archive is required"), nil
+		}
+		if export.Redacted {
+			return mcp.NewToolResultError("the export has redacted values; export again with redact_values=false to import it"), nil
+		}
+
+		onConflict := "skip"
+		if c, ok := arguments["on_conflict"].(string); ok && c != "" {
+			onConflict = c
+		}
+		if onConflict != "skip" && onConflict != "overwrite" && onConflict != "fail" {
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported on_conflict %q: use skip, overwrite or fail", onConflict)), nil
+		}
+		dryRun, _ := arguments["dry_run"].(bool)
+		namespace := utils.EffectiveToolNamespace(arguments)
+
+		report := importVariables(ctx, client, export.Variables, namespace, onConflict, dryRun, logger)
+
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format import report", err), nil
+		}
+		if len(report.Failed) > 0 {
+			return mcp.NewToolResultError(string(reportJSON)), nil
+		}
+		return mcp.NewToolResultText(string(reportJSON)), nil
+	}
+}
+
+// exportVariables reads every variable under prefix, sorted by path.
+func exportVariables(ctx context.Context, client utils.VariableAPI, namespace, prefix string, redact bool) (VariableExport, error) {
+	export := VariableExport{Namespace: namespace, Prefix: prefix, ExportedAt: time.Now().UTC(), Redacted: redact, Variables: []ExportedVariable{}}
+
+	stubs, err := client.ListVariables(ctx, namespace, prefix, "", 0, "")
+	if err != nil {
+		return export, err
+	}
+	for _, stub := range stubs {
+		variable, err := client.GetVariable(ctx, stub.Path, namespace)
+		if err != nil {
+			return export, fmt.Errorf("error reading variable %s: %w", stub.Path, err)
+		}
+		items := map[string]string{}
+		for k, v := range variable.Items {
+			if redact {
+				v = redactedVariableValue
+			}
+			items[k] = v
+		}
+		export.Variables = append(export.Variables, ExportedVariable{Path: stub.Path, Items: items, ModifyIndex: variable.ModifyIndex})
+	}
+	sort.Slice(export.Variables, func(i, j int) bool { return export.Variables[i].Path < export.Variables[j].Path })
+	return export, nil
+}
+
+// importVariables creates missing variables with a must-not-exist check and, depending on
+// onConflict, overwrites differing ones with a check-and-set on the index just read, so a
+// variable changed during the import is reported instead of overwritten.
+func importVariables(ctx context.Context, client utils.VariableAPI, variables []ExportedVariable, namespace, onConflict string, dryRun bool, logger *log.Logger) VariableImportReport {
+	report := VariableImportReport{
+		Namespace: namespace,
+		DryRun:    dryRun,
+		Created:   []string{},
+		Updated:   []string{},
+		Unchanged: []string{},
+		Skipped:   []VariableImportIssue{},
+		Failed:    []VariableImportIssue{},
+	}
+	fail := func(path string, err error) {
+		requestLogf(ctx, logger, "Error importing variable %s: %v", path, err)
+		report.Failed = append(report.Failed, VariableImportIssue{Path: path, Reason: err.Error()})
+	}
+
+	for _, v := range variables {
+		if v.Path == "" || len(v.Items) == 0 {
+			report.Skipped = append(report.Skipped, VariableImportIssue{Path: v.Path, Reason: "variable has no path or no items"})
+			continue
+		}
+
+		cas := utils.VariableCASMustNotExist
+		current, err := client.GetVariable(ctx, v.Path, namespace)
+		switch {
+		case isNotFound(err):
+		case err != nil:
+			fail(v.Path, err)
+			continue
+		case maps.Equal(current.Items, v.Items):
+			report.Unchanged = append(report.Unchanged, v.Path)
+			continue
+		case onConflict == "skip":
+			report.Skipped = append(report.Skipped, VariableImportIssue{Path: v.Path, Reason: "exists with different items; use on_conflict=overwrite to replace it"})
+			continue
+		case onConflict == "fail":
+			fail(v.Path, fmt.Errorf("exists with different items"))
+			continue
+		default:
+			cas = int(current.ModifyIndex)
+		}
+
+		if !dryRun {
+			value, err := json.Marshal(map[string]interface{}{"Items": v.Items})
+			if err != nil {
+				fail(v.Path, err)
+				continue
+			}
+			err = client.CreateVariable(ctx, types.Variable{Path: v.Path, Value: string(value)}, namespace, cas, "")
+			var httpErr *utils.NomadHTTPError
+			if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusConflict {
+				fail(v.Path, fmt.Errorf("modified concurrently during the import; run it again"))
+				continue
+			}
+			if err != nil {
+				fail(v.Path, err)
+				continue
+			}
+		}
+		if cas == utils.VariableCASMustNotExist {
+			report.Created = append(report.Created, v.Path)
+		} else {
+			report.Updated = append(report.Updated, v.Path)
+		}
+	}
+	return report
+}
+
+// variableArchive packs an export as a gzipped tar: a manifest plus one JSON file per
+// variable, laid out by variable path.
+func variableArchive(export VariableExport) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	write := func(name string, v interface{}) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: export.ExportedAt}); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	}
+
+	manifest := export
+	manifest.Variables = nil
+	if err := write(variableArchiveManifest, manifest); err != nil {
+		return nil, err
+	}
+	for _, v := range export.Variables {
+		if err := write(path.Join("variables", v.Path+".json"), v); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readVariableArchive unpacks an archive written by variableArchive.
+func readVariableArchive(data []byte) (VariableExport, error) {
+	var export VariableExport
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return export, err
+	}
+	tr := tar.NewReader(gz)
+
+	sawManifest := false
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return export, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		switch {
+		case header.Name == variableArchiveManifest:
+			if err := json.NewDecoder(tr).Decode(&export); err != nil {
+				return export, fmt.Errorf("%s: %v", header.Name, err)
+			}
+			sawManifest = true
+		case strings.HasPrefix(header.Name, "variables/") && strings.HasSuffix(header.Name, ".json"):
+			var v ExportedVariable
+			if err := json.NewDecoder(tr).Decode(&v); err != nil {
+				return export, fmt.Errorf("%s: %v", header.Name, err)
+			}
+			export.Variables = append(export.Variables, v)
+		}
+	}
+	if !sawManifest {
+		return export, fmt.Errorf("missing %s", variableArchiveManifest)
+	}
+	sort.Slice(export.Variables, func(i, j int) bool { return export.Variables[i].Path < export.Variables[j].Path })
+	return export, nil
+}
@@ -17,6 +17,7 @@ func RegisterVariableTools(s *server.MCPServer, nomadClient utils.VariableAPI, l
 	// List variables tool
 	listVariablesTool := mcp.NewTool("list_variables",
 		mcp.WithDescription("List all variables in Nomad"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("namespace",
 			mcp.Description("The namespace to list variables from (default: default)"),
 		),
@@ -38,6 +39,7 @@ func RegisterVariableTools(s *server.MCPServer, nomadClient utils.VariableAPI, l
 	// Get variable tool
 	getVariableTool := mcp.NewTool("get_variable",
 		mcp.WithDescription("Get variable details by path"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("path",
 			mcp.Required(),
 			mcp.Description("The path of the variable to retrieve"),
@@ -91,6 +93,71 @@ func RegisterVariableTools(s *server.MCPServer, nomadClient utils.VariableAPI, l
 		),
 	)
 	s.AddTool(deleteVariableTool, DeleteVariableHandler(nomadClient, logger))
+
+	// Export variables tool
+	exportVariablesTool := mcp.NewTool("export_variables",
+		mcp.WithDescription("Export the variables under a prefix with their items, as a JSON document or a gzipped tar archive, for migrating configuration with import_variables. Values are redacted unless redact_values=false"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace to export variables from (default: default)"),
+		),
+		mcp.WithString("prefix",
+			mcp.Description("Only export variables whose path starts with this prefix (default: all)"),
+		),
+		mcp.WithBoolean("redact_values",
+			mcp.Description("Replace item values with a placeholder; redacted exports cannot be imported (default: true)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("json returns the export document; archive returns a base64 gzipped tar with one file per variable (default: json)"),
+			mcp.Enum("json", "archive"),
+		),
+	)
+	s.AddTool(exportVariablesTool, ExportVariablesHandler(nomadClient, logger))
+
+	// Import variables tool
+	importVariablesTool := mcp.NewTool("import_variables",
+		mcp.WithDescription("Create or update variables from an export_variables document or archive. New variables are written only if still absent and overwrites use check-and-set on the index just read, so concurrent changes are reported instead of lost"),
+		mcp.WithString("variables",
+			mcp.Description("The JSON document returned by export_variables"),
+		),
+		mcp.WithString("archive",
+			mcp.Description("The base64 gzipped tar returned by export_variables with format=archive"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace to import into (default: default)"),
+		),
+		mcp.WithString("on_conflict",
+			mcp.Description("What to do with existing variables whose items differ: skip, overwrite or fail (default: skip)"),
+			mcp.Enum("skip", "overwrite", "fail"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Report what would be created and updated without writing (default: false)"),
+		),
+	)
+	s.AddTool(importVariablesTool, ImportVariablesHandler(nomadClient, logger))
+
+	// Diff variables tool
+	diffVariablesTool := mcp.NewTool("diff_variables",
+		mcp.WithDescription("Compare the variables under a prefix between two namespaces, or against an export_variables document taken from another cluster, and report missing paths and missing or differing items"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("namespace",
+			mcp.Description("The namespace on the left side of the comparison (default: default)"),
+		),
+		mcp.WithString("other_namespace",
+			mcp.Description("The namespace on the right side of the comparison"),
+		),
+		mcp.WithString("other_export",
+			mcp.Description("An export_variables JSON document to use as the right side instead of other_namespace, e.g. from another cluster"),
+		),
+		mcp.WithString("prefix",
+			mcp.Description("Only compare variables whose path starts with this prefix (default: all)"),
+		),
+		mcp.WithString("compare",
+			mcp.Description("keys compares item keys only; hashes also compares values and shows short SHA-256 hashes; values shows the values themselves (default: hashes)"),
+			mcp.Enum("keys", "hashes", "values"),
+		),
+	)
+	s.AddTool(diffVariablesTool, DiffVariablesHandler(nomadClient, logger))
 }
 
 // ListVariablesHandler returns a handler for listing variables
@@ -125,7 +192,7 @@ func ListVariablesHandler(client utils.VariableAPI, logger *log.Logger) func(con
 
 		variables, err := client.ListVariables(ctx, namespace, prefix, nextToken, perPage, filter)
 		if err != nil {
-			logger.Printf("Error listing variables: %v", err)
+			requestLogf(ctx, logger, "Error listing variables: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to list variables", err), nil
 		}
 
@@ -155,7 +222,7 @@ func GetVariableHandler(client utils.VariableAPI, logger *log.Logger) func(conte
 
 		variable, err := client.GetVariable(ctx, path, namespace)
 		if err != nil {
-			logger.Printf("Error getting variable: %v", err)
+			requestLogf(ctx, logger, "Error getting variable: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get variable", err), nil
 		}
 
@@ -226,7 +293,7 @@ func CreateVariableHandler(client utils.VariableAPI, logger *log.Logger) func(co
 		// Convert to JSON string
 		jsonValue, err := json.Marshal(variableValue)
 		if err != nil {
-			logger.Printf("Error marshaling variable value: %v", err)
+			requestLogf(ctx, logger, "Error marshaling variable value: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to format variable value", err), nil
 		}
 
@@ -237,7 +304,7 @@ func CreateVariableHandler(client utils.VariableAPI, logger *log.Logger) func(co
 
 		err = client.CreateVariable(ctx, variable, namespace, cas, lockOp)
 		if err != nil {
-			logger.Printf("Error creating variable: %v", err)
+			requestLogf(ctx, logger, "Error creating variable: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to create variable", err), nil
 		}
 
@@ -276,7 +343,7 @@ func DeleteVariableHandler(client utils.VariableAPI, logger *log.Logger) func(co
 
 		err := client.DeleteVariable(ctx, path, namespace, cas)
 		if err != nil {
-			logger.Printf("Error deleting variable: %v", err)
+			requestLogf(ctx, logger, "Error deleting variable: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to delete variable", err), nil
 		}
 
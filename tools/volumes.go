@@ -16,6 +16,7 @@ func RegisterVolumeTools(s *server.MCPServer, nomadClient utils.VolumeAPI, logge
 	// List volumes tool
 	listVolumesTool := mcp.NewTool("list_volumes",
 		mcp.WithDescription("List all volumes in a namespace"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("namespace",
 			mcp.Description("Namespace to list volumes from (optional)"),
 		),
@@ -25,6 +26,7 @@ func RegisterVolumeTools(s *server.MCPServer, nomadClient utils.VolumeAPI, logge
 	// Get volume tool
 	getVolumeTool := mcp.NewTool("get_volume",
 		mcp.WithDescription("Get details of a specific volume"),
+		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("volume_id",
 			mcp.Required(),
 			mcp.Description("ID of the volume to get"),
@@ -75,7 +77,7 @@ func ListVolumesHandler(client utils.VolumeAPI, logger *log.Logger) func(context
 		// List volumes with the specified parameters
 		volumes, err := client.ListVolumes(ctx, nodeID, pluginID, nextToken, perPage, filter)
 		if err != nil {
-			logger.Printf("Error listing volumes: %v", err)
+			requestLogf(ctx, logger, "Error listing volumes: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to list volumes", err), nil
 		}
 
@@ -106,7 +108,7 @@ func GetVolumeHandler(client utils.VolumeAPI, logger *log.Logger) func(context.C
 		volume, err := client.GetVolume(ctx, volumeID)
 
 		if err != nil {
-			logger.Printf("Error getting volume: %v", err)
+			requestLogf(ctx, logger, "Error getting volume: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to get volume", err), nil
 		}
 
@@ -136,7 +138,7 @@ func DeleteVolumeHandler(client utils.VolumeAPI, logger *log.Logger) func(contex
 		err := client.DeleteVolume(ctx, volumeID)
 
 		if err != nil {
-			logger.Printf("Error deleting volume: %v", err)
+			requestLogf(ctx, logger, "Error deleting volume: %v", err)
 			return mcp.NewToolResultErrorFromErr("Failed to delete volume", err), nil
 		}
 
@@ -0,0 +1,41 @@
+// File: tools/warnings.go
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// nomadWarningsHeader starts the content block that carries the warnings Nomad returned for
+// a call, so clients and agents can tell them apart from the result itself.
+const nomadWarningsHeader = "WARNINGS from Nomad:"
+
+// withNomadWarnings appends the non-empty warnings Nomad returned for a call to result as a
+// separate content block. Nomad accepts a request despite its warnings, e.g. deprecated job
+// fields, so they would otherwise go unnoticed.
+func withNomadWarnings(result *mcp.CallToolResult, warnings ...string) *mcp.CallToolResult {
+	var lines []string
+	for _, warning := range warnings {
+		if warning = strings.TrimSpace(warning); warning != "" {
+			lines = append(lines, warning)
+		}
+	}
+	if result == nil || len(lines) == 0 {
+		return result
+	}
+	result.Content = append(result.Content, mcp.NewTextContent(nomadWarningsHeader+"\n"+strings.Join(lines, "\n")))
+	return result
+}
+
+// jobResultWithWarnings formats the result of a job submission with the warnings Nomad
+// returned for it.
+func jobResultWithWarnings(result interface{}, warnings ...string) (*mcp.CallToolResult, error) {
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format result", err), nil
+	}
+
+	return withNomadWarnings(mcp.NewToolResultText(string(resultJSON)), warnings...), nil
+}
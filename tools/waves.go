@@ -0,0 +1,297 @@
+// File: tools/waves.go
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/kocierik/mcp-nomad/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// GroupDrainLimit is how many allocations of a task group one wave may migrate at once
+type GroupDrainLimit struct {
+	Namespace    string `json:"Namespace"`
+	JobID        string `json:"JobID"`
+	Group        string `json:"Group"`
+	Count        int    `json:"Count"`
+	MaxParallel  int    `json:"MaxParallel"` // from the group's migrate block
+	AntiAffinity bool   `json:"AntiAffinity"`
+	PerWave      int    `json:"PerWave"`
+}
+
+// UpgradeWaveNode is a node drained in an upgrade wave
+type UpgradeWaveNode struct {
+	NodeID      string `json:"NodeID"`
+	Name        string `json:"Name"`
+	Datacenter  string `json:"Datacenter"`
+	NodePool    string `json:"NodePool,omitempty"`
+	Allocations int    `json:"Allocations"` // service allocations the drain migrates
+}
+
+// UpgradeWave is one batch of nodes drained and upgraded together
+type UpgradeWave struct {
+	Wave        int               `json:"Wave"`
+	Nodes       []UpgradeWaveNode `json:"Nodes"`
+	Allocations int               `json:"Allocations"`
+	Steps       []string          `json:"Steps"`
+}
+
+// UpgradePlan is the plan_node_upgrade_waves response
+type UpgradePlan struct {
+	Nodes       int               `json:"Nodes"`
+	MaxWaveSize int               `json:"MaxWaveSize"`
+	Waves       []UpgradeWave     `json:"Waves"`
+	Limits      []GroupDrainLimit `json:"Limits"`
+	Warnings    []string          `json:"Warnings"`
+	Errors      []string          `json:"Errors,omitempty"`
+}
+
+// PlanNodeUpgradeWavesHandler returns a handler that partitions client nodes into drain waves
+func PlanNodeUpgradeWavesHandler(client utils.NodeToolsDeps, logger *log.Logger) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		datacenter, _ := arguments["datacenter"].(string)
+		nodePool, _ := arguments["node_pool"].(string)
+		nodeClass, _ := arguments["node_class"].(string)
+
+		maxWaveSize := 0
+		if m, ok := arguments["max_wave_size"].(float64); ok {
+			if m < 1 {
+				return mcp.NewToolResultError("max_wave_size must be at least 1"), nil
+			}
+			maxWaveSize = int(m)
+		}
+
+		nodes, err := fetchNodeDetails(ctx, client)
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing nodes: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list nodes", err), nil
+		}
+
+		plan := UpgradePlan{Waves: []UpgradeWave{}, Limits: []GroupDrainLimit{}, Warnings: []string{}}
+
+		var selected []types.NodeDetail
+		skipped := 0
+		for _, node := range nodes {
+			if (datacenter != "" && node.Datacenter != datacenter) || (nodePool != "" && node.NodePool != nodePool) || (nodeClass != "" && node.NodeClass != nodeClass) {
+				continue
+			}
+			if node.Status != "ready" {
+				skipped++
+				continue
+			}
+			selected = append(selected, node)
+		}
+		if skipped > 0 {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("%d matching nodes are not ready and are left out of the plan", skipped))
+		}
+		if len(selected) == 0 {
+			return mcp.NewToolResultError("no ready client nodes match the filters"), nil
+		}
+		plan.Nodes = len(selected)
+		if maxWaveSize == 0 {
+			maxWaveSize = (len(selected) + 9) / 10
+		}
+		plan.MaxWaveSize = maxWaveSize
+
+		stubs, err := client.ListJobs(ctx, "*", "")
+		if err != nil {
+			requestLogf(ctx, logger, "Error listing jobs: %v", err)
+			return mcp.NewToolResultErrorFromErr("Failed to list jobs", err), nil
+		}
+		limits := map[string]GroupDrainLimit{}
+		for _, job := range fetchLiveJobs(ctx, client, stubs, utils.NomadDefaultNamespace, &plan.Errors, logger) {
+			if job.Type != "service" {
+				continue
+			}
+			for _, tg := range job.TaskGroups {
+				limit := groupDrainLimit(job, tg)
+				limits[limit.Namespace+"/"+limit.JobID+"/"+limit.Group] = limit
+			}
+		}
+
+		allocs, errs := fetchNodeAllocations(ctx, client, selected)
+		load := make([]map[string]int, len(selected))
+		for i, node := range selected {
+			load[i] = map[string]int{}
+			if errs[i] != nil {
+				requestLogf(ctx, logger, "Error listing allocations for node %s: %v", node.ID, errs[i])
+				plan.Errors = append(plan.Errors, fmt.Sprintf("node %s: %v", node.ID, errs[i]))
+				continue
+			}
+			for _, alloc := range allocs[i] {
+				key := alloc.Namespace + "/" + alloc.JobID + "/" + alloc.TaskGroup
+				if _, ok := limits[key]; ok && isLiveAllocation(alloc) {
+					load[i][key]++
+				}
+			}
+		}
+
+		plan.Waves = planUpgradeWaves(selected, load, limits, maxWaveSize, &plan.Warnings)
+
+		used := map[string]bool{}
+		for _, l := range load {
+			for key := range l {
+				used[key] = true
+			}
+		}
+		for key, limit := range limits {
+			if used[key] {
+				plan.Limits = append(plan.Limits, limit)
+			}
+		}
+		sort.Slice(plan.Limits, func(i, j int) bool {
+			a, b := plan.Limits[i], plan.Limits[j]
+			return a.Namespace+"/"+a.JobID+"/"+a.Group < b.Namespace+"/"+b.JobID+"/"+b.Group
+		})
+
+		planJSON, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format upgrade plan", err), nil
+		}
+
+		return mcp.NewToolResultText(string(planJSON)), nil
+	}
+}
+
+// groupDrainLimit caps a group's concurrent migrations by its migrate max_parallel (default 1)
+// while always keeping one allocation running. Groups that ask to be kept apart with
+// distinct_hosts, distinct_property, spread or a negative affinity lose one node's worth at a
+// time, so they are limited to a single migration per wave.
+func groupDrainLimit(job types.Job, tg types.TaskGroup) GroupDrainLimit {
+	limit := GroupDrainLimit{Namespace: job.Namespace, JobID: job.ID, Group: tg.Name, Count: tg.Count, MaxParallel: 1}
+	if tg.Migrate != nil && tg.Migrate.MaxParallel > 0 {
+		limit.MaxParallel = tg.Migrate.MaxParallel
+	}
+
+	constraints := append(append([]types.Constraint{}, job.Constraints...), tg.Constraints...)
+	for _, c := range constraints {
+		if c.Operand == "distinct_hosts" || c.Operand == "distinct_property" {
+			limit.AntiAffinity = true
+		}
+	}
+	for _, a := range append(append([]types.Affinity{}, job.Affinities...), tg.Affinities...) {
+		if a.Weight < 0 {
+			limit.AntiAffinity = true
+		}
+	}
+	if len(job.Spreads) > 0 || len(tg.Spreads) > 0 {
+		limit.AntiAffinity = true
+	}
+
+	limit.PerWave = limit.MaxParallel
+	if limit.Count-1 < limit.PerWave {
+		limit.PerWave = limit.Count - 1
+	}
+	if limit.AntiAffinity {
+		limit.PerWave = 1
+	}
+	if limit.PerWave < 1 {
+		limit.PerWave = 1
+	}
+	return limit
+}
+
+// planUpgradeWaves packs nodes into waves of at most maxWaveSize, most loaded nodes first, so
+// that no wave migrates more of a group's allocations than its limit. A node that alone exceeds
+// a limit gets a wave of its own and a warning. Waves are returned lightest first so the first
+// upgrades carry the least risk and give migrated allocations upgraded nodes to land on.
+func planUpgradeWaves(nodes []types.NodeDetail, load []map[string]int, limits map[string]GroupDrainLimit, maxWaveSize int, warnings *[]string) []UpgradeWave {
+	total := func(i int) int {
+		n := 0
+		for _, c := range load[i] {
+			n += c
+		}
+		return n
+	}
+
+	order := make([]int, len(nodes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		if total(order[a]) != total(order[b]) {
+			return total(order[a]) > total(order[b])
+		}
+		return nodes[order[a]].Name < nodes[order[b]].Name
+	})
+
+	placed := make([]bool, len(nodes))
+	waves := []UpgradeWave{}
+	for remaining := len(nodes); remaining > 0; {
+		wave := UpgradeWave{Nodes: []UpgradeWaveNode{}}
+		usage := map[string]int{}
+		for _, i := range order {
+			if placed[i] || len(wave.Nodes) >= maxWaveSize {
+				continue
+			}
+			fits := true
+			for key, n := range load[i] {
+				if usage[key]+n > limits[key].PerWave {
+					fits = false
+					break
+				}
+			}
+			if !fits && len(wave.Nodes) > 0 {
+				continue
+			}
+			if !fits {
+				for _, key := range sortedCountKeys(load[i]) {
+					if load[i][key] > limits[key].PerWave {
+						*warnings = append(*warnings, fmt.Sprintf("node %s runs %d allocations of %s, more than the %d that can migrate at once; draining it will interrupt the group", nodes[i].Name, load[i][key], key, limits[key].PerWave))
+					}
+				}
+			}
+			for key, n := range load[i] {
+				usage[key] += n
+			}
+			placed[i] = true
+			remaining--
+			wave.Allocations += total(i)
+			wave.Nodes = append(wave.Nodes, UpgradeWaveNode{
+				NodeID:      nodes[i].ID,
+				Name:        nodes[i].Name,
+				Datacenter:  nodes[i].Datacenter,
+				NodePool:    nodes[i].NodePool,
+				Allocations: total(i),
+			})
+		}
+		waves = append(waves, wave)
+	}
+
+	sort.SliceStable(waves, func(i, j int) bool { return waves[i].Allocations < waves[j].Allocations })
+	for i := range waves {
+		waves[i].Wave = i + 1
+		sort.Slice(waves[i].Nodes, func(a, b int) bool { return waves[i].Nodes[a].Name < waves[i].Nodes[b].Name })
+		names := make([]string, len(waves[i].Nodes))
+		for j, node := range waves[i].Nodes {
+			names[j] = node.Name
+		}
+		list := strings.Join(names, ", ")
+		waves[i].Steps = []string{
+			fmt.Sprintf("Run enter_node_maintenance on %s and wait until their allocations have migrated", list),
+			fmt.Sprintf("Upgrade the operating system or Nomad on %s and restart the Nomad agent", list),
+			fmt.Sprintf("Run exit_node_maintenance on %s and confirm the nodes are ready before starting the next wave", list),
+		}
+	}
+	return waves
+}
+
+func sortedCountKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
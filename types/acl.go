@@ -2,14 +2,15 @@ package types
 
 // ACLToken represents a Nomad ACL token
 type ACLToken struct {
-	AccessorID  string   `json:"AccessorID"`
-	SecretID    string   `json:"SecretID"`
-	Name        string   `json:"Name"`
-	Type        string   `json:"Type"`
-	Policies    []string `json:"Policies"`
-	Global      bool     `json:"Global"`
-	CreateIndex int      `json:"CreateIndex"`
-	ModifyIndex int      `json:"ModifyIndex"`
+	AccessorID  string        `json:"AccessorID"`
+	SecretID    string        `json:"SecretID"`
+	Name        string        `json:"Name"`
+	Type        string        `json:"Type"`
+	Policies    []string      `json:"Policies"`
+	Roles       []ACLRoleLink `json:"Roles,omitempty"`
+	Global      bool          `json:"Global"`
+	CreateIndex int           `json:"CreateIndex"`
+	ModifyIndex int           `json:"ModifyIndex"`
 }
 
 // ACLPolicy represents a Nomad ACL policy
@@ -26,6 +27,12 @@ type ACLPolicyLink struct {
 	Name string `json:"Name"`
 }
 
+// ACLRoleLink references a role linked to a token
+type ACLRoleLink struct {
+	ID   string `json:"ID"`
+	Name string `json:"Name,omitempty"`
+}
+
 // ACLRole represents a Nomad ACL role
 type ACLRole struct {
 	ID          string              `json:"id"`
@@ -0,0 +1,99 @@
+// File: types/agent.go
+package types
+
+import "time"
+
+// AgentMembers is the response of agent/members: the agent's server identity and the
+// servers in its region's gossip pool
+type AgentMembers struct {
+	ServerName   string        `json:"ServerName"`
+	ServerRegion string        `json:"ServerRegion"`
+	ServerDC     string        `json:"ServerDC"`
+	Members      []AgentMember `json:"Members"`
+}
+
+// AgentSelf is the response of agent/self. Config is limited to the fields below, so
+// credentials in the agent's configuration are never returned.
+type AgentSelf struct {
+	Config AgentConfig                  `json:"config"`
+	Member AgentMember                  `json:"member"`
+	Stats  map[string]map[string]string `json:"stats,omitempty"`
+}
+
+// AgentConfig is the part of an agent's configuration that describes its role and reach
+type AgentConfig struct {
+	Region     string        `json:"Region"`
+	Datacenter string        `json:"Datacenter"`
+	NodeName   string        `json:"NodeName"`
+	DataDir    string        `json:"DataDir"`
+	LogLevel   string        `json:"LogLevel"`
+	BindAddr   string        `json:"BindAddr"`
+	Ports      *AgentPorts   `json:"Ports,omitempty"`
+	Server     *AgentServer  `json:"Server,omitempty"`
+	Client     *AgentClient  `json:"Client,omitempty"`
+	ACL        *AgentACL     `json:"ACL,omitempty"`
+	TLSConfig  *AgentTLS     `json:"TLSConfig,omitempty"`
+	Version    *AgentVersion `json:"Version,omitempty"`
+}
+
+// AgentPorts are the ports an agent listens on
+type AgentPorts struct {
+	HTTP int `json:"HTTP"`
+	RPC  int `json:"RPC"`
+	Serf int `json:"Serf"`
+}
+
+// AgentServer is the server block of an agent's configuration
+type AgentServer struct {
+	Enabled           bool     `json:"Enabled"`
+	BootstrapExpect   int      `json:"BootstrapExpect"`
+	NumSchedulers     *int     `json:"NumSchedulers,omitempty"`
+	EnabledSchedulers []string `json:"EnabledSchedulers,omitempty"`
+	RaftProtocol      int      `json:"RaftProtocol"`
+	// The heartbeat settings only appear in the configuration of server agents.
+	HeartbeatGrace         time.Duration `json:"HeartbeatGrace,omitempty"`
+	MinHeartbeatTTL        time.Duration `json:"MinHeartbeatTTL,omitempty"`
+	MaxHeartbeatsPerSecond float64       `json:"MaxHeartbeatsPerSecond,omitempty"`
+}
+
+// AgentClient is the client block of an agent's configuration
+type AgentClient struct {
+	Enabled   bool              `json:"Enabled"`
+	NodeClass string            `json:"NodeClass,omitempty"`
+	NodePool  string            `json:"NodePool,omitempty"`
+	Servers   []string          `json:"Servers,omitempty"`
+	Meta      map[string]string `json:"Meta,omitempty"`
+}
+
+// AgentACL is the acl block of an agent's configuration
+type AgentACL struct {
+	Enabled bool `json:"Enabled"`
+}
+
+// AgentTLS is the tls block of an agent's configuration, without key material
+type AgentTLS struct {
+	EnableHTTP           bool `json:"EnableHTTP"`
+	EnableRPC            bool `json:"EnableRPC"`
+	VerifyServerHostname bool `json:"VerifyServerHostname"`
+}
+
+// AgentVersion is the build an agent runs
+type AgentVersion struct {
+	Version           string `json:"Version"`
+	VersionPrerelease string `json:"VersionPrerelease,omitempty"`
+	VersionMetadata   string `json:"VersionMetadata,omitempty"`
+	Revision          string `json:"Revision,omitempty"`
+}
+
+// AgentHealth is the response of agent/health; a section is present for each role the
+// agent runs
+type AgentHealth struct {
+	Client *AgentHealthStatus `json:"client,omitempty"`
+	Server *AgentHealthStatus `json:"server,omitempty"`
+}
+
+// AgentHealthStatus reports whether one role of an agent is healthy
+type AgentHealthStatus struct {
+	OK      bool   `json:"ok"`
+	Message string `json:"message"`
+}
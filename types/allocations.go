@@ -9,8 +9,10 @@ type Allocation struct {
 	ID                 string                 `json:"ID"`
 	EvalID             string                 `json:"EvalID"`
 	Name               string                 `json:"Name"`
+	Namespace          string                 `json:"Namespace"`
 	NodeID             string                 `json:"NodeID"`
 	JobID              string                 `json:"JobID"`
+	JobType            string                 `json:"JobType"`
 	TaskGroup          string                 `json:"TaskGroup"`
 	DesiredStatus      string                 `json:"DesiredStatus"`
 	DesiredDescription string                 `json:"DesiredDescription"`
@@ -19,6 +21,7 @@ type Allocation struct {
 	TaskStates         map[string]TaskState   `json:"TaskStates"`
 	DeploymentID       string                 `json:"DeploymentID"`
 	DeploymentStatus   *AllocDeploymentStatus `json:"DeploymentStatus"`
+	AllocatedResources *AllocatedResources    `json:"AllocatedResources,omitempty"`
 	FollowupEvalID     string                 `json:"FollowupEvalID"`
 	RescheduleTracker  *RescheduleTracker     `json:"RescheduleTracker"`
 	NextAllocation     string                 `json:"NextAllocation"`
@@ -28,6 +31,59 @@ type Allocation struct {
 	ModifyTime         int64                  `json:"ModifyTime"`
 }
 
+// AllocationPlacement is an allocation with the metrics the scheduler recorded while placing it
+type AllocationPlacement struct {
+	ID        string       `json:"ID"`
+	Name      string       `json:"Name"`
+	Namespace string       `json:"Namespace"`
+	JobID     string       `json:"JobID"`
+	TaskGroup string       `json:"TaskGroup"`
+	EvalID    string       `json:"EvalID"`
+	NodeID    string       `json:"NodeID"`
+	NodeName  string       `json:"NodeName"`
+	Metrics   *AllocMetric `json:"Metrics"`
+}
+
+// AllocMetric is the scheduler's record of how it placed an allocation: how many nodes it
+// considered, why others were filtered or exhausted, and the scores of the best candidates
+type AllocMetric struct {
+	NodesEvaluated     int             `json:"NodesEvaluated"`
+	NodesFiltered      int             `json:"NodesFiltered"`
+	NodesInPool        int             `json:"NodesInPool"`
+	NodesAvailable     map[string]int  `json:"NodesAvailable,omitempty"`
+	ClassFiltered      map[string]int  `json:"ClassFiltered,omitempty"`
+	ConstraintFiltered map[string]int  `json:"ConstraintFiltered,omitempty"`
+	NodesExhausted     int             `json:"NodesExhausted"`
+	ClassExhausted     map[string]int  `json:"ClassExhausted,omitempty"`
+	DimensionExhausted map[string]int  `json:"DimensionExhausted,omitempty"`
+	QuotaExhausted     []string        `json:"QuotaExhausted,omitempty"`
+	ScoreMetaData      []NodeScoreMeta `json:"ScoreMetaData,omitempty"`
+	AllocationTime     time.Duration   `json:"AllocationTime"`
+	CoalescedFailures  int             `json:"CoalescedFailures"`
+}
+
+// NodeScoreMeta is the score of one candidate node, per scoring component and normalized
+type NodeScoreMeta struct {
+	NodeID    string             `json:"NodeID"`
+	Scores    map[string]float64 `json:"Scores"`
+	NormScore float64            `json:"NormScore"`
+}
+
+// AllocatedResources is the subset of an allocation's resources that records its ports
+type AllocatedResources struct {
+	Shared struct {
+		Ports []AllocatedPort `json:"Ports"`
+	} `json:"Shared"`
+}
+
+// AllocatedPort is a host port assigned to an allocation
+type AllocatedPort struct {
+	Label  string `json:"Label"`
+	Value  int    `json:"Value"`
+	To     int    `json:"To"`
+	HostIP string `json:"HostIP"`
+}
+
 // AllocDeploymentStatus represents the deployment status of an allocation
 type AllocDeploymentStatus struct {
 	Healthy     bool       `json:"Healthy"`
@@ -47,3 +103,32 @@ type RescheduleEvent struct {
 	PrevAllocID    string `json:"PrevAllocID"`
 	PrevNodeID     string `json:"PrevNodeID"`
 }
+
+// ExecRequest is a command to run inside a task of an allocation
+type ExecRequest struct {
+	AllocID string
+	Task    string
+	Command []string
+	// Stdin is written to the command's standard input, which is then closed
+	Stdin string
+	// MaxOutputBytes caps what is kept of stdout and stderr each; 0 means no limit
+	MaxOutputBytes int
+}
+
+// ExecResult is the outcome of a command run with client/allocation/:id/exec
+type ExecResult struct {
+	Stdout          string `json:"Stdout"`
+	Stderr          string `json:"Stderr"`
+	ExitCode        int    `json:"ExitCode"`
+	OutputTruncated bool   `json:"OutputTruncated,omitempty"`
+}
+
+// AllocFileInfo describes a file or directory in an allocation's filesystem
+type AllocFileInfo struct {
+	Name        string    `json:"Name"`
+	IsDir       bool      `json:"IsDir"`
+	Size        int64     `json:"Size"`
+	FileMode    string    `json:"FileMode"`
+	ModTime     time.Time `json:"ModTime"`
+	ContentType string    `json:"ContentType,omitempty"`
+}
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 type RaftOperator struct {
 	Address      string `json:"Address"`
 	ID           string `json:"ID"`
@@ -8,3 +10,118 @@ type RaftOperator struct {
 	RaftProtocol string `json:"RaftProtocol"`
 	Voter        bool   `json:"Voter"`
 }
+
+// RaftConfiguration is the response of operator/raft/configuration
+type RaftConfiguration struct {
+	Servers []RaftOperator `json:"Servers"`
+	Index   uint64         `json:"Index"`
+}
+
+// AgentMember is a server in the gossip pool as reported by agent/members
+type AgentMember struct {
+	Name   string            `json:"Name"`
+	Addr   string            `json:"Addr"`
+	Port   int               `json:"Port"`
+	Status string            `json:"Status"`
+	Tags   map[string]string `json:"Tags"`
+	// Gossip protocol versions the member speaks
+	ProtocolMin int `json:"ProtocolMin,omitempty"`
+	ProtocolMax int `json:"ProtocolMax,omitempty"`
+	ProtocolCur int `json:"ProtocolCur,omitempty"`
+	DelegateMin int `json:"DelegateMin,omitempty"`
+	DelegateMax int `json:"DelegateMax,omitempty"`
+	DelegateCur int `json:"DelegateCur,omitempty"`
+}
+
+// AutopilotConfiguration is the autopilot configuration of operator/autopilot/configuration.
+// Durations are Go duration strings, e.g. 200ms or 10s.
+type AutopilotConfiguration struct {
+	CleanupDeadServers      bool   `json:"CleanupDeadServers"`
+	LastContactThreshold    string `json:"LastContactThreshold"`
+	MaxTrailingLogs         uint64 `json:"MaxTrailingLogs"`
+	MinQuorum               uint   `json:"MinQuorum"`
+	ServerStabilizationTime string `json:"ServerStabilizationTime"`
+	// Enterprise only
+	EnableRedundancyZones   bool   `json:"EnableRedundancyZones"`
+	DisableUpgradeMigration bool   `json:"DisableUpgradeMigration"`
+	EnableCustomUpgrades    bool   `json:"EnableCustomUpgrades"`
+	CreateIndex             uint64 `json:"CreateIndex"`
+	ModifyIndex             uint64 `json:"ModifyIndex"`
+}
+
+// AutopilotHealth is the response of operator/autopilot/health
+type AutopilotHealth struct {
+	Healthy          bool                    `json:"Healthy"`
+	FailureTolerance int                     `json:"FailureTolerance"`
+	Servers          []AutopilotServerHealth `json:"Servers"`
+}
+
+// AutopilotServerHealth is the autopilot view of one server
+type AutopilotServerHealth struct {
+	ID          string `json:"ID"`
+	Name        string `json:"Name"`
+	Address     string `json:"Address"`
+	SerfStatus  string `json:"SerfStatus"`
+	Version     string `json:"Version"`
+	Leader      bool   `json:"Leader"`
+	LastContact string `json:"LastContact"`
+	LastTerm    uint64 `json:"LastTerm"`
+	LastIndex   uint64 `json:"LastIndex"`
+	Healthy     bool   `json:"Healthy"`
+	Voter       bool   `json:"Voter"`
+	StableSince string `json:"StableSince"`
+}
+
+// License is a Nomad Enterprise license as returned by operator/license
+type License struct {
+	LicenseID       string                 `json:"LicenseID"`
+	CustomerID      string                 `json:"CustomerID"`
+	InstallationID  string                 `json:"InstallationID"`
+	IssueTime       time.Time              `json:"IssueTime"`
+	StartTime       time.Time              `json:"StartTime"`
+	ExpirationTime  time.Time              `json:"ExpirationTime"`
+	TerminationTime time.Time              `json:"TerminationTime"`
+	Product         string                 `json:"Product"`
+	Modules         []string               `json:"Modules,omitempty"`
+	Features        []string               `json:"Features,omitempty"`
+	Flags           map[string]interface{} `json:"Flags,omitempty"`
+}
+
+// LicenseReply is the response of operator/license
+type LicenseReply struct {
+	License        *License `json:"License"`
+	ConfigOutdated bool     `json:"ConfigOutdated,omitempty"`
+	Warnings       []string `json:"Warnings,omitempty"`
+}
+
+// SchedulerConfiguration is the cluster scheduler configuration of
+// operator/scheduler/configuration
+type SchedulerConfiguration struct {
+	SchedulerAlgorithm            string           `json:"SchedulerAlgorithm"`
+	PreemptionConfig              PreemptionConfig `json:"PreemptionConfig"`
+	MemoryOversubscriptionEnabled bool             `json:"MemoryOversubscriptionEnabled"`
+	RejectJobRegistration         bool             `json:"RejectJobRegistration"`
+	PauseEvalBroker               bool             `json:"PauseEvalBroker"`
+}
+
+// PreemptionConfig selects the schedulers allowed to preempt lower priority allocations
+type PreemptionConfig struct {
+	SystemSchedulerEnabled   bool `json:"SystemSchedulerEnabled"`
+	SysBatchSchedulerEnabled bool `json:"SysBatchSchedulerEnabled"`
+	BatchSchedulerEnabled    bool `json:"BatchSchedulerEnabled"`
+	ServiceSchedulerEnabled  bool `json:"ServiceSchedulerEnabled"`
+}
+
+// Enabled reports whether the scheduler of jobType may preempt allocations.
+func (p PreemptionConfig) Enabled(jobType string) bool {
+	switch jobType {
+	case "system":
+		return p.SystemSchedulerEnabled
+	case "sysbatch":
+		return p.SysBatchSchedulerEnabled
+	case "batch":
+		return p.BatchSchedulerEnabled
+	default:
+		return p.ServiceSchedulerEnabled
+	}
+}
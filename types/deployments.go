@@ -1,21 +1,88 @@
 // File: types/deployments.go
 package types
 
+import "encoding/json"
+
 // DeploymentSummary represents a summary of a deployment
 type DeploymentSummary struct {
-	ID        string `json:"id"`
-	JobID     string `json:"job_id"`
-	Namespace string `json:"namespace"`
-	Status    string `json:"status"`
+	ID          string `json:"id"`
+	JobID       string `json:"job_id"`
+	Namespace   string `json:"namespace"`
+	Status      string `json:"status"`
+	ModifyIndex uint64 `json:"modify_index"`
+}
+
+// UnmarshalJSON decodes a deployment as returned by the Nomad API, whose keys are PascalCase.
+func (d *DeploymentSummary) UnmarshalJSON(data []byte) error {
+	var deployment struct {
+		ID          string `json:"ID"`
+		JobID       string `json:"JobID"`
+		Namespace   string `json:"Namespace"`
+		Status      string `json:"Status"`
+		ModifyIndex uint64 `json:"ModifyIndex"`
+	}
+	if err := json.Unmarshal(data, &deployment); err != nil {
+		return err
+	}
+	*d = DeploymentSummary(deployment)
+	return nil
 }
 
 // Deployment represents a detailed view of a deployment
 type Deployment struct {
-	ID         string                         `json:"id"`
-	JobID      string                         `json:"job_id"`
-	Namespace  string                         `json:"namespace"`
-	Status     string                         `json:"status"`
-	TaskGroups map[string]DeploymentTaskGroup `json:"task_groups"`
+	ID                string                         `json:"id"`
+	JobID             string                         `json:"job_id"`
+	JobVersion        int                            `json:"job_version"`
+	JobModifyIndex    int                            `json:"job_modify_index"`
+	Namespace         string                         `json:"namespace"`
+	Status            string                         `json:"status"`
+	StatusDescription string                         `json:"status_description,omitempty"`
+	TaskGroups        map[string]DeploymentTaskGroup `json:"task_groups"`
+	CreateIndex       uint64                         `json:"create_index"`
+	ModifyIndex       uint64                         `json:"modify_index"`
+}
+
+// UnmarshalJSON decodes a deployment as returned by the Nomad API, whose keys are PascalCase.
+func (d *Deployment) UnmarshalJSON(data []byte) error {
+	var deployment struct {
+		ID                string `json:"ID"`
+		JobID             string `json:"JobID"`
+		JobVersion        int    `json:"JobVersion"`
+		JobModifyIndex    int    `json:"JobModifyIndex"`
+		Namespace         string `json:"Namespace"`
+		Status            string `json:"Status"`
+		StatusDescription string `json:"StatusDescription"`
+		TaskGroups        map[string]struct {
+			DesiredTotal    int `json:"DesiredTotal"`
+			PlacedAllocs    int `json:"PlacedAllocs"`
+			HealthyAllocs   int `json:"HealthyAllocs"`
+			UnhealthyAllocs int `json:"UnhealthyAllocs"`
+		} `json:"TaskGroups"`
+		CreateIndex uint64 `json:"CreateIndex"`
+		ModifyIndex uint64 `json:"ModifyIndex"`
+	}
+	if err := json.Unmarshal(data, &deployment); err != nil {
+		return err
+	}
+
+	*d = Deployment{
+		ID:                deployment.ID,
+		JobID:             deployment.JobID,
+		JobVersion:        deployment.JobVersion,
+		JobModifyIndex:    deployment.JobModifyIndex,
+		Namespace:         deployment.Namespace,
+		Status:            deployment.Status,
+		StatusDescription: deployment.StatusDescription,
+		CreateIndex:       deployment.CreateIndex,
+		ModifyIndex:       deployment.ModifyIndex,
+	}
+	if deployment.TaskGroups != nil {
+		d.TaskGroups = make(map[string]DeploymentTaskGroup, len(deployment.TaskGroups))
+		for name, tg := range deployment.TaskGroups {
+			d.TaskGroups[name] = DeploymentTaskGroup(tg)
+		}
+	}
+	return nil
 }
 
 // DeploymentTaskGroup represents the deployment status of a task group
@@ -25,3 +92,14 @@ type DeploymentTaskGroup struct {
 	HealthyAllocs   int `json:"healthy_allocs"`
 	UnhealthyAllocs int `json:"unhealthy_allocs"`
 }
+
+// DeploymentUpdateResponse is Nomad's answer to promoting, failing, pausing or resuming a
+// deployment
+type DeploymentUpdateResponse struct {
+	DeploymentID          string `json:"DeploymentID"`
+	EvalID                string `json:"EvalID,omitempty"`
+	EvalCreateIndex       uint64 `json:"EvalCreateIndex,omitempty"`
+	DeploymentModifyIndex uint64 `json:"DeploymentModifyIndex,omitempty"`
+	// RevertedJobVersion is set when failing the deployment rolled the job back
+	RevertedJobVersion *uint64 `json:"RevertedJobVersion,omitempty"`
+}
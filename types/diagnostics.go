@@ -0,0 +1,48 @@
+package types
+
+// ConnectionDiagnosis is the readiness matrix reported by diagnose_connection
+type ConnectionDiagnosis struct {
+	Address     string          `json:"Address"`
+	Ready       bool            `json:"Ready"`
+	Issues      []string        `json:"Issues,omitempty"`
+	Reachable   bool            `json:"Reachable"`
+	Leader      string          `json:"Leader,omitempty"`
+	LatencyMs   int64           `json:"LatencyMs,omitempty"`
+	Error       string          `json:"Error,omitempty"`
+	TLS         *TLSDiagnosis   `json:"TLS,omitempty"`
+	Token       TokenDiagnosis  `json:"Token"`
+	APIFamilies []APIFamilyPing `json:"APIFamilies"`
+}
+
+// TLSDiagnosis describes the certificate presented by an https Nomad address
+type TLSDiagnosis struct {
+	Verified   bool   `json:"Verified"`
+	SkipVerify bool   `json:"SkipVerify"`
+	ServerName string `json:"ServerName,omitempty"`
+	Subject    string `json:"Subject,omitempty"`
+	Issuer     string `json:"Issuer,omitempty"`
+	NotAfter   string `json:"NotAfter,omitempty"`
+	Error      string `json:"Error,omitempty"`
+}
+
+// TokenDiagnosis is the outcome of resolving the configured token via acl/token/self
+type TokenDiagnosis struct {
+	Configured     bool     `json:"Configured"`
+	ACLEnabled     bool     `json:"ACLEnabled"`
+	Valid          bool     `json:"Valid"`
+	AccessorID     string   `json:"AccessorID,omitempty"`
+	Name           string   `json:"Name,omitempty"`
+	Type           string   `json:"Type,omitempty"`
+	Policies       []string `json:"Policies,omitempty"`
+	ExpirationTime string   `json:"ExpirationTime,omitempty"`
+	Error          string   `json:"Error,omitempty"`
+}
+
+// APIFamilyPing is the result of probing one family of Nomad API endpoints
+type APIFamilyPing struct {
+	Family     string `json:"Family"`
+	Path       string `json:"Path"`
+	Status     string `json:"Status"` // ok, forbidden or error
+	HTTPStatus int    `json:"HTTPStatus,omitempty"`
+	Error      string `json:"Error,omitempty"`
+}
@@ -0,0 +1,29 @@
+package types
+
+import "encoding/json"
+
+// EventStreamRequest selects the events read from event/stream
+type EventStreamRequest struct {
+	// Topics are Topic or Topic:key filters, e.g. "Job", "Job:web" or "*"; empty means all
+	Topics    []string
+	Namespace string
+	// Index is the Raft index to start from; 0 streams only new events
+	Index uint64
+}
+
+// Event is one entry of the Nomad event stream
+type Event struct {
+	Topic      string          `json:"Topic"`
+	Type       string          `json:"Type"`
+	Key        string          `json:"Key"`
+	Namespace  string          `json:"Namespace,omitempty"`
+	FilterKeys []string        `json:"FilterKeys,omitempty"`
+	Index      uint64          `json:"Index"`
+	Payload    json.RawMessage `json:"Payload,omitempty"`
+}
+
+// EventFrame is one frame of the event stream: the events committed at one Raft index
+type EventFrame struct {
+	Index  uint64  `json:"Index"`
+	Events []Event `json:"Events"`
+}
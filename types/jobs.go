@@ -1,13 +1,22 @@
 // File: types/jobs.go
 package types
 
-// JobSummary represents a summary of a Nomad job
+// JobSummary represents a summary of a Nomad job.
+// The list-stub fields (Name through SubmitTime) are only populated by GET /v1/jobs.
 type JobSummary struct {
-	ID          string                 `json:"ID"`
-	Summary     map[string]TaskSummary `json:"Summary"`
-	Children    *JobChildrenSummary    `json:"Children"`
-	CreateIndex int                    `json:"CreateIndex"`
-	ModifyIndex int                    `json:"ModifyIndex"`
+	ID               string                 `json:"ID"`
+	ParentID         string                 `json:"ParentID,omitempty"`
+	Name             string                 `json:"Name,omitempty"`
+	Namespace        string                 `json:"Namespace,omitempty"`
+	Type             string                 `json:"Type,omitempty"`
+	Status           string                 `json:"Status,omitempty"`
+	Periodic         bool                   `json:"Periodic,omitempty"`
+	ParameterizedJob bool                   `json:"ParameterizedJob,omitempty"`
+	SubmitTime       int64                  `json:"SubmitTime,omitempty"`
+	Summary          map[string]TaskSummary `json:"Summary"`
+	Children         *JobChildrenSummary    `json:"Children"`
+	CreateIndex      int                    `json:"CreateIndex"`
+	ModifyIndex      int                    `json:"ModifyIndex"`
 }
 
 // JobSummaryDetails represents detailed summary information for a job
@@ -42,6 +51,7 @@ type Job struct {
 	ID             string            `json:"ID"`
 	ParentID       string            `json:"ParentID"`
 	Name           string            `json:"Name"`
+	Namespace      string            `json:"Namespace,omitempty"`
 	Type           string            `json:"Type"`
 	Priority       int               `json:"Priority"`
 	Status         string            `json:"Status"`
@@ -51,12 +61,93 @@ type Job struct {
 	Update         *Update           `json:"Update"`
 	Periodic       *Periodic         `json:"Periodic"`
 	Parameterized  *Parameterized    `json:"Parameterized"`
+	Constraints    []Constraint      `json:"Constraints,omitempty"`
+	Affinities     []Affinity        `json:"Affinities,omitempty"`
+	Spreads        []Spread          `json:"Spreads,omitempty"`
 	Meta           map[string]string `json:"Meta"`
+	Version        int               `json:"Version"`
+	Stable         bool              `json:"Stable,omitempty"`
+	SubmitTime     int64             `json:"SubmitTime,omitempty"`
 	CreateIndex    int               `json:"CreateIndex"`
 	ModifyIndex    int               `json:"ModifyIndex"`
 	JobModifyIndex int               `json:"JobModifyIndex"`
 }
 
+// JobSubmission is the source a job version was registered from, as stored by Nomad
+type JobSubmission struct {
+	Source        string            `json:"Source"`
+	Format        string            `json:"Format"`
+	VariableFlags map[string]string `json:"VariableFlags,omitempty"`
+	Variables     string            `json:"Variables,omitempty"`
+}
+
+// JobVersionsResponse is Nomad's response to a job versions request with diffs: the versions
+// newest first, and Diffs[i] the changes from Versions[i+1] to Versions[i]
+type JobVersionsResponse struct {
+	Versions []Job     `json:"Versions"`
+	Diffs    []JobDiff `json:"Diffs"`
+}
+
+// JobRegisterResponse is Nomad's response to a job registration
+type JobRegisterResponse struct {
+	EvalID          string `json:"EvalID"`
+	EvalCreateIndex int    `json:"EvalCreateIndex"`
+	JobModifyIndex  int    `json:"JobModifyIndex"`
+	// Warnings are problems Nomad accepted the job despite, e.g. deprecated fields
+	Warnings string `json:"Warnings,omitempty"`
+}
+
+// JobDeregisterResponse is Nomad's response to stopping or purging a job
+type JobDeregisterResponse struct {
+	EvalID          string `json:"EvalID"`
+	EvalCreateIndex int    `json:"EvalCreateIndex"`
+	JobModifyIndex  int    `json:"JobModifyIndex"`
+	Warnings        string `json:"Warnings,omitempty"`
+}
+
+// JobStopOptions are the query parameters of a job deregistration
+type JobStopOptions struct {
+	// Purge removes the job from Nomad instead of marking it stopped
+	Purge bool
+	// Global stops every region of a multi-region job
+	Global bool
+	// NoShutdownDelay skips the shutdown_delay of the job's tasks and groups
+	NoShutdownDelay bool
+}
+
+// JobDispatchOptions are the optional fields of a parameterized job dispatch
+type JobDispatchOptions struct {
+	// Payload is passed to the dispatched job as a file; Nomad limits it to 16 KiB
+	Payload []byte
+	Meta    map[string]string
+	// IdempotencyToken makes Nomad skip the dispatch while a child with the same token exists
+	IdempotencyToken string
+}
+
+// JobDispatchResponse is Nomad's response to dispatching a parameterized job
+type JobDispatchResponse struct {
+	DispatchedJobID string `json:"DispatchedJobID"`
+	EvalID          string `json:"EvalID"`
+	EvalCreateIndex int    `json:"EvalCreateIndex"`
+	JobCreateIndex  int    `json:"JobCreateIndex"`
+}
+
+// PeriodicForceResponse is Nomad's response to forcing a launch of a periodic job
+type PeriodicForceResponse struct {
+	EvalID          string `json:"EvalID"`
+	EvalCreateIndex int    `json:"EvalCreateIndex"`
+}
+
+// JobRevertOptions are the optional fields of a job revert request
+type JobRevertOptions struct {
+	// EnforcePriorVersion, if set, makes Nomad revert only while the job is at this version
+	EnforcePriorVersion *int
+	// ConsulToken and VaultToken prove access to the Consul and Vault policies the
+	// reverted version uses, when the cluster requires it
+	ConsulToken string
+	VaultToken  string
+}
+
 // Update represents the update strategy for a job
 type Update struct {
 	Stagger          int    `json:"Stagger"`
@@ -69,6 +160,14 @@ type Update struct {
 	Canary           int    `json:"Canary"`
 }
 
+// MigrateStrategy represents how a task group's allocations are migrated off draining nodes
+type MigrateStrategy struct {
+	MaxParallel     int    `json:"MaxParallel"`
+	HealthCheck     string `json:"HealthCheck"`
+	MinHealthyTime  int    `json:"MinHealthyTime"`
+	HealthyDeadline int    `json:"HealthyDeadline"`
+}
+
 // Periodic represents periodic job configuration
 type Periodic struct {
 	Enabled         bool   `json:"Enabled"`
@@ -112,6 +211,10 @@ type TaskGroup struct {
 	ReschedulePolicy *ReschedulePolicy          `json:"ReschedulePolicy"`
 	EphemeralDisk    *EphemeralDisk             `json:"EphemeralDisk"`
 	Update           *Update                    `json:"Update"`
+	Migrate          *MigrateStrategy           `json:"Migrate,omitempty"`
+	Constraints      []Constraint               `json:"Constraints,omitempty"`
+	Affinities       []Affinity                 `json:"Affinities,omitempty"`
+	Spreads          []Spread                   `json:"Spreads,omitempty"`
 	Meta             map[string]string          `json:"Meta"`
 }
 
@@ -157,7 +260,57 @@ type ServiceCheck struct {
 
 // ConsulConnect represents Consul Connect configuration
 type ConsulConnect struct {
-	Native bool `json:"Native"`
+	Native         bool                   `json:"Native"`
+	SidecarService *ConsulSidecarService  `json:"SidecarService,omitempty"`
+	SidecarTask    *SidecarTask           `json:"SidecarTask,omitempty"`
+	Gateway        map[string]interface{} `json:"Gateway,omitempty"`
+}
+
+// ConsulSidecarService represents the sidecar_service block of a Connect service
+type ConsulSidecarService struct {
+	Tags                   []string     `json:"Tags,omitempty"`
+	Port                   string       `json:"Port,omitempty"`
+	DisableDefaultTCPCheck bool         `json:"DisableDefaultTCPCheck,omitempty"`
+	Proxy                  *ConsulProxy `json:"Proxy,omitempty"`
+}
+
+// ConsulProxy represents the proxy block of a Connect sidecar service
+type ConsulProxy struct {
+	LocalServiceAddress string                 `json:"LocalServiceAddress,omitempty"`
+	LocalServicePort    int                    `json:"LocalServicePort,omitempty"`
+	Upstreams           []ConsulUpstream       `json:"Upstreams,omitempty"`
+	Expose              *ConsulExposeConfig    `json:"Expose,omitempty"`
+	Config              map[string]interface{} `json:"Config,omitempty"`
+}
+
+// ConsulUpstream represents a Connect upstream
+type ConsulUpstream struct {
+	DestinationName      string `json:"DestinationName"`
+	DestinationNamespace string `json:"DestinationNamespace,omitempty"`
+	Datacenter           string `json:"Datacenter,omitempty"`
+	LocalBindAddress     string `json:"LocalBindAddress,omitempty"`
+	LocalBindPort        int    `json:"LocalBindPort"`
+}
+
+// ConsulExposeConfig represents the expose block of a Connect proxy
+type ConsulExposeConfig struct {
+	Paths []ConsulExposePath `json:"Paths,omitempty"`
+}
+
+// ConsulExposePath is an HTTP path exposed through the sidecar without mTLS
+type ConsulExposePath struct {
+	Path          string `json:"Path"`
+	Protocol      string `json:"Protocol,omitempty"`
+	LocalPathPort int    `json:"LocalPathPort"`
+	ListenerPort  string `json:"ListenerPort"`
+}
+
+// SidecarTask represents the sidecar_task overrides of a Connect service
+type SidecarTask struct {
+	Name      string                 `json:"Name,omitempty"`
+	Driver    string                 `json:"Driver,omitempty"`
+	Config    map[string]interface{} `json:"Config,omitempty"`
+	Resources *Resources             `json:"Resources,omitempty"`
 }
 
 // RestartPolicy represents the restart policy for a task group
@@ -190,14 +343,20 @@ type EphemeralDisk struct {
 type Task struct {
 	Name            string                 `json:"Name"`
 	Driver          string                 `json:"Driver"`
+	Kind            string                 `json:"Kind,omitempty"`
 	User            string                 `json:"User"`
 	Config          map[string]interface{} `json:"Config"`
+	Env             map[string]string      `json:"Env,omitempty"`
 	Resources       Resources              `json:"Resources"`
 	Services        []Service              `json:"Services"`
 	Vault           *Vault                 `json:"Vault"`
 	Templates       []Template             `json:"Templates"`
+	Artifacts       []TaskArtifact         `json:"Artifacts,omitempty"`
 	DispatchPayload *DispatchPayload       `json:"DispatchPayload"`
 	Lifecycle       *TaskLifecycle         `json:"Lifecycle"`
+	RestartPolicy   *RestartPolicy         `json:"RestartPolicy,omitempty"`
+	Constraints     []Constraint           `json:"Constraints,omitempty"`
+	Affinities      []Affinity             `json:"Affinities,omitempty"`
 	Meta            map[string]string      `json:"Meta"`
 }
 
@@ -243,9 +402,24 @@ type Affinity struct {
 	Weight  int    `json:"Weight"`
 }
 
+// Spread represents a spread block
+type Spread struct {
+	Attribute    string         `json:"Attribute"`
+	Weight       int            `json:"Weight"`
+	SpreadTarget []SpreadTarget `json:"SpreadTarget,omitempty"`
+}
+
+// SpreadTarget is the desired share of allocations for one attribute value
+type SpreadTarget struct {
+	Value   string `json:"Value"`
+	Percent int    `json:"Percent"`
+}
+
 // Vault represents Vault configuration for a task
 type Vault struct {
 	Policies     []string `json:"Policies"`
+	Role         string   `json:"Role,omitempty"`
+	Namespace    string   `json:"Namespace,omitempty"`
 	Env          bool     `json:"Env"`
 	ChangeMode   string   `json:"ChangeMode"`
 	ChangeSignal string   `json:"ChangeSignal"`
@@ -267,6 +441,15 @@ type Template struct {
 	Wait         *WaitConfig `json:"Wait"`
 }
 
+// TaskArtifact represents an artifact fetched by go-getter before a task starts
+type TaskArtifact struct {
+	GetterSource  string            `json:"GetterSource"`
+	GetterOptions map[string]string `json:"GetterOptions,omitempty"`
+	GetterHeaders map[string]string `json:"GetterHeaders,omitempty"`
+	GetterMode    string            `json:"GetterMode,omitempty"`
+	RelativeDest  string            `json:"RelativeDest,omitempty"`
+}
+
 // WaitConfig represents template wait configuration
 type WaitConfig struct {
 	Min string `json:"Min"`
@@ -294,6 +477,7 @@ type Evaluation struct {
 	JobModifyIndex       int                    `json:"JobModifyIndex"`
 	NodeID               string                 `json:"NodeID"`
 	NodeModifyIndex      int                    `json:"NodeModifyIndex"`
+	DeploymentID         string                 `json:"DeploymentID,omitempty"`
 	Status               string                 `json:"Status"`
 	StatusDescription    string                 `json:"StatusDescription"`
 	Wait                 int                    `json:"Wait"`
@@ -308,6 +492,17 @@ type Evaluation struct {
 	SnapshotIndex        int                    `json:"SnapshotIndex"`
 	CreateIndex          int                    `json:"CreateIndex"`
 	ModifyIndex          int                    `json:"ModifyIndex"`
+	CreateTime           int64                  `json:"CreateTime"`
+	ModifyTime           int64                  `json:"ModifyTime"`
+}
+
+// EvaluationFilter narrows an evaluation listing server-side; empty fields match everything
+type EvaluationFilter struct {
+	Status      string
+	JobID       string
+	TriggeredBy []string // any of these trigger types
+	// Expression is a raw filter expression, combined with the fields above
+	Expression string
 }
 
 // JobDeployment represents a Nomad deployment
@@ -401,6 +596,8 @@ type TaskDiff struct {
 // PlanAnnotations represents annotations for a plan
 type PlanAnnotations struct {
 	DesiredTGUpdates map[string]DesiredUpdates `json:"DesiredTGUpdates"`
+	// PreemptedAllocs are the allocations of other jobs the plan would preempt
+	PreemptedAllocs []Allocation `json:"PreemptedAllocs,omitempty"`
 }
 
 // DesiredUpdates represents desired updates for a task group
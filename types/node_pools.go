@@ -0,0 +1,32 @@
+// File: types/node_pools.go
+package types
+
+// NodePool is a Nomad node pool, a named group of client nodes jobs can be placed in
+type NodePool struct {
+	Name                   string                          `json:"Name"`
+	Description            string                          `json:"Description,omitempty"`
+	Meta                   map[string]string               `json:"Meta,omitempty"`
+	SchedulerConfiguration *NodePoolSchedulerConfiguration `json:"SchedulerConfiguration,omitempty"`
+	CreateIndex            uint64                          `json:"CreateIndex,omitempty"`
+	ModifyIndex            uint64                          `json:"ModifyIndex,omitempty"`
+}
+
+// NodePoolSchedulerConfiguration overrides the cluster scheduler configuration for the jobs
+// of a node pool (Nomad Enterprise)
+type NodePoolSchedulerConfiguration struct {
+	SchedulerAlgorithm            string `json:"SchedulerAlgorithm,omitempty"`
+	MemoryOversubscriptionEnabled *bool  `json:"MemoryOversubscriptionEnabled,omitempty"`
+}
+
+// NodeListStub is a node as returned by Nomad's node list endpoints
+type NodeListStub struct {
+	ID                    string `json:"ID"`
+	Name                  string `json:"Name"`
+	Datacenter            string `json:"Datacenter"`
+	NodeClass             string `json:"NodeClass,omitempty"`
+	NodePool              string `json:"NodePool"`
+	Status                string `json:"Status"`
+	SchedulingEligibility string `json:"SchedulingEligibility"`
+	Drain                 bool   `json:"Drain"`
+	Version               string `json:"Version,omitempty"`
+}
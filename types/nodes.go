@@ -1,6 +1,8 @@
 // File: types/nodes.go
 package types
 
+import "time"
+
 // NodeSummary represents a summary of a Nomad node
 type NodeSummary struct {
 	ID         string `json:"id"`
@@ -24,6 +26,93 @@ type Node struct {
 	Meta       map[string]string `json:"meta"`
 }
 
+// Node scheduling eligibility values accepted by the Nomad API.
+const (
+	NodeSchedulingEligible   = "eligible"
+	NodeSchedulingIneligible = "ineligible"
+)
+
+// NodePurgeResponse is the outcome of purging a node from the cluster state
+type NodePurgeResponse struct {
+	NodeID          string   `json:"NodeID"`
+	EvalIDs         []string `json:"EvalIDs,omitempty"`
+	EvalCreateIndex uint64   `json:"EvalCreateIndex,omitempty"`
+	NodeModifyIndex uint64   `json:"NodeModifyIndex,omitempty"`
+}
+
+// NodeEligibilityUpdate is the outcome of changing a node's scheduling eligibility
+type NodeEligibilityUpdate struct {
+	NodeID                string   `json:"NodeID"`
+	Status                string   `json:"Status,omitempty"`
+	SchedulingEligibility string   `json:"SchedulingEligibility,omitempty"`
+	EvalIDs               []string `json:"EvalIDs,omitempty"`
+	EvalCreateIndex       uint64   `json:"EvalCreateIndex,omitempty"`
+	NodeModifyIndex       uint64   `json:"NodeModifyIndex,omitempty"`
+	// StatusError says why Status and SchedulingEligibility are missing: the node could not be
+	// read back after the change, which still took effect
+	StatusError string `json:"StatusError,omitempty"`
+}
+
+// NodeDetail is the node as the scheduler sees it: identity, placement targets and
+// fingerprinted attributes, decoded with Nomad's own field names
+type NodeDetail struct {
+	ID                    string                 `json:"ID"`
+	Name                  string                 `json:"Name"`
+	Datacenter            string                 `json:"Datacenter"`
+	NodeClass             string                 `json:"NodeClass"`
+	NodePool              string                 `json:"NodePool"`
+	Status                string                 `json:"Status"`
+	SchedulingEligibility string                 `json:"SchedulingEligibility"`
+	Drain                 bool                   `json:"Drain"`
+	Attributes            map[string]string      `json:"Attributes"`
+	Meta                  map[string]string      `json:"Meta"`
+	NodeResources         *NodePortResources     `json:"NodeResources,omitempty"`
+	ReservedResources     *NodeReservedResources `json:"ReservedResources,omitempty"`
+}
+
+// NodePortResources is the port configuration of a node's NodeResources
+type NodePortResources struct {
+	MinDynamicPort int `json:"MinDynamicPort"`
+	MaxDynamicPort int `json:"MaxDynamicPort"`
+}
+
+// NodeReservedResources is the part of a node's reserved resources that affects ports
+type NodeReservedResources struct {
+	Networks struct {
+		ReservedHostPorts string `json:"ReservedHostPorts"`
+	} `json:"Networks"`
+}
+
+// NodeConnectivity is the part of a node that records its heartbeats: its status, when the
+// status last changed and the node events, which include missed heartbeats
+type NodeConnectivity struct {
+	ID                string      `json:"ID"`
+	Name              string      `json:"Name"`
+	Datacenter        string      `json:"Datacenter"`
+	HTTPAddr          string      `json:"HTTPAddr"`
+	Status            string      `json:"Status"`
+	StatusDescription string      `json:"StatusDescription,omitempty"`
+	StatusUpdatedAt   int64       `json:"StatusUpdatedAt"`
+	Events            []NodeEvent `json:"Events"`
+}
+
+// NodeEvent is an event Nomad recorded on a node, such as a missed heartbeat or a drain
+type NodeEvent struct {
+	Message     string            `json:"Message"`
+	Subsystem   string            `json:"Subsystem"`
+	Details     map[string]string `json:"Details,omitempty"`
+	Timestamp   time.Time         `json:"Timestamp"`
+	CreateIndex uint64            `json:"CreateIndex"`
+}
+
+// DatacenterSummary counts the client nodes registered in one datacenter
+type DatacenterSummary struct {
+	Datacenter string `json:"Datacenter"`
+	Nodes      int    `json:"Nodes"`
+	Ready      int    `json:"Ready"`
+	Down       int    `json:"Down"`
+}
+
 // NodeResources represents the resources of a node
 type NodeResources struct {
 	CPU      int `json:"cpu"`
@@ -0,0 +1,41 @@
+// File: types/quotas.go
+package types
+
+// QuotaSpec is a Nomad Enterprise resource quota, attached to namespaces to cap what their
+// jobs may use
+type QuotaSpec struct {
+	Name        string       `json:"Name"`
+	Description string       `json:"Description,omitempty"`
+	Limits      []QuotaLimit `json:"Limits"`
+	CreateIndex uint64       `json:"CreateIndex,omitempty"`
+	ModifyIndex uint64       `json:"ModifyIndex,omitempty"`
+}
+
+// QuotaLimit is the limit of a quota in one region. In a QuotaUsage it holds the resources
+// in use instead.
+type QuotaLimit struct {
+	Region      string          `json:"Region"`
+	RegionLimit *QuotaResources `json:"RegionLimit,omitempty"`
+	// VariablesLimit is the maximum size of the variables in the quota's namespaces, in MiB
+	VariablesLimit *int   `json:"VariablesLimit,omitempty"`
+	Hash           []byte `json:"Hash,omitempty"`
+}
+
+// QuotaResources are the resources a quota limit covers. 0 means unlimited and -1 means the
+// resource may not be used at all.
+type QuotaResources struct {
+	CPU         int `json:"CPU,omitempty"`
+	Cores       int `json:"Cores,omitempty"`
+	MemoryMB    int `json:"MemoryMB,omitempty"`
+	MemoryMaxMB int `json:"MemoryMaxMB,omitempty"`
+	DiskMB      int `json:"DiskMB,omitempty"`
+}
+
+// QuotaUsage is the current usage of a quota, keyed by the hash of the limit it counts
+// against
+type QuotaUsage struct {
+	Name        string                 `json:"Name"`
+	Used        map[string]*QuotaLimit `json:"Used"`
+	CreateIndex uint64                 `json:"CreateIndex,omitempty"`
+	ModifyIndex uint64                 `json:"ModifyIndex,omitempty"`
+}
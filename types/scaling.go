@@ -0,0 +1,27 @@
+// File: types/scaling.go
+package types
+
+// ScalingPolicyListStub is a scaling policy as listed by /v1/scaling/policies
+type ScalingPolicyListStub struct {
+	ID          string            `json:"ID"`
+	Enabled     bool              `json:"Enabled"`
+	Type        string            `json:"Type"`
+	Target      map[string]string `json:"Target"`
+	CreateIndex uint64            `json:"CreateIndex,omitempty"`
+	ModifyIndex uint64            `json:"ModifyIndex,omitempty"`
+}
+
+// ScalingPolicy is the scaling block of a task group (or, for vertical policies, a task),
+// as read by the Nomad Autoscaler. Target holds the Namespace, Job, Group and Task it scales.
+type ScalingPolicy struct {
+	ID          string                 `json:"ID"`
+	Namespace   string                 `json:"Namespace,omitempty"`
+	Type        string                 `json:"Type"`
+	Target      map[string]string      `json:"Target"`
+	Min         *int64                 `json:"Min,omitempty"`
+	Max         *int64                 `json:"Max,omitempty"`
+	Enabled     bool                   `json:"Enabled"`
+	Policy      map[string]interface{} `json:"Policy,omitempty"`
+	CreateIndex uint64                 `json:"CreateIndex,omitempty"`
+	ModifyIndex uint64                 `json:"ModifyIndex,omitempty"`
+}
@@ -0,0 +1,45 @@
+// File: types/services.go
+package types
+
+// ServiceRegistration is one instance of a service registered with Nomad's built-in service
+// discovery (provider = "nomad"), placed by an allocation
+type ServiceRegistration struct {
+	ID          string   `json:"ID"`
+	ServiceName string   `json:"ServiceName"`
+	Namespace   string   `json:"Namespace"`
+	NodeID      string   `json:"NodeID"`
+	Datacenter  string   `json:"Datacenter"`
+	JobID       string   `json:"JobID"`
+	AllocID     string   `json:"AllocID"`
+	Tags        []string `json:"Tags,omitempty"`
+	Address     string   `json:"Address"`
+	Port        int      `json:"Port"`
+	CreateIndex uint64   `json:"CreateIndex"`
+	ModifyIndex uint64   `json:"ModifyIndex"`
+}
+
+// ServiceRegistrationListStub groups the registered service names of a namespace
+type ServiceRegistrationListStub struct {
+	Namespace string                    `json:"Namespace"`
+	Services  []ServiceRegistrationStub `json:"Services"`
+}
+
+// ServiceRegistrationStub is a registered service name with the tags of its instances
+type ServiceRegistrationStub struct {
+	ServiceName string   `json:"ServiceName"`
+	Tags        []string `json:"Tags,omitempty"`
+}
+
+// AllocCheckStatus is the latest result of a Nomad service check run by an allocation
+type AllocCheckStatus struct {
+	ID         string `json:"ID"`
+	Check      string `json:"Check"`
+	Group      string `json:"Group"`
+	Task       string `json:"Task,omitempty"`
+	Service    string `json:"Service"`
+	Mode       string `json:"Mode"`
+	Status     string `json:"Status"`
+	StatusCode int    `json:"StatusCode,omitempty"`
+	Output     string `json:"Output,omitempty"`
+	Timestamp  int64  `json:"Timestamp"`
+}
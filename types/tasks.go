@@ -38,3 +38,36 @@ type TaskEvent struct {
 	DriverMessage    string `json:"DriverMessage"`
 	GenericSource    string `json:"GenericSource"`
 }
+
+// LogStreamRequest selects the task log followed with client/fs/logs or paged through
+// with ReadAllocationLogs
+type LogStreamRequest struct {
+	AllocID string
+	Task    string
+	// Type is stdout or stderr
+	Type string
+	// Origin is start or end; Offset counts from it
+	Origin string
+	Offset int64
+}
+
+// LogFrame is one frame of a followed task log
+type LogFrame struct {
+	Data []byte `json:"Data,omitempty"` // base64 on the wire
+	File string `json:"File,omitempty"`
+	// Offset is the file offset just past Data
+	Offset int64 `json:"Offset"`
+	// FileEvent reports "file deleted" or "file truncated" instead of data
+	FileEvent string `json:"FileEvent,omitempty"`
+}
+
+// LogPage is a byte range of a task log. Offsets count bytes across the task's retained
+// log files (alloc/logs/<task>.<type>.<n>), oldest first.
+type LogPage struct {
+	Data []byte
+	// Offset is where Data starts and NextOffset where it ends
+	Offset     int64
+	NextOffset int64
+	// Size is the total size of the retained log files
+	Size int64
+}
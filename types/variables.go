@@ -5,4 +5,9 @@ type Variable struct {
 	Path      string `json:"Path"`
 	Value     string `json:"Value"`
 	Namespace string `json:"Namespace"`
+	// Items, CreateIndex and ModifyIndex are returned by Nomad; Value is the request body
+	// CreateVariable sends.
+	Items       map[string]string `json:"Items,omitempty"`
+	CreateIndex uint64            `json:"CreateIndex,omitempty"`
+	ModifyIndex uint64            `json:"ModifyIndex,omitempty"`
 }
@@ -16,6 +16,15 @@ type Volume struct {
 	ModifyIndex           int                `json:"ModifyIndex"`
 }
 
+// VolumeStub is a volume as listed by /v1/volumes
+type VolumeStub struct {
+	ID        string `json:"ID"`
+	Name      string `json:"Name"`
+	Namespace string `json:"Namespace"`
+	PluginID  string `json:"PluginID,omitempty"`
+	Type      string `json:"Type"` // csi or host; set by the client
+}
+
 // VolumeTopology represents the topology of a volume
 type VolumeTopology struct {
 	Segments map[string]string `json:"Segments"`
@@ -14,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -21,10 +22,16 @@ import (
 // It provides methods for managing jobs, deployments, namespaces, nodes, allocations,
 // variables, volumes, and ACL tokens.
 type NomadClient struct {
-	address          string
+	// addressMu guards address, which start_dev_agent changes while other calls run
+	addressMu sync.RWMutex
+	address   string
+	// tokenMu guards token, which a server-scope ACL bootstrap changes while other calls run
+	tokenMu          sync.RWMutex
 	token            string
 	httpClient       *http.Client
-	DefaultTailLines int // Default number of lines to show when tailing logs
+	DefaultTailLines int   // Default number of lines to show when tailing logs
+	MaxLogBytes      int64 // Maximum bytes of log output read in one call
+	compat           apiCompat
 }
 
 // NewNomadClient creates a new Nomad client with the specified address and token.
@@ -37,13 +44,29 @@ type NomadClient struct {
 //	    log.Fatal(err)
 //	}
 func NewNomadClient(address, token string) (*NomadClient, error) {
+	client, err := NewLazyNomadClient(address, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Test the connection
+	_, err = client.makeRequest(context.Background(), "GET", "status/leader", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Nomad server: %w", err)
+	}
+
+	return client, nil
+}
+
+// NewLazyNomadClient creates a client like NewNomadClient without testing the connection,
+// for a server started before its Nomad agent (see start_dev_agent).
+func NewLazyNomadClient(address, token string) (*NomadClient, error) {
 	// Validate the address
 	if address == "" {
 		return nil, fmt.Errorf("nomad address is required")
 	}
 
-	// Create the client
-	client := &NomadClient{
+	return &NomadClient{
 		address: address,
 		token:   token,
 		httpClient: &http.Client{
@@ -52,25 +75,40 @@ func NewNomadClient(address, token string) (*NomadClient, error) {
 				TLSClientConfig: buildTLSConfig(),
 			},
 		},
-		DefaultTailLines: 100, // Default to showing last 100 lines
-	}
+		DefaultTailLines: 100,     // Default to showing last 100 lines
+		MaxLogBytes:      1 << 20, // Default to reading at most 1 MiB of logs
+	}, nil
+}
 
-	// Test the connection
-	_, err := client.makeRequest(context.Background(), "GET", "status/leader", nil, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to Nomad server: %w", err)
-	}
+// SetAddress points the client at another Nomad agent. A detected Nomad version is
+// forgotten; a pinned one is kept.
+func (c *NomadClient) SetAddress(address string) {
+	c.addressMu.Lock()
+	c.address = address
+	c.addressMu.Unlock()
+	c.compat.mu.Lock()
+	defer c.compat.mu.Unlock()
+	c.compat.reset()
+}
 
-	return client, nil
+// GetAddress returns the address of the Nomad agent the client talks to
+func (c *NomadClient) GetAddress() string {
+	c.addressMu.RLock()
+	defer c.addressMu.RUnlock()
+	return c.address
 }
 
 // SetToken sets the ACL token for the client
 func (c *NomadClient) SetToken(token string) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
 	c.token = token
 }
 
 // GetToken returns the current ACL token
 func (c *NomadClient) GetToken() string {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
 	return c.token
 }
 
@@ -88,6 +126,20 @@ func (c *NomadClient) GetDefaultTailLines() int {
 	return c.DefaultTailLines
 }
 
+// SetMaxLogBytes sets the maximum number of bytes of log output read in one call
+func (c *NomadClient) SetMaxLogBytes(bytes int64) error {
+	if bytes <= 0 {
+		return fmt.Errorf("number of bytes must be positive")
+	}
+	c.MaxLogBytes = bytes
+	return nil
+}
+
+// GetMaxLogBytes returns the maximum number of bytes of log output read in one call
+func (c *NomadClient) GetMaxLogBytes() int64 {
+	return c.MaxLogBytes
+}
+
 // buildTLSConfig constructs a *tls.Config from the standard NOMAD_* TLS environment
 // variables, matching the behavior of the official Nomad CLI and Go SDK.
 func buildTLSConfig() *tls.Config {
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kocierik/mcp-nomad/types"
+)
+
+// GetNomadVersion returns the version of the Nomad agent the client talks to
+func (c *NomadClient) GetNomadVersion(ctx context.Context) (string, error) {
+	respBody, err := c.makeRequest(ctx, "GET", "agent/self", nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var self struct {
+		Config struct {
+			Version struct {
+				Version string `json:"Version"`
+			} `json:"Version"`
+		} `json:"config"`
+		Member struct {
+			Tags map[string]string `json:"Tags"`
+		} `json:"member"`
+	}
+	if err := json.Unmarshal(respBody, &self); err != nil {
+		return "", fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	if version := self.Config.Version.Version; version != "" {
+		return version, nil
+	}
+	if build := self.Member.Tags["build"]; build != "" {
+		return build, nil
+	}
+	return "", fmt.Errorf("agent did not report a version")
+}
+
+// GetAgentMembers returns the agent's server identity and the gossip pool of its region
+func (c *NomadClient) GetAgentMembers(ctx context.Context) (types.AgentMembers, error) {
+	respBody, err := c.makeRequest(ctx, "GET", "agent/members", nil, nil)
+	if err != nil {
+		return types.AgentMembers{}, err
+	}
+
+	var members types.AgentMembers
+	if err := json.Unmarshal(respBody, &members); err != nil {
+		return types.AgentMembers{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return members, nil
+}
+
+// GetAgentSelf returns the configuration, gossip membership and stats of the agent
+func (c *NomadClient) GetAgentSelf(ctx context.Context) (types.AgentSelf, error) {
+	respBody, err := c.makeRequest(ctx, "GET", "agent/self", nil, nil)
+	if err != nil {
+		return types.AgentSelf{}, err
+	}
+
+	var self types.AgentSelf
+	if err := json.Unmarshal(respBody, &self); err != nil {
+		return types.AgentSelf{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return self, nil
+}
+
+// GetAgentHealth returns the health of the agent's client and server. Nomad answers 500
+// when either is unhealthy, with the same body, so a decodable 500 is not an error here.
+func (c *NomadClient) GetAgentHealth(ctx context.Context) (types.AgentHealth, error) {
+	statusCode, respBody, err := c.doRequest(ctx, "GET", "agent/health", nil, nil)
+	if err != nil {
+		return types.AgentHealth{}, err
+	}
+
+	var health types.AgentHealth
+	decodeErr := json.Unmarshal(respBody, &health)
+	reported := decodeErr == nil && (health.Client != nil || health.Server != nil)
+	if statusCode >= 400 && (statusCode != http.StatusInternalServerError || !reported) {
+		httpErr := NewNomadHTTPError(statusCode, "GET", "agent/health", respBody)
+		httpErr.RequestID = RequestIDFromContext(ctx)
+		return types.AgentHealth{}, httpErr
+	}
+	if decodeErr != nil {
+		return types.AgentHealth{}, fmt.Errorf("error unmarshaling response: %v", decodeErr)
+	}
+
+	return health, nil
+}
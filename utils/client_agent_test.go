@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAgentHealth_decodesUnhealthyResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/agent/health" {
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"client":{"ok":false,"message":"no known servers"},"server":{"ok":true,"message":"ok"}}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	health, err := client.GetAgentHealth(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, health.Client)
+	require.False(t, health.Client.OK)
+	require.Equal(t, "no known servers", health.Client.Message)
+	require.True(t, health.Server.OK)
+}
+
+func TestGetAgentHealth_returnsOtherErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/agent/health" {
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	_, err = client.GetAgentHealth(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "HTTP 500")
+}
+
+func TestGetAgentSelf_keepsOnlyTypedConfig(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/agent/self" {
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"config":{"Region":"global","Datacenter":"dc1","Server":{"Enabled":true,"BootstrapExpect":3,"EncryptKey":"secret"},"ACL":{"Enabled":true,"ReplicationToken":"secret"},"Version":{"Version":"1.9.3"}},"member":{"Name":"s1.global","Status":"alive","ProtocolCur":2},"stats":{"raft":{"state":"Leader"}}}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	self, err := client.GetAgentSelf(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "global", self.Config.Region)
+	require.Equal(t, 3, self.Config.Server.BootstrapExpect)
+	require.True(t, self.Config.ACL.Enabled)
+	require.Equal(t, "1.9.3", self.Config.Version.Version)
+	require.Equal(t, 2, self.Member.ProtocolCur)
+	require.Equal(t, "Leader", self.Stats["raft"]["state"])
+}
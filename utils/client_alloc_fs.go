@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/kocierik/mcp-nomad/types"
+)
+
+// ListAllocationFiles lists a directory of an allocation's filesystem (client/fs/ls)
+func (c *NomadClient) ListAllocationFiles(ctx context.Context, allocID, path string) ([]types.AllocFileInfo, error) {
+	if allocID == "" {
+		return nil, fmt.Errorf("allocation ID is required")
+	}
+
+	respBody, err := c.makeRequest(ctx, "GET", fmt.Sprintf("client/fs/ls/%s", allocID), map[string]string{"path": path}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []types.AllocFileInfo
+	if err := json.Unmarshal(respBody, &files); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return files, nil
+}
+
+// StatAllocationFile describes a file of an allocation's filesystem (client/fs/stat)
+func (c *NomadClient) StatAllocationFile(ctx context.Context, allocID, path string) (types.AllocFileInfo, error) {
+	if allocID == "" {
+		return types.AllocFileInfo{}, fmt.Errorf("allocation ID is required")
+	}
+
+	respBody, err := c.makeRequest(ctx, "GET", fmt.Sprintf("client/fs/stat/%s", allocID), map[string]string{"path": path}, nil)
+	if err != nil {
+		return types.AllocFileInfo{}, err
+	}
+
+	var file types.AllocFileInfo
+	if err := json.Unmarshal(respBody, &file); err != nil {
+		return types.AllocFileInfo{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return file, nil
+}
+
+// ReadAllocationFile reads a file of an allocation's filesystem. With a positive limit it
+// reads at most limit bytes from offset (client/fs/readat); otherwise it reads the whole
+// file (client/fs/cat) and offset is ignored.
+func (c *NomadClient) ReadAllocationFile(ctx context.Context, allocID, path string, offset, limit int64) ([]byte, error) {
+	if allocID == "" {
+		return nil, fmt.Errorf("allocation ID is required")
+	}
+
+	queryParams := map[string]string{"path": path}
+	endpoint := fmt.Sprintf("client/fs/cat/%s", allocID)
+	if limit > 0 {
+		endpoint = fmt.Sprintf("client/fs/readat/%s", allocID)
+		queryParams["offset"] = strconv.FormatInt(offset, 10)
+		queryParams["limit"] = strconv.FormatInt(limit, 10)
+	}
+
+	return c.makeRequest(ctx, "GET", endpoint, queryParams, nil)
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAllocationFile_usesCatOrReadAt(t *testing.T) {
+	var gotPaths []string
+	var gotQueries []map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.Path)
+		q := r.URL.Query()
+		gotQueries = append(gotQueries, map[string]string{"path": q.Get("path"), "offset": q.Get("offset"), "limit": q.Get("limit")})
+		_, _ = w.Write([]byte("content"))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+	gotPaths, gotQueries = nil, nil
+
+	content, err := client.ReadAllocationFile(context.Background(), "a1", "web/local/config.yml", 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, "content", string(content))
+
+	_, err = client.ReadAllocationFile(context.Background(), "a1", "web/local/config.yml", 10, 20)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/v1/client/fs/cat/a1", "/v1/client/fs/readat/a1"}, gotPaths)
+	require.Equal(t, map[string]string{"path": "web/local/config.yml", "offset": "", "limit": ""}, gotQueries[0])
+	require.Equal(t, map[string]string{"path": "web/local/config.yml", "offset": "10", "limit": "20"}, gotQueries[1])
+}
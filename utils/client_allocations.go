@@ -22,6 +22,18 @@ func (c *NomadClient) GetAllocation(ctx context.Context, allocID string) (types.
 	return alloc, nil
 }
 
+// GetAllocationMetrics retrieves an allocation with the metrics recorded when it was placed
+func (c *NomadClient) GetAllocationMetrics(ctx context.Context, allocID string) (types.AllocationPlacement, error) {
+	path := fmt.Sprintf("allocation/%s", allocID)
+
+	var placement types.AllocationPlacement
+	if err := c.get(ctx, path, &placement); err != nil {
+		return types.AllocationPlacement{}, err
+	}
+
+	return placement, nil
+}
+
 // ListAllocations lists allocations via GET /v1/allocations (namespace optional) when jobID is empty.
 // When jobID is non-empty, it uses GET /v1/job/:job_id/allocations for that namespace (consistent with Nomad API).
 func (c *NomadClient) ListAllocations(ctx context.Context, namespace, jobID string) ([]types.Allocation, error) {
@@ -55,3 +67,15 @@ func (c *NomadClient) StopAllocation(ctx context.Context, allocationID string) e
 	_, err := c.makeRequest(ctx, "POST", path, nil, nil)
 	return err
 }
+
+// RestartAllocation restarts the tasks of a running allocation in place, or only task when
+// set (PUT /v1/client/allocation/:id/restart).
+func (c *NomadClient) RestartAllocation(ctx context.Context, allocationID, task string) error {
+	allocationID = strings.TrimSpace(allocationID)
+	if allocationID == "" {
+		return fmt.Errorf("allocation ID is required")
+	}
+	body := map[string]interface{}{"TaskName": task, "AllTasks": task == ""}
+	_, err := c.makeRequest(ctx, "PUT", fmt.Sprintf("client/allocation/%s/restart", allocationID), nil, body)
+	return err
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestartAllocation_restartsOneTaskOrAll(t *testing.T) {
+	var bodies []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/client/allocation/a1/restart" {
+			require.Equal(t, "PUT", r.Method)
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			bodies = append(bodies, body)
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	require.NoError(t, client.RestartAllocation(context.Background(), "a1", "web"))
+	require.NoError(t, client.RestartAllocation(context.Background(), "a1", ""))
+	require.Equal(t, []map[string]interface{}{
+		{"TaskName": "web", "AllTasks": false},
+		{"TaskName": "", "AllTasks": true},
+	}, bodies)
+	require.Error(t, client.RestartAllocation(context.Background(), " ", ""))
+}
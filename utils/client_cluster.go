@@ -1,6 +1,30 @@
 package utils
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+)
+
+// GetLeader returns the RPC address of the cluster leader, or "" while there is none
+func (c *NomadClient) GetLeader(ctx context.Context) (string, error) {
+	respBody, err := c.makeRequest(ctx, "GET", "status/leader", nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var leader string
+	if err := json.Unmarshal(respBody, &leader); err != nil {
+		return "", fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return leader, nil
+}
 
 // GetClusterLeader return the info of the cluster leader
 func (c *NomadClient) GetClusterLeader(ctx context.Context) ([]byte, error) {
@@ -20,7 +44,163 @@ func (c *NomadClient) ListClusterPeers(ctx context.Context) ([]byte, error) {
 	return respBody, nil
 }
 
-// ListRegions return the regions listed
-func (c *NomadClient) ListRegions(ctx context.Context) ([]byte, error) {
-	return c.MakeRequest(ctx, "GET", "regions", nil, nil)
+// ListRegions returns the names of the regions known to the cluster
+func (c *NomadClient) ListRegions(ctx context.Context) ([]string, error) {
+	respBody, err := c.makeRequest(ctx, "GET", "regions", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var regions []string
+	if err := json.Unmarshal(respBody, &regions); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return regions, nil
+}
+
+// GetRaftConfiguration returns the servers in the Raft configuration
+func (c *NomadClient) GetRaftConfiguration(ctx context.Context) (types.RaftConfiguration, error) {
+	respBody, err := c.makeRequest(ctx, "GET", "operator/raft/configuration", nil, nil)
+	if err != nil {
+		return types.RaftConfiguration{}, err
+	}
+
+	var config types.RaftConfiguration
+	if err := json.Unmarshal(respBody, &config); err != nil {
+		return types.RaftConfiguration{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return config, nil
+}
+
+// ListAgentMembers returns the servers known to the gossip pool of the agent's region
+func (c *NomadClient) ListAgentMembers(ctx context.Context) ([]types.AgentMember, error) {
+	members, err := c.GetAgentMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return members.Members, nil
+}
+
+// GetAutopilotHealth returns autopilot's server health. Nomad answers 429 Too Many Requests
+// when the cluster is unhealthy, with the same body, so that status is not an error here.
+func (c *NomadClient) GetAutopilotHealth(ctx context.Context) (types.AutopilotHealth, error) {
+	statusCode, respBody, err := c.doRequest(ctx, "GET", "operator/autopilot/health", nil, nil)
+	if err != nil {
+		return types.AutopilotHealth{}, err
+	}
+	if statusCode >= 400 && statusCode != http.StatusTooManyRequests {
+		httpErr := NewNomadHTTPError(statusCode, "GET", "operator/autopilot/health", respBody)
+		httpErr.RequestID = RequestIDFromContext(ctx)
+		return types.AutopilotHealth{}, httpErr
+	}
+
+	var health types.AutopilotHealth
+	if err := json.Unmarshal(respBody, &health); err != nil {
+		return types.AutopilotHealth{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return health, nil
+}
+
+// GetAutopilotConfiguration returns the cluster's autopilot configuration
+func (c *NomadClient) GetAutopilotConfiguration(ctx context.Context) (types.AutopilotConfiguration, error) {
+	respBody, err := c.makeRequest(ctx, "GET", "operator/autopilot/configuration", nil, nil)
+	if err != nil {
+		return types.AutopilotConfiguration{}, err
+	}
+
+	var config types.AutopilotConfiguration
+	if err := json.Unmarshal(respBody, &config); err != nil {
+		return types.AutopilotConfiguration{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return config, nil
+}
+
+// SetAutopilotConfiguration replaces the cluster's autopilot configuration. With cas the
+// write only happens when the configuration is still at config.ModifyIndex; the result
+// reports whether it was applied.
+func (c *NomadClient) SetAutopilotConfiguration(ctx context.Context, config types.AutopilotConfiguration, cas bool) (bool, error) {
+	var queryParams map[string]string
+	if cas {
+		queryParams = map[string]string{"cas": strconv.FormatUint(config.ModifyIndex, 10)}
+	}
+
+	respBody, err := c.makeRequest(ctx, "PUT", "operator/autopilot/configuration", queryParams, config)
+	if err != nil {
+		return false, err
+	}
+
+	var applied bool
+	if err := json.Unmarshal(respBody, &applied); err != nil {
+		return false, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return applied, nil
+}
+
+// GetSchedulerConfig returns the cluster's scheduler configuration
+func (c *NomadClient) GetSchedulerConfig(ctx context.Context) (types.SchedulerConfiguration, error) {
+	respBody, err := c.makeRequest(ctx, "GET", "operator/scheduler/configuration", nil, nil)
+	if err != nil {
+		return types.SchedulerConfiguration{}, err
+	}
+
+	var response struct {
+		SchedulerConfig types.SchedulerConfiguration `json:"SchedulerConfig"`
+	}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return types.SchedulerConfiguration{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return response.SchedulerConfig, nil
+}
+
+// raftPeerQuery selects a Raft peer by ID or, without one, by address
+func raftPeerQuery(id, address string) map[string]string {
+	if id != "" {
+		return map[string]string{"id": id}
+	}
+	return map[string]string{"address": address}
+}
+
+// RemoveRaftPeer removes a server from the Raft configuration by ID or address
+func (c *NomadClient) RemoveRaftPeer(ctx context.Context, id, address string) error {
+	_, err := c.makeRequest(ctx, "DELETE", "operator/raft/peer", raftPeerQuery(id, address), nil)
+	return err
+}
+
+// TransferRaftLeadership asks the leader to hand leadership to the voter with the given ID
+// or address
+func (c *NomadClient) TransferRaftLeadership(ctx context.Context, id, address string) error {
+	_, err := c.makeRequest(ctx, "PUT", "operator/raft/transfer-leadership", raftPeerQuery(id, address), nil)
+	return err
+}
+
+// GetLicense returns the cluster's Nomad Enterprise license
+func (c *NomadClient) GetLicense(ctx context.Context) (types.LicenseReply, error) {
+	respBody, err := c.makeRequest(ctx, "GET", "operator/license", nil, nil)
+	if err != nil {
+		return types.LicenseReply{}, err
+	}
+
+	var reply types.LicenseReply
+	if err := json.Unmarshal(respBody, &reply); err != nil {
+		return types.LicenseReply{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return reply, nil
+}
+
+// PutLicense installs a Nomad Enterprise license blob. force allows a license older than
+// the current one.
+func (c *NomadClient) PutLicense(ctx context.Context, license string, force bool) error {
+	var queryParams map[string]string
+	if force {
+		queryParams = map[string]string{"force": "true"}
+	}
+	_, err := c.makeRequest(ctx, "PUT", "operator/license", queryParams, strings.NewReader(license))
+	return err
 }
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAutopilotHealth_decodesUnhealthyResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/operator/autopilot/health" {
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+			return
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"Healthy":false,"FailureTolerance":0,"Servers":[{"ID":"s1","Healthy":false,"LastContact":"9s"}]}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	health, err := client.GetAutopilotHealth(context.Background())
+	require.NoError(t, err)
+	require.False(t, health.Healthy)
+	require.Len(t, health.Servers, 1)
+	require.Equal(t, "9s", health.Servers[0].LastContact)
+}
+
+func TestGetAutopilotHealth_returnsOtherErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/operator/autopilot/health" {
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+			return
+		}
+		http.Error(w, "Permission denied", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	_, err = client.GetAutopilotHealth(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "HTTP 403")
+}
+
+func TestSetAutopilotConfiguration_sendsCheckAndSetIndex(t *testing.T) {
+	var gotCAS string
+	var gotBody map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/operator/autopilot/configuration" {
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+			return
+		}
+		require.Equal(t, http.MethodPut, r.Method)
+		gotCAS = r.URL.Query().Get("cas")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		_, _ = w.Write([]byte(`false`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	applied, err := client.SetAutopilotConfiguration(context.Background(), types.AutopilotConfiguration{CleanupDeadServers: true, LastContactThreshold: "200ms", ModifyIndex: 42}, true)
+	require.NoError(t, err)
+	require.False(t, applied)
+	require.Equal(t, "42", gotCAS)
+	require.Equal(t, true, gotBody["CleanupDeadServers"])
+	require.Equal(t, "200ms", gotBody["LastContactThreshold"])
+}
+
+func TestGetSchedulerConfig_decodesPreemptionConfig(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/operator/scheduler/configuration" {
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"Index":5,"SchedulerConfig":{"SchedulerAlgorithm":"spread","PreemptionConfig":{"SystemSchedulerEnabled":true,"BatchSchedulerEnabled":true}}}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	config, err := client.GetSchedulerConfig(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "spread", config.SchedulerAlgorithm)
+	require.True(t, config.PreemptionConfig.Enabled("batch"))
+	require.False(t, config.PreemptionConfig.Enabled("service"))
+}
@@ -84,3 +84,54 @@ func (c *NomadClient) GetJobDeployment(ctx context.Context, jobID, namespace str
 
 	return deployment, nil
 }
+
+// PromoteDeployment promotes the canaries of a deployment. With no groups every task group
+// is promoted.
+func (c *NomadClient) PromoteDeployment(ctx context.Context, deploymentID, namespace string, groups []string) (types.DeploymentUpdateResponse, error) {
+	body := map[string]interface{}{
+		"DeploymentID": deploymentID,
+		"All":          len(groups) == 0,
+	}
+	if len(groups) > 0 {
+		body["Groups"] = groups
+	}
+	return c.updateDeployment(ctx, "promote", deploymentID, namespace, body)
+}
+
+// FailDeployment marks a deployment as failed; Nomad rolls the job back when its update
+// block has auto_revert set.
+func (c *NomadClient) FailDeployment(ctx context.Context, deploymentID, namespace string) (types.DeploymentUpdateResponse, error) {
+	return c.updateDeployment(ctx, "fail", deploymentID, namespace, map[string]interface{}{
+		"DeploymentID": deploymentID,
+	})
+}
+
+// PauseDeployment pauses a deployment, or resumes it when pause is false.
+func (c *NomadClient) PauseDeployment(ctx context.Context, deploymentID, namespace string, pause bool) (types.DeploymentUpdateResponse, error) {
+	return c.updateDeployment(ctx, "pause", deploymentID, namespace, map[string]interface{}{
+		"DeploymentID": deploymentID,
+		"Pause":        pause,
+	})
+}
+
+// updateDeployment posts to one of the deployment/<action>/:id endpoints.
+func (c *NomadClient) updateDeployment(ctx context.Context, action, deploymentID, namespace string, body map[string]interface{}) (types.DeploymentUpdateResponse, error) {
+	if deploymentID == "" {
+		return types.DeploymentUpdateResponse{}, fmt.Errorf("deployment ID is required")
+	}
+
+	queryParams := make(map[string]string)
+	AddNomadNamespaceQuery(queryParams, namespace)
+
+	respBody, err := c.makeRequest(ctx, "POST", fmt.Sprintf("deployment/%s/%s", action, deploymentID), queryParams, body)
+	if err != nil {
+		return types.DeploymentUpdateResponse{}, err
+	}
+
+	response := types.DeploymentUpdateResponse{DeploymentID: deploymentID}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return types.DeploymentUpdateResponse{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return response, nil
+}
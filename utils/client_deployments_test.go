@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDeployments_decodesNomadStubs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"ID":"d1","JobID":"web","Namespace":"prod","Status":"running","ModifyIndex":42}]`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	deployments, err := client.ListDeployments(context.Background(), "prod")
+	require.NoError(t, err)
+	require.Len(t, deployments, 1)
+	require.Equal(t, "web", deployments[0].JobID)
+	require.Equal(t, uint64(42), deployments[0].ModifyIndex)
+}
+
+func TestGetDeployment_decodesNomadDeployment(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ID":"d1","JobID":"web","JobVersion":3,"JobModifyIndex":17,"Namespace":"prod","Status":"running",
+			"TaskGroups":{"api":{"DesiredTotal":3,"PlacedAllocs":2,"HealthyAllocs":1}}}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	deployment, err := client.GetDeployment(context.Background(), "d1")
+	require.NoError(t, err)
+	require.Equal(t, "web", deployment.JobID)
+	require.Equal(t, 17, deployment.JobModifyIndex)
+	require.Equal(t, 3, deployment.TaskGroups["api"].DesiredTotal)
+	require.Equal(t, 1, deployment.TaskGroups["api"].HealthyAllocs)
+}
+
+func TestPromoteDeployment_postsGroupsInNamespace(t *testing.T) {
+	var gotPath, gotNamespace string
+	var gotBody map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		gotPath, gotNamespace = r.URL.Path, r.URL.Query().Get("namespace")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte(`{"EvalID":"e1","EvalCreateIndex":50,"DeploymentModifyIndex":51,"Index":51}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	response, err := client.PromoteDeployment(context.Background(), "d1", "prod", []string{"api"})
+	require.NoError(t, err)
+	require.Equal(t, "/v1/deployment/promote/d1", gotPath)
+	require.Equal(t, "prod", gotNamespace)
+	require.Equal(t, map[string]interface{}{"DeploymentID": "d1", "All": false, "Groups": []interface{}{"api"}}, gotBody)
+	require.Equal(t, "d1", response.DeploymentID)
+	require.Equal(t, "e1", response.EvalID)
+	require.Equal(t, uint64(51), response.DeploymentModifyIndex)
+
+	gotBody = nil
+	_, err = client.PauseDeployment(context.Background(), "d1", "prod", false)
+	require.NoError(t, err)
+	require.Equal(t, "/v1/deployment/pause/d1", gotPath)
+	require.Equal(t, map[string]interface{}{"DeploymentID": "d1", "Pause": false}, gotBody)
+}
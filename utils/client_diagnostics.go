@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kocierik/mcp-nomad/types"
+)
+
+// diagnosticProbes are the API families probed for permissions, one cheap read each.
+var diagnosticProbes = []struct {
+	Family string
+	Path   string
+	Query  map[string]string
+}{
+	{"agent", "agent/self", nil},
+	{"jobs", "jobs", map[string]string{"per_page": "1"}},
+	{"allocations", "allocations", map[string]string{"per_page": "1"}},
+	{"evaluations", "evaluations", map[string]string{"per_page": "1"}},
+	{"deployments", "deployments", map[string]string{"per_page": "1"}},
+	{"nodes", "nodes", map[string]string{"per_page": "1"}},
+	{"namespaces", "namespaces", nil},
+	{"variables", "vars", map[string]string{"per_page": "1"}},
+	{"volumes", "volumes", map[string]string{"type": "csi", "per_page": "1"}},
+	{"services", "services", nil},
+	{"acl", "acl/policies", nil},
+	{"operator", "operator/raft/configuration", nil},
+}
+
+// DiagnoseConnection checks reachability, TLS, token validity and which API families the
+// token may read. Failures are recorded in the diagnosis rather than returned as errors.
+func (c *NomadClient) DiagnoseConnection(ctx context.Context) (types.ConnectionDiagnosis, error) {
+	diagnosis := types.ConnectionDiagnosis{
+		Address: c.GetAddress(),
+		Token:   types.TokenDiagnosis{Configured: c.tokenFor(ctx) != ""},
+	}
+
+	start := time.Now()
+	respBody, err := c.makeRequest(ctx, "GET", "status/leader", nil, nil)
+	if err != nil {
+		diagnosis.Error = err.Error()
+		diagnosis.Issues = append(diagnosis.Issues, "Nomad API is not reachable")
+	} else {
+		diagnosis.Reachable = true
+		diagnosis.LatencyMs = time.Since(start).Milliseconds()
+		_ = json.Unmarshal(respBody, &diagnosis.Leader)
+		if diagnosis.Leader == "" {
+			diagnosis.Issues = append(diagnosis.Issues, "cluster reports no leader")
+		}
+	}
+
+	if u, err := url.Parse(c.GetAddress()); err == nil && u.Scheme == "https" {
+		diagnosis.TLS = c.diagnoseTLS(ctx, u)
+		if !diagnosis.TLS.Verified && !diagnosis.TLS.SkipVerify {
+			diagnosis.Issues = append(diagnosis.Issues, "TLS certificate could not be verified")
+		}
+	}
+
+	if diagnosis.Reachable {
+		diagnosis.Token = c.diagnoseToken(ctx)
+		if !diagnosis.Token.Valid {
+			diagnosis.Issues = append(diagnosis.Issues, "ACL token is missing or invalid")
+		}
+
+		diagnosis.APIFamilies = c.probeAPIFamilies(ctx)
+		var forbidden []string
+		for _, ping := range diagnosis.APIFamilies {
+			if ping.Status == "forbidden" {
+				forbidden = append(forbidden, ping.Family)
+			}
+		}
+		if len(forbidden) > 0 {
+			diagnosis.Issues = append(diagnosis.Issues, "token is denied access to: "+strings.Join(forbidden, ", "))
+		}
+	}
+
+	diagnosis.Ready = diagnosis.Reachable && diagnosis.Token.Valid &&
+		(diagnosis.TLS == nil || diagnosis.TLS.Verified || diagnosis.TLS.SkipVerify)
+	return diagnosis, nil
+}
+
+// diagnoseTLS handshakes with the server using the client's TLS settings. When verification
+// fails the certificate is fetched again without verification so it can be described.
+func (c *NomadClient) diagnoseTLS(ctx context.Context, u *url.URL) *types.TLSDiagnosis {
+	cfg := &tls.Config{}
+	if transport, ok := c.httpClient.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+		cfg = transport.TLSClientConfig.Clone()
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = u.Hostname()
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	diagnosis := &types.TLSDiagnosis{SkipVerify: cfg.InsecureSkipVerify, ServerName: cfg.ServerName}
+
+	state, err := tlsHandshake(ctx, host, cfg)
+	if err != nil {
+		diagnosis.Error = err.Error()
+		if cfg.InsecureSkipVerify {
+			return diagnosis
+		}
+		cfg.InsecureSkipVerify = true
+		if state, err = tlsHandshake(ctx, host, cfg); err != nil {
+			return diagnosis
+		}
+	} else {
+		diagnosis.Verified = !cfg.InsecureSkipVerify
+	}
+
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		diagnosis.Subject = cert.Subject.String()
+		diagnosis.Issuer = cert.Issuer.String()
+		diagnosis.NotAfter = cert.NotAfter.UTC().Format(time.RFC3339)
+	}
+	return diagnosis
+}
+
+func tlsHandshake(ctx context.Context, host string, cfg *tls.Config) (tls.ConnectionState, error) {
+	dialer := &tls.Dialer{Config: cfg, NetDialer: &net.Dialer{Timeout: 10 * time.Second}}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return tls.ConnectionState{}, err
+	}
+	defer conn.Close()
+	return conn.(*tls.Conn).ConnectionState(), nil
+}
+
+// diagnoseToken resolves the configured token. A cluster without ACLs accepts any token.
+func (c *NomadClient) diagnoseToken(ctx context.Context) types.TokenDiagnosis {
+	diagnosis := types.TokenDiagnosis{Configured: c.tokenFor(ctx) != "", ACLEnabled: true}
+
+	respBody, err := c.makeRequest(ctx, "GET", "acl/token/self", nil, nil)
+	if err != nil {
+		var httpErr *NomadHTTPError
+		if errors.As(err, &httpErr) && strings.Contains(httpErr.Snippet(), "ACL support disabled") {
+			diagnosis.ACLEnabled = false
+			diagnosis.Valid = true
+			return diagnosis
+		}
+		diagnosis.Error = err.Error()
+		return diagnosis
+	}
+
+	var token struct {
+		AccessorID     string     `json:"AccessorID"`
+		Name           string     `json:"Name"`
+		Type           string     `json:"Type"`
+		Policies       []string   `json:"Policies"`
+		ExpirationTime *time.Time `json:"ExpirationTime"`
+	}
+	if err := json.Unmarshal(respBody, &token); err != nil {
+		diagnosis.Error = fmt.Sprintf("error unmarshaling response: %v", err)
+		return diagnosis
+	}
+
+	diagnosis.Valid = true
+	diagnosis.AccessorID = token.AccessorID
+	diagnosis.Name = token.Name
+	diagnosis.Type = token.Type
+	diagnosis.Policies = token.Policies
+	if token.ExpirationTime != nil {
+		diagnosis.ExpirationTime = token.ExpirationTime.UTC().Format(time.RFC3339)
+	}
+	return diagnosis
+}
+
+// probeAPIFamilies issues the diagnostic probes concurrently and keeps their order.
+func (c *NomadClient) probeAPIFamilies(ctx context.Context) []types.APIFamilyPing {
+	pings := make([]types.APIFamilyPing, len(diagnosticProbes))
+
+	var wg sync.WaitGroup
+	for i, probe := range diagnosticProbes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ping := types.APIFamilyPing{Family: probe.Family, Path: probe.Path, Status: "ok"}
+			if _, err := c.makeRequest(ctx, "GET", probe.Path, probe.Query, nil); err != nil {
+				ping.Status = "error"
+				ping.Error = err.Error()
+				var httpErr *NomadHTTPError
+				if errors.As(err, &httpErr) {
+					ping.HTTPStatus = httpErr.StatusCode
+					if httpErr.StatusCode == http.StatusForbidden {
+						ping.Status = "forbidden"
+					}
+				}
+			}
+			pings[i] = ping
+		}()
+	}
+	wg.Wait()
+
+	return pings
+}
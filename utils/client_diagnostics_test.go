@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnoseConnection_reportsForbiddenFamilies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/status/leader":
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+		case "/v1/acl/token/self":
+			_, _ = w.Write([]byte(`{"AccessorID":"a1","Name":"mcp","Type":"client","Policies":["readonly"]}`))
+		case "/v1/acl/policies", "/v1/operator/raft/configuration":
+			http.Error(w, "Permission denied", http.StatusForbidden)
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "secret")
+	require.NoError(t, err)
+
+	diagnosis, err := client.DiagnoseConnection(context.Background())
+	require.NoError(t, err)
+	require.True(t, diagnosis.Reachable)
+	require.True(t, diagnosis.Ready)
+	require.Nil(t, diagnosis.TLS)
+	require.Equal(t, "10.0.0.1:4647", diagnosis.Leader)
+	require.True(t, diagnosis.Token.Valid)
+	require.Equal(t, []string{"readonly"}, diagnosis.Token.Policies)
+
+	statuses := map[string]string{}
+	for _, ping := range diagnosis.APIFamilies {
+		statuses[ping.Family] = ping.Status
+	}
+	require.Equal(t, "ok", statuses["jobs"])
+	require.Equal(t, "forbidden", statuses["acl"])
+	require.Equal(t, "forbidden", statuses["operator"])
+	require.Contains(t, diagnosis.Issues, "token is denied access to: acl, operator")
+}
+
+func newTokenSelfServer(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/status/leader":
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+		case "/v1/acl/token/self":
+			http.Error(w, body, status)
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+}
+
+func TestDiagnoseConnection_aclDisabled(t *testing.T) {
+	srv := newTokenSelfServer(http.StatusBadRequest, "ACL support disabled")
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	diagnosis, err := client.DiagnoseConnection(context.Background())
+	require.NoError(t, err)
+	require.True(t, diagnosis.Ready)
+	require.False(t, diagnosis.Token.ACLEnabled)
+}
+
+func TestDiagnoseConnection_invalidToken(t *testing.T) {
+	srv := newTokenSelfServer(http.StatusForbidden, "ACL token not found")
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "stale")
+	require.NoError(t, err)
+
+	diagnosis, err := client.DiagnoseConnection(context.Background())
+	require.NoError(t, err)
+	require.False(t, diagnosis.Ready)
+	require.False(t, diagnosis.Token.Valid)
+	require.Contains(t, diagnosis.Issues, "ACL token is missing or invalid")
+}
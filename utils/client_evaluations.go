@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+)
+
+// ListEvaluations lists evaluations, optionally narrowed server-side to a status and/or job
+func (c *NomadClient) ListEvaluations(ctx context.Context, namespace, status, jobID string) ([]types.Evaluation, error) {
+	return c.FilterEvaluations(ctx, namespace, types.EvaluationFilter{Status: status, JobID: jobID})
+}
+
+// FilterEvaluations lists the evaluations matching filter, evaluated server-side
+func (c *NomadClient) FilterEvaluations(ctx context.Context, namespace string, filter types.EvaluationFilter) ([]types.Evaluation, error) {
+	queryParams := make(map[string]string)
+	AddNomadNamespaceQuery(queryParams, namespace)
+
+	var filters []string
+	if filter.Status != "" {
+		filters = append(filters, fmt.Sprintf("Status == %q", filter.Status))
+	}
+	if filter.JobID != "" {
+		filters = append(filters, fmt.Sprintf("JobID == %q", filter.JobID))
+	}
+	if len(filter.TriggeredBy) > 0 {
+		triggers := make([]string, len(filter.TriggeredBy))
+		for i, trigger := range filter.TriggeredBy {
+			triggers[i] = fmt.Sprintf("TriggeredBy == %q", trigger)
+		}
+		filters = append(filters, "("+strings.Join(triggers, " or ")+")")
+	}
+	if filter.Expression != "" {
+		filters = append(filters, "("+filter.Expression+")")
+	}
+	if len(filters) > 0 {
+		queryParams["filter"] = strings.Join(filters, " and ")
+	}
+
+	respBody, err := c.makeRequest(ctx, "GET", "evaluations", queryParams, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var evaluations []types.Evaluation
+	if err := json.Unmarshal(respBody, &evaluations); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return evaluations, nil
+}
+
+// GetEvaluation retrieves a specific evaluation
+func (c *NomadClient) GetEvaluation(ctx context.Context, evalID string) (types.Evaluation, error) {
+	if evalID == "" {
+		return types.Evaluation{}, fmt.Errorf("evaluation ID is required")
+	}
+
+	respBody, err := c.makeRequest(ctx, "GET", fmt.Sprintf("evaluation/%s", evalID), nil, nil)
+	if err != nil {
+		return types.Evaluation{}, err
+	}
+
+	var evaluation types.Evaluation
+	if err := json.Unmarshal(respBody, &evaluation); err != nil {
+		return types.Evaluation{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return evaluation, nil
+}
+
+// ListEvaluationAllocations lists the allocations an evaluation created or updated
+func (c *NomadClient) ListEvaluationAllocations(ctx context.Context, evalID string) ([]types.Allocation, error) {
+	if evalID == "" {
+		return nil, fmt.Errorf("evaluation ID is required")
+	}
+
+	respBody, err := c.makeRequest(ctx, "GET", fmt.Sprintf("evaluation/%s/allocations", evalID), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var allocations []types.Allocation
+	if err := json.Unmarshal(respBody, &allocations); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return allocations, nil
+}
+
+// DeleteEvaluations deletes evaluations from the state store. Nomad only allows this while
+// the scheduler is paused and with a management token.
+func (c *NomadClient) DeleteEvaluations(ctx context.Context, evalIDs []string) error {
+	if len(evalIDs) == 0 {
+		return fmt.Errorf("at least one evaluation ID is required")
+	}
+
+	_, err := c.makeRequest(ctx, "DELETE", "evaluations", nil, map[string]interface{}{"EvalIDs": evalIDs})
+	return err
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListEvaluations_buildsFilter(t *testing.T) {
+	var gotPath, gotFilter, gotNamespace string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotFilter = r.URL.Query().Get("filter")
+		gotNamespace = r.URL.Query().Get("namespace")
+		_, _ = w.Write([]byte(`[{"ID":"e1","Status":"blocked","JobID":"web"}]`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	evals, err := client.ListEvaluations(context.Background(), "prod", "blocked", "web")
+	require.NoError(t, err)
+	require.Len(t, evals, 1)
+	require.Equal(t, "/v1/evaluations", gotPath)
+	require.Equal(t, `Status == "blocked" and JobID == "web"`, gotFilter)
+	require.Equal(t, "prod", gotNamespace)
+}
+
+func TestFilterEvaluations_matchesAnyTrigger(t *testing.T) {
+	var gotFilter string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotFilter = r.URL.Query().Get("filter")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	_, err = client.FilterEvaluations(context.Background(), "", types.EvaluationFilter{Status: "pending", TriggeredBy: []string{"node-update", "job-register"}})
+	require.NoError(t, err)
+	require.Equal(t, `Status == "pending" and (TriggeredBy == "node-update" or TriggeredBy == "job-register")`, gotFilter)
+}
+
+func TestDeleteEvaluations_sendsIDsInBody(t *testing.T) {
+	var gotMethod, gotPath string
+	var gotBody map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		gotMethod, gotPath = r.Method, r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	require.NoError(t, client.DeleteEvaluations(context.Background(), []string{"e1", "e2"}))
+	require.Equal(t, http.MethodDelete, gotMethod)
+	require.Equal(t, "/v1/evaluations", gotPath)
+	require.Equal(t, map[string][]string{"EvalIDs": {"e1", "e2"}}, gotBody)
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/kocierik/mcp-nomad/types"
+)
+
+// StreamEvents reads event/stream and calls handle for every frame carrying events, until
+// ctx is done, handle returns an error or Nomad closes the stream. Heartbeat frames are
+// skipped. Cancelling ctx or returning ErrStopStream ends the stream with a nil error.
+func (c *NomadClient) StreamEvents(ctx context.Context, request types.EventStreamRequest, handle func(types.EventFrame) error) error {
+	query := url.Values{}
+	for _, topic := range request.Topics {
+		query.Add("topic", topic)
+	}
+	if request.Namespace != "" {
+		query.Set("namespace", request.Namespace)
+	}
+	if request.Index > 0 {
+		query.Set("index", strconv.FormatUint(request.Index, 10))
+	}
+
+	body, err := c.doStream(ctx, "event/stream", query)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	defer body.Close()
+
+	return decodeStreamFrames(ctx, body, func(frame types.EventFrame) bool { return len(frame.Events) == 0 }, handle)
+}
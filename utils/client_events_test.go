@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamEvents_decodesFramesAndSkipsHeartbeats(t *testing.T) {
+	var gotTopics []string
+	var gotNamespace, gotIndex string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/event/stream" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		gotTopics = r.URL.Query()["topic"]
+		gotNamespace = r.URL.Query().Get("namespace")
+		gotIndex = r.URL.Query().Get("index")
+		_, _ = w.Write([]byte("{}\n"))
+		_, _ = w.Write([]byte(`{"Index":7,"Events":[{"Topic":"Job","Type":"JobRegistered","Key":"web","Index":7,"Payload":{"Job":{"ID":"web"}}}]}` + "\n"))
+		_, _ = w.Write([]byte("{}\n"))
+		_, _ = w.Write([]byte(`{"Index":9,"Events":[{"Topic":"Job","Type":"JobDeregistered","Key":"web","Index":9}]}` + "\n"))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	var frames []types.EventFrame
+	err = client.StreamEvents(context.Background(), types.EventStreamRequest{Topics: []string{"Job:web", "Node"}, Namespace: "prod", Index: 5}, func(frame types.EventFrame) error {
+		frames = append(frames, frame)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Job:web", "Node"}, gotTopics)
+	require.Equal(t, "prod", gotNamespace)
+	require.Equal(t, "5", gotIndex)
+	require.Len(t, frames, 2)
+	require.Equal(t, "JobRegistered", frames[0].Events[0].Type)
+	require.JSONEq(t, `{"Job":{"ID":"web"}}`, string(frames[0].Events[0].Payload))
+	require.Equal(t, uint64(9), frames[1].Index)
+}
+
+func TestStreamEvents_stopsOnHandlerRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/event/stream" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"Index":7,"Events":[{"Topic":"Node","Type":"NodeRegistration","Key":"n1","Index":7}]}` + "\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	calls := 0
+	err = client.StreamEvents(context.Background(), types.EventStreamRequest{}, func(frame types.EventFrame) error {
+		calls++
+		return ErrStopStream
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+}
+
+func TestStreamEvents_returnsHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/event/stream" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		http.Error(w, "Permission denied", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	err = client.StreamEvents(context.Background(), types.EventStreamRequest{}, func(types.EventFrame) error { return nil })
+	var httpErr *NomadHTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusForbidden, httpErr.StatusCode)
+}
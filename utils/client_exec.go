@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
+)
+
+// execFrame is one message of the exec WebSocket protocol, in either direction
+type execFrame struct {
+	Stdin  *execStream `json:"stdin,omitempty"`
+	Stdout *execStream `json:"stdout,omitempty"`
+	Stderr *execStream `json:"stderr,omitempty"`
+	Exited bool        `json:"exited,omitempty"`
+	Result *struct {
+		ExitCode int `json:"exit_code"`
+	} `json:"result,omitempty"`
+}
+
+type execStream struct {
+	Data  []byte `json:"data,omitempty"` // base64 on the wire
+	Close bool   `json:"close,omitempty"`
+}
+
+// ExecAllocation runs a command inside a task of an allocation over the
+// client/allocation/:id/exec WebSocket endpoint, without a TTY, and waits for it to exit.
+// The command runs until it exits or ctx is done.
+func (c *NomadClient) ExecAllocation(ctx context.Context, request types.ExecRequest) (types.ExecResult, error) {
+	if request.AllocID == "" {
+		return types.ExecResult{}, fmt.Errorf("allocation ID is required")
+	}
+	if request.Task == "" {
+		return types.ExecResult{}, fmt.Errorf("task name is required")
+	}
+	if len(request.Command) == 0 {
+		return types.ExecResult{}, fmt.Errorf("command is required")
+	}
+
+	command, err := json.Marshal(request.Command)
+	if err != nil {
+		return types.ExecResult{}, fmt.Errorf("error marshaling command: %v", err)
+	}
+	query := url.Values{}
+	query.Set("task", request.Task)
+	query.Set("command", string(command))
+	query.Set("tty", "false")
+
+	conn, err := c.dialWebSocket(ctx, fmt.Sprintf("client/allocation/%s/exec", request.AllocID), query)
+	if err != nil {
+		return types.ExecResult{}, err
+	}
+	defer conn.close()
+
+	if request.Stdin != "" {
+		if err := conn.writeExecFrame(execFrame{Stdin: &execStream{Data: []byte(request.Stdin)}}); err != nil {
+			return types.ExecResult{}, fmt.Errorf("error writing stdin: %v", err)
+		}
+	}
+	if err := conn.writeExecFrame(execFrame{Stdin: &execStream{Close: true}}); err != nil {
+		return types.ExecResult{}, fmt.Errorf("error closing stdin: %v", err)
+	}
+
+	var result types.ExecResult
+	var stdout, stderr strings.Builder
+	appendOutput := func(out *strings.Builder, data []byte) {
+		if request.MaxOutputBytes > 0 && out.Len()+len(data) > request.MaxOutputBytes {
+			data = data[:request.MaxOutputBytes-out.Len()]
+			result.OutputTruncated = true
+		}
+		out.Write(data)
+	}
+
+	for {
+		_, message, err := conn.readMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return types.ExecResult{}, fmt.Errorf("command did not exit: %w", ctx.Err())
+			}
+			if errors.Is(err, errWebSocketClosed) {
+				return types.ExecResult{}, fmt.Errorf("exec session ended before the command exited: %v", err)
+			}
+			return types.ExecResult{}, fmt.Errorf("error reading exec output: %v", err)
+		}
+
+		var frame execFrame
+		if err := json.Unmarshal(message, &frame); err != nil {
+			return types.ExecResult{}, fmt.Errorf("error unmarshaling response: %v", err)
+		}
+		if frame.Stdout != nil {
+			appendOutput(&stdout, frame.Stdout.Data)
+		}
+		if frame.Stderr != nil {
+			appendOutput(&stderr, frame.Stderr.Data)
+		}
+		if frame.Exited {
+			if frame.Result != nil {
+				result.ExitCode = frame.Result.ExitCode
+			}
+			result.Stdout = stdout.String()
+			result.Stderr = stderr.String()
+			return result, nil
+		}
+	}
+}
+
+func (w *wsConn) writeExecFrame(frame execFrame) error {
+	payload, err := json.Marshal(frame)
+	if err != nil {
+		return err
+	}
+	return w.writeMessage(wsOpText, payload)
+}
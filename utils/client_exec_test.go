@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/stretchr/testify/require"
+)
+
+// serverFrame encodes an unmasked server-to-client WebSocket frame.
+func serverFrame(opcode byte, payload []byte) []byte {
+	frame := []byte{0x80 | opcode, byte(len(payload))}
+	return append(frame, payload...)
+}
+
+func TestExecAllocation_collectsOutputAndExitCode(t *testing.T) {
+	var gotPath, gotTask, gotCommand, gotToken string
+	var gotStdin []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		gotPath = r.URL.Path
+		gotTask = r.URL.Query().Get("task")
+		gotCommand = r.URL.Query().Get("command")
+		gotToken = r.Header.Get("X-Nomad-Token")
+
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + websocketAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		require.NoError(t, rw.Flush())
+
+		client := &wsConn{rw: conn, br: rw.Reader}
+		for {
+			_, message, err := client.readMessage()
+			require.NoError(t, err)
+			gotStdin = append(gotStdin, string(message))
+			if len(gotStdin) == 2 {
+				break
+			}
+		}
+
+		_, _ = conn.Write(serverFrame(wsOpPing, nil))
+		_, _ = conn.Write(serverFrame(wsOpText, []byte(`{"stdout":{"data":"aGVsbG8K"}}`)))
+		_, _ = conn.Write(serverFrame(wsOpText, []byte(`{"stderr":{"data":"b29wcwo="}}`)))
+		_, _ = conn.Write(serverFrame(wsOpText, []byte(`{"stdout":{"close":true}}`)))
+		_, _ = conn.Write(serverFrame(wsOpText, []byte(`{"exited":true,"result":{"exit_code":3}}`)))
+		_, _, _ = client.readMessage()
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "secret")
+	require.NoError(t, err)
+
+	result, err := client.ExecAllocation(context.Background(), types.ExecRequest{
+		AllocID: "a1",
+		Task:    "web",
+		Command: []string{"/bin/sh", "-c", "cat"},
+		Stdin:   "hi",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/v1/client/allocation/a1/exec", gotPath)
+	require.Equal(t, "web", gotTask)
+	require.Equal(t, `["/bin/sh","-c","cat"]`, gotCommand)
+	require.Equal(t, "secret", gotToken)
+	require.Len(t, gotStdin, 2)
+	var stdin execFrame
+	require.NoError(t, json.Unmarshal([]byte(gotStdin[0]), &stdin))
+	require.Equal(t, "hi", string(stdin.Stdin.Data))
+	require.JSONEq(t, `{"stdin":{"close":true}}`, gotStdin[1])
+	require.Equal(t, types.ExecResult{Stdout: "hello\n", Stderr: "oops\n", ExitCode: 3}, result)
+}
+
+func TestExecAllocation_returnsHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		http.Error(w, "Permission denied", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	_, err = client.ExecAllocation(context.Background(), types.ExecRequest{AllocID: "a1", Task: "web", Command: []string{"id"}})
+	var httpErr *NomadHTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusForbidden, httpErr.StatusCode)
+}
@@ -2,8 +2,10 @@ package utils
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -19,12 +21,18 @@ func normalizeAPIPath(p string) string {
 	return p
 }
 
-func applyRegionFromEnvironment(query url.Values, queryKeys map[string]bool) {
+// applyRegion sets the region query parameter unless the request already has one: the
+// region stored in ctx (session defaults) wins over NOMAD_REGION.
+func applyRegion(ctx context.Context, query url.Values, queryKeys map[string]bool) {
 	// Nomad forwards cross-region RPC when "region" is set (REST query param).
 	// Mirrors NOMAD_REGION used by Nomad CLI; see Nomad HTTP API docs.
 	if query.Has("region") || queryKeys["region"] {
 		return
 	}
+	if reg := RegionFromContext(ctx); reg != "" {
+		query.Set("region", reg)
+		return
+	}
 	if reg := strings.TrimSpace(os.Getenv("NOMAD_REGION")); reg != "" {
 		query.Set("region", reg)
 	}
@@ -32,24 +40,144 @@ func applyRegionFromEnvironment(query url.Values, queryKeys map[string]bool) {
 
 // makeRequest is a helper function to make HTTP requests to the Nomad API.
 func (c *NomadClient) makeRequest(ctx context.Context, method, path string, queryParams map[string]string, body interface{}) ([]byte, error) {
-	rel := normalizeAPIPath(path)
-	base := strings.TrimSuffix(c.address, "/")
-	baseURL := fmt.Sprintf("%s/v1/%s", base, rel)
+	statusCode, respBody, err := c.doRequest(ctx, method, path, queryParams, body)
+	if err != nil {
+		return nil, err
+	}
 
+	if statusCode >= 400 {
+		httpErr := NewNomadHTTPError(statusCode, method, normalizeAPIPath(path), respBody)
+		httpErr.RequestID = RequestIDFromContext(ctx)
+		return nil, httpErr
+	}
+
+	return respBody, nil
+}
+
+// doRequest sends a request to the Nomad API and returns the status code and the full
+// response body without treating error statuses as failures. Endpoints such as autopilot
+// health answer with a useful body on non-2xx statuses.
+func (c *NomadClient) doRequest(ctx context.Context, method, path string, queryParams map[string]string, body interface{}) (int, []byte, error) {
 	query := url.Values{}
-	queryKeysPresent := map[string]bool{}
 	for key, value := range queryParams {
-		queryKeysPresent[key] = true
 		query.Set(key, value)
 	}
-	applyRegionFromEnvironment(query, queryKeysPresent)
+	req, err := c.newRequest(ctx, method, path, query, body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respReader, err := decodedBody(resp)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error decoding response body: %w", err)
+	}
+	defer respReader.Close()
+
+	respBody, err := io.ReadAll(respReader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	return resp.StatusCode, respBody, nil
+}
+
+// ErrStopStream is returned by the handler of a streaming method (StreamEvents,
+// StreamAllocationLogs) to close the stream without error.
+var ErrStopStream = errors.New("stop stream")
+
+// doStream sends a GET request for a long-lived streaming endpoint and returns the open
+// response body. Unlike doRequest it is not bounded by the client timeout; cancel ctx to
+// close the stream. Error statuses are returned as *NomadHTTPError.
+func (c *NomadClient) doStream(ctx context.Context, path string, query url.Values) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, "GET", path, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+
+	respReader, err := decodedBody(resp)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("error decoding response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(io.LimitReader(respReader, MaxNomadHTTPErrorBodyBytes+1))
+		httpErr := NewNomadHTTPError(resp.StatusCode, "GET", normalizeAPIPath(path), respBody)
+		httpErr.RequestID = RequestIDFromContext(ctx)
+		return nil, httpErr
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{respReader, resp.Body}, nil
+}
+
+// decodeStreamFrames decodes the JSON frames of a streaming response and calls handle for
+// each one skip does not reject (heartbeats), until the stream ends, ctx is done or handle
+// returns an error. The end of the stream, ctx and ErrStopStream are not errors.
+func decodeStreamFrames[T any](ctx context.Context, body io.Reader, skip func(T) bool, handle func(T) error) error {
+	decoder := json.NewDecoder(body)
+	for {
+		var frame T
+		if err := decoder.Decode(&frame); err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("error decoding stream: %v", err)
+		}
+		if skip(frame) {
+			continue
+		}
+		if err := handle(frame); err != nil {
+			if errors.Is(err, ErrStopStream) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// newRequest builds a Nomad API request with the region, request ID and token of ctx.
+// query may repeat keys (e.g. the event stream's topic) and is modified in place. Requests
+// for an API feature the cluster's Nomad version lacks fail with *UnsupportedFeatureError.
+func (c *NomadClient) newRequest(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Request, error) {
+	if err := c.checkCompatibility(ctx, path); err != nil {
+		return nil, err
+	}
+
+	rel := normalizeAPIPath(path)
+	base := strings.TrimSuffix(c.GetAddress(), "/")
+	baseURL := fmt.Sprintf("%s/v1/%s", base, rel)
+
+	applyRegion(ctx, query, nil)
 
 	if encoded := query.Encode(); encoded != "" {
 		baseURL = fmt.Sprintf("%s?%s", baseURL, encoded)
 	}
 
+	// An io.Reader body (e.g. a snapshot) is sent as is; anything else is sent as JSON.
 	var reqBody io.Reader
-	if body != nil {
+	contentType := "application/json"
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reqBody = b
+		contentType = "application/octet-stream"
+	default:
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("error marshaling request body: %w", err)
@@ -62,29 +190,29 @@ func (c *NomadClient) makeRequest(ctx context.Context, method, path string, quer
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
+	// Setting Accept-Encoding ourselves disables net/http's transparent decoding, see decodedBody.
+	req.Header.Set("Accept-Encoding", "gzip")
 
-	// Add ACL token to headers if available
-	if c.token != "" {
-		req.Header.Set("X-Nomad-Token", c.token)
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		req.Header.Set(RequestIDHeader, requestID)
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+	// Add ACL token to headers if available; the caller's token (WithToken) wins
+	if token := c.tokenFor(ctx); token != "" {
+		req.Header.Set("X-Nomad-Token", token)
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
-	}
+	return req, nil
+}
 
-	if resp.StatusCode >= 400 {
-		return nil, NewNomadHTTPError(resp.StatusCode, method, rel, respBody)
+// decodedBody returns the response body, transparently decompressing gzip-encoded payloads
+// (large job and allocation lists compress very well on remote clusters).
+func decodedBody(resp *http.Response) (io.ReadCloser, error) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return io.NopCloser(resp.Body), nil
 	}
-
-	return respBody, nil
+	return gzip.NewReader(resp.Body)
 }
 
 // MakeRequest performs an HTTP request to the Nomad API for MCP tools that cannot use typed client methods yet.
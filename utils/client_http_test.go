@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeRequest_decodesGzipResponses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			_, _ = w.Write([]byte(`"plain"`))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(`"compressed"`))
+		_ = gz.Close()
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	body, err := client.makeRequest(context.Background(), "GET", "status/leader", nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, `"compressed"`, string(body))
+}
+
+func TestMakeRequest_regionPrecedence(t *testing.T) {
+	t.Setenv("NOMAD_REGION", "global")
+
+	var regions []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		regions = append(regions, r.URL.Query().Get("region"))
+		_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+	regions = nil
+
+	ctx := WithRegion(context.Background(), "eu")
+	_, err = client.makeRequest(context.Background(), "GET", "status/leader", nil, nil)
+	require.NoError(t, err)
+	_, err = client.makeRequest(ctx, "GET", "status/leader", nil, nil)
+	require.NoError(t, err)
+	_, err = client.makeRequest(ctx, "GET", "status/leader", map[string]string{"region": "us"}, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"global", "eu", "us"}, regions)
+}
+
+func TestMakeRequest_prefersCallerToken(t *testing.T) {
+	var tokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("X-Nomad-Token"))
+		_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "server-token")
+	require.NoError(t, err)
+	tokens = nil
+
+	_, err = client.makeRequest(context.Background(), "GET", "status/leader", nil, nil)
+	require.NoError(t, err)
+	_, err = client.makeRequest(WithToken(context.Background(), "alice-token"), "GET", "status/leader", nil, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"server-token", "alice-token"}, tokens)
+}
+
+func TestSetAddress_isSafeWhileRequestsRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, err := client.GetLeader(context.Background())
+				require.NoError(t, err)
+			}
+		}()
+	}
+	for j := 0; j < 20; j++ {
+		client.SetAddress(srv.URL + "/")
+		client.SetAddress(srv.URL)
+	}
+	wg.Wait()
+	require.Equal(t, srv.URL, client.GetAddress())
+}
+
+func TestSetToken_isSafeWhileRequestsRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, err := client.GetLeader(context.Background())
+				require.NoError(t, err)
+			}
+		}()
+	}
+	for j := 0; j < 20; j++ {
+		client.SetToken("bootstrap-secret")
+		client.SetToken("")
+	}
+	wg.Wait()
+	require.Empty(t, client.GetToken())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/kocierik/mcp-nomad/types"
 )
@@ -51,74 +52,181 @@ func (c *NomadClient) GetJob(ctx context.Context, jobID, namespace string) (type
 	return job, nil
 }
 
-// RunJob submits a job to Nomad
-func (c *NomadClient) RunJob(ctx context.Context, jobSpec string, detach bool) (map[string]interface{}, error) {
-	// Try to parse as JSON first
-	var jobData interface{}
-	if err := json.Unmarshal([]byte(jobSpec), &jobData); err != nil {
-		// If not JSON, assume it's HCL and use Nomad's HCL parser endpoint
-		path := "jobs/parse"
-		parseRequest := map[string]string{
-			"JobHCL": jobSpec,
-		}
+// GetJobDefinition retrieves a job as the raw API object. Unlike GetJob it keeps every field,
+// so the result can be modified and registered again without dropping configuration.
+func (c *NomadClient) GetJobDefinition(ctx context.Context, jobID, namespace string) (map[string]interface{}, error) {
+	path := fmt.Sprintf("job/%s", jobID)
 
-		// First parse the HCL to validate and convert to JSON
-		parseResp, err := c.makeRequest(ctx, "POST", path, nil, parseRequest)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing HCL job spec: %v", err)
-		}
+	queryParams := make(map[string]string)
+	AddNomadNamespaceQuery(queryParams, namespace)
 
-		// Unmarshal the parse response into a map
-		var parsedJob map[string]interface{}
-		if err := json.Unmarshal(parseResp, &parsedJob); err != nil {
-			return nil, fmt.Errorf("error unmarshaling parsed job spec: %v", err)
-		}
+	respBody, err := c.makeRequest(ctx, "GET", path, queryParams, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var job map[string]interface{}
+	if err := json.Unmarshal(respBody, &job); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return job, nil
+}
+
+// RunJob submits a job to Nomad. A non-empty namespace overrides the namespace declared in the job spec.
+func (c *NomadClient) RunJob(ctx context.Context, jobSpec, namespace string, detach bool) (types.JobRegisterResponse, error) {
+	return c.registerJob(ctx, jobSpec, namespace, detach, nil)
+}
+
+// EnforceRunJob is RunJob with a check-and-set on the job's modify index: Nomad rejects the
+// registration unless the job's current JobModifyIndex equals jobModifyIndex (0 means the job
+// must not exist yet).
+func (c *NomadClient) EnforceRunJob(ctx context.Context, jobSpec, namespace string, detach bool, jobModifyIndex int) (types.JobRegisterResponse, error) {
+	return c.registerJob(ctx, jobSpec, namespace, detach, &jobModifyIndex)
+}
+
+func (c *NomadClient) registerJob(ctx context.Context, jobSpec, namespace string, detach bool, jobModifyIndex *int) (types.JobRegisterResponse, error) {
+	jobData, err := c.ParseJobSpec(ctx, jobSpec)
+	if err != nil {
+		return types.JobRegisterResponse{}, err
+	}
 
-		// Use the parsed job data
-		jobData = parsedJob
+	if namespace != "" {
+		setJobNamespace(jobData, namespace)
 	}
 
 	// Wrap the job data in a Job field as required by the Nomad API
 	jobRequest := map[string]interface{}{
 		"Job": jobData,
 	}
+	if jobModifyIndex != nil {
+		jobRequest["EnforceIndex"] = true
+		jobRequest["JobModifyIndex"] = *jobModifyIndex
+	}
+	if submission := jobSpecSubmission(jobSpec); submission != nil {
+		jobRequest["Submission"] = submission
+	}
 
 	queryParams := map[string]string{}
+	AddNomadNamespaceQuery(queryParams, namespace)
 	if detach {
 		queryParams["detach"] = "true"
 	}
 
 	respBody, err := c.makeRequest(ctx, "POST", "jobs", queryParams, jobRequest)
 	if err != nil {
-		return nil, err
+		return types.JobRegisterResponse{}, err
 	}
 
-	var result map[string]interface{}
+	var result types.JobRegisterResponse
 	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+		return types.JobRegisterResponse{}, fmt.Errorf("error unmarshaling response: %v", err)
 	}
 
 	return result, nil
 }
 
+// ParseJobSpec decodes a JSON or HCL job specification into the API job object.
+// JSON may be a bare job or wrapped as {"Job": {...}}; HCL is converted by Nomad's parse endpoint.
+func (c *NomadClient) ParseJobSpec(ctx context.Context, jobSpec string) (map[string]interface{}, error) {
+	return c.parseJobSpec(ctx, jobSpec, false)
+}
+
+// CanonicalizeJobSpec decodes a job specification like ParseJobSpec, but has Nomad fill in
+// the defaults of an HCL spec (region, update strategy, restart policy, ...) as it does when
+// the job is registered. A JSON spec is returned as given.
+func (c *NomadClient) CanonicalizeJobSpec(ctx context.Context, jobSpec string) (map[string]interface{}, error) {
+	return c.parseJobSpec(ctx, jobSpec, true)
+}
+
+func (c *NomadClient) parseJobSpec(ctx context.Context, jobSpec string, canonicalize bool) (map[string]interface{}, error) {
+	// Try to parse as JSON first
+	var jobData interface{}
+	if err := json.Unmarshal([]byte(jobSpec), &jobData); err == nil {
+		job, ok := jobData.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("job spec must be a JSON object")
+		}
+		if inner, ok := job["Job"].(map[string]interface{}); ok {
+			job = inner
+		}
+		return job, nil
+	}
+
+	// If not JSON, assume it's HCL and use Nomad's HCL parser endpoint
+	parseRequest := map[string]interface{}{
+		"JobHCL": jobSpec,
+	}
+	if canonicalize {
+		parseRequest["Canonicalize"] = true
+	}
+	parseResp, err := c.makeRequest(ctx, "POST", "jobs/parse", nil, parseRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing HCL job spec: %v", err)
+	}
+
+	var parsedJob map[string]interface{}
+	if err := json.Unmarshal(parseResp, &parsedJob); err != nil {
+		return nil, fmt.Errorf("error unmarshaling parsed job spec: %v", err)
+	}
+
+	return parsedJob, nil
+}
+
+// jobSpecSubmission returns the submission registered along with a job spec, so Nomad
+// stores the source the job was written in (see GetJobSubmission): an HCL spec is its own
+// submission, and a register request, {"Job": {...}, "Submission": {...}}, keeps its own.
+// Other JSON specs carry none.
+func jobSpecSubmission(jobSpec string) interface{} {
+	var wrapped struct {
+		Submission map[string]interface{} `json:"Submission"`
+	}
+	if err := json.Unmarshal([]byte(jobSpec), &wrapped); err != nil {
+		return types.JobSubmission{Source: jobSpec, Format: "hcl2"}
+	}
+	if wrapped.Submission == nil {
+		return nil
+	}
+	return wrapped.Submission
+}
+
+// setJobNamespace overrides the Namespace of a decoded job, accepting either a bare job
+// object or one wrapped as {"Job": {...}}.
+func setJobNamespace(jobData interface{}, namespace string) {
+	job, ok := jobData.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if inner, ok := job["Job"].(map[string]interface{}); ok {
+		job = inner
+	}
+	job["Namespace"] = namespace
+}
+
 // StopJob stops a job
-func (c *NomadClient) StopJob(ctx context.Context, jobID, namespace string, purge bool) (map[string]interface{}, error) {
+func (c *NomadClient) StopJob(ctx context.Context, jobID, namespace string, opts types.JobStopOptions) (types.JobDeregisterResponse, error) {
 	path := fmt.Sprintf("job/%s", jobID)
 
 	queryParams := make(map[string]string)
 	AddNomadNamespaceQuery(queryParams, namespace)
-	if purge {
+	if opts.Purge {
 		queryParams["purge"] = "true"
 	}
+	if opts.Global {
+		queryParams["global"] = "true"
+	}
+	if opts.NoShutdownDelay {
+		queryParams["no_shutdown_delay"] = "true"
+	}
 
 	respBody, err := c.makeRequest(ctx, "DELETE", path, queryParams, nil)
 	if err != nil {
-		return nil, err
+		return types.JobDeregisterResponse{}, err
 	}
 
-	var result map[string]interface{}
+	var result types.JobDeregisterResponse
 	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+		return types.JobDeregisterResponse{}, fmt.Errorf("error unmarshaling response: %v", err)
 	}
 
 	return result, nil
@@ -138,19 +246,70 @@ func (c *NomadClient) GetJobVersions(ctx context.Context, jobID, namespace strin
 	return versions, nil
 }
 
-// GetJobSubmission retrieves the original job submission
-func (c *NomadClient) GetJobSubmission(ctx context.Context, jobID, namespace string) (string, error) {
-	path := fmt.Sprintf("job/%s/submission", jobID)
+// GetJobVersionDiffs returns the versions of a job with the diff of each version against
+// the one before it
+func (c *NomadClient) GetJobVersionDiffs(ctx context.Context, jobID, namespace string) (types.JobVersionsResponse, error) {
+	path := fmt.Sprintf("job/%s/versions", jobID)
+
+	queryParams := map[string]string{"diffs": "true"}
+	AddNomadNamespaceQuery(queryParams, namespace)
+
+	respBody, err := c.makeRequest(ctx, "GET", path, queryParams, nil)
+	if err != nil {
+		return types.JobVersionsResponse{}, err
+	}
+
+	var versions types.JobVersionsResponse
+	if err := json.Unmarshal(respBody, &versions); err != nil {
+		return types.JobVersionsResponse{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return versions, nil
+}
+
+// GetJobVersionSpec returns one version of a job as the raw API job, like GetJobDefinition
+func (c *NomadClient) GetJobVersionSpec(ctx context.Context, jobID, namespace string, version int) (map[string]interface{}, error) {
+	path := fmt.Sprintf("job/%s/versions", jobID)
 
 	queryParams := make(map[string]string)
 	AddNomadNamespaceQuery(queryParams, namespace)
 
 	respBody, err := c.makeRequest(ctx, "GET", path, queryParams, nil)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	var versions []map[string]interface{}
+	if err := json.Unmarshal(respBody, &versions); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	for _, job := range versions {
+		if v, ok := job["Version"].(float64); ok && int(v) == version {
+			return job, nil
+		}
+	}
+	return nil, fmt.Errorf("job %s has no version %d; Nomad only keeps recent versions", jobID, version)
+}
+
+// GetJobSubmission retrieves the source a version of a job was registered from
+func (c *NomadClient) GetJobSubmission(ctx context.Context, jobID, namespace string, version int) (types.JobSubmission, error) {
+	path := fmt.Sprintf("job/%s/submission", jobID)
+
+	queryParams := map[string]string{"version": strconv.Itoa(version)}
+	AddNomadNamespaceQuery(queryParams, namespace)
+
+	respBody, err := c.makeRequest(ctx, "GET", path, queryParams, nil)
+	if err != nil {
+		return types.JobSubmission{}, err
 	}
 
-	return string(respBody), nil
+	var submission types.JobSubmission
+	if err := json.Unmarshal(respBody, &submission); err != nil {
+		return types.JobSubmission{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return submission, nil
 }
 
 // ListJobVersions lists all versions of a job
@@ -224,43 +383,73 @@ func (c *NomadClient) UpdateJob(ctx context.Context, job types.Job, enforceIndex
 	return err
 }
 
-// DispatchJob dispatches a parameterized job
-func (c *NomadClient) DispatchJob(ctx context.Context, jobID string, payload map[string]interface{}, meta map[string]string) (string, error) {
+// DispatchJob creates a child of a parameterized job with the given payload and metadata
+func (c *NomadClient) DispatchJob(ctx context.Context, jobID, namespace string, opts types.JobDispatchOptions) (types.JobDispatchResponse, error) {
 	path := fmt.Sprintf("job/%s/dispatch", jobID)
 
+	queryParams := make(map[string]string)
+	AddNomadNamespaceQuery(queryParams, namespace)
+
 	request := map[string]interface{}{
-		"Payload": payload,
-		"Meta":    meta,
+		"JobID": jobID,
+	}
+	if len(opts.Payload) > 0 {
+		request["Payload"] = opts.Payload
+	}
+	if len(opts.Meta) > 0 {
+		request["Meta"] = opts.Meta
+	}
+	if opts.IdempotencyToken != "" {
+		request["IdempotencyToken"] = opts.IdempotencyToken
 	}
 
-	respBody, err := c.makeRequest(ctx, "POST", path, nil, request)
+	respBody, err := c.makeRequest(ctx, "POST", path, queryParams, request)
 	if err != nil {
-		return "", err
+		return types.JobDispatchResponse{}, err
 	}
 
-	var response struct {
-		DispatchedJobID string `json:"DispatchedJobID"`
-	}
+	var response types.JobDispatchResponse
 	if err := json.Unmarshal(respBody, &response); err != nil {
-		return "", err
+		return types.JobDispatchResponse{}, fmt.Errorf("error unmarshaling response: %v", err)
 	}
 
-	return response.DispatchedJobID, nil
+	return response, nil
 }
 
-// RevertJob reverts a job to a specific version
-func (c *NomadClient) RevertJob(ctx context.Context, jobID string, version int, enforceIndex bool) error {
+// RevertJob reverts a job to a specific version; the response carries the evaluation it
+// creates. With opts.EnforcePriorVersion set, Nomad only reverts while the job's current
+// version still equals it.
+func (c *NomadClient) RevertJob(ctx context.Context, jobID, namespace string, version int, opts types.JobRevertOptions) (types.JobRegisterResponse, error) {
 	path := fmt.Sprintf("job/%s/revert", jobID)
-	if enforceIndex {
-		path = fmt.Sprintf("%s?enforce_index=true", path)
-	}
+
+	queryParams := make(map[string]string)
+	AddNomadNamespaceQuery(queryParams, namespace)
 
 	request := map[string]interface{}{
+		"JobID":      jobID,
 		"JobVersion": version,
 	}
+	if opts.EnforcePriorVersion != nil {
+		request["EnforcePriorVersion"] = *opts.EnforcePriorVersion
+	}
+	if opts.ConsulToken != "" {
+		request["ConsulToken"] = opts.ConsulToken
+	}
+	if opts.VaultToken != "" {
+		request["VaultToken"] = opts.VaultToken
+	}
 
-	_, err := c.makeRequest(ctx, "POST", path, nil, request)
-	return err
+	respBody, err := c.makeRequest(ctx, "POST", path, queryParams, request)
+	if err != nil {
+		return types.JobRegisterResponse{}, err
+	}
+
+	var response types.JobRegisterResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return types.JobRegisterResponse{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return response, nil
 }
 
 // SetJobStability sets the stability of a job
@@ -276,48 +465,83 @@ func (c *NomadClient) SetJobStability(ctx context.Context, jobID string, version
 	return err
 }
 
-// CreateJobEvaluation forces a new evaluation for a job
-func (c *NomadClient) CreateJobEvaluation(ctx context.Context, jobID string) (string, error) {
+// CreateJobEvaluation forces a new evaluation for a job. With forceReschedule, failed
+// allocations are rescheduled even if their reschedule policy is exhausted or delayed.
+func (c *NomadClient) CreateJobEvaluation(ctx context.Context, jobID, namespace string, forceReschedule bool) (types.JobRegisterResponse, error) {
 	path := fmt.Sprintf("job/%s/evaluate", jobID)
 
-	respBody, err := c.makeRequest(ctx, "POST", path, nil, nil)
-	if err != nil {
-		return "", err
+	queryParams := make(map[string]string)
+	AddNomadNamespaceQuery(queryParams, namespace)
+
+	request := map[string]interface{}{
+		"JobID": jobID,
+		"EvalOptions": map[string]interface{}{
+			"ForceReschedule": forceReschedule,
+		},
 	}
 
-	var response struct {
-		EvalID string `json:"EvalID"`
+	respBody, err := c.makeRequest(ctx, "POST", path, queryParams, request)
+	if err != nil {
+		return types.JobRegisterResponse{}, err
 	}
+
+	var response types.JobRegisterResponse
 	if err := json.Unmarshal(respBody, &response); err != nil {
-		return "", err
+		return types.JobRegisterResponse{}, fmt.Errorf("error unmarshaling response: %v", err)
 	}
 
-	return response.EvalID, nil
+	return response, nil
 }
 
-// CreateJobPlan creates a plan for a job
-func (c *NomadClient) CreateJobPlan(ctx context.Context, job types.Job) (types.JobPlan, error) {
-	path := "job/plan"
+// PlanJob dry-runs the registration of a raw API job object and returns the scheduler's
+// annotations together with a diff against the registered version.
+func (c *NomadClient) PlanJob(ctx context.Context, job map[string]interface{}, namespace string) (types.JobPlan, error) {
+	jobID, _ := job["ID"].(string)
+	if jobID == "" {
+		return types.JobPlan{}, fmt.Errorf("job has no ID")
+	}
+	path := fmt.Sprintf("job/%s/plan", jobID)
+
+	queryParams := make(map[string]string)
+	AddNomadNamespaceQuery(queryParams, namespace)
 
-	respBody, err := c.makeRequest(ctx, "POST", path, nil, job)
+	request := map[string]interface{}{
+		"Job":  job,
+		"Diff": true,
+	}
+
+	respBody, err := c.makeRequest(ctx, "POST", path, queryParams, request)
 	if err != nil {
 		return types.JobPlan{}, err
 	}
 
 	var plan types.JobPlan
 	if err := json.Unmarshal(respBody, &plan); err != nil {
-		return types.JobPlan{}, err
+		return types.JobPlan{}, fmt.Errorf("error unmarshaling response: %v", err)
 	}
 
 	return plan, nil
 }
 
-// ForceNewPeriodicInstance forces a new instance of a periodic job
-func (c *NomadClient) ForceNewPeriodicInstance(ctx context.Context, jobID string) error {
+// ForceNewPeriodicInstance launches a periodic job now instead of at its next scheduled time.
+// The response carries the evaluation of the launched child job.
+func (c *NomadClient) ForceNewPeriodicInstance(ctx context.Context, jobID, namespace string) (types.PeriodicForceResponse, error) {
 	path := fmt.Sprintf("job/%s/periodic/force", jobID)
 
-	_, err := c.makeRequest(ctx, "POST", path, nil, nil)
-	return err
+	queryParams := make(map[string]string)
+	AddNomadNamespaceQuery(queryParams, namespace)
+
+	respBody, err := c.makeRequest(ctx, "POST", path, queryParams, nil)
+	if err != nil {
+		return types.PeriodicForceResponse{}, err
+	}
+
+	var response types.PeriodicForceResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return types.PeriodicForceResponse{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return response, nil
 }
 
 // GetJobScaleStatus retrieves the scale status of a job
@@ -341,7 +565,7 @@ func (c *NomadClient) GetJobScaleStatus(ctx context.Context, jobID, namespace st
 }
 
 // ScaleTaskGroup scales a task group
-func (c *NomadClient) ScaleTaskGroup(ctx context.Context, jobID, group string, count int, namespace string) error {
+func (c *NomadClient) ScaleTaskGroup(ctx context.Context, jobID, group string, count int, namespace string) (types.JobRegisterResponse, error) {
 	path := fmt.Sprintf("job/%s/scale", jobID)
 
 	queryParams := make(map[string]string)
@@ -354,12 +578,21 @@ func (c *NomadClient) ScaleTaskGroup(ctx context.Context, jobID, group string, c
 		},
 	}
 
-	_, err := c.makeRequest(ctx, "POST", path, queryParams, request)
-	return err
+	respBody, err := c.makeRequest(ctx, "POST", path, queryParams, request)
+	if err != nil {
+		return types.JobRegisterResponse{}, err
+	}
+
+	var response types.JobRegisterResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return types.JobRegisterResponse{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return response, nil
 }
 
-// ListJobServices lists all services for a job
-func (c *NomadClient) ListJobServices(ctx context.Context, jobID, namespace string) ([]types.Service, error) {
+// ListJobServices lists the service registrations of a job's allocations
+func (c *NomadClient) ListJobServices(ctx context.Context, jobID, namespace string) ([]types.ServiceRegistration, error) {
 	path := fmt.Sprintf("job/%s/services", jobID)
 
 	queryParams := make(map[string]string)
@@ -370,7 +603,7 @@ func (c *NomadClient) ListJobServices(ctx context.Context, jobID, namespace stri
 		return nil, err
 	}
 
-	var services []types.Service
+	var services []types.ServiceRegistration
 	if err := json.Unmarshal(respBody, &services); err != nil {
 		return nil, fmt.Errorf("error unmarshaling response: %v", err)
 	}
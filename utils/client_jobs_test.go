@@ -0,0 +1,293 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanJob_requestsDiff(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/job/web/plan" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		require.Equal(t, "prod", r.URL.Query().Get("namespace"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"JobModifyIndex":17,"Diff":{"Type":"Edited","ID":"web"},
+			"Annotations":{"DesiredTGUpdates":{"app":{"DestructiveUpdate":2}}}}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	plan, err := client.PlanJob(context.Background(), map[string]interface{}{"ID": "web"}, "prod")
+	require.NoError(t, err)
+	require.Equal(t, true, body["Diff"])
+	require.Equal(t, "web", body["Job"].(map[string]interface{})["ID"])
+	require.Equal(t, 17, plan.JobModifyIndex)
+	require.Equal(t, "Edited", plan.Diff.Type)
+}
+
+func TestGetJobSubmission_requestsVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/job/web/submission" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		require.Equal(t, "3", r.URL.Query().Get("version"))
+		require.Equal(t, "prod", r.URL.Query().Get("namespace"))
+		_, _ = w.Write([]byte(`{"Source":"job \"web\" {}","Format":"hcl2","VariableFlags":{"image":"nginx"},"Variables":"count = 2"}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	submission, err := client.GetJobSubmission(context.Background(), "web", "prod", 3)
+	require.NoError(t, err)
+	require.Equal(t, `job "web" {}`, submission.Source)
+	require.Equal(t, "hcl2", submission.Format)
+	require.Equal(t, map[string]string{"image": "nginx"}, submission.VariableFlags)
+	require.Equal(t, "count = 2", submission.Variables)
+}
+
+func TestRunJob_keepsHCLSubmission(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/jobs/parse":
+			_, _ = w.Write([]byte(`{"ID":"web"}`))
+		case "/v1/jobs":
+			body = nil
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			_, _ = w.Write([]byte(`{"EvalID":"e1"}`))
+		default:
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	source := "# web service\njob \"web\" {}\n"
+	_, err = client.RunJob(context.Background(), source, "", false)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"Source": source, "Format": "hcl2"}, body["Submission"])
+
+	_, err = client.RunJob(context.Background(), `{"ID":"web"}`, "", false)
+	require.NoError(t, err)
+	require.NotContains(t, body, "Submission")
+}
+
+func TestRunJob_decodesWarnings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/jobs" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"EvalID":"e1","EvalCreateIndex":30,"JobModifyIndex":29,"Warnings":"1 warning:\n\n* Group \"web\" has warnings"}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	result, err := client.RunJob(context.Background(), `{"ID":"web"}`, "", false)
+	require.NoError(t, err)
+	require.Equal(t, types.JobRegisterResponse{EvalID: "e1", EvalCreateIndex: 30, JobModifyIndex: 29, Warnings: "1 warning:\n\n* Group \"web\" has warnings"}, result)
+}
+
+func TestCanonicalizeJobSpec_requestsDefaultsForHCL(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/jobs/parse" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"ID":"web","Priority":50}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	job, err := client.CanonicalizeJobSpec(context.Background(), "job \"web\" {}")
+	require.NoError(t, err)
+	require.Equal(t, true, body["Canonicalize"])
+	require.Equal(t, float64(50), job["Priority"])
+
+	body = nil
+	_, err = client.ParseJobSpec(context.Background(), "job \"web\" {}")
+	require.NoError(t, err)
+	require.NotContains(t, body, "Canonicalize")
+}
+
+func TestStopJob_sendsStopOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		require.Equal(t, "/v1/job/web", r.URL.Path)
+		q := r.URL.Query()
+		require.Equal(t, "true", q.Get("global"))
+		require.Equal(t, "true", q.Get("no_shutdown_delay"))
+		require.Empty(t, q.Get("purge"))
+		_, _ = w.Write([]byte(`{"EvalID":"eval-9","JobModifyIndex":12}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	result, err := client.StopJob(context.Background(), "web", "", types.JobStopOptions{Global: true, NoShutdownDelay: true})
+	require.NoError(t, err)
+	require.Equal(t, "eval-9", result.EvalID)
+}
+
+func TestDispatchJob_sendsPayloadMetaAndToken(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/job/resize/dispatch" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		require.Equal(t, "media", r.URL.Query().Get("namespace"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"DispatchedJobID":"resize/dispatch-1700000000-abcd1234","EvalID":"e1","EvalCreateIndex":12,"JobCreateIndex":11}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	result, err := client.DispatchJob(context.Background(), "resize", "media", types.JobDispatchOptions{
+		Payload:          []byte("image.png"),
+		Meta:             map[string]string{"width": "640"},
+		IdempotencyToken: "req-1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "resize/dispatch-1700000000-abcd1234", result.DispatchedJobID)
+	require.Equal(t, "e1", result.EvalID)
+	require.Equal(t, "aW1hZ2UucG5n", body["Payload"])
+	require.Equal(t, map[string]interface{}{"width": "640"}, body["Meta"])
+	require.Equal(t, "req-1", body["IdempotencyToken"])
+}
+
+func TestScaleTaskGroup_decodesWarnings(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/job/web/scale" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"EvalID":"e4","JobModifyIndex":31,"Warnings":"count exceeds the scaling policy max of 5"}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	result, err := client.ScaleTaskGroup(context.Background(), "web", "app", 6, "")
+	require.NoError(t, err)
+	require.Equal(t, float64(6), body["Count"])
+	require.Equal(t, "e4", result.EvalID)
+	require.Equal(t, "count exceeds the scaling policy max of 5", result.Warnings)
+}
+
+func TestForceNewPeriodicInstance_usesNamespace(t *testing.T) {
+	var method string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/job/backup/periodic/force" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		method = r.Method
+		require.Equal(t, "ops", r.URL.Query().Get("namespace"))
+		_, _ = w.Write([]byte(`{"EvalID":"e5","EvalCreateIndex":40,"Index":40}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	result, err := client.ForceNewPeriodicInstance(context.Background(), "backup", "ops")
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPost, method)
+	require.Equal(t, types.PeriodicForceResponse{EvalID: "e5", EvalCreateIndex: 40}, result)
+}
+
+func TestCreateJobEvaluation_forcesReschedule(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/job/web/evaluate" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		require.Equal(t, "prod", r.URL.Query().Get("namespace"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"EvalID":"eval-3"}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	result, err := client.CreateJobEvaluation(context.Background(), "web", "prod", true)
+	require.NoError(t, err)
+	require.Equal(t, "eval-3", result.EvalID)
+	require.Equal(t, map[string]interface{}{"ForceReschedule": true}, body["EvalOptions"])
+}
+
+func TestGetJobVersionDiffs_requestsDiffs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/job/web/versions" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		require.Equal(t, "true", r.URL.Query().Get("diffs"))
+		require.Equal(t, "prod", r.URL.Query().Get("namespace"))
+		_, _ = w.Write([]byte(`{"Versions":[{"ID":"web","Version":1,"Stable":true},{"ID":"web","Version":0}],
+			"Diffs":[{"Type":"Edited","ID":"web"}],"Index":12}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+	history, err := client.GetJobVersionDiffs(context.Background(), "web", "prod")
+	require.NoError(t, err)
+	require.Len(t, history.Versions, 2)
+	require.True(t, history.Versions[0].Stable)
+	require.Equal(t, []types.JobDiff{{Type: "Edited", ID: "web"}}, history.Diffs)
+}
+
+func TestGetJobVersionSpec_picksTheVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/job/web/versions" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"ID":"web","Version":2,"Priority":70},{"ID":"web","Version":1,"Priority":50}]`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+	job, err := client.GetJobVersionSpec(context.Background(), "web", "", 1)
+	require.NoError(t, err)
+	require.Equal(t, float64(50), job["Priority"])
+
+	_, err = client.GetJobVersionSpec(context.Background(), "web", "", 0)
+	require.ErrorContains(t, err, "no version 0")
+}
@@ -3,7 +3,12 @@ package utils
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/kocierik/mcp-nomad/types"
 )
 
 // GetAllocationLogs retrieves logs from a specific task in an allocation
@@ -28,11 +33,19 @@ func (c *NomadClient) GetAllocationLogs(ctx context.Context, allocID, task, logT
 		"plain":  "true",
 	}
 
+	// Without a tail or an offset, show the default number of lines from the end
+	if tail == 0 && offset == 0 {
+		tail = int64(c.DefaultTailLines)
+	}
+
 	// If tail is specified, we want to read from the end
 	if tail > 0 {
 		queryParams["origin"] = "end"
 		// Estimate bytes needed for tail lines (assume average 200 bytes per line)
 		estimatedBytes := tail * 200
+		if c.MaxLogBytes > 0 {
+			estimatedBytes = min(estimatedBytes, c.MaxLogBytes)
+		}
 		queryParams["offset"] = fmt.Sprintf("%d", estimatedBytes)
 	} else if offset > 0 {
 		queryParams["offset"] = fmt.Sprintf("%d", offset)
@@ -45,6 +58,15 @@ func (c *NomadClient) GetAllocationLogs(ctx context.Context, allocID, task, logT
 		return "", fmt.Errorf("failed to get allocation logs: %v", err)
 	}
 
+	// Keep the output within the byte cap: the end of a tail, the start of a read from an offset
+	if c.MaxLogBytes > 0 && int64(len(respBody)) > c.MaxLogBytes {
+		if tail > 0 {
+			respBody = respBody[int64(len(respBody))-c.MaxLogBytes:]
+		} else {
+			respBody = respBody[:c.MaxLogBytes]
+		}
+	}
+
 	// If tail was specified, we need to process the response to get the correct number of lines
 	if tail > 0 {
 		lines := strings.Split(string(respBody), "\n")
@@ -57,3 +79,122 @@ func (c *NomadClient) GetAllocationLogs(ctx context.Context, allocID, task, logT
 
 	return string(respBody), nil
 }
+
+// StreamAllocationLogs follows a task log (client/fs/logs with follow=true) and calls handle
+// for every frame carrying data or a file event, until ctx is done, handle returns an error
+// or the task's log ends. Cancelling ctx or returning ErrStopStream ends the stream with a
+// nil error.
+func (c *NomadClient) StreamAllocationLogs(ctx context.Context, request types.LogStreamRequest, handle func(types.LogFrame) error) error {
+	if request.AllocID == "" {
+		return fmt.Errorf("allocation ID is required")
+	}
+	if request.Task == "" {
+		return fmt.Errorf("task name is required")
+	}
+
+	logType := request.Type
+	if logType == "" {
+		logType = "stdout"
+	}
+
+	query := url.Values{}
+	query.Set("task", request.Task)
+	query.Set("type", logType)
+	query.Set("follow", "true")
+	query.Set("plain", "false")
+	if request.Origin != "" {
+		query.Set("origin", request.Origin)
+	}
+	query.Set("offset", strconv.FormatInt(request.Offset, 10))
+
+	body, err := c.doStream(ctx, fmt.Sprintf("client/fs/logs/%s", request.AllocID), query)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	defer body.Close()
+
+	return decodeStreamFrames(ctx, body, func(frame types.LogFrame) bool { return len(frame.Data) == 0 && frame.FileEvent == "" }, handle)
+}
+
+// ReadAllocationLogs reads at most limit bytes of a task log from request.Origin and
+// request.Offset. Unlike client/fs/logs it returns exact byte offsets: the rotated log
+// files are listed and read with client/fs/readat, so a page can be requested again or
+// the previous one read deterministically. An offset counted from the end that goes past
+// the start is clamped to it.
+func (c *NomadClient) ReadAllocationLogs(ctx context.Context, request types.LogStreamRequest, limit int64) (types.LogPage, error) {
+	if request.AllocID == "" {
+		return types.LogPage{}, fmt.Errorf("allocation ID is required")
+	}
+	if request.Task == "" {
+		return types.LogPage{}, fmt.Errorf("task name is required")
+	}
+	if limit <= 0 {
+		return types.LogPage{}, fmt.Errorf("limit must be positive")
+	}
+
+	logType := request.Type
+	if logType == "" {
+		logType = "stdout"
+	}
+
+	entries, err := c.ListAllocationFiles(ctx, request.AllocID, "alloc/logs")
+	if err != nil {
+		return types.LogPage{}, fmt.Errorf("failed to list allocation logs: %v", err)
+	}
+
+	type logFile struct {
+		path  string
+		index int
+		size  int64
+	}
+	prefix := fmt.Sprintf("%s.%s.", request.Task, logType)
+	var files []logFile
+	var size int64
+	for _, entry := range entries {
+		suffix, ok := strings.CutPrefix(entry.Name, prefix)
+		if !ok || entry.IsDir {
+			continue
+		}
+		index, err := strconv.Atoi(suffix)
+		if err != nil {
+			continue
+		}
+		files = append(files, logFile{path: "alloc/logs/" + entry.Name, index: index, size: entry.Size})
+		size += entry.Size
+	}
+	if len(files) == 0 {
+		return types.LogPage{}, fmt.Errorf("no %s log found for task %s", logType, request.Task)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].index < files[j].index })
+
+	start := request.Offset
+	if request.Origin == "end" {
+		start = max(size-request.Offset, 0)
+	}
+	if start < 0 || start > size {
+		return types.LogPage{}, fmt.Errorf("offset %d is outside the %s log of task %s (%d bytes)", request.Offset, logType, request.Task, size)
+	}
+	end := min(start+limit, size)
+
+	page := types.LogPage{Offset: start, NextOffset: end, Size: size}
+	var fileStart int64
+	for _, file := range files {
+		fileEnd := fileStart + file.size
+		if fileEnd > start && fileStart < end {
+			from := max(start, fileStart)
+			data, err := c.ReadAllocationFile(ctx, request.AllocID, file.path, from-fileStart, min(end, fileEnd)-from)
+			if err != nil {
+				return types.LogPage{}, fmt.Errorf("failed to read %s: %v", file.path, err)
+			}
+			page.Data = append(page.Data, data...)
+		}
+		fileStart = fileEnd
+	}
+	// A file may have shrunk since it was listed; report where the data really ends
+	page.NextOffset = start + int64(len(page.Data))
+
+	return page, nil
+}
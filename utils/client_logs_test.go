@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamAllocationLogs_decodesFrames(t *testing.T) {
+	var gotQuery map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/client/fs/logs/a1" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		q := r.URL.Query()
+		gotQuery = map[string]string{"task": q.Get("task"), "type": q.Get("type"), "follow": q.Get("follow"), "plain": q.Get("plain"), "origin": q.Get("origin"), "offset": q.Get("offset")}
+		_, _ = w.Write([]byte(`{"File":"alloc/logs/web.stdout.0","Data":"aGVsbG8K","Offset":6}`))
+		_, _ = w.Write([]byte(`{}`))
+		_, _ = w.Write([]byte(`{"File":"alloc/logs/web.stdout.0","FileEvent":"file truncated"}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	var frames []types.LogFrame
+	err = client.StreamAllocationLogs(context.Background(), types.LogStreamRequest{AllocID: "a1", Task: "web", Origin: "end", Offset: 400}, func(frame types.LogFrame) error {
+		frames = append(frames, frame)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"task": "web", "type": "stdout", "follow": "true", "plain": "false", "origin": "end", "offset": "400"}, gotQuery)
+	require.Len(t, frames, 2)
+	require.Equal(t, "hello\n", string(frames[0].Data))
+	require.Equal(t, int64(6), frames[0].Offset)
+	require.Equal(t, "file truncated", frames[1].FileEvent)
+}
+
+func TestReadAllocationLogs_readsAcrossRotatedFiles(t *testing.T) {
+	files := map[string]string{
+		"alloc/logs/web.stdout.0": "0123456789",
+		"alloc/logs/web.stdout.1": "abcdef",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/v1/client/fs/ls/a1":
+			_, _ = w.Write([]byte(`[
+				{"Name":"web.stdout.1","Size":6},
+				{"Name":"web.stderr.0","Size":4},
+				{"Name":".web.stdout.fifo","Size":0},
+				{"Name":"web.stdout.0","Size":10}
+			]`))
+		case "/v1/client/fs/readat/a1":
+			offset, _ := strconv.Atoi(q.Get("offset"))
+			limit, _ := strconv.Atoi(q.Get("limit"))
+			_, _ = w.Write([]byte(files[q.Get("path")][offset : offset+limit]))
+		default:
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	page, err := client.ReadAllocationLogs(context.Background(), types.LogStreamRequest{AllocID: "a1", Task: "web", Origin: "start", Offset: 7}, 5)
+	require.NoError(t, err)
+	require.Equal(t, "789ab", string(page.Data))
+	require.Equal(t, types.LogPage{Data: page.Data, Offset: 7, NextOffset: 12, Size: 16}, page)
+
+	page, err = client.ReadAllocationLogs(context.Background(), types.LogStreamRequest{AllocID: "a1", Task: "web", Origin: "end", Offset: 20}, 4)
+	require.NoError(t, err)
+	require.Equal(t, "0123", string(page.Data))
+	require.Equal(t, int64(0), page.Offset)
+
+	_, err = client.ReadAllocationLogs(context.Background(), types.LogStreamRequest{AllocID: "a1", Task: "web", Offset: 17}, 4)
+	require.ErrorContains(t, err, "outside the stdout log")
+}
+
+func TestGetAllocationLogs_defaultsTailAndCapsBytes(t *testing.T) {
+	var gotOffset, gotOrigin string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/client/fs/logs/a1" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		gotOffset, gotOrigin = r.URL.Query().Get("offset"), r.URL.Query().Get("origin")
+		_, _ = w.Write([]byte("one\ntwo\nthree\nfour"))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+	require.NoError(t, client.SetDefaultTailLines(2))
+	require.NoError(t, client.SetMaxLogBytes(300))
+
+	logs, err := client.GetAllocationLogs(context.Background(), "a1", "web", "stdout", false, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, "end", gotOrigin)
+	require.Equal(t, "300", gotOffset)
+	require.Equal(t, "three\nfour", logs)
+
+	require.NoError(t, client.SetMaxLogBytes(7))
+	logs, err = client.GetAllocationLogs(context.Background(), "a1", "web", "stdout", false, 0, 4)
+	require.NoError(t, err)
+	require.Equal(t, "4", gotOffset)
+	require.Equal(t, "one\ntwo", logs)
+
+	require.Error(t, client.SetMaxLogBytes(0))
+}
@@ -23,6 +23,22 @@ func (c *NomadClient) ListNamespaces(ctx context.Context) ([]types.Namespace, er
 	return namespaces, nil
 }
 
+// GetNamespace retrieves a namespace by name
+func (c *NomadClient) GetNamespace(ctx context.Context, name string) (types.Namespace, error) {
+	path := fmt.Sprintf("namespace/%s", name)
+	respBody, err := c.makeRequest(ctx, "GET", path, nil, nil)
+	if err != nil {
+		return types.Namespace{}, err
+	}
+
+	var namespace types.Namespace
+	if err := json.Unmarshal(respBody, &namespace); err != nil {
+		return types.Namespace{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return namespace, nil
+}
+
 // CreateNamespace creates a new namespace
 func (c *NomadClient) CreateNamespace(ctx context.Context, namespace types.Namespace) error {
 	_, err := c.makeRequest(ctx, "POST", "namespace", nil, namespace)
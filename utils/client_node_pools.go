@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kocierik/mcp-nomad/types"
+)
+
+// ListNodePools lists the node pools, optionally only those whose name starts with prefix
+func (c *NomadClient) ListNodePools(ctx context.Context, prefix string) ([]types.NodePool, error) {
+	queryParams := make(map[string]string)
+	if prefix != "" {
+		queryParams["prefix"] = prefix
+	}
+
+	respBody, err := c.makeRequest(ctx, "GET", "node/pools", queryParams, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pools []types.NodePool
+	if err := json.Unmarshal(respBody, &pools); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return pools, nil
+}
+
+// GetNodePool retrieves a node pool by name
+func (c *NomadClient) GetNodePool(ctx context.Context, name string) (types.NodePool, error) {
+	path := fmt.Sprintf("node/pool/%s", name)
+
+	respBody, err := c.makeRequest(ctx, "GET", path, nil, nil)
+	if err != nil {
+		return types.NodePool{}, err
+	}
+
+	var pool types.NodePool
+	if err := json.Unmarshal(respBody, &pool); err != nil {
+		return types.NodePool{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return pool, nil
+}
+
+// UpsertNodePool creates a node pool or updates the one of the same name
+func (c *NomadClient) UpsertNodePool(ctx context.Context, pool types.NodePool) error {
+	_, err := c.makeRequest(ctx, "PUT", "node/pools", nil, pool)
+	return err
+}
+
+// DeleteNodePool deletes a node pool
+func (c *NomadClient) DeleteNodePool(ctx context.Context, name string) error {
+	path := fmt.Sprintf("node/pool/%s", name)
+	_, err := c.makeRequest(ctx, "DELETE", path, nil, nil)
+	return err
+}
+
+// ListNodePoolNodes lists the nodes in a node pool
+func (c *NomadClient) ListNodePoolNodes(ctx context.Context, name string) ([]types.NodeListStub, error) {
+	path := fmt.Sprintf("node/pool/%s/nodes", name)
+
+	respBody, err := c.makeRequest(ctx, "GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []types.NodeListStub
+	if err := json.Unmarshal(respBody, &nodes); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePools_upsertAndListNodes(t *testing.T) {
+	var upserted types.NodePool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/node/pools":
+			if r.Method == http.MethodPut {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&upserted))
+				return
+			}
+			require.Equal(t, "gp", r.URL.Query().Get("prefix"))
+			_, _ = w.Write([]byte(`[{"Name":"gpu","Description":"GPU nodes","CreateIndex":5,"ModifyIndex":7}]`))
+		case "/v1/node/pool/gpu/nodes":
+			_, _ = w.Write([]byte(`[{"ID":"n1","Name":"gpu-1","Datacenter":"dc1","NodePool":"gpu","Status":"ready","SchedulingEligibility":"eligible"}]`))
+		default:
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	pool := types.NodePool{Name: "gpu", Meta: map[string]string{"team": "ml"}}
+	require.NoError(t, client.UpsertNodePool(context.Background(), pool))
+	require.Equal(t, pool, upserted)
+
+	pools, err := client.ListNodePools(context.Background(), "gp")
+	require.NoError(t, err)
+	require.Equal(t, []types.NodePool{{Name: "gpu", Description: "GPU nodes", CreateIndex: 5, ModifyIndex: 7}}, pools)
+
+	nodes, err := client.ListNodePoolNodes(context.Background(), "gpu")
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	require.Equal(t, "gpu", nodes[0].NodePool)
+	require.Equal(t, "eligible", nodes[0].SchedulingEligibility)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/kocierik/mcp-nomad/types"
 )
@@ -45,13 +46,83 @@ func (c *NomadClient) GetNode(ctx context.Context, nodeID string) (types.Node, e
 	return node, nil
 }
 
+// ListNodeAllocations lists the allocations placed on a node
+func (c *NomadClient) ListNodeAllocations(ctx context.Context, nodeID string) ([]types.Allocation, error) {
+	path := fmt.Sprintf("node/%s/allocations", nodeID)
+
+	respBody, err := c.makeRequest(ctx, "GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// This endpoint returns full allocations, which embed the job instead of a JobType.
+	var allocs []struct {
+		types.Allocation
+		Job *struct {
+			Type string `json:"Type"`
+		} `json:"Job"`
+	}
+	if err := json.Unmarshal(respBody, &allocs); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	allocations := make([]types.Allocation, 0, len(allocs))
+	for _, alloc := range allocs {
+		if alloc.JobType == "" && alloc.Job != nil {
+			alloc.JobType = alloc.Job.Type
+		}
+		allocations = append(allocations, alloc.Allocation)
+	}
+	return allocations, nil
+}
+
+// GetNodeDetail retrieves a node with the attributes and placement fields used for scheduling
+func (c *NomadClient) GetNodeDetail(ctx context.Context, nodeID string) (types.NodeDetail, error) {
+	path := fmt.Sprintf("node/%s", nodeID)
+
+	respBody, err := c.makeRequest(ctx, "GET", path, nil, nil)
+	if err != nil {
+		return types.NodeDetail{}, err
+	}
+
+	var node types.NodeDetail
+	if err := json.Unmarshal(respBody, &node); err != nil {
+		return types.NodeDetail{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return node, nil
+}
+
+// GetNodeConnectivity retrieves the status and event history of a node
+func (c *NomadClient) GetNodeConnectivity(ctx context.Context, nodeID string) (types.NodeConnectivity, error) {
+	path := fmt.Sprintf("node/%s", nodeID)
+
+	respBody, err := c.makeRequest(ctx, "GET", path, nil, nil)
+	if err != nil {
+		return types.NodeConnectivity{}, err
+	}
+
+	var node types.NodeConnectivity
+	if err := json.Unmarshal(respBody, &node); err != nil {
+		return types.NodeConnectivity{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return node, nil
+}
+
 // DrainNode enables or disables drain mode for a node
 func (c *NomadClient) DrainNode(ctx context.Context, nodeID string, enable bool, deadline int64) (string, error) {
 	path := fmt.Sprintf("node/%s/drain", nodeID)
 
+	// Nomad expects the deadline as a duration in nanoseconds; negative values force the drain.
+	deadlineNanos := deadline
+	if deadline > 0 {
+		deadlineNanos = int64(time.Duration(deadline) * time.Second)
+	}
+
 	drainSpec := map[string]interface{}{
 		"DrainSpec": map[string]interface{}{
-			"Deadline":         deadline,
+			"Deadline":         deadlineNanos,
 			"IgnoreSystemJobs": false,
 		},
 		"Meta": map[string]string{
@@ -87,23 +158,76 @@ func (c *NomadClient) DrainNode(ctx context.Context, nodeID string, enable bool,
 	return "Node drain disabled", nil
 }
 
-// EligibilityNode sets scheduling eligibility on a node
-func (c *NomadClient) EligibilityNode(ctx context.Context, nodeID string, eligible string) (types.NodeSummary, error) {
+// EligibilityNode sets scheduling eligibility on a node and returns the node's resulting
+// status together with any evaluations Nomad created for the change. Once the change is made
+// it does not fail: when the node cannot be read back, StatusError says why instead.
+func (c *NomadClient) EligibilityNode(ctx context.Context, nodeID string, eligible bool) (types.NodeEligibilityUpdate, error) {
 	path := fmt.Sprintf("node/%s/eligibility", nodeID)
 
+	eligibility := types.NodeSchedulingIneligible
+	if eligible {
+		eligibility = types.NodeSchedulingEligible
+	}
 	eligibilitySpec := map[string]interface{}{
-		"Eligibility": eligible,
+		"NodeID":      nodeID,
+		"Eligibility": eligibility,
 	}
 
 	respBody, err := c.makeRequest(ctx, "POST", path, nil, eligibilitySpec)
 	if err != nil {
-		return types.NodeSummary{}, err
+		return types.NodeEligibilityUpdate{}, err
 	}
 
-	var nodes types.NodeSummary
-	if err := json.Unmarshal(respBody, &nodes); err != nil {
-		return types.NodeSummary{}, fmt.Errorf("error unmarshaling response: %v", err)
+	update := types.NodeEligibilityUpdate{NodeID: nodeID}
+	if err := json.Unmarshal(respBody, &update); err != nil {
+		return types.NodeEligibilityUpdate{}, fmt.Errorf("error unmarshaling response: %v", err)
 	}
 
-	return nodes, nil
+	nodeBody, err := c.makeRequest(ctx, "GET", fmt.Sprintf("node/%s", nodeID), nil, nil)
+	if err != nil {
+		update.StatusError = err.Error()
+		return update, nil
+	}
+	var node struct {
+		Status                string
+		SchedulingEligibility string
+	}
+	if err := json.Unmarshal(nodeBody, &node); err != nil {
+		update.StatusError = fmt.Sprintf("error unmarshaling response: %v", err)
+		return update, nil
+	}
+	update.Status = node.Status
+	update.SchedulingEligibility = node.SchedulingEligibility
+
+	return update, nil
+}
+
+// PurgeNode removes a node and its allocations from the cluster state. A client that is
+// still running registers itself again.
+func (c *NomadClient) PurgeNode(ctx context.Context, nodeID string) (types.NodePurgeResponse, error) {
+	if nodeID == "" {
+		return types.NodePurgeResponse{}, fmt.Errorf("node ID is required")
+	}
+
+	respBody, err := c.makeRequest(ctx, "POST", fmt.Sprintf("node/%s/purge", nodeID), nil, nil)
+	if err != nil {
+		return types.NodePurgeResponse{}, err
+	}
+
+	response := types.NodePurgeResponse{NodeID: nodeID}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return types.NodePurgeResponse{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return response, nil
+}
+
+// GCNode asks a client node to garbage collect its terminal allocations (client/gc)
+func (c *NomadClient) GCNode(ctx context.Context, nodeID string) error {
+	if nodeID == "" {
+		return fmt.Errorf("node ID is required")
+	}
+
+	_, err := c.makeRequest(ctx, "PUT", "client/gc", map[string]string{"node_id": nodeID}, nil)
+	return err
 }
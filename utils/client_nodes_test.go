@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainNode_sendsDeadlineAsDuration(t *testing.T) {
+	var body struct {
+		DrainSpec struct {
+			Deadline int64
+		}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/node/n1/drain" {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	_, err = client.DrainNode(context.Background(), "n1", true, 600)
+	require.NoError(t, err)
+	require.Equal(t, int64(10*time.Minute), body.DrainSpec.Deadline)
+}
+
+func TestListNodeAllocations_readsJobTypeFromEmbeddedJob(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"ID":"a1","Namespace":"prod","JobID":"web","Job":{"Type":"service"}}]`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	allocs, err := client.ListNodeAllocations(context.Background(), "n1")
+	require.NoError(t, err)
+	require.Len(t, allocs, 1)
+	require.Equal(t, "service", allocs[0].JobType)
+	require.Equal(t, "prod", allocs[0].Namespace)
+}
+
+func TestGCNode_targetsNodeThroughClientGC(t *testing.T) {
+	var gotMethod, gotNodeID string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/client/gc" {
+			gotMethod, gotNodeID = r.Method, r.URL.Query().Get("node_id")
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	require.NoError(t, client.GCNode(context.Background(), "n1"))
+	require.Equal(t, http.MethodPut, gotMethod)
+	require.Equal(t, "n1", gotNodeID)
+}
+
+func TestEligibilityNode_reportsTheChangeWhenTheNodeCannotBeReadBack(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/node/n1/eligibility":
+			_, _ = w.Write([]byte(`{"EvalIDs":["e1"],"NodeModifyIndex":12}`))
+		case "/v1/node/n1":
+			http.Error(w, "permission denied", http.StatusForbidden)
+		default:
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	update, err := client.EligibilityNode(context.Background(), "n1", false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"e1"}, update.EvalIDs)
+	require.Empty(t, update.Status)
+	require.Empty(t, update.SchedulingEligibility)
+	require.Contains(t, update.StatusError, "permission denied")
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kocierik/mcp-nomad/types"
+)
+
+// ListQuotas lists the quota specifications (Nomad Enterprise)
+func (c *NomadClient) ListQuotas(ctx context.Context) ([]types.QuotaSpec, error) {
+	respBody, err := c.makeRequest(ctx, "GET", "quotas", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var quotas []types.QuotaSpec
+	if err := json.Unmarshal(respBody, &quotas); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return quotas, nil
+}
+
+// GetQuota retrieves a quota specification by name (Nomad Enterprise)
+func (c *NomadClient) GetQuota(ctx context.Context, name string) (types.QuotaSpec, error) {
+	path := fmt.Sprintf("quota/%s", name)
+
+	respBody, err := c.makeRequest(ctx, "GET", path, nil, nil)
+	if err != nil {
+		return types.QuotaSpec{}, err
+	}
+
+	var quota types.QuotaSpec
+	if err := json.Unmarshal(respBody, &quota); err != nil {
+		return types.QuotaSpec{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return quota, nil
+}
+
+// UpsertQuota creates a quota specification or replaces the one of the same name (Nomad Enterprise)
+func (c *NomadClient) UpsertQuota(ctx context.Context, quota types.QuotaSpec) error {
+	_, err := c.makeRequest(ctx, "POST", "quota", nil, quota)
+	return err
+}
+
+// DeleteQuota deletes a quota specification (Nomad Enterprise)
+func (c *NomadClient) DeleteQuota(ctx context.Context, name string) error {
+	path := fmt.Sprintf("quota/%s", name)
+	_, err := c.makeRequest(ctx, "DELETE", path, nil, nil)
+	return err
+}
+
+// ListQuotaUsages lists the current usage of every quota (Nomad Enterprise)
+func (c *NomadClient) ListQuotaUsages(ctx context.Context) ([]types.QuotaUsage, error) {
+	respBody, err := c.makeRequest(ctx, "GET", "quota-usages", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []types.QuotaUsage
+	if err := json.Unmarshal(respBody, &usages); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return usages, nil
+}
+
+// GetQuotaUsage retrieves the current usage of a quota (Nomad Enterprise)
+func (c *NomadClient) GetQuotaUsage(ctx context.Context, name string) (types.QuotaUsage, error) {
+	path := fmt.Sprintf("quota/usage/%s", name)
+
+	respBody, err := c.makeRequest(ctx, "GET", path, nil, nil)
+	if err != nil {
+		return types.QuotaUsage{}, err
+	}
+
+	var usage types.QuotaUsage
+	if err := json.Unmarshal(respBody, &usage); err != nil {
+		return types.QuotaUsage{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return usage, nil
+}
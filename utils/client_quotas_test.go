@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotas_upsertAndReadUsage(t *testing.T) {
+	var upserted types.QuotaSpec
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/quota":
+			require.Equal(t, http.MethodPost, r.Method)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&upserted))
+		case "/v1/quota/usage/team-a":
+			_, _ = w.Write([]byte(`{"Name":"team-a","Used":{"aGFzaA==":{"Region":"global","RegionLimit":{"CPU":1200,"MemoryMB":512},"Hash":"aGFzaA=="}}}`))
+		default:
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	variables := 10
+	quota := types.QuotaSpec{Name: "team-a", Limits: []types.QuotaLimit{{
+		Region:         "global",
+		RegionLimit:    &types.QuotaResources{CPU: 4000, MemoryMB: -1},
+		VariablesLimit: &variables,
+	}}}
+	require.NoError(t, client.UpsertQuota(context.Background(), quota))
+	require.Equal(t, quota, upserted)
+
+	usage, err := client.GetQuotaUsage(context.Background(), "team-a")
+	require.NoError(t, err)
+	require.Equal(t, "team-a", usage.Name)
+	require.Equal(t, 1200, usage.Used["aGFzaA=="].RegionLimit.CPU)
+	require.Equal(t, []byte("hash"), usage.Used["aGFzaA=="].Hash)
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kocierik/mcp-nomad/types"
+)
+
+// ListScalingPolicies lists scaling policies, optionally only those of one job or of one type
+// (horizontal, or vertical_cpu / vertical_mem with Nomad Enterprise)
+func (c *NomadClient) ListScalingPolicies(ctx context.Context, namespace, jobID, policyType string) ([]types.ScalingPolicyListStub, error) {
+	queryParams := make(map[string]string)
+	AddNomadNamespaceQuery(queryParams, namespace)
+	if jobID != "" {
+		queryParams["job"] = jobID
+	}
+	if policyType != "" {
+		queryParams["type"] = policyType
+	}
+
+	respBody, err := c.makeRequest(ctx, "GET", "scaling/policies", queryParams, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []types.ScalingPolicyListStub
+	if err := json.Unmarshal(respBody, &policies); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return policies, nil
+}
+
+// GetScalingPolicy retrieves a scaling policy by ID
+func (c *NomadClient) GetScalingPolicy(ctx context.Context, policyID string) (types.ScalingPolicy, error) {
+	path := fmt.Sprintf("scaling/policy/%s", policyID)
+
+	respBody, err := c.makeRequest(ctx, "GET", path, nil, nil)
+	if err != nil {
+		return types.ScalingPolicy{}, err
+	}
+
+	var policy types.ScalingPolicy
+	if err := json.Unmarshal(respBody, &policy); err != nil {
+		return types.ScalingPolicy{}, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return policy, nil
+}
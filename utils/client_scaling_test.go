@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListScalingPolicies_passesFilters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/scaling/policies" {
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+			return
+		}
+		require.Equal(t, "prod", r.URL.Query().Get("namespace"))
+		require.Equal(t, "web", r.URL.Query().Get("job"))
+		require.Equal(t, "horizontal", r.URL.Query().Get("type"))
+		_, _ = w.Write([]byte(`[{"ID":"p1","Enabled":true,"Type":"horizontal","Target":{"Namespace":"prod","Job":"web","Group":"web"}}]`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	policies, err := client.ListScalingPolicies(context.Background(), "prod", "web", "horizontal")
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	require.Equal(t, "web", policies[0].Target["Group"])
+	require.True(t, policies[0].Enabled)
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/kocierik/mcp-nomad/types"
+)
+
+// ListServices lists the services registered with Nomad service discovery, grouped by
+// namespace
+func (c *NomadClient) ListServices(ctx context.Context, namespace string) ([]types.ServiceRegistrationListStub, error) {
+	queryParams := make(map[string]string)
+	AddNomadNamespaceQuery(queryParams, namespace)
+
+	respBody, err := c.makeRequest(ctx, "GET", "services", queryParams, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var services []types.ServiceRegistrationListStub
+	if err := json.Unmarshal(respBody, &services); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return services, nil
+}
+
+// GetService retrieves the registered instances of a service
+func (c *NomadClient) GetService(ctx context.Context, name, namespace string) ([]types.ServiceRegistration, error) {
+	path := fmt.Sprintf("service/%s", url.PathEscape(name))
+
+	queryParams := make(map[string]string)
+	AddNomadNamespaceQuery(queryParams, namespace)
+
+	respBody, err := c.makeRequest(ctx, "GET", path, queryParams, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var registrations []types.ServiceRegistration
+	if err := json.Unmarshal(respBody, &registrations); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return registrations, nil
+}
+
+// DeleteService removes one registration of a service
+func (c *NomadClient) DeleteService(ctx context.Context, name, id, namespace string) error {
+	path := fmt.Sprintf("service/%s/%s", url.PathEscape(name), url.PathEscape(id))
+
+	queryParams := make(map[string]string)
+	AddNomadNamespaceQuery(queryParams, namespace)
+
+	_, err := c.makeRequest(ctx, "DELETE", path, queryParams, nil)
+	return err
+}
+
+// GetAllocationChecks retrieves the latest results of the Nomad service checks of an
+// allocation, keyed by check ID
+func (c *NomadClient) GetAllocationChecks(ctx context.Context, allocID string) (map[string]types.AllocCheckStatus, error) {
+	path := fmt.Sprintf("client/allocation/%s/checks", allocID)
+
+	respBody, err := c.makeRequest(ctx, "GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var checks map[string]types.AllocCheckStatus
+	if err := json.Unmarshal(respBody, &checks); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %v", err)
+	}
+
+	return checks, nil
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetService_decodesRegistrations(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/service/web" {
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+			return
+		}
+		require.Equal(t, "prod", r.URL.Query().Get("namespace"))
+		_, _ = w.Write([]byte(`[{"ID":"_nomad-task-1","ServiceName":"web","Namespace":"prod","NodeID":"n1","AllocID":"a1","JobID":"web","Address":"10.0.0.5","Port":23456}]`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	registrations, err := client.GetService(context.Background(), "web", "prod")
+	require.NoError(t, err)
+	require.Len(t, registrations, 1)
+	require.Equal(t, "a1", registrations[0].AllocID)
+	require.Equal(t, 23456, registrations[0].Port)
+}
+
+func TestDeleteService_deletesOneRegistration(t *testing.T) {
+	var method, path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			method, path = r.Method, r.URL.Path
+		}
+		_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	require.NoError(t, client.DeleteService(context.Background(), "web", "_nomad-task-1", ""))
+	require.Equal(t, http.MethodDelete, method)
+	require.Equal(t, "/v1/service/web/_nomad-task-1", path)
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// SaveSnapshot streams a snapshot of the cluster state (a gzipped archive) to w and returns
+// its size. With stale any server may answer instead of only the leader.
+func (c *NomadClient) SaveSnapshot(ctx context.Context, w io.Writer, stale bool) (int64, error) {
+	query := url.Values{}
+	if stale {
+		query.Set("stale", "true")
+	}
+
+	body, err := c.doStream(ctx, "operator/snapshot", query)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return n, fmt.Errorf("error reading snapshot: %w", err)
+	}
+	return n, nil
+}
+
+// RestoreSnapshot replaces the cluster state with the snapshot read from r. Like the
+// streaming methods it is not bounded by the client timeout; cancel ctx to abort.
+func (c *NomadClient) RestoreSnapshot(ctx context.Context, r io.Reader) error {
+	req, err := c.newRequest(ctx, "PUT", "operator/snapshot", url.Values{}, r)
+	if err != nil {
+		return err
+	}
+
+	uploadClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := uploadClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respReader, err := decodedBody(resp)
+		if err != nil {
+			return fmt.Errorf("error decoding response body: %w", err)
+		}
+		respBody, _ := io.ReadAll(io.LimitReader(respReader, MaxNomadHTTPErrorBodyBytes+1))
+		httpErr := NewNomadHTTPError(resp.StatusCode, "PUT", "operator/snapshot", respBody)
+		httpErr.RequestID = RequestIDFromContext(ctx)
+		return httpErr
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot_streamsBinaryBodies(t *testing.T) {
+	snapshot := []byte{0x1f, 0x8b, 0x08, 0x00, 0xff, 0x00, 0x10}
+	var restored []byte
+	var restoredType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/operator/snapshot" {
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			require.Equal(t, "true", r.URL.Query().Get("stale"))
+			w.Header().Set("Content-Type", "application/x-gzip")
+			_, _ = w.Write(snapshot)
+		case http.MethodPut:
+			restoredType = r.Header.Get("Content-Type")
+			restored, _ = io.ReadAll(r.Body)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	var saved bytes.Buffer
+	n, err := client.SaveSnapshot(context.Background(), &saved, true)
+	require.NoError(t, err)
+	require.EqualValues(t, len(snapshot), n)
+	require.Equal(t, snapshot, saved.Bytes())
+
+	require.NoError(t, client.RestoreSnapshot(context.Background(), bytes.NewReader(snapshot)))
+	require.Equal(t, snapshot, restored)
+	require.Equal(t, "application/octet-stream", restoredType)
+}
+
+func TestRestoreSnapshot_returnsHTTPErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/operator/snapshot" {
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+			return
+		}
+		http.Error(w, "Permission denied", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	err = client.RestoreSnapshot(context.Background(), bytes.NewReader([]byte("snap")))
+	require.ErrorContains(t, err, "HTTP 403")
+}
@@ -61,7 +61,12 @@ func (c *NomadClient) GetVariable(ctx context.Context, path, namespace string) (
 	return variable, nil
 }
 
-// CreateVariable creates a new variable
+// VariableCASMustNotExist passed as the cas of CreateVariable makes the write fail when a
+// variable already exists at the path.
+const VariableCASMustNotExist = -1
+
+// CreateVariable creates a new variable, or updates it. A positive cas only succeeds when it
+// equals the variable's current ModifyIndex.
 func (c *NomadClient) CreateVariable(ctx context.Context, variable types.Variable, namespace string, cas int, lockOperation string) error {
 	apiPath := fmt.Sprintf("var/%s", variable.Path)
 
@@ -71,11 +76,6 @@ func (c *NomadClient) CreateVariable(ctx context.Context, variable types.Variabl
 		return fmt.Errorf("failed to parse variable value: %v", err)
 	}
 
-	// Add CAS if provided
-	if cas > 0 {
-		requestBody["CAS"] = cas
-	}
-
 	// Add lock operation if provided
 	if lockOperation != "" {
 		requestBody["LockOperation"] = lockOperation
@@ -85,6 +85,14 @@ func (c *NomadClient) CreateVariable(ctx context.Context, variable types.Variabl
 	queryParams := make(map[string]string)
 	AddNomadNamespaceQuery(queryParams, namespace)
 
+	// Nomad checks the index passed in the cas query parameter; 0 requires a new variable.
+	switch {
+	case cas > 0:
+		queryParams["cas"] = strconv.Itoa(cas)
+	case cas == VariableCASMustNotExist:
+		queryParams["cas"] = "0"
+	}
+
 	_, err := c.makeRequest(ctx, "PUT", apiPath, queryParams, requestBody)
 	return err
 }
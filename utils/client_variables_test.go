@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kocierik/mcp-nomad/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateVariable_sendsCASAsQueryParameter(t *testing.T) {
+	var casValues []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/var/app/config" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		require.Equal(t, http.MethodPut, r.Method)
+		body, _ := io.ReadAll(r.Body)
+		require.NotContains(t, string(body), "cas")
+		_, hasCAS := r.URL.Query()["cas"]
+		if !hasCAS {
+			casValues = append(casValues, "none")
+		} else {
+			casValues = append(casValues, r.URL.Query().Get("cas"))
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	variable := types.Variable{Path: "app/config", Value: `{"Items":{"k":"v"}}`}
+	for _, cas := range []int{0, 42, VariableCASMustNotExist} {
+		require.NoError(t, client.CreateVariable(context.Background(), variable, "", cas, ""))
+	}
+	require.Equal(t, []string{"none", "42", "0"}, casValues)
+}
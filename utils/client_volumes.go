@@ -2,6 +2,8 @@ package utils
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -37,6 +39,35 @@ func (c *NomadClient) ListVolumes(ctx context.Context, nodeID string, pluginID s
 	return volumes, nil
 }
 
+// ListNamespaceVolumes lists the CSI and host volumes registered in a namespace. Clusters
+// without dynamic host volumes (before Nomad 1.10) reject type=host; they only report CSI volumes.
+func (c *NomadClient) ListNamespaceVolumes(ctx context.Context, namespace string) ([]types.VolumeStub, error) {
+	var volumes []types.VolumeStub
+	for _, volumeType := range []string{"csi", "host"} {
+		queryParams := map[string]string{"type": volumeType}
+		AddNomadNamespaceQuery(queryParams, namespace)
+
+		respBody, err := c.makeRequest(ctx, "GET", "volumes", queryParams, nil)
+		var httpErr *NomadHTTPError
+		if volumeType == "host" && errors.As(err, &httpErr) && (httpErr.StatusCode == 400 || httpErr.StatusCode == 404) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var stubs []types.VolumeStub
+		if err := json.Unmarshal(respBody, &stubs); err != nil {
+			return nil, fmt.Errorf("error unmarshaling response: %v", err)
+		}
+		for i := range stubs {
+			stubs[i].Type = volumeType
+		}
+		volumes = append(volumes, stubs...)
+	}
+	return volumes, nil
+}
+
 // GetVolume retrieves a specific host volume
 func (c *NomadClient) GetVolume(ctx context.Context, volumeID string) (*types.Volume, error) {
 	path := fmt.Sprintf("/v1/volume/host/%s", volumeID)
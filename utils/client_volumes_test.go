@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListNamespaceVolumes_toleratesMissingHostVolumeAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/volumes" {
+			_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+			return
+		}
+		require.Equal(t, "team-a", r.URL.Query().Get("namespace"))
+		if r.URL.Query().Get("type") == "host" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("unsupported volume type"))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"ID":"data","Name":"data","Namespace":"team-a","PluginID":"ebs"}]`))
+	}))
+	defer srv.Close()
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	volumes, err := client.ListNamespaceVolumes(context.Background(), "team-a")
+	require.NoError(t, err)
+	require.Len(t, volumes, 1)
+	require.Equal(t, "csi", volumes[0].Type)
+}
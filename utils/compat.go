@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// versionRetryInterval is how long a failed Nomad version detection is remembered before
+// the next version-gated request tries again.
+const versionRetryInterval = time.Minute
+
+// apiFeature is a part of the Nomad HTTP API that older Nomad versions do not serve.
+type apiFeature struct {
+	Name       string
+	MinVersion string
+	matches    func(path string) bool
+}
+
+// apiFeatures are the version-gated endpoints the client calls. Requests for them are
+// refused with an UnsupportedFeatureError when the cluster is known to be too old, instead
+// of surfacing Nomad's bare 404.
+var apiFeatures = []apiFeature{
+	{Name: "the event stream", MinVersion: "1.0.0", matches: pathPrefix("event/stream")},
+	{Name: "job services", MinVersion: "1.3.0", matches: jobSubresource("services")},
+	{Name: "service registrations", MinVersion: "1.3.0", matches: pathPrefix("services", "service/")},
+	{Name: "allocation checks", MinVersion: "1.4.0", matches: clientAllocationSubresource("checks")},
+	{Name: "variables", MinVersion: "1.4.0", matches: pathPrefix("vars", "var/")},
+	{Name: "ACL roles", MinVersion: "1.4.0", matches: pathPrefix("acl/roles", "acl/role")},
+	{Name: "ACL auth methods", MinVersion: "1.5.0", matches: pathPrefix("acl/auth-method")},
+	{Name: "ACL binding rules", MinVersion: "1.5.0", matches: pathPrefix("acl/binding-rule")},
+	{Name: "job submissions", MinVersion: "1.6.0", matches: jobSubresource("submission")},
+	{Name: "node pools", MinVersion: "1.6.0", matches: pathPrefix("node/pools", "node/pool/")},
+	{Name: "Raft leadership transfer", MinVersion: "1.7.0", matches: pathPrefix("operator/raft/transfer-leadership")},
+	{Name: "dynamic host volumes", MinVersion: "1.10.0", matches: pathPrefix("volume/host")},
+}
+
+// pathPrefix matches paths equal to or below one of prefixes.
+func pathPrefix(prefixes ...string) func(string) bool {
+	return func(path string) bool {
+		for _, prefix := range prefixes {
+			if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// jobSubresource matches job/<id>/<name>.
+func jobSubresource(name string) func(string) bool {
+	return func(path string) bool {
+		parts := strings.Split(path, "/")
+		return len(parts) == 3 && parts[0] == "job" && parts[2] == name
+	}
+}
+
+// clientAllocationSubresource matches client/allocation/<id>/<name>.
+func clientAllocationSubresource(name string) func(string) bool {
+	return func(path string) bool {
+		parts := strings.Split(path, "/")
+		return len(parts) == 4 && parts[0] == "client" && parts[1] == "allocation" && parts[3] == name
+	}
+}
+
+// UnsupportedFeatureError is returned for a request the connected Nomad version cannot
+// serve.
+type UnsupportedFeatureError struct {
+	Feature        string
+	MinVersion     string
+	ClusterVersion string
+}
+
+func (e *UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("Nomad %s does not support %s (added in Nomad %s)", e.ClusterVersion, e.Feature, e.MinVersion)
+}
+
+// apiCompat caches the Nomad version used for compatibility checks.
+type apiCompat struct {
+	mu       sync.Mutex
+	version  string
+	pinned   bool
+	failedAt time.Time
+	// fetching is closed when the detection in flight ends; nil when none is
+	fetching chan struct{}
+	// generation changes when the version is pinned or the address changes, so a
+	// detection started before does not store its result
+	generation uint64
+}
+
+// reset forgets a detected version; c.mu must be held.
+func (c *apiCompat) reset() {
+	if !c.pinned {
+		c.version = ""
+	}
+	c.failedAt = time.Time{}
+	c.generation++
+}
+
+// PinNomadVersion makes the client assume version for API compatibility checks instead of
+// asking the agent, e.g. when the token may not read agent/self. An empty version restores
+// detection.
+func (c *NomadClient) PinNomadVersion(version string) error {
+	if version != "" {
+		if _, ok := ParseVersion(version); !ok {
+			return fmt.Errorf("invalid Nomad version %q", version)
+		}
+	}
+	c.compat.mu.Lock()
+	defer c.compat.mu.Unlock()
+	c.compat.version = version
+	c.compat.pinned = version != ""
+	c.compat.reset()
+	return nil
+}
+
+// clusterVersion returns the pinned or detected Nomad version, or "" when it is unknown.
+// Detection runs once, without holding the lock, and concurrent callers wait for it; a
+// failure is retried after versionRetryInterval.
+func (c *NomadClient) clusterVersion(ctx context.Context) string {
+	c.compat.mu.Lock()
+	for c.compat.fetching != nil {
+		fetching := c.compat.fetching
+		c.compat.mu.Unlock()
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return ""
+		}
+		c.compat.mu.Lock()
+	}
+	if c.compat.version != "" || time.Since(c.compat.failedAt) < versionRetryInterval {
+		defer c.compat.mu.Unlock()
+		return c.compat.version
+	}
+	fetching, generation := make(chan struct{}), c.compat.generation
+	c.compat.fetching = fetching
+	c.compat.mu.Unlock()
+
+	version, err := c.GetNomadVersion(ctx)
+
+	c.compat.mu.Lock()
+	defer c.compat.mu.Unlock()
+	c.compat.fetching = nil
+	close(fetching)
+	if c.compat.generation != generation {
+		return c.compat.version
+	}
+	if err != nil {
+		c.compat.failedAt = time.Now()
+		return ""
+	}
+	c.compat.version = version
+	return version
+}
+
+// checkCompatibility refuses a request for an API feature the cluster's Nomad version does
+// not have. Requests pass when the version cannot be determined.
+func (c *NomadClient) checkCompatibility(ctx context.Context, path string) error {
+	path, _, _ = strings.Cut(normalizeAPIPath(path), "?")
+	for _, feature := range apiFeatures {
+		if !feature.matches(path) {
+			continue
+		}
+		version := c.clusterVersion(ctx)
+		have, ok := ParseVersion(version)
+		if !ok {
+			return nil
+		}
+		want, _ := ParseVersion(feature.MinVersion)
+		if CompareVersions(have, want) < 0 {
+			return &UnsupportedFeatureError{Feature: feature.Name, MinVersion: feature.MinVersion, ClusterVersion: version}
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newCompatTestServer(t *testing.T, version string, varsCalls *atomic.Int32) *NomadClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/agent/self":
+			_, _ = w.Write([]byte(`{"config":{"Version":{"Version":"` + version + `"}}}`))
+		case "/v1/vars":
+			varsCalls.Add(1)
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+	return client
+}
+
+func TestCheckCompatibility_refusesFeaturesNewerThanCluster(t *testing.T) {
+	var varsCalls atomic.Int32
+	client := newCompatTestServer(t, "1.3.5", &varsCalls)
+
+	_, err := client.ListVariables(context.Background(), "default", "", "", 0, "")
+	var unsupported *UnsupportedFeatureError
+	require.True(t, errors.As(err, &unsupported), "got %v", err)
+	require.Equal(t, "variables", unsupported.Feature)
+	require.Equal(t, "Nomad 1.3.5 does not support variables (added in Nomad 1.4.0)", err.Error())
+	require.Zero(t, varsCalls.Load())
+}
+
+func TestCheckCompatibility_allowsSupportedAndPinnedVersions(t *testing.T) {
+	var varsCalls atomic.Int32
+	client := newCompatTestServer(t, "1.3.5", &varsCalls)
+	require.NoError(t, client.PinNomadVersion("1.7.2"))
+
+	_, err := client.ListVariables(context.Background(), "default", "", "", 0, "")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, varsCalls.Load())
+
+	require.Error(t, client.PinNomadVersion("latest"))
+}
+
+func TestCheckCompatibility_passesWhenVersionIsUnknown(t *testing.T) {
+	var varsCalls atomic.Int32
+	client := newCompatTestServer(t, "", &varsCalls)
+
+	_, err := client.ListVariables(context.Background(), "default", "", "", 0, "")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, varsCalls.Load())
+}
+
+func TestCheckCompatibility_matchesJobSubresources(t *testing.T) {
+	client := &NomadClient{}
+	require.NoError(t, client.PinNomadVersion("1.5.0"))
+
+	err := client.checkCompatibility(context.Background(), "/v1/job/web/submission?version=2")
+	require.ErrorContains(t, err, "job submissions")
+	require.NoError(t, client.checkCompatibility(context.Background(), "job/submission"))
+	require.NoError(t, client.checkCompatibility(context.Background(), "varsity"))
+}
+
+func TestClusterVersion_doesNotHoldTheLockWhileDetecting(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	var selfCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/agent/self" {
+			_, _ = w.Write([]byte(`"10.0.0.1:4647"`))
+			return
+		}
+		if selfCalls.Add(1) == 1 {
+			close(entered)
+		}
+		<-release
+		_, _ = w.Write([]byte(`{"config":{"Version":{"Version":"1.3.5"}}}`))
+	}))
+	t.Cleanup(srv.Close)
+	client, err := NewNomadClient(srv.URL, "")
+	require.NoError(t, err)
+
+	detected := make(chan string, 2)
+	for range 2 {
+		go func() { detected <- client.clusterVersion(context.Background()) }()
+	}
+	<-entered
+
+	pinned := make(chan error)
+	go func() { pinned <- client.PinNomadVersion("1.9.0") }()
+	select {
+	case err := <-pinned:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		close(release)
+		t.Fatal("PinNomadVersion waited for the agent/self request")
+	}
+
+	close(release)
+	require.Equal(t, "1.9.0", <-detected)
+	require.Equal(t, "1.9.0", <-detected)
+	require.EqualValues(t, 1, selfCalls.Load())
+	require.Equal(t, "1.9.0", client.clusterVersion(context.Background()))
+}
@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"unicode/utf8"
 )
@@ -15,6 +17,7 @@ type NomadHTTPError struct {
 	StatusCode int
 	Method     string
 	Path       string // API path fragment under /v1/ (normalized).
+	RequestID  string // MCP request ID sent as X-Request-ID, when the call came from a tool.
 	body       []byte // truncated UTF-8 or raw capped bytes before validation
 	truncated  bool   // original response exceeded MaxNomadHTTPErrorBodyBytes
 }
@@ -41,11 +44,14 @@ func (e *NomadHTTPError) Error() string {
 	if e == nil {
 		return ""
 	}
-	snip := sanitizeErrorBodySnippet(e.body, e.truncated)
-	if snip == "" {
-		return fmt.Sprintf("nomad API error %s %s: HTTP %d", e.Method, e.Path, e.StatusCode)
+	msg := fmt.Sprintf("nomad API error %s %s: HTTP %d", e.Method, e.Path, e.StatusCode)
+	if snip := sanitizeErrorBodySnippet(e.body, e.truncated); snip != "" {
+		msg = fmt.Sprintf("%s (%s)", msg, snip)
 	}
-	return fmt.Sprintf("nomad API error %s %s: HTTP %d (%s)", e.Method, e.Path, e.StatusCode, snip)
+	if e.RequestID != "" {
+		msg = fmt.Sprintf("%s [request_id=%s]", msg, e.RequestID)
+	}
+	return msg
 }
 
 // Status returns the HTTP status code from Nomad (e.g. 404).
@@ -64,6 +70,16 @@ func (e *NomadHTTPError) Snippet() string {
 	return sanitizeErrorBodySnippet(e.body, e.truncated)
 }
 
+// IsEnterpriseOnly reports whether err is Nomad refusing an Enterprise-only endpoint on a
+// community edition cluster (HTTP 501, "Nomad Enterprise only endpoint").
+func IsEnterpriseOnly(err error) bool {
+	var httpErr *NomadHTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	return httpErr.StatusCode == http.StatusNotImplemented || strings.Contains(httpErr.Snippet(), "Enterprise only")
+}
+
 func sanitizeErrorBodySnippet(b []byte, truncated bool) string {
 	if len(b) == 0 {
 		if truncated {
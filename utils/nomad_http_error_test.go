@@ -2,6 +2,7 @@ package utils
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -39,3 +40,16 @@ func TestCanonicalAuthorizationBearer(t *testing.T) {
 	require.Equal(t, "", CanonicalAuthorizationBearer("   "))
 	require.Equal(t, "Basic xxx", CanonicalAuthorizationBearer("Basic xxx"))
 }
+
+func TestNomadHTTPError_includesRequestID(t *testing.T) {
+	err := NewNomadHTTPError(403, "GET", "jobs", []byte("Permission denied"))
+	err.RequestID = "abc123"
+	require.EqualError(t, err, "nomad API error GET jobs: HTTP 403 (Permission denied) [request_id=abc123]")
+}
+
+func TestIsEnterpriseOnly(t *testing.T) {
+	require.True(t, IsEnterpriseOnly(NewNomadHTTPError(501, "GET", "operator/license", []byte("Nomad Enterprise only endpoint"))))
+	require.True(t, IsEnterpriseOnly(fmt.Errorf("wrapped: %w", NewNomadHTTPError(400, "GET", "quotas", []byte("Nomad Enterprise only endpoint")))))
+	require.False(t, IsEnterpriseOnly(NewNomadHTTPError(403, "GET", "operator/license", []byte("Permission denied"))))
+	require.False(t, IsEnterpriseOnly(errors.New("Nomad Enterprise only endpoint")))
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"context"
+	"io"
 
 	"github.com/kocierik/mcp-nomad/types"
 )
@@ -15,43 +16,113 @@ type RawNomadCaller interface {
 
 var _ RawNomadCaller = (*NomadClient)(nil)
 
-// NomadACLTokenBootstrapper resets the in-memory client token after ACL bootstrap (tools/acl.go).
+// NomadACLTokenBootstrapper switches the in-memory client token after ACL bootstrap and
+// back on rollback (tools/acl_bootstrap.go).
 type NomadACLTokenBootstrapper interface {
 	SetToken(token string)
+	GetToken() string
 }
 
 var _ NomadACLTokenBootstrapper = (*NomadClient)(nil)
 
 // JobAPI is implemented by NomadClient and used by job-related MCP tools plus dynamic resources.
 type JobAPI interface {
+	RegionAPI
 	ListJobs(ctx context.Context, namespace, status string) ([]types.JobSummary, error)
 	GetJob(ctx context.Context, jobID, namespace string) (types.Job, error)
-	RunJob(ctx context.Context, jobSpec string, detach bool) (map[string]interface{}, error)
-	StopJob(ctx context.Context, jobID, namespace string, purge bool) (map[string]interface{}, error)
-	ScaleTaskGroup(ctx context.Context, jobID, group string, count int, namespace string) error
+	GetJobDefinition(ctx context.Context, jobID, namespace string) (map[string]interface{}, error)
+	PlanJob(ctx context.Context, job map[string]interface{}, namespace string) (types.JobPlan, error)
+	ParseJobSpec(ctx context.Context, jobSpec string) (map[string]interface{}, error)
+	CanonicalizeJobSpec(ctx context.Context, jobSpec string) (map[string]interface{}, error)
+	RunJob(ctx context.Context, jobSpec, namespace string, detach bool) (types.JobRegisterResponse, error)
+	EnforceRunJob(ctx context.Context, jobSpec, namespace string, detach bool, jobModifyIndex int) (types.JobRegisterResponse, error)
+	StopJob(ctx context.Context, jobID, namespace string, opts types.JobStopOptions) (types.JobDeregisterResponse, error)
+	CreateJobEvaluation(ctx context.Context, jobID, namespace string, forceReschedule bool) (types.JobRegisterResponse, error)
+	DispatchJob(ctx context.Context, jobID, namespace string, opts types.JobDispatchOptions) (types.JobDispatchResponse, error)
+	ForceNewPeriodicInstance(ctx context.Context, jobID, namespace string) (types.PeriodicForceResponse, error)
+	RevertJob(ctx context.Context, jobID, namespace string, version int, opts types.JobRevertOptions) (types.JobRegisterResponse, error)
+	ScaleTaskGroup(ctx context.Context, jobID, group string, count int, namespace string) (types.JobRegisterResponse, error)
 	ListJobAllocations(ctx context.Context, jobID, namespace string) ([]types.Allocation, error)
 	ListJobEvaluations(ctx context.Context, jobID, namespace string) ([]types.Evaluation, error)
 	ListJobDeployments(ctx context.Context, jobID, namespace string) ([]types.JobDeployment, error)
+	GetJobDeployment(ctx context.Context, jobID, namespace string) (types.JobDeployment, error)
 	GetJobSummary(ctx context.Context, jobID, namespace string) (types.JobSummary, error)
-	ListJobServices(ctx context.Context, jobID, namespace string) ([]types.Service, error)
+	ListJobServices(ctx context.Context, jobID, namespace string) ([]types.ServiceRegistration, error)
 	GetJobVersions(ctx context.Context, jobID, namespace string) ([]types.Job, error)
+	GetJobVersionDiffs(ctx context.Context, jobID, namespace string) (types.JobVersionsResponse, error)
+	GetJobVersionSpec(ctx context.Context, jobID, namespace string, version int) (map[string]interface{}, error)
+	GetJobSubmission(ctx context.Context, jobID, namespace string, version int) (types.JobSubmission, error)
 }
 
 var _ JobAPI = (*NomadClient)(nil)
 
 // NodeAPI backs node MCP tools (and helpers that inspect node payloads in resources).
 type NodeAPI interface {
+	RegionAPI
 	ListNodes(ctx context.Context, status string) ([]types.NodeSummary, error)
 	GetNode(ctx context.Context, nodeID string) (types.Node, error)
+	GetNodeDetail(ctx context.Context, nodeID string) (types.NodeDetail, error)
+	ListNodeAllocations(ctx context.Context, nodeID string) ([]types.Allocation, error)
 	DrainNode(ctx context.Context, nodeID string, enable bool, deadline int64) (string, error)
-	EligibilityNode(ctx context.Context, nodeID string, eligible string) (types.NodeSummary, error)
+	EligibilityNode(ctx context.Context, nodeID string, eligible bool) (types.NodeEligibilityUpdate, error)
+	PurgeNode(ctx context.Context, nodeID string) (types.NodePurgeResponse, error)
+	GCNode(ctx context.Context, nodeID string) error
 }
 
 var _ NodeAPI = (*NomadClient)(nil)
 
+// NodeConnectivityAPI backs the node heartbeat diagnostics; server agents report the
+// heartbeat settings in their configuration.
+type NodeConnectivityAPI interface {
+	ListNodes(ctx context.Context, status string) ([]types.NodeSummary, error)
+	GetNodeConnectivity(ctx context.Context, nodeID string) (types.NodeConnectivity, error)
+	GetAgentSelf(ctx context.Context) (types.AgentSelf, error)
+}
+
+var _ NodeConnectivityAPI = (*NomadClient)(nil)
+
+// NodePoolAPI backs node pool tools (Nomad 1.6+).
+type NodePoolAPI interface {
+	ListNodePools(ctx context.Context, prefix string) ([]types.NodePool, error)
+	GetNodePool(ctx context.Context, name string) (types.NodePool, error)
+	UpsertNodePool(ctx context.Context, pool types.NodePool) error
+	DeleteNodePool(ctx context.Context, name string) error
+	ListNodePoolNodes(ctx context.Context, name string) ([]types.NodeListStub, error)
+}
+
+var _ NodePoolAPI = (*NomadClient)(nil)
+
+// PlacementAPI backs tools that evaluate jobs against cluster nodes.
+type PlacementAPI interface {
+	JobAPI
+	NodeAPI
+}
+
+var _ PlacementAPI = (*NomadClient)(nil)
+
+// PrioritySimulationAPI backs the priority change simulator: it plans a job at another
+// priority and reads the scheduler's preemption settings.
+type PrioritySimulationAPI interface {
+	GetJob(ctx context.Context, jobID, namespace string) (types.Job, error)
+	GetJobDefinition(ctx context.Context, jobID, namespace string) (map[string]interface{}, error)
+	PlanJob(ctx context.Context, job map[string]interface{}, namespace string) (types.JobPlan, error)
+	GetSchedulerConfig(ctx context.Context) (types.SchedulerConfiguration, error)
+}
+
+var _ PrioritySimulationAPI = (*NomadClient)(nil)
+
+// NodeToolsDeps backs node tools; the drain preflight inspects the jobs running on a node.
+type NodeToolsDeps interface {
+	NodeAPI
+	JobAPI
+}
+
+var _ NodeToolsDeps = (*NomadClient)(nil)
+
 // NamespaceAPI backs namespace tools.
 type NamespaceAPI interface {
 	ListNamespaces(ctx context.Context) ([]types.Namespace, error)
+	GetNamespace(ctx context.Context, name string) (types.Namespace, error)
 	CreateNamespace(ctx context.Context, namespace types.Namespace) error
 	DeleteNamespace(ctx context.Context, name string) error
 }
@@ -66,11 +137,66 @@ type DeploymentAPI interface {
 
 var _ DeploymentAPI = (*NomadClient)(nil)
 
+// DeploymentControlAPI backs the tools that drive a deployment (promote, fail, pause, resume).
+type DeploymentControlAPI interface {
+	PromoteDeployment(ctx context.Context, deploymentID, namespace string, groups []string) (types.DeploymentUpdateResponse, error)
+	FailDeployment(ctx context.Context, deploymentID, namespace string) (types.DeploymentUpdateResponse, error)
+	PauseDeployment(ctx context.Context, deploymentID, namespace string, pause bool) (types.DeploymentUpdateResponse, error)
+}
+
+var _ DeploymentControlAPI = (*NomadClient)(nil)
+
+// EvaluationAPI backs cluster-wide evaluation MCP tools.
+type EvaluationAPI interface {
+	ListEvaluations(ctx context.Context, namespace, status, jobID string) ([]types.Evaluation, error)
+	FilterEvaluations(ctx context.Context, namespace string, filter types.EvaluationFilter) ([]types.Evaluation, error)
+	GetEvaluation(ctx context.Context, evalID string) (types.Evaluation, error)
+	ListEvaluationAllocations(ctx context.Context, evalID string) ([]types.Allocation, error)
+	DeleteEvaluations(ctx context.Context, evalIDs []string) error
+}
+
+var _ EvaluationAPI = (*NomadClient)(nil)
+
+// DeploymentToolsDeps backs deployment tools; evaluations map since windows to Raft indexes.
+type DeploymentToolsDeps interface {
+	DeploymentAPI
+	DeploymentControlAPI
+	EvaluationAPI
+}
+
+var _ DeploymentToolsDeps = (*NomadClient)(nil)
+
+// EventAPI backs tools and resources that read the Nomad event stream.
+type EventAPI interface {
+	StreamEvents(ctx context.Context, request types.EventStreamRequest, handle func(types.EventFrame) error) error
+}
+
+var _ EventAPI = (*NomadClient)(nil)
+
+// DiagnosticsAPI backs connection troubleshooting tools.
+type DiagnosticsAPI interface {
+	DiagnoseConnection(ctx context.Context) (types.ConnectionDiagnosis, error)
+}
+
+var _ DiagnosticsAPI = (*NomadClient)(nil)
+
+// ServiceAPI backs the Nomad service discovery tools; check results are read from the
+// client running each instance.
+type ServiceAPI interface {
+	ListServices(ctx context.Context, namespace string) ([]types.ServiceRegistrationListStub, error)
+	GetService(ctx context.Context, name, namespace string) ([]types.ServiceRegistration, error)
+	DeleteService(ctx context.Context, name, id, namespace string) error
+	GetAllocationChecks(ctx context.Context, allocID string) (map[string]types.AllocCheckStatus, error)
+}
+
+var _ ServiceAPI = (*NomadClient)(nil)
+
 // VolumeAPI backs CSI/host volume MCP tools currently exposed via MCP.
 type VolumeAPI interface {
 	ListVolumes(ctx context.Context, nodeID string, pluginID string, nextToken string, perPage int, filter string) ([]types.Volume, error)
 	GetVolume(ctx context.Context, volumeID string) (*types.Volume, error)
 	DeleteVolume(ctx context.Context, volumeID string) error
+	ListNamespaceVolumes(ctx context.Context, namespace string) ([]types.VolumeStub, error)
 }
 
 var _ VolumeAPI = (*NomadClient)(nil)
@@ -90,13 +216,37 @@ type AllocationAPI interface {
 	ListAllocations(ctx context.Context, namespace, jobID string) ([]types.Allocation, error)
 	GetAllocation(ctx context.Context, allocID string) (types.Allocation, error)
 	StopAllocation(ctx context.Context, allocID string) error
+	RestartAllocation(ctx context.Context, allocID, task string) error
+	ExecAllocation(ctx context.Context, request types.ExecRequest) (types.ExecResult, error)
 }
 
 var _ AllocationAPI = (*NomadClient)(nil)
 
+// PlacementMetricsAPI backs the allocation placement explanation; node names come from
+// the node list.
+type PlacementMetricsAPI interface {
+	GetAllocationMetrics(ctx context.Context, allocID string) (types.AllocationPlacement, error)
+	ListNodes(ctx context.Context, status string) ([]types.NodeSummary, error)
+}
+
+var _ PlacementMetricsAPI = (*NomadClient)(nil)
+
+// AllocFSAPI backs allocation filesystem browsing tools.
+type AllocFSAPI interface {
+	ListAllocationFiles(ctx context.Context, allocID, path string) ([]types.AllocFileInfo, error)
+	StatAllocationFile(ctx context.Context, allocID, path string) (types.AllocFileInfo, error)
+	ReadAllocationFile(ctx context.Context, allocID, path string, offset, limit int64) ([]byte, error)
+}
+
+var _ AllocFSAPI = (*NomadClient)(nil)
+
 // LogAPI backs allocation log tools.
 type LogAPI interface {
 	GetAllocationLogs(ctx context.Context, allocID, task, logType string, follow bool, tail, offset int64) (string, error)
+	StreamAllocationLogs(ctx context.Context, request types.LogStreamRequest, handle func(types.LogFrame) error) error
+	ReadAllocationLogs(ctx context.Context, request types.LogStreamRequest, limit int64) (types.LogPage, error)
+	GetDefaultTailLines() int
+	GetMaxLogBytes() int64
 }
 
 var _ LogAPI = (*NomadClient)(nil)
@@ -138,16 +288,157 @@ type SentinelAPI interface {
 
 var _ SentinelAPI = (*NomadClient)(nil)
 
+// NamespaceToolsDeps composes namespace and ACL access for namespace bootstrap workflows, and
+// the job, variable and volume access delete_namespace needs to find and remove residual objects.
+type NamespaceToolsDeps interface {
+	NamespaceAPI
+	ACLAPI
+	JobAPI
+	VariableAPI
+	VolumeAPI
+}
+
+var _ NamespaceToolsDeps = (*NomadClient)(nil)
+
+// ServerHealthAPI backs tools that inspect server membership, Raft and autopilot.
+type ServerHealthAPI interface {
+	GetRaftConfiguration(ctx context.Context) (types.RaftConfiguration, error)
+	ListAgentMembers(ctx context.Context) ([]types.AgentMember, error)
+	GetAutopilotHealth(ctx context.Context) (types.AutopilotHealth, error)
+}
+
+var _ ServerHealthAPI = (*NomadClient)(nil)
+
+// RaftPeerAPI backs the Raft peer management tools.
+type RaftPeerAPI interface {
+	GetRaftConfiguration(ctx context.Context) (types.RaftConfiguration, error)
+	ListAgentMembers(ctx context.Context) ([]types.AgentMember, error)
+	RemoveRaftPeer(ctx context.Context, id, address string) error
+	TransferRaftLeadership(ctx context.Context, id, address string) error
+}
+
+var _ RaftPeerAPI = (*NomadClient)(nil)
+
+// SnapshotAPI backs the snapshot save and restore tools.
+type SnapshotAPI interface {
+	SaveSnapshot(ctx context.Context, w io.Writer, stale bool) (int64, error)
+	RestoreSnapshot(ctx context.Context, r io.Reader) error
+}
+
+var _ SnapshotAPI = (*NomadClient)(nil)
+
+// LicenseAPI backs the Enterprise license tools.
+type LicenseAPI interface {
+	GetLicense(ctx context.Context) (types.LicenseReply, error)
+	PutLicense(ctx context.Context, license string, force bool) error
+}
+
+var _ LicenseAPI = (*NomadClient)(nil)
+
+// AutopilotAPI backs the autopilot configuration and health tools.
+type AutopilotAPI interface {
+	GetAutopilotConfiguration(ctx context.Context) (types.AutopilotConfiguration, error)
+	SetAutopilotConfiguration(ctx context.Context, config types.AutopilotConfiguration, cas bool) (bool, error)
+	GetAutopilotHealth(ctx context.Context) (types.AutopilotHealth, error)
+}
+
+var _ AutopilotAPI = (*NomadClient)(nil)
+
+// RegionAPI lists the regions read tools can fan out to.
+type RegionAPI interface {
+	ListRegions(ctx context.Context) ([]string, error)
+}
+
+var _ RegionAPI = (*NomadClient)(nil)
+
+// ErrorHintAPI backs the lookups that turn a failed tool call into a retry hint.
+type ErrorHintAPI interface {
+	RegionAPI
+	ListJobs(ctx context.Context, namespace, status string) ([]types.JobSummary, error)
+}
+
+var _ ErrorHintAPI = (*NomadClient)(nil)
+
 // ClusterToolsAPI backs cluster/regions MCP tools.
 type ClusterToolsAPI interface {
 	RawNomadCaller
+	RegionAPI
+	ServerHealthAPI
 	ListClusterPeers(ctx context.Context) ([]byte, error)
 }
 
 var _ ClusterToolsAPI = (*NomadClient)(nil)
 
+// DevAgentAPI backs start_dev_agent, which points the client at a local dev agent and back.
+type DevAgentAPI interface {
+	GetAddress() string
+	SetAddress(address string)
+	GetLeader(ctx context.Context) (string, error)
+}
+
+var _ DevAgentAPI = (*NomadClient)(nil)
+
+// AgentAPI backs tools and resources that depend on the local Nomad agent.
+type AgentAPI interface {
+	GetNomadVersion(ctx context.Context) (string, error)
+}
+
+var _ AgentAPI = (*NomadClient)(nil)
+
+// AgentToolsAPI backs the agent introspection tools.
+type AgentToolsAPI interface {
+	GetAgentMembers(ctx context.Context) (types.AgentMembers, error)
+	GetAgentSelf(ctx context.Context) (types.AgentSelf, error)
+	GetAgentHealth(ctx context.Context) (types.AgentHealth, error)
+}
+
+var _ AgentToolsAPI = (*NomadClient)(nil)
+
+// ScalingAPI backs the scaling policy and job scale status tools.
+type ScalingAPI interface {
+	ListScalingPolicies(ctx context.Context, namespace, jobID, policyType string) ([]types.ScalingPolicyListStub, error)
+	GetScalingPolicy(ctx context.Context, policyID string) (types.ScalingPolicy, error)
+	GetJobScaleStatus(ctx context.Context, jobID, namespace string) (types.JobScaleStatus, error)
+}
+
+var _ ScalingAPI = (*NomadClient)(nil)
+
+// QuotaAPI backs the resource quota tools (Nomad Enterprise).
+type QuotaAPI interface {
+	ListQuotas(ctx context.Context) ([]types.QuotaSpec, error)
+	GetQuota(ctx context.Context, name string) (types.QuotaSpec, error)
+	UpsertQuota(ctx context.Context, quota types.QuotaSpec) error
+	DeleteQuota(ctx context.Context, name string) error
+	ListQuotaUsages(ctx context.Context) ([]types.QuotaUsage, error)
+	GetQuotaUsage(ctx context.Context, name string) (types.QuotaUsage, error)
+}
+
+var _ QuotaAPI = (*NomadClient)(nil)
+
+// JournalAPI backs the operation journal, which records the state of the job, node or
+// variable an operation acted on.
+type JournalAPI interface {
+	GetJob(ctx context.Context, jobID, namespace string) (types.Job, error)
+	GetNodeDetail(ctx context.Context, nodeID string) (types.NodeDetail, error)
+	GetVariable(ctx context.Context, path, namespace string) (types.Variable, error)
+}
+
+var _ JournalAPI = (*NomadClient)(nil)
+
+// UndoAPI backs undo_operation, which reverts journaled operations.
+type UndoAPI interface {
+	JournalAPI
+	RevertJob(ctx context.Context, jobID, namespace string, version int, opts types.JobRevertOptions) (types.JobRegisterResponse, error)
+	EligibilityNode(ctx context.Context, nodeID string, eligible bool) (types.NodeEligibilityUpdate, error)
+	DrainNode(ctx context.Context, nodeID string, enable bool, deadline int64) (string, error)
+	CreateVariable(ctx context.Context, variable types.Variable, namespace string, cas int, lockOperation string) error
+}
+
+var _ UndoAPI = (*NomadClient)(nil)
+
 // DynamicResourcesNomad is the subset of NomadClient used when publishing MCP dynamic resources.
 type DynamicResourcesNomad interface {
+	AgentAPI
 	GetJob(ctx context.Context, jobID, namespace string) (types.Job, error)
 	GetJobVersions(ctx context.Context, jobID, namespace string) ([]types.Job, error)
 	GetNode(ctx context.Context, nodeID string) (types.Node, error)
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"context"
+	"strings"
+)
+
+type regionKey struct{}
+
+// WithRegion stores the Nomad region Nomad API requests made with ctx are forwarded to,
+// unless the request sets its own region. An empty region leaves ctx unchanged.
+func WithRegion(ctx context.Context, region string) context.Context {
+	region = strings.TrimSpace(region)
+	if region == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, regionKey{}, region)
+}
+
+// RegionFromContext returns the region stored by WithRegion, or "".
+func RegionFromContext(ctx context.Context) string {
+	region, _ := ctx.Value(regionKey{}).(string)
+	return region
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "context"
+
+type tokenKey struct{}
+
+// WithToken stores the ACL token of the calling MCP client in ctx. Nomad API requests made
+// with ctx send it instead of the token the NomadClient was created with, so each session
+// of a shared HTTP server acts with its own ACLs. An empty token leaves ctx unchanged.
+func WithToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// TokenFromContext returns the token stored by WithToken, or "".
+func TokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(tokenKey{}).(string)
+	return token
+}
+
+// tokenFor returns the token a request made with ctx authenticates with.
+func (c *NomadClient) tokenFor(ctx context.Context) string {
+	if token := TokenFromContext(ctx); token != "" {
+		return token
+	}
+	return c.GetToken()
+}
@@ -0,0 +1,358 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// tokenVaultKeyIterations is the PBKDF2 work factor deriving the vault key from its passphrase.
+const tokenVaultKeyIterations = 600000
+
+// APIKeyHeader carries the API key an MCP client identifies with on HTTP transports, as an
+// alternative to sending it as the Authorization bearer.
+const APIKeyHeader = "X-API-Key"
+
+// tokenVaultFile is the on-disk form of a TokenVault: the identity mappings encrypted with
+// AES-256-GCM under a key derived from the vault passphrase and Salt.
+type tokenVaultFile struct {
+	Version int    `json:"Version"`
+	Salt    string `json:"Salt"`
+	Nonce   string `json:"Nonce"`
+	Data    string `json:"Data"`
+}
+
+// TokenVault maps the identities of MCP clients to the Nomad tokens their calls use, so one
+// HTTP server can serve several operators with their own Nomad permissions. Identities are
+// API keys, stored only as SHA-256 hashes, and subjects of HS256 JWTs. The mappings are kept
+// encrypted on disk.
+type TokenVault struct {
+	path      string
+	key       []byte
+	salt      []byte
+	jwtSecret []byte
+
+	mu      sync.RWMutex
+	entries map[string]string
+}
+
+// OpenTokenVault decrypts the vault at path with passphrase, or starts an empty one when the
+// file does not exist yet.
+func OpenTokenVault(path, passphrase string) (*TokenVault, error) {
+	if passphrase == "" {
+		return nil, errors.New("the token vault passphrase is empty")
+	}
+	vault := &TokenVault{path: path, entries: map[string]string{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		vault.salt = make([]byte, 16)
+		if _, err := rand.Read(vault.salt); err != nil {
+			return nil, err
+		}
+		vault.key, err = pbkdf2.Key(sha256.New, passphrase, vault.salt, tokenVaultKeyIterations, 32)
+		if err != nil {
+			return nil, err
+		}
+		return vault, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file tokenVaultFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s is not a token vault: %v", path, err)
+	}
+	if file.Version != 1 {
+		return nil, fmt.Errorf("unsupported token vault version %d", file.Version)
+	}
+	if vault.salt, err = base64.StdEncoding.DecodeString(file.Salt); err != nil {
+		return nil, fmt.Errorf("invalid token vault salt: %v", err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(file.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token vault nonce: %v", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(file.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token vault data: %v", err)
+	}
+
+	if vault.key, err = pbkdf2.Key(sha256.New, passphrase, vault.salt, tokenVaultKeyIterations, 32); err != nil {
+		return nil, err
+	}
+	aead, err := vault.aead()
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, errors.New("cannot decrypt the token vault: wrong passphrase or corrupted file")
+	}
+	if err := json.Unmarshal(plain, &vault.entries); err != nil {
+		return nil, fmt.Errorf("invalid token vault content: %v", err)
+	}
+	return vault, nil
+}
+
+// SetJWTSecret sets the HS256 secret that bearer JWTs must be signed with for their subject
+// to be trusted. Without it, JWT subjects are not looked up.
+func (v *TokenVault) SetJWTSecret(secret []byte) {
+	v.jwtSecret = secret
+}
+
+// Set maps identity to nomadToken, or removes the mapping when nomadToken is empty. Call Save
+// to persist the change.
+func (v *TokenVault) Set(identity, nomadToken string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if nomadToken == "" {
+		delete(v.entries, identity)
+		return
+	}
+	v.entries[identity] = nomadToken
+}
+
+// Lookup returns the Nomad token mapped to identity.
+func (v *TokenVault) Lookup(identity string) (string, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	token, ok := v.entries[identity]
+	return token, ok
+}
+
+// Identities lists the mapped identities in order, without their tokens.
+func (v *TokenVault) Identities() []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	identities := make([]string, 0, len(v.entries))
+	for identity := range v.entries {
+		identities = append(identities, identity)
+	}
+	sort.Strings(identities)
+	return identities
+}
+
+// Save encrypts the mappings with a fresh nonce and replaces the vault file, readable by
+// its owner only.
+func (v *TokenVault) Save() error {
+	v.mu.RLock()
+	plain, err := json.Marshal(v.entries)
+	v.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	aead, err := v.aead()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(tokenVaultFile{
+		Version: 1,
+		Salt:    base64.StdEncoding.EncodeToString(v.salt),
+		Nonce:   base64.StdEncoding.EncodeToString(nonce),
+		Data:    base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, plain, nil)),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(v.path), filepath.Base(v.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), v.path)
+}
+
+func (v *TokenVault) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(v.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// TokenForRequest returns the Nomad token mapped to the identity an HTTP request presents:
+// the X-API-Key header, else the Authorization bearer as a JWT signed with the vault's JWT
+// secret, else the bearer as an API key. ok is false when the request has no mapped identity.
+func (v *TokenVault) TokenForRequest(r *http.Request) (token, identity string, ok bool) {
+	if apiKey := strings.TrimSpace(r.Header.Get(APIKeyHeader)); apiKey != "" {
+		identity = APIKeyIdentity(apiKey)
+		token, ok = v.Lookup(identity)
+		return token, identity, ok
+	}
+
+	bearer := CanonicalAuthorizationBearer(r.Header.Get("Authorization"))
+	if bearer == "" {
+		return "", "", false
+	}
+	if strings.Count(bearer, ".") == 2 && len(v.jwtSecret) > 0 {
+		if subject, err := VerifyHS256JWT(bearer, v.jwtSecret, time.Now()); err == nil {
+			identity = JWTSubjectIdentity(subject)
+			token, ok = v.Lookup(identity)
+			return token, identity, ok
+		}
+	}
+	identity = APIKeyIdentity(bearer)
+	token, ok = v.Lookup(identity)
+	return token, identity, ok
+}
+
+// RequireIdentity wraps next so that requests whose identity is not mapped in the vault,
+// including requests without credentials, are rejected with 401. They would otherwise reach
+// Nomad with the server's own token.
+func (v *TokenVault) RequireIdentity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := v.TokenForRequest(r); !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp-nomad"`)
+			http.Error(w, "no Nomad token is mapped to the presented API key or JWT", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// APIKeyIdentity is the vault identity of an API key. Only its hash is stored.
+func APIKeyIdentity(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return "api-key-sha256:" + hex.EncodeToString(sum[:])
+}
+
+// JWTSubjectIdentity is the vault identity of the subject of a verified JWT.
+func JWTSubjectIdentity(subject string) string {
+	return "jwt-sub:" + subject
+}
+
+// VerifyHS256JWT checks the HS256 signature and the exp and nbf claims of a compact JWT and
+// returns its sub claim.
+func VerifyHS256JWT(token string, secret []byte, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("not a compact JWT")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return "", fmt.Errorf("invalid JWT header: %v", err)
+	}
+	if header.Alg != "HS256" {
+		return "", fmt.Errorf("unsupported JWT algorithm %q", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("invalid JWT signature: %v", err)
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", errors.New("JWT signature does not match")
+	}
+
+	var claims struct {
+		Subject   string `json:"sub"`
+		ExpiresAt *int64 `json:"exp"`
+		NotBefore *int64 `json:"nbf"`
+	}
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return "", fmt.Errorf("invalid JWT claims: %v", err)
+	}
+	if claims.ExpiresAt != nil && !now.Before(time.Unix(*claims.ExpiresAt, 0)) {
+		return "", errors.New("JWT has expired")
+	}
+	if claims.NotBefore != nil && now.Before(time.Unix(*claims.NotBefore, 0)) {
+		return "", errors.New("JWT is not valid yet")
+	}
+	if claims.Subject == "" {
+		return "", errors.New("JWT has no sub claim")
+	}
+	return claims.Subject, nil
+}
+
+func decodeJWTSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// TokenVaultMapping is one entry of a token vault import file: an API key or a JWT subject
+// and the Nomad token its calls use. An empty nomad_token removes the mapping.
+type TokenVaultMapping struct {
+	APIKey     string `yaml:"api_key"`
+	JWTSubject string `yaml:"jwt_subject"`
+	NomadToken string `yaml:"nomad_token"`
+}
+
+// ImportTokenVaultMappings merges the mappings of a YAML import file into vault and returns
+// how many were set and removed. Call Save to persist them.
+func ImportTokenVaultMappings(vault *TokenVault, path string) (set, removed int, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	var mappings []TokenVaultMapping
+	if err := yaml.Unmarshal(data, &mappings); err != nil {
+		return 0, 0, fmt.Errorf("invalid token vault import file: %v", err)
+	}
+
+	identities := make([]string, len(mappings))
+	for i, mapping := range mappings {
+		switch {
+		case mapping.APIKey != "" && mapping.JWTSubject != "":
+			return 0, 0, fmt.Errorf("mapping %d sets both api_key and jwt_subject", i+1)
+		case mapping.APIKey != "":
+			identities[i] = APIKeyIdentity(mapping.APIKey)
+		case mapping.JWTSubject != "":
+			identities[i] = JWTSubjectIdentity(mapping.JWTSubject)
+		default:
+			return 0, 0, fmt.Errorf("mapping %d needs api_key or jwt_subject", i+1)
+		}
+	}
+	for i, mapping := range mappings {
+		vault.Set(identities[i], mapping.NomadToken)
+		if mapping.NomadToken == "" {
+			removed++
+		} else {
+			set++
+		}
+	}
+	return set, removed, nil
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func signHS256JWT(t *testing.T, claims string, secret []byte) string {
+	t.Helper()
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestTokenVault_encryptsMappingsAndNeedsThePassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.json")
+	importFile := filepath.Join(t.TempDir(), "mappings.yaml")
+	require.NoError(t, os.WriteFile(importFile, []byte(`
+- api_key: key-alice
+  nomad_token: nomad-alice
+- jwt_subject: bob
+  nomad_token: nomad-bob
+`), 0o600))
+
+	vault, err := OpenTokenVault(path, "passphrase")
+	require.NoError(t, err)
+	set, removed, err := ImportTokenVaultMappings(vault, importFile)
+	require.NoError(t, err)
+	require.Equal(t, 2, set)
+	require.Equal(t, 0, removed)
+	require.NoError(t, vault.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "nomad-alice")
+	require.NotContains(t, string(data), "key-alice")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	_, err = OpenTokenVault(path, "wrong")
+	require.ErrorContains(t, err, "wrong passphrase")
+
+	reopened, err := OpenTokenVault(path, "passphrase")
+	require.NoError(t, err)
+	token, ok := reopened.Lookup(APIKeyIdentity("key-alice"))
+	require.True(t, ok)
+	require.Equal(t, "nomad-alice", token)
+	token, ok = reopened.Lookup(JWTSubjectIdentity("bob"))
+	require.True(t, ok)
+	require.Equal(t, "nomad-bob", token)
+}
+
+func TestTokenVault_tokenForRequest(t *testing.T) {
+	secret := []byte("jwt-secret")
+	vault, err := OpenTokenVault(filepath.Join(t.TempDir(), "vault.json"), "passphrase")
+	require.NoError(t, err)
+	vault.SetJWTSecret(secret)
+	vault.Set(APIKeyIdentity("key-alice"), "nomad-alice")
+	vault.Set(JWTSubjectIdentity("bob"), "nomad-bob")
+
+	r := httptest.NewRequest("POST", "/mcp", nil)
+	r.Header.Set(APIKeyHeader, "key-alice")
+	token, _, ok := vault.TokenForRequest(r)
+	require.True(t, ok)
+	require.Equal(t, "nomad-alice", token)
+
+	r = httptest.NewRequest("POST", "/mcp", nil)
+	r.Header.Set("Authorization", "Bearer key-alice")
+	token, _, ok = vault.TokenForRequest(r)
+	require.True(t, ok)
+	require.Equal(t, "nomad-alice", token)
+
+	jwt := signHS256JWT(t, fmt.Sprintf(`{"sub":"bob","exp":%d}`, time.Now().Add(time.Hour).Unix()), secret)
+	r = httptest.NewRequest("POST", "/mcp", nil)
+	r.Header.Set("Authorization", "Bearer "+jwt)
+	token, identity, ok := vault.TokenForRequest(r)
+	require.True(t, ok)
+	require.Equal(t, "nomad-bob", token)
+	require.Equal(t, "jwt-sub:bob", identity)
+
+	// A JWT signed with another secret does not get bob's token.
+	forged := signHS256JWT(t, `{"sub":"bob"}`, []byte("other-secret"))
+	r = httptest.NewRequest("POST", "/mcp", nil)
+	r.Header.Set("Authorization", "Bearer "+forged)
+	_, _, ok = vault.TokenForRequest(r)
+	require.False(t, ok)
+
+	_, err = VerifyHS256JWT(signHS256JWT(t, `{"sub":"bob","exp":1}`, secret), secret, time.Now())
+	require.ErrorContains(t, err, "expired")
+}
+
+func TestTokenVault_requireIdentityRejectsUnmappedCallers(t *testing.T) {
+	vault, err := OpenTokenVault(filepath.Join(t.TempDir(), "vault.json"), "passphrase")
+	require.NoError(t, err)
+	vault.Set(APIKeyIdentity("key-alice"), "nomad-alice")
+
+	reached := 0
+	handler := vault.RequireIdentity(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached++
+	}))
+	serve := func(header, value string) int {
+		r := httptest.NewRequest("POST", "/mcp", nil)
+		if header != "" {
+			r.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusUnauthorized, serve("", ""))
+	require.Equal(t, http.StatusUnauthorized, serve("Authorization", "Bearer some-nomad-token"))
+	require.Equal(t, http.StatusUnauthorized, serve(APIKeyHeader, "key-mallory"))
+	require.Equal(t, 0, reached)
+	require.Equal(t, http.StatusOK, serve(APIKeyHeader, "key-alice"))
+	require.Equal(t, 1, reached)
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseVersion splits a Nomad version such as 1.6.2, v1.7.0-beta.1 or 1.5.3+ent into its
+// numeric components, ignoring any prerelease or metadata suffix.
+func ParseVersion(value string) ([]int, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "v")
+	if i := strings.IndexAny(value, "-+"); i >= 0 {
+		value = value[:i]
+	}
+	if value == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, p := range strings.Split(value, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
+
+// CompareVersions compares two parsed versions and returns -1, 0 or 1. Missing trailing
+// components count as 0.
+func CompareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
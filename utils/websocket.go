@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// WebSocket opcodes (RFC 6455, section 5.2)
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// wsGUID is appended to the handshake key to compute Sec-WebSocket-Accept
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxWebSocketMessageBytes bounds a single message read from Nomad.
+const maxWebSocketMessageBytes = 16 << 20
+
+// errWebSocketClosed is returned by readMessage once the server sent a close frame.
+var errWebSocketClosed = errors.New("websocket closed by server")
+
+// wsConn is a minimal client side of a WebSocket connection, enough for Nomad's exec
+// endpoint: text and binary messages, fragmentation, ping/pong and close. It is not safe
+// for concurrent readers; writes are serialized.
+type wsConn struct {
+	rw      io.ReadWriteCloser
+	br      *bufio.Reader
+	writeMu sync.Mutex
+	done    chan struct{}
+	once    sync.Once
+}
+
+// dialWebSocket upgrades a GET request on path to a WebSocket connection. The request
+// carries the region, request ID and token of ctx like any other Nomad request and reuses
+// the client's TLS configuration. Cancelling ctx closes the connection.
+func (c *NomadClient) dialWebSocket(ctx context.Context, path string, query url.Values) (*wsConn, error) {
+	req, err := c.newRequest(ctx, "GET", path, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return nil, fmt.Errorf("error creating websocket key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+	req.Header.Del("Accept-Encoding")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, MaxNomadHTTPErrorBodyBytes+1))
+		httpErr := NewNomadHTTPError(resp.StatusCode, "GET", normalizeAPIPath(path), respBody)
+		httpErr.RequestID = RequestIDFromContext(ctx)
+		return nil, httpErr
+	}
+
+	rw, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		return nil, fmt.Errorf("websocket upgrade did not return a writable connection")
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != websocketAccept(key) {
+		rw.Close()
+		return nil, fmt.Errorf("websocket upgrade returned an invalid Sec-WebSocket-Accept header")
+	}
+
+	conn := &wsConn{rw: rw, br: bufio.NewReader(rw), done: make(chan struct{})}
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.rw.Close()
+		case <-conn.done:
+		}
+	}()
+	return conn, nil
+}
+
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// writeMessage sends one unfragmented, masked frame.
+func (w *wsConn) writeMessage(opcode byte, payload []byte) error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, 0x80|byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 0x80|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 0x80|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	mask := make([]byte, 4)
+	if _, err := rand.Read(mask); err != nil {
+		return err
+	}
+	header = append(header, mask...)
+
+	frame := make([]byte, len(header)+len(payload))
+	copy(frame, header)
+	for i, b := range payload {
+		frame[len(header)+i] = b ^ mask[i%4]
+	}
+	_, err := w.rw.Write(frame)
+	return err
+}
+
+// readMessage returns the next text or binary message, answering pings on the way. A
+// close frame from the server is acknowledged and reported as errWebSocketClosed, with the
+// server's reason when it gave one.
+func (w *wsConn) readMessage() (byte, []byte, error) {
+	var message []byte
+	var messageOp byte
+	for {
+		fin, opcode, payload, err := w.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case wsOpPing:
+			if err := w.writeMessage(wsOpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			_ = w.writeMessage(wsOpClose, payload[:min(len(payload), 2)])
+			if len(payload) > 2 {
+				return 0, nil, fmt.Errorf("%w: %s", errWebSocketClosed, payload[2:])
+			}
+			return 0, nil, errWebSocketClosed
+		case wsOpText, wsOpBinary:
+			messageOp = opcode
+			message = payload
+		case wsOpContinuation:
+			if messageOp == 0 {
+				return 0, nil, fmt.Errorf("websocket continuation frame without a message")
+			}
+			if len(message)+len(payload) > maxWebSocketMessageBytes {
+				return 0, nil, fmt.Errorf("websocket message exceeds %d bytes", maxWebSocketMessageBytes)
+			}
+			message = append(message, payload...)
+		default:
+			return 0, nil, fmt.Errorf("unexpected websocket opcode %#x", opcode)
+		}
+
+		if fin {
+			return messageOp, message, nil
+		}
+	}
+}
+
+func (w *wsConn) readFrame() (bool, byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(w.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := head[0]&0x80 != 0
+	opcode := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(w.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(w.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxWebSocketMessageBytes {
+		return false, 0, nil, fmt.Errorf("websocket frame exceeds %d bytes", maxWebSocketMessageBytes)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(w.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(w.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// close sends a normal-closure frame and closes the connection.
+func (w *wsConn) close() error {
+	w.once.Do(func() { close(w.done) })
+	_ = w.writeMessage(wsOpClose, []byte{0x03, 0xE8})
+	return w.rw.Close()
+}